@@ -2,7 +2,9 @@ package filekit
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"maps"
 	"sync"
 	"time"
 )
@@ -242,6 +244,21 @@ type CacheOptions struct {
 	// OnCacheMiss is called when a cache miss occurs.
 	// Useful for metrics and debugging.
 	OnCacheMiss func(op, path string)
+
+	// NegativeTTL is the time-to-live for negative results: false from
+	// FileExists() and DirExists(), and not-found errors from Stat().
+	// A shorter value than TTL lets newly created files show up sooner.
+	// Default: 0 (false results use TTL; Stat() errors are not cached)
+	NegativeTTL time.Duration
+
+	// OperationTimeout bounds each call to the underlying filesystem.
+	// A call that exceeds it fails with ErrCodeTimeout and its result is
+	// not cached. Reads return as soon as it elapses; mutating calls only
+	// pass it to the backend as a context deadline and wait for the backend
+	// to return, so a write never outlives the call that started it. Read
+	// and Watch are not bounded, as their results outlive the call.
+	// Default: 0 (no timeout)
+	OperationTimeout time.Duration
 }
 
 // CacheOption is a functional option for configuring CachingFileSystem.
@@ -310,6 +327,26 @@ func WithCacheMissCallback(callback func(op, path string)) CacheOption {
 	}
 }
 
+// WithNegativeTTL sets the TTL for negative results. FileExists() and
+// DirExists() false results expire after ttl instead of the default TTL, and
+// Stat() not-found errors are cached for ttl as well.
+func WithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.NegativeTTL = ttl
+	}
+}
+
+// WithOperationTimeout bounds each call to the underlying filesystem to d.
+// Reads from backends that ignore their context are abandoned once d
+// elapses; the eventual result is discarded. Mutating calls (Write, Delete,
+// DeleteMany, CreateDir, DeleteDir, Copy, Move) get d as a context deadline
+// and are always waited for. A d of 0 disables the timeout.
+func WithOperationTimeout(d time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.OperationTimeout = d
+	}
+}
+
 // NewCachingFileSystem creates a caching wrapper around a FileSystem.
 func NewCachingFileSystem(fs FileSystem, cache Cache, opts ...CacheOption) *CachingFileSystem {
 	options := CacheOptions{
@@ -355,6 +392,33 @@ func (c *CachingFileSystem) shouldCache(path string) bool {
 	return c.opts.PathFilter(path)
 }
 
+// existsTTL returns the TTL for a FileExists() or DirExists() result.
+func (c *CachingFileSystem) existsTTL(exists bool) time.Duration {
+	if !exists && c.opts.NegativeTTL > 0 {
+		return c.opts.NegativeTTL
+	}
+	return c.opts.TTL
+}
+
+// statNotFound is the cached Stat() value for a path that does not exist.
+type statNotFound struct{}
+
+// cacheStatError caches a not-found Stat() error when NegativeTTL is set.
+func (c *CachingFileSystem) cacheStatError(path string, err error) {
+	if c.opts.NegativeTTL > 0 && IsNotFound(err) {
+		c.cache.Set(c.cacheKey("stat", path), statNotFound{}, c.opts.NegativeTTL)
+	}
+}
+
+// cachedStat converts a cached Stat() value back into its result.
+func cachedStat(path string, cached interface{}) (*FileInfo, error) {
+	if _, ok := cached.(statNotFound); ok {
+		return nil, WrapPathErr("stat", path, ErrNotExist)
+	}
+	// Return a copy to prevent mutation
+	return copyCachedInfo(cached.(*FileInfo)), nil
+}
+
 // invalidatePath removes cache entries for a path.
 func (c *CachingFileSystem) invalidatePath(path string) {
 	if !c.opts.InvalidateOnWrite {
@@ -374,6 +438,75 @@ func (c *CachingFileSystem) invalidateAll() {
 	}
 }
 
+// callWithTimeout runs fn against the underlying filesystem, bounded by
+// OperationTimeout. When the deadline passes first it returns an
+// ErrCodeTimeout error without waiting for fn.
+func callWithTimeout[T any](c *CachingFileSystem, ctx context.Context, op, path string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if c.opts.OperationTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, c.opts.OperationTimeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn(tctx)
+		done <- result{value, err}
+	}()
+
+	var zero T
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() == nil && tctx.Err() != nil {
+			return zero, c.timeoutError(r.err, op, path)
+		}
+		return r.value, r.err
+	case <-tctx.Done():
+		if err := FromContext(ctx, op, path); err != nil {
+			return zero, err
+		}
+		return zero, c.timeoutError(tctx.Err(), op, path)
+	}
+}
+
+// mutateWithTimeout runs a mutating fn against the underlying filesystem
+// with an OperationTimeout deadline on its context. Unlike callWithTimeout
+// it always waits for fn, so the caller never returns while the backend may
+// still be changing state behind it; a failure caused by the deadline is
+// reported as ErrCodeTimeout.
+func mutateWithTimeout[T any](c *CachingFileSystem, ctx context.Context, op, path string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if c.opts.OperationTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, c.opts.OperationTimeout)
+	defer cancel()
+
+	value, err := fn(tctx)
+	if err != nil && ctx.Err() == nil && tctx.Err() != nil {
+		var zero T
+		return zero, c.timeoutError(err, op, path)
+	}
+	return value, err
+}
+
+// mutateWithTimeoutErr is mutateWithTimeout for operations that return only an error.
+func mutateWithTimeoutErr(c *CachingFileSystem, ctx context.Context, op, path string, fn func(ctx context.Context) error) error {
+	_, err := mutateWithTimeout(c, ctx, op, path, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+func (c *CachingFileSystem) timeoutError(err error, op, path string) *FileError {
+	return WrapPath(err, op, path, ErrCodeTimeout, fmt.Sprintf("backend did not respond within %s", c.opts.OperationTimeout))
+}
+
 // ============================================================================
 // FileSystem Interface - Cached Operations
 // ============================================================================
@@ -381,7 +514,9 @@ func (c *CachingFileSystem) invalidateAll() {
 // FileExists checks if a file exists, using cache when available.
 func (c *CachingFileSystem) FileExists(ctx context.Context, path string) (bool, error) {
 	if !c.opts.CacheExists || !c.shouldCache(path) {
-		return c.fs.FileExists(ctx, path)
+		return callWithTimeout(c, ctx, "fileexists", path, func(ctx context.Context) (bool, error) {
+			return c.fs.FileExists(ctx, path)
+		})
 	}
 
 	key := c.cacheKey("fileexists", path)
@@ -399,20 +534,24 @@ func (c *CachingFileSystem) FileExists(ctx context.Context, path string) (bool,
 	}
 
 	// Cache miss, call underlying filesystem
-	exists, err := c.fs.FileExists(ctx, path)
+	exists, err := callWithTimeout(c, ctx, "fileexists", path, func(ctx context.Context) (bool, error) {
+		return c.fs.FileExists(ctx, path)
+	})
 	if err != nil {
 		return false, err
 	}
 
 	// Cache the result
-	c.cache.Set(key, exists, c.opts.TTL)
+	c.cache.Set(key, exists, c.existsTTL(exists))
 	return exists, nil
 }
 
 // DirExists checks if a directory exists, using cache when available.
 func (c *CachingFileSystem) DirExists(ctx context.Context, path string) (bool, error) {
 	if !c.opts.CacheExists || !c.shouldCache(path) {
-		return c.fs.DirExists(ctx, path)
+		return callWithTimeout(c, ctx, "direxists", path, func(ctx context.Context) (bool, error) {
+			return c.fs.DirExists(ctx, path)
+		})
 	}
 
 	key := c.cacheKey("direxists", path)
@@ -430,20 +569,24 @@ func (c *CachingFileSystem) DirExists(ctx context.Context, path string) (bool, e
 	}
 
 	// Cache miss, call underlying filesystem
-	exists, err := c.fs.DirExists(ctx, path)
+	exists, err := callWithTimeout(c, ctx, "direxists", path, func(ctx context.Context) (bool, error) {
+		return c.fs.DirExists(ctx, path)
+	})
 	if err != nil {
 		return false, err
 	}
 
 	// Cache the result
-	c.cache.Set(key, exists, c.opts.TTL)
+	c.cache.Set(key, exists, c.existsTTL(exists))
 	return exists, nil
 }
 
 // Stat returns file information, using cache when available.
 func (c *CachingFileSystem) Stat(ctx context.Context, path string) (*FileInfo, error) {
 	if !c.opts.CacheFileInfo || !c.shouldCache(path) {
-		return c.fs.Stat(ctx, path)
+		return callWithTimeout(c, ctx, "stat", path, func(ctx context.Context) (*FileInfo, error) {
+			return c.fs.Stat(ctx, path)
+		})
 	}
 
 	key := c.cacheKey("stat", path)
@@ -453,17 +596,7 @@ func (c *CachingFileSystem) Stat(ctx context.Context, path string) (*FileInfo, e
 		if c.opts.OnCacheHit != nil {
 			c.opts.OnCacheHit("stat", path)
 		}
-		// Return a copy to prevent mutation
-		info := cached.(*FileInfo)
-		return &FileInfo{
-			Name:        info.Name,
-			Path:        info.Path,
-			Size:        info.Size,
-			ModTime:     info.ModTime,
-			IsDir:       info.IsDir,
-			ContentType: info.ContentType,
-			Metadata:    info.Metadata,
-		}, nil
+		return cachedStat(path, cached)
 	}
 
 	if c.opts.OnCacheMiss != nil {
@@ -471,8 +604,11 @@ func (c *CachingFileSystem) Stat(ctx context.Context, path string) (*FileInfo, e
 	}
 
 	// Cache miss, call underlying filesystem
-	info, err := c.fs.Stat(ctx, path)
+	info, err := callWithTimeout(c, ctx, "stat", path, func(ctx context.Context) (*FileInfo, error) {
+		return c.fs.Stat(ctx, path)
+	})
 	if err != nil {
+		c.cacheStatError(path, err)
 		return nil, err
 	}
 
@@ -481,10 +617,101 @@ func (c *CachingFileSystem) Stat(ctx context.Context, path string) (*FileInfo, e
 	return info, nil
 }
 
+// StatMany returns metadata for many paths. Cached entries are served from the
+// cache and only misses are fetched, using the underlying filesystem's
+// CanStatMany implementation when available.
+func (c *CachingFileSystem) StatMany(ctx context.Context, paths []string) (map[string]*FileInfo, map[string]error) {
+	if !c.opts.CacheFileInfo {
+		return c.statMany(ctx, paths)
+	}
+
+	infos := make(map[string]*FileInfo, len(paths))
+	cachedErrs := make(map[string]error)
+	misses := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !c.shouldCache(p) {
+			misses = append(misses, p)
+			continue
+		}
+		cached, ok := c.cache.Get(c.cacheKey("stat", p))
+		if !ok {
+			if c.opts.OnCacheMiss != nil {
+				c.opts.OnCacheMiss("stat", p)
+			}
+			misses = append(misses, p)
+			continue
+		}
+		if c.opts.OnCacheHit != nil {
+			c.opts.OnCacheHit("stat", p)
+		}
+		info, err := cachedStat(p, cached)
+		if err != nil {
+			cachedErrs[p] = err
+			continue
+		}
+		infos[p] = info
+	}
+
+	if len(misses) == 0 {
+		return infos, cachedErrs
+	}
+
+	fetched, errs := c.statMany(ctx, misses)
+	for p, info := range fetched {
+		if c.shouldCache(p) {
+			c.cache.Set(c.cacheKey("stat", p), info, c.opts.TTL)
+		}
+		infos[p] = info
+	}
+	for p, err := range errs {
+		if c.shouldCache(p) {
+			c.cacheStatError(p, err)
+		}
+		cachedErrs[p] = err
+	}
+	return infos, cachedErrs
+}
+
+// statMany fetches paths from the underlying filesystem. On timeout every
+// path reports the timeout error.
+func (c *CachingFileSystem) statMany(ctx context.Context, paths []string) (map[string]*FileInfo, map[string]error) {
+	type statResult struct {
+		infos map[string]*FileInfo
+		errs  map[string]error
+	}
+	r, err := callWithTimeout(c, ctx, "stat-many", "", func(ctx context.Context) (statResult, error) {
+		infos, errs := StatMany(ctx, c.fs, paths)
+		return statResult{infos, errs}, nil
+	})
+	if err != nil {
+		return map[string]*FileInfo{}, errorForEach(paths, err)
+	}
+	return r.infos, r.errs
+}
+
+// errorForEach maps every path to err.
+func errorForEach(paths []string, err error) map[string]error {
+	errs := make(map[string]error, len(paths))
+	for _, p := range paths {
+		errs[p] = err
+	}
+	return errs
+}
+
+// copyCachedInfo returns a copy of a cached FileInfo to prevent mutation.
+// Every field is kept, so ETags and checksums survive caching.
+func copyCachedInfo(info *FileInfo) *FileInfo {
+	cp := *info
+	cp.Metadata = maps.Clone(info.Metadata)
+	return &cp
+}
+
 // ListContents returns directory contents, using cache when available.
 func (c *CachingFileSystem) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
 	if !c.opts.CacheList || !c.shouldCache(path) {
-		return c.fs.ListContents(ctx, path, recursive)
+		return callWithTimeout(c, ctx, "list", path, func(ctx context.Context) ([]FileInfo, error) {
+			return c.fs.ListContents(ctx, path, recursive)
+		})
 	}
 
 	// Include recursive flag in cache key
@@ -511,7 +738,9 @@ func (c *CachingFileSystem) ListContents(ctx context.Context, path string, recur
 	}
 
 	// Cache miss, call underlying filesystem
-	files, err := c.fs.ListContents(ctx, path, recursive)
+	files, err := callWithTimeout(c, ctx, "list", path, func(ctx context.Context) ([]FileInfo, error) {
+		return c.fs.ListContents(ctx, path, recursive)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -532,43 +761,72 @@ func (c *CachingFileSystem) Read(ctx context.Context, path string) (io.ReadClose
 
 // ReadAll delegates to the underlying filesystem (content is not cached).
 func (c *CachingFileSystem) ReadAll(ctx context.Context, path string) ([]byte, error) {
-	return c.fs.ReadAll(ctx, path)
+	return callWithTimeout(c, ctx, "readall", path, func(ctx context.Context) ([]byte, error) {
+		return c.fs.ReadAll(ctx, path)
+	})
 }
 
 // Write delegates to the underlying filesystem and invalidates cache.
 func (c *CachingFileSystem) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
-	result, err := c.fs.Write(ctx, path, content, options...)
-	if err == nil {
-		c.invalidatePath(path)
-	}
-	return result, err
+	return mutateWithTimeout(c, ctx, "write", path, func(ctx context.Context) (*WriteResult, error) {
+		result, err := c.fs.Write(ctx, path, content, options...)
+		if err == nil {
+			c.invalidatePath(path)
+		}
+		return result, err
+	})
 }
 
 // Delete delegates to the underlying filesystem and invalidates cache.
 func (c *CachingFileSystem) Delete(ctx context.Context, path string) error {
-	err := c.fs.Delete(ctx, path)
-	if err == nil {
-		c.invalidatePath(path)
+	return mutateWithTimeoutErr(c, ctx, "delete", path, func(ctx context.Context) error {
+		err := c.fs.Delete(ctx, path)
+		if err == nil {
+			c.invalidatePath(path)
+		}
+		return err
+	})
+}
+
+// DeleteMany delegates to the underlying filesystem and invalidates the deleted paths.
+func (c *CachingFileSystem) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	type deleteResult struct {
+		deleted []string
+		errs    map[string]error
 	}
-	return err
+	r, err := mutateWithTimeout(c, ctx, "delete-many", "", func(ctx context.Context) (deleteResult, error) {
+		deleted, errs := DeleteMany(ctx, c.fs, paths)
+		for _, p := range deleted {
+			c.invalidatePath(p)
+		}
+		return deleteResult{deleted, errs}, nil
+	})
+	if err != nil {
+		return nil, errorForEach(paths, err)
+	}
+	return r.deleted, r.errs
 }
 
 // CreateDir delegates to the underlying filesystem and invalidates cache.
 func (c *CachingFileSystem) CreateDir(ctx context.Context, path string) error {
-	err := c.fs.CreateDir(ctx, path)
-	if err == nil {
-		c.invalidatePath(path)
-	}
-	return err
+	return mutateWithTimeoutErr(c, ctx, "createdir", path, func(ctx context.Context) error {
+		err := c.fs.CreateDir(ctx, path)
+		if err == nil {
+			c.invalidatePath(path)
+		}
+		return err
+	})
 }
 
 // DeleteDir delegates to the underlying filesystem and invalidates cache.
 func (c *CachingFileSystem) DeleteDir(ctx context.Context, path string) error {
-	err := c.fs.DeleteDir(ctx, path)
-	if err == nil {
-		c.invalidateAll() // Directory deletion affects many paths
-	}
-	return err
+	return mutateWithTimeoutErr(c, ctx, "deletedir", path, func(ctx context.Context) error {
+		err := c.fs.DeleteDir(ctx, path)
+		if err == nil {
+			c.invalidateAll() // Directory deletion affects many paths
+		}
+		return err
+	})
 }
 
 // ============================================================================
@@ -578,24 +836,28 @@ func (c *CachingFileSystem) DeleteDir(ctx context.Context, path string) error {
 // Copy delegates to the underlying filesystem and invalidates cache.
 func (c *CachingFileSystem) Copy(ctx context.Context, src, dst string) error {
 	if copier, ok := c.fs.(CanCopy); ok {
-		err := copier.Copy(ctx, src, dst)
-		if err == nil {
-			c.invalidatePath(dst)
-		}
-		return err
+		return mutateWithTimeoutErr(c, ctx, "copy", src, func(ctx context.Context) error {
+			err := copier.Copy(ctx, src, dst)
+			if err == nil {
+				c.invalidatePath(dst)
+			}
+			return err
+		})
 	}
 	return NewPathError("copy", src, ErrCodeNotSupported, "underlying filesystem does not support copy")
 }
 
 // Move delegates to the underlying filesystem and invalidates cache.
-func (c *CachingFileSystem) Move(ctx context.Context, src, dst string) error {
+func (c *CachingFileSystem) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
 	if mover, ok := c.fs.(CanMove); ok {
-		err := mover.Move(ctx, src, dst)
-		if err == nil {
-			c.invalidatePath(src)
-			c.invalidatePath(dst)
-		}
-		return err
+		return mutateWithTimeoutErr(c, ctx, "move", src, func(ctx context.Context) error {
+			err := mover.Move(ctx, src, dst, opts...)
+			if err == nil {
+				c.invalidatePath(src)
+				c.invalidatePath(dst)
+			}
+			return err
+		})
 	}
 	return NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
 }
@@ -603,7 +865,9 @@ func (c *CachingFileSystem) Move(ctx context.Context, src, dst string) error {
 // Checksum delegates to the underlying filesystem.
 func (c *CachingFileSystem) Checksum(ctx context.Context, path string, algorithm ChecksumAlgorithm) (string, error) {
 	if checksummer, ok := c.fs.(CanChecksum); ok {
-		return checksummer.Checksum(ctx, path, algorithm)
+		return callWithTimeout(c, ctx, "checksum", path, func(ctx context.Context) (string, error) {
+			return checksummer.Checksum(ctx, path, algorithm)
+		})
 	}
 	return "", NewPathError("checksum", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
 }
@@ -611,7 +875,9 @@ func (c *CachingFileSystem) Checksum(ctx context.Context, path string, algorithm
 // Checksums delegates to the underlying filesystem.
 func (c *CachingFileSystem) Checksums(ctx context.Context, path string, algorithms []ChecksumAlgorithm) (map[ChecksumAlgorithm]string, error) {
 	if checksummer, ok := c.fs.(CanChecksum); ok {
-		return checksummer.Checksums(ctx, path, algorithms)
+		return callWithTimeout(c, ctx, "checksums", path, func(ctx context.Context) (map[ChecksumAlgorithm]string, error) {
+			return checksummer.Checksums(ctx, path, algorithms)
+		})
 	}
 	return nil, NewPathError("checksums", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
 }
@@ -619,7 +885,9 @@ func (c *CachingFileSystem) Checksums(ctx context.Context, path string, algorith
 // SignedURL delegates to the underlying filesystem.
 func (c *CachingFileSystem) SignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
 	if urlGen, ok := c.fs.(CanSignURL); ok {
-		return urlGen.SignedURL(ctx, path, expires)
+		return callWithTimeout(c, ctx, "signed-url", path, func(ctx context.Context) (string, error) {
+			return urlGen.SignedURL(ctx, path, expires)
+		})
 	}
 	return "", NewPathError("signed-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
 }
@@ -627,7 +895,9 @@ func (c *CachingFileSystem) SignedURL(ctx context.Context, path string, expires
 // SignedUploadURL delegates to the underlying filesystem.
 func (c *CachingFileSystem) SignedUploadURL(ctx context.Context, path string, expires time.Duration) (string, error) {
 	if urlGen, ok := c.fs.(CanSignURL); ok {
-		return urlGen.SignedUploadURL(ctx, path, expires)
+		return callWithTimeout(c, ctx, "signed-upload-url", path, func(ctx context.Context) (string, error) {
+			return urlGen.SignedUploadURL(ctx, path, expires)
+		})
 	}
 	return "", NewPathError("signed-upload-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
 }
@@ -678,14 +948,16 @@ func (t *cacheInvalidatingToken) RegisterChangeCallback(callback func()) (unregi
 
 // Ensure CachingFileSystem implements FileSystem and optional interfaces
 var (
-	_ FileSystem  = (*CachingFileSystem)(nil)
-	_ FileReader  = (*CachingFileSystem)(nil)
-	_ FileWriter  = (*CachingFileSystem)(nil)
-	_ CanCopy     = (*CachingFileSystem)(nil)
-	_ CanMove     = (*CachingFileSystem)(nil)
-	_ CanChecksum = (*CachingFileSystem)(nil)
-	_ CanSignURL  = (*CachingFileSystem)(nil)
-	_ CanWatch    = (*CachingFileSystem)(nil)
+	_ FileSystem    = (*CachingFileSystem)(nil)
+	_ FileReader    = (*CachingFileSystem)(nil)
+	_ FileWriter    = (*CachingFileSystem)(nil)
+	_ CanCopy       = (*CachingFileSystem)(nil)
+	_ CanMove       = (*CachingFileSystem)(nil)
+	_ CanChecksum   = (*CachingFileSystem)(nil)
+	_ CanSignURL    = (*CachingFileSystem)(nil)
+	_ CanWatch      = (*CachingFileSystem)(nil)
+	_ CanStatMany   = (*CachingFileSystem)(nil)
+	_ CanDeleteMany = (*CachingFileSystem)(nil)
 )
 
 // ============================================================================
@@ -0,0 +1,290 @@
+package filekit_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// stallingFS delays FileExists, Stat and Write by delay, ignoring the
+// context, and counts backend calls.
+type stallingFS struct {
+	filekit.FileSystem
+
+	delay atomic.Int64
+	calls atomic.Int32
+}
+
+func (s *stallingFS) wait() {
+	s.calls.Add(1)
+	time.Sleep(time.Duration(s.delay.Load()))
+}
+
+func (s *stallingFS) FileExists(ctx context.Context, path string) (bool, error) {
+	s.wait()
+	return s.FileSystem.FileExists(ctx, path)
+}
+
+func (s *stallingFS) Stat(ctx context.Context, path string) (*filekit.FileInfo, error) {
+	s.wait()
+	return s.FileSystem.Stat(ctx, path)
+}
+
+func (s *stallingFS) Write(ctx context.Context, path string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
+	s.wait()
+	return s.FileSystem.Write(context.Background(), path, content, options...)
+}
+
+// deadlineFS blocks Delete until its context is done.
+type deadlineFS struct {
+	filekit.FileSystem
+}
+
+func (d deadlineFS) Delete(ctx context.Context, path string) error {
+	<-ctx.Done()
+	return filekit.FromContext(ctx, "delete", path)
+}
+
+func TestCachingFileSystem_OperationTimeout(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	ctx := context.Background()
+	base := memory.New()
+	if _, err := base.Write(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+	slow := &stallingFS{FileSystem: base}
+	cached := filekit.NewCachingFileSystem(slow, filekit.NewMemoryCache(), filekit.WithOperationTimeout(timeout))
+
+	slow.delay.Store(int64(10 * timeout))
+	start := time.Now()
+	_, err := cached.Stat(ctx, "a.txt")
+	if elapsed := time.Since(start); elapsed > 5*timeout {
+		t.Errorf("Stat returned after %v, want about %v", elapsed, timeout)
+	}
+	if !filekit.IsCode(err, filekit.ErrCodeTimeout) {
+		t.Fatalf("Stat on a slow backend = %v, want ErrCodeTimeout", err)
+	}
+	if exists, err := cached.FileExists(ctx, "a.txt"); !filekit.IsCode(err, filekit.ErrCodeTimeout) || exists {
+		t.Fatalf("FileExists on a slow backend = %v, %v; want false, ErrCodeTimeout", exists, err)
+	}
+
+	// The timeouts were not cached: once the backend is fast the call reaches it
+	slow.delay.Store(0)
+	before := slow.calls.Load()
+	if info, err := cached.Stat(ctx, "a.txt"); err != nil || info.Size != 1 {
+		t.Fatalf("Stat after recovery = %+v, %v", info, err)
+	}
+	if exists, err := cached.FileExists(ctx, "a.txt"); err != nil || !exists {
+		t.Fatalf("FileExists after recovery = %v, %v; want true", exists, err)
+	}
+	if n := slow.calls.Load() - before; n != 2 {
+		t.Errorf("backend got %d calls after recovery, want 2", n)
+	}
+
+	// Successful results are cached as usual
+	cached.Stat(ctx, "a.txt")
+	if n := slow.calls.Load() - before; n != 2 {
+		t.Errorf("backend got %d calls, want the second Stat served from cache", n)
+	}
+}
+
+func TestCachingFileSystem_OperationTimeoutWaitsForWrites(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	ctx := context.Background()
+	base := memory.New()
+	slow := &stallingFS{FileSystem: base}
+	cached := filekit.NewCachingFileSystem(slow, filekit.NewMemoryCache(), filekit.WithOperationTimeout(timeout))
+
+	// Prime a negative entry that the write must clear
+	if exists, _ := cached.FileExists(ctx, "a.txt"); exists {
+		t.Fatal("a.txt exists before the write")
+	}
+
+	// A backend that ignores its deadline is waited for, not abandoned
+	slow.delay.Store(int64(5 * timeout))
+	start := time.Now()
+	if _, err := cached.Write(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Write on a slow backend: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*timeout {
+		t.Errorf("Write returned after %v, before the backend finished", elapsed)
+	}
+	slow.delay.Store(0)
+	if exists, err := cached.FileExists(ctx, "a.txt"); err != nil || !exists {
+		t.Errorf("FileExists after Write = %v, %v; want true", exists, err)
+	}
+
+	// A backend that honours its deadline fails with ErrCodeTimeout
+	bounded := filekit.NewCachingFileSystem(deadlineFS{base}, filekit.NewMemoryCache(), filekit.WithOperationTimeout(timeout))
+	if err := bounded.Delete(ctx, "a.txt"); !filekit.IsCode(err, filekit.ErrCodeTimeout) {
+		t.Errorf("Delete past the deadline = %v, want ErrCodeTimeout", err)
+	}
+}
+
+func TestCachingFileSystem_OperationTimeoutParentCanceled(t *testing.T) {
+	slow := &stallingFS{FileSystem: memory.New()}
+	slow.delay.Store(int64(time.Second))
+	cached := filekit.NewCachingFileSystem(slow, filekit.NewMemoryCache(), filekit.WithOperationTimeout(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := cached.Stat(ctx, "a.txt"); !filekit.IsCode(err, filekit.ErrCodeAborted) {
+		t.Errorf("Stat with canceled context = %v, want ErrCodeAborted", err)
+	}
+}
+
+func TestCachingFileSystem_NegativeTTL(t *testing.T) {
+	const (
+		ttl         = time.Hour
+		negativeTTL = 30 * time.Millisecond
+	)
+	ctx := context.Background()
+	base := memory.New()
+	seedTree(t, base, map[string]string{"present.txt": "x", "dir/keep.txt": "x"})
+	cached := filekit.NewCachingFileSystem(base, filekit.NewMemoryCache(),
+		filekit.WithCacheTTL(ttl),
+		filekit.WithNegativeTTL(negativeTTL),
+	)
+
+	// Prime positive and negative results
+	for _, p := range []string{"present.txt", "later.txt"} {
+		cached.FileExists(ctx, p)
+		cached.Stat(ctx, p)
+	}
+	cached.DirExists(ctx, "dir")
+	cached.DirExists(ctx, "newdir")
+
+	// Change the backend behind the cache's back
+	seedTree(t, base, map[string]string{"later.txt": "x", "newdir/a.txt": "x"})
+	if err := base.Delete(ctx, "present.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.DeleteDir(ctx, "dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything is still served from the cache
+	if _, err := cached.Stat(ctx, "later.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("Stat(later.txt) before negative TTL = %v, want cached not found", err)
+	}
+	if exists, _ := cached.FileExists(ctx, "later.txt"); exists {
+		t.Error("FileExists(later.txt) before negative TTL = true, want cached false")
+	}
+
+	time.Sleep(2 * negativeTTL)
+
+	// Negative results expired; positive results did not
+	if exists, err := cached.FileExists(ctx, "later.txt"); err != nil || !exists {
+		t.Errorf("FileExists(later.txt) = %v, %v; want negative result expired", exists, err)
+	}
+	if exists, err := cached.DirExists(ctx, "newdir"); err != nil || !exists {
+		t.Errorf("DirExists(newdir) = %v, %v; want negative result expired", exists, err)
+	}
+	if info, err := cached.Stat(ctx, "later.txt"); err != nil || info.Size != 1 {
+		t.Errorf("Stat(later.txt) = %+v, %v; want negative result expired", info, err)
+	}
+	if exists, _ := cached.FileExists(ctx, "present.txt"); !exists {
+		t.Error("FileExists(present.txt) = false, want cached positive result")
+	}
+	if exists, _ := cached.DirExists(ctx, "dir"); !exists {
+		t.Error("DirExists(dir) = false, want cached positive result")
+	}
+	if _, err := cached.Stat(ctx, "present.txt"); err != nil {
+		t.Errorf("Stat(present.txt) = %v, want cached positive result", err)
+	}
+}
+
+func TestCachingFileSystem_NegativeStatCache(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	counter := &stallingFS{FileSystem: base}
+
+	t.Run("not cached by default", func(t *testing.T) {
+		cached := filekit.NewCachingFileSystem(counter, filekit.NewMemoryCache())
+		before := counter.calls.Load()
+		cached.Stat(ctx, "missing.txt")
+		cached.Stat(ctx, "missing.txt")
+		if n := counter.calls.Load() - before; n != 2 {
+			t.Errorf("backend got %d Stat calls, want 2", n)
+		}
+	})
+
+	t.Run("cached with negative TTL", func(t *testing.T) {
+		cached := filekit.NewCachingFileSystem(counter, filekit.NewMemoryCache(), filekit.WithNegativeTTL(time.Minute))
+		before := counter.calls.Load()
+		cached.Stat(ctx, "missing.txt")
+		infos, errs := cached.StatMany(ctx, []string{"missing.txt"})
+		if len(infos) != 0 || !filekit.IsNotFound(errs["missing.txt"]) {
+			t.Errorf("StatMany = %v, %v; want cached not found", infos, errs)
+		}
+		if n := counter.calls.Load() - before; n != 1 {
+			t.Errorf("backend got %d Stat calls, want 1", n)
+		}
+
+		// Writes through the cache clear the negative entry
+		if _, err := cached.Write(ctx, "missing.txt", strings.NewReader("x")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.Stat(ctx, "missing.txt"); err != nil {
+			t.Errorf("Stat after Write = %v, want the new file", err)
+		}
+	})
+}
+
+// richStatFS reports object-store metadata from Stat
+type richStatFS struct {
+	filekit.FileSystem
+	info filekit.FileInfo
+}
+
+func (r *richStatFS) Stat(ctx context.Context, path string) (*filekit.FileInfo, error) {
+	info := r.info
+	return &info, nil
+}
+
+func TestCachingFileSystem_StatKeepsAllFields(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	want := filekit.FileInfo{
+		Name:                 "a.txt",
+		Path:                 "docs/a.txt",
+		Size:                 5,
+		ContentType:          "text/plain",
+		ContentDisposition:   "attachment",
+		Metadata:             map[string]string{"k": "v"},
+		ETag:                 `"abc123"`,
+		Version:              "7",
+		StorageClass:         "STANDARD",
+		ServerSideEncryption: "aws:kms",
+		EncryptionKeyID:      "key-1",
+		Checksum:             "deadbeef",
+		ChecksumAlgorithm:    filekit.ChecksumCRC32C,
+		CreatedAt:            &created,
+	}
+	cached := filekit.NewCachingFileSystem(&richStatFS{FileSystem: memory.New(), info: want}, filekit.NewMemoryCache())
+
+	infos, errs := cached.StatMany(ctx, []string{"docs/a.txt"})
+	if len(errs) != 0 {
+		t.Fatalf("StatMany: %v", errs)
+	}
+	for i := 0; i < 2; i++ { // miss, then hit
+		got, err := cached.Stat(ctx, "docs/a.txt")
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("Stat #%d = %+v, want %+v", i+1, *got, want)
+		}
+		got.Metadata["k"] = "changed"
+	}
+	if got := infos["docs/a.txt"]; got == nil || got.ETag != want.ETag || got.Checksum != want.Checksum {
+		t.Errorf("StatMany = %+v, want the ETag and checksum kept", got)
+	}
+}
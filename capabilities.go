@@ -0,0 +1,129 @@
+package filekit
+
+// ============================================================================
+// Capabilities - Optional interface discovery
+// ============================================================================
+
+// CapabilitySet reports which optional interfaces a filesystem supports.
+// Each field is named after the interface it stands for.
+type CapabilitySet struct {
+	CanCopy           bool
+	CanMove           bool
+	CanWriteLocalFile bool
+	CanSignURL        bool
+	CanChecksum       bool
+	CanWatch          bool
+	CanWatchMany      bool
+	CanReadRange      bool
+	CanTag            bool
+	CanStatMany       bool
+	CanDeleteMany     bool
+	CanListPage       bool
+	HealthChecker     bool
+	ChunkedUploader   bool
+	ChecksumUploader  bool
+}
+
+// Capabilities returns the optional interfaces fs effectively supports.
+//
+// Decorators forward every capability method and fail at call time when the
+// filesystem they wrap lacks it, so a type assertion on a decorator says
+// little. Capabilities looks through decorators that expose Unwrap or
+// Underlying and reports a capability only if every layer implements it. A
+// layer whose IsReadOnly method returns true (ReadOnlyFileSystem) clears the
+// write capabilities: CanCopy, CanMove, CanWriteLocalFile, CanTag,
+// CanDeleteMany, ChunkedUploader and ChecksumUploader.
+//
+// Example:
+//
+//	caps := filekit.Capabilities(fs)
+//	if caps.CanSignURL {
+//	    url, err := fs.(filekit.CanSignURL).SignedURL(ctx, "report.pdf", time.Hour)
+//	}
+func Capabilities(fs FileSystem) CapabilitySet {
+	caps := CapabilitySet{
+		CanCopy: true, CanMove: true, CanWriteLocalFile: true, CanSignURL: true,
+		CanChecksum: true, CanWatch: true, CanWatchMany: true, CanReadRange: true,
+		CanTag: true, CanStatMany: true, CanDeleteMany: true, CanListPage: true,
+		HealthChecker: true, ChunkedUploader: true, ChecksumUploader: true,
+	}
+	for fs != nil {
+		caps = caps.and(capabilitiesOf(fs))
+		if ro, ok := fs.(interface{ IsReadOnly() bool }); ok && ro.IsReadOnly() {
+			caps.CanCopy = false
+			caps.CanMove = false
+			caps.CanWriteLocalFile = false
+			caps.CanTag = false
+			caps.CanDeleteMany = false
+			caps.ChunkedUploader = false
+			caps.ChecksumUploader = false
+		}
+
+		switch f := fs.(type) {
+		case interface{ Unwrap() FileSystem }:
+			fs = f.Unwrap()
+		case interface{ Underlying() FileSystem }:
+			fs = f.Underlying()
+		default:
+			return caps
+		}
+	}
+	return caps
+}
+
+// capabilitiesOf reports the optional interfaces fs itself implements.
+func capabilitiesOf(fs FileSystem) CapabilitySet {
+	_, canCopy := fs.(CanCopy)
+	_, canMove := fs.(CanMove)
+	_, canWriteLocalFile := fs.(CanWriteLocalFile)
+	_, canSignURL := fs.(CanSignURL)
+	_, canChecksum := fs.(CanChecksum)
+	_, canWatch := fs.(CanWatch)
+	_, canWatchMany := fs.(CanWatchMany)
+	_, canReadRange := fs.(CanReadRange)
+	_, canTag := fs.(CanTag)
+	_, canStatMany := fs.(CanStatMany)
+	_, canDeleteMany := fs.(CanDeleteMany)
+	_, canListPage := fs.(CanListPage)
+	_, healthChecker := fs.(HealthChecker)
+	_, chunkedUploader := fs.(ChunkedUploader)
+	_, checksumUploader := fs.(ChecksumUploader)
+	return CapabilitySet{
+		CanCopy:           canCopy,
+		CanMove:           canMove,
+		CanWriteLocalFile: canWriteLocalFile,
+		CanSignURL:        canSignURL,
+		CanChecksum:       canChecksum,
+		CanWatch:          canWatch,
+		CanWatchMany:      canWatchMany,
+		CanReadRange:      canReadRange,
+		CanTag:            canTag,
+		CanStatMany:       canStatMany,
+		CanDeleteMany:     canDeleteMany,
+		CanListPage:       canListPage,
+		HealthChecker:     healthChecker,
+		ChunkedUploader:   chunkedUploader,
+		ChecksumUploader:  checksumUploader,
+	}
+}
+
+// and returns the capabilities present in both c and o.
+func (c CapabilitySet) and(o CapabilitySet) CapabilitySet {
+	return CapabilitySet{
+		CanCopy:           c.CanCopy && o.CanCopy,
+		CanMove:           c.CanMove && o.CanMove,
+		CanWriteLocalFile: c.CanWriteLocalFile && o.CanWriteLocalFile,
+		CanSignURL:        c.CanSignURL && o.CanSignURL,
+		CanChecksum:       c.CanChecksum && o.CanChecksum,
+		CanWatch:          c.CanWatch && o.CanWatch,
+		CanWatchMany:      c.CanWatchMany && o.CanWatchMany,
+		CanReadRange:      c.CanReadRange && o.CanReadRange,
+		CanTag:            c.CanTag && o.CanTag,
+		CanStatMany:       c.CanStatMany && o.CanStatMany,
+		CanDeleteMany:     c.CanDeleteMany && o.CanDeleteMany,
+		CanListPage:       c.CanListPage && o.CanListPage,
+		HealthChecker:     c.HealthChecker && o.HealthChecker,
+		ChunkedUploader:   c.ChunkedUploader && o.ChunkedUploader,
+		ChecksumUploader:  c.ChecksumUploader && o.ChecksumUploader,
+	}
+}
@@ -0,0 +1,52 @@
+package filekit_test
+
+import (
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestCapabilities(t *testing.T) {
+	memoryCaps := filekit.CapabilitySet{
+		CanCopy:           true,
+		CanMove:           true,
+		CanWriteLocalFile: true,
+		CanChecksum:       true,
+		CanWatch:          true,
+		CanStatMany:       true,
+		CanDeleteMany:     true,
+		CanListPage:       true,
+		HealthChecker:     true,
+	}
+
+	tests := []struct {
+		name string
+		fs   filekit.FileSystem
+		want filekit.CapabilitySet
+	}{
+		{"memory", memory.New(), memoryCaps},
+		{
+			// ReadOnlyFileSystem implements every capability method itself
+			"read-only", filekit.NewReadOnlyFileSystem(memory.New()),
+			filekit.CapabilitySet{CanChecksum: true, CanWatch: true, CanStatMany: true},
+		},
+		{
+			// Instrumented has no Watch, ListPage or Ping of its own
+			"instrumented", filekit.NewInstrumentedFileSystem(memory.New(), nil),
+			filekit.CapabilitySet{CanCopy: true, CanMove: true, CanChecksum: true, CanStatMany: true, CanDeleteMany: true},
+		},
+		{
+			"read-only over instrumented", filekit.NewReadOnlyFileSystem(filekit.NewInstrumentedFileSystem(memory.New(), nil)),
+			filekit.CapabilitySet{CanChecksum: true, CanStatMany: true},
+		},
+		{"no capabilities", struct{ filekit.FileSystem }{memory.New()}, filekit.CapabilitySet{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filekit.Capabilities(tt.fs); got != tt.want {
+				t.Errorf("Capabilities() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -264,6 +264,178 @@ func (c *CompositeChangeToken) RegisterChangeCallback(callback func()) (unregist
 	}
 }
 
+// ============================================================================
+// WatchMany
+// ============================================================================
+
+// WatchMany returns a single token that signals when a file matching any of
+// filters changes. Backends implementing CanWatchMany serve all filters from
+// one watcher; for others each filter is watched separately and the child
+// tokens are fanned in.
+//
+// The combined token signals at most once. Child tokens are released when it
+// signals or when ctx is cancelled.
+//
+// Example:
+//
+//	token, err := filekit.WatchMany(ctx, fs.(filekit.CanWatch), "*.json", "*.yaml", "certs/*")
+//	if err != nil {
+//	    return err
+//	}
+//	token.RegisterChangeCallback(reloadConfig)
+func WatchMany(ctx context.Context, w CanWatch, filters ...string) (ChangeToken, error) {
+	if len(filters) == 0 {
+		return nil, NewPathError("watch", "", ErrCodeInvalidInput, "at least one filter is required")
+	}
+	if m, ok := w.(CanWatchMany); ok {
+		return m.WatchMany(ctx, filters...)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	t := &watchManyToken{CallbackChangeToken: NewCallbackChangeToken(), cancel: cancel}
+	for _, filter := range filters {
+		child, err := w.Watch(ctx, filter)
+		if err != nil {
+			t.stop()
+			return nil, err
+		}
+		t.add(child.RegisterChangeCallback(t.fire))
+		if child.HasChanged() {
+			t.fire()
+			break
+		}
+	}
+	context.AfterFunc(ctx, t.stop)
+
+	return t, nil
+}
+
+// watchManyToken fans the callbacks of several child tokens into one
+// CallbackChangeToken.
+type watchManyToken struct {
+	*CallbackChangeToken
+
+	mu          sync.Mutex
+	unregisters []func()
+	stopped     bool
+	cancel      context.CancelFunc
+}
+
+func (t *watchManyToken) fire() {
+	t.SignalChange()
+	t.stop()
+}
+
+// add records a child's unregister function, or calls it right away if the
+// token has already stopped.
+func (t *watchManyToken) add(unregister func()) {
+	t.mu.Lock()
+	if !t.stopped {
+		t.unregisters = append(t.unregisters, unregister)
+		t.mu.Unlock()
+		return
+	}
+	t.mu.Unlock()
+	unregister()
+}
+
+// stop unregisters from every child and cancels their watch context.
+func (t *watchManyToken) stop() {
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+	t.stopped = true
+	unregisters := t.unregisters
+	t.unregisters = nil
+	t.mu.Unlock()
+
+	for _, u := range unregisters {
+		u()
+	}
+	t.cancel()
+}
+
+// ============================================================================
+// Debounced ChangeToken
+// ============================================================================
+
+// Debounce returns a token that collapses bursts of callbacks from token into
+// a single invocation. Each signal restarts a timer of length window, and the
+// callback runs once the timer expires without another signal. A signal
+// arriving after that starts a new burst and a new invocation.
+//
+// This matters for tokens whose callbacks can run more than once, such as a
+// CompositeChangeToken over several watches that an editor's save touches
+// together. HasChanged reports the wrapped token's state and becomes true on
+// the first signal, without waiting for the window. Unregistering stops any
+// pending timer. A window of zero or less returns token unchanged.
+//
+// Example:
+//
+//	token := filekit.Debounce(filekit.NewCompositeChangeToken(configToken, tmpToken), 200*time.Millisecond)
+//	unregister := token.RegisterChangeCallback(reloadConfig)
+//	defer unregister()
+func Debounce(token ChangeToken, window time.Duration) ChangeToken {
+	if window <= 0 {
+		return token
+	}
+	return &debouncedChangeToken{token: token, window: window}
+}
+
+type debouncedChangeToken struct {
+	token  ChangeToken
+	window time.Duration
+}
+
+func (d *debouncedChangeToken) HasChanged() bool {
+	return d.token.HasChanged()
+}
+
+func (d *debouncedChangeToken) ActiveChangeCallbacks() bool {
+	return d.token.ActiveChangeCallbacks()
+}
+
+func (d *debouncedChangeToken) RegisterChangeCallback(callback func()) (unregister func()) {
+	var (
+		mu      sync.Mutex
+		timer   *time.Timer
+		stopped bool
+	)
+	fire := func() {
+		mu.Lock()
+		done := stopped
+		mu.Unlock()
+		if !done {
+			callback()
+		}
+	}
+
+	unregisterSource := d.token.RegisterChangeCallback(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		if timer == nil {
+			timer = time.AfterFunc(d.window, fire)
+		} else {
+			timer.Reset(d.window)
+		}
+	})
+
+	return func() {
+		mu.Lock()
+		stopped = true
+		if timer != nil {
+			timer.Stop()
+		}
+		mu.Unlock()
+		unregisterSource()
+	}
+}
+
 // ============================================================================
 // Static/Cancelled ChangeToken
 // ============================================================================
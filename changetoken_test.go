@@ -0,0 +1,236 @@
+package filekit_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestWatchMany_SignalsOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fs := memory.New()
+
+	token, err := filekit.WatchMany(ctx, fs, "*.json", "*.yaml", "certs/*")
+	if err != nil {
+		t.Fatalf("WatchMany: %v", err)
+	}
+	var calls atomic.Int32
+	fired := make(chan struct{}, 3)
+	token.RegisterChangeCallback(func() {
+		calls.Add(1)
+		fired <- struct{}{}
+	})
+
+	// One change per filter; only the first reaches the callback
+	for _, p := range []string{"certs/server.pem", "app.json", "app.yaml"} {
+		if _, err := fs.Write(ctx, p, strings.NewReader("x")); err != nil {
+			t.Fatalf("Write(%s): %v", p, err)
+		}
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("combined token did not signal")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("callback ran %d times, want 1", n)
+	}
+	if !token.HasChanged() {
+		t.Error("HasChanged = false after a change")
+	}
+}
+
+func TestWatchMany_IgnoresUnmatchedChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fs := memory.New()
+
+	token, err := filekit.WatchMany(ctx, fs, "*.json", "*.yaml")
+	if err != nil {
+		t.Fatalf("WatchMany: %v", err)
+	}
+	if _, err := fs.Write(ctx, "notes.txt", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if token.HasChanged() {
+		t.Error("HasChanged = true after a change matching no filter")
+	}
+}
+
+// fakeWatcher hands out callback tokens and counts unregistered callbacks.
+type fakeWatcher struct {
+	mu           sync.Mutex
+	filters      []string
+	tokens       []*filekit.CallbackChangeToken
+	unregistered atomic.Int32
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, filter string) (filekit.ChangeToken, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	token := filekit.NewCallbackChangeToken()
+	w.filters = append(w.filters, filter)
+	w.tokens = append(w.tokens, token)
+	return &countingToken{CallbackChangeToken: token, unregistered: &w.unregistered}, nil
+}
+
+type countingToken struct {
+	*filekit.CallbackChangeToken
+	unregistered *atomic.Int32
+}
+
+func (t *countingToken) RegisterChangeCallback(callback func()) func() {
+	unregister := t.CallbackChangeToken.RegisterChangeCallback(callback)
+	return func() {
+		unregister()
+		t.unregistered.Add(1)
+	}
+}
+
+func TestWatchMany_ReleasesChildren(t *testing.T) {
+	waitUnregistered := func(t *testing.T, w *fakeWatcher, want int32) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for w.unregistered.Load() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("unregistered %d children, want %d", w.unregistered.Load(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &fakeWatcher{}
+		token, err := filekit.WatchMany(ctx, w, "a", "b", "c")
+		if err != nil {
+			t.Fatalf("WatchMany: %v", err)
+		}
+		var calls atomic.Int32
+		token.RegisterChangeCallback(func() { calls.Add(1) })
+
+		cancel()
+		waitUnregistered(t, w, 3)
+		w.tokens[1].SignalChange()
+		if calls.Load() != 0 || token.HasChanged() {
+			t.Error("combined token signalled after cancel")
+		}
+	})
+
+	t.Run("after signalling", func(t *testing.T) {
+		w := &fakeWatcher{}
+		token, err := filekit.WatchMany(context.Background(), w, "a", "b", "c")
+		if err != nil {
+			t.Fatalf("WatchMany: %v", err)
+		}
+		w.tokens[2].SignalChange()
+		waitUnregistered(t, w, 3)
+		if !token.HasChanged() {
+			t.Error("HasChanged = false after a child signalled")
+		}
+	})
+}
+
+// fakeMultiWatcher implements CanWatchMany and records the filters it got.
+type fakeMultiWatcher struct {
+	fakeWatcher
+	many []string
+}
+
+func (w *fakeMultiWatcher) WatchMany(ctx context.Context, filters ...string) (filekit.ChangeToken, error) {
+	w.many = filters
+	return filekit.NeverChangeToken{}, nil
+}
+
+func TestWatchMany_UsesCanWatchMany(t *testing.T) {
+	w := &fakeMultiWatcher{}
+	if _, err := filekit.WatchMany(context.Background(), w, "a", "b", "c"); err != nil {
+		t.Fatalf("WatchMany: %v", err)
+	}
+	if !slices.Equal(w.many, []string{"a", "b", "c"}) || len(w.filters) != 0 {
+		t.Errorf("WatchMany filters = %v, Watch filters = %v; want all filters in one WatchMany call", w.many, w.filters)
+	}
+
+	if _, err := filekit.WatchMany(context.Background(), w); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("WatchMany with no filters = %v, want ErrCodeInvalidInput", err)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	const window = 50 * time.Millisecond
+	children := []*filekit.CallbackChangeToken{
+		filekit.NewCallbackChangeToken(),
+		filekit.NewCallbackChangeToken(),
+		filekit.NewCallbackChangeToken(),
+		filekit.NewCallbackChangeToken(),
+	}
+	// A composite token runs its callback once per child signal
+	source := filekit.NewCompositeChangeToken(children[0], children[1], children[2], children[3])
+	token := filekit.Debounce(source, window)
+
+	var calls atomic.Int32
+	fired := make(chan struct{}, 4)
+	unregister := token.RegisterChangeCallback(func() {
+		calls.Add(1)
+		fired <- struct{}{}
+	})
+	defer unregister()
+
+	waitFired := func() {
+		t.Helper()
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatal("debounced callback did not run")
+		}
+	}
+
+	for _, c := range children[:3] {
+		c.SignalChange()
+		time.Sleep(window / 10)
+	}
+	if !token.HasChanged() {
+		t.Error("HasChanged = false right after a signal, want true without waiting for the window")
+	}
+	waitFired()
+	time.Sleep(2 * window)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("callback ran %d times for a burst of 3 signals, want 1", n)
+	}
+
+	// A signal after the window is a new burst
+	children[3].SignalChange()
+	waitFired()
+	if n := calls.Load(); n != 2 {
+		t.Errorf("callback ran %d times, want 2", n)
+	}
+}
+
+func TestDebounce_UnregisterStopsPendingTimer(t *testing.T) {
+	source := filekit.NewCallbackChangeToken()
+	token := filekit.Debounce(source, 20*time.Millisecond)
+
+	var calls atomic.Int32
+	unregister := token.RegisterChangeCallback(func() { calls.Add(1) })
+	source.SignalChange()
+	unregister()
+
+	time.Sleep(60 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Errorf("callback ran %d times after unregister, want 0", n)
+	}
+	if !token.HasChanged() {
+		t.Error("HasChanged = false, want the source's state")
+	}
+}
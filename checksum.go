@@ -11,6 +11,7 @@ import (
 	"hash"
 	"hash/crc32"
 	"io"
+	"strings"
 
 	"github.com/cespare/xxhash/v2"
 )
@@ -103,3 +104,122 @@ func VerifyChecksum(ctx context.Context, fs FileSystem, path, expected string, a
 
 	return actual == expected, nil
 }
+
+// ChecksumMismatchError reports that the content read from a file does not
+// match the expected checksum. It is the cause of the ErrCodeIntegrity errors
+// returned by VerifiedRead and CompleteUploadWithChecksum, so callers can
+// detect it with errors.As.
+type ChecksumMismatchError struct {
+	Path string
+	// Part is the chunked upload part that failed verification, or 0 when
+	// the whole file did.
+	Part      int
+	Algorithm ChecksumAlgorithm
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	if e.Part > 0 {
+		return fmt.Sprintf("%s checksum mismatch for %s part %d: expected %s, got %s", e.Algorithm, e.Path, e.Part, e.Expected, e.Actual)
+	}
+	return fmt.Sprintf("%s checksum mismatch for %s: expected %s, got %s", e.Algorithm, e.Path, e.Expected, e.Actual)
+}
+
+// VerifiedRead opens a file for reading and verifies its content against
+// expectedChecksum (hex-encoded) while it is streamed.
+//
+// Content is returned as it is read, so callers must treat it as unverified
+// until the reader reports io.EOF. On a mismatch, the final Read returns an
+// ErrCodeIntegrity error wrapping a *ChecksumMismatchError instead of io.EOF,
+// and Close returns the same error. Closing the reader before EOF only closes
+// it: the content read so far was never verified, and nothing is reported.
+//
+// Example:
+//
+//	r, err := filekit.VerifiedRead(ctx, fs, "backup.tar", result.Checksum, filekit.ChecksumSHA256)
+//	if err != nil {
+//	    return err
+//	}
+//	defer r.Close()
+//	if _, err := io.Copy(dst, r); err != nil {
+//	    var mismatch *filekit.ChecksumMismatchError
+//	    if errors.As(err, &mismatch) {
+//	        // corrupted or tampered file
+//	    }
+//	    return err
+//	}
+func VerifiedRead(ctx context.Context, fs FileSystem, path, expectedChecksum string, algo ChecksumAlgorithm) (io.ReadCloser, error) {
+	h, err := NewHasher(algo)
+	if err != nil {
+		return nil, WrapPath(err, "verified-read", path, ErrCodeNotSupported, err.Error())
+	}
+
+	rc, err := fs.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyingReader{
+		rc:        rc,
+		hasher:    h,
+		path:      path,
+		algorithm: algo,
+		expected:  expectedChecksum,
+	}, nil
+}
+
+// verifyingReader hashes content as it is read and checks the digest at EOF
+type verifyingReader struct {
+	rc        io.ReadCloser
+	hasher    hash.Hash
+	path      string
+	algorithm ChecksumAlgorithm
+	expected  string
+
+	done bool  // EOF reached and digest checked
+	err  error // verification result, returned again on Close
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	if v.done {
+		if v.err != nil {
+			return 0, v.err
+		}
+		return 0, io.EOF
+	}
+
+	n, err := v.rc.Read(p)
+	v.hasher.Write(p[:n])
+	if err == io.EOF {
+		if verr := v.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying reader without reading the rest of the content.
+// It returns the mismatch found at EOF, if any.
+func (v *verifyingReader) Close() error {
+	closeErr := v.rc.Close()
+	if v.err != nil {
+		return v.err
+	}
+	return closeErr
+}
+
+func (v *verifyingReader) verify() error {
+	v.done = true
+	actual := hex.EncodeToString(v.hasher.Sum(nil))
+	if !strings.EqualFold(actual, v.expected) {
+		mismatch := &ChecksumMismatchError{
+			Path:      v.path,
+			Algorithm: v.algorithm,
+			Expected:  v.expected,
+			Actual:    actual,
+		}
+		v.err = WrapPath(mismatch, "verified-read", v.path, ErrCodeIntegrity, mismatch.Error())
+	}
+	return v.err
+}
@@ -0,0 +1,105 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestVerifiedRead_Match(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	_, err := fs.Write(ctx, "report.csv", strings.NewReader("id,total\n1,42\n"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	sum, err := filekit.CalculateChecksum(strings.NewReader("id,total\n1,42\n"), filekit.ChecksumSHA256)
+	if err != nil {
+		t.Fatalf("CalculateChecksum failed: %v", err)
+	}
+
+	r, err := filekit.VerifiedRead(ctx, fs, "report.csv", strings.ToUpper(sum), filekit.ChecksumSHA256)
+	if err != nil {
+		t.Fatalf("VerifiedRead failed: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll returned %v, want no error", err)
+	}
+	if string(data) != "id,total\n1,42\n" {
+		t.Errorf("content = %q", data)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close returned %v, want nil", err)
+	}
+}
+
+func TestVerifiedRead_Corrupted(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	sum, _ := filekit.CalculateChecksum(strings.NewReader("original content"), filekit.ChecksumSHA256)
+
+	// The stored file no longer matches the recorded checksum
+	seedTree(t, fs, map[string]string{"data.bin": "corrupted content"})
+
+	r, err := filekit.VerifiedRead(ctx, fs, "data.bin", sum, filekit.ChecksumSHA256)
+	if err != nil {
+		t.Fatalf("VerifiedRead failed: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if string(data) != "corrupted content" {
+		t.Errorf("content = %q, want the bytes read before EOF", data)
+	}
+
+	var mismatch *filekit.ChecksumMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("ReadAll error = %v, want *ChecksumMismatchError", err)
+	}
+	if mismatch.Expected != sum || mismatch.Algorithm != filekit.ChecksumSHA256 || mismatch.Path != "data.bin" {
+		t.Errorf("unexpected mismatch details: %+v", mismatch)
+	}
+	if !filekit.IsCode(err, filekit.ErrCodeIntegrity) {
+		t.Errorf("expected ErrCodeIntegrity, got %v", err)
+	}
+	if err := r.Close(); !errors.As(err, &mismatch) {
+		t.Errorf("Close error = %v, want *ChecksumMismatchError", err)
+	}
+}
+
+func TestVerifiedRead_CloseBeforeEOF(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{"data.bin": "corrupted content"})
+
+	r, err := filekit.VerifiedRead(ctx, fs, "data.bin", "00000000", filekit.ChecksumCRC32)
+	if err != nil {
+		t.Fatalf("VerifiedRead failed: %v", err)
+	}
+	if _, err := r.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	// Close does not read the rest to verify it, so nothing is reported
+	if err := r.Close(); err != nil {
+		t.Errorf("Close before EOF = %v, want nil", err)
+	}
+}
+
+func TestVerifiedRead_Errors(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+
+	if _, err := filekit.VerifiedRead(ctx, fs, "missing.txt", "abc", filekit.ChecksumSHA256); !filekit.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := filekit.VerifiedRead(ctx, fs, "missing.txt", "abc", "rot13"); !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+		t.Errorf("expected ErrCodeNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestConditionalWrites(t *testing.T) {
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New: %v", err)
+	}
+
+	for name, fs := range map[string]filekit.FileSystem{
+		"memory": memory.New(),
+		"local":  localFS,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			base, err := fs.Write(ctx, "config.json", strings.NewReader(`{"v":0}`), filekit.WithIfNoneMatch("*"))
+			if err != nil {
+				t.Fatalf("create with IfNoneMatch(*): %v", err)
+			}
+			if base.ETag == "" {
+				t.Fatal("WriteResult.ETag is empty")
+			}
+
+			_, err = fs.Write(ctx, "config.json", strings.NewReader(`{"v":1}`), filekit.WithIfNoneMatch("*"))
+			if !errors.Is(err, filekit.ErrPreconditionFailed) || !filekit.IsCode(err, filekit.ErrCodePreconditionFailed) {
+				t.Errorf("second create = %v, want ErrPreconditionFailed", err)
+			}
+
+			// Two writers starting from the same ETag: exactly one wins
+			contents := []string{`{"v":"a"}`, `{"v":"b"}`}
+			errs := make([]error, len(contents))
+			var wg sync.WaitGroup
+			for i, content := range contents {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = fs.Write(ctx, "config.json", strings.NewReader(content), filekit.WithIfMatch(base.ETag))
+				}()
+			}
+			wg.Wait()
+
+			winner := -1
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					if winner >= 0 {
+						t.Fatal("both writers succeeded")
+					}
+					winner = i
+				case !errors.Is(err, filekit.ErrPreconditionFailed):
+					t.Errorf("writer %d: unexpected error %v", i, err)
+				}
+			}
+			if winner < 0 {
+				t.Fatal("neither writer succeeded")
+			}
+			data, err := fs.ReadAll(ctx, "config.json")
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if string(data) != contents[winner] {
+				t.Errorf("content = %s, want the winner's %s", data, contents[winner])
+			}
+
+			// IfNoneMatch with an ETag excludes that exact version
+			_, err = fs.Write(ctx, "config.json", strings.NewReader(`{"v":2}`), filekit.WithIfNoneMatch(base.ETag))
+			if err != nil {
+				t.Errorf("IfNoneMatch(stale etag) = %v, want success", err)
+			}
+
+			_, err = fs.Write(ctx, "missing.json", strings.NewReader("{}"), filekit.WithIfMatch(base.ETag))
+			if !errors.Is(err, filekit.ErrPreconditionFailed) {
+				t.Errorf("IfMatch on a missing file = %v, want ErrPreconditionFailed", err)
+			}
+			if exists, _ := fs.FileExists(ctx, "missing.json"); exists {
+				t.Error("failed conditional write created the file")
+			}
+		})
+	}
+}
+
+func TestCreateExclusive(t *testing.T) {
+	newLocal := func(opts ...local.AdapterOption) filekit.FileSystem {
+		fs, err := local.New(t.TempDir(), opts...)
+		if err != nil {
+			t.Fatalf("local.New: %v", err)
+		}
+		return fs
+	}
+
+	for name, fs := range map[string]filekit.FileSystem{
+		"memory":       memory.New(),
+		"local":        newLocal(),
+		"local/atomic": newLocal(local.WithAtomicWrites(true)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			const writers = 8
+			errs := make([]error, writers)
+			var wg sync.WaitGroup
+			for i := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = filekit.CreateExclusive(ctx, fs, "locks/job.lock", strings.NewReader(string(rune('a'+i))))
+				}()
+			}
+			wg.Wait()
+
+			winner := -1
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					if winner >= 0 {
+						t.Fatalf("writers %d and %d both created the file", winner, i)
+					}
+					winner = i
+				case !errors.Is(err, filekit.ErrExist) || !filekit.IsCode(err, filekit.ErrCodeAlreadyExists):
+					t.Errorf("writer %d: %v, want ErrExist", i, err)
+				}
+			}
+			if winner < 0 {
+				t.Fatal("no writer created the file")
+			}
+			data, err := fs.ReadAll(ctx, "locks/job.lock")
+			if err != nil || string(data) != string(rune('a'+winner)) {
+				t.Errorf("content = %q, %v; want the winner's", data, err)
+			}
+
+			// Overwrite does not weaken the check
+			err = filekit.CreateExclusive(ctx, fs, "locks/job.lock", strings.NewReader("x"), filekit.WithOverwrite(true))
+			if !errors.Is(err, filekit.ErrExist) {
+				t.Errorf("CreateExclusive with overwrite = %v, want ErrExist", err)
+			}
+		})
+	}
+}
+
+func TestConditionalWrites_EncryptedFS(t *testing.T) {
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New: %v", err)
+	}
+	fs, err := filekit.NewEncryptedFS(localFS, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewEncryptedFS: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := filekit.CreateExclusive(ctx, fs, "job.lock", strings.NewReader("first")); err != nil {
+		t.Fatalf("CreateExclusive: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fs.Write(ctx, "job.lock", strings.NewReader("second"), filekit.WithIfNoneMatch("*"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, filekit.ErrPreconditionFailed) {
+			t.Errorf("Write with If-None-Match over an existing file = %v, want ErrPreconditionFailed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("conditional Write through EncryptedFS did not return")
+	}
+	if err := filekit.CreateExclusive(ctx, fs, "job.lock", strings.NewReader("third")); !errors.Is(err, filekit.ErrExist) {
+		t.Errorf("second CreateExclusive = %v, want ErrExist", err)
+	}
+
+	data, err := fs.ReadAll(ctx, "job.lock")
+	if err != nil || string(data) != "first" {
+		t.Errorf("content = %q, %v; want the first write's", data, err)
+	}
+}
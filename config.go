@@ -1,63 +1,83 @@
 package filekit
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
 	"github.com/gobeaver/beaver-kit/config"
+	"gopkg.in/yaml.v3"
 )
 
+// envPrefix is the prefix config.Load puts in front of the env tag names.
+const envPrefix = "BEAVER_"
+
 type Config struct {
-	// Default driver to use (local, s3)
-	Driver string `env:"FILEKIT_DRIVER,default:local"`
+	// Default driver to use (local, s3, gcs, azure, sftp, memory, zip)
+	Driver string `env:"FILEKIT_DRIVER,default:local" json:"driver" yaml:"driver"`
 
 	// Local driver configuration
-	LocalBasePath string `env:"FILEKIT_LOCAL_BASE_PATH,default:./storage"`
+	LocalBasePath string `env:"FILEKIT_LOCAL_BASE_PATH,default:./storage" json:"local_base_path" yaml:"local_base_path"`
 
 	// S3 driver configuration
-	S3Region          string `env:"FILEKIT_S3_REGION,default:us-east-1"`
-	S3Bucket          string `env:"FILEKIT_S3_BUCKET"`
-	S3Prefix          string `env:"FILEKIT_S3_PREFIX"`
-	S3Endpoint        string `env:"FILEKIT_S3_ENDPOINT"`
-	S3AccessKeyID     string `env:"FILEKIT_S3_ACCESS_KEY_ID"`
-	S3SecretAccessKey string `env:"FILEKIT_S3_SECRET_ACCESS_KEY"`
-	S3ForcePathStyle  bool   `env:"FILEKIT_S3_FORCE_PATH_STYLE,default:false"`
+	S3Region          string `env:"FILEKIT_S3_REGION,default:us-east-1" json:"s3_region" yaml:"s3_region"`
+	S3Bucket          string `env:"FILEKIT_S3_BUCKET" json:"s3_bucket" yaml:"s3_bucket"`
+	S3Prefix          string `env:"FILEKIT_S3_PREFIX" json:"s3_prefix" yaml:"s3_prefix"`
+	S3Endpoint        string `env:"FILEKIT_S3_ENDPOINT" json:"s3_endpoint" yaml:"s3_endpoint"`
+	S3AccessKeyID     string `env:"FILEKIT_S3_ACCESS_KEY_ID" json:"s3_access_key_id" yaml:"s3_access_key_id"`
+	S3SecretAccessKey string `env:"FILEKIT_S3_SECRET_ACCESS_KEY" json:"s3_secret_access_key" yaml:"s3_secret_access_key"`
+	S3ForcePathStyle  bool   `env:"FILEKIT_S3_FORCE_PATH_STYLE,default:false" json:"s3_force_path_style" yaml:"s3_force_path_style"`
 
 	// GCS (Google Cloud Storage) driver configuration
-	GCSBucket          string `env:"FILEKIT_GCS_BUCKET"`
-	GCSPrefix          string `env:"FILEKIT_GCS_PREFIX"`
-	GCSCredentialsFile string `env:"FILEKIT_GCS_CREDENTIALS_FILE"` // Path to service account JSON
-	GCSProjectID       string `env:"FILEKIT_GCS_PROJECT_ID"`
+	GCSBucket          string `env:"FILEKIT_GCS_BUCKET" json:"gcs_bucket" yaml:"gcs_bucket"`
+	GCSPrefix          string `env:"FILEKIT_GCS_PREFIX" json:"gcs_prefix" yaml:"gcs_prefix"`
+	GCSCredentialsFile string `env:"FILEKIT_GCS_CREDENTIALS_FILE" json:"gcs_credentials_file" yaml:"gcs_credentials_file"` // Path to service account JSON; default credentials when empty
+	GCSProjectID       string `env:"FILEKIT_GCS_PROJECT_ID" json:"gcs_project_id" yaml:"gcs_project_id"`
 
 	// Azure Blob Storage driver configuration
-	AzureAccountName   string `env:"FILEKIT_AZURE_ACCOUNT_NAME"`
-	AzureAccountKey    string `env:"FILEKIT_AZURE_ACCOUNT_KEY"`
-	AzureContainerName string `env:"FILEKIT_AZURE_CONTAINER_NAME"`
-	AzurePrefix        string `env:"FILEKIT_AZURE_PREFIX"`
-	AzureEndpoint      string `env:"FILEKIT_AZURE_ENDPOINT"` // Optional custom endpoint
+	AzureAccountName   string `env:"FILEKIT_AZURE_ACCOUNT_NAME" json:"azure_account_name" yaml:"azure_account_name"`
+	AzureAccountKey    string `env:"FILEKIT_AZURE_ACCOUNT_KEY" json:"azure_account_key" yaml:"azure_account_key"`
+	AzureContainerName string `env:"FILEKIT_AZURE_CONTAINER_NAME" json:"azure_container_name" yaml:"azure_container_name"`
+	AzurePrefix        string `env:"FILEKIT_AZURE_PREFIX" json:"azure_prefix" yaml:"azure_prefix"`
+	AzureEndpoint      string `env:"FILEKIT_AZURE_ENDPOINT" json:"azure_endpoint" yaml:"azure_endpoint"` // Optional custom endpoint
+
+	// Alternatives to the account key: a connection string, or the managed
+	// identity of the host (requires AzureAccountName or AzureEndpoint)
+	AzureConnectionString   string `env:"FILEKIT_AZURE_CONNECTION_STRING" json:"azure_connection_string" yaml:"azure_connection_string"`
+	AzureUseManagedIdentity bool   `env:"FILEKIT_AZURE_USE_MANAGED_IDENTITY,default:false" json:"azure_use_managed_identity" yaml:"azure_use_managed_identity"`
 
 	// SFTP driver configuration
-	SFTPHost       string `env:"FILEKIT_SFTP_HOST"`
-	SFTPPort       int    `env:"FILEKIT_SFTP_PORT,default:22"`
-	SFTPUsername   string `env:"FILEKIT_SFTP_USERNAME"`
-	SFTPPassword   string `env:"FILEKIT_SFTP_PASSWORD"`
-	SFTPPrivateKey string `env:"FILEKIT_SFTP_PRIVATE_KEY"` // Path to private key file
-	SFTPBasePath   string `env:"FILEKIT_SFTP_BASE_PATH"`
+	SFTPHost       string `env:"FILEKIT_SFTP_HOST" json:"sftp_host" yaml:"sftp_host"`
+	SFTPPort       int    `env:"FILEKIT_SFTP_PORT,default:22" json:"sftp_port" yaml:"sftp_port"`
+	SFTPUsername   string `env:"FILEKIT_SFTP_USERNAME" json:"sftp_username" yaml:"sftp_username"`
+	SFTPPassword   string `env:"FILEKIT_SFTP_PASSWORD" json:"sftp_password" yaml:"sftp_password"`
+	SFTPPrivateKey string `env:"FILEKIT_SFTP_PRIVATE_KEY" json:"sftp_private_key" yaml:"sftp_private_key"` // Path to private key file
+	SFTPBasePath   string `env:"FILEKIT_SFTP_BASE_PATH" json:"sftp_base_path" yaml:"sftp_base_path"`
+
+	// ZIP driver configuration
+	ZipPath string `env:"FILEKIT_ZIP_PATH" json:"zip_path" yaml:"zip_path"` // Path to the ZIP archive
+	ZipMode string `env:"FILEKIT_ZIP_MODE" json:"zip_mode" yaml:"zip_mode"` // open (read-only), create, or open-or-create (default)
 
 	// Default upload options
-	DefaultVisibility       string `env:"FILEKIT_DEFAULT_VISIBILITY,default:private"`
-	DefaultCacheControl     string `env:"FILEKIT_DEFAULT_CACHE_CONTROL"`
-	DefaultOverwrite        bool   `env:"FILEKIT_DEFAULT_OVERWRITE,default:false"`
-	DefaultPreserveFilename bool   `env:"FILEKIT_DEFAULT_PRESERVE_FILENAME,default:false"`
+	DefaultVisibility       string `env:"FILEKIT_DEFAULT_VISIBILITY,default:private" json:"default_visibility" yaml:"default_visibility"`
+	DefaultCacheControl     string `env:"FILEKIT_DEFAULT_CACHE_CONTROL" json:"default_cache_control" yaml:"default_cache_control"`
+	DefaultOverwrite        bool   `env:"FILEKIT_DEFAULT_OVERWRITE,default:false" json:"default_overwrite" yaml:"default_overwrite"`
+	DefaultPreserveFilename bool   `env:"FILEKIT_DEFAULT_PRESERVE_FILENAME,default:false" json:"default_preserve_filename" yaml:"default_preserve_filename"`
 
 	// File validation defaults
-	MaxFileSize       int64  `env:"FILEKIT_MAX_FILE_SIZE,default:10485760"` // 10MB default
-	AllowedMimeTypes  string `env:"FILEKIT_ALLOWED_MIME_TYPES"`             // comma-separated
-	BlockedMimeTypes  string `env:"FILEKIT_BLOCKED_MIME_TYPES"`             // comma-separated
-	AllowedExtensions string `env:"FILEKIT_ALLOWED_EXTENSIONS"`             // comma-separated
-	BlockedExtensions string `env:"FILEKIT_BLOCKED_EXTENSIONS"`             // comma-separated
+	MaxFileSize       int64  `env:"FILEKIT_MAX_FILE_SIZE,default:10485760" json:"max_file_size" yaml:"max_file_size"` // 10MB default
+	AllowedMimeTypes  string `env:"FILEKIT_ALLOWED_MIME_TYPES" json:"allowed_mime_types" yaml:"allowed_mime_types"`   // comma-separated
+	BlockedMimeTypes  string `env:"FILEKIT_BLOCKED_MIME_TYPES" json:"blocked_mime_types" yaml:"blocked_mime_types"`   // comma-separated
+	AllowedExtensions string `env:"FILEKIT_ALLOWED_EXTENSIONS" json:"allowed_extensions" yaml:"allowed_extensions"`   // comma-separated
+	BlockedExtensions string `env:"FILEKIT_BLOCKED_EXTENSIONS" json:"blocked_extensions" yaml:"blocked_extensions"`   // comma-separated
 
 	// Encryption settings
-	EncryptionEnabled   bool   `env:"FILEKIT_ENCRYPTION_ENABLED,default:false"`
-	EncryptionAlgorithm string `env:"FILEKIT_ENCRYPTION_ALGORITHM,default:AES-256-GCM"`
-	EncryptionKey       string `env:"FILEKIT_ENCRYPTION_KEY"`
+	EncryptionEnabled   bool   `env:"FILEKIT_ENCRYPTION_ENABLED,default:false" json:"encryption_enabled" yaml:"encryption_enabled"`
+	EncryptionAlgorithm string `env:"FILEKIT_ENCRYPTION_ALGORITHM,default:AES-256-GCM" json:"encryption_algorithm" yaml:"encryption_algorithm"`
+	EncryptionKey       string `env:"FILEKIT_ENCRYPTION_KEY" json:"encryption_key" yaml:"encryption_key"`
 }
 
 // GetConfig returns config loaded from environment
@@ -68,3 +88,167 @@ func GetConfig() (*Config, error) {
 	}
 	return cfg, nil
 }
+
+// LoadConfig reads a config file in the given format ("json", or "yaml"/"yml")
+// from r. Keys are the env names without the FILEKIT_ prefix, in lower case
+// (FILEKIT_S3_BUCKET is s3_bucket); unknown keys are rejected.
+//
+// Fields missing from the file keep their defaults, and environment
+// variables that are set override the file, so deployments can patch a
+// shared file per environment. The result is not validated; call Validate.
+//
+// Example:
+//
+//	f, err := os.Open("filekit.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	cfg, err := filekit.LoadConfig(f, "yaml")
+func LoadConfig(r io.Reader, format string) (*Config, error) {
+	// Start from the defaults, with the environment applied
+	env, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg := *env
+
+	switch strings.ToLower(format) {
+	case "json":
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return nil, NewError(ErrCodeInvalidInput, "unsupported config format "+format+" (want json or yaml)")
+	}
+	if err != nil && err != io.EOF {
+		return nil, Wrap(err, ErrCodeInvalidInput, "failed to parse "+format+" config")
+	}
+
+	overlayEnv(&cfg, env)
+	return &cfg, nil
+}
+
+// overlayEnv copies the fields whose environment variable is set from env
+// into cfg.
+func overlayEnv(cfg, env *Config) {
+	dst, src := reflect.ValueOf(cfg).Elem(), reflect.ValueOf(env).Elem()
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("env"), ",")
+		if name != "" && os.Getenv(envPrefix+name) != "" {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
+// Validate checks that the fields the selected driver needs are set and
+// consistent. Every problem is reported, not just the first: the returned
+// error has ErrCodeValidation, joins all messages, and wraps a MultiError
+// with one error per problem. Each of those carries the config key it is
+// about as the "field" detail.
+func (c *Config) Validate() error {
+	if c.Driver == "" {
+		return NewError(ErrCodeValidation, "driver is required").WithDetail("field", "driver")
+	}
+
+	errs := NewMultiError("validate-config")
+	invalid := func(field, message string) {
+		errs.Add(NewError(ErrCodeValidation, message).WithDetail("field", field))
+	}
+
+	switch c.Driver {
+	case "local":
+		if c.LocalBasePath == "" {
+			invalid("local_base_path", "local base path is required for local driver")
+		}
+	case "s3":
+		if c.S3Bucket == "" {
+			invalid("s3_bucket", "S3 bucket is required for S3 driver")
+		}
+		if c.S3Region == "" {
+			invalid("s3_region", "S3 region is required for S3 driver")
+		}
+		// Access keys can be provided via IAM roles, but a static pair must be complete
+		if c.S3AccessKeyID != "" && c.S3SecretAccessKey == "" {
+			invalid("s3_secret_access_key", "S3 secret access key is required with an access key ID")
+		}
+		if c.S3SecretAccessKey != "" && c.S3AccessKeyID == "" {
+			invalid("s3_access_key_id", "S3 access key ID is required with a secret access key")
+		}
+	case "gcs":
+		if c.GCSBucket == "" {
+			invalid("gcs_bucket", "GCS bucket is required for GCS driver")
+		}
+		// Credentials fall back to Application Default Credentials
+	case "azure":
+		if c.AzureContainerName == "" {
+			invalid("azure_container_name", "azure container name is required for azure driver")
+		}
+		switch {
+		case c.AzureConnectionString != "":
+		case c.AzureUseManagedIdentity:
+			if c.AzureAccountName == "" && c.AzureEndpoint == "" {
+				invalid("azure_account_name", "azure account name or endpoint is required for managed identity")
+			}
+		case c.AzureAccountName == "" || c.AzureAccountKey == "":
+			field := "azure_account_key"
+			if c.AzureAccountName == "" {
+				field = "azure_account_name"
+			}
+			invalid(field, "azure account name and key, a connection string, or managed identity is required for azure driver")
+		}
+	case "sftp":
+		if c.SFTPHost == "" {
+			invalid("sftp_host", "SFTP host is required for SFTP driver")
+		}
+		if c.SFTPUsername == "" {
+			invalid("sftp_username", "SFTP username is required for SFTP driver")
+		}
+		if c.SFTPPassword == "" && c.SFTPPrivateKey == "" {
+			invalid("sftp_password", "SFTP password or private key is required for SFTP driver")
+		}
+		if c.SFTPPort < 0 || c.SFTPPort > 65535 {
+			invalid("sftp_port", fmt.Sprintf("invalid SFTP port: %d", c.SFTPPort))
+		}
+	case "memory":
+		// No configuration needed
+	case "zip":
+		if c.ZipPath == "" {
+			invalid("zip_path", "ZIP path is required for zip driver")
+		}
+		switch c.ZipMode {
+		case "", "open", "create", "open-or-create":
+		default:
+			invalid("zip_mode", fmt.Sprintf("invalid ZIP mode %q (want open, create or open-or-create)", c.ZipMode))
+		}
+	default:
+		// Drivers registered by third-party packages validate their own config
+		factoryMutex.RLock()
+		_, registered := driverFactories[c.Driver]
+		factoryMutex.RUnlock()
+		if !registered {
+			invalid("driver", "unknown driver: "+c.Driver)
+		}
+	}
+	if c.EncryptionEnabled && c.EncryptionKey == "" {
+		invalid("encryption_key", "encryption key is required when encryption is enabled")
+	}
+
+	if !errs.HasErrors() {
+		return nil
+	}
+	msgs := make([]string, len(errs.Errors))
+	fields := make([]string, len(errs.Errors))
+	for i, err := range errs.Errors {
+		fe := err.(*FileError)
+		msgs[i] = fe.Message
+		fields[i] = fe.Detail["field"].(string)
+	}
+	return Wrap(errs, ErrCodeValidation, "invalid "+c.Driver+" config: "+strings.Join(msgs, "; ")).
+		WithDetail("fields", fields)
+}
@@ -0,0 +1,73 @@
+package filekit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	_ "github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestNewFromConfig_Memory(t *testing.T) {
+	ctx := context.Background()
+	fs, err := filekit.NewFromConfig(&filekit.Config{Driver: "memory"})
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+	if _, ok := fs.(*memory.Adapter); !ok {
+		t.Fatalf("got %T, want *memory.Adapter", fs)
+	}
+
+	if _, err := fs.Write(ctx, "hello.txt", strings.NewReader("hi")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data, err := fs.ReadAll(ctx, "hello.txt")
+	if err != nil || string(data) != "hi" {
+		t.Fatalf("ReadAll = %q, %v; want hi", data, err)
+	}
+}
+
+func TestNewFromConfig_Local(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fs, err := filekit.NewFromConfig(&filekit.Config{Driver: "local", LocalBasePath: dir})
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+
+	if _, err := fs.Write(ctx, "docs/a.txt", strings.NewReader("local")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if exists, err := fs.FileExists(ctx, "docs/a.txt"); err != nil || !exists {
+		t.Fatalf("FileExists = %v, %v; want true", exists, err)
+	}
+}
+
+func TestNewFromConfig_RequiredFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    filekit.Config
+		errMsg string
+	}{
+		{"local without base path", filekit.Config{Driver: "local"}, "local base path is required"},
+		{"gcs without bucket", filekit.Config{Driver: "gcs"}, "GCS bucket is required"},
+		{"azure without container", filekit.Config{Driver: "azure"}, "azure container name is required"},
+		{"sftp without host", filekit.Config{Driver: "sftp"}, "SFTP host is required"},
+		{"zip without path", filekit.Config{Driver: "zip"}, "ZIP path is required"},
+		{"unregistered driver", filekit.Config{Driver: "ftp"}, "unknown driver: ftp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := filekit.NewFromConfig(&tt.cfg)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
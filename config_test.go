@@ -1,7 +1,10 @@
 package filekit
 
 import (
+	"errors"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -195,3 +198,89 @@ func TestGetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		cfg, err := LoadConfig(strings.NewReader(`{
+			"driver": "s3",
+			"s3_bucket": "uploads",
+			"s3_region": "eu-west-1",
+			"s3_access_key_id": "key",
+			"s3_secret_access_key": "secret",
+			"s3_force_path_style": true
+		}`), "json")
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.Driver != "s3" || cfg.S3Bucket != "uploads" || cfg.S3Region != "eu-west-1" || !cfg.S3ForcePathStyle {
+			t.Errorf("LoadConfig() = %+v, want the file's S3 settings", cfg)
+		}
+		// Fields missing from the file keep their defaults
+		if cfg.MaxFileSize != 10485760 || cfg.DefaultVisibility != "private" {
+			t.Errorf("MaxFileSize = %d, DefaultVisibility = %q; want the defaults", cfg.MaxFileSize, cfg.DefaultVisibility)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() error = %v, want nil for a complete S3 config", err)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		cfg, err := LoadConfig(strings.NewReader("driver: sftp\nsftp_host: files.example.com\nsftp_port: 2222\nsftp_username: deploy\nsftp_private_key: /etc/keys/id_ed25519\n"), "yaml")
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.SFTPHost != "files.example.com" || cfg.SFTPPort != 2222 || cfg.SFTPUsername != "deploy" {
+			t.Errorf("LoadConfig() = %+v, want the file's SFTP settings", cfg)
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() error = %v", err)
+		}
+	})
+
+	t.Run("environment overrides the file", func(t *testing.T) {
+		t.Setenv("BEAVER_FILEKIT_S3_BUCKET", "from-env")
+		cfg, err := LoadConfig(strings.NewReader(`{"driver": "s3", "s3_bucket": "from-file", "s3_prefix": "tenant/"}`), "json")
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if cfg.S3Bucket != "from-env" || cfg.S3Prefix != "tenant/" {
+			t.Errorf("S3Bucket = %q, S3Prefix = %q; want from-env, tenant/", cfg.S3Bucket, cfg.S3Prefix)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for name, tc := range map[string]struct{ input, format string }{
+			"unknown key":    {`{"s3_bukcet": "typo"}`, "json"},
+			"malformed":      {`{"driver":`, "json"},
+			"unknown format": {`driver = "s3"`, "toml"},
+			"yaml unknown":   {"s3_bukcet: typo\n", "yml"},
+		} {
+			if _, err := LoadConfig(strings.NewReader(tc.input), tc.format); !IsCode(err, ErrCodeInvalidInput) {
+				t.Errorf("%s: LoadConfig() error = %v, want ErrCodeInvalidInput", name, err)
+			}
+		}
+	})
+}
+
+func TestConfigValidate_IncompleteS3(t *testing.T) {
+	cfg := &Config{Driver: "s3", S3AccessKeyID: "key"}
+
+	err := cfg.Validate()
+	if !IsCode(err, ErrCodeValidation) {
+		t.Fatalf("Validate() error = %v, want ErrCodeValidation", err)
+	}
+	for _, msg := range []string{"S3 bucket is required", "S3 region is required", "S3 secret access key is required"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Validate() error %q does not mention %q", err, msg)
+		}
+	}
+
+	var fe *FileError
+	if !errors.As(err, &fe) || !reflect.DeepEqual(fe.Detail["fields"], []string{"s3_bucket", "s3_region", "s3_secret_access_key"}) {
+		t.Errorf("fields detail = %v, want every missing key", fe.Detail["fields"])
+	}
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 3 {
+		t.Errorf("Validate() error = %v, want a MultiError with 3 entries", err)
+	}
+}
@@ -0,0 +1,73 @@
+package filekit
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+)
+
+// ============================================================================
+// Content Type Resolution - Shared by every driver
+// ============================================================================
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// ResolveContentType returns the content type drivers store for a file
+// written to path. The first of these that yields a type wins:
+//
+//  1. optType, the value of WithContentType
+//  2. the extension of path, looked up with MIMEForExtension
+//  3. sniffing head, the first bytes of the content, if any
+//  4. "application/octet-stream"
+//
+// WithForceContentType skips resolution altogether; drivers then store the
+// forced value as is.
+func ResolveContentType(path, optType string, head []byte) string {
+	if optType != "" {
+		return optType
+	}
+	if contentType := MIMEForExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	if len(head) > 0 {
+		return http.DetectContentType(head)
+	}
+	return "application/octet-stream"
+}
+
+// PeekContentType is ResolveContentType for content that has not been read
+// yet. It only reads from r when neither optType nor the extension of path
+// decide the type, and returns a reader that yields the full content: r
+// itself for an io.ReadSeeker (which is seeked back) or a *bytes.Buffer
+// (which is not consumed), or a reader that replays the peeked bytes.
+func PeekContentType(path, optType string, r io.Reader) (string, io.Reader, error) {
+	if optType != "" {
+		return optType, r, nil
+	}
+	if contentType := MIMEForExtension(filepath.Ext(path)); contentType != "" {
+		return contentType, r, nil
+	}
+
+	if buf, ok := r.(*bytes.Buffer); ok {
+		head := buf.Bytes()
+		return ResolveContentType(path, "", head[:min(len(head), sniffLen)]), r, nil
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", r, err
+	}
+	head = head[:n]
+	contentType := ResolveContentType(path, "", head)
+
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		if _, err := seeker.Seek(int64(-n), io.SeekCurrent); err != nil {
+			return "", r, err
+		}
+		return contentType, r, nil
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
@@ -0,0 +1,86 @@
+package filekit_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestResolveContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		optType string
+		head    []byte
+		want    string
+	}{
+		{"option wins", "data.json", "text/x-custom", pngHeader, "text/x-custom"},
+		{"extension", "data.json", "", nil, "application/json"},
+		{"extension before content", "data.json", "", pngHeader, "application/json"},
+		{"uppercase extension", "PHOTO.JPG", "", nil, "image/jpeg"},
+		{"sniffed content", "upload", "", pngHeader, "image/png"},
+		{"unknown extension sniffed", "upload.nope42", "", []byte("<html><body>hi"), "text/html; charset=utf-8"},
+		{"nothing to go on", "upload", "", nil, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filekit.ResolveContentType(tt.path, tt.optType, tt.head); got != tt.want {
+				t.Errorf("ResolveContentType(%q, %q) = %q, want %q", tt.path, tt.optType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeekContentType(t *testing.T) {
+	content := string(pngHeader) + strings.Repeat("x", 1024)
+
+	readers := []struct {
+		name       string
+		r          io.Reader
+		sameReader bool
+	}{
+		{"seeker", strings.NewReader(content), true},
+		{"buffer", bytes.NewBufferString(content), true},
+		{"stream", io.MultiReader(strings.NewReader(content)), false},
+	}
+	for _, tt := range readers {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, body, err := filekit.PeekContentType("upload", "", tt.r)
+			if err != nil {
+				t.Fatalf("PeekContentType: %v", err)
+			}
+			if contentType != "image/png" {
+				t.Errorf("content type = %q, want image/png", contentType)
+			}
+			if tt.sameReader && body != tt.r {
+				t.Error("expected the original reader back")
+			}
+			if data, _ := io.ReadAll(body); string(data) != content {
+				t.Errorf("body has %d bytes, want the full %d bytes of content", len(data), len(content))
+			}
+		})
+	}
+
+	t.Run("extension does not read", func(t *testing.T) {
+		r := io.MultiReader(strings.NewReader(content))
+		contentType, body, err := filekit.PeekContentType("data.json", "", r)
+		if err != nil || contentType != "application/json" || body != r {
+			t.Errorf("PeekContentType = %q, %v, same reader %v; want application/json and r", contentType, err, body == r)
+		}
+	})
+
+	t.Run("short content", func(t *testing.T) {
+		contentType, body, err := filekit.PeekContentType("note", "", io.MultiReader(strings.NewReader("hello")))
+		if err != nil || contentType != "text/plain; charset=utf-8" {
+			t.Errorf("PeekContentType = %q, %v; want text/plain", contentType, err)
+		}
+		if data, _ := io.ReadAll(body); string(data) != "hello" {
+			t.Errorf("body = %q, want %q", data, "hello")
+		}
+	})
+}
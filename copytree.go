@@ -0,0 +1,180 @@
+package filekit
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// CopyTree - Recursive copy between filesystems
+// ============================================================================
+
+// CopyTreeOptions configures CopyTree behavior.
+type CopyTreeOptions struct {
+	// Concurrency is the number of files copied in parallel.
+	// Default: 1
+	Concurrency int
+
+	// SkipExisting skips files that already exist at the destination.
+	// When false, existing destination files are overwritten.
+	// Default: false
+	SkipExisting bool
+
+	// Progress is called after each file is copied or skipped.
+	// done is the number of files processed so far, total is the number of files to copy.
+	// Calls are serialized, so the callback does not need to be thread-safe.
+	Progress func(done, total int)
+}
+
+// CopyTreeOption is a functional option for configuring CopyTree.
+type CopyTreeOption func(*CopyTreeOptions)
+
+// WithConcurrency sets the number of files copied in parallel.
+func WithConcurrency(n int) CopyTreeOption {
+	return func(o *CopyTreeOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithSkipExisting skips files that already exist at the destination.
+func WithSkipExisting() CopyTreeOption {
+	return func(o *CopyTreeOptions) {
+		o.SkipExisting = true
+	}
+}
+
+// WithProgress sets a callback invoked after each file is processed.
+func WithProgress(fn func(done, total int)) CopyTreeOption {
+	return func(o *CopyTreeOptions) {
+		o.Progress = fn
+	}
+}
+
+// CopyTree recursively copies everything under srcPath in src to dstPath in dst.
+// Directory structure is recreated and each file is streamed via Read→Write, or
+// copied natively when src and dst are the same backend and it implements [CanCopy].
+//
+// Failures of individual files do not stop the copy; they are collected and
+// returned as a [MultiError] (or the single error if only one file failed).
+//
+// Example:
+//
+//	// Mirror a local directory into S3 with 8 parallel uploads
+//	err := filekit.CopyTree(ctx, localFS, s3FS, "site", "backups/site",
+//	    filekit.WithConcurrency(8),
+//	    filekit.WithSkipExisting(),
+//	)
+func CopyTree(ctx context.Context, src, dst FileSystem, srcPath, dstPath string, opts ...CopyTreeOption) error {
+	options := CopyTreeOptions{Concurrency: 1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = 1
+	}
+
+	entries, err := src.ListContents(ctx, srcPath, true)
+	if err != nil {
+		return err
+	}
+
+	srcPrefix := strings.Trim(srcPath, "/")
+	relative := func(p string) string {
+		p = strings.Trim(p, "/")
+		if srcPrefix == "" {
+			return p
+		}
+		return strings.TrimPrefix(strings.TrimPrefix(p, srcPrefix), "/")
+	}
+
+	// Recreate directories first so files land in existing parents
+	if err := dst.CreateDir(ctx, dstPath); err != nil {
+		return err
+	}
+	var files []FileInfo
+	for i := range entries {
+		if entries[i].IsDir {
+			if err := dst.CreateDir(ctx, path.Join(dstPath, relative(entries[i].Path))); err != nil {
+				return err
+			}
+			continue
+		}
+		files = append(files, entries[i])
+	}
+
+	copier, native := src.(CanCopy)
+	native = native && src == dst
+
+	var (
+		mu   sync.Mutex
+		done int
+		wg   sync.WaitGroup
+	)
+	errs := NewMultiError("copytree")
+	sem := make(chan struct{}, options.Concurrency)
+
+	finish := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs.Add(err)
+		done++
+		if options.Progress != nil {
+			options.Progress(done, len(files))
+		}
+	}
+
+	for i := range files {
+		if err := FromContext(ctx, "copytree", files[i].Path); err != nil {
+			wg.Wait()
+			return err
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(file FileInfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			target := path.Join(dstPath, relative(file.Path))
+
+			if options.SkipExisting {
+				exists, err := dst.FileExists(ctx, target)
+				if err != nil || exists {
+					finish(err)
+					return
+				}
+			}
+
+			if native {
+				finish(copier.Copy(ctx, file.Path, target))
+				return
+			}
+			finish(copyFile(ctx, src, dst, &file, target))
+		}(files[i])
+	}
+	wg.Wait()
+
+	return errs.Err()
+}
+
+// copyFile streams a single file from src to dst, preserving content type and metadata.
+func copyFile(ctx context.Context, src, dst FileSystem, file *FileInfo, target string) error {
+	reader, err := src.Read(ctx, file.Path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	opts := []Option{WithOverwrite(true)}
+	if file.ContentType != "" {
+		opts = append(opts, WithContentType(file.ContentType))
+	}
+	if len(file.Metadata) > 0 {
+		opts = append(opts, WithMetadata(file.Metadata))
+	}
+
+	_, err = dst.Write(ctx, target, reader, opts...)
+	return err
+}
@@ -0,0 +1,135 @@
+package filekit_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func seedTree(t *testing.T, fs filekit.FileSystem, files map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+	for p, content := range files {
+		if _, err := fs.Write(ctx, p, strings.NewReader(content)); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	dst := memory.New()
+
+	files := map[string]string{
+		"site/index.html":        "<html></html>",
+		"site/css/main.css":      "body{}",
+		"site/img/a/logo.png":    "png-bytes",
+		"site/img/a/b/deep.txt":  "deep",
+		"other/not-copied.txt":   "nope",
+		"site/js/app/vendor.js":  "vendor",
+		"site/js/app/bundle.js":  "bundle",
+		"site/js/app/chunk-1.js": "chunk",
+	}
+	seedTree(t, src, files)
+	if err := src.CreateDir(ctx, "site/empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var calls, lastDone, lastTotal int
+	err := filekit.CopyTree(ctx, src, dst, "site", "backup/site",
+		filekit.WithConcurrency(4),
+		filekit.WithProgress(func(done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			lastDone, lastTotal = done, total
+		}),
+	)
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	for p, content := range files {
+		if !strings.HasPrefix(p, "site/") {
+			continue
+		}
+		got, err := dst.ReadAll(ctx, "backup/"+p)
+		if err != nil {
+			t.Errorf("read %s: %v", p, err)
+			continue
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", p, got, content)
+		}
+	}
+
+	if exists, _ := dst.FileExists(ctx, "backup/other/not-copied.txt"); exists {
+		t.Error("files outside srcPath should not be copied")
+	}
+	if exists, _ := dst.DirExists(ctx, "backup/site/empty"); !exists {
+		t.Error("empty directories should be recreated")
+	}
+
+	if calls != 7 || lastDone != 7 || lastTotal != 7 {
+		t.Errorf("progress calls=%d done=%d total=%d, want 7/7/7", calls, lastDone, lastTotal)
+	}
+}
+
+func TestCopyTree_SkipExisting(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	dst := memory.New()
+
+	seedTree(t, src, map[string]string{"data/a.txt": "new-a", "data/b.txt": "new-b"})
+	seedTree(t, dst, map[string]string{"data/a.txt": "old-a"})
+
+	if err := filekit.CopyTree(ctx, src, dst, "data", "data", filekit.WithSkipExisting()); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	if got, _ := dst.ReadAll(ctx, "data/a.txt"); string(got) != "old-a" {
+		t.Errorf("existing file was overwritten: %q", got)
+	}
+	if got, _ := dst.ReadAll(ctx, "data/b.txt"); string(got) != "new-b" {
+		t.Errorf("missing file not copied: %q", got)
+	}
+
+	// Without SkipExisting the destination is overwritten
+	if err := filekit.CopyTree(ctx, src, dst, "data", "data"); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if got, _ := dst.ReadAll(ctx, "data/a.txt"); string(got) != "new-a" {
+		t.Errorf("existing file not overwritten: %q", got)
+	}
+}
+
+func TestCopyTree_SameBackend(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{"a/x.txt": "x", "a/sub/y.txt": "y"})
+
+	if err := filekit.CopyTree(ctx, fs, fs, "a", "b"); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	for p, want := range map[string]string{"b/x.txt": "x", "b/sub/y.txt": "y", "a/x.txt": "x"} {
+		got, err := fs.ReadAll(ctx, p)
+		if err != nil || !bytes.Equal(got, []byte(want)) {
+			t.Errorf("%s = %q, %v; want %q", p, got, err, want)
+		}
+	}
+}
+
+func TestCopyTree_MissingSource(t *testing.T) {
+	err := filekit.CopyTree(context.Background(), memory.New(), memory.New(), "missing", "dst")
+	if !filekit.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
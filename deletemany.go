@@ -0,0 +1,92 @@
+package filekit
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultDeleteConcurrency is the number of parallel Delete calls drivers use for DeleteMany.
+const DefaultDeleteConcurrency = 16
+
+// DeleteMany deletes many paths. It uses the native batch implementation when
+// fs implements CanDeleteMany, and calls Delete for each path otherwise.
+// Failures are reported per path in errs; deleted lists the remaining paths
+// in input order. Missing paths are failures with ErrCodeNotFound, except on
+// S3 (see CanDeleteMany).
+//
+// Example:
+//
+//	deleted, errs := filekit.DeleteMany(ctx, fs, []string{"a.txt", "b.txt", "missing.txt"})
+//	for path, err := range errs {
+//	    log.Printf("delete %s: %v", path, err)
+//	}
+func DeleteMany(ctx context.Context, fs FileWriter, paths []string) (deleted []string, errs map[string]error) {
+	if batcher, ok := fs.(CanDeleteMany); ok {
+		return batcher.DeleteMany(ctx, paths)
+	}
+	return DeleteConcurrently(ctx, paths, 1, fs.Delete)
+}
+
+// DeleteConcurrently calls del for each path using at most concurrency calls in
+// flight, and collects the results per path. Duplicate paths are deleted once.
+// Paths not yet started when ctx is cancelled report the context error.
+//
+// Drivers use it to implement CanDeleteMany on top of a single-object delete.
+func DeleteConcurrently(
+	ctx context.Context,
+	paths []string,
+	concurrency int,
+	del func(ctx context.Context, path string) error,
+) (deleted []string, errs map[string]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs = make(map[string]error)
+	unique := make([]string, 0, len(paths))
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	record := func(p string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[p] = err
+	}
+
+	seen := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		if _, dup := seen[p]; dup {
+			continue
+		}
+		seen[p] = struct{}{}
+		unique = append(unique, p)
+
+		if err := FromContext(ctx, "delete", p); err != nil {
+			record(p, err)
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := del(ctx, p); err != nil {
+				record(p, err)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	deleted = make([]string, 0, len(unique)-len(errs))
+	for _, p := range unique {
+		if _, failed := errs[p]; !failed {
+			deleted = append(deleted, p)
+		}
+	}
+	return deleted, errs
+}
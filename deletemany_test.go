@@ -0,0 +1,132 @@
+package filekit_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestDeleteConcurrently_RespectsBound(t *testing.T) {
+	const limit = 3
+
+	var inFlight, peak atomic.Int32
+	del := func(ctx context.Context, p string) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	paths := make([]string, 20)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("file-%d.txt", i)
+	}
+
+	deleted, errs := filekit.DeleteConcurrently(context.Background(), paths, limit, del)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(deleted, paths) {
+		t.Errorf("deleted = %v, want input order %v", deleted, paths)
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak concurrency = %d, want <= %d", got, limit)
+	}
+}
+
+func TestDeleteConcurrently_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	del := func(ctx context.Context, p string) error {
+		t.Errorf("delete called for %s after cancellation", p)
+		return nil
+	}
+	deleted, errs := filekit.DeleteConcurrently(ctx, []string{"a", "b"}, 2, del)
+	if len(deleted) != 0 || len(errs) != 2 {
+		t.Fatalf("got %d deleted, %d errors; want 0, 2", len(deleted), len(errs))
+	}
+	if !filekit.IsCode(errs["a"], filekit.ErrCodeAborted) {
+		t.Errorf("expected aborted error, got %v", errs["a"])
+	}
+}
+
+func TestDeleteMany_MixedPaths(t *testing.T) {
+	backends := map[string]filekit.FileSystem{
+		"memory": memory.New(),
+	}
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New failed: %v", err)
+	}
+	backends["local"] = localFS
+
+	for name, fs := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			seedTree(t, fs, map[string]string{
+				"a.txt":     "a",
+				"dir/b.txt": "bb",
+				"keep.txt":  "keep",
+			})
+
+			if _, ok := fs.(filekit.CanDeleteMany); !ok {
+				t.Fatalf("%s driver does not implement CanDeleteMany", name)
+			}
+
+			deleted, errs := filekit.DeleteMany(ctx, fs, []string{"a.txt", "missing.txt", "dir/b.txt", "a.txt"})
+
+			if want := []string{"a.txt", "dir/b.txt"}; !reflect.DeepEqual(deleted, want) {
+				t.Errorf("deleted = %v, want %v", deleted, want)
+			}
+			if len(errs) != 1 {
+				t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+			}
+			if !filekit.IsCode(errs["missing.txt"], filekit.ErrCodeNotFound) {
+				t.Errorf("expected not found error, got %v", errs["missing.txt"])
+			}
+
+			for _, p := range []string{"a.txt", "dir/b.txt"} {
+				if exists, _ := fs.FileExists(ctx, p); exists {
+					t.Errorf("%s still exists", p)
+				}
+			}
+			if exists, _ := fs.FileExists(ctx, "keep.txt"); !exists {
+				t.Error("keep.txt was deleted")
+			}
+		})
+	}
+}
+
+func TestCachingFileSystem_DeleteMany(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	seedTree(t, base, map[string]string{"a.txt": "a", "b.txt": "b"})
+	cached := filekit.NewCachingFileSystem(base, filekit.NewMemoryCache())
+
+	// Warm the cache
+	if exists, _ := cached.FileExists(ctx, "a.txt"); !exists {
+		t.Fatal("a.txt should exist")
+	}
+
+	deleted, errs := cached.DeleteMany(ctx, []string{"a.txt", "missing.txt"})
+	if len(deleted) != 1 || len(errs) != 1 {
+		t.Fatalf("got %d deleted, %d errors; want 1, 1", len(deleted), len(errs))
+	}
+	if exists, _ := cached.FileExists(ctx, "a.txt"); exists {
+		t.Error("a.txt still reported as existing after DeleteMany")
+	}
+}
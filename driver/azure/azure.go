@@ -2,6 +2,7 @@ package azure
 
 import (
 	"context"
+	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -10,10 +11,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 	"github.com/gobeaver/filekit"
 )
 
@@ -35,6 +37,16 @@ type Adapter struct {
 	prefix        string
 	accountName   string
 	accountKey    string
+
+	// Settings consumed by the NewFrom* constructors when building the client
+	credential              azcore.TokenCredential
+	managedIdentityClientID string
+	clientOptions           *azblob.ClientOptions
+
+	// Cached user-delegation key for SAS signing with a token credential
+	delegationMu        sync.Mutex
+	delegationKey       *service.UserDelegationCredential
+	delegationKeyExpiry time.Time
 }
 
 // AdapterOption is a function that configures Azure Adapter
@@ -51,6 +63,31 @@ func WithPrefix(prefix string) AdapterOption {
 	}
 }
 
+// WithTokenCredential sets the credential used by NewFromManagedIdentity instead
+// of the built-in managed identity credential, e.g. one from azidentity.
+// With New, pass the credential the client was built with to enable
+// user-delegation SAS URLs.
+func WithTokenCredential(cred azcore.TokenCredential) AdapterOption {
+	return func(a *Adapter) {
+		a.credential = cred
+	}
+}
+
+// WithManagedIdentityClientID selects a user-assigned managed identity by client ID.
+// Without it NewFromManagedIdentity uses the system-assigned identity.
+func WithManagedIdentityClientID(clientID string) AdapterOption {
+	return func(a *Adapter) {
+		a.managedIdentityClientID = clientID
+	}
+}
+
+// WithClientOptions sets the azblob client options used by the NewFrom* constructors
+func WithClientOptions(opts *azblob.ClientOptions) AdapterOption {
+	return func(a *Adapter) {
+		a.clientOptions = opts
+	}
+}
+
 // New creates a new Azure Blob Storage filesystem adapter
 func New(client *azblob.Client, containerName string, accountName, accountKey string, options ...AdapterOption) *Adapter {
 	adapter := &Adapter{
@@ -68,15 +105,191 @@ func New(client *azblob.Client, containerName string, accountName, accountKey st
 	return adapter
 }
 
+// NewFromConnectionString creates an adapter from an Azure Storage connection string,
+// as shown under "Access keys" in the portal. Connection strings carrying an
+// AccountKey support SAS URL generation; SharedAccessSignature ones do not.
+// "UseDevelopmentStorage=true" connects to a local Azurite emulator.
+func NewFromConnectionString(connStr, containerName string, options ...AdapterOption) (*Adapter, error) {
+	parsed, err := parseConnectionString(connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := New(nil, containerName, parsed.accountName, parsed.accountKey, options...)
+
+	var client *azblob.Client
+	if parsed.accountKey != "" {
+		cred, err := azblob.NewSharedKeyCredential(parsed.accountName, parsed.accountKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure credential: %w", err)
+		}
+		client, err = azblob.NewClientWithSharedKeyCredential(parsed.serviceURL, cred, adapter.clientOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure client: %w", err)
+		}
+	} else {
+		client, err = azblob.NewClientWithNoCredential(parsed.serviceURL+"?"+parsed.sharedAccessSignature, adapter.clientOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure client: %w", err)
+		}
+	}
+
+	adapter.client = client
+	return adapter, nil
+}
+
+// NewFromManagedIdentity creates an adapter that authenticates with the managed
+// identity of the hosting Azure resource (VM, AKS, App Service, Functions, ...).
+// accountURL is the blob service URL, e.g. https://myaccount.blob.core.windows.net/.
+//
+// No account key is available, so GenerateSASURL and the presigned URL helpers
+// sign with a user-delegation key obtained through the identity.
+func NewFromManagedIdentity(accountURL, containerName string, options ...AdapterOption) (*Adapter, error) {
+	accountName, err := accountNameFromURL(accountURL)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := New(nil, containerName, accountName, "", options...)
+
+	cred := adapter.credential
+	if cred == nil {
+		cred = newManagedIdentityCredential(adapter.managedIdentityClientID)
+		adapter.credential = cred
+	}
+
+	client, err := azblob.NewClient(accountURL, cred, adapter.clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure client: %w", err)
+	}
+
+	adapter.client = client
+	return adapter, nil
+}
+
+// parsedConnectionString holds the parts of a connection string the adapter needs
+type parsedConnectionString struct {
+	serviceURL            string
+	accountName           string
+	accountKey            string
+	sharedAccessSignature string
+}
+
+// Well-known Azurite development account, see
+// https://learn.microsoft.com/azure/storage/common/storage-use-azurite
+const (
+	devStoreAccountName = "devstoreaccount1"
+	devStoreAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	devStoreBlobURL     = "http://127.0.0.1:10000/devstoreaccount1"
+)
+
+// parseConnectionString parses an Azure Storage connection string
+func parseConnectionString(connStr string) (*parsedConnectionString, error) {
+	values := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimSpace(connStr), ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid azure connection string: malformed segment %q", key)
+		}
+		values[key] = value
+	}
+
+	if strings.EqualFold(values["UseDevelopmentStorage"], "true") {
+		serviceURL := devStoreBlobURL
+		if proxy := values["DevelopmentStorageProxyUri"]; proxy != "" {
+			serviceURL = strings.TrimSuffix(proxy, "/") + "/" + devStoreAccountName
+		}
+		return &parsedConnectionString{
+			serviceURL:  serviceURL + "/",
+			accountName: devStoreAccountName,
+			accountKey:  devStoreAccountKey,
+		}, nil
+	}
+
+	parsed := &parsedConnectionString{
+		accountName:           values["AccountName"],
+		accountKey:            values["AccountKey"],
+		sharedAccessSignature: strings.TrimPrefix(values["SharedAccessSignature"], "?"),
+	}
+
+	switch {
+	case values["BlobEndpoint"] != "":
+		parsed.serviceURL = values["BlobEndpoint"]
+	case parsed.accountName != "":
+		protocol := values["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := values["EndpointSuffix"]
+		if suffix == "" {
+			suffix = "core.windows.net"
+		}
+		parsed.serviceURL = fmt.Sprintf("%s://%s.blob.%s", protocol, parsed.accountName, suffix)
+	default:
+		return nil, fmt.Errorf("invalid azure connection string: AccountName or BlobEndpoint is required")
+	}
+	if !strings.HasSuffix(parsed.serviceURL, "/") {
+		parsed.serviceURL += "/"
+	}
+
+	if parsed.accountName == "" {
+		// SAS connection strings may only carry BlobEndpoint
+		parsed.accountName, _ = accountNameFromURL(parsed.serviceURL)
+	}
+
+	switch {
+	case parsed.accountKey != "":
+		if parsed.accountName == "" {
+			return nil, fmt.Errorf("invalid azure connection string: AccountKey requires AccountName")
+		}
+	case parsed.sharedAccessSignature == "":
+		return nil, fmt.Errorf("invalid azure connection string: AccountKey or SharedAccessSignature is required")
+	}
+
+	return parsed, nil
+}
+
+// accountNameFromURL extracts the storage account name from a blob service URL.
+// It handles both https://<account>.blob.core.windows.net and path-style
+// emulator URLs such as http://127.0.0.1:10000/<account>.
+func accountNameFromURL(serviceURL string) (string, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid azure account URL %q", serviceURL)
+	}
+
+	host := u.Hostname()
+	if idx := strings.Index(host, ".blob."); idx > 0 {
+		return host[:idx], nil
+	}
+
+	if account, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/"); account != "" {
+		return account, nil
+	}
+	return "", fmt.Errorf("cannot determine azure account name from URL %q", serviceURL)
+}
+
 // Write implements filekit.FileWriter
 func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
 	opts := processOptions(options...)
+	tier, err := accessTier(filePath, opts.StorageClass)
+	if err != nil {
+		return nil, err
+	}
 
 	// Combine prefix and path
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("write", filePath, err)
+	}
 
-	// Check if blob exists and overwrite is not allowed
-	if !opts.Overwrite {
+	// Check if blob exists and overwrite is not allowed. Conditional writes
+	// are checked by Azure instead.
+	if !opts.Overwrite && !opts.HasPreconditions() {
 		blobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(blobName)
 		_, err := blobClient.GetProperties(ctx, nil)
 		if err == nil {
@@ -87,27 +300,28 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		}
 	}
 
-	// Determine content type
-	contentType := opts.ContentType
-	if contentType == "" {
-		contentType = detectContentType(filePath)
-	}
-
 	// Read content into buffer (Azure SDK requires content length for some operations)
-	data, err := io.ReadAll(content)
+	data, err := io.ReadAll(opts.TrackProgress(content))
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
 
+	// Determine content type
+	contentType := opts.ContentType
+	if !opts.ForceContentType {
+		contentType = filekit.ResolveContentType(filePath, opts.ContentType, data)
+	}
+
 	// Calculate checksum
 	hash := sha256.Sum256(data)
 	checksum := hex.EncodeToString(hash[:])
 
 	// Upload options
 	uploadOpts := &azblob.UploadBufferOptions{
-		HTTPHeaders: &blob.HTTPHeaders{
-			BlobContentType: &contentType,
-		},
+		HTTPHeaders: &blob.HTTPHeaders{},
+	}
+	if contentType != "" {
+		uploadOpts.HTTPHeaders.BlobContentType = &contentType
 	}
 
 	// Set cache control if provided
@@ -115,6 +329,16 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		uploadOpts.HTTPHeaders.BlobCacheControl = &opts.CacheControl
 	}
 
+	// Set content disposition if provided
+	if opts.ContentDisposition != "" {
+		uploadOpts.HTTPHeaders.BlobContentDisposition = &opts.ContentDisposition
+	}
+
+	// Set the access tier if provided; otherwise the account default applies
+	if tier != "" {
+		uploadOpts.AccessTier = &tier
+	}
+
 	// Set metadata if provided
 	if len(opts.Metadata) > 0 {
 		metadata := make(map[string]*string, len(opts.Metadata))
@@ -125,9 +349,27 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		uploadOpts.Metadata = metadata
 	}
 
+	// Make the write conditional if requested
+	if opts.HasPreconditions() {
+		conditions := &blob.ModifiedAccessConditions{}
+		if opts.IfMatch != "" {
+			etag := azcore.ETag(opts.IfMatch)
+			conditions.IfMatch = &etag
+		}
+		if opts.IfNoneMatch != "" {
+			etag := azcore.ETag(opts.IfNoneMatch)
+			conditions.IfNoneMatch = &etag
+		}
+		uploadOpts.AccessConditions = &blob.AccessConditions{ModifiedAccessConditions: conditions}
+	}
+
 	// Upload the blob
 	resp, err := a.client.UploadBuffer(ctx, a.containerName, blobName, data, uploadOpts)
 	if err != nil {
+		// Azure reports If-None-Match: * on an existing blob as a conflict
+		if opts.IfNoneMatch != "" && bloberror.HasCode(err, bloberror.BlobAlreadyExists) {
+			return nil, filekit.WrapPath(filekit.ErrPreconditionFailed, "write", filePath, filekit.ErrCodePreconditionFailed, "file already exists")
+		}
 		return nil, mapAzureError("write", filePath, err)
 	}
 
@@ -148,13 +390,17 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		Checksum:          checksum,
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
 		ETag:              etag,
+		ContentType:       contentType,
 		ServerTimestamp:   serverTime,
 	}, nil
 }
 
 // Read implements filekit.FileReader
 func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, error) {
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("read", filePath, err)
+	}
 
 	// Download the blob
 	resp, err := a.client.DownloadStream(ctx, a.containerName, blobName, nil)
@@ -177,9 +423,12 @@ func (a *Adapter) ReadAll(ctx context.Context, path string) ([]byte, error) {
 
 // Delete implements filekit.FileSystem
 func (a *Adapter) Delete(ctx context.Context, filePath string) error {
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return filekit.WrapPathErr("delete", filePath, err)
+	}
 
-	_, err := a.client.DeleteBlob(ctx, a.containerName, blobName, nil)
+	_, err = a.client.DeleteBlob(ctx, a.containerName, blobName, nil)
 	if err != nil {
 		return mapAzureError("delete", filePath, err)
 	}
@@ -189,7 +438,10 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 
 // FileExists checks if a file exists (not a directory)
 func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error) {
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return false, filekit.WrapPathErr("fileexists", filePath, err)
+	}
 
 	// Ensure it's not a directory marker
 	if strings.HasSuffix(blobName, "/") {
@@ -216,7 +468,10 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 // DirExists checks if a directory exists
 func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 	// Prepare directory path
-	dirPrefix := path.Join(a.prefix, dirPath)
+	dirPrefix, err := a.key(dirPath)
+	if err != nil {
+		return false, filekit.WrapPathErr("direxists", dirPath, err)
+	}
 	if !strings.HasSuffix(dirPrefix, "/") {
 		dirPrefix += "/"
 	}
@@ -225,7 +480,7 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 
 	// Check if directory marker blob exists
 	blobClient := containerClient.NewBlobClient(dirPrefix)
-	_, err := blobClient.GetProperties(ctx, nil)
+	_, err = blobClient.GetProperties(ctx, nil)
 	if err == nil {
 		return true, nil
 	}
@@ -254,7 +509,10 @@ func ptr[T any](v T) *T {
 
 // Stat implements filekit.FileReader
 func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo, error) {
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("stat", filePath, err)
+	}
 
 	blobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(blobName)
 	props, err := blobClient.GetProperties(ctx, nil)
@@ -306,6 +564,12 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 		storageClass = string(*props.AccessTier)
 	}
 
+	// Extract ContentDisposition
+	var contentDisposition string
+	if props.ContentDisposition != nil {
+		contentDisposition = *props.ContentDisposition
+	}
+
 	// Extract Checksum (ContentMD5 in Azure)
 	var checksum string
 	var checksumAlgorithm filekit.ChecksumAlgorithm
@@ -321,31 +585,117 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	}
 
 	return &filekit.FileInfo{
-		Name:              filepath.Base(filePath),
-		Path:              filePath,
-		Size:              size,
-		ModTime:           modTime,
-		IsDir:             isDir,
-		ContentType:       contentType,
-		Metadata:          metadata,
-		ETag:              etag,
-		Version:           version,
-		StorageClass:      storageClass,
-		Checksum:          checksum,
-		ChecksumAlgorithm: checksumAlgorithm,
-		CreatedAt:         createdAt,
+		Name:               filepath.Base(filePath),
+		Path:               filePath,
+		Size:               size,
+		ModTime:            modTime,
+		IsDir:              isDir,
+		ContentType:        contentType,
+		ContentDisposition: contentDisposition,
+		Metadata:           metadata,
+		ETag:               etag,
+		Version:            version,
+		StorageClass:       storageClass,
+		Checksum:           checksum,
+		ChecksumAlgorithm:  checksumAlgorithm,
+		CreatedAt:          createdAt,
 	}, nil
 }
 
+// StatMany implements filekit.CanStatMany by issuing GetProperties requests
+// concurrently, bounded by filekit.DefaultStatConcurrency.
+func (a *Adapter) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	return filekit.StatConcurrently(ctx, paths, filekit.DefaultStatConcurrency, a.Stat)
+}
+
+// maxBatchDeletes is the maximum number of sub-requests in a blob batch
+const maxBatchDeletes = 256
+
+// DeleteMany implements filekit.CanDeleteMany using the Blob Batch API,
+// submitting up to 256 deletes per request.
+func (a *Adapter) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	errs := make(map[string]error)
+	deleted := make([]string, 0, len(paths))
+	containerClient := a.client.ServiceClient().NewContainerClient(a.containerName)
+
+	unique := make([]string, 0, len(paths))
+	keys := make(map[string]string, len(paths))
+	for _, p := range paths {
+		if _, dup := keys[p]; dup {
+			continue
+		}
+		if _, bad := errs[p]; bad {
+			continue
+		}
+		key, err := a.key(p)
+		if err != nil {
+			errs[p] = filekit.WrapPathErr("delete", p, err)
+			continue
+		}
+		keys[p] = key
+		unique = append(unique, p)
+	}
+
+	failBatch := func(batch []string, err error) {
+		for _, p := range batch {
+			errs[p] = mapAzureError("delete", p, err)
+		}
+	}
+
+	for start := 0; start < len(unique); start += maxBatchDeletes {
+		batch := unique[start:min(start+maxBatchDeletes, len(unique))]
+
+		if err := filekit.FromContext(ctx, "delete", batch[0]); err != nil {
+			for _, p := range batch {
+				errs[p] = err
+			}
+			continue
+		}
+
+		builder, err := containerClient.NewBatchBuilder()
+		if err != nil {
+			failBatch(batch, err)
+			continue
+		}
+		for _, p := range batch {
+			if err = builder.Delete(keys[p], nil); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			failBatch(batch, err)
+			continue
+		}
+
+		resp, err := containerClient.SubmitBatch(ctx, builder, nil)
+		if err != nil {
+			failBatch(batch, err)
+			continue
+		}
+
+		// Sub-responses carry the index of their sub-request as Content-ID
+		for _, item := range resp.Responses {
+			if item.Error == nil || item.ContentID == nil || *item.ContentID < 0 || *item.ContentID >= len(batch) {
+				continue
+			}
+			p := batch[*item.ContentID]
+			errs[p] = mapAzureError("delete", p, item.Error)
+		}
+	}
+
+	for _, p := range unique {
+		if _, failed := errs[p]; !failed {
+			deleted = append(deleted, p)
+		}
+	}
+	return deleted, errs
+}
+
 // ListContents lists files and directories at the given path with optional recursion
 func (a *Adapter) ListContents(ctx context.Context, dirPath string, recursive bool) ([]filekit.FileInfo, error) {
-	// Prepare prefix for listing
-	listPrefix := dirPath
-	if a.prefix != "" {
-		listPrefix = path.Join(a.prefix, dirPath)
-	}
-	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
-		listPrefix += "/"
+	listPrefix, err := a.listPrefix(dirPath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("listcontents", dirPath, err)
 	}
 
 	containerClient := a.client.ServiceClient().NewContainerClient(a.containerName)
@@ -363,88 +713,7 @@ func (a *Adapter) ListContents(ctx context.Context, dirPath string, recursive bo
 			if err != nil {
 				return nil, mapAzureError("listcontents", dirPath, err)
 			}
-
-			for _, blobItem := range resp.Segment.BlobItems {
-				if blobItem.Name == nil {
-					continue
-				}
-
-				// Skip the directory itself
-				if *blobItem.Name == listPrefix {
-					continue
-				}
-
-				relativePath := strings.TrimPrefix(*blobItem.Name, listPrefix)
-				if relativePath == "" {
-					continue
-				}
-
-				var size int64
-				var modTime time.Time
-				var contentType string
-
-				if blobItem.Properties != nil {
-					if blobItem.Properties.ContentLength != nil {
-						size = *blobItem.Properties.ContentLength
-					}
-					if blobItem.Properties.LastModified != nil {
-						modTime = *blobItem.Properties.LastModified
-					}
-					if blobItem.Properties.ContentType != nil {
-						contentType = *blobItem.Properties.ContentType
-					}
-				}
-
-				isDir := strings.HasSuffix(*blobItem.Name, "/") || (contentType == "application/x-directory")
-
-				// Extract additional fields from Properties
-				var etag, version, storageClass, checksum string
-				var checksumAlgorithm filekit.ChecksumAlgorithm
-				var createdAt *time.Time
-
-				if blobItem.Properties != nil {
-					if blobItem.Properties.ETag != nil {
-						etag = string(*blobItem.Properties.ETag)
-					}
-					if blobItem.Properties.AccessTier != nil {
-						storageClass = string(*blobItem.Properties.AccessTier)
-					}
-					if blobItem.Properties.ContentMD5 != nil && len(blobItem.Properties.ContentMD5) > 0 {
-						checksum = hex.EncodeToString(blobItem.Properties.ContentMD5)
-						checksumAlgorithm = filekit.ChecksumMD5
-					}
-					if blobItem.Properties.CreationTime != nil {
-						createdAt = blobItem.Properties.CreationTime
-					}
-				}
-				if blobItem.VersionID != nil {
-					version = *blobItem.VersionID
-				}
-
-				// Convert metadata
-				metadata := make(map[string]string, len(blobItem.Metadata))
-				for k, v := range blobItem.Metadata {
-					if v != nil {
-						metadata[k] = *v
-					}
-				}
-
-				files = append(files, filekit.FileInfo{
-					Name:              filepath.Base(relativePath),
-					Path:              path.Join(dirPath, relativePath),
-					Size:              size,
-					ModTime:           modTime,
-					IsDir:             isDir,
-					ContentType:       contentType,
-					Metadata:          metadata,
-					ETag:              etag,
-					Version:           version,
-					StorageClass:      storageClass,
-					Checksum:          checksum,
-					ChecksumAlgorithm: checksumAlgorithm,
-					CreatedAt:         createdAt,
-				})
-			}
+			files = append(files, flatEntries(dirPath, listPrefix, resp.Segment.BlobItems)...)
 		}
 	} else {
 		// Non-recursive listing - use hierarchy pager
@@ -457,113 +726,225 @@ func (a *Adapter) ListContents(ctx context.Context, dirPath string, recursive bo
 			if err != nil {
 				return nil, mapAzureError("listcontents", dirPath, err)
 			}
+			files = append(files, hierarchyEntries(dirPath, listPrefix, resp.Segment.BlobPrefixes, resp.Segment.BlobItems)...)
+		}
+	}
 
-			// Add directories (blob prefixes)
-			for _, blobPrefix := range resp.Segment.BlobPrefixes {
-				if blobPrefix.Name == nil {
-					continue
-				}
-				dirName := strings.TrimPrefix(*blobPrefix.Name, listPrefix)
-				dirName = strings.TrimSuffix(dirName, "/")
-				if dirName == "" {
-					continue
-				}
+	filekit.SortFiles(files, filekit.SortByName, false)
+	return files, nil
+}
 
-				files = append(files, filekit.FileInfo{
-					Name:  dirName,
-					Path:  path.Join(dirPath, dirName),
-					IsDir: true,
-				})
-			}
+// ListPage implements filekit.CanListPage using Azure list markers.
+func (a *Adapter) ListPage(ctx context.Context, dirPath string, opts filekit.ListPageOptions) (filekit.ListPage, error) {
+	listPrefix, err := a.listPrefix(dirPath)
+	if err != nil {
+		return filekit.ListPage{}, filekit.WrapPathErr("listpage", dirPath, err)
+	}
+	maxResults := int32(opts.PageSize())
+	var marker *string
+	if opts.ContinuationToken != "" {
+		marker = &opts.ContinuationToken
+	}
 
-			// Add files
-			for _, blobItem := range resp.Segment.BlobItems {
-				if blobItem.Name == nil {
-					continue
-				}
+	containerClient := a.client.ServiceClient().NewContainerClient(a.containerName)
 
-				// Skip the directory itself
-				if *blobItem.Name == listPrefix {
-					continue
-				}
+	var page filekit.ListPage
+	var nextMarker *string
 
-				fileName := strings.TrimPrefix(*blobItem.Name, listPrefix)
-				if fileName == "" || strings.Contains(fileName, "/") {
-					continue
-				}
+	if opts.Recursive {
+		pager := containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+			Prefix:     &listPrefix,
+			Marker:     marker,
+			MaxResults: &maxResults,
+		})
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return filekit.ListPage{}, mapAzureError("listpage", dirPath, err)
+		}
+		page.Entries = flatEntries(dirPath, listPrefix, resp.Segment.BlobItems)
+		nextMarker = resp.NextMarker
+	} else {
+		pager := containerClient.NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{
+			Prefix:     &listPrefix,
+			Marker:     marker,
+			MaxResults: &maxResults,
+		})
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return filekit.ListPage{}, mapAzureError("listpage", dirPath, err)
+		}
+		page.Entries = hierarchyEntries(dirPath, listPrefix, resp.Segment.BlobPrefixes, resp.Segment.BlobItems)
+		nextMarker = resp.NextMarker
+	}
 
-				var size int64
-				var modTime time.Time
-				var contentType string
+	if nextMarker != nil {
+		page.NextToken = *nextMarker
+	}
+	return page, nil
+}
 
-				// Extract additional fields from Properties
-				var etag, version, storageClass, checksum string
-				var checksumAlgorithm filekit.ChecksumAlgorithm
-				var createdAt *time.Time
+// key returns the blob name for p, normalized with filekit.CleanPath. Paths
+// that escape the prefix return filekit.ErrNotAllowed.
+func (a *Adapter) key(p string) (string, error) {
+	clean, err := filekit.CleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(a.prefix, clean), nil
+}
 
-				if blobItem.Properties != nil {
-					if blobItem.Properties.ContentLength != nil {
-						size = *blobItem.Properties.ContentLength
-					}
-					if blobItem.Properties.LastModified != nil {
-						modTime = *blobItem.Properties.LastModified
-					}
-					if blobItem.Properties.ContentType != nil {
-						contentType = *blobItem.Properties.ContentType
-					}
-					if blobItem.Properties.ETag != nil {
-						etag = string(*blobItem.Properties.ETag)
-					}
-					if blobItem.Properties.AccessTier != nil {
-						storageClass = string(*blobItem.Properties.AccessTier)
-					}
-					if blobItem.Properties.ContentMD5 != nil && len(blobItem.Properties.ContentMD5) > 0 {
-						checksum = hex.EncodeToString(blobItem.Properties.ContentMD5)
-						checksumAlgorithm = filekit.ChecksumMD5
-					}
-					if blobItem.Properties.CreationTime != nil {
-						createdAt = blobItem.Properties.CreationTime
-					}
-				}
-				if blobItem.VersionID != nil {
-					version = *blobItem.VersionID
-				}
+// listPrefix returns the blob prefix under which the entries of dirPath are stored
+func (a *Adapter) listPrefix(dirPath string) (string, error) {
+	listPrefix, err := a.key(dirPath)
+	if err != nil {
+		return "", err
+	}
+	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
+		listPrefix += "/"
+	}
+	return listPrefix, nil
+}
 
-				// Convert metadata
-				metadata := make(map[string]string, len(blobItem.Metadata))
-				for k, v := range blobItem.Metadata {
-					if v != nil {
-						metadata[k] = *v
-					}
-				}
+// flatEntries converts the blobs of a flat listing segment to FileInfo entries
+func flatEntries(dirPath, listPrefix string, items []*container.BlobItem) []filekit.FileInfo {
+	var files []filekit.FileInfo
+	for _, blobItem := range items {
+		if blobItem.Name == nil {
+			continue
+		}
 
-				files = append(files, filekit.FileInfo{
-					Name:              fileName,
-					Path:              path.Join(dirPath, fileName),
-					Size:              size,
-					ModTime:           modTime,
-					IsDir:             false,
-					ContentType:       contentType,
-					Metadata:          metadata,
-					ETag:              etag,
-					Version:           version,
-					StorageClass:      storageClass,
-					Checksum:          checksum,
-					ChecksumAlgorithm: checksumAlgorithm,
-					CreatedAt:         createdAt,
-				})
-			}
+		// Skip the directory itself
+		if *blobItem.Name == listPrefix {
+			continue
 		}
+
+		relativePath := strings.TrimPrefix(*blobItem.Name, listPrefix)
+		if relativePath == "" {
+			continue
+		}
+
+		info := blobItemInfo(blobItem, filepath.Base(relativePath), path.Join(dirPath, relativePath))
+		info.IsDir = strings.HasSuffix(*blobItem.Name, "/") || (info.ContentType == "application/x-directory")
+		files = append(files, info)
 	}
+	return files
+}
 
-	return files, nil
+// hierarchyEntries converts the prefixes and blobs of a hierarchy listing
+// segment to FileInfo entries, directories first
+func hierarchyEntries(dirPath, listPrefix string, prefixes []*container.BlobPrefix, items []*container.BlobItem) []filekit.FileInfo {
+	var files []filekit.FileInfo
+
+	// Add directories (blob prefixes)
+	for _, blobPrefix := range prefixes {
+		if blobPrefix.Name == nil {
+			continue
+		}
+		dirName := strings.TrimPrefix(*blobPrefix.Name, listPrefix)
+		dirName = strings.TrimSuffix(dirName, "/")
+		if dirName == "" {
+			continue
+		}
+
+		files = append(files, filekit.FileInfo{
+			Name:  dirName,
+			Path:  path.Join(dirPath, dirName),
+			IsDir: true,
+		})
+	}
+
+	// Add files
+	for _, blobItem := range items {
+		if blobItem.Name == nil {
+			continue
+		}
+
+		// Skip the directory itself
+		if *blobItem.Name == listPrefix {
+			continue
+		}
+
+		fileName := strings.TrimPrefix(*blobItem.Name, listPrefix)
+		if fileName == "" || strings.Contains(fileName, "/") {
+			continue
+		}
+
+		files = append(files, blobItemInfo(blobItem, fileName, path.Join(dirPath, fileName)))
+	}
+	return files
+}
+
+// blobItemInfo converts a listed blob to a FileInfo
+func blobItemInfo(blobItem *container.BlobItem, name, filePath string) filekit.FileInfo {
+	var size int64
+	var modTime time.Time
+	var contentType string
+
+	// Extract additional fields from Properties
+	var etag, version, storageClass, checksum string
+	var checksumAlgorithm filekit.ChecksumAlgorithm
+	var createdAt *time.Time
+
+	if blobItem.Properties != nil {
+		if blobItem.Properties.ContentLength != nil {
+			size = *blobItem.Properties.ContentLength
+		}
+		if blobItem.Properties.LastModified != nil {
+			modTime = *blobItem.Properties.LastModified
+		}
+		if blobItem.Properties.ContentType != nil {
+			contentType = *blobItem.Properties.ContentType
+		}
+		if blobItem.Properties.ETag != nil {
+			etag = string(*blobItem.Properties.ETag)
+		}
+		if blobItem.Properties.AccessTier != nil {
+			storageClass = string(*blobItem.Properties.AccessTier)
+		}
+		if len(blobItem.Properties.ContentMD5) > 0 {
+			checksum = hex.EncodeToString(blobItem.Properties.ContentMD5)
+			checksumAlgorithm = filekit.ChecksumMD5
+		}
+		if blobItem.Properties.CreationTime != nil {
+			createdAt = blobItem.Properties.CreationTime
+		}
+	}
+	if blobItem.VersionID != nil {
+		version = *blobItem.VersionID
+	}
+
+	// Convert metadata
+	metadata := make(map[string]string, len(blobItem.Metadata))
+	for k, v := range blobItem.Metadata {
+		if v != nil {
+			metadata[k] = *v
+		}
+	}
+
+	return filekit.FileInfo{
+		Name:              name,
+		Path:              filePath,
+		Size:              size,
+		ModTime:           modTime,
+		ContentType:       contentType,
+		Metadata:          metadata,
+		ETag:              etag,
+		Version:           version,
+		StorageClass:      storageClass,
+		Checksum:          checksum,
+		ChecksumAlgorithm: checksumAlgorithm,
+		CreatedAt:         createdAt,
+	}
 }
 
 // CreateDir implements filekit.FileSystem
 func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	// Azure Blob Storage doesn't have real directories
 	// We create an empty blob with a trailing slash to simulate a directory
-	blobName := path.Join(a.prefix, dirPath)
+	blobName, err := a.key(dirPath)
+	if err != nil {
+		return filekit.WrapPathErr("createdir", dirPath, err)
+	}
 	if !strings.HasSuffix(blobName, "/") {
 		blobName += "/"
 	}
@@ -575,7 +956,7 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 		},
 	}
 
-	_, err := a.client.UploadBuffer(ctx, a.containerName, blobName, []byte{}, uploadOpts)
+	_, err = a.client.UploadBuffer(ctx, a.containerName, blobName, []byte{}, uploadOpts)
 	if err != nil {
 		return mapAzureError("createdir", dirPath, err)
 	}
@@ -583,10 +964,16 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	return nil
 }
 
-// DeleteDir implements filekit.FileSystem
+// DeleteDir implements filekit.FileSystem. Blobs are deleted one listing page
+// at a time through the Blob Batch API, up to 256 per request. Blobs that
+// cannot be deleted are reported in the returned error (a *filekit.MultiError
+// when there are several) once the rest are gone.
 func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 	// Prepare directory path
-	dirPrefix := path.Join(a.prefix, dirPath)
+	dirPrefix, err := a.key(dirPath)
+	if err != nil {
+		return filekit.WrapPathErr("deletedir", dirPath, err)
+	}
 	if !strings.HasSuffix(dirPrefix, "/") {
 		dirPrefix += "/"
 	}
@@ -598,6 +985,9 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 		Prefix: &dirPrefix,
 	})
 
+	// Each page is deleted with the Blob Batch API (see DeleteMany); a blob
+	// that fails is reported without stopping the rest
+	errs := filekit.NewMultiError("deletedir")
 	var found bool
 	for pager.More() {
 		resp, err := pager.NextPage(ctx)
@@ -605,17 +995,26 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 			return mapAzureError("deletedir", dirPath, err)
 		}
 
+		paths := make([]string, 0, len(resp.Segment.BlobItems))
 		for _, blobItem := range resp.Segment.BlobItems {
 			if blobItem.Name == nil {
 				continue
 			}
-			found = true
-
-			// Delete the blob
-			_, err := a.client.DeleteBlob(ctx, a.containerName, *blobItem.Name, nil)
-			if err != nil {
-				return mapAzureError("deletedir", dirPath, err)
+			paths = append(paths, strings.TrimPrefix(strings.TrimPrefix(*blobItem.Name, a.prefix), "/"))
+		}
+		if len(paths) == 0 {
+			continue
+		}
+		found = true
+
+		_, failed := a.DeleteMany(ctx, paths)
+		for _, p := range paths {
+			err := failed[p]
+			// Already gone, e.g. deleted concurrently
+			if filekit.IsCode(err, filekit.ErrCodeNotFound) {
+				err = nil
 			}
+			errs.Add(err)
 		}
 	}
 
@@ -623,7 +1022,7 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 		return filekit.WrapPathErr("deletedir", dirPath, filekit.ErrNotExist)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // WriteFile writes a local file to the storage
@@ -634,23 +1033,35 @@ func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath stri
 	}
 	defer file.Close()
 
-	// Detect content type if not provided
+	// Detect content type from the local file if not provided
 	opts := processOptions(options...)
-	if opts.ContentType == "" {
-		contentType := detectContentType(localPath)
+	if opts.ContentType == "" && !opts.ForceContentType {
+		contentType, _, err := filekit.PeekContentType(localPath, "", file)
+		if err != nil {
+			return nil, filekit.WrapPathErr("writefile", localPath, err)
+		}
 		options = append(options, filekit.WithContentType(contentType))
 	}
 
 	return a.Write(ctx, destPath, file, options...)
 }
 
-// GenerateSASURL generates a SAS URL for accessing a blob
+// GenerateSASURL generates a SAS URL for accessing a blob.
+// Adapters with a token credential sign with a user-delegation key;
+// otherwise the account key is used.
 func (a *Adapter) GenerateSASURL(ctx context.Context, filePath string, expiry time.Duration, permissions sas.BlobPermissions) (string, error) {
+	if a.credential != nil {
+		return a.GenerateUserDelegationSAS(ctx, filePath, expiry, permissions)
+	}
 	if a.accountKey == "" {
-		return "", fmt.Errorf("account key required for SAS URL generation")
+		return "", filekit.NewPathError("generate-sas", filePath, filekit.ErrCodeNotSupported,
+			"account key or token credential required for SAS URL generation")
 	}
 
-	blobName := path.Join(a.prefix, filePath)
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("generate-sas", filePath, err)
+	}
 
 	// Create shared key credential
 	cred, err := azblob.NewSharedKeyCredential(a.accountName, a.accountKey)
@@ -671,11 +1082,91 @@ func (a *Adapter) GenerateSASURL(ctx context.Context, filePath string, expiry ti
 		return "", mapAzureError("generate-sas", filePath, err)
 	}
 
-	// Construct full URL
-	blobURL := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s?%s",
-		a.accountName, a.containerName, blobName, sasQueryParams.Encode())
+	// Construct full URL from the client endpoint so custom and emulator endpoints work
+	blobURL := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(blobName).URL()
+
+	return blobURL + "?" + sasQueryParams.Encode(), nil
+}
+
+// userDelegationKeyLifetime is how long a requested user-delegation key stays valid.
+// Keys are reused for every SAS that expires before them.
+const userDelegationKeyLifetime = 24 * time.Hour
+
+// maxUserDelegationKeyLifetime is the service limit for user-delegation keys
+const maxUserDelegationKeyLifetime = 7 * 24 * time.Hour
+
+// GenerateUserDelegationSAS generates a SAS URL signed with a user-delegation key,
+// which the service issues to the adapter's token credential (e.g. a managed
+// identity). No account key is needed. The identity must hold a role that grants
+// Microsoft.Storage/storageAccounts/blobServices/generateUserDelegationKey,
+// such as Storage Blob Delegator or Storage Blob Data Contributor.
+func (a *Adapter) GenerateUserDelegationSAS(ctx context.Context, filePath string, expiry time.Duration, permissions sas.BlobPermissions) (string, error) {
+	if a.credential == nil {
+		return "", filekit.NewPathError("generate-sas", filePath, filekit.ErrCodeNotSupported,
+			"user-delegation SAS requires a token credential")
+	}
+	if expiry > maxUserDelegationKeyLifetime {
+		return "", filekit.NewPathError("generate-sas", filePath, filekit.ErrCodeInvalidInput,
+			"user-delegation SAS expiry cannot exceed 7 days")
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(expiry)
+
+	udc, err := a.userDelegationCredential(ctx, expiresAt)
+	if err != nil {
+		return "", mapAzureError("generate-sas", filePath, err)
+	}
+
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("generate-sas", filePath, err)
+	}
+	sasQueryParams, err := sas.BlobSignatureValues{
+		Protocol:      sas.ProtocolHTTPS,
+		StartTime:     now,
+		ExpiryTime:    expiresAt,
+		Permissions:   permissions.String(),
+		ContainerName: a.containerName,
+		BlobName:      blobName,
+	}.SignWithUserDelegation(udc)
+	if err != nil {
+		return "", mapAzureError("generate-sas", filePath, err)
+	}
+
+	blobURL := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(blobName).URL()
 
-	return blobURL, nil
+	return blobURL + "?" + sasQueryParams.Encode(), nil
+}
+
+// userDelegationCredential returns a cached user-delegation key valid until at
+// least expiresAt, requesting a new one from the service when needed.
+func (a *Adapter) userDelegationCredential(ctx context.Context, expiresAt time.Time) (*service.UserDelegationCredential, error) {
+	a.delegationMu.Lock()
+	defer a.delegationMu.Unlock()
+
+	if a.delegationKey != nil && !a.delegationKeyExpiry.Before(expiresAt) {
+		return a.delegationKey, nil
+	}
+
+	// Backdate the start to tolerate clock skew between client and service
+	start := time.Now().UTC().Add(-5 * time.Minute)
+	keyExpiry := time.Now().UTC().Add(userDelegationKeyLifetime)
+	if keyExpiry.Before(expiresAt) {
+		keyExpiry = expiresAt
+	}
+
+	udc, err := a.client.ServiceClient().GetUserDelegationCredential(ctx, service.KeyInfo{
+		Start:  ptr(start.Format(sas.TimeFormat)),
+		Expiry: ptr(keyExpiry.Format(sas.TimeFormat)),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	a.delegationKey = udc
+	a.delegationKeyExpiry = keyExpiry
+	return udc, nil
 }
 
 // GenerateDownloadURL generates a SAS URL for downloading a blob
@@ -707,57 +1198,22 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 	return opts
 }
 
-// detectContentType determines the content type from file extension
-func detectContentType(filePath string) string {
-	ext := filepath.Ext(filePath)
-
-	// Common extension mappings
-	switch strings.ToLower(ext) {
-	case ".html", ".htm":
-		return "text/html"
-	case ".css":
-		return "text/css"
-	case ".js":
-		return "application/javascript"
-	case ".json":
-		return "application/json"
-	case ".xml":
-		return "application/xml"
-	case ".pdf":
-		return "application/pdf"
-	case ".zip":
-		return "application/zip"
-	case ".gz", ".gzip":
-		return "application/gzip"
-	case ".tar":
-		return "application/x-tar"
-	case ".png":
-		return "image/png"
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".gif":
-		return "image/gif"
-	case ".webp":
-		return "image/webp"
-	case ".svg":
-		return "image/svg+xml"
-	case ".ico":
-		return "image/x-icon"
-	case ".mp3":
-		return "audio/mpeg"
-	case ".mp4":
-		return "video/mp4"
-	case ".webm":
-		return "video/webm"
-	case ".txt":
-		return "text/plain"
-	case ".csv":
-		return "text/csv"
-	case ".md":
-		return "text/markdown"
-	default:
-		return "application/octet-stream"
+// accessTiers are the access tiers of block blobs
+var accessTiers = []blob.AccessTier{blob.AccessTierHot, blob.AccessTierCool, blob.AccessTierCold, blob.AccessTierArchive}
+
+// accessTier resolves a WithStorageClass value, matched case-insensitively,
+// to a block blob access tier.
+func accessTier(filePath, class string) (blob.AccessTier, error) {
+	if class == "" {
+		return "", nil
 	}
+	for _, known := range accessTiers {
+		if strings.EqualFold(string(known), class) {
+			return known, nil
+		}
+	}
+	return "", filekit.NewPathError("write", filePath, filekit.ErrCodeInvalidInput,
+		fmt.Sprintf("unknown Azure access tier %q", class))
 }
 
 // mapAzureError maps Azure errors to filekit errors
@@ -770,6 +1226,22 @@ func mapAzureError(op, path string, err error) error {
 		return filekit.WrapPathErr(op, path, filekit.ErrNotExist)
 	}
 
+	// Failed conditional writes
+	if bloberror.HasCode(err, bloberror.ConditionNotMet) {
+		return filekit.WrapPath(filekit.ErrPreconditionFailed, op, path, filekit.ErrCodePreconditionFailed, "condition not met")
+	}
+
+	// Archive-tier blobs must be rehydrated before they can be read
+	if bloberror.HasCode(err, bloberror.BlobArchived) {
+		return filekit.WrapPath(filekit.ErrNotReadable, op, path, filekit.ErrCodeNotReadable,
+			"blob is in the Archive tier and must be rehydrated before it can be read")
+	}
+
+	// Storage account quotas and disabled accounts (e.g. a subscription over its spending limit)
+	if bloberror.HasCode(err, bloberror.AccountIsDisabled) {
+		return filekit.WrapPath(filekit.ErrQuotaExceeded, op, path, filekit.ErrCodeQuotaExceeded, "account is disabled")
+	}
+
 	var respErr *azcore.ResponseError
 	if errors.As(err, &respErr) {
 		if respErr.StatusCode == http.StatusNotFound {
@@ -787,32 +1259,117 @@ func mapAzureError(op, path string, err error) error {
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker by listing at most one blob in the
+// container.
+func (a *Adapter) Ping(ctx context.Context) error {
+	containerClient := a.client.ServiceClient().NewContainerClient(a.containerName)
+	pager := containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix:     &a.prefix,
+		MaxResults: ptr(int32(1)),
+	})
+	if _, err := pager.NextPage(ctx); err != nil {
+		return mapAzureError("ping", "", err)
+	}
+	return nil
+}
+
 // Copy implements filekit.CanCopy using Azure's native StartCopyFromURL.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
-	srcKey := path.Join(a.prefix, src)
-	dstKey := path.Join(a.prefix, dst)
+	return a.copyBlob(ctx, src, dst, nil)
+}
+
+// copyBlob copies src to dst with StartCopyFromURL and waits for the copy to
+// finish. opts may carry destination access conditions.
+func (a *Adapter) copyBlob(ctx context.Context, src, dst string, opts *blob.StartCopyFromURLOptions) error {
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("copy", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("copy", dst, err)
+	}
+
+	// Copying a blob onto itself would only rewrite it
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
 
 	// Generate source URL (need SAS for copy to work)
 	srcURL, err := a.GenerateSASURL(ctx, src, 15*time.Minute, sas.BlobPermissions{Read: true})
 	if err != nil {
 		// If SAS generation fails, try direct URL
-		srcURL = fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s",
-			a.accountName, a.containerName, srcKey)
+		srcURL = a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(srcKey).URL()
 	}
 
 	// Start the copy operation
-	_, err = a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(dstKey).StartCopyFromURL(ctx, srcURL, nil)
+	dstClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(dstKey)
+	resp, err := dstClient.StartCopyFromURL(ctx, srcURL, opts)
 	if err != nil {
 		return mapAzureError("copy", src, err)
 	}
 
+	// Copies within an account usually complete synchronously; otherwise wait
+	// so the destination is complete when Copy returns and Move never deletes
+	// the source of a copy still in progress
+	status := resp.CopyStatus
+	for status != nil && *status == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			return filekit.WrapPathErr("copy", src, ctx.Err())
+		case <-time.After(copyPollInterval):
+		}
+		props, err := dstClient.GetProperties(ctx, nil)
+		if err != nil {
+			return mapAzureError("copy", dst, err)
+		}
+		status = props.CopyStatus
+	}
+	if status != nil && *status != blob.CopyStatusTypeSuccess {
+		return filekit.NewPathError("copy", src, filekit.ErrCodeService, fmt.Sprintf("copy %s", *status))
+	}
+
 	return nil
 }
 
-// Move implements filekit.CanMove using Azure's copy + delete.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+// copyPollInterval is how often Copy checks the status of a pending copy
+var copyPollInterval = time.Second
+
+// Move implements filekit.CanMove using Azure's copy + delete. Moving a blob
+// onto itself only checks that it exists. With filekit.WithMoveNoOverwrite the
+// copy is sent with If-None-Match: *, so an existing dst is never replaced.
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("move", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("move", dst, err)
+	}
+
+	// Deleting the source would delete the destination
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
+
 	// Copy the blob
-	if err := a.Copy(ctx, src, dst); err != nil {
+	var copyOpts *blob.StartCopyFromURLOptions
+	if filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		copyOpts = &blob.StartCopyFromURLOptions{
+			AccessConditions: &blob.AccessConditions{
+				ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfNoneMatch: ptr(azcore.ETagAny)},
+			},
+		}
+	}
+	if err := a.copyBlob(ctx, src, dst, copyOpts); err != nil {
+		// Azure reports If-None-Match: * on an existing blob as a conflict
+		if copyOpts != nil && (bloberror.HasCode(err, bloberror.BlobAlreadyExists) ||
+			filekit.IsCode(err, filekit.ErrCodePreconditionFailed)) {
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
 		return err
 	}
 
@@ -861,8 +1418,14 @@ func (a *Adapter) Checksums(ctx context.Context, filePath string, algorithms []f
 // The filter pattern supports glob patterns like "**/*.json", "config/*".
 // Default polling interval is 30 seconds.
 func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken, error) {
+	return a.WatchMany(ctx, filter)
+}
+
+// WatchMany implements filekit.CanWatchMany. All filters share one poller,
+// so each poll lists the objects once however many filters there are.
+func (a *Adapter) WatchMany(ctx context.Context, filters ...string) (filekit.ChangeToken, error) {
 	// Get initial state of matching files
-	initialState, err := a.getMatchingFilesState(ctx, filter)
+	initialState, err := a.getMatchingFilesState(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -871,7 +1434,7 @@ func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken
 	token := filekit.NewPollingChangeToken(ctx, filekit.PollingConfig{
 		Interval: 30 * time.Second,
 		CheckFunc: func() bool {
-			currentState, err := a.getMatchingFilesState(ctx, filter)
+			currentState, err := a.getMatchingFilesState(ctx, filters)
 			if err != nil {
 				return false // Can't determine change, don't signal
 			}
@@ -889,8 +1452,8 @@ type azureFileState struct {
 	size    int64
 }
 
-// getMatchingFilesState returns the current state of files matching the filter
-func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map[string]azureFileState, error) {
+// getMatchingFilesState returns the current state of files matching any of the filters
+func (a *Adapter) getMatchingFilesState(ctx context.Context, filters []string) (map[string]azureFileState, error) {
 	state := make(map[string]azureFileState)
 
 	// List all blobs with the prefix
@@ -901,7 +1464,7 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return nil, filekit.WrapPathErr("watch", filter, err)
+			return nil, filekit.WrapPathErr("watch", strings.Join(filters, ","), err)
 		}
 
 		for _, blob := range page.Segment.BlobItems {
@@ -912,8 +1475,8 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 			// Remove prefix from key to get relative path
 			relPath := strings.TrimPrefix(*blob.Name, a.prefix)
 
-			// Check if path matches filter
-			if azureMatchesGlobFilter(relPath, filter) {
+			// Check if path matches any filter
+			if azureMatchesAnyGlobFilter(relPath, filters) {
 				var modTime time.Time
 				var size int64
 				if blob.Properties != nil {
@@ -953,6 +1516,16 @@ func azureStatesEqual(a, b map[string]azureFileState) bool {
 	return true
 }
 
+// azureMatchesAnyGlobFilter checks if a path matches at least one of the glob patterns
+func azureMatchesAnyGlobFilter(path string, filters []string) bool {
+	for _, filter := range filters {
+		if azureMatchesGlobFilter(path, filter) {
+			return true
+		}
+	}
+	return false
+}
+
 // azureMatchesGlobFilter checks if a path matches a glob pattern
 func azureMatchesGlobFilter(filePath, filter string) bool {
 	// Handle ** patterns for recursive matching
@@ -981,7 +1554,10 @@ func azureMatchesGlobFilter(filePath, filter string) bool {
 // azureUploadInfo stores metadata for an in-progress chunked upload.
 type azureUploadInfo struct {
 	path     string   // Target blob path
+	blobName string   // Blob name of path under the adapter prefix
 	blockIDs []string // List of block IDs in order
+	sums     []string // SHA-256 of each part, indexed like blockIDs
+	sizes    []int64  // Size of each part, indexed like blockIDs
 	adapter  *Adapter
 	mu       sync.Mutex
 }
@@ -1014,6 +1590,11 @@ func generateBlockID(partNumber int) string {
 // InitiateUpload starts a chunked upload process and returns an upload ID.
 // Uses Azure Block Blob's native chunked upload mechanism.
 func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string, error) {
+	blobName, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
+	}
+
 	// Generate a unique upload ID
 	uploadID, err := generateAzureUploadID()
 	if err != nil {
@@ -1024,6 +1605,7 @@ func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string,
 	azureUploadRegistry.Lock()
 	azureUploadRegistry.uploads[uploadID] = &azureUploadInfo{
 		path:     filePath,
+		blobName: blobName,
 		blockIDs: make([]string, 0),
 		adapter:  a,
 	}
@@ -1032,7 +1614,9 @@ func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string,
 	return uploadID, nil
 }
 
-// UploadPart uploads a part of a file using Azure's StageBlock.
+// UploadPart uploads a part of a file using Azure's StageBlock. The block is
+// sent with its MD5, so Azure rejects blocks corrupted in transit, and its
+// SHA-256 is recorded for CompleteUploadWithChecksum.
 func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
 	// Validate part number
 	if partNumber < 1 {
@@ -1052,11 +1636,13 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 	blockID := generateBlockID(partNumber)
 
 	// Get block blob client
-	blobName := path.Join(a.prefix, info.path)
-	blockBlobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlockBlobClient(blobName)
+	blockBlobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlockBlobClient(info.blobName)
 
 	// Stage the block
-	_, err := blockBlobClient.StageBlock(ctx, blockID, &readSeekCloser{data: data}, nil)
+	md5Sum := md5.Sum(data)
+	_, err := blockBlobClient.StageBlock(ctx, blockID, &readSeekCloser{data: data}, &blockblob.StageBlockOptions{
+		TransactionalValidation: blob.TransferValidationTypeMD5(md5Sum[:]),
+	})
 	if err != nil {
 		return filekit.WrapPathErr("upload-part", uploadID, fmt.Errorf("failed to stage block: %w", err))
 	}
@@ -1066,8 +1652,12 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 	// Ensure we have enough space in the slice
 	for len(info.blockIDs) < partNumber {
 		info.blockIDs = append(info.blockIDs, "")
+		info.sums = append(info.sums, "")
+		info.sizes = append(info.sizes, 0)
 	}
 	info.blockIDs[partNumber-1] = blockID
+	info.sums[partNumber-1] = filekit.PartChecksum(data)
+	info.sizes[partNumber-1] = int64(len(data))
 	info.mu.Unlock()
 
 	return nil
@@ -1113,6 +1703,20 @@ func (r *readSeekCloser) Close() error {
 
 // CompleteUpload finalizes a chunked upload by committing all blocks.
 func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
+	return a.completeUpload(ctx, uploadID, "", false)
+}
+
+// CompleteUploadWithChecksum implements filekit.ChecksumUploader. Azure
+// cannot read uncommitted blocks, so the block list is committed and the blob
+// read back; each part is checked against the SHA-256 recorded by UploadPart
+// and the whole against expected. On a mismatch the blob is deleted.
+func (a *Adapter) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	return a.completeUpload(ctx, uploadID, expected, true)
+}
+
+// completeUpload commits the blocks of an upload, verifying the committed blob
+// if verify is set.
+func (a *Adapter) completeUpload(ctx context.Context, uploadID, expected string, verify bool) error {
 	// Get and remove upload info
 	azureUploadRegistry.Lock()
 	info, ok := azureUploadRegistry.uploads[uploadID]
@@ -1125,12 +1729,14 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 		return filekit.NewPathError("complete-upload", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
 	}
 
-	// Filter out empty block IDs and collect valid ones
+	// Filter out empty block IDs and collect valid ones, in part order
 	info.mu.Lock()
 	var validBlockIDs []string
-	for _, id := range info.blockIDs {
+	var parts []int
+	for i, id := range info.blockIDs {
 		if id != "" {
 			validBlockIDs = append(validBlockIDs, id)
+			parts = append(parts, i)
 		}
 	}
 	info.mu.Unlock()
@@ -1139,12 +1745,8 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 		return filekit.NewPathError("complete-upload", uploadID, filekit.ErrCodeValidation, "no parts uploaded")
 	}
 
-	// Sort block IDs by their part number (they're base64 encoded, but in order)
-	sort.Strings(validBlockIDs)
-
 	// Get block blob client
-	blobName := path.Join(a.prefix, info.path)
-	blockBlobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlockBlobClient(blobName)
+	blockBlobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlockBlobClient(info.blobName)
 
 	// Commit the block list
 	_, err := blockBlobClient.CommitBlockList(ctx, validBlockIDs, &blockblob.CommitBlockListOptions{})
@@ -1152,9 +1754,42 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 		return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to commit block list: %w", err))
 	}
 
+	if verify {
+		if err := a.verifyBlob(ctx, info, parts, expected); err != nil {
+			_, _ = a.client.DeleteBlob(context.WithoutCancel(ctx), a.containerName, info.blobName, nil)
+			return err
+		}
+	}
+
 	return nil
 }
 
+// verifyBlob reads back a committed upload, checking each part (by index into
+// info.sums and info.sizes) against its recorded SHA-256 and the whole blob
+// against expected.
+func (a *Adapter) verifyBlob(ctx context.Context, info *azureUploadInfo, parts []int, expected string) error {
+	body, err := a.Read(ctx, info.path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	whole := sha256.New()
+	for _, i := range parts {
+		part := sha256.New()
+		if _, err := io.CopyN(io.MultiWriter(whole, part), body, info.sizes[i]); err != nil && !errors.Is(err, io.EOF) {
+			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to read part %d: %w", i+1, err))
+		}
+		if err := filekit.VerifyUploadChecksum(info.path, i+1, info.sums[i], part.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	if _, err := io.Copy(whole, body); err != nil {
+		return filekit.WrapPathErr("complete-upload", info.path, err)
+	}
+	return filekit.VerifyUploadChecksum(info.path, 0, expected, whole.Sum(nil))
+}
+
 // AbortUpload cancels a chunked upload.
 // For Azure Block Blobs, uncommitted blocks are automatically cleaned up after 7 days.
 func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
@@ -1172,21 +1807,27 @@ func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
 
 	// Azure automatically cleans up uncommitted blocks after 7 days
 	// We could try to delete any partially committed blob, but it may not exist yet
-	blobName := path.Join(a.prefix, info.path)
-	_, _ = a.client.DeleteBlob(ctx, a.containerName, blobName, nil)
+	_, _ = a.client.DeleteBlob(ctx, a.containerName, info.blobName, nil)
 
 	return nil
 }
 
 // Ensure Adapter implements required and optional interfaces
 var (
-	_ filekit.FileSystem      = (*Adapter)(nil)
-	_ filekit.FileReader      = (*Adapter)(nil)
-	_ filekit.FileWriter      = (*Adapter)(nil)
-	_ filekit.CanCopy         = (*Adapter)(nil)
-	_ filekit.CanMove         = (*Adapter)(nil)
-	_ filekit.CanSignURL      = (*Adapter)(nil)
-	_ filekit.CanChecksum     = (*Adapter)(nil)
-	_ filekit.CanWatch        = (*Adapter)(nil)
-	_ filekit.ChunkedUploader = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanSignURL        = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanWatchMany      = (*Adapter)(nil)
+	_ filekit.CanStatMany       = (*Adapter)(nil)
+	_ filekit.CanDeleteMany     = (*Adapter)(nil)
+	_ filekit.CanListPage       = (*Adapter)(nil)
+	_ filekit.ChunkedUploader   = (*Adapter)(nil)
+	_ filekit.ChecksumUploader  = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
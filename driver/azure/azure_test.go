@@ -0,0 +1,1295 @@
+package azure
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/fstest"
+)
+
+// testAccountKey is a syntactically valid (base64) but fake account key
+const testAccountKey = "dGVzdC1hY2NvdW50LWtleS1mb3ItZmlsZWtpdC11bml0LXRlc3Rz"
+
+func TestParseConnectionString(t *testing.T) {
+	tests := []struct {
+		name    string
+		connStr string
+		want    parsedConnectionString
+	}{
+		{
+			name:    "account key",
+			connStr: "DefaultEndpointsProtocol=https;AccountName=myaccount;AccountKey=" + testAccountKey + ";EndpointSuffix=core.windows.net",
+			want: parsedConnectionString{
+				serviceURL:  "https://myaccount.blob.core.windows.net/",
+				accountName: "myaccount",
+				accountKey:  testAccountKey,
+			},
+		},
+		{
+			name:    "defaults and trailing semicolon",
+			connStr: "AccountName=myaccount;AccountKey=" + testAccountKey + ";",
+			want: parsedConnectionString{
+				serviceURL:  "https://myaccount.blob.core.windows.net/",
+				accountName: "myaccount",
+				accountKey:  testAccountKey,
+			},
+		},
+		{
+			name:    "sovereign cloud suffix",
+			connStr: "DefaultEndpointsProtocol=https;AccountName=myaccount;AccountKey=" + testAccountKey + ";EndpointSuffix=core.chinacloudapi.cn",
+			want: parsedConnectionString{
+				serviceURL:  "https://myaccount.blob.core.chinacloudapi.cn/",
+				accountName: "myaccount",
+				accountKey:  testAccountKey,
+			},
+		},
+		{
+			name:    "explicit blob endpoint",
+			connStr: "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=http://azurite:10000/devstoreaccount1",
+			want: parsedConnectionString{
+				serviceURL:  "http://azurite:10000/devstoreaccount1/",
+				accountName: "devstoreaccount1",
+				accountKey:  testAccountKey,
+			},
+		},
+		{
+			name:    "shared access signature",
+			connStr: "BlobEndpoint=https://myaccount.blob.core.windows.net/;SharedAccessSignature=?sv=2022-11-02&sig=abc%3D",
+			want: parsedConnectionString{
+				serviceURL:            "https://myaccount.blob.core.windows.net/",
+				accountName:           "myaccount",
+				sharedAccessSignature: "sv=2022-11-02&sig=abc%3D",
+			},
+		},
+		{
+			name:    "development storage",
+			connStr: "UseDevelopmentStorage=true",
+			want: parsedConnectionString{
+				serviceURL:  "http://127.0.0.1:10000/devstoreaccount1/",
+				accountName: devStoreAccountName,
+				accountKey:  devStoreAccountKey,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConnectionString(tt.connStr)
+			if err != nil {
+				t.Fatalf("parseConnectionString() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseConnectionString() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConnectionString_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		connStr string
+	}{
+		{"empty", ""},
+		{"malformed segment", "AccountName=myaccount;garbage"},
+		{"no account or endpoint", "AccountKey=" + testAccountKey},
+		{"no credentials", "AccountName=myaccount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseConnectionString(tt.connStr); err == nil {
+				t.Errorf("parseConnectionString(%q) expected error", tt.connStr)
+			}
+		})
+	}
+}
+
+func TestNewFromConnectionString_GenerateSASURL(t *testing.T) {
+	connStr := "AccountName=myaccount;AccountKey=" + testAccountKey
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("tenant"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	signed, err := adapter.GenerateSASURL(context.Background(), "docs/report.pdf", time.Hour, sas.BlobPermissions{Read: true})
+	if err != nil {
+		t.Fatalf("GenerateSASURL() error = %v", err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid SAS URL %q: %v", signed, err)
+	}
+	if u.Host != "myaccount.blob.core.windows.net" {
+		t.Errorf("host = %q, want myaccount.blob.core.windows.net", u.Host)
+	}
+	if u.Path != "/uploads/tenant/docs/report.pdf" {
+		t.Errorf("path = %q, want /uploads/tenant/docs/report.pdf", u.Path)
+	}
+	q := u.Query()
+	if q.Get("sig") == "" {
+		t.Error("SAS URL has no signature")
+	}
+	if q.Get("sp") != "r" {
+		t.Errorf("sp = %q, want r", q.Get("sp"))
+	}
+}
+
+func TestNewFromConnectionString_Invalid(t *testing.T) {
+	if _, err := NewFromConnectionString("AccountName=myaccount", "uploads"); err == nil {
+		t.Fatal("expected error for connection string without credentials")
+	}
+}
+
+func TestAccountNameFromURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"https://myaccount.blob.core.windows.net/", "myaccount", false},
+		{"https://myaccount.blob.core.usgovcloudapi.net", "myaccount", false},
+		{"http://127.0.0.1:10000/devstoreaccount1", "devstoreaccount1", false},
+		{"https://example.com/", "", true},
+		{"not a url", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := accountNameFromURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("accountNameFromURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("accountNameFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSASURL_NoCredentials(t *testing.T) {
+	// A SAS connection string carries neither an account key nor a token credential
+	connStr := "BlobEndpoint=https://myaccount.blob.core.windows.net/;SharedAccessSignature=sv=2022-11-02&sig=abc"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	ctx := context.Background()
+	_, err = adapter.GenerateSASURL(ctx, "docs/report.pdf", time.Hour, sas.BlobPermissions{Read: true})
+	if !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+		t.Errorf("GenerateSASURL() error = %v, want ErrCodeNotSupported", err)
+	}
+	_, err = adapter.GenerateUserDelegationSAS(ctx, "docs/report.pdf", time.Hour, sas.BlobPermissions{Read: true})
+	if !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+		t.Errorf("GenerateUserDelegationSAS() error = %v, want ErrCodeNotSupported", err)
+	}
+}
+
+// staticTokenCredential is an azcore.TokenCredential returning a fixed token
+type staticTokenCredential struct{}
+
+func (staticTokenCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "test-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// delegationKeyServer fakes the Get User Delegation Key operation
+func delegationKeyServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodPost || q.Get("restype") != "service" || q.Get("comp") != "userdelegationkey" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		requests.Add(1)
+
+		start := time.Now().UTC().Format(time.RFC3339)
+		expiry := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><UserDelegationKey>` +
+			`<SignedOid>00000000-0000-0000-0000-000000000001</SignedOid>` +
+			`<SignedTid>00000000-0000-0000-0000-000000000002</SignedTid>` +
+			`<SignedStart>` + start + `</SignedStart><SignedExpiry>` + expiry + `</SignedExpiry>` +
+			`<SignedService>b</SignedService><SignedVersion>2023-11-03</SignedVersion>` +
+			`<Value>` + testAccountKey + `</Value></UserDelegationKey>`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewFromManagedIdentity_UserDelegationSAS(t *testing.T) {
+	var requests atomic.Int32
+	srv := delegationKeyServer(t, &requests)
+
+	adapter, err := NewFromManagedIdentity(srv.URL+"/myaccount", "uploads",
+		WithTokenCredential(staticTokenCredential{}),
+		WithClientOptions(&azblob.ClientOptions{
+			ClientOptions: policy.ClientOptions{
+				Transport: srv.Client(),
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFromManagedIdentity() error = %v", err)
+	}
+	if adapter.accountKey != "" {
+		t.Fatal("managed identity adapter must not have an account key")
+	}
+
+	ctx := context.Background()
+	signed, err := adapter.SignedURL(ctx, "docs/report.pdf", time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("invalid SAS URL %q: %v", signed, err)
+	}
+	if u.Path != "/myaccount/uploads/docs/report.pdf" {
+		t.Errorf("path = %q, want /myaccount/uploads/docs/report.pdf", u.Path)
+	}
+	q := u.Query()
+	// skoid/sktid are only present on user-delegation SAS tokens
+	if q.Get("skoid") != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("skoid = %q, want delegation key object ID", q.Get("skoid"))
+	}
+	if q.Get("sktid") != "00000000-0000-0000-0000-000000000002" {
+		t.Errorf("sktid = %q, want delegation key tenant ID", q.Get("sktid"))
+	}
+	if q.Get("sig") == "" || q.Get("sp") != "r" {
+		t.Errorf("unexpected SAS query %q", u.RawQuery)
+	}
+
+	upload, err := adapter.SignedUploadURL(ctx, "docs/new.pdf", time.Hour)
+	if err != nil {
+		t.Fatalf("SignedUploadURL() error = %v", err)
+	}
+	if uq, _ := url.Parse(upload); uq.Query().Get("sp") != "cw" {
+		t.Errorf("upload sp = %q, want cw", uq.Query().Get("sp"))
+	}
+
+	// The delegation key is reused while it outlives the requested SAS
+	if got := requests.Load(); got != 1 {
+		t.Errorf("user delegation key requested %d times, want 1", got)
+	}
+
+	if _, err := adapter.GenerateUserDelegationSAS(ctx, "docs/report.pdf", 8*24*time.Hour, sas.BlobPermissions{Read: true}); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("expiry beyond 7 days: error = %v, want ErrCodeInvalidInput", err)
+	}
+}
+
+func TestNewFromManagedIdentity_InvalidURL(t *testing.T) {
+	if _, err := NewFromManagedIdentity("://bad", "uploads"); err == nil {
+		t.Fatal("expected error for invalid account URL")
+	}
+}
+
+// batchDeleteServer fakes the Blob Batch API (see batchDeleteHandler).
+func batchDeleteServer(t *testing.T, existing map[string]bool, batches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(batchDeleteHandler(t, existing, batches))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// batchDeleteHandler serves Blob Batch API requests. Blobs set to true in
+// existing are deleted, blobs set to false answer 403 and other names answer
+// 404 BlobNotFound.
+func batchDeleteHandler(t *testing.T, existing map[string]bool, batches *atomic.Int32) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Query().Get("comp") != "batch" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		batches.Add(1)
+
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Errorf("invalid batch content type: %v", err)
+			return
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		var body strings.Builder
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("invalid batch body: %v", err)
+				return
+			}
+			sub, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Errorf("invalid sub-request: %v", err)
+				return
+			}
+
+			status := "202 Accepted"
+			deletable, ok := existing[sub.URL.Path]
+			switch {
+			case !ok:
+				status = "404 The specified blob does not exist.\r\nx-ms-error-code: BlobNotFound"
+			case !deletable:
+				status = "403 Forbidden\r\nx-ms-error-code: AuthorizationPermissionMismatch"
+			default:
+				delete(existing, sub.URL.Path)
+			}
+			fmt.Fprintf(&body, "--batchresponse_test\r\nContent-Type: application/http\r\nContent-ID: %s\r\n\r\nHTTP/1.1 %s\r\nContent-Length: 0\r\n\r\n",
+				part.Header.Get("Content-ID"), status)
+		}
+		body.WriteString("--batchresponse_test--\r\n")
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary=batchresponse_test")
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, body.String())
+	}
+}
+
+func TestDeleteMany_Batch(t *testing.T) {
+	existing := map[string]bool{"/uploads/data/a.txt": true, "/uploads/data/b.txt": true}
+	var batches atomic.Int32
+	srv := batchDeleteServer(t, existing, &batches)
+
+	paths := []string{"a.txt", "missing.txt", "b.txt", "a.txt"}
+	for i := 0; i < 300; i++ {
+		name := fmt.Sprintf("bulk/%d.txt", i)
+		existing["/uploads/data/"+name] = true
+		paths = append(paths, name)
+	}
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"),
+		WithClientOptions(&azblob.ClientOptions{
+			ClientOptions: policy.ClientOptions{Retry: policy.RetryOptions{MaxRetries: -1}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	deleted, errs := adapter.DeleteMany(context.Background(), paths)
+
+	if got := batches.Load(); got != 2 {
+		t.Errorf("submitted %d batches, want 2", got)
+	}
+	if len(errs) != 1 || !filekit.IsCode(errs["missing.txt"], filekit.ErrCodeNotFound) {
+		t.Fatalf("errs = %v, want not found for missing.txt only", errs)
+	}
+	if len(deleted) != 302 {
+		t.Fatalf("got %d deleted paths, want 302", len(deleted))
+	}
+	if deleted[0] != "a.txt" || deleted[1] != "b.txt" {
+		t.Errorf("deleted[:2] = %v, want [a.txt b.txt]", deleted[:2])
+	}
+	if len(existing) != 0 {
+		t.Errorf("%d blobs were not deleted", len(existing))
+	}
+}
+
+func TestDeleteDir_Batch(t *testing.T) {
+	existing := map[string]bool{"/uploads/data/other.txt": true, "/uploads/data/big/locked.txt": false}
+	for i := 0; i < 600; i++ {
+		existing[fmt.Sprintf("/uploads/data/big/%03d.txt", i)] = true
+	}
+	var (
+		mu      sync.Mutex
+		batches atomic.Int32
+	)
+	deleteBatch := batchDeleteHandler(t, existing, &batches)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		q := r.URL.Query()
+		if q.Get("comp") != "list" {
+			deleteBatch(w, r)
+			return
+		}
+		// Pages of 250 blobs; the marker is the last name returned
+		var names []string
+		for p := range existing {
+			name := strings.TrimPrefix(p, "/uploads/")
+			if strings.HasPrefix(name, q.Get("prefix")) && name > q.Get("marker") {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		truncated := len(names) > 250
+		names = names[:min(len(names), 250)]
+
+		var body strings.Builder
+		body.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults ContainerName="uploads"><Blobs>`)
+		for _, name := range names {
+			fmt.Fprintf(&body, `<Blob><Name>%s</Name><Properties><Content-Length>1</Content-Length></Properties></Blob>`, name)
+		}
+		body.WriteString(`</Blobs>`)
+		if truncated {
+			fmt.Fprintf(&body, `<NextMarker>%s</NextMarker>`, names[len(names)-1])
+		}
+		body.WriteString(`</EnumerationResults>`)
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, body.String())
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"),
+		WithClientOptions(&azblob.ClientOptions{
+			ClientOptions: policy.ClientOptions{Retry: policy.RetryOptions{MaxRetries: -1}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	err = adapter.DeleteDir(ctx, "big")
+	if !filekit.IsCode(err, filekit.ErrCodePermission) || !strings.Contains(err.Error(), "big/locked.txt") {
+		t.Errorf("DeleteDir() error = %v, want a permission error for big/locked.txt", err)
+	}
+	if len(existing) != 2 || !existing["/uploads/data/other.txt"] {
+		t.Errorf("%d blobs remain, want only other.txt and the locked blob", len(existing))
+	}
+	if got := batches.Load(); got != 3 {
+		t.Errorf("submitted %d batches, want one per listing page (3)", got)
+	}
+
+	if err := adapter.DeleteDir(ctx, "missing"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("DeleteDir(missing) error = %v, want ErrCodeNotFound", err)
+	}
+}
+
+// listBlobsServer fakes List Blobs over a fixed, sorted set of blob names in
+// the uploads container. Markers are offsets.
+func listBlobsServer(t *testing.T, names []string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodGet || r.URL.Path != "/uploads" || q.Get("comp") != "list" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+		maxResults, _ := strconv.Atoi(q.Get("maxresults"))
+		offset, _ := strconv.Atoi(q.Get("marker"))
+
+		// Collapse names into the entries Azure would return, in order
+		var entries []string
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if delimiter != "" {
+				if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+					name = name[:len(prefix)+i+1]
+					if seen[name] {
+						continue
+					}
+					seen[name] = true
+				}
+			}
+			entries = append(entries, name)
+		}
+
+		end := len(entries)
+		if maxResults > 0 && offset+maxResults < end {
+			end = offset + maxResults
+		}
+		var body strings.Builder
+		body.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults ContainerName="uploads"><Blobs>`)
+		for _, entry := range entries[offset:end] {
+			if delimiter != "" && strings.HasSuffix(entry, delimiter) {
+				fmt.Fprintf(&body, `<BlobPrefix><Name>%s</Name></BlobPrefix>`, entry)
+			} else {
+				fmt.Fprintf(&body, `<Blob><Name>%s</Name><Properties><Content-Length>1</Content-Length></Properties></Blob>`, entry)
+			}
+		}
+		body.WriteString(`</Blobs>`)
+		if end < len(entries) {
+			fmt.Fprintf(&body, `<NextMarker>%d</NextMarker>`, end)
+		}
+		body.WriteString(`</EnumerationResults>`)
+
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, body.String())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestListPage(t *testing.T) {
+	var names []string
+	for i := 0; i < 7; i++ {
+		names = append(names, fmt.Sprintf("data/docs/%d.txt", i))
+	}
+	names = append(names, "data/docs/sub/a.txt", "data/docs/sub/b.txt")
+	srv := listBlobsServer(t, names)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	collect := func(t *testing.T, recursive bool) ([]string, int) {
+		t.Helper()
+		var paths []string
+		pages := 0
+		opts := filekit.ListPageOptions{MaxResults: 3, Recursive: recursive}
+		for {
+			page, err := adapter.ListPage(context.Background(), "docs", opts)
+			if err != nil {
+				t.Fatalf("ListPage() error = %v", err)
+			}
+			if len(page.Entries) > 3 {
+				t.Fatalf("page has %d entries, want at most 3", len(page.Entries))
+			}
+			pages++
+			for _, e := range page.Entries {
+				paths = append(paths, e.Path)
+			}
+			if page.NextToken == "" {
+				return paths, pages
+			}
+			opts.ContinuationToken = page.NextToken
+		}
+	}
+
+	t.Run("non-recursive", func(t *testing.T) {
+		paths, pages := collect(t, false)
+		// Blob prefixes are listed before the blobs of the same page
+		slices.Sort(paths)
+		want := "docs/0.txt docs/1.txt docs/2.txt docs/3.txt docs/4.txt docs/5.txt docs/6.txt docs/sub"
+		if got := strings.Join(paths, " "); got != want {
+			t.Errorf("paths = %s, want %s", got, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		paths, pages := collect(t, true)
+		want := "docs/0.txt docs/1.txt docs/2.txt docs/3.txt docs/4.txt docs/5.txt docs/6.txt docs/sub/a.txt docs/sub/b.txt"
+		if got := strings.Join(paths, " "); got != want {
+			t.Errorf("paths = %s, want %s", got, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+}
+
+func TestStat_ReportsETagAndChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/uploads/data/report.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"0x8DC1234567890AB"`)
+		w.Header().Set("Content-MD5", "CY9rzUYh03PK3k6DJie09g==")
+		w.Header().Set("Content-Length", "4")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	info, err := adapter.Stat(context.Background(), "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.ETag != `"0x8DC1234567890AB"` {
+		t.Errorf("ETag = %q", info.ETag)
+	}
+	if info.Checksum != "098f6bcd4621d373cade4e832627b4f6" || info.ChecksumAlgorithm != filekit.ChecksumMD5 {
+		t.Errorf("Checksum = %q (%s), want MD5 of \"test\"", info.Checksum, info.ChecksumAlgorithm)
+	}
+}
+
+func TestWrite_Conditional(t *testing.T) {
+	var mu sync.Mutex
+	etag, version := `"0x1"`, 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/uploads/data/config.json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != etag {
+			w.Header().Set("x-ms-error-code", "ConditionNotMet")
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" {
+			w.Header().Set("x-ms-error-code", "BlobAlreadyExists")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		version++
+		etag = fmt.Sprintf(`"0x%d"`, version)
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("one of two writers with the same base ETag wins", func(t *testing.T) {
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = adapter.Write(ctx, "config.json", strings.NewReader("update"), filekit.WithIfMatch(`"0x1"`))
+			}()
+		}
+		wg.Wait()
+
+		succeeded, failed := 0, 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, filekit.ErrPreconditionFailed) && filekit.IsCode(err, filekit.ErrCodePreconditionFailed):
+				failed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 1 || failed != 1 {
+			t.Errorf("got %d successes and %d precondition failures, want 1 and 1", succeeded, failed)
+		}
+	})
+
+	t.Run("create only", func(t *testing.T) {
+		_, err := adapter.Write(ctx, "config.json", strings.NewReader("new"), filekit.WithIfNoneMatch("*"))
+		if !errors.Is(err, filekit.ErrPreconditionFailed) {
+			t.Errorf("Write with IfNoneMatch(*) = %v, want ErrPreconditionFailed", err)
+		}
+	})
+}
+
+func TestMove_WaitsForCopyAndDestinationParentExists(t *testing.T) {
+	defer func(interval time.Duration) { copyPollInterval = interval }(copyPollInterval)
+	copyPollInterval = time.Millisecond
+
+	var mu sync.Mutex
+	blobs := map[string]bool{"data/report.txt": true}
+	var deleted []string
+	pendingPolls := 2 // the copy completes on the third status check
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, "/uploads/")
+		switch {
+		case r.Method == http.MethodPut && r.Header.Get("x-ms-copy-source") != "":
+			blobs[name] = true
+			w.Header().Set("x-ms-copy-status", "pending")
+			w.Header().Set("x-ms-copy-id", "copy-1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodHead && blobs[name]:
+			status := "success"
+			if pendingPolls > 0 {
+				pendingPolls--
+				status = "pending"
+			}
+			w.Header().Set("x-ms-copy-status", status)
+			w.Header().Set("Content-Length", "4")
+		case r.Method == http.MethodHead:
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			if pendingPolls > 0 {
+				t.Error("source deleted while the copy was pending")
+			}
+			deleted = append(deleted, name)
+			delete(blobs, name)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Query().Get("comp") == "list":
+			var body strings.Builder
+			body.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults ContainerName="uploads"><Blobs>`)
+			for blobName := range blobs {
+				if strings.HasPrefix(blobName, r.URL.Query().Get("prefix")) {
+					fmt.Fprintf(&body, `<Blob><Name>%s</Name><Properties><Content-Length>4</Content-Length></Properties></Blob>`, blobName)
+				}
+			}
+			body.WriteString(`</Blobs></EnumerationResults>`)
+			w.Header().Set("Content-Type", "application/xml")
+			io.WriteString(w, body.String())
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "archive/2024/report.txt"); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if !slices.Equal(deleted, []string{"data/report.txt"}) {
+		t.Errorf("deleted = %v, want the source once the copy succeeded", deleted)
+	}
+	for _, dir := range []string{"archive", "archive/2024"} {
+		if exists, err := adapter.DirExists(ctx, dir); err != nil || !exists {
+			t.Errorf("DirExists(%s) after move = %v, %v; want true", dir, exists, err)
+		}
+	}
+}
+
+func TestMove_SamePathKeepsBlob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/uploads/data/report.txt":
+			w.Header().Set("Content-Length", "4")
+		case r.Method == http.MethodHead:
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "./report.txt"); err != nil {
+		t.Errorf("Move() onto itself error = %v", err)
+	}
+	if err := adapter.Copy(ctx, "report.txt", "report.txt"); err != nil {
+		t.Errorf("Copy() onto itself error = %v", err)
+	}
+	if err := adapter.Move(ctx, "missing.txt", "missing.txt"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("Move(missing) onto itself = %v, want ErrCodeNotFound", err)
+	}
+}
+
+func TestMove_NoOverwrite(t *testing.T) {
+	var mu sync.Mutex
+	blobs := map[string]bool{"data/a.txt": true, "data/b.txt": true}
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, "/uploads/")
+		switch {
+		case r.Method == http.MethodPut && r.Header.Get("x-ms-copy-source") != "":
+			if r.Header.Get("If-None-Match") == "*" && blobs[name] {
+				w.Header().Set("x-ms-error-code", "BlobAlreadyExists")
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			blobs[name] = true
+			w.Header().Set("x-ms-copy-status", "success")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, name)
+			delete(blobs, name)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"))
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+		t.Fatalf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("deleted = %v after a refused move, want nothing", deleted)
+	}
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Move() onto existing error = %v", err)
+	}
+	if !slices.Equal(deleted, []string{"data/a.txt"}) {
+		t.Errorf("deleted = %v, want the source", deleted)
+	}
+}
+
+func TestWrite_AccountDisabledIsQuotaError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("x-ms-error-code", "AccountIsDisabled")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	_, err = adapter.Write(context.Background(), "a.txt", strings.NewReader("data"), filekit.WithOverwrite(true))
+	if !errors.Is(err, filekit.ErrQuotaExceeded) || !filekit.IsCode(err, filekit.ErrCodeQuotaExceeded) {
+		t.Errorf("Write to a disabled account = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestWrite_StorageClass(t *testing.T) {
+	var mu sync.Mutex
+	var tiers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		switch r.Method {
+		case http.MethodPut:
+			mu.Lock()
+			tiers = append(tiers, r.Header.Get("x-ms-access-tier"))
+			mu.Unlock()
+			w.Header().Set("ETag", `"0x1"`)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			w.Header().Set("x-ms-error-code", "BlobArchived")
+			w.WriteHeader(http.StatusConflict)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "backup.tar", strings.NewReader("data"), filekit.WithOverwrite(true), filekit.WithStorageClass("archive")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(tiers) != 1 || tiers[0] != "Archive" {
+		t.Errorf("x-ms-access-tier = %q, want [Archive]", tiers)
+	}
+
+	_, err = adapter.Write(ctx, "bad.tar", strings.NewReader("data"), filekit.WithOverwrite(true), filekit.WithStorageClass("GLACIER"))
+	if !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("Write() with unknown tier = %v, want ErrCodeInvalidInput", err)
+	}
+	if len(tiers) != 1 {
+		t.Error("Write() with unknown tier reached Azure")
+	}
+
+	_, err = adapter.Read(ctx, "backup.tar")
+	if !errors.Is(err, filekit.ErrNotReadable) || !filekit.IsCode(err, filekit.ErrCodeNotReadable) {
+		t.Errorf("Read() of archived blob = %v, want ErrNotReadable", err)
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	const disposition = `attachment; filename="report.pdf"`
+	var mu sync.Mutex
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			sent = r.Header.Get("x-ms-blob-content-disposition")
+			w.Header().Set("ETag", `"0x1"`)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodHead:
+			w.Header().Set("Content-Disposition", sent)
+			w.Header().Set("Content-Length", "3")
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "report.pdf", strings.NewReader("pdf"), filekit.WithOverwrite(true), filekit.WithContentDisposition(disposition)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if sent != disposition {
+		t.Errorf("x-ms-blob-content-disposition = %q, want %q", sent, disposition)
+	}
+
+	info, err := adapter.Stat(ctx, "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.ContentDisposition != disposition {
+		t.Errorf("Stat() ContentDisposition = %q, want %q", info.ContentDisposition, disposition)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	var mu sync.Mutex
+	var body, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		body, contentType = string(data), r.Header.Get("x-ms-blob-content-type")
+		mu.Unlock()
+		w.Header().Set("ETag", `"0x1"`)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	localPath := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(localPath, []byte("png bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := adapter.WriteFile(context.Background(), "images/logo", localPath, filekit.WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if result.BytesWritten != int64(len("png bytes")) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("png bytes"))
+	}
+	if body != "png bytes" {
+		t.Errorf("uploaded body = %q, want %q", body, "png bytes")
+	}
+	if contentType != "image/png" {
+		t.Errorf("x-ms-blob-content-type = %q, want image/png from the local file name", contentType)
+	}
+}
+
+func TestWrite_ContentTypeResolution(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	var mu sync.Mutex
+	sent := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if r.Method != http.MethodPut {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		sent[strings.TrimPrefix(r.URL.Path, "/uploads/")] = r.Header.Get("x-ms-blob-content-type")
+		mu.Unlock()
+		w.Header().Set("ETag", `"0x1"`)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		content string
+		options []filekit.Option
+		want    string
+	}{
+		{"data.json", `{"ok": true}`, nil, "application/json"},
+		{"upload", png, nil, "image/png"},
+		{"forced.json", "{}", []filekit.Option{filekit.WithForceContentType("text/plain")}, "text/plain"},
+	}
+	for _, tt := range tests {
+		options := append([]filekit.Option{filekit.WithOverwrite(true)}, tt.options...)
+		result, err := adapter.Write(context.Background(), tt.path, strings.NewReader(tt.content), options...)
+		if err != nil {
+			t.Fatalf("Write(%s) error = %v", tt.path, err)
+		}
+		if result.ContentType != tt.want || sent[tt.path] != tt.want {
+			t.Errorf("%s: ContentType = %q (result), %q (sent); want %q", tt.path, result.ContentType, sent[tt.path], tt.want)
+		}
+	}
+}
+
+// blockStore is a fake Azure container for chunked uploads: staged blocks,
+// block list commits, reads and deletes.
+type blockStore struct {
+	mu      sync.Mutex
+	staged  map[string][]byte // block ID -> data
+	blobs   map[string][]byte
+	deleted []string
+}
+
+func newBlockServer(t *testing.T, store *blockStore) *Adapter {
+	t.Helper()
+	store.staged = make(map[string][]byte)
+	store.blobs = make(map[string][]byte)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		name := strings.TrimPrefix(r.URL.Path, "/uploads/")
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPut && q.Get("comp") == "block":
+			sum := md5.Sum(data)
+			if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(sum[:]) {
+				w.Header().Set("x-ms-error-code", "Md5Mismatch")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			store.staged[q.Get("blockid")] = data
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && q.Get("comp") == "blocklist":
+			var list struct {
+				Latest []string `xml:"Latest"`
+			}
+			if err := xml.Unmarshal(data, &list); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var blob []byte
+			for _, id := range list.Latest {
+				blob = append(blob, store.staged[id]...)
+			}
+			store.blobs[name] = blob
+			w.Header().Set("ETag", `"0x1"`)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet:
+			blob, ok := store.blobs[name]
+			if !ok {
+				w.Header().Set("x-ms-error-code", "BlobNotFound")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			_, _ = w.Write(blob)
+		case r.Method == http.MethodDelete:
+			delete(store.blobs, name)
+			store.deleted = append(store.deleted, name)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads")
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	return adapter
+}
+
+func TestCompleteUploadWithChecksum(t *testing.T) {
+	ctx := context.Background()
+	parts := []string{"hello", " ", "world"}
+	whole := filekit.PartChecksum([]byte("hello world"))
+
+	stage := func(t *testing.T, adapter *Adapter, name string) string {
+		t.Helper()
+		uploadID, err := adapter.InitiateUpload(ctx, name)
+		if err != nil {
+			t.Fatalf("InitiateUpload() error = %v", err)
+		}
+		for i, part := range parts {
+			if err := adapter.UploadPart(ctx, uploadID, i+1, []byte(part)); err != nil {
+				t.Fatalf("UploadPart(%d) error = %v", i+1, err)
+			}
+		}
+		return uploadID
+	}
+
+	t.Run("matching checksum", func(t *testing.T) {
+		store := &blockStore{}
+		adapter := newBlockServer(t, store)
+
+		uploadID := stage(t, adapter, "docs/a.txt")
+		if err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole); err != nil {
+			t.Fatalf("CompleteUploadWithChecksum() error = %v", err)
+		}
+		if got := string(store.blobs["docs/a.txt"]); got != "hello world" {
+			t.Errorf("blob = %q, want %q", got, "hello world")
+		}
+	})
+
+	t.Run("corrupted part", func(t *testing.T) {
+		store := &blockStore{}
+		adapter := newBlockServer(t, store)
+
+		uploadID := stage(t, adapter, "docs/b.txt")
+		store.staged[generateBlockID(3)] = []byte("w0rld")
+
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole)
+		var mismatch *filekit.ChecksumMismatchError
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) || !errors.As(err, &mismatch) || mismatch.Part != 3 {
+			t.Fatalf("CompleteUploadWithChecksum() error = %v, want integrity error for part 3", err)
+		}
+		if _, ok := store.blobs["docs/b.txt"]; ok {
+			t.Error("corrupted blob was not deleted")
+		}
+	})
+
+	t.Run("wrong expected checksum", func(t *testing.T) {
+		store := &blockStore{}
+		adapter := newBlockServer(t, store)
+
+		uploadID := stage(t, adapter, "docs/c.txt")
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, filekit.PartChecksum([]byte("other")))
+		var mismatch *filekit.ChecksumMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Part != 0 {
+			t.Fatalf("CompleteUploadWithChecksum() error = %v, want whole-blob checksum mismatch", err)
+		}
+		if _, ok := store.blobs["docs/c.txt"]; ok {
+			t.Error("mismatched blob was not deleted")
+		}
+	})
+
+	t.Run("CompleteUpload skips verification", func(t *testing.T) {
+		store := &blockStore{}
+		adapter := newBlockServer(t, store)
+
+		uploadID := stage(t, adapter, "docs/d.txt")
+		store.staged[generateBlockID(1)] = []byte("jello")
+		if err := adapter.CompleteUpload(ctx, uploadID); err != nil {
+			t.Fatalf("CompleteUpload() error = %v", err)
+		}
+		if got := string(store.blobs["docs/d.txt"]); got != "jello world" {
+			t.Errorf("blob = %q, want %q", got, "jello world")
+		}
+	})
+}
+
+func TestPathEscapes(t *testing.T) {
+	var requests []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("x-ms-error-code", "BlobNotFound")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	connStr := "AccountName=devstoreaccount1;AccountKey=" + testAccountKey + ";BlobEndpoint=" + srv.URL + "/"
+	adapter, err := NewFromConnectionString(connStr, "uploads", WithPrefix("data"),
+		WithClientOptions(&azblob.ClientOptions{
+			ClientOptions: policy.ClientOptions{Retry: policy.RetryOptions{MaxRetries: -1}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFromConnectionString() error = %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("rejected before any request", func(t *testing.T) {
+		fstest.TestPathEscapes(t, adapter)
+
+		p := "../other/x"
+		checks := map[string]error{}
+		_, checks["InitiateUpload"] = adapter.InitiateUpload(ctx, p)
+		_, checks["GenerateSASURL"] = adapter.GenerateSASURL(ctx, p, time.Minute, sas.BlobPermissions{Read: true})
+		_, errs := adapter.DeleteMany(ctx, []string{p})
+		checks["DeleteMany"] = errs[p]
+		for op, err := range checks {
+			if !filekit.IsCode(err, filekit.ErrCodePermission) {
+				t.Errorf("%s(%q) = %v, want ErrCodePermission", op, p, err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(requests) > 0 {
+			t.Errorf("escaping paths reached the server: %v", requests)
+		}
+	})
+
+	t.Run("keys are cleaned", func(t *testing.T) {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		if _, err := adapter.FileExists(ctx, "/a//b/../c.txt"); err != nil {
+			t.Fatalf("FileExists failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if want := []string{"HEAD /uploads/data/a/c.txt"}; !slices.Equal(requests, want) {
+			t.Errorf("requests = %v, want %v", requests, want)
+		}
+	})
+}
@@ -16,7 +16,8 @@ require (
 	github.com/gobeaver/beaver-kit/config v0.1.0 // indirect
 	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
 	golang.org/x/net v0.43.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/gobeaver/filekit => ../..
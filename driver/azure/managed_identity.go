@@ -0,0 +1,131 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	// imdsEndpoint is the Azure Instance Metadata Service token endpoint (VMs, AKS, Container Instances)
+	imdsEndpoint   = "http://169.254.169.254/metadata/identity/oauth2/token"
+	imdsAPIVersion = "2018-02-01"
+
+	// appServiceAPIVersion is the token API exposed through IDENTITY_ENDPOINT (App Service, Functions, Container Apps)
+	appServiceAPIVersion = "2019-08-01"
+)
+
+// managedIdentityCredential is an azcore.TokenCredential that obtains tokens
+// from the managed identity endpoint of the hosting Azure resource.
+// Token caching and refresh are handled by the azcore bearer token policy.
+type managedIdentityCredential struct {
+	clientID   string
+	httpClient *http.Client
+}
+
+// newManagedIdentityCredential creates a managed identity credential.
+// An empty clientID selects the system-assigned identity.
+func newManagedIdentityCredential(clientID string) *managedIdentityCredential {
+	return &managedIdentityCredential{
+		clientID:   clientID,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// managedIdentityToken is the token response shared by IMDS and App Service
+type managedIdentityToken struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresOn   json.Number `json:"expires_on"`
+}
+
+// GetToken implements azcore.TokenCredential
+func (c *managedIdentityCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if len(opts.Scopes) != 1 {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: expected exactly one scope, got %d", len(opts.Scopes))
+	}
+	resource := strings.TrimSuffix(opts.Scopes[0], "/.default")
+
+	req, err := c.newTokenRequest(ctx, resource)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: token endpoint returned %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token managedIdentityToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: token response has no access_token")
+	}
+
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn.String(), 10, 64)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("managed identity: invalid expires_on %q", token.ExpiresOn)
+	}
+
+	return azcore.AccessToken{
+		Token:     token.AccessToken,
+		ExpiresOn: time.Unix(expiresOn, 0).UTC(),
+	}, nil
+}
+
+// newTokenRequest builds the token request for the current hosting environment.
+// IDENTITY_ENDPOINT and IDENTITY_HEADER are set by App Service and similar hosts;
+// everywhere else the instance metadata service is used.
+func (c *managedIdentityCredential) newTokenRequest(ctx context.Context, resource string) (*http.Request, error) {
+	endpoint, header := os.Getenv("IDENTITY_ENDPOINT"), os.Getenv("IDENTITY_HEADER")
+
+	query := url.Values{}
+	query.Set("resource", resource)
+
+	var req *http.Request
+	var err error
+	if endpoint != "" && header != "" {
+		query.Set("api-version", appServiceAPIVersion)
+		if c.clientID != "" {
+			query.Set("client_id", c.clientID)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("managed identity: %w", err)
+		}
+		req.Header.Set("X-IDENTITY-HEADER", header)
+		return req, nil
+	}
+
+	query.Set("api-version", imdsAPIVersion)
+	if c.clientID != "" {
+		query.Set("client_id", c.clientID)
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, imdsEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("managed identity: %w", err)
+	}
+	req.Header.Set("Metadata", "true")
+	return req, nil
+}
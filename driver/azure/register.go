@@ -9,20 +9,35 @@ import (
 
 func init() {
 	filekit.RegisterDriver("azure", func(cfg *filekit.Config) (filekit.FileSystem, error) {
-		if cfg.AzureAccountName == "" || cfg.AzureAccountKey == "" {
-			return nil, fmt.Errorf("azure account name and key are required")
-		}
-
 		if cfg.AzureContainerName == "" {
 			return nil, fmt.Errorf("azure container name is required")
 		}
 
+		var options []AdapterOption
+		if cfg.AzurePrefix != "" {
+			options = append(options, WithPrefix(cfg.AzurePrefix))
+		}
+
 		// Build service URL
 		serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", cfg.AzureAccountName)
 		if cfg.AzureEndpoint != "" {
 			serviceURL = cfg.AzureEndpoint
 		}
 
+		switch {
+		case cfg.AzureConnectionString != "":
+			return NewFromConnectionString(cfg.AzureConnectionString, cfg.AzureContainerName, options...)
+		case cfg.AzureUseManagedIdentity:
+			if cfg.AzureAccountName == "" && cfg.AzureEndpoint == "" {
+				return nil, fmt.Errorf("azure account name or endpoint is required for managed identity")
+			}
+			return NewFromManagedIdentity(serviceURL, cfg.AzureContainerName, options...)
+		}
+
+		if cfg.AzureAccountName == "" || cfg.AzureAccountKey == "" {
+			return nil, fmt.Errorf("azure account name and key are required")
+		}
+
 		// Create shared key credential
 		cred, err := azblob.NewSharedKeyCredential(cfg.AzureAccountName, cfg.AzureAccountKey)
 		if err != nil {
@@ -35,11 +50,6 @@ func init() {
 			return nil, fmt.Errorf("failed to create azure client: %w", err)
 		}
 
-		var options []AdapterOption
-		if cfg.AzurePrefix != "" {
-			options = append(options, WithPrefix(cfg.AzurePrefix))
-		}
-
 		return New(client, cfg.AzureContainerName, cfg.AzureAccountName, cfg.AzureAccountKey, options...), nil
 	})
 }
@@ -3,9 +3,11 @@ package gcs
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"net/http"
 	"os"
@@ -19,6 +21,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/gobeaver/filekit"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
@@ -27,8 +30,15 @@ type Adapter struct {
 	client *storage.Client
 	bucket string
 	prefix string
+
+	// chunkSize is the resumable upload chunk size (0 = single request)
+	chunkSize int
 }
 
+// DefaultChunkSize is the resumable upload chunk size used by Write unless
+// WithChunkSize is set. Each in-progress Write holds one chunk in memory.
+const DefaultChunkSize = 16 * 1024 * 1024
+
 // AdapterOption is a function that configures GCS Adapter
 type AdapterOption func(*Adapter)
 
@@ -43,11 +53,24 @@ func WithPrefix(prefix string) AdapterOption {
 	}
 }
 
+// WithChunkSize sets the chunk size of the resumable uploads used by Write.
+// Content is streamed to GCS one chunk at a time, so n bounds the memory a
+// Write uses and each chunk is retried on transient errors. n is rounded up to
+// a multiple of 256 KiB. n <= 0 disables chunking: the content is sent in a
+// single request without buffering, but a failed upload cannot be retried or
+// resumed. Default: DefaultChunkSize.
+func WithChunkSize(n int) AdapterOption {
+	return func(a *Adapter) {
+		a.chunkSize = max(n, 0)
+	}
+}
+
 // New creates a new GCS filesystem adapter
 func New(client *storage.Client, bucket string, options ...AdapterOption) *Adapter {
 	adapter := &Adapter{
-		client: client,
-		bucket: bucket,
+		client:    client,
+		bucket:    bucket,
+		chunkSize: DefaultChunkSize,
 	}
 
 	// Apply options
@@ -61,16 +84,28 @@ func New(client *storage.Client, bucket string, options ...AdapterOption) *Adapt
 // Write implements filekit.FileWriter
 func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
 	opts := processOptions(options...)
+	class, err := storageClass(filePath, opts.StorageClass)
+	if err != nil {
+		return nil, err
+	}
 
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("write", filePath, err)
+	}
 
 	// Get bucket handle
 	bkt := a.client.Bucket(a.bucket)
 	obj := bkt.Object(key)
 
-	// Check if file exists and overwrite is not allowed
-	if !opts.Overwrite {
+	// Make the write conditional if requested, or check that overwrite is allowed
+	target := obj
+	if opts.HasPreconditions() {
+		if target, err = a.conditionalObject(ctx, obj, filePath, opts); err != nil {
+			return nil, err
+		}
+	} else if !opts.Overwrite {
 		_, err := obj.Attrs(ctx)
 		if err == nil {
 			return nil, filekit.WrapPathErr("write", filePath, filekit.ErrExist)
@@ -80,22 +115,39 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		}
 	}
 
-	// Create a writer
-	writer := obj.NewWriter(ctx)
-
-	// Set content type if provided
-	if opts.ContentType != "" {
-		writer.ContentType = opts.ContentType
-	} else {
-		// Try to detect content type from extension
-		writer.ContentType = detectContentType(filePath)
+	// Resolve the content type, sniffing the content if the extension is unknown
+	contentType := opts.ContentType
+	if !opts.ForceContentType {
+		contentType, content, err = filekit.PeekContentType(filePath, opts.ContentType, content)
+		if err != nil {
+			return nil, filekit.WrapPathErr("write", filePath, err)
+		}
 	}
 
+	// Create a writer. Cancelling its context on error aborts the upload, so
+	// a failed stream never leaves a truncated object behind.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	writer := target.NewWriter(writeCtx)
+	writer.ChunkSize = a.writerChunkSize(content)
+
+	// An empty forced content type stops GCS from sniffing one itself
+	writer.ContentType = contentType
+	writer.ForceEmptyContentType = opts.ForceContentType && contentType == ""
+
 	// Set cache control if provided
 	if opts.CacheControl != "" {
 		writer.CacheControl = opts.CacheControl
 	}
 
+	// Set content disposition if provided
+	if opts.ContentDisposition != "" {
+		writer.ContentDisposition = opts.ContentDisposition
+	}
+
+	// An empty storage class selects the bucket default
+	writer.StorageClass = class
+
 	// Set metadata if provided
 	if len(opts.Metadata) > 0 {
 		writer.Metadata = opts.Metadata
@@ -108,9 +160,25 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		}
 	}
 
+	// Compute a client-side CRC32C. Seekable content is hashed up front so the
+	// checksum is sent with the upload and GCS rejects corrupted data itself;
+	// other readers are hashed while streaming and checked after the upload.
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		sum, err := crc32cOf(seeker)
+		if err != nil {
+			cancel()
+			writer.Close()
+			return nil, filekit.WrapPathErr("write", filePath, err)
+		}
+		writer.CRC32C = sum
+		writer.SendCRC32C = true
+	}
+	crc := crc32.New(crc32cTable)
+
 	// Copy content to writer while counting bytes
-	written, err := io.Copy(writer, content)
+	written, err := io.Copy(io.MultiWriter(writer, crc), opts.TrackProgress(content))
 	if err != nil {
+		cancel()
 		writer.Close()
 		return nil, mapGCSError("write", filePath, err)
 	}
@@ -120,32 +188,106 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		return nil, mapGCSError("write", filePath, err)
 	}
 
-	// Get the object attrs for metadata
-	attrs, err := obj.Attrs(ctx)
-	var etag, checksum string
-	var serverTime time.Time
-	if err == nil {
-		etag = attrs.Etag
-		if len(attrs.MD5) > 0 {
-			checksum = hex.EncodeToString(attrs.MD5)
+	attrs := writer.Attrs()
+	if attrs == nil {
+		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeInternal, "upload returned no object attributes")
+	}
+	if attrs.CRC32C != crc.Sum32() {
+		// Never leave a corrupted object behind
+		if err := obj.Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeIntegrity,
+				fmt.Sprintf("CRC32C mismatch (sent %08x, stored %08x) and failed to delete corrupted object: %v",
+					crc.Sum32(), attrs.CRC32C, err))
 		}
-		serverTime = attrs.Updated
-	} else {
+		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeIntegrity,
+			fmt.Sprintf("CRC32C mismatch: sent %08x, stored %08x", crc.Sum32(), attrs.CRC32C))
+	}
+
+	var checksum string
+	if len(attrs.MD5) > 0 {
+		checksum = hex.EncodeToString(attrs.MD5)
+	}
+	serverTime := attrs.Updated
+	if serverTime.IsZero() {
 		serverTime = time.Now()
 	}
+	if attrs.ContentType != "" {
+		contentType = attrs.ContentType
+	}
 
 	return &filekit.WriteResult{
 		BytesWritten:      written,
-		ETag:              etag,
+		ETag:              attrs.Etag,
 		Checksum:          checksum,
 		ChecksumAlgorithm: filekit.ChecksumMD5,
+		ContentType:       contentType,
 		ServerTimestamp:   serverTime,
 	}, nil
 }
 
+// crc32cTable is the Castagnoli table GCS uses for object checksums
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// writerChunkSize returns the chunk size for uploading content. Content known
+// to fit in one chunk gets a chunk just large enough to hold it, so small
+// writes do not allocate a full-size buffer.
+func (a *Adapter) writerChunkSize(content io.Reader) int {
+	if a.chunkSize == 0 {
+		return 0
+	}
+	size := knownSize(content)
+	if size < 0 || size >= int64(a.chunkSize) {
+		return a.chunkSize
+	}
+	// A chunk that is filled exactly would start a resumable session
+	chunks := size/googleapi.MinUploadChunkSize + 1
+	return int(chunks * googleapi.MinUploadChunkSize)
+}
+
+// knownSize returns the number of bytes left in r, or -1 if unknown
+func knownSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(pos, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - pos
+	}
+	return -1
+}
+
+// crc32cOf hashes the remainder of r and seeks back to where it started
+func crc32cOf(r io.ReadSeeker) (uint32, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	crc := crc32.New(crc32cTable)
+	if _, err := io.Copy(crc, r); err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return crc.Sum32(), nil
+}
+
 // Read implements filekit.FileReader
 func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("read", filePath, err)
+	}
 
 	bkt := a.client.Bucket(a.bucket)
 	obj := bkt.Object(key)
@@ -170,7 +312,10 @@ func (a *Adapter) ReadAll(ctx context.Context, path string) ([]byte, error) {
 
 // Delete implements filekit.FileSystem
 func (a *Adapter) Delete(ctx context.Context, filePath string) error {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return filekit.WrapPathErr("delete", filePath, err)
+	}
 
 	bkt := a.client.Bucket(a.bucket)
 	obj := bkt.Object(key)
@@ -184,7 +329,10 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 
 // FileExists checks if a file exists (not a directory)
 func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return false, filekit.WrapPathErr("fileexists", filePath, err)
+	}
 
 	// Ensure it's not a directory marker
 	if strings.HasSuffix(key, "/") {
@@ -203,7 +351,7 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 	}
 
 	// Check if it's a directory marker
-	if strings.HasSuffix(attrs.Name, "/") || attrs.ContentType == "application/x-directory" {
+	if filekit.IsDirMarker(attrs.Name, attrs.Size, attrs.ContentType) {
 		return false, nil
 	}
 
@@ -212,7 +360,10 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 
 // DirExists checks if a directory prefix exists
 func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
-	dirKey := path.Join(a.prefix, dirPath)
+	dirKey, err := a.key(dirPath)
+	if err != nil {
+		return false, filekit.WrapPathErr("direxists", dirPath, err)
+	}
 	if !strings.HasSuffix(dirKey, "/") {
 		dirKey += "/"
 	}
@@ -221,7 +372,7 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 
 	// Check if the directory marker exists
 	obj := bkt.Object(dirKey)
-	_, err := obj.Attrs(ctx)
+	_, err = obj.Attrs(ctx)
 	if err == nil {
 		return true, nil
 	}
@@ -247,7 +398,10 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 
 // Stat implements filekit.FileReader
 func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("stat", filePath, err)
+	}
 
 	bkt := a.client.Bucket(a.bucket)
 	obj := bkt.Object(key)
@@ -258,7 +412,7 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	}
 
 	// Determine if it's a directory
-	isDir := strings.HasSuffix(key, "/") || attrs.ContentType == "application/x-directory"
+	isDir := filekit.IsDirMarker(attrs.Name, attrs.Size, attrs.ContentType)
 
 	// Determine checksum - prefer CRC32C (GCS native), fall back to MD5
 	var checksum string
@@ -279,46 +433,44 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	}
 
 	return &filekit.FileInfo{
-		Name:              filepath.Base(filePath),
-		Path:              filePath,
-		Size:              attrs.Size,
-		ModTime:           attrs.Updated,
-		IsDir:             isDir,
-		ContentType:       attrs.ContentType,
-		Metadata:          attrs.Metadata,
-		ETag:              attrs.Etag,
-		Version:           strconv.FormatInt(attrs.Generation, 10),
-		StorageClass:      attrs.StorageClass,
-		Checksum:          checksum,
-		ChecksumAlgorithm: checksumAlgorithm,
-		CreatedAt:         createdAt,
+		Name:               filepath.Base(filePath),
+		Path:               filePath,
+		Size:               attrs.Size,
+		ModTime:            attrs.Updated,
+		IsDir:              isDir,
+		ContentType:        attrs.ContentType,
+		ContentDisposition: attrs.ContentDisposition,
+		Metadata:           attrs.Metadata,
+		ETag:               attrs.Etag,
+		Version:            strconv.FormatInt(attrs.Generation, 10),
+		StorageClass:       attrs.StorageClass,
+		Checksum:           checksum,
+		ChecksumAlgorithm:  checksumAlgorithm,
+		CreatedAt:          createdAt,
 	}, nil
 }
 
-// ListContents lists files and directories at the specified path
-func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
-	// Prepare prefix for listing
-	listPrefix := path
-	if a.prefix != "" {
-		listPrefix = strings.TrimPrefix(path, "/")
-		listPrefix = strings.TrimSuffix(a.prefix, "/") + "/" + listPrefix
-	}
-	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
-		listPrefix += "/"
-	}
+// StatMany implements filekit.CanStatMany by fetching object attributes
+// concurrently, bounded by filekit.DefaultStatConcurrency.
+func (a *Adapter) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	return filekit.StatConcurrently(ctx, paths, filekit.DefaultStatConcurrency, a.Stat)
+}
 
-	bkt := a.client.Bucket(a.bucket)
+// DeleteMany implements filekit.CanDeleteMany by deleting objects
+// concurrently, bounded by filekit.DefaultDeleteConcurrency.
+func (a *Adapter) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	return filekit.DeleteConcurrently(ctx, paths, filekit.DefaultDeleteConcurrency, a.Delete)
+}
 
-	// Create query with or without delimiter based on recursive flag
-	query := &storage.Query{
-		Prefix: listPrefix,
-	}
-	if !recursive {
-		query.Delimiter = "/"
+// ListContents lists files and directories at the specified path
+func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
+	listPrefix, err := a.listPrefix(path)
+	if err != nil {
+		return nil, filekit.WrapPathErr("listcontents", path, err)
 	}
 
 	var files []filekit.FileInfo
-	it := bkt.Objects(ctx, query)
+	it := a.client.Bucket(a.bucket).Objects(ctx, a.listQuery(listPrefix, recursive))
 
 	for {
 		attrs, err := it.Next()
@@ -329,81 +481,151 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 			return nil, mapGCSError("listcontents", path, err)
 		}
 
-		// Handle "directory" prefixes (only when not recursive)
-		if attrs.Prefix != "" {
-			dirName := strings.TrimPrefix(attrs.Prefix, listPrefix)
-			dirName = strings.TrimSuffix(dirName, "/")
-			if dirName == "" {
-				continue
-			}
-
-			files = append(files, filekit.FileInfo{
-				Name:  filepath.Base(dirName),
-				Path:  strings.TrimPrefix(attrs.Prefix, a.prefix),
-				IsDir: true,
-			})
-			continue
+		if info, ok := a.listEntry(listPrefix, recursive, attrs); ok {
+			files = append(files, info)
 		}
+	}
 
-		// Skip the directory itself
-		if attrs.Name == listPrefix {
-			continue
-		}
+	filekit.SortFiles(files, filekit.SortByName, false)
+	return files, nil
+}
 
-		// Get the file name relative to the prefix
-		relPath := strings.TrimPrefix(attrs.Name, listPrefix)
-		if relPath == "" {
-			continue
-		}
+// ListPage implements filekit.CanListPage using GCS page tokens.
+func (a *Adapter) ListPage(ctx context.Context, path string, opts filekit.ListPageOptions) (filekit.ListPage, error) {
+	listPrefix, err := a.listPrefix(path)
+	if err != nil {
+		return filekit.ListPage{}, filekit.WrapPathErr("listpage", path, err)
+	}
 
-		// For non-recursive, skip items with slashes (deeper nested items)
-		if !recursive && strings.Contains(relPath, "/") {
-			continue
-		}
+	it := a.client.Bucket(a.bucket).Objects(ctx, a.listQuery(listPrefix, opts.Recursive))
+	pager := iterator.NewPager(it, opts.PageSize(), opts.ContinuationToken)
 
-		isDir := strings.HasSuffix(attrs.Name, "/") || attrs.ContentType == "application/x-directory"
-
-		// Determine checksum - prefer CRC32C (GCS native), fall back to MD5
-		var checksum string
-		var checksumAlgorithm filekit.ChecksumAlgorithm
-		if attrs.CRC32C != 0 {
-			checksum = fmt.Sprintf("%08x", attrs.CRC32C)
-			checksumAlgorithm = filekit.ChecksumCRC32C
-		} else if len(attrs.MD5) > 0 {
-			checksum = hex.EncodeToString(attrs.MD5)
-			checksumAlgorithm = filekit.ChecksumMD5
+	var objects []*storage.ObjectAttrs
+	nextToken, err := pager.NextPage(&objects)
+	if err != nil {
+		return filekit.ListPage{}, mapGCSError("listpage", path, err)
+	}
+
+	page := filekit.ListPage{Entries: make([]filekit.FileInfo, 0, len(objects)), NextToken: nextToken}
+	for _, attrs := range objects {
+		if info, ok := a.listEntry(listPrefix, opts.Recursive, attrs); ok {
+			page.Entries = append(page.Entries, info)
 		}
+	}
+	return page, nil
+}
 
-		// Handle CreatedAt
-		var createdAt *time.Time
-		if !attrs.Created.IsZero() {
-			createdAt = &attrs.Created
+// key returns the object name for p, normalized with filekit.CleanPath. Paths
+// that escape the prefix return filekit.ErrNotAllowed.
+func (a *Adapter) key(p string) (string, error) {
+	clean, err := filekit.CleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(a.prefix, clean), nil
+}
+
+// listPrefix returns the object prefix under which the entries of p are stored
+func (a *Adapter) listPrefix(p string) (string, error) {
+	listPrefix, err := a.key(p)
+	if err != nil {
+		return "", err
+	}
+	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
+		listPrefix += "/"
+	}
+	return listPrefix, nil
+}
+
+// listQuery creates a query with or without delimiter based on the recursive flag
+func (a *Adapter) listQuery(listPrefix string, recursive bool) *storage.Query {
+	query := &storage.Query{
+		Prefix: listPrefix,
+	}
+	if !recursive {
+		query.Delimiter = "/"
+	}
+	return query
+}
+
+// listEntry converts one listed object or prefix to a FileInfo. It returns
+// false for entries that are not part of the listing.
+func (a *Adapter) listEntry(listPrefix string, recursive bool, attrs *storage.ObjectAttrs) (filekit.FileInfo, bool) {
+	// Handle "directory" prefixes (only when not recursive)
+	if attrs.Prefix != "" {
+		dirName := strings.TrimPrefix(attrs.Prefix, listPrefix)
+		dirName = strings.TrimSuffix(dirName, "/")
+		if dirName == "" {
+			return filekit.FileInfo{}, false
 		}
 
-		files = append(files, filekit.FileInfo{
-			Name:              filepath.Base(strings.TrimSuffix(attrs.Name, "/")),
-			Path:              strings.TrimPrefix(attrs.Name, a.prefix),
-			Size:              attrs.Size,
-			ModTime:           attrs.Updated,
-			IsDir:             isDir,
-			ContentType:       attrs.ContentType,
-			Metadata:          attrs.Metadata,
-			ETag:              attrs.Etag,
-			Version:           strconv.FormatInt(attrs.Generation, 10),
-			StorageClass:      attrs.StorageClass,
-			Checksum:          checksum,
-			ChecksumAlgorithm: checksumAlgorithm,
-			CreatedAt:         createdAt,
-		})
+		return filekit.FileInfo{
+			Name:  filepath.Base(dirName),
+			Path:  strings.Trim(strings.TrimPrefix(attrs.Prefix, a.prefix), "/"),
+			IsDir: true,
+		}, true
 	}
 
-	return files, nil
+	// Skip the directory itself
+	if attrs.Name == listPrefix {
+		return filekit.FileInfo{}, false
+	}
+
+	// Get the file name relative to the prefix
+	relPath := strings.TrimPrefix(attrs.Name, listPrefix)
+	if relPath == "" {
+		return filekit.FileInfo{}, false
+	}
+
+	// For non-recursive, skip items with slashes (deeper nested items)
+	if !recursive && strings.Contains(relPath, "/") {
+		return filekit.FileInfo{}, false
+	}
+
+	// Paths never end in "/"; only markers are directories
+	isDir := filekit.IsDirMarker(attrs.Name, attrs.Size, attrs.ContentType)
+
+	// Determine checksum - prefer CRC32C (GCS native), fall back to MD5
+	var checksum string
+	var checksumAlgorithm filekit.ChecksumAlgorithm
+	if attrs.CRC32C != 0 {
+		checksum = fmt.Sprintf("%08x", attrs.CRC32C)
+		checksumAlgorithm = filekit.ChecksumCRC32C
+	} else if len(attrs.MD5) > 0 {
+		checksum = hex.EncodeToString(attrs.MD5)
+		checksumAlgorithm = filekit.ChecksumMD5
+	}
+
+	// Handle CreatedAt
+	var createdAt *time.Time
+	if !attrs.Created.IsZero() {
+		createdAt = &attrs.Created
+	}
+
+	return filekit.FileInfo{
+		Name:              filepath.Base(strings.TrimSuffix(attrs.Name, "/")),
+		Path:              strings.Trim(strings.TrimPrefix(attrs.Name, a.prefix), "/"),
+		Size:              attrs.Size,
+		ModTime:           attrs.Updated,
+		IsDir:             isDir,
+		ContentType:       attrs.ContentType,
+		Metadata:          attrs.Metadata,
+		ETag:              attrs.Etag,
+		Version:           strconv.FormatInt(attrs.Generation, 10),
+		StorageClass:      attrs.StorageClass,
+		Checksum:          checksum,
+		ChecksumAlgorithm: checksumAlgorithm,
+		CreatedAt:         createdAt,
+	}, true
 }
 
 // CreateDir implements filekit.FileSystem
 func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	// GCS doesn't have real directories, but we can create an empty object with a trailing slash
-	key := path.Join(a.prefix, dirPath)
+	key, err := a.key(dirPath)
+	if err != nil {
+		return filekit.WrapPathErr("createdir", dirPath, err)
+	}
 	if !strings.HasSuffix(key, "/") {
 		key += "/"
 	}
@@ -412,7 +634,7 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	obj := bkt.Object(key)
 
 	writer := obj.NewWriter(ctx)
-	writer.ContentType = "application/x-directory"
+	writer.ContentType = filekit.DirContentType
 
 	if err := writer.Close(); err != nil {
 		return mapGCSError("createdir", dirPath, err)
@@ -424,7 +646,10 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 // DeleteDir implements filekit.FileSystem
 func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 	// Prepare directory path
-	dirKey := path.Join(a.prefix, dirPath)
+	dirKey, err := a.key(dirPath)
+	if err != nil {
+		return filekit.WrapPathErr("deletedir", dirPath, err)
+	}
 	if !strings.HasSuffix(dirKey, "/") {
 		dirKey += "/"
 	}
@@ -468,10 +693,13 @@ func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath stri
 	}
 	defer file.Close()
 
-	// Detect content type if not provided
+	// Detect content type from the local file if not provided
 	opts := processOptions(options...)
-	if opts.ContentType == "" {
-		contentType := detectContentType(localPath)
+	if opts.ContentType == "" && !opts.ForceContentType {
+		contentType, _, err := filekit.PeekContentType(localPath, "", file)
+		if err != nil {
+			return nil, filekit.WrapPathErr("writefile", localPath, err)
+		}
 		options = append(options, filekit.WithContentType(contentType))
 	}
 
@@ -490,7 +718,10 @@ func (a *Adapter) SignedUploadURL(ctx context.Context, filePath string, expiry t
 
 // GenerateSignedGetURL generates a signed URL for downloading a file
 func (a *Adapter) GenerateSignedGetURL(ctx context.Context, filePath string, expiry time.Duration) (string, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("signed-get-url", filePath, err)
+	}
 
 	opts := &storage.SignedURLOptions{
 		Method:  "GET",
@@ -507,7 +738,10 @@ func (a *Adapter) GenerateSignedGetURL(ctx context.Context, filePath string, exp
 
 // GenerateSignedPutURL generates a signed URL for uploading a file
 func (a *Adapter) GenerateSignedPutURL(ctx context.Context, filePath string, expiry time.Duration, contentType string) (string, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("signed-put-url", filePath, err)
+	}
 
 	opts := &storage.SignedURLOptions{
 		Method:      "PUT",
@@ -532,64 +766,50 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 	return opts
 }
 
-// detectContentType determines the content type from file extension
-func detectContentType(filePath string) string {
-	ext := filepath.Ext(filePath)
-	if ext != "" {
-		// Read a bit of content to detect type
-		contentType := http.DetectContentType([]byte{})
-		if contentType != "application/octet-stream" {
-			return contentType
+// storageClasses are the GCS storage classes, including the legacy ones
+var storageClasses = []string{"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE", "MULTI_REGIONAL", "REGIONAL", "DURABLE_REDUCED_AVAILABILITY"}
+
+// storageClass resolves a WithStorageClass value, matched case-insensitively,
+// to a GCS storage class.
+func storageClass(filePath, class string) (string, error) {
+	if class == "" {
+		return "", nil
+	}
+	for _, known := range storageClasses {
+		if strings.EqualFold(known, class) {
+			return known, nil
 		}
 	}
+	return "", filekit.NewPathError("write", filePath, filekit.ErrCodeInvalidInput,
+		fmt.Sprintf("unknown GCS storage class %q", class))
+}
 
-	// Common extension mappings
-	switch strings.ToLower(ext) {
-	case ".html", ".htm":
-		return "text/html"
-	case ".css":
-		return "text/css"
-	case ".js":
-		return "application/javascript"
-	case ".json":
-		return "application/json"
-	case ".xml":
-		return "application/xml"
-	case ".pdf":
-		return "application/pdf"
-	case ".zip":
-		return "application/zip"
-	case ".gz", ".gzip":
-		return "application/gzip"
-	case ".tar":
-		return "application/x-tar"
-	case ".png":
-		return "image/png"
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".gif":
-		return "image/gif"
-	case ".webp":
-		return "image/webp"
-	case ".svg":
-		return "image/svg+xml"
-	case ".ico":
-		return "image/x-icon"
-	case ".mp3":
-		return "audio/mpeg"
-	case ".mp4":
-		return "video/mp4"
-	case ".webm":
-		return "video/webm"
-	case ".txt":
-		return "text/plain"
-	case ".csv":
-		return "text/csv"
-	case ".md":
-		return "text/markdown"
-	default:
-		return "application/octet-stream"
+// conditionalObject returns obj with the preconditions of a conditional
+// write. GCS conditions are generation based: "*" for WithIfNoneMatch maps to
+// DoesNotExist, and ETag conditions are checked against the current object
+// and then pinned to its generation, so a write that lands in between fails
+// with a precondition error instead of being overwritten.
+func (a *Adapter) conditionalObject(ctx context.Context, obj *storage.ObjectHandle, filePath string, opts *filekit.Options) (*storage.ObjectHandle, error) {
+	if opts.IfMatch == "" && opts.IfNoneMatch == "*" {
+		return obj.If(storage.Conditions{DoesNotExist: true}), nil
 	}
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, mapGCSError("write", filePath, err)
+	}
+	exists := err == nil
+	var etag string
+	if exists {
+		etag = attrs.Etag
+	}
+	if err := filekit.CheckPreconditions("write", filePath, opts, exists, etag); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return obj.If(storage.Conditions{DoesNotExist: true}), nil
+	}
+	return obj.If(storage.Conditions{GenerationMatch: attrs.Generation}), nil
 }
 
 // mapGCSError maps GCS errors to filekit errors
@@ -598,6 +818,21 @@ func mapGCSError(op, path string, err error) error {
 		return filekit.WrapPathErr(op, path, filekit.ErrNotExist)
 	}
 
+	// Failed conditional writes
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return filekit.WrapPath(filekit.ErrPreconditionFailed, op, path, filekit.ErrCodePreconditionFailed, apiErr.Message)
+	}
+
+	// Project or bucket quotas are reported by reason, usually with a 403 or 429
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if item.Reason == "quotaExceeded" || item.Reason == "storageQuotaExceeded" {
+				return filekit.WrapPath(filekit.ErrQuotaExceeded, op, path, filekit.ErrCodeQuotaExceeded, apiErr.Message)
+			}
+		}
+	}
+
 	if errors.Is(err, storage.ErrBucketNotExist) {
 		return filekit.WrapPathErr(op, path, filekit.ErrNotExist)
 	}
@@ -609,16 +844,39 @@ func mapGCSError(op, path string, err error) error {
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker by listing at most one object in the
+// bucket.
+func (a *Adapter) Ping(ctx context.Context) error {
+	it := a.client.Bucket(a.bucket).Objects(ctx, &storage.Query{Prefix: a.prefix})
+	it.PageInfo().MaxSize = 1
+	if _, err := it.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return mapGCSError("ping", "", err)
+	}
+	return nil
+}
+
 // Copy implements filekit.CanCopy using GCS's native CopierFrom.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
-	srcKey := path.Join(a.prefix, src)
-	dstKey := path.Join(a.prefix, dst)
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("copy", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("copy", dst, err)
+	}
+
+	// Copying an object onto itself would only rewrite it
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
 
 	srcObj := a.client.Bucket(a.bucket).Object(srcKey)
 	dstObj := a.client.Bucket(a.bucket).Object(dstKey)
 
 	// Use GCS native copy
-	_, err := dstObj.CopierFrom(srcObj).Run(ctx)
+	_, err = dstObj.CopierFrom(srcObj).Run(ctx)
 	if err != nil {
 		return mapGCSError("copy", src, err)
 	}
@@ -626,15 +884,43 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	return nil
 }
 
-// Move implements filekit.CanMove using GCS's copy + delete.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+// Move implements filekit.CanMove using GCS's copy + delete. Moving an object
+// onto itself only checks that it exists. With filekit.WithMoveNoOverwrite the
+// copy carries a DoesNotExist precondition, so an existing dst is never
+// replaced.
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("move", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("move", dst, err)
+	}
+
+	// Deleting the source would delete the destination
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
+
 	// Copy the object
-	if err := a.Copy(ctx, src, dst); err != nil {
+	if filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		srcObj := a.client.Bucket(a.bucket).Object(srcKey)
+		dstObj := a.client.Bucket(a.bucket).Object(dstKey)
+		_, err := dstObj.If(storage.Conditions{DoesNotExist: true}).CopierFrom(srcObj).Run(ctx)
+		if err != nil {
+			err = mapGCSError("move", src, err)
+			if filekit.IsCode(err, filekit.ErrCodePreconditionFailed) {
+				return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+			}
+			return err
+		}
+	} else if err := a.Copy(ctx, src, dst); err != nil {
 		return err
 	}
 
 	// Delete the source
-	srcKey := path.Join(a.prefix, src)
 	if err := a.client.Bucket(a.bucket).Object(srcKey).Delete(ctx); err != nil {
 		return mapGCSError("move", src, err)
 	}
@@ -642,6 +928,59 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 	return nil
 }
 
+// SetTags implements filekit.CanTag using the object's custom metadata.
+// GCS has no separate tagging API, so tags share the namespace of metadata
+// set with filekit.WithMetadata. The existing metadata is replaced.
+func (a *Adapter) SetTags(ctx context.Context, filePath string, tags map[string]string) error {
+	key, err := a.key(filePath)
+	if err != nil {
+		return filekit.WrapPathErr("set-tags", filePath, err)
+	}
+	obj := a.client.Bucket(a.bucket).Object(key)
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return mapGCSError("set-tags", filePath, err)
+	}
+
+	// Keys missing from tags are removed by setting them to the empty string;
+	// a non-nil empty map clears all metadata.
+	metadata := make(map[string]string, len(tags)+len(attrs.Metadata))
+	for k := range attrs.Metadata {
+		metadata[k] = ""
+	}
+	for k, v := range tags {
+		metadata[k] = v
+	}
+
+	_, err = obj.If(storage.Conditions{MetagenerationMatch: attrs.Metageneration}).
+		Update(ctx, storage.ObjectAttrsToUpdate{Metadata: metadata})
+	if err != nil {
+		return mapGCSError("set-tags", filePath, err)
+	}
+
+	return nil
+}
+
+// GetTags implements filekit.CanTag by returning the object's custom metadata.
+func (a *Adapter) GetTags(ctx context.Context, filePath string) (map[string]string, error) {
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("get-tags", filePath, err)
+	}
+	attrs, err := a.client.Bucket(a.bucket).Object(key).Attrs(ctx)
+	if err != nil {
+		return nil, mapGCSError("get-tags", filePath, err)
+	}
+
+	tags := make(map[string]string, len(attrs.Metadata))
+	for k, v := range attrs.Metadata {
+		tags[k] = v
+	}
+
+	return tags, nil
+}
+
 // GenerateDownloadURL implements filekit.CanSignURL.
 func (a *Adapter) GenerateDownloadURL(ctx context.Context, filePath string, expires time.Duration) (string, error) {
 	return a.GenerateSignedGetURL(ctx, filePath, expires)
@@ -693,8 +1032,14 @@ func (a *Adapter) Checksums(ctx context.Context, filePath string, algorithms []f
 // The filter pattern supports glob patterns like "**/*.json", "config/*".
 // Default polling interval is 30 seconds.
 func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken, error) {
+	return a.WatchMany(ctx, filter)
+}
+
+// WatchMany implements filekit.CanWatchMany. All filters share one poller,
+// so each poll lists the objects once however many filters there are.
+func (a *Adapter) WatchMany(ctx context.Context, filters ...string) (filekit.ChangeToken, error) {
 	// Get initial state of matching files
-	initialState, err := a.getMatchingFilesState(ctx, filter)
+	initialState, err := a.getMatchingFilesState(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -703,7 +1048,7 @@ func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken
 	token := filekit.NewPollingChangeToken(ctx, filekit.PollingConfig{
 		Interval: 30 * time.Second,
 		CheckFunc: func() bool {
-			currentState, err := a.getMatchingFilesState(ctx, filter)
+			currentState, err := a.getMatchingFilesState(ctx, filters)
 			if err != nil {
 				return false // Can't determine change, don't signal
 			}
@@ -721,8 +1066,8 @@ type gcsFileState struct {
 	size    int64
 }
 
-// getMatchingFilesState returns the current state of files matching the filter
-func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map[string]gcsFileState, error) {
+// getMatchingFilesState returns the current state of files matching any of the filters
+func (a *Adapter) getMatchingFilesState(ctx context.Context, filters []string) (map[string]gcsFileState, error) {
 	state := make(map[string]gcsFileState)
 
 	// List all objects with the prefix
@@ -736,14 +1081,14 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 			break
 		}
 		if err != nil {
-			return nil, filekit.WrapPathErr("watch", filter, err)
+			return nil, filekit.WrapPathErr("watch", strings.Join(filters, ","), err)
 		}
 
 		// Remove prefix from key to get relative path
 		relPath := strings.TrimPrefix(attrs.Name, a.prefix)
 
-		// Check if path matches filter
-		if gcsMatchesGlobFilter(relPath, filter) {
+		// Check if path matches any filter
+		if gcsMatchesAnyGlobFilter(relPath, filters) {
 			state[relPath] = gcsFileState{
 				path:    relPath,
 				modTime: attrs.Updated,
@@ -772,6 +1117,16 @@ func gcsStatesEqual(a, b map[string]gcsFileState) bool {
 	return true
 }
 
+// gcsMatchesAnyGlobFilter checks if a path matches at least one of the glob patterns
+func gcsMatchesAnyGlobFilter(path string, filters []string) bool {
+	for _, filter := range filters {
+		if gcsMatchesGlobFilter(path, filter) {
+			return true
+		}
+	}
+	return false
+}
+
 // gcsMatchesGlobFilter checks if a path matches a glob pattern
 func gcsMatchesGlobFilter(filePath, filter string) bool {
 	// Handle ** patterns for recursive matching
@@ -824,6 +1179,10 @@ func generateGCSUploadID() (string, error) {
 // InitiateUpload starts a chunked upload process and returns an upload ID.
 // Parts are stored as temporary objects in GCS until CompleteUpload is called.
 func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string, error) {
+	if _, err := a.key(filePath); err != nil {
+		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
+	}
+
 	// Generate a unique upload ID
 	uploadID, err := generateGCSUploadID()
 	if err != nil {
@@ -845,8 +1204,13 @@ func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string,
 	return uploadID, nil
 }
 
+// partChecksumKey is the part object metadata key holding the part's SHA-256
+const partChecksumKey = "filekit-part-sha256"
+
 // UploadPart uploads a part of a file in a chunked upload process.
-// Parts are stored as numbered objects (1, 2, 3, ...) in GCS.
+// Parts are stored as numbered objects (1, 2, 3, ...) in GCS. Each part is
+// sent with its CRC32C, so GCS rejects parts corrupted in transit, and its
+// SHA-256 is recorded in the part's metadata for CompleteUploadWithChecksum.
 func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
 	// Validate part number
 	if partNumber < 1 {
@@ -866,6 +1230,9 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 	partKey := fmt.Sprintf("%s%d", info.partsPrefix, partNumber)
 	obj := a.client.Bucket(a.bucket).Object(partKey)
 	writer := obj.NewWriter(ctx)
+	writer.CRC32C = crc32.Checksum(data, crc32cTable)
+	writer.SendCRC32C = true
+	writer.Metadata = map[string]string{partChecksumKey: filekit.PartChecksum(data)}
 
 	if _, err := writer.Write(data); err != nil {
 		writer.Close()
@@ -883,6 +1250,20 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 // GCS supports composing up to 32 objects at a time, so for more parts
 // we do iterative composition.
 func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
+	return a.completeUpload(ctx, uploadID, "", false)
+}
+
+// CompleteUploadWithChecksum implements filekit.ChecksumUploader. Compose
+// runs server-side, so the parts are first read back and each is checked
+// against the SHA-256 recorded by UploadPart and the whole against expected.
+// On a mismatch the parts are deleted and nothing is composed.
+func (a *Adapter) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	return a.completeUpload(ctx, uploadID, expected, true)
+}
+
+// completeUpload composes the parts of an upload into its target object,
+// verifying them first if verify is set.
+func (a *Adapter) completeUpload(ctx context.Context, uploadID, expected string, verify bool) error {
 	// Get and remove upload info
 	gcsUploadRegistry.Lock()
 	info, ok := gcsUploadRegistry.uploads[uploadID]
@@ -901,6 +1282,7 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 	it := bkt.Objects(ctx, query)
 
 	var partKeys []string
+	partSums := make(map[string]string)
 	for {
 		attrs, err := it.Next()
 		if errors.Is(err, iterator.Done) {
@@ -910,6 +1292,7 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 			return filekit.WrapPathErr("complete-upload", uploadID, err)
 		}
 		partKeys = append(partKeys, attrs.Name)
+		partSums[attrs.Name] = attrs.Metadata[partChecksumKey]
 	}
 
 	if len(partKeys) == 0 {
@@ -924,8 +1307,18 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 		return numI < numJ
 	})
 
+	if verify {
+		if err := a.verifyParts(ctx, bkt, info, partKeys, partSums, expected); err != nil {
+			a.cleanupGCSParts(ctx, bkt, partKeys)
+			return err
+		}
+	}
+
 	// Target object
-	targetKey := path.Join(a.prefix, info.path)
+	targetKey, err := a.key(info.path)
+	if err != nil {
+		return filekit.WrapPathErr("complete-upload", info.path, err)
+	}
 	targetObj := bkt.Object(targetKey)
 
 	// GCS Compose supports up to 32 sources at a time
@@ -956,6 +1349,31 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 	return nil
 }
 
+// verifyParts reads the parts in order, checking each against its recorded
+// SHA-256 and their concatenation against expected.
+func (a *Adapter) verifyParts(ctx context.Context, bkt *storage.BucketHandle, info *gcsUploadInfo, partKeys []string, partSums map[string]string, expected string) error {
+	whole := sha256.New()
+	for _, key := range partKeys {
+		reader, err := bkt.Object(key).NewReader(ctx)
+		if err != nil {
+			return mapGCSError("complete-upload", info.path, err)
+		}
+		part := sha256.New()
+		_, err = io.Copy(io.MultiWriter(whole, part), reader)
+		reader.Close()
+		if err != nil {
+			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to read part: %w", err))
+		}
+
+		// Parts uploaded without a recorded checksum are not verified
+		partNumber := extractPartNumber(key, info.partsPrefix)
+		if err := filekit.VerifyUploadChecksum(info.path, partNumber, partSums[key], part.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	return filekit.VerifyUploadChecksum(info.path, 0, expected, whole.Sum(nil))
+}
+
 // composeIteratively handles composition when there are more than 32 parts
 func (a *Adapter) composeIteratively(ctx context.Context, bkt *storage.BucketHandle, partKeys []string, targetKey string) error {
 	const maxCompose = 32
@@ -1072,15 +1490,82 @@ func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
 	return nil
 }
 
+// GarbageCollectUploads deletes the temporary part objects of chunked uploads
+// that were never completed or aborted and whose newest part is older than
+// olderThan. It returns the number of uploads reclaimed.
+func (a *Adapter) GarbageCollectUploads(ctx context.Context, olderThan time.Duration) (reclaimed int, err error) {
+	cutoff := time.Now().Add(-olderThan)
+	uploadsPrefix := path.Join(a.prefix, ".filekit-uploads") + "/"
+
+	// Group part objects by upload ID
+	bkt := a.client.Bucket(a.bucket)
+	it := bkt.Objects(ctx, &storage.Query{Prefix: uploadsPrefix})
+	parts := make(map[string][]string)
+	newest := make(map[string]time.Time)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return 0, mapGCSError("gc-uploads", uploadsPrefix, err)
+		}
+		uploadID, _, ok := strings.Cut(strings.TrimPrefix(attrs.Name, uploadsPrefix), "/")
+		if !ok {
+			continue
+		}
+		parts[uploadID] = append(parts[uploadID], attrs.Name)
+		if attrs.Updated.After(newest[uploadID]) {
+			newest[uploadID] = attrs.Updated
+		}
+	}
+
+	errs := filekit.NewMultiError("gc-uploads")
+	for uploadID, names := range parts {
+		if !newest[uploadID].Before(cutoff) {
+			continue
+		}
+		if err := filekit.FromContext(ctx, "gc-uploads", uploadID); err != nil {
+			return reclaimed, err
+		}
+
+		var failed bool
+		for _, name := range names {
+			if err := bkt.Object(name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				errs.Add(mapGCSError("gc-uploads", name, err))
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+
+		gcsUploadRegistry.Lock()
+		delete(gcsUploadRegistry.uploads, uploadID)
+		gcsUploadRegistry.Unlock()
+		reclaimed++
+	}
+
+	return reclaimed, errs.Err()
+}
+
 // Ensure Adapter implements required and optional interfaces
 var (
-	_ filekit.FileSystem      = (*Adapter)(nil)
-	_ filekit.FileReader      = (*Adapter)(nil)
-	_ filekit.FileWriter      = (*Adapter)(nil)
-	_ filekit.CanCopy         = (*Adapter)(nil)
-	_ filekit.CanMove         = (*Adapter)(nil)
-	_ filekit.CanSignURL      = (*Adapter)(nil)
-	_ filekit.CanChecksum     = (*Adapter)(nil)
-	_ filekit.CanWatch        = (*Adapter)(nil)
-	_ filekit.ChunkedUploader = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanSignURL        = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanWatchMany      = (*Adapter)(nil)
+	_ filekit.CanStatMany       = (*Adapter)(nil)
+	_ filekit.CanDeleteMany     = (*Adapter)(nil)
+	_ filekit.CanTag            = (*Adapter)(nil)
+	_ filekit.CanListPage       = (*Adapter)(nil)
+	_ filekit.ChunkedUploader   = (*Adapter)(nil)
+	_ filekit.ChecksumUploader  = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
@@ -0,0 +1,1365 @@
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/fstest"
+	"google.golang.org/api/option"
+)
+
+// newMetadataServer returns an adapter backed by a fake GCS JSON API that
+// serves object attributes and applies metadata patches.
+func newMetadataServer(t *testing.T, objects map[string]map[string]string) *Adapter {
+	t.Helper()
+
+	var mu sync.Mutex
+	metagen := make(map[string]int64)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/storage/v1/b/bucket/o/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		metadata, ok := objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"No such object"}}`))
+			return
+		}
+
+		if r.Method == http.MethodPatch {
+			var patch struct {
+				Metadata map[string]*string `json:"metadata"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			for k, v := range patch.Metadata {
+				if v == nil || *v == "" {
+					delete(metadata, k)
+				} else {
+					metadata[k] = *v
+				}
+			}
+			metagen[name]++
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"bucket":         "bucket",
+			"name":           name,
+			"metageneration": strconv.FormatInt(metagen[name]+1, 10),
+			"metadata":       metadata,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, "bucket", WithPrefix("data"))
+}
+
+func TestTags_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMetadataServer(t, map[string]map[string]string{
+		"data/reports/q1.pdf": {"owner": "alice"},
+	})
+
+	tags := map[string]string{"retention": "7y", "team": "finance"}
+	if err := adapter.SetTags(ctx, "reports/q1.pdf", tags); err != nil {
+		t.Fatalf("SetTags failed: %v", err)
+	}
+
+	got, err := adapter.GetTags(ctx, "reports/q1.pdf")
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(got) != len(tags) {
+		t.Fatalf("got %v, want %v", got, tags)
+	}
+	for k, v := range tags {
+		if got[k] != v {
+			t.Errorf("tag %q = %q, want %q", k, got[k], v)
+		}
+	}
+
+	// An empty map removes all tags
+	if err := adapter.SetTags(ctx, "reports/q1.pdf", map[string]string{}); err != nil {
+		t.Fatalf("SetTags(empty) failed: %v", err)
+	}
+	got, err = adapter.GetTags(ctx, "reports/q1.pdf")
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no tags, got %v", got)
+	}
+}
+
+func TestTags_NotFound(t *testing.T) {
+	ctx := context.Background()
+	adapter := newMetadataServer(t, map[string]map[string]string{})
+
+	if _, err := adapter.GetTags(ctx, "missing.txt"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("GetTags: expected not found error, got %v", err)
+	}
+	if err := adapter.SetTags(ctx, "missing.txt", map[string]string{"a": "b"}); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("SetTags: expected not found error, got %v", err)
+	}
+}
+
+// uploadRecorder is a fake GCS upload endpoint. It records the object metadata
+// sent with each multipart upload and the names of deleted objects.
+type uploadRecorder struct {
+	mu       sync.Mutex
+	metadata []map[string]any
+	deleted  []string
+
+	// corrupt makes the server store different bytes than it received
+	corrupt bool
+}
+
+func newUploadServer(t *testing.T, rec *uploadRecorder) *Adapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+
+		if r.Method == http.MethodDelete {
+			rec.deleted = append(rec.deleted, strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Query().Get("uploadType") != "multipart" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		var meta map[string]any
+		part, err := reader.NextPart()
+		if err == nil {
+			err = json.NewDecoder(part).Decode(&meta)
+		}
+		if err != nil {
+			http.Error(w, "invalid metadata part: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		rec.metadata = append(rec.metadata, meta)
+
+		part, err = reader.NextPart()
+		if err != nil {
+			http.Error(w, "missing media part", http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(part)
+		if rec.corrupt {
+			data = append(data, '!')
+		}
+
+		sum := make([]byte, 4)
+		binary.BigEndian.PutUint32(sum, crc32.Checksum(data, crc32cTable))
+		if sent, ok := meta["crc32c"].(string); ok && sent != base64.StdEncoding.EncodeToString(sum) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Provided CRC32C doesn't match calculated CRC32C"}}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"bucket":      "bucket",
+			"name":        meta["name"],
+			"size":        strconv.Itoa(len(data)),
+			"crc32c":      base64.StdEncoding.EncodeToString(sum),
+			"contentType": meta["contentType"],
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, "bucket", WithPrefix("data"))
+}
+
+func TestWrite_SendsCRC32C(t *testing.T) {
+	rec := &uploadRecorder{}
+	adapter := newUploadServer(t, rec)
+
+	content := []byte("hello, integrity")
+	result, err := adapter.Write(context.Background(), "docs/a.txt", bytes.NewReader(content), filekit.WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if result.BytesWritten != int64(len(content)) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len(content))
+	}
+
+	if len(rec.metadata) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(rec.metadata))
+	}
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, crc32.Checksum(content, crc32cTable))
+	if got, want := rec.metadata[0]["crc32c"], base64.StdEncoding.EncodeToString(sum); got != want {
+		t.Errorf("upload crc32c = %v, want %s", got, want)
+	}
+}
+
+func TestWrite_ReportsContentType(t *testing.T) {
+	rec := &uploadRecorder{}
+	adapter := newUploadServer(t, rec)
+
+	result, err := adapter.Write(context.Background(), "images/logo.png", strings.NewReader("png bytes"), filekit.WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if result.ContentType != "image/png" {
+		t.Errorf("ContentType = %q, want image/png", result.ContentType)
+	}
+	if got := rec.metadata[0]["contentType"]; got != "image/png" {
+		t.Errorf("uploaded contentType = %v, want image/png", got)
+	}
+}
+
+func TestWrite_StreamingCRC32CMismatch(t *testing.T) {
+	rec := &uploadRecorder{corrupt: true}
+	adapter := newUploadServer(t, rec)
+
+	// A non-seekable reader cannot be hashed up front, so the checksum is
+	// verified against the stored object after the upload
+	content := io.MultiReader(strings.NewReader("streamed "), strings.NewReader("content"))
+	_, err := adapter.Write(context.Background(), "docs/b.txt", content, filekit.WithOverwrite(true))
+	if !filekit.IsCode(err, filekit.ErrCodeIntegrity) {
+		t.Fatalf("Write error = %v, want ErrCodeIntegrity", err)
+	}
+	if _, sent := rec.metadata[0]["crc32c"]; sent {
+		t.Error("crc32c must not be sent for non-seekable content")
+	}
+	if len(rec.deleted) != 1 || rec.deleted[0] != "data/docs/b.txt" {
+		t.Errorf("deleted = %v, want the corrupted object removed", rec.deleted)
+	}
+}
+
+func TestWrite_SeekableCRC32CRejected(t *testing.T) {
+	rec := &uploadRecorder{corrupt: true}
+	adapter := newUploadServer(t, rec)
+
+	// The server rejects the upload because the sent checksum does not match
+	_, err := adapter.Write(context.Background(), "docs/c.txt", strings.NewReader("seekable"), filekit.WithOverwrite(true))
+	if err == nil {
+		t.Fatal("Write succeeded despite checksum mismatch")
+	}
+	if len(rec.deleted) != 0 {
+		t.Errorf("deleted = %v, rejected uploads leave nothing to delete", rec.deleted)
+	}
+}
+
+// newListingServer returns an adapter backed by a fake GCS JSON API that
+// lists a fixed, sorted set of objects. Page tokens are offsets.
+func newListingServer(t *testing.T, names []string) *Adapter {
+	t.Helper()
+	return newObjectListingServer(t, names, nil)
+}
+
+// listedObject overrides the size and content type of a listed object
+type listedObject struct {
+	size        int
+	contentType string
+}
+
+// newObjectListingServer is newListingServer with some objects described;
+// other objects are 1 byte with no content type.
+func newObjectListingServer(t *testing.T, names []string, objects map[string]listedObject) *Adapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/bucket/o" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+		maxResults, _ := strconv.Atoi(q.Get("maxResults"))
+		offset, _ := strconv.Atoi(q.Get("pageToken"))
+
+		// Collapse names into the entries GCS would return, in order
+		type entry struct{ name, prefix string }
+		var entries []entry
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if delimiter != "" {
+				if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+					p := name[:len(prefix)+i+1]
+					if !seen[p] {
+						seen[p] = true
+						entries = append(entries, entry{prefix: p})
+					}
+					continue
+				}
+			}
+			entries = append(entries, entry{name: name})
+		}
+
+		end := len(entries)
+		if maxResults > 0 && offset+maxResults < end {
+			end = offset + maxResults
+		}
+		resp := map[string]any{}
+		var items []map[string]any
+		var prefixes []string
+		for _, e := range entries[offset:end] {
+			if e.prefix != "" {
+				prefixes = append(prefixes, e.prefix)
+			} else {
+				obj, ok := objects[e.name]
+				if !ok {
+					obj.size = 1
+				}
+				items = append(items, map[string]any{
+					"bucket":      "bucket",
+					"name":        e.name,
+					"size":        strconv.Itoa(obj.size),
+					"contentType": obj.contentType,
+				})
+			}
+		}
+		resp["items"] = items
+		resp["prefixes"] = prefixes
+		if end < len(entries) {
+			resp["nextPageToken"] = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, "bucket")
+}
+
+func TestListPage(t *testing.T) {
+	var names []string
+	for i := 0; i < 7; i++ {
+		names = append(names, "docs/"+strconv.Itoa(i)+".txt")
+	}
+	names = append(names, "docs/sub/a.txt", "docs/sub/b.txt")
+	adapter := newListingServer(t, names)
+
+	collect := func(t *testing.T, recursive bool) ([]string, int) {
+		t.Helper()
+		var paths []string
+		pages := 0
+		opts := filekit.ListPageOptions{MaxResults: 3, Recursive: recursive}
+		for {
+			page, err := adapter.ListPage(context.Background(), "docs", opts)
+			if err != nil {
+				t.Fatalf("ListPage: %v", err)
+			}
+			if len(page.Entries) > 3 {
+				t.Fatalf("page has %d entries, want at most 3", len(page.Entries))
+			}
+			pages++
+			for _, e := range page.Entries {
+				paths = append(paths, e.Path)
+			}
+			if page.NextToken == "" {
+				return paths, pages
+			}
+			opts.ContinuationToken = page.NextToken
+		}
+	}
+
+	t.Run("non-recursive", func(t *testing.T) {
+		paths, pages := collect(t, false)
+		want := "docs/0.txt docs/1.txt docs/2.txt docs/3.txt docs/4.txt docs/5.txt docs/6.txt docs/sub"
+		if got := strings.Join(paths, " "); got != want {
+			t.Errorf("paths = %s, want %s", got, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		paths, pages := collect(t, true)
+		if got, want := strings.Join(paths, " "), strings.Join(names, " "); got != want {
+			t.Errorf("paths = %s, want %s", got, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+}
+
+func TestListContents_DirectoryMarkers(t *testing.T) {
+	names := []string{
+		"docs/",       // marker of the listed directory
+		"docs/a.txt",  // empty file
+		"docs/empty/", // marker of an empty directory
+		"docs/odd/",   // a file whose name ends in "/"
+		"docs/stub",   // a marker without a trailing "/"
+		"docs/sub/",   // marker of a directory with files
+		"docs/sub/b.txt",
+	}
+	adapter := newObjectListingServer(t, names, map[string]listedObject{
+		"docs/":       {contentType: filekit.DirContentType},
+		"docs/a.txt":  {contentType: "text/plain"},
+		"docs/empty/": {contentType: filekit.DirContentType},
+		"docs/odd/":   {size: 5, contentType: "text/plain"},
+		"docs/stub":   {contentType: filekit.DirContentType},
+		"docs/sub/":   {},
+	})
+
+	classify := func(t *testing.T, recursive bool) map[string]bool {
+		t.Helper()
+		entries, err := adapter.ListContents(context.Background(), "docs", recursive)
+		if err != nil {
+			t.Fatalf("ListContents: %v", err)
+		}
+		got := make(map[string]bool)
+		for _, e := range entries {
+			if strings.HasSuffix(e.Path, "/") {
+				t.Errorf("path %q ends in /", e.Path)
+			}
+			got[e.Path] = e.IsDir
+		}
+		return got
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		want := map[string]bool{
+			"docs/a.txt":     false,
+			"docs/empty":     true,
+			"docs/odd":       false,
+			"docs/stub":      true,
+			"docs/sub":       true,
+			"docs/sub/b.txt": false,
+		}
+		if got := classify(t, true); !maps.Equal(got, want) {
+			t.Errorf("IsDir by path = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("non-recursive", func(t *testing.T) {
+		// Delimited listings roll every name ending in "/" into a prefix
+		want := map[string]bool{
+			"docs/a.txt": false,
+			"docs/empty": true,
+			"docs/odd":   true,
+			"docs/stub":  true,
+			"docs/sub":   true,
+		}
+		if got := classify(t, false); !maps.Equal(got, want) {
+			t.Errorf("IsDir by path = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("tree stats", func(t *testing.T) {
+		stats, err := filekit.TreeStats(context.Background(), adapter, "docs")
+		if err != nil {
+			t.Fatalf("TreeStats: %v", err)
+		}
+		if stats.Files != 3 {
+			t.Errorf("Files = %d, want 3 (markers are not files)", stats.Files)
+		}
+	})
+}
+
+func TestStat_ReportsETagAndChecksum(t *testing.T) {
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, 0xdeadbeef)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/bucket/o/data/report.pdf" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"bucket":  "bucket",
+			"name":    "data/report.pdf",
+			"size":    "4",
+			"etag":    "CJj4tM2N/YIDEAE=",
+			"md5Hash": base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")),
+			"crc32c":  base64.StdEncoding.EncodeToString(crc),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+
+	info, err := adapter.Stat(context.Background(), "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ETag != "CJj4tM2N/YIDEAE=" {
+		t.Errorf("ETag = %q", info.ETag)
+	}
+	if info.Checksum != "deadbeef" || info.ChecksumAlgorithm != filekit.ChecksumCRC32C {
+		t.Errorf("Checksum = %q (%s), want CRC32C deadbeef", info.Checksum, info.ChecksumAlgorithm)
+	}
+}
+
+func TestWrite_Conditional(t *testing.T) {
+	var mu sync.Mutex
+	generation := 1 // 0 means the object does not exist
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attrs := func(data []byte) map[string]any {
+			sum := make([]byte, 4)
+			binary.BigEndian.PutUint32(sum, crc32.Checksum(data, crc32cTable))
+			return map[string]any{
+				"bucket":     "bucket",
+				"name":       "data/config.json",
+				"generation": strconv.Itoa(generation),
+				"etag":       "etag-" + strconv.Itoa(generation),
+				"crc32c":     base64.StdEncoding.EncodeToString(sum),
+			}
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/bucket/o/data/config.json":
+			if generation == 0 {
+				http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(attrs(nil))
+		case r.Method == http.MethodPost && r.URL.Query().Get("uploadType") == "multipart":
+			q := r.URL.Query()
+			if want := q.Get("ifGenerationMatch"); want != "" && want != strconv.Itoa(generation) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"error":{"code":412,"message":"At least one of the pre-conditions you specified did not hold."}}`))
+				return
+			}
+			_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			reader := multipart.NewReader(r.Body, params["boundary"])
+			_, _ = reader.NextPart()
+			part, err := reader.NextPart()
+			if err != nil {
+				http.Error(w, "missing media part", http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(part)
+			generation++
+			_ = json.NewEncoder(w).Encode(attrs(data))
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	t.Run("one of two writers with the same base ETag wins", func(t *testing.T) {
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = adapter.Write(ctx, "config.json", strings.NewReader("update"), filekit.WithIfMatch("etag-1"))
+			}()
+		}
+		wg.Wait()
+
+		succeeded, failed := 0, 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, filekit.ErrPreconditionFailed) && filekit.IsCode(err, filekit.ErrCodePreconditionFailed):
+				failed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 1 || failed != 1 {
+			t.Errorf("got %d successes and %d precondition failures, want 1 and 1", succeeded, failed)
+		}
+	})
+
+	t.Run("create only", func(t *testing.T) {
+		_, err := adapter.Write(ctx, "config.json", strings.NewReader("new"), filekit.WithIfNoneMatch("*"))
+		if !errors.Is(err, filekit.ErrPreconditionFailed) {
+			t.Errorf("Write with IfNoneMatch(*) = %v, want ErrPreconditionFailed", err)
+		}
+	})
+}
+
+func TestMove_DestinationParentExists(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]bool{"data/report.txt": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/b/bucket/o")
+		switch {
+		case r.Method == http.MethodPost && strings.Contains(escaped, "/rewriteTo/b/bucket/o/"):
+			src, dst, _ := strings.Cut(strings.TrimPrefix(escaped, "/"), "/rewriteTo/b/bucket/o/")
+			src, _ = url.PathUnescape(src)
+			dst, _ = url.PathUnescape(dst)
+			if !objects[src] {
+				http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+				return
+			}
+			objects[dst] = true
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"done":     true,
+				"resource": map[string]any{"bucket": "bucket", "name": dst, "size": "4"},
+			})
+		case r.Method == http.MethodDelete:
+			name, _ := url.PathUnescape(strings.TrimPrefix(escaped, "/"))
+			delete(objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && escaped == "":
+			var items []map[string]any
+			for name := range objects {
+				if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+					items = append(items, map[string]any{"bucket": "bucket", "name": name, "size": "4"})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+		case r.Method == http.MethodGet:
+			// Directory markers are never stored
+			http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "archive/2024/report.txt"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	for _, dir := range []string{"archive", "archive/2024"} {
+		if exists, err := adapter.DirExists(ctx, dir); err != nil || !exists {
+			t.Errorf("DirExists(%s) after move = %v, %v; want true", dir, exists, err)
+		}
+	}
+	if objects["data/report.txt"] || !objects["data/archive/2024/report.txt"] {
+		t.Errorf("objects after move = %v", objects)
+	}
+}
+
+func TestMove_NoOverwrite(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]bool{"data/a.txt": true, "data/b.txt": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/b/bucket/o")
+		switch {
+		case r.Method == http.MethodPost && strings.Contains(escaped, "/rewriteTo/b/bucket/o/"):
+			src, dst, _ := strings.Cut(strings.TrimPrefix(escaped, "/"), "/rewriteTo/b/bucket/o/")
+			src, _ = url.PathUnescape(src)
+			dst, _ = url.PathUnescape(dst)
+			if r.URL.Query().Get("ifGenerationMatch") == "0" && objects[dst] {
+				http.Error(w, `{"error":{"code":412,"message":"Precondition Failed"}}`, http.StatusPreconditionFailed)
+				return
+			}
+			objects[dst] = true
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"done":     true,
+				"resource": map[string]any{"bucket": "bucket", "name": dst, "size": "4"},
+			})
+		case r.Method == http.MethodDelete:
+			name, _ := url.PathUnescape(strings.TrimPrefix(escaped, "/"))
+			delete(objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+		t.Fatalf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if !objects["data/a.txt"] {
+		t.Error("refused move deleted the source")
+	}
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Move onto existing: %v", err)
+	}
+	if objects["data/a.txt"] || !objects["data/b.txt"] {
+		t.Errorf("objects after move = %v", objects)
+	}
+}
+
+func TestMove_SamePathKeepsObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/b/bucket/o/")
+		switch {
+		case r.Method == http.MethodGet && escaped == "data%2Freport.txt":
+			_ = json.NewEncoder(w).Encode(map[string]any{"bucket": "bucket", "name": "data/report.txt", "size": "4"})
+		case r.Method == http.MethodGet:
+			http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "./report.txt"); err != nil {
+		t.Errorf("Move onto itself: %v", err)
+	}
+	if err := adapter.Copy(ctx, "report.txt", "report.txt"); err != nil {
+		t.Errorf("Copy onto itself: %v", err)
+	}
+	if err := adapter.Move(ctx, "missing.txt", "missing.txt"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("Move(missing) onto itself = %v, want ErrCodeNotFound", err)
+	}
+}
+
+func TestWrite_QuotaExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"message":"The project exceeded its storage quota","errors":[{"reason":"quotaExceeded","message":"The project exceeded its storage quota"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket")
+
+	_, err = adapter.Write(context.Background(), "a.txt", strings.NewReader("data"), filekit.WithOverwrite(true))
+	if !errors.Is(err, filekit.ErrQuotaExceeded) || !filekit.IsCode(err, filekit.ErrCodeQuotaExceeded) {
+		t.Errorf("Write over quota = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestWriterChunkSize(t *testing.T) {
+	const kib = 1024
+	tests := []struct {
+		name    string
+		options []AdapterOption
+		content io.Reader
+		want    int
+	}{
+		{"default", nil, io.MultiReader(), DefaultChunkSize},
+		{"configured", []AdapterOption{WithChunkSize(1024 * kib)}, io.MultiReader(), 1024 * kib},
+		{"known large", []AdapterOption{WithChunkSize(1024 * kib)}, bytes.NewReader(make([]byte, 2048*kib)), 1024 * kib},
+		{"known small", []AdapterOption{WithChunkSize(1024 * kib)}, strings.NewReader("tiny"), 256 * kib},
+		{"exact chunk multiple", []AdapterOption{WithChunkSize(1024 * kib)}, bytes.NewReader(make([]byte, 256*kib)), 512 * kib},
+		{"disabled", []AdapterOption{WithChunkSize(0)}, io.MultiReader(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(nil, "bucket", tt.options...).writerChunkSize(tt.content); got != tt.want {
+				t.Errorf("writerChunkSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// resumableRecorder is a fake GCS resumable upload endpoint. It records the
+// size of each chunk and whether the upload was finalized.
+type resumableRecorder struct {
+	mu        sync.Mutex
+	chunks    []int
+	data      []byte
+	finalized bool
+}
+
+func newResumableServer(t *testing.T, rec *resumableRecorder, options ...AdapterOption) *Adapter {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Query().Get("uploadType") == "resumable":
+			w.Header().Set("Location", server.URL+"/session")
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/session":
+			body, _ := io.ReadAll(r.Body)
+			rec.chunks = append(rec.chunks, len(body))
+			rec.data = append(rec.data, body...)
+			// "bytes 0-262143/*" while streaming, "bytes 262144-300000/300001" for the last chunk
+			if strings.HasSuffix(r.Header.Get("Content-Range"), "/*") {
+				// The client sends X-GUploader-No-308, asking for 200 with an override header
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(rec.data)-1))
+				w.Header().Set("X-Http-Status-Code-Override", "308")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			rec.finalized = true
+			sum := make([]byte, 4)
+			binary.BigEndian.PutUint32(sum, crc32.Checksum(rec.data, crc32cTable))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"bucket": "bucket",
+				"name":   "data/big.bin",
+				"size":   strconv.Itoa(len(rec.data)),
+				"crc32c": base64.StdEncoding.EncodeToString(sum),
+			})
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, "bucket", append([]AdapterOption{WithPrefix("data")}, options...)...)
+}
+
+func TestWrite_ResumableChunks(t *testing.T) {
+	rec := &resumableRecorder{}
+	adapter := newResumableServer(t, rec, WithChunkSize(256*1024))
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 600*1024/16)
+	result, err := adapter.Write(context.Background(), "big.bin", io.MultiReader(bytes.NewReader(content)), filekit.WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if result.BytesWritten != int64(len(content)) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len(content))
+	}
+	want := []int{256 * 1024, 256 * 1024, 88 * 1024}
+	if !slices.Equal(rec.chunks, want) || !bytes.Equal(rec.data, content) {
+		t.Errorf("chunks = %v, want %v", rec.chunks, want)
+	}
+}
+
+func TestWrite_StreamErrorAbortsUpload(t *testing.T) {
+	rec := &resumableRecorder{}
+	adapter := newResumableServer(t, rec, WithChunkSize(256*1024))
+
+	readErr := errors.New("source went away")
+	content := io.MultiReader(bytes.NewReader(make([]byte, 300*1024)), iotest.ErrReader(readErr))
+	_, err := adapter.Write(context.Background(), "big.bin", content, filekit.WithOverwrite(true))
+	if !errors.Is(err, readErr) {
+		t.Errorf("Write error = %v, want the read error", err)
+	}
+	var fkErr *filekit.FileError
+	if !errors.As(err, &fkErr) || fkErr.Op != "write" {
+		t.Errorf("Write error = %v, want a filekit write error", err)
+	}
+	if rec.finalized {
+		t.Error("a failed stream finalized the object")
+	}
+}
+
+func TestWrite_StorageClass(t *testing.T) {
+	rec := &uploadRecorder{}
+	adapter := newUploadServer(t, rec)
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "logs/old.log", strings.NewReader("log"), filekit.WithOverwrite(true), filekit.WithStorageClass("coldline")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(rec.metadata) != 1 || rec.metadata[0]["storageClass"] != "COLDLINE" {
+		t.Errorf("upload metadata = %v, want storageClass COLDLINE", rec.metadata)
+	}
+
+	_, err := adapter.Write(ctx, "logs/bad.log", strings.NewReader("log"), filekit.WithOverwrite(true), filekit.WithStorageClass("GLACIER"))
+	if !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("Write with unknown class = %v, want ErrCodeInvalidInput", err)
+	}
+	if len(rec.metadata) != 1 {
+		t.Error("Write with unknown class reached GCS")
+	}
+}
+
+func TestWrite_ContentDisposition(t *testing.T) {
+	const disposition = `attachment; filename="report.pdf"`
+	rec := &uploadRecorder{}
+	adapter := newUploadServer(t, rec)
+
+	if _, err := adapter.Write(context.Background(), "report.pdf", strings.NewReader("pdf"), filekit.WithOverwrite(true), filekit.WithContentDisposition(disposition)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(rec.metadata) != 1 || rec.metadata[0]["contentDisposition"] != disposition {
+		t.Errorf("upload metadata = %v, want contentDisposition %q", rec.metadata, disposition)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"bucket":             "bucket",
+			"name":               "report.pdf",
+			"size":               "3",
+			"contentDisposition": disposition,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	info, err := New(client, "bucket").Stat(context.Background(), "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ContentDisposition != disposition {
+		t.Errorf("Stat ContentDisposition = %q, want %q", info.ContentDisposition, disposition)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	rec := &uploadRecorder{}
+	adapter := newUploadServer(t, rec)
+
+	localPath := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(localPath, []byte("png bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := adapter.WriteFile(context.Background(), "images/logo", localPath, filekit.WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if result.BytesWritten != int64(len("png bytes")) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("png bytes"))
+	}
+	if got := rec.metadata[0]["contentType"]; got != "image/png" {
+		t.Errorf("uploaded contentType = %v, want image/png from the local file name", got)
+	}
+}
+
+func TestWrite_ContentTypeResolution(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name    string
+		path    string
+		content io.Reader
+		options []filekit.Option
+		want    any
+	}{
+		{"extension", "data.json", strings.NewReader(`{"ok": true}`), nil, "application/json"},
+		{"sniffed seekable", "upload", strings.NewReader(png), nil, "image/png"},
+		{"sniffed stream", "upload", io.MultiReader(strings.NewReader(png)), nil, "image/png"},
+		{"forced", "forced.json", strings.NewReader("{}"), []filekit.Option{filekit.WithForceContentType("text/plain")}, "text/plain"},
+		// Without ForceEmptyContentType the client library would sniff one
+		{"forced empty", "empty.json", strings.NewReader("{}"), []filekit.Option{filekit.WithForceContentType("")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &uploadRecorder{}
+			adapter := newUploadServer(t, rec)
+
+			options := append([]filekit.Option{filekit.WithOverwrite(true)}, tt.options...)
+			if _, err := adapter.Write(context.Background(), tt.path, tt.content, options...); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if got := rec.metadata[0]["contentType"]; got != tt.want {
+				t.Errorf("uploaded contentType = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// chunkedStore is a fake GCS bucket for chunked uploads: multipart uploads,
+// listing, XML reads, compose and delete.
+type chunkedStore struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	metadata map[string]map[string]string
+	reads    int
+}
+
+func newChunkedServer(t *testing.T, store *chunkedStore) *Adapter {
+	t.Helper()
+	store.objects = make(map[string][]byte)
+	store.metadata = make(map[string]map[string]string)
+
+	const objectsPath = "/storage/v1/b/bucket/o"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		resource := func(name string) map[string]any {
+			return map[string]any{
+				"bucket":   "bucket",
+				"name":     name,
+				"size":     strconv.Itoa(len(store.objects[name])),
+				"metadata": store.metadata[name],
+			}
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Query().Get("uploadType") == "multipart":
+			_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			reader := multipart.NewReader(r.Body, params["boundary"])
+			var meta struct {
+				Name     string            `json:"name"`
+				Metadata map[string]string `json:"metadata"`
+			}
+			part, err := reader.NextPart()
+			if err == nil {
+				err = json.NewDecoder(part).Decode(&meta)
+			}
+			if err == nil {
+				part, err = reader.NextPart()
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(part)
+			store.objects[meta.Name] = data
+			store.metadata[meta.Name] = meta.Metadata
+			_ = json.NewEncoder(w).Encode(resource(meta.Name))
+
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/compose"):
+			dst := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, objectsPath+"/"), "/compose")
+			var req struct {
+				SourceObjects []struct {
+					Name string `json:"name"`
+				} `json:"sourceObjects"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var data []byte
+			for _, src := range req.SourceObjects {
+				data = append(data, store.objects[src.Name]...)
+			}
+			store.objects[dst] = data
+			_ = json.NewEncoder(w).Encode(resource(dst))
+
+		case r.Method == http.MethodGet && r.URL.Path == objectsPath:
+			prefix := r.URL.Query().Get("prefix")
+			var items []map[string]any
+			for _, name := range slices.Sorted(maps.Keys(store.objects)) {
+				if strings.HasPrefix(name, prefix) {
+					items = append(items, resource(name))
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+
+		case r.Method == http.MethodDelete:
+			delete(store.objects, strings.TrimPrefix(r.URL.Path, objectsPath+"/"))
+			w.WriteHeader(http.StatusNoContent)
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/bucket/"):
+			data, ok := store.objects[strings.TrimPrefix(r.URL.Path, "/bucket/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			store.reads++
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			_, _ = w.Write(data)
+
+		default:
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.String(), http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, "bucket", WithPrefix("data"))
+}
+
+func TestCompleteUploadWithChecksum(t *testing.T) {
+	ctx := context.Background()
+	parts := []string{"hello", " ", "world"}
+	whole := filekit.PartChecksum([]byte("hello world"))
+
+	// stage uploads the parts and returns the upload ID and part object names
+	stage := func(t *testing.T, adapter *Adapter, name string) (string, []string) {
+		t.Helper()
+		uploadID, err := adapter.InitiateUpload(ctx, name)
+		if err != nil {
+			t.Fatalf("InitiateUpload failed: %v", err)
+		}
+		var keys []string
+		for i, part := range parts {
+			if err := adapter.UploadPart(ctx, uploadID, i+1, []byte(part)); err != nil {
+				t.Fatalf("UploadPart %d failed: %v", i+1, err)
+			}
+			keys = append(keys, fmt.Sprintf("data/.filekit-uploads/%s/%d", uploadID, i+1))
+		}
+		return uploadID, keys
+	}
+
+	t.Run("records part checksums and composes", func(t *testing.T) {
+		store := &chunkedStore{}
+		adapter := newChunkedServer(t, store)
+
+		uploadID, keys := stage(t, adapter, "docs/a.txt")
+		for i, key := range keys {
+			if got, want := store.metadata[key][partChecksumKey], filekit.PartChecksum([]byte(parts[i])); got != want {
+				t.Errorf("part %d checksum = %q, want %q", i+1, got, want)
+			}
+		}
+		if err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole); err != nil {
+			t.Fatalf("CompleteUploadWithChecksum failed: %v", err)
+		}
+		if got := string(store.objects["data/docs/a.txt"]); got != "hello world" {
+			t.Errorf("composed object = %q, want %q", got, "hello world")
+		}
+		if store.reads != len(parts) {
+			t.Errorf("read %d parts back, want %d", store.reads, len(parts))
+		}
+	})
+
+	t.Run("corrupted part aborts", func(t *testing.T) {
+		store := &chunkedStore{}
+		adapter := newChunkedServer(t, store)
+
+		uploadID, keys := stage(t, adapter, "docs/b.txt")
+		store.objects[keys[1]] = []byte("_")
+
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole)
+		var mismatch *filekit.ChecksumMismatchError
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) || !errors.As(err, &mismatch) || mismatch.Part != 2 {
+			t.Fatalf("error = %v, want integrity error for part 2", err)
+		}
+		if _, ok := store.objects["data/docs/b.txt"]; ok {
+			t.Error("target object was composed despite a corrupted part")
+		}
+		for _, key := range keys {
+			if _, ok := store.objects[key]; ok {
+				t.Errorf("part %s was not cleaned up", key)
+			}
+		}
+	})
+
+	t.Run("wrong expected checksum aborts", func(t *testing.T) {
+		store := &chunkedStore{}
+		adapter := newChunkedServer(t, store)
+
+		uploadID, _ := stage(t, adapter, "docs/c.txt")
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, filekit.PartChecksum([]byte("other")))
+		var mismatch *filekit.ChecksumMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Part != 0 {
+			t.Fatalf("error = %v, want whole-file checksum mismatch", err)
+		}
+		if _, ok := store.objects["data/docs/c.txt"]; ok {
+			t.Error("target object was composed despite a checksum mismatch")
+		}
+	})
+
+	t.Run("CompleteUpload does not read parts", func(t *testing.T) {
+		store := &chunkedStore{}
+		adapter := newChunkedServer(t, store)
+
+		uploadID, _ := stage(t, adapter, "docs/d.txt")
+		if err := adapter.CompleteUpload(ctx, uploadID); err != nil {
+			t.Fatalf("CompleteUpload failed: %v", err)
+		}
+		if store.reads != 0 {
+			t.Errorf("read %d parts back, want none", store.reads)
+		}
+		if got := string(store.objects["data/docs/d.txt"]); got != "hello world" {
+			t.Errorf("composed object = %q, want %q", got, "hello world")
+		}
+	})
+}
+
+func TestPathEscapes(t *testing.T) {
+	var requests []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"No such object"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := storage.NewClient(context.Background(),
+		option.WithEndpoint(server.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	t.Run("rejected before any request", func(t *testing.T) {
+		fstest.TestPathEscapes(t, adapter)
+
+		p := "../other/x"
+		checks := map[string]error{}
+		_, checks["InitiateUpload"] = adapter.InitiateUpload(ctx, p)
+		_, checks["GenerateSignedGetURL"] = adapter.GenerateSignedGetURL(ctx, p, time.Minute)
+		_, checks["GenerateSignedPutURL"] = adapter.GenerateSignedPutURL(ctx, p, time.Minute, "text/plain")
+		checks["SetTags"] = adapter.SetTags(ctx, p, map[string]string{"k": "v"})
+		_, checks["GetTags"] = adapter.GetTags(ctx, p)
+		_, errs := adapter.DeleteMany(ctx, []string{p})
+		checks["DeleteMany"] = errs[p]
+		for op, err := range checks {
+			if !filekit.IsCode(err, filekit.ErrCodePermission) {
+				t.Errorf("%s(%q) = %v, want ErrCodePermission", op, p, err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(requests) > 0 {
+			t.Errorf("escaping paths reached the server: %v", requests)
+		}
+	})
+
+	t.Run("keys are cleaned", func(t *testing.T) {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		if _, err := adapter.FileExists(ctx, "/a//b/../c.txt"); err != nil {
+			t.Fatalf("FileExists failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if want := []string{"GET /storage/v1/b/bucket/o/data/a/c.txt"}; !slices.Equal(requests, want) {
+			t.Errorf("requests = %v, want %v", requests, want)
+		}
+	})
+}
@@ -2,19 +2,31 @@ package gcs
 
 import (
 	"context"
+	"fmt"
 
 	"cloud.google.com/go/storage"
 	"github.com/gobeaver/filekit"
+	"google.golang.org/api/option"
 )
 
 func init() {
 	filekit.RegisterDriver("gcs", func(cfg *filekit.Config) (filekit.FileSystem, error) {
+		if cfg.GCSBucket == "" {
+			return nil, fmt.Errorf("GCS bucket is required")
+		}
+
 		ctx := context.Background()
 
-		// Create client - uses GOOGLE_APPLICATION_CREDENTIALS env var or default credentials
-		client, err := storage.NewClient(ctx)
+		// Uses the credentials file when set, otherwise GOOGLE_APPLICATION_CREDENTIALS
+		// or the default credentials of the environment
+		var clientOptions []option.ClientOption
+		if cfg.GCSCredentialsFile != "" {
+			clientOptions = append(clientOptions, option.WithCredentialsFile(cfg.GCSCredentialsFile))
+		}
+
+		client, err := storage.NewClient(ctx, clientOptions...)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to create GCS client: %w", err)
 		}
 
 		var options []AdapterOption
@@ -3,10 +3,18 @@ package local
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gobeaver/filekit"
 )
@@ -307,10 +315,11 @@ func TestCompleteUpload(t *testing.T) {
 		}
 
 		// Get the parts directory before completing
-		uploadRegistry.RLock()
-		info := uploadRegistry.uploads[uploadID]
-		partsDir := info.partsDir
-		uploadRegistry.RUnlock()
+		info, err := a.uploads.Load(ctx, uploadID)
+		if err != nil {
+			t.Fatalf("failed to load upload state: %v", err)
+		}
+		partsDir := info.PartsDir
 
 		if err := a.CompleteUpload(ctx, uploadID); err != nil {
 			t.Fatalf("failed to complete upload: %v", err)
@@ -351,6 +360,173 @@ func TestCompleteUpload(t *testing.T) {
 	})
 }
 
+func TestCompleteUploadWithChecksum(t *testing.T) {
+	ctx := context.Background()
+	parts := [][]byte{[]byte("hello"), []byte(" "), []byte("world")}
+	sum := sha256.Sum256([]byte("hello world"))
+	wholeChecksum := hex.EncodeToString(sum[:])
+
+	// stage uploads the parts and returns the upload ID and parts directory
+	stage := func(t *testing.T, a *Adapter, path string) (string, string) {
+		t.Helper()
+		uploadID, err := a.InitiateUpload(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to initiate upload: %v", err)
+		}
+		for i, data := range parts {
+			if err := a.UploadPart(ctx, uploadID, i+1, data); err != nil {
+				t.Fatalf("failed to upload part %d: %v", i+1, err)
+			}
+		}
+		state, err := a.uploads.Load(ctx, uploadID)
+		if err != nil {
+			t.Fatalf("failed to load upload state: %v", err)
+		}
+		return uploadID, state.PartsDir
+	}
+
+	// wantMismatch checks err is an integrity error for the given part
+	wantMismatch := func(t *testing.T, err error, part int) {
+		t.Helper()
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) {
+			t.Fatalf("expected ErrCodeIntegrity, got: %v", err)
+		}
+		var mismatch *filekit.ChecksumMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Part != part {
+			t.Fatalf("expected *ChecksumMismatchError for part %d, got: %v", part, err)
+		}
+	}
+
+	t.Run("completes when checksum matches", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		a, err := New(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create adapter: %v", err)
+		}
+
+		uploadID, _ := stage(t, a, "test.txt")
+		if err := a.CompleteUploadWithChecksum(ctx, uploadID, wholeChecksum); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "test.txt"))
+		if err != nil {
+			t.Fatalf("failed to read completed file: %v", err)
+		}
+		if string(content) != "hello world" {
+			t.Errorf("expected 'hello world', got %q", content)
+		}
+	})
+
+	t.Run("fails on wrong expected checksum", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		a, err := New(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create adapter: %v", err)
+		}
+
+		uploadID, _ := stage(t, a, "test.txt")
+		wrong := filekit.PartChecksum([]byte("something else"))
+		wantMismatch(t, a.CompleteUploadWithChecksum(ctx, uploadID, wrong), 0)
+
+		if _, err := os.Stat(filepath.Join(tmpDir, "test.txt")); !os.IsNotExist(err) {
+			t.Errorf("expected no file after mismatch, got: %v", err)
+		}
+	})
+
+	t.Run("fails on corrupted part", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		a, err := New(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create adapter: %v", err)
+		}
+
+		uploadID, partsDir := stage(t, a, "test.txt")
+		if err := os.WriteFile(filepath.Join(partsDir, "3"), []byte("w0rld"), 0600); err != nil {
+			t.Fatalf("failed to corrupt part: %v", err)
+		}
+		wantMismatch(t, a.CompleteUploadWithChecksum(ctx, uploadID, wholeChecksum), 3)
+
+		if _, err := os.Stat(filepath.Join(tmpDir, "test.txt")); !os.IsNotExist(err) {
+			t.Errorf("expected no file after mismatch, got: %v", err)
+		}
+	})
+
+	t.Run("CompleteUpload verifies parts", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		a, err := New(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create adapter: %v", err)
+		}
+
+		uploadID, partsDir := stage(t, a, "test.txt")
+		if err := os.WriteFile(filepath.Join(partsDir, "1"), []byte("jello"), 0600); err != nil {
+			t.Fatalf("failed to corrupt part: %v", err)
+		}
+		wantMismatch(t, a.CompleteUpload(ctx, uploadID), 1)
+	})
+
+	t.Run("keeps existing file on mismatch", func(t *testing.T) {
+		for _, atomic := range []bool{false, true} {
+			t.Run(fmt.Sprintf("atomic=%t", atomic), func(t *testing.T) {
+				tmpDir := t.TempDir()
+				a, err := New(tmpDir, WithAtomicWrites(atomic))
+				if err != nil {
+					t.Fatalf("failed to create adapter: %v", err)
+				}
+				target := filepath.Join(tmpDir, "test.txt")
+				if err := os.WriteFile(target, []byte("original"), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				// A whole-file mismatch
+				uploadID, _ := stage(t, a, "test.txt")
+				wantMismatch(t, a.CompleteUploadWithChecksum(ctx, uploadID, strings.Repeat("0", 64)), 0)
+
+				// A corrupted part
+				uploadID, partsDir := stage(t, a, "test.txt")
+				if err := os.WriteFile(filepath.Join(partsDir, "2"), []byte("_"), 0600); err != nil {
+					t.Fatalf("failed to corrupt part: %v", err)
+				}
+				wantMismatch(t, a.CompleteUploadWithChecksum(ctx, uploadID, wholeChecksum), 2)
+
+				content, err := os.ReadFile(target)
+				if err != nil || string(content) != "original" {
+					t.Errorf("expected original file to be kept, got %q (err %v)", content, err)
+				}
+				entries, _ := os.ReadDir(tmpDir)
+				for _, e := range entries {
+					if strings.HasSuffix(e.Name(), ".tmp") {
+						t.Errorf("temporary file %s left behind", e.Name())
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("UploadReader with WithUploadPartChecksum", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		a, err := New(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create adapter: %v", err)
+		}
+
+		data := bytes.Repeat([]byte("0123456789"), 1000)
+		err = filekit.UploadReader(ctx, a, "big.bin", bytes.NewReader(data),
+			filekit.WithPartSize(1024),
+			filekit.WithUploadPartChecksum(),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "big.bin"))
+		if err != nil || !bytes.Equal(content, data) {
+			t.Errorf("uploaded content mismatch (err %v)", err)
+		}
+	})
+}
+
 func TestAbortUpload(t *testing.T) {
 	ctx := context.Background()
 
@@ -372,10 +548,11 @@ func TestAbortUpload(t *testing.T) {
 		}
 
 		// Get the parts directory before aborting
-		uploadRegistry.RLock()
-		info := uploadRegistry.uploads[uploadID]
-		partsDir := info.partsDir
-		uploadRegistry.RUnlock()
+		info, err := a.uploads.Load(ctx, uploadID)
+		if err != nil {
+			t.Fatalf("failed to load upload state: %v", err)
+		}
+		partsDir := info.PartsDir
 
 		// Abort upload
 		if err := a.AbortUpload(ctx, uploadID); err != nil {
@@ -521,6 +698,7 @@ func TestChunkedUploadIntegration(t *testing.T) {
 func TestChunkedUploaderInterface(t *testing.T) {
 	// Verify Adapter implements ChunkedUploader
 	var _ filekit.ChunkedUploader = (*Adapter)(nil)
+	var _ filekit.ChecksumUploader = (*Adapter)(nil)
 }
 
 func TestConcurrentChunkedUploads(t *testing.T) {
@@ -586,3 +764,204 @@ func TestConcurrentChunkedUploads(t *testing.T) {
 		}
 	})
 }
+
+func TestChunkedUpload_ResumeAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	storeDir := t.TempDir()
+
+	store, err := filekit.NewFileUploadStore(storeDir)
+	if err != nil {
+		t.Fatalf("failed to create upload store: %v", err)
+	}
+	before, err := New(root, WithUploadStore(store))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	uploadID, err := before.InitiateUpload(ctx, "resumed.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	if err := before.UploadPart(ctx, uploadID, 1, []byte("hello ")); err != nil {
+		t.Fatalf("failed to upload part: %v", err)
+	}
+
+	// Simulate a restart: fresh store and adapter over the same directories
+	reopened, err := filekit.NewFileUploadStore(storeDir)
+	if err != nil {
+		t.Fatalf("failed to reopen upload store: %v", err)
+	}
+	after, err := New(root, WithUploadStore(reopened))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	if err := after.UploadPart(ctx, uploadID, 2, []byte("world")); err != nil {
+		t.Fatalf("failed to upload part after restart: %v", err)
+	}
+	if err := after.CompleteUpload(ctx, uploadID); err != nil {
+		t.Fatalf("failed to complete upload after restart: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "resumed.txt"))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if !bytes.Equal(content, []byte("hello world")) {
+		t.Errorf("expected %q, got %q", "hello world", content)
+	}
+
+	// Upload state is gone once completed
+	if _, err := reopened.Load(ctx, uploadID); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected upload state to be removed, got %v", err)
+	}
+}
+
+func TestChunkedUpload_SeparateStores(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	a, err := New(root, WithUploadStore(filekit.NewMemoryUploadStore()))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+	b, err := New(root, WithUploadStore(filekit.NewMemoryUploadStore()))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	uploadID, err := a.InitiateUpload(ctx, "file.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	defer a.AbortUpload(ctx, uploadID)
+
+	// An adapter with a different store does not know the upload
+	err = b.UploadPart(ctx, uploadID, 1, []byte("data"))
+	if !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+// slowLoadStore stalls Load, widening the window between looking up an
+// upload and removing it.
+type slowLoadStore struct {
+	*filekit.MemoryUploadStore
+}
+
+func (s slowLoadStore) Load(ctx context.Context, uploadID string) (*filekit.UploadState, error) {
+	state, err := s.MemoryUploadStore.Load(ctx, uploadID)
+	time.Sleep(20 * time.Millisecond)
+	return state, err
+}
+
+func TestChunkedUpload_ConcurrentComplete(t *testing.T) {
+	ctx := context.Background()
+	adapter, err := New(t.TempDir(), WithUploadStore(slowLoadStore{filekit.NewMemoryUploadStore()}))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	uploadID, err := adapter.InitiateUpload(ctx, "file.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	if err := adapter.UploadPart(ctx, uploadID, 1, []byte("payload")); err != nil {
+		t.Fatalf("failed to upload part: %v", err)
+	}
+
+	// Only one of several racing completes and aborts claims the upload
+	var wg sync.WaitGroup
+	var won atomic.Int32
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				err = adapter.CompleteUpload(ctx, uploadID)
+			} else {
+				err = adapter.AbortUpload(ctx, uploadID)
+			}
+			switch {
+			case err == nil:
+				won.Add(1)
+			case !filekit.IsCode(err, filekit.ErrCodeNotFound):
+				t.Errorf("racing call = %v, want success or not found", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := won.Load(); n != 1 {
+		t.Errorf("%d racing calls succeeded, want 1", n)
+	}
+}
+
+func TestGarbageCollectUploads(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TMPDIR", t.TempDir())
+
+	store := filekit.NewMemoryUploadStore()
+	a, err := New(t.TempDir(), WithUploadStore(store))
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	staleID, err := a.InitiateUpload(ctx, "stale.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	freshID, err := a.InitiateUpload(ctx, "fresh.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	defer a.AbortUpload(ctx, freshID)
+
+	stale, err := store.Load(ctx, staleID)
+	if err != nil {
+		t.Fatalf("failed to load upload state: %v", err)
+	}
+	fresh, err := store.Load(ctx, freshID)
+	if err != nil {
+		t.Fatalf("failed to load upload state: %v", err)
+	}
+
+	// An orphaned part directory left by another process
+	orphan := filepath.Join(os.TempDir(), "filekit-upload-orphan-123")
+	if err := os.Mkdir(orphan, 0700); err != nil {
+		t.Fatalf("failed to create orphan: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, dir := range []string{stale.PartsDir, orphan} {
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatalf("failed to age %s: %v", dir, err)
+		}
+	}
+
+	reclaimed, err := a.GarbageCollectUploads(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("garbage collection failed: %v", err)
+	}
+	if reclaimed != 2 {
+		t.Errorf("expected 2 reclaimed upload areas, got %d", reclaimed)
+	}
+
+	for _, dir := range []string{stale.PartsDir, orphan} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", dir)
+		}
+	}
+	if _, err := os.Stat(fresh.PartsDir); err != nil {
+		t.Errorf("fresh upload should be left alone: %v", err)
+	}
+
+	// The stale upload is gone, the fresh one still works
+	if err := a.UploadPart(ctx, staleID, 1, []byte("x")); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected stale upload to be forgotten, got %v", err)
+	}
+	if err := a.UploadPart(ctx, freshID, 1, []byte("x")); err != nil {
+		t.Errorf("fresh upload should survive garbage collection: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !darwin
+
+package local
+
+import (
+	"os"
+
+	"github.com/gobeaver/filekit"
+)
+
+// setContentDisposition fails with filekit.ErrNotSupported when disposition is
+// set: extended attributes are only used on Linux and macOS.
+func setContentDisposition(_ *os.File, disposition string) error {
+	if disposition == "" {
+		return nil
+	}
+	return filekit.ErrNotSupported
+}
+
+// contentDisposition returns "": no disposition is stored on this platform.
+func contentDisposition(string) string {
+	return ""
+}
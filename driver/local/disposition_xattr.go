@@ -0,0 +1,51 @@
+//go:build linux || darwin
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/gobeaver/filekit"
+)
+
+// dispositionAttr is the extended attribute holding a file's Content-Disposition.
+const dispositionAttr = "user.filekit.content_disposition"
+
+// setContentDisposition stores disposition in f's extended attributes, or
+// removes a stored one when disposition is empty. Filesystems without user
+// extended attributes fail with filekit.ErrNotSupported when disposition is set.
+func setContentDisposition(f *os.File, disposition string) error {
+	fd := int(f.Fd())
+	if disposition == "" {
+		// Nothing to remove if the attribute is missing or unsupported
+		if _, err := unix.Fgetxattr(fd, dispositionAttr, nil); err != nil {
+			return nil
+		}
+		return unix.Fremovexattr(fd, dispositionAttr)
+	}
+
+	err := unix.Fsetxattr(fd, dispositionAttr, []byte(disposition), 0)
+	if errors.Is(err, unix.ENOTSUP) {
+		return fmt.Errorf("%w: content disposition: %w", filekit.ErrNotSupported, err)
+	}
+	return err
+}
+
+// contentDisposition returns the Content-Disposition stored for the file at
+// fullPath, or "" if it has none.
+func contentDisposition(fullPath string) string {
+	size, err := unix.Getxattr(fullPath, dispositionAttr, nil)
+	if err != nil || size == 0 {
+		return ""
+	}
+	buf := make([]byte, size)
+	n, err := unix.Getxattr(fullPath, dispositionAttr, buf)
+	if err != nil {
+		return ""
+	}
+	return string(buf[:n])
+}
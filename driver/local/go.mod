@@ -7,13 +7,15 @@ toolchain go1.24.2
 require (
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/gobeaver/filekit v0.0.4
+	golang.org/x/sys v0.13.0
 )
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/gobeaver/beaver-kit/config v0.1.0 // indirect
 	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
-	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 // During development, use local replace directives
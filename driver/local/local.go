@@ -16,17 +16,75 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gobeaver/filekit"
 )
 
 // Adapter provides a local filesystem implementation of filekit.FileSystem
 type Adapter struct {
-	root string
+	root     string
+	realRoot string
+	uploads  filekit.UploadStore
+	symlinks SymlinkPolicy
+	atomic   bool
+
+	// writeMu serializes conditional writes so that checking the current
+	// ETag and replacing the file happen as one step
+	writeMu sync.Mutex
+}
+
+// AdapterOption is a function that configures the local Adapter
+type AdapterOption func(*Adapter)
+
+// WithAtomicWrites makes Write write to a temporary file in the target
+// directory and rename it into place on success, so readers never observe a
+// partially written file and a failed or interrupted write leaves the
+// previous version intact. Writing to a symlink replaces the link rather than
+// its target. CompleteUpload always works this way. Default: false (write in
+// place).
+func WithAtomicWrites(enabled bool) AdapterOption {
+	return func(a *Adapter) {
+		a.atomic = enabled
+	}
+}
+
+// SymlinkPolicy controls how the adapter treats symbolic links under its root.
+type SymlinkPolicy int
+
+const (
+	// SymlinkDeny rejects operations on paths that resolve, through any
+	// symlink, to a location outside the root. Symlinks that stay under the
+	// root are followed. This is the default.
+	SymlinkDeny SymlinkPolicy = iota
+
+	// SymlinkFollow follows every symlink, including those pointing outside
+	// the root. Only use it when the contents of the root are trusted.
+	SymlinkFollow
+
+	// SymlinkReport behaves like SymlinkDeny and additionally sets
+	// FileInfo.IsSymlink in Stat and ListContents results.
+	SymlinkReport
+)
+
+// WithSymlinkPolicy sets how symlinks are treated. Default: SymlinkDeny.
+func WithSymlinkPolicy(policy SymlinkPolicy) AdapterOption {
+	return func(a *Adapter) {
+		a.symlinks = policy
+	}
+}
+
+// WithUploadStore sets the store used to persist chunked upload state.
+// With a persistent store, an upload initiated before a restart can still be
+// completed by a new adapter. Default: a process-wide in-memory store.
+func WithUploadStore(store filekit.UploadStore) AdapterOption {
+	return func(a *Adapter) {
+		a.uploads = store
+	}
 }
 
 // New creates a new local filesystem adapter
-func New(root string) (*Adapter, error) {
+func New(root string, options ...AdapterOption) (*Adapter, error) {
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
 		return nil, err
@@ -37,12 +95,32 @@ func New(root string) (*Adapter, error) {
 		return nil, err
 	}
 
-	return &Adapter{
-		root: absRoot,
-	}, nil
+	// Resolve the root itself so that a symlinked root (e.g. /tmp on macOS)
+	// does not make every path look like an escape
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := &Adapter{
+		root:     absRoot,
+		realRoot: realRoot,
+		uploads:  defaultUploadStore,
+	}
+
+	// Apply options
+	for _, option := range options {
+		option(adapter)
+	}
+
+	return adapter, nil
 }
 
-// Write implements filekit.FileWriter
+// Write implements filekit.FileWriter. For WithIfMatch and WithIfNoneMatch,
+// the ETag of a local file is the hex SHA-256 of its content, as returned in
+// WriteResult.ETag and by Checksum with filekit.ChecksumSHA256.
+// WithIfNoneMatch("*") creates the file with O_EXCL (or a hard link for
+// atomic writes), so it also holds against other processes.
 func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
 	select {
 	case <-ctx.Done():
@@ -51,43 +129,66 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("write", path, filekit.ErrNotAllowed)
 	}
 
+	// Apply file options (permissions, etc.) if needed
+	opts := processOptions(options...)
+
+	// Check preconditions against the current content under the write lock,
+	// held until the new content is in place
+	if opts.HasPreconditions() {
+		a.writeMu.Lock()
+		defer a.writeMu.Unlock()
+		if err := a.checkPreconditions(path, fullPath, opts); err != nil {
+			return nil, err
+		}
+	}
+
 	// Ensure the directory exists
 	dir := filepath.Dir(fullPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, filekit.WrapPathErr("write", path, err)
 	}
 
-	// Create the file
-	f, err := os.Create(fullPath)
+	// Create the file, or a temporary file to rename into place
+	exclusive := opts.IfNoneMatch == "*" && opts.IfMatch == ""
+	f, err := a.createFile(fullPath, exclusive)
+	if exclusive && errors.Is(err, os.ErrExist) {
+		return nil, filekit.CheckPreconditions("write", path, opts, true, "")
+	}
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", path, err)
 	}
-	defer f.Close()
+	committed := false
+	defer func() {
+		f.Close()
+		if !committed && f.Name() != fullPath {
+			os.Remove(f.Name())
+		}
+	}()
 
 	// Copy the content to the file while calculating checksum
 	hash := sha256.New()
-	written, err := io.Copy(io.MultiWriter(f, hash), content)
+	written, err := io.Copy(io.MultiWriter(f, hash), opts.TrackProgress(content))
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", path, err)
 	}
 
-	// Apply file options (permissions, etc.) if needed
-	opts := processOptions(options...)
+	// Store the Content-Disposition, clearing any left by an earlier write
+	if err := setContentDisposition(f, opts.ContentDisposition); err != nil {
+		return nil, filekit.WrapPathErr("write", path, err)
+	}
 
 	// Set file permissions based on visibility
 	if opts.Visibility == filekit.Public {
-		if err := os.Chmod(fullPath, 0644); err != nil {
+		if err := os.Chmod(f.Name(), 0644); err != nil {
 			return nil, filekit.WrapPathErr("write", path, err)
 		}
 	} else if opts.Visibility == filekit.Private {
-		if err := os.Chmod(fullPath, 0600); err != nil {
+		if err := os.Chmod(f.Name(), 0600); err != nil {
 			return nil, filekit.WrapPathErr("write", path, err)
 		}
 	}
@@ -98,14 +199,45 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 		return nil, filekit.WrapPathErr("write", path, err)
 	}
 
+	err = a.commitFile(f, fullPath, exclusive)
+	if exclusive && errors.Is(err, os.ErrExist) {
+		return nil, filekit.CheckPreconditions("write", path, opts, true, "")
+	}
+	if err != nil {
+		return nil, filekit.WrapPathErr("write", path, err)
+	}
+	committed = true
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
 	return &filekit.WriteResult{
 		BytesWritten:      written,
-		Checksum:          hex.EncodeToString(hash.Sum(nil)),
+		Checksum:          checksum,
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
+		ETag:              checksum,
+		ContentType:       getContentType(fullPath),
 		ServerTimestamp:   stat.ModTime(),
 	}, nil
 }
 
+// checkPreconditions evaluates the conditional write options against the
+// SHA-256 of the file currently at fullPath
+func (a *Adapter) checkPreconditions(path, fullPath string, opts *filekit.Options) error {
+	f, err := os.Open(fullPath)
+	if os.IsNotExist(err) {
+		return filekit.CheckPreconditions("write", path, opts, false, "")
+	}
+	if err != nil {
+		return filekit.WrapPathErr("write", path, err)
+	}
+	defer f.Close()
+
+	etag, err := filekit.CalculateChecksum(f, filekit.ChecksumSHA256)
+	if err != nil {
+		return filekit.WrapPathErr("write", path, err)
+	}
+	return filekit.CheckPreconditions("write", path, opts, true, etag)
+}
+
 // Read implements filekit.FileReader
 func (a *Adapter) Read(ctx context.Context, path string) (io.ReadCloser, error) {
 	select {
@@ -115,10 +247,8 @@ func (a *Adapter) Read(ctx context.Context, path string) (io.ReadCloser, error)
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("read", path, filekit.ErrNotAllowed)
 	}
 
@@ -130,6 +260,10 @@ func (a *Adapter) Read(ctx context.Context, path string) (io.ReadCloser, error)
 		}
 		return nil, filekit.WrapPathErr("read", path, err)
 	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		return nil, filekit.WrapPathErr("read", path, filekit.ErrIsDir)
+	}
 
 	return f, nil
 }
@@ -154,13 +288,16 @@ func (a *Adapter) Delete(ctx context.Context, path string) error {
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return filekit.WrapPathErr("delete", path, filekit.ErrNotAllowed)
 	}
 
+	// Directories are removed with DeleteDir
+	if info, err := os.Lstat(fullPath); err == nil && info.IsDir() {
+		return filekit.WrapPathErr("delete", path, filekit.ErrIsDir)
+	}
+
 	// Delete the file
 	err := os.Remove(fullPath)
 	if err != nil {
@@ -182,10 +319,8 @@ func (a *Adapter) FileExists(ctx context.Context, path string) (bool, error) {
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return false, filekit.WrapPathErr("fileexists", path, filekit.ErrNotAllowed)
 	}
 
@@ -210,10 +345,8 @@ func (a *Adapter) DirExists(ctx context.Context, path string) (bool, error) {
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return false, filekit.WrapPathErr("direxists", path, filekit.ErrNotAllowed)
 	}
 
@@ -238,10 +371,8 @@ func (a *Adapter) Stat(ctx context.Context, path string) (*filekit.FileInfo, err
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("stat", path, filekit.ErrNotAllowed)
 	}
 
@@ -253,27 +384,42 @@ func (a *Adapter) Stat(ctx context.Context, path string) (*filekit.FileInfo, err
 		return nil, filekit.WrapPathErr("stat", path, err)
 	}
 
-	// Get content type
-	contentType := ""
+	// Get content type and disposition
+	contentType, disposition := "", ""
 	if !info.IsDir() {
 		contentType = getContentType(fullPath)
+		disposition = contentDisposition(fullPath)
 	}
 
 	// Extract platform-specific information (Owner, CreatedAt)
 	owner, createdAt := extractPlatformInfo(info)
 
 	return &filekit.FileInfo{
-		Name:        filepath.Base(path),
-		Path:        path,
-		Size:        info.Size(),
-		ModTime:     info.ModTime(),
-		IsDir:       info.IsDir(),
-		ContentType: contentType,
-		Owner:       owner,
-		CreatedAt:   createdAt,
+		Name:               filepath.Base(path),
+		Path:               path,
+		Size:               info.Size(),
+		ModTime:            info.ModTime(),
+		IsDir:              info.IsDir(),
+		IsSymlink:          a.isSymlink(fullPath),
+		ContentType:        contentType,
+		ContentDisposition: disposition,
+		Owner:              owner,
+		CreatedAt:          createdAt,
 	}, nil
 }
 
+// StatMany implements filekit.CanStatMany. Lookups are cheap, so paths are
+// stat'ed sequentially.
+func (a *Adapter) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	return filekit.StatConcurrently(ctx, paths, 1, a.Stat)
+}
+
+// DeleteMany implements filekit.CanDeleteMany by deleting files
+// concurrently, bounded by filekit.DefaultDeleteConcurrency.
+func (a *Adapter) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	return filekit.DeleteConcurrently(ctx, paths, filekit.DefaultDeleteConcurrency, a.Delete)
+}
+
 // ListContents implements filekit.FileReader
 func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
 	select {
@@ -283,10 +429,8 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("listcontents", path, filekit.ErrNotAllowed)
 	}
 
@@ -344,6 +488,7 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 				Size:        info.Size(),
 				ModTime:     info.ModTime(),
 				IsDir:       info.IsDir(),
+				IsSymlink:   a.symlinks == SymlinkReport && info.Mode()&os.ModeSymlink != 0,
 				ContentType: contentType,
 				Owner:       owner,
 				CreatedAt:   createdAt,
@@ -383,6 +528,7 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 				Size:        info.Size(),
 				ModTime:     info.ModTime(),
 				IsDir:       info.IsDir(),
+				IsSymlink:   a.symlinks == SymlinkReport && info.Mode()&os.ModeSymlink != 0,
 				ContentType: contentType,
 				Owner:       owner,
 				CreatedAt:   createdAt,
@@ -390,9 +536,20 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		}
 	}
 
+	filekit.SortFiles(files, filekit.SortByName, false)
 	return files, nil
 }
 
+// ListPage implements filekit.CanListPage. Entries are sorted by path and
+// tokens are offsets, so a page may shift if the directory changes between calls.
+func (a *Adapter) ListPage(ctx context.Context, path string, opts filekit.ListPageOptions) (filekit.ListPage, error) {
+	files, err := a.ListContents(ctx, path, opts.Recursive)
+	if err != nil {
+		return filekit.ListPage{}, err
+	}
+	return filekit.PageEntries(path, files, opts)
+}
+
 // CreateDir implements filekit.FileSystem
 func (a *Adapter) CreateDir(ctx context.Context, path string) error {
 	select {
@@ -402,10 +559,8 @@ func (a *Adapter) CreateDir(ctx context.Context, path string) error {
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return filekit.WrapPathErr("createdir", path, filekit.ErrNotAllowed)
 	}
 
@@ -426,10 +581,8 @@ func (a *Adapter) DeleteDir(ctx context.Context, path string) error {
 		// Continue
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return filekit.WrapPathErr("deletedir", path, filekit.ErrNotAllowed)
 	}
 
@@ -468,6 +621,66 @@ func (a *Adapter) WriteFile(ctx context.Context, path string, localPath string,
 	return a.Write(ctx, path, file, options...)
 }
 
+// createFile opens fullPath for writing. With atomic writes it instead
+// creates a temporary file next to fullPath, which commitFile renames into
+// place. The temporary file takes the mode of the file it replaces, or the
+// default mode for new files. With exclusive, fullPath must not exist yet.
+func (a *Adapter) createFile(fullPath string, exclusive bool) (*os.File, error) {
+	if !a.atomic {
+		if exclusive {
+			return os.OpenFile(fullPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		}
+		return os.Create(fullPath)
+	}
+	return createTempFile(fullPath)
+}
+
+// createTempFile creates a hidden temporary file next to fullPath with the
+// permissions of the file it will replace, if any.
+func createTempFile(fullPath string) (*os.File, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	tmpPath := filepath.Join(filepath.Dir(fullPath),
+		"."+filepath.Base(fullPath)+"."+hex.EncodeToString(suffix)+".tmp")
+
+	f, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(fullPath); err == nil {
+		if err := f.Chmod(info.Mode().Perm()); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// commitFile finishes a file opened with createFile. Temporary files are
+// synced and renamed over fullPath. With exclusive they are hard linked
+// instead, which fails if fullPath exists.
+func (a *Adapter) commitFile(f *os.File, fullPath string, exclusive bool) error {
+	if f.Name() == fullPath {
+		return nil
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if exclusive {
+		if err := os.Link(f.Name(), fullPath); err != nil {
+			return err
+		}
+		return os.Remove(f.Name())
+	}
+	return os.Rename(f.Name(), fullPath)
+}
+
 // isPathUnderRoot checks if a path is under a given root directory
 func isPathUnderRoot(root, path string) bool {
 	rel, err := filepath.Rel(root, path)
@@ -475,7 +688,84 @@ func isPathUnderRoot(root, path string) bool {
 		return false
 	}
 
-	return !filepath.IsAbs(rel) && !strings.HasPrefix(rel, "../")
+	return !filepath.IsAbs(rel) && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// fullPath resolves path, normalized with filekit.CleanPath, to a path under
+// the root. It returns false if path escapes the root.
+func (a *Adapter) fullPath(path string) (string, bool) {
+	clean, err := filekit.CleanPath(path)
+	if err != nil {
+		return "", false
+	}
+	fullPath := filepath.Join(a.root, filepath.FromSlash(clean))
+	return fullPath, a.isAllowed(fullPath)
+}
+
+// isAllowed checks that fullPath is under the root and, unless the policy is
+// SymlinkFollow, that it still is once symlinks are resolved
+func (a *Adapter) isAllowed(fullPath string) bool {
+	if !isPathUnderRoot(a.root, fullPath) {
+		return false
+	}
+	if a.symlinks == SymlinkFollow {
+		return true
+	}
+
+	realPath, err := resolveSymlinks(fullPath)
+	if err != nil {
+		return false
+	}
+	return isPathUnderRoot(a.realRoot, realPath)
+}
+
+// maxSymlinkHops bounds symlink resolution so that link cycles terminate
+const maxSymlinkHops = 255
+
+// resolveSymlinks returns the real location of p. Unlike filepath.EvalSymlinks
+// it accepts paths that do not exist yet, resolving the longest prefix that
+// does, and follows dangling symlinks to where a write would create them.
+func resolveSymlinks(p string) (string, error) {
+	rest := ""
+	hops := 0
+	for {
+		realPath, err := filepath.EvalSymlinks(p)
+		if err == nil {
+			return filepath.Join(realPath, rest), nil
+		}
+
+		// p is a symlink whose target cannot be resolved (e.g. dangling)
+		if info, lerr := os.Lstat(p); lerr == nil && info.Mode()&os.ModeSymlink != 0 {
+			if hops++; hops > maxSymlinkHops {
+				return "", errors.New("too many levels of symbolic links")
+			}
+			target, err := os.Readlink(p)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(p), target)
+			}
+			p = target
+			continue
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(p), rest)
+		p = parent
+	}
+}
+
+// isSymlink reports whether fullPath is a symlink when the policy asks for it
+func (a *Adapter) isSymlink(fullPath string) bool {
+	if a.symlinks != SymlinkReport {
+		return false
+	}
+	info, err := os.Lstat(fullPath)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
 }
 
 // getContentType tries to determine the content type of a file
@@ -518,6 +808,22 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker by checking that the root directory
+// is still there.
+func (a *Adapter) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return filekit.FromContext(ctx, "ping", "")
+	}
+	info, err := os.Stat(a.root)
+	if err != nil {
+		return filekit.WrapPathErr("ping", "", err)
+	}
+	if !info.IsDir() {
+		return filekit.NewPathError("ping", "", filekit.ErrCodeTypeMismatch, "root is not a directory")
+	}
+	return nil
+}
+
 // Copy implements filekit.CanCopy for native file copying.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	select {
@@ -526,14 +832,13 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	default:
 	}
 
-	srcPath := filepath.Join(a.root, filepath.Clean(src))
-	dstPath := filepath.Join(a.root, filepath.Clean(dst))
-
 	// Check paths are under root
-	if !isPathUnderRoot(a.root, srcPath) {
+	srcPath, ok := a.fullPath(src)
+	if !ok {
 		return filekit.WrapPathErr("copy", src, filekit.ErrNotAllowed)
 	}
-	if !isPathUnderRoot(a.root, dstPath) {
+	dstPath, ok := a.fullPath(dst)
+	if !ok {
 		return filekit.WrapPathErr("copy", dst, filekit.ErrNotAllowed)
 	}
 
@@ -547,6 +852,11 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	}
 	defer srcFile.Close()
 
+	// Creating the destination would truncate the source
+	if srcPath == dstPath {
+		return nil
+	}
+
 	// Create destination directory if needed
 	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
 		return filekit.WrapPathErr("copy", dst, err)
@@ -564,6 +874,11 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("copy", dst, err)
 	}
 
+	// Copy the Content-Disposition, replacing any the destination had
+	if err := setContentDisposition(dstFile, contentDisposition(srcPath)); err != nil {
+		return filekit.WrapPathErr("copy", dst, err)
+	}
+
 	// Copy file permissions
 	srcInfo, err := os.Stat(srcPath)
 	if err == nil {
@@ -573,22 +888,24 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	return nil
 }
 
-// Move implements filekit.CanMove for native file moving/renaming.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+// Move implements filekit.CanMove for native file moving/renaming. With
+// filekit.WithMoveNoOverwrite, files are hard-linked to dst and then unlinked
+// from src, so an existing dst is never replaced; directories and filesystems
+// without hard links check for dst before renaming.
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	srcPath := filepath.Join(a.root, filepath.Clean(src))
-	dstPath := filepath.Join(a.root, filepath.Clean(dst))
-
 	// Check paths are under root
-	if !isPathUnderRoot(a.root, srcPath) {
+	srcPath, ok := a.fullPath(src)
+	if !ok {
 		return filekit.WrapPathErr("move", src, filekit.ErrNotAllowed)
 	}
-	if !isPathUnderRoot(a.root, dstPath) {
+	dstPath, ok := a.fullPath(dst)
+	if !ok {
 		return filekit.WrapPathErr("move", dst, filekit.ErrNotAllowed)
 	}
 
@@ -600,11 +917,33 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("move", src, err)
 	}
 
+	// Moving a file onto itself is a no-op; the copy+delete fallback below
+	// would delete it
+	if srcPath == dstPath {
+		return nil
+	}
+
 	// Create destination directory if needed
 	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
 		return filekit.WrapPathErr("move", dst, err)
 	}
 
+	if filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		err := os.Link(srcPath, dstPath)
+		switch {
+		case err == nil:
+			if err := os.Remove(srcPath); err != nil {
+				return filekit.WrapPathErr("move", src, err)
+			}
+			return nil
+		case errors.Is(err, os.ErrExist):
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
+		if _, err := os.Lstat(dstPath); err == nil {
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
+	}
+
 	// Try rename first (works if same filesystem)
 	if err := os.Rename(srcPath, dstPath); err != nil {
 		// If rename fails (cross-device), fall back to copy+delete
@@ -628,9 +967,8 @@ func (a *Adapter) Checksum(ctx context.Context, path string, algorithm filekit.C
 	default:
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return "", filekit.WrapPathErr("checksum", path, filekit.ErrNotAllowed)
 	}
 
@@ -659,9 +997,8 @@ func (a *Adapter) Checksums(ctx context.Context, path string, algorithms []filek
 	default:
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("checksums", path, filekit.ErrNotAllowed)
 	}
 
@@ -818,18 +1155,35 @@ type fsEvent struct {
 // Chunked Upload Implementation
 // ============================================================================
 
-// uploadInfo stores metadata for an in-progress chunked upload.
-type uploadInfo struct {
-	path     string // Target path for the final file
-	partsDir string // Directory storing uploaded parts
+// defaultUploadStore holds upload state for adapters without WithUploadStore.
+var defaultUploadStore filekit.UploadStore = filekit.NewMemoryUploadStore()
+
+// loadUpload returns the state of an upload, reporting a missing upload as not found.
+func (a *Adapter) loadUpload(ctx context.Context, op, uploadID string) (*filekit.UploadState, error) {
+	info, err := a.uploads.Load(ctx, uploadID)
+	if err != nil {
+		return nil, uploadStoreError(op, uploadID, err)
+	}
+	return info, nil
 }
 
-// uploadRegistry is a thread-safe registry for in-progress uploads.
-var uploadRegistry = struct {
-	sync.RWMutex
-	uploads map[string]*uploadInfo
-}{
-	uploads: make(map[string]*uploadInfo),
+// takeUpload removes the state of an upload and returns it, so that only one
+// of several concurrent CompleteUpload and AbortUpload calls proceeds; the
+// others see the upload as not found.
+func (a *Adapter) takeUpload(ctx context.Context, op, uploadID string) (*filekit.UploadState, error) {
+	info, err := a.uploads.Take(ctx, uploadID)
+	if err != nil {
+		return nil, uploadStoreError(op, uploadID, err)
+	}
+	return info, nil
+}
+
+// uploadStoreError wraps an upload store error for op.
+func uploadStoreError(op, uploadID string, err error) error {
+	if filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		return filekit.NewPathError(op, uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	}
+	return filekit.WrapPathErr(op, uploadID, err)
 }
 
 // generateUploadID creates a unique upload identifier.
@@ -850,10 +1204,7 @@ func (a *Adapter) InitiateUpload(ctx context.Context, path string) (string, erro
 	default:
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	// Check if the path is under the root
-	if !isPathUnderRoot(a.root, fullPath) {
+	if _, ok := a.fullPath(path); !ok {
 		return "", filekit.WrapPathErr("initiate-upload", path, filekit.ErrNotAllowed)
 	}
 
@@ -870,18 +1221,23 @@ func (a *Adapter) InitiateUpload(ctx context.Context, path string) (string, erro
 	}
 
 	// Store upload info
-	uploadRegistry.Lock()
-	uploadRegistry.uploads[uploadID] = &uploadInfo{
-		path:     path,
-		partsDir: partsDir,
+	err = a.uploads.Save(ctx, &filekit.UploadState{
+		UploadID:  uploadID,
+		Path:      path,
+		PartsDir:  partsDir,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		_ = os.RemoveAll(partsDir)
+		return "", filekit.WrapPathErr("initiate-upload", path, err)
 	}
-	uploadRegistry.Unlock()
 
 	return uploadID, nil
 }
 
 // UploadPart uploads a part of a file in a chunked upload process.
-// Parts are stored as numbered files (1, 2, 3, ...) in the temporary directory.
+// Parts are stored as numbered files (1, 2, 3, ...) in the temporary directory,
+// each next to a .sha256 file holding its checksum.
 func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
 	select {
 	case <-ctx.Done():
@@ -895,49 +1251,58 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 	}
 
 	// Get upload info
-	uploadRegistry.RLock()
-	info, ok := uploadRegistry.uploads[uploadID]
-	uploadRegistry.RUnlock()
-
-	if !ok {
-		return filekit.NewPathError("upload-part", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	info, err := a.loadUpload(ctx, "upload-part", uploadID)
+	if err != nil {
+		return err
 	}
 
 	// Write part to file
-	partPath := filepath.Join(info.partsDir, fmt.Sprintf("%d", partNumber))
+	partPath := filepath.Join(info.PartsDir, fmt.Sprintf("%d", partNumber))
 	if err := os.WriteFile(partPath, data, 0600); err != nil {
 		return filekit.WrapPathErr("upload-part", uploadID, err)
 	}
+	if err := os.WriteFile(partPath+".sha256", []byte(filekit.PartChecksum(data)), 0600); err != nil {
+		return filekit.WrapPathErr("upload-part", uploadID, err)
+	}
 
 	return nil
 }
 
 // CompleteUpload finalizes a chunked upload by concatenating all parts.
-// Parts are read in numerical order and written to the target file.
+// Parts are read in numerical order and written to the target file. A part
+// that no longer matches the checksum recorded by UploadPart fails the upload.
 func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
+	return a.completeUpload(ctx, uploadID, "")
+}
+
+// CompleteUploadWithChecksum implements filekit.ChecksumUploader. It is
+// CompleteUpload with the concatenated parts also checked against expected,
+// a hex-encoded SHA-256, before the target file is committed.
+func (a *Adapter) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	return a.completeUpload(ctx, uploadID, expected)
+}
+
+// completeUpload concatenates the parts of an upload into its target file,
+// verifying each part and, unless expected is empty, the whole. On failure
+// the partially written file is removed.
+func (a *Adapter) completeUpload(ctx context.Context, uploadID, expected string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	// Get and remove upload info
-	uploadRegistry.Lock()
-	info, ok := uploadRegistry.uploads[uploadID]
-	if ok {
-		delete(uploadRegistry.uploads, uploadID)
-	}
-	uploadRegistry.Unlock()
-
-	if !ok {
-		return filekit.NewPathError("complete-upload", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	// Claim the upload; a concurrent call for the same ID gets not found
+	info, err := a.takeUpload(ctx, "complete-upload", uploadID)
+	if err != nil {
+		return err
 	}
 
 	// Ensure cleanup of parts directory
-	defer os.RemoveAll(info.partsDir)
+	defer os.RemoveAll(info.PartsDir)
 
 	// Read all part files
-	entries, err := os.ReadDir(info.partsDir)
+	entries, err := os.ReadDir(info.PartsDir)
 	if err != nil {
 		return filekit.WrapPathErr("complete-upload", uploadID, err)
 	}
@@ -960,37 +1325,63 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 	}
 	sort.Ints(partNumbers)
 
-	// Prepare target path
-	fullPath := filepath.Join(a.root, filepath.Clean(info.path))
+	// Prepare target path; re-checked in case a symlink appeared since InitiateUpload
+	fullPath, ok := a.fullPath(info.Path)
+	if !ok {
+		return filekit.WrapPathErr("complete-upload", info.Path, filekit.ErrNotAllowed)
+	}
 
 	// Ensure the directory exists
 	dir := filepath.Dir(fullPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return filekit.WrapPathErr("complete-upload", info.path, err)
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
 	}
 
-	// Create the target file
-	targetFile, err := os.Create(fullPath)
+	// Assemble into a temporary file so a failed verification leaves any
+	// existing file untouched
+	targetFile, err := createTempFile(fullPath)
 	if err != nil {
-		return filekit.WrapPathErr("complete-upload", info.path, err)
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
 	}
-	defer targetFile.Close()
+	committed := false
+	defer func() {
+		targetFile.Close()
+		if !committed {
+			os.Remove(targetFile.Name())
+		}
+	}()
 
-	// Concatenate all parts in order
+	// Concatenate all parts in order, hashing each part and the whole
+	whole := sha256.New()
 	for _, partNum := range partNumbers {
-		partPath := filepath.Join(info.partsDir, fmt.Sprintf("%d", partNum))
+		partPath := filepath.Join(info.PartsDir, fmt.Sprintf("%d", partNum))
 		partFile, err := os.Open(partPath)
 		if err != nil {
-			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to open part %d: %w", partNum, err))
+			return filekit.WrapPathErr("complete-upload", info.Path, fmt.Errorf("failed to open part %d: %w", partNum, err))
 		}
 
-		_, err = io.Copy(targetFile, partFile)
+		part := sha256.New()
+		_, err = io.Copy(io.MultiWriter(targetFile, whole, part), partFile)
 		partFile.Close()
 		if err != nil {
-			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to write part %d: %w", partNum, err))
+			return filekit.WrapPathErr("complete-upload", info.Path, fmt.Errorf("failed to write part %d: %w", partNum, err))
 		}
+
+		// Parts staged without a checksum file are not verified
+		recorded, _ := os.ReadFile(partPath + ".sha256")
+		if err := filekit.VerifyUploadChecksum(info.Path, partNum, string(recorded), part.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	if err := filekit.VerifyUploadChecksum(info.Path, 0, expected, whole.Sum(nil)); err != nil {
+		return err
 	}
 
+	if err := a.commitFile(targetFile, fullPath, false); err != nil {
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
+	}
+	committed = true
+
 	return nil
 }
 
@@ -1002,26 +1393,87 @@ func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
 	default:
 	}
 
-	// Get and remove upload info
-	uploadRegistry.Lock()
-	info, ok := uploadRegistry.uploads[uploadID]
-	if ok {
-		delete(uploadRegistry.uploads, uploadID)
-	}
-	uploadRegistry.Unlock()
-
-	if !ok {
-		return filekit.NewPathError("abort-upload", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	// Claim the upload; a concurrent call for the same ID gets not found
+	info, err := a.takeUpload(ctx, "abort-upload", uploadID)
+	if err != nil {
+		return err
 	}
 
 	// Clean up parts directory
-	if err := os.RemoveAll(info.partsDir); err != nil {
+	if err := os.RemoveAll(info.PartsDir); err != nil {
 		return filekit.WrapPathErr("abort-upload", uploadID, err)
 	}
 
 	return nil
 }
 
+// GarbageCollectUploads removes temporary part directories of chunked uploads
+// that were never completed or aborted and have not been written to for longer
+// than olderThan. It returns the number of part directories removed.
+//
+// Both uploads known to the adapter's upload store and orphaned part
+// directories left in the system temp directory (for example by a process
+// that exited mid-upload) are reclaimed.
+func (a *Adapter) GarbageCollectUploads(ctx context.Context, olderThan time.Duration) (reclaimed int, err error) {
+	cutoff := time.Now().Add(-olderThan)
+	errs := filekit.NewMultiError("gc-uploads")
+	seen := make(map[string]bool)
+
+	// Uploads tracked by the store
+	states, err := a.uploads.List(ctx)
+	if err != nil {
+		return 0, filekit.WrapPathErr("gc-uploads", "", err)
+	}
+	for _, state := range states {
+		if err := filekit.FromContext(ctx, "gc-uploads", state.UploadID); err != nil {
+			return reclaimed, err
+		}
+		seen[state.PartsDir] = true
+
+		info, statErr := os.Stat(state.PartsDir)
+		switch {
+		case statErr == nil && info.ModTime().Before(cutoff):
+			if err := os.RemoveAll(state.PartsDir); err != nil {
+				errs.Add(filekit.WrapPathErr("gc-uploads", state.UploadID, err))
+				continue
+			}
+			reclaimed++
+		case errors.Is(statErr, os.ErrNotExist) && state.CreatedAt.Before(cutoff):
+			// Parts are already gone; only the stale state remains
+		default:
+			continue
+		}
+		errs.Add(a.uploads.Delete(ctx, state.UploadID))
+	}
+
+	// Orphaned part directories without upload state
+	tempDir := os.TempDir()
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return reclaimed, filekit.WrapPathErr("gc-uploads", tempDir, err)
+	}
+	for _, entry := range entries {
+		partsDir := filepath.Join(tempDir, entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "filekit-upload-") || seen[partsDir] {
+			continue
+		}
+		if err := filekit.FromContext(ctx, "gc-uploads", partsDir); err != nil {
+			return reclaimed, err
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(partsDir); err != nil {
+			errs.Add(filekit.WrapPathErr("gc-uploads", partsDir, err))
+			continue
+		}
+		reclaimed++
+	}
+
+	return reclaimed, errs.Err()
+}
+
 // ReadRange implements filekit.CanReadRange for efficient partial file reads.
 func (a *Adapter) ReadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
 	select {
@@ -1034,9 +1486,8 @@ func (a *Adapter) ReadRange(ctx context.Context, path string, offset, length int
 		return nil, filekit.WrapPathErr("read_range", path, filekit.ErrInvalidOffset)
 	}
 
-	fullPath := filepath.Join(a.root, filepath.Clean(path))
-
-	if !isPathUnderRoot(a.root, fullPath) {
+	fullPath, ok := a.fullPath(path)
+	if !ok {
 		return nil, filekit.WrapPathErr("read_range", path, filekit.ErrNotAllowed)
 	}
 
@@ -1095,13 +1546,19 @@ func (l *limitedReadCloser) Close() error {
 
 // Ensure Adapter implements interfaces
 var (
-	_ filekit.FileSystem      = (*Adapter)(nil)
-	_ filekit.FileReader      = (*Adapter)(nil)
-	_ filekit.FileWriter      = (*Adapter)(nil)
-	_ filekit.CanCopy         = (*Adapter)(nil)
-	_ filekit.CanMove         = (*Adapter)(nil)
-	_ filekit.CanChecksum     = (*Adapter)(nil)
-	_ filekit.CanWatch        = (*Adapter)(nil)
-	_ filekit.CanReadRange    = (*Adapter)(nil)
-	_ filekit.ChunkedUploader = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanReadRange      = (*Adapter)(nil)
+	_ filekit.CanStatMany       = (*Adapter)(nil)
+	_ filekit.CanDeleteMany     = (*Adapter)(nil)
+	_ filekit.CanListPage       = (*Adapter)(nil)
+	_ filekit.ChunkedUploader   = (*Adapter)(nil)
+	_ filekit.ChecksumUploader  = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
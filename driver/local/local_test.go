@@ -0,0 +1,417 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/fstest"
+)
+
+// newSymlinkFixture creates a root containing "escape" (a symlink to a
+// directory outside the root), "secret.txt" (a symlink to a file outside
+// the root) and "inner.txt" (a symlink to a file inside the root).
+func newSymlinkFixture(t *testing.T) (root, outside string) {
+	t.Helper()
+	root = t.TempDir()
+	outside = t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outside, "passwd"), []byte("root:x:0:0"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "real.txt"), []byte("inside"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "passwd"), filepath.Join(root, "secret.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("real.txt", filepath.Join(root, "inner.txt")); err != nil {
+		t.Fatal(err)
+	}
+	return root, outside
+}
+
+func TestSymlinkDeny(t *testing.T) {
+	ctx := context.Background()
+	root, outside := newSymlinkFixture(t)
+
+	a, err := New(root)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	t.Run("rejects reads through escaping symlinks", func(t *testing.T) {
+		for _, p := range []string{"secret.txt", "escape/passwd"} {
+			if _, err := a.ReadAll(ctx, p); !filekit.IsCode(err, filekit.ErrCodePermission) {
+				t.Errorf("ReadAll(%q) error = %v, want permission denied", p, err)
+			}
+			if _, err := a.Stat(ctx, p); !filekit.IsCode(err, filekit.ErrCodePermission) {
+				t.Errorf("Stat(%q) error = %v, want permission denied", p, err)
+			}
+		}
+		if _, err := a.ListContents(ctx, "escape", false); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("ListContents(escape) error = %v, want permission denied", err)
+		}
+	})
+
+	t.Run("rejects writes through escaping symlinks", func(t *testing.T) {
+		if _, err := a.Write(ctx, "escape/new.txt", strings.NewReader("x")); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Write() error = %v, want permission denied", err)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "new.txt")); !os.IsNotExist(err) {
+			t.Error("file was created outside the root")
+		}
+	})
+
+	t.Run("rejects writes through dangling symlinks", func(t *testing.T) {
+		if err := os.Symlink(filepath.Join(outside, "planted.txt"), filepath.Join(root, "dangling.txt")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := a.Write(ctx, "dangling.txt", strings.NewReader("x")); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Write() error = %v, want permission denied", err)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "planted.txt")); !os.IsNotExist(err) {
+			t.Error("file was created outside the root")
+		}
+	})
+
+	t.Run("follows symlinks inside the root", func(t *testing.T) {
+		data, err := a.ReadAll(ctx, "inner.txt")
+		if err != nil || string(data) != "inside" {
+			t.Errorf("ReadAll(inner.txt) = %q, %v; want \"inside\"", data, err)
+		}
+	})
+
+	t.Run("does not report symlinks", func(t *testing.T) {
+		info, err := a.Stat(ctx, "inner.txt")
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if info.IsSymlink {
+			t.Error("IsSymlink set without SymlinkReport")
+		}
+	})
+}
+
+func TestSymlinkFollow(t *testing.T) {
+	ctx := context.Background()
+	root, _ := newSymlinkFixture(t)
+
+	a, err := New(root, WithSymlinkPolicy(SymlinkFollow))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data, err := a.ReadAll(ctx, "escape/passwd")
+	if err != nil || string(data) != "root:x:0:0" {
+		t.Errorf("ReadAll(escape/passwd) = %q, %v; want the outside file", data, err)
+	}
+
+	// Lexical escapes are rejected regardless of the policy
+	if _, err := a.ReadAll(ctx, "../outside.txt"); !filekit.IsCode(err, filekit.ErrCodePermission) {
+		t.Errorf("ReadAll(../outside.txt) error = %v, want permission denied", err)
+	}
+}
+
+func TestSymlinkReport(t *testing.T) {
+	ctx := context.Background()
+	root, _ := newSymlinkFixture(t)
+
+	a, err := New(root, WithSymlinkPolicy(SymlinkReport))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info, err := a.Stat(ctx, "inner.txt")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if !info.IsSymlink {
+		t.Error("Stat(inner.txt).IsSymlink = false, want true")
+	}
+	if info.Size != int64(len("inside")) {
+		t.Errorf("Stat(inner.txt).Size = %d, want the target's size", info.Size)
+	}
+
+	files, err := a.ListContents(ctx, "", false)
+	if err != nil {
+		t.Fatalf("ListContents() error = %v", err)
+	}
+	links := make(map[string]bool)
+	for _, f := range files {
+		links[f.Name] = f.IsSymlink
+	}
+	want := map[string]bool{"real.txt": false, "inner.txt": true, "secret.txt": true, "escape": true}
+	for name, isLink := range want {
+		if got, ok := links[name]; !ok || got != isLink {
+			t.Errorf("%s: IsSymlink = %v (listed %v), want %v", name, got, ok, isLink)
+		}
+	}
+
+	if _, err := a.ReadAll(ctx, "secret.txt"); !filekit.IsCode(err, filekit.ErrCodePermission) {
+		t.Errorf("ReadAll(secret.txt) error = %v, want permission denied", err)
+	}
+}
+
+// failAfterReader returns data, then fails as if the source was interrupted
+type failAfterReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failAfterReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestAtomicWrites(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("failed write leaves the original intact", func(t *testing.T) {
+		root := t.TempDir()
+		a, err := New(root, WithAtomicWrites(true))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := a.Write(ctx, "docs/report.txt", strings.NewReader("original")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		_, err = a.Write(ctx, "docs/report.txt", &failAfterReader{data: []byte("partial"), err: errors.New("connection reset")})
+		if err == nil {
+			t.Fatal("Write() succeeded, want error")
+		}
+
+		data, err := os.ReadFile(filepath.Join(root, "docs", "report.txt"))
+		if err != nil || string(data) != "original" {
+			t.Errorf("file = %q, %v; want \"original\"", data, err)
+		}
+		entries, err := os.ReadDir(filepath.Join(root, "docs"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			t.Errorf("directory contains %v, want only report.txt", names)
+		}
+	})
+
+	t.Run("replaces the file and keeps its mode", func(t *testing.T) {
+		root := t.TempDir()
+		a, err := New(root, WithAtomicWrites(true))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		target := filepath.Join(root, "config.json")
+		if err := os.WriteFile(target, []byte("old"), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(target, 0o640); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := a.Write(ctx, "config.json", strings.NewReader("new"))
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if result.BytesWritten != 3 {
+			t.Errorf("BytesWritten = %d, want 3", result.BytesWritten)
+		}
+		data, _ := os.ReadFile(target)
+		if string(data) != "new" {
+			t.Errorf("file = %q, want \"new\"", data)
+		}
+		if info, err := os.Stat(target); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0o640) {
+			t.Errorf("mode = %v, %v; want 0640", info.Mode().Perm(), err)
+		}
+	})
+
+	t.Run("applies visibility before rename", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Unix permissions")
+		}
+		root := t.TempDir()
+		a, err := New(root, WithAtomicWrites(true))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := a.Write(ctx, "secret.txt", strings.NewReader("x"), filekit.WithVisibility(filekit.Private)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		info, err := os.Stat(filepath.Join(root, "secret.txt"))
+		if err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("mode = %v, %v; want 0600", info.Mode().Perm(), err)
+		}
+	})
+
+	t.Run("chunked upload is renamed into place", func(t *testing.T) {
+		root := t.TempDir()
+		a, err := New(root, WithAtomicWrites(true))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		uploadID, err := a.InitiateUpload(ctx, "big.bin")
+		if err != nil {
+			t.Fatalf("InitiateUpload() error = %v", err)
+		}
+		if err := a.UploadPart(ctx, uploadID, 1, []byte("hello ")); err != nil {
+			t.Fatal(err)
+		}
+		if err := a.UploadPart(ctx, uploadID, 2, []byte("world")); err != nil {
+			t.Fatal(err)
+		}
+		if err := a.CompleteUpload(ctx, uploadID); err != nil {
+			t.Fatalf("CompleteUpload() error = %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(root, "big.bin"))
+		if string(data) != "hello world" {
+			t.Errorf("file = %q, want \"hello world\"", data)
+		}
+		matches, _ := filepath.Glob(filepath.Join(root, ".big.bin.*.tmp"))
+		if len(matches) != 0 {
+			t.Errorf("temporary files leaked: %v", matches)
+		}
+	})
+}
+
+// TestExclusiveCreate_SeparateAdapters uses one adapter per writer, as
+// separate processes would, so only O_EXCL (or the hard link) can serialize
+// them.
+func TestExclusiveCreate_SeparateAdapters(t *testing.T) {
+	for _, atomic := range []bool{false, true} {
+		t.Run(fmt.Sprintf("atomic=%v", atomic), func(t *testing.T) {
+			ctx := context.Background()
+			root := t.TempDir()
+
+			const writers = 8
+			errs := make([]error, writers)
+			var wg sync.WaitGroup
+			for i := range writers {
+				a, err := New(root, WithAtomicWrites(atomic))
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = filekit.CreateExclusive(ctx, a, "job.lock", strings.NewReader(strconv.Itoa(i)))
+				}()
+			}
+			wg.Wait()
+
+			winners := 0
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					winners++
+				case !errors.Is(err, os.ErrExist):
+					t.Errorf("writer %d: %v, want ErrExist", i, err)
+				}
+			}
+			if winners != 1 {
+				t.Errorf("%d writers created the file, want 1", winners)
+			}
+			if matches, _ := filepath.Glob(filepath.Join(root, ".job.lock.*.tmp")); len(matches) != 0 {
+				t.Errorf("temporary files leaked: %v", matches)
+			}
+		})
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("Content-Disposition is stored in extended attributes on Linux and macOS only")
+	}
+	const disposition = `attachment; filename="report.pdf"`
+
+	for _, atomic := range []bool{false, true} {
+		t.Run(fmt.Sprintf("atomic=%v", atomic), func(t *testing.T) {
+			ctx := context.Background()
+			a, err := New(t.TempDir(), WithAtomicWrites(atomic))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			stat := func(path string) string {
+				t.Helper()
+				info, err := a.Stat(ctx, path)
+				if err != nil {
+					t.Fatalf("Stat(%s) error = %v", path, err)
+				}
+				return info.ContentDisposition
+			}
+
+			_, err = a.Write(ctx, "report.pdf", strings.NewReader("pdf"), filekit.WithContentDisposition(disposition))
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := stat("report.pdf"); got != disposition {
+				t.Errorf("ContentDisposition = %q, want %q", got, disposition)
+			}
+
+			// Copy and Move keep it
+			if err := a.Copy(ctx, "report.pdf", "copy.pdf"); err != nil {
+				t.Fatalf("Copy() error = %v", err)
+			}
+			if err := a.Move(ctx, "copy.pdf", "moved.pdf"); err != nil {
+				t.Fatalf("Move() error = %v", err)
+			}
+			if got := stat("moved.pdf"); got != disposition {
+				t.Errorf("ContentDisposition after Copy and Move = %q, want %q", got, disposition)
+			}
+
+			// A write without one clears it, as on object stores
+			if _, err := a.Write(ctx, "report.pdf", strings.NewReader("v2")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := stat("report.pdf"); got != "" {
+				t.Errorf("ContentDisposition after overwrite = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	fstest.RunConformanceTests(t, func() filekit.FileSystem {
+		a, err := New(t.TempDir())
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return a
+	}, fstest.WithOverwriteByDefault())
+}
+
+func TestPing(t *testing.T) {
+	root := t.TempDir()
+	adapter, err := New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.Ping(context.Background()); err != nil {
+		t.Errorf("Ping = %v, want nil", err)
+	}
+
+	if err := os.RemoveAll(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.Ping(context.Background()); !errors.Is(err, filekit.ErrNotExist) {
+		t.Errorf("Ping with the root removed = %v, want ErrNotExist", err)
+	}
+}
@@ -13,6 +13,8 @@ require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/gobeaver/beaver-kit/config v0.1.0 // indirect
 	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/gobeaver/filekit => ../..
@@ -5,11 +5,10 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"io"
-	"mime"
-	"net/http"
+	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,12 +19,14 @@ import (
 
 // memoryFile represents a file stored in memory
 type memoryFile struct {
-	content     []byte
-	contentType string
-	metadata    map[string]string
-	createdAt   time.Time
-	modTime     time.Time
-	visibility  filekit.Visibility
+	content            []byte
+	contentType        string
+	contentDisposition string
+	etag               string // hex SHA-256 of content
+	metadata           map[string]string
+	createdAt          time.Time
+	modTime            time.Time
+	visibility         filekit.Visibility
 }
 
 // memoryDir represents a directory in memory
@@ -87,28 +88,36 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 	default:
 	}
 
-	path = normalizePath(path)
-
-	// Validate path
-	if !isValidPath(path) {
-		return nil, filekit.WrapPathErr("write", path, filekit.ErrNotAllowed)
+	path, err := cleanPath("write", path)
+	if err != nil {
+		return nil, err
 	}
 
+	opts := processOptions(options...)
+
 	// Read content into memory
-	data, err := io.ReadAll(content)
+	data, err := io.ReadAll(opts.TrackProgress(content))
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", path, err)
 	}
 
-	opts := processOptions(options...)
-
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	// Check if file exists and overwrite is not allowed
+	// Check preconditions, or that overwrite is allowed
+	existing, exists := a.files[path]
+	if opts.HasPreconditions() {
+		var etag string
+		if exists {
+			etag = existing.etag
+		}
+		if err := filekit.CheckPreconditions("write", path, opts, exists, etag); err != nil {
+			return nil, err
+		}
+	}
 	var existingCreatedAt time.Time
-	if existing, exists := a.files[path]; exists {
-		if !opts.Overwrite {
+	if exists {
+		if !opts.Overwrite && !opts.HasPreconditions() {
 			return nil, filekit.WrapPathErr("write", path, filekit.ErrExist)
 		}
 		// Preserve original creation time
@@ -120,7 +129,7 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 	// Check max size limit
 	newSize := a.size + int64(len(data))
 	if a.maxSize > 0 && newSize > a.maxSize {
-		return nil, filekit.WrapPathErr("write", path, filekit.ErrInvalidSize)
+		return nil, quotaError("write", path, a.maxSize)
 	}
 
 	// Ensure parent directories exist
@@ -128,8 +137,8 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 
 	// Determine content type
 	contentType := opts.ContentType
-	if contentType == "" {
-		contentType = detectContentType(path, data)
+	if !opts.ForceContentType {
+		contentType = filekit.ResolveContentType(path, opts.ContentType, data)
 	}
 
 	// Calculate checksum
@@ -146,12 +155,14 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 
 	// Store the file
 	a.files[path] = &memoryFile{
-		content:     data,
-		contentType: contentType,
-		metadata:    opts.Metadata,
-		createdAt:   createdAt,
-		modTime:     now,
-		visibility:  opts.Visibility,
+		content:            data,
+		contentType:        contentType,
+		contentDisposition: opts.ContentDisposition,
+		etag:               checksum,
+		metadata:           opts.Metadata,
+		createdAt:          createdAt,
+		modTime:            now,
+		visibility:         opts.Visibility,
 	}
 	a.size = newSize
 
@@ -162,6 +173,8 @@ func (a *Adapter) Write(ctx context.Context, path string, content io.Reader, opt
 		BytesWritten:      int64(len(data)),
 		Checksum:          checksum,
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
+		ETag:              checksum,
+		ContentType:       contentType,
 		ServerTimestamp:   now,
 	}, nil
 }
@@ -174,13 +187,19 @@ func (a *Adapter) Read(ctx context.Context, path string) (io.ReadCloser, error)
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("read", path)
+	if err != nil {
+		return nil, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
 	file, exists := a.files[path]
 	if !exists {
+		if _, isDir := a.dirs[path]; isDir {
+			return nil, filekit.WrapPathErr("read", path, filekit.ErrIsDir)
+		}
 		return nil, filekit.WrapPathErr("read", path, filekit.ErrNotExist)
 	}
 
@@ -206,13 +225,19 @@ func (a *Adapter) Delete(ctx context.Context, path string) error {
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("delete", path)
+	if err != nil {
+		return err
+	}
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	file, exists := a.files[path]
 	if !exists {
+		if _, isDir := a.dirs[path]; isDir {
+			return filekit.WrapPathErr("delete", path, filekit.ErrIsDir)
+		}
 		return filekit.WrapPathErr("delete", path, filekit.ErrNotExist)
 	}
 
@@ -233,7 +258,10 @@ func (a *Adapter) FileExists(ctx context.Context, path string) (bool, error) {
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("fileexists", path)
+	if err != nil {
+		return false, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -251,7 +279,10 @@ func (a *Adapter) DirExists(ctx context.Context, path string) (bool, error) {
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("direxists", path)
+	if err != nil {
+		return false, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -269,7 +300,10 @@ func (a *Adapter) Stat(ctx context.Context, path string) (*filekit.FileInfo, err
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("stat", path)
+	if err != nil {
+		return nil, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -281,14 +315,16 @@ func (a *Adapter) Stat(ctx context.Context, path string) (*filekit.FileInfo, err
 			createdAt = &file.createdAt
 		}
 		return &filekit.FileInfo{
-			Name:        filepath.Base(path),
-			Path:        path,
-			Size:        int64(len(file.content)),
-			ModTime:     file.modTime,
-			IsDir:       false,
-			ContentType: file.contentType,
-			Metadata:    file.metadata,
-			CreatedAt:   createdAt,
+			Name:               filepath.Base(path),
+			Path:               path,
+			Size:               int64(len(file.content)),
+			ModTime:            file.modTime,
+			IsDir:              false,
+			ContentType:        file.contentType,
+			ContentDisposition: file.contentDisposition,
+			ETag:               file.etag,
+			Metadata:           file.metadata,
+			CreatedAt:          createdAt,
 		}, nil
 	}
 
@@ -306,6 +342,18 @@ func (a *Adapter) Stat(ctx context.Context, path string) (*filekit.FileInfo, err
 	return nil, filekit.WrapPathErr("stat", path, filekit.ErrNotExist)
 }
 
+// StatMany implements filekit.CanStatMany. Lookups are cheap, so paths are
+// stat'ed sequentially.
+func (a *Adapter) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	return filekit.StatConcurrently(ctx, paths, 1, a.Stat)
+}
+
+// DeleteMany implements filekit.CanDeleteMany. Deletes only touch the
+// in-memory maps, so paths are deleted sequentially.
+func (a *Adapter) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	return filekit.DeleteConcurrently(ctx, paths, 1, a.Delete)
+}
+
 // ListContents implements filekit.FileSystem
 func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
 	select {
@@ -314,7 +362,10 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("listcontents", path)
+	if err != nil {
+		return nil, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -348,14 +399,16 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 					createdAt = &file.createdAt
 				}
 				files = append(files, filekit.FileInfo{
-					Name:        filepath.Base(filePath),
-					Path:        filePath,
-					Size:        int64(len(file.content)),
-					ModTime:     file.modTime,
-					IsDir:       false,
-					ContentType: file.contentType,
-					Metadata:    file.metadata,
-					CreatedAt:   createdAt,
+					Name:               filepath.Base(filePath),
+					Path:               filePath,
+					Size:               int64(len(file.content)),
+					ModTime:            file.modTime,
+					IsDir:              false,
+					ContentType:        file.contentType,
+					ContentDisposition: file.contentDisposition,
+					ETag:               file.etag,
+					Metadata:           file.metadata,
+					CreatedAt:          createdAt,
 				})
 			}
 		}
@@ -420,14 +473,16 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 			}
 
 			files = append(files, filekit.FileInfo{
-				Name:        childName,
-				Path:        childPath,
-				Size:        int64(len(file.content)),
-				ModTime:     file.modTime,
-				IsDir:       false,
-				ContentType: file.contentType,
-				Metadata:    file.metadata,
-				CreatedAt:   createdAt,
+				Name:               childName,
+				Path:               childPath,
+				Size:               int64(len(file.content)),
+				ModTime:            file.modTime,
+				IsDir:              false,
+				ContentType:        file.contentType,
+				ContentDisposition: file.contentDisposition,
+				ETag:               file.etag,
+				Metadata:           file.metadata,
+				CreatedAt:          createdAt,
 			})
 		}
 
@@ -477,14 +532,21 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		}
 	}
 
-	// Sort by name
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Name < files[j].Name
-	})
+	filekit.SortFiles(files, filekit.SortByName, false)
 
 	return files, nil
 }
 
+// ListPage implements filekit.CanListPage. Entries are sorted by path and
+// tokens are offsets, so a page may shift if the directory changes between calls.
+func (a *Adapter) ListPage(ctx context.Context, path string, opts filekit.ListPageOptions) (filekit.ListPage, error) {
+	files, err := a.ListContents(ctx, path, opts.Recursive)
+	if err != nil {
+		return filekit.ListPage{}, err
+	}
+	return filekit.PageEntries(path, files, opts)
+}
+
 // CreateDir implements filekit.FileSystem
 func (a *Adapter) CreateDir(ctx context.Context, path string) error {
 	select {
@@ -493,10 +555,9 @@ func (a *Adapter) CreateDir(ctx context.Context, path string) error {
 	default:
 	}
 
-	path = normalizePath(path)
-
-	if !isValidPath(path) {
-		return filekit.WrapPathErr("createdir", path, filekit.ErrNotAllowed)
+	path, err := cleanPath("createdir", path)
+	if err != nil {
+		return err
 	}
 
 	a.mu.Lock()
@@ -524,7 +585,10 @@ func (a *Adapter) DeleteDir(ctx context.Context, path string) error {
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("deletedir", path)
+	if err != nil {
+		return err
+	}
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -575,9 +639,25 @@ func (a *Adapter) DeleteDir(ctx context.Context, path string) error {
 	return nil
 }
 
-// WriteFile implements filekit.FileWriter
+// WriteFile writes a local file to the memory filesystem
 func (a *Adapter) WriteFile(ctx context.Context, path string, localPath string, options ...filekit.Option) (*filekit.WriteResult, error) {
-	return nil, filekit.WrapPathErr("writefile", localPath, filekit.ErrNotSupported)
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("writefile", localPath, err)
+	}
+	defer file.Close()
+
+	// Detect content type from the local file if not provided
+	opts := processOptions(options...)
+	if opts.ContentType == "" && !opts.ForceContentType {
+		contentType, _, err := filekit.PeekContentType(localPath, "", file)
+		if err != nil {
+			return nil, filekit.WrapPathErr("writefile", localPath, err)
+		}
+		options = append(options, filekit.WithContentType(contentType))
+	}
+
+	return a.Write(ctx, path, file, options...)
 }
 
 // Clear removes all files and directories from the memory filesystem
@@ -609,52 +689,138 @@ func (a *Adapter) FileCount() int {
 	return len(a.files)
 }
 
-// ensureParentDirs creates all parent directories for a given path
-// Must be called with lock held
-func (a *Adapter) ensureParentDirs(path string) {
-	dir := filepath.Dir(path)
-	for dir != "" && dir != "." && dir != "/" {
-		if _, exists := a.dirs[dir]; !exists {
-			a.dirs[dir] = &memoryDir{modTime: time.Now()}
+// Snapshot is a point-in-time copy of the files and directories of an
+// Adapter, taken with Snapshot and applied with Restore. It shares no memory
+// with the adapter, so one snapshot can seed any number of tests.
+// The zero Snapshot is an empty filesystem.
+type Snapshot struct {
+	files map[string]*memoryFile
+	dirs  map[string]*memoryDir
+	size  int64
+}
+
+// Snapshot returns a deep copy of the current contents of the filesystem
+func (a *Adapter) Snapshot() Snapshot {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return Snapshot{
+		files: copyFiles(a.files),
+		dirs:  copyDirs(a.dirs),
+		size:  a.size,
+	}
+}
+
+// Restore replaces the contents of the filesystem with a deep copy of s.
+// Watchers are notified of every file that was added, removed or changed.
+// MaxSize is not enforced: a snapshot larger than the limit is restored as is.
+func (a *Adapter) Restore(s Snapshot) {
+	files := copyFiles(s.files)
+	dirs := copyDirs(s.dirs)
+	for _, root := range []string{"", "/"} {
+		if _, ok := dirs[root]; !ok {
+			dirs[root] = &memoryDir{modTime: time.Now()}
 		}
-		dir = filepath.Dir(dir)
 	}
+
+	a.mu.Lock()
+	var changed []string
+	for p, old := range a.files {
+		if f, ok := files[p]; !ok || f.etag != old.etag {
+			changed = append(changed, p)
+		}
+	}
+	for p := range files {
+		if _, ok := a.files[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	a.files = files
+	a.dirs = dirs
+	a.size = s.size
+	a.mu.Unlock()
+
+	if len(changed) > 0 {
+		go func() {
+			for _, p := range changed {
+				a.notifyWatchers(p)
+			}
+		}()
+	}
+}
+
+// LoadFromFS copies every file under prefix in src into the filesystem,
+// keeping their paths, content types and metadata and replacing files that
+// already exist. An empty prefix loads all of src. Use it to populate a
+// fixture once, then Snapshot it.
+//
+// Example:
+//
+//	testdata, err := local.New("testdata")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	fixtures := memory.New()
+//	if err := fixtures.LoadFromFS(ctx, testdata, ""); err != nil {
+//	    t.Fatal(err)
+//	}
+func (a *Adapter) LoadFromFS(ctx context.Context, src filekit.FileSystem, prefix string) error {
+	return filekit.CopyTree(ctx, src, a, prefix, prefix)
 }
 
-// normalizePath normalizes a file path
-func normalizePath(path string) string {
-	path = strings.TrimPrefix(path, "/")
-	if path == "" || path == "." {
-		return ""
+// copyFiles returns a deep copy of files
+func copyFiles(files map[string]*memoryFile) map[string]*memoryFile {
+	out := make(map[string]*memoryFile, len(files))
+	for p, f := range files {
+		c := *f
+		c.content = bytes.Clone(f.content)
+		if f.metadata != nil {
+			c.metadata = make(map[string]string, len(f.metadata))
+			for k, v := range f.metadata {
+				c.metadata[k] = v
+			}
+		}
+		out[p] = &c
 	}
-	path = filepath.Clean(path)
-	return path
+	return out
 }
 
-// isValidPath checks if a path is valid (no directory traversal)
-func isValidPath(path string) bool {
-	if strings.Contains(path, "..") {
-		return false
+// copyDirs returns a deep copy of dirs
+func copyDirs(dirs map[string]*memoryDir) map[string]*memoryDir {
+	out := make(map[string]*memoryDir, len(dirs))
+	for p, d := range dirs {
+		c := *d
+		out[p] = &c
 	}
-	return true
+	return out
 }
 
-// detectContentType determines the content type of a file
-func detectContentType(path string, data []byte) string {
-	// Try extension first
-	ext := filepath.Ext(path)
-	if ext != "" {
-		if contentType := mime.TypeByExtension(ext); contentType != "" {
-			return contentType
+// ensureParentDirs creates all parent directories for a given path
+// Must be called with lock held
+func (a *Adapter) ensureParentDirs(path string) {
+	dir := filepath.Dir(path)
+	for dir != "" && dir != "." && dir != "/" {
+		if _, exists := a.dirs[dir]; !exists {
+			a.dirs[dir] = &memoryDir{modTime: time.Now()}
 		}
+		dir = filepath.Dir(dir)
 	}
+}
 
-	// Fall back to content detection
-	if len(data) > 0 {
-		return http.DetectContentType(data)
+// cleanPath normalizes path with filekit.CleanPath, rejecting paths that
+// escape the root
+func cleanPath(op, path string) (string, error) {
+	clean, err := filekit.CleanPath(path)
+	if err != nil {
+		return "", filekit.WrapPathErr(op, path, err)
 	}
+	return clean, nil
+}
 
-	return "application/octet-stream"
+// quotaError reports an operation that would take the total size past MaxSize.
+// It matches filekit.ErrQuotaExceeded and, as before, filekit.ErrNoSpace.
+func quotaError(op, path string, maxSize int64) error {
+	return filekit.WrapPath(filekit.ErrNoSpace, op, path, filekit.ErrCodeQuotaExceeded,
+		fmt.Sprintf("storage limit of %d bytes exceeded", maxSize))
 }
 
 // processOptions processes the provided options
@@ -670,6 +836,12 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker. An in-memory filesystem is always
+// reachable.
+func (a *Adapter) Ping(ctx context.Context) error {
+	return nil
+}
+
 // Copy implements filekit.CanCopy for in-memory file copying.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	select {
@@ -678,11 +850,13 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	default:
 	}
 
-	src = normalizePath(src)
-	dst = normalizePath(dst)
-
-	if !isValidPath(src) || !isValidPath(dst) {
-		return filekit.WrapPathErr("copy", src, filekit.ErrNotAllowed)
+	src, err := cleanPath("copy", src)
+	if err != nil {
+		return err
+	}
+	dst, err = cleanPath("copy", dst)
+	if err != nil {
+		return err
 	}
 
 	a.mu.Lock()
@@ -693,10 +867,13 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	if !exists {
 		return filekit.WrapPathErr("copy", src, filekit.ErrNotExist)
 	}
+	if src == dst {
+		return nil
+	}
 
 	// Check size limit
 	if a.maxSize > 0 && a.size+int64(len(srcFile.content)) > a.maxSize {
-		return filekit.WrapPathErr("copy", dst, filekit.ErrNoSpace)
+		return quotaError("copy", dst, a.maxSize)
 	}
 
 	// Ensure parent directories exist
@@ -712,11 +889,13 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	}
 
 	a.files[dst] = &memoryFile{
-		content:     content,
-		contentType: srcFile.contentType,
-		createdAt:   time.Now(),
-		modTime:     time.Now(),
-		metadata:    metadata,
+		content:            content,
+		contentType:        srcFile.contentType,
+		contentDisposition: srcFile.contentDisposition,
+		etag:               srcFile.etag,
+		createdAt:          time.Now(),
+		modTime:            time.Now(),
+		metadata:           metadata,
 	}
 	a.size += int64(len(content))
 
@@ -727,18 +906,20 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 }
 
 // Move implements filekit.CanMove for in-memory file moving.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	src = normalizePath(src)
-	dst = normalizePath(dst)
-
-	if !isValidPath(src) || !isValidPath(dst) {
-		return filekit.WrapPathErr("move", src, filekit.ErrNotAllowed)
+	src, err := cleanPath("move", src)
+	if err != nil {
+		return err
+	}
+	dst, err = cleanPath("move", dst)
+	if err != nil {
+		return err
 	}
 
 	a.mu.Lock()
@@ -749,6 +930,12 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 	if !exists {
 		return filekit.WrapPathErr("move", src, filekit.ErrNotExist)
 	}
+	if src == dst {
+		return nil // Deleting the source would delete the file
+	}
+	if _, exists := a.files[dst]; exists && filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+	}
 
 	// Ensure parent directories exist
 	a.ensureParentDirs(dst)
@@ -775,7 +962,10 @@ func (a *Adapter) Checksum(ctx context.Context, path string, algorithm filekit.C
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("checksum", path)
+	if err != nil {
+		return "", err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -801,7 +991,10 @@ func (a *Adapter) Checksums(ctx context.Context, path string, algorithms []filek
 	default:
 	}
 
-	path = normalizePath(path)
+	path, err := cleanPath("checksums", path)
+	if err != nil {
+		return nil, err
+	}
 
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -894,11 +1087,16 @@ func matchesFilter(path, filter string) bool {
 
 // Ensure Adapter implements interfaces
 var (
-	_ filekit.FileSystem  = (*Adapter)(nil)
-	_ filekit.FileReader  = (*Adapter)(nil)
-	_ filekit.FileWriter  = (*Adapter)(nil)
-	_ filekit.CanCopy     = (*Adapter)(nil)
-	_ filekit.CanMove     = (*Adapter)(nil)
-	_ filekit.CanChecksum = (*Adapter)(nil)
-	_ filekit.CanWatch    = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanStatMany       = (*Adapter)(nil)
+	_ filekit.CanDeleteMany     = (*Adapter)(nil)
+	_ filekit.CanListPage       = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
@@ -3,12 +3,17 @@ package memory
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/fstest"
 )
 
 func TestNew(t *testing.T) {
@@ -57,6 +62,26 @@ func TestWrite(t *testing.T) {
 		}
 	})
 
+	t.Run("reports content type", func(t *testing.T) {
+		a := New()
+
+		result, err := a.Write(ctx, "images/logo.png", strings.NewReader("not really a png"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.ContentType != "image/png" {
+			t.Errorf("expected ContentType=image/png, got %q", result.ContentType)
+		}
+
+		result, err = a.Write(ctx, "data.bin", strings.NewReader("{}"), filekit.WithContentType("application/json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.ContentType != "application/json" {
+			t.Errorf("expected explicit ContentType to win, got %q", result.ContentType)
+		}
+	})
+
 	t.Run("fails on path traversal", func(t *testing.T) {
 		a := New()
 
@@ -76,6 +101,24 @@ func TestWrite(t *testing.T) {
 		if err == nil {
 			t.Fatal("expected error for exceeding max size")
 		}
+		if !errors.Is(err, filekit.ErrQuotaExceeded) || !filekit.IsCode(err, filekit.ErrCodeQuotaExceeded) {
+			t.Errorf("expected quota exceeded error, got: %v", err)
+		}
+	})
+
+	t.Run("copy past max size is a quota error", func(t *testing.T) {
+		a := New(Config{MaxSize: 10})
+		if _, err := a.Write(ctx, "a.txt", strings.NewReader("123456")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err := a.Copy(ctx, "a.txt", "b.txt")
+		if !errors.Is(err, filekit.ErrQuotaExceeded) || !errors.Is(err, filekit.ErrNoSpace) {
+			t.Errorf("expected quota exceeded error, got: %v", err)
+		}
+		if exists, _ := a.FileExists(ctx, "b.txt"); exists {
+			t.Error("copy created the destination despite the quota error")
+		}
 	})
 
 	t.Run("prevents overwrite by default", func(t *testing.T) {
@@ -673,12 +716,59 @@ func TestWriteFile(t *testing.T) {
 	ctx := context.Background()
 	a := New()
 
-	t.Run("returns not supported error", func(t *testing.T) {
-		_, err := a.WriteFile(ctx, "dest.txt", "/local/file.txt")
-		if err == nil {
-			t.Fatal("expected error")
+	t.Run("writes local file", func(t *testing.T) {
+		localPath := filepath.Join(t.TempDir(), "page.html")
+		if err := os.WriteFile(localPath, []byte("<p>hello</p>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := a.WriteFile(ctx, "dest/page", localPath)
+		if err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if result.BytesWritten != 12 {
+			t.Errorf("BytesWritten = %d, want 12", result.BytesWritten)
+		}
+
+		rc, err := a.Read(ctx, "dest/page")
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		defer rc.Close()
+		data, _ := io.ReadAll(rc)
+		if string(data) != "<p>hello</p>" {
+			t.Errorf("content = %q, want %q", data, "<p>hello</p>")
+		}
+
+		info, err := a.Stat(ctx, "dest/page")
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if !strings.HasPrefix(info.ContentType, "text/html") {
+			t.Errorf("ContentType = %q, want text/html", info.ContentType)
+		}
+	})
+
+	t.Run("keeps explicit content type", func(t *testing.T) {
+		localPath := filepath.Join(t.TempDir(), "data.json")
+		if err := os.WriteFile(localPath, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := a.WriteFile(ctx, "data.bin", localPath, filekit.WithContentType("application/x-custom")); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		info, _ := a.Stat(ctx, "data.bin")
+		if info.ContentType != "application/x-custom" {
+			t.Errorf("ContentType = %q, want application/x-custom", info.ContentType)
+		}
+	})
+
+	t.Run("missing local file", func(t *testing.T) {
+		_, err := a.WriteFile(ctx, "dest.txt", filepath.Join(t.TempDir(), "missing.txt"))
+		if !errors.Is(err, filekit.ErrNotExist) {
+			t.Errorf("expected ErrNotExist, got %v", err)
 		}
-		// Memory adapter doesn't support writing from local filesystem
 	})
 }
 
@@ -702,3 +792,162 @@ func TestModTime(t *testing.T) {
 func TestImplementsInterface(t *testing.T) {
 	var _ filekit.FileSystem = (*Adapter)(nil)
 }
+
+func TestConformance(t *testing.T) {
+	fstest.RunConformanceTests(t, func() filekit.FileSystem { return New() })
+}
+
+func TestContentDisposition(t *testing.T) {
+	ctx := context.Background()
+	fs := New()
+	const disposition = `attachment; filename="report.pdf"`
+
+	if _, err := fs.Write(ctx, "report.pdf", strings.NewReader("pdf"), filekit.WithContentDisposition(disposition)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fs.Copy(ctx, "report.pdf", "copy.pdf"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	for _, p := range []string{"report.pdf", "copy.pdf"} {
+		info, err := fs.Stat(ctx, p)
+		if err != nil {
+			t.Fatalf("Stat(%s): %v", p, err)
+		}
+		if info.ContentDisposition != disposition {
+			t.Errorf("Stat(%s).ContentDisposition = %q, want %q", p, info.ContentDisposition, disposition)
+		}
+	}
+}
+
+func TestContentTypeResolution(t *testing.T) {
+	ctx := context.Background()
+	fs := New()
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		options []filekit.Option
+		want    string
+	}{
+		{"extension", "data.json", `{"ok": true}`, nil, "application/json"},
+		{"sniffed", "upload", png, nil, "image/png"},
+		{"option", "data.json", "{}", []filekit.Option{filekit.WithContentType("application/x-custom")}, "application/x-custom"},
+		{"forced", "forced.json", "{}", []filekit.Option{filekit.WithForceContentType("text/plain")}, "text/plain"},
+		{"forced empty", "empty.json", "{}", []filekit.Option{filekit.WithForceContentType("")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]filekit.Option{filekit.WithOverwrite(true)}, tt.options...)
+			result, err := fs.Write(ctx, tt.path, strings.NewReader(tt.content), options...)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			info, err := fs.Stat(ctx, tt.path)
+			if err != nil {
+				t.Fatalf("Stat: %v", err)
+			}
+			if result.ContentType != tt.want || info.ContentType != tt.want {
+				t.Errorf("ContentType = %q (result), %q (Stat); want %q", result.ContentType, info.ContentType, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	fs := New()
+	for path, content := range map[string]string{"a.txt": "a", "dir/b.txt": "b", "dir/sub/c.txt": "c"} {
+		if _, err := fs.Write(ctx, path, strings.NewReader(content), filekit.WithMetadata(map[string]string{"v": "1"})); err != nil {
+			t.Fatalf("Write(%s): %v", path, err)
+		}
+	}
+	snap := fs.Snapshot()
+
+	assertOriginal := func(t *testing.T) {
+		t.Helper()
+		for path, want := range map[string]string{"a.txt": "a", "dir/b.txt": "b", "dir/sub/c.txt": "c"} {
+			if data, err := fs.ReadAll(ctx, path); err != nil || string(data) != want {
+				t.Errorf("ReadAll(%s) = %q, %v; want %q", path, data, err, want)
+			}
+		}
+		if exists, _ := fs.FileExists(ctx, "new.txt"); exists {
+			t.Error("new.txt survived Restore")
+		}
+		if exists, _ := fs.DirExists(ctx, "dir/sub"); !exists {
+			t.Error("dir/sub missing after Restore")
+		}
+		if info, _ := fs.Stat(ctx, "a.txt"); info == nil || info.Metadata["v"] != "1" {
+			t.Errorf("Stat(a.txt) metadata = %v, want v=1", info)
+		}
+		if fs.Size() != 3 || fs.FileCount() != 3 {
+			t.Errorf("Size = %d, FileCount = %d; want 3, 3", fs.Size(), fs.FileCount())
+		}
+	}
+
+	for round := range 2 {
+		fs.Write(ctx, "a.txt", strings.NewReader("changed"), filekit.WithOverwrite(true), filekit.WithMetadata(map[string]string{"v": "2"}))
+		fs.Write(ctx, "new.txt", strings.NewReader("new"))
+		fs.DeleteDir(ctx, "dir")
+
+		fs.Restore(snap)
+		t.Run(fmt.Sprintf("restore %d", round+1), assertOriginal)
+	}
+
+	t.Run("zero snapshot", func(t *testing.T) {
+		fs.Restore(Snapshot{})
+		if fs.FileCount() != 0 || fs.Size() != 0 {
+			t.Errorf("FileCount = %d, Size = %d; want an empty filesystem", fs.FileCount(), fs.Size())
+		}
+		if _, err := fs.Write(ctx, "x/y.txt", strings.NewReader("y")); err != nil {
+			t.Errorf("Write after restoring an empty snapshot: %v", err)
+		}
+	})
+
+	t.Run("notifies watchers", func(t *testing.T) {
+		fs.Restore(snap)
+		token, err := fs.Watch(ctx, "a.txt")
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+		fs.Restore(Snapshot{})
+		deadline := time.Now().Add(time.Second)
+		for !token.HasChanged() && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if !token.HasChanged() {
+			t.Error("expected Restore to signal the watcher of a removed file")
+		}
+	})
+}
+
+func TestLoadFromFS(t *testing.T) {
+	ctx := context.Background()
+	src := New()
+	src.Write(ctx, "fixtures/users.json", strings.NewReader(`[]`), filekit.WithMetadata(map[string]string{"owner": "qa"}))
+	src.Write(ctx, "fixtures/img/logo.bin", strings.NewReader("png"), filekit.WithContentType("image/png"))
+	src.Write(ctx, "other.txt", strings.NewReader("skip me"))
+
+	fs := New()
+	fs.Write(ctx, "fixtures/users.json", strings.NewReader("stale"))
+	if err := fs.LoadFromFS(ctx, src, "fixtures"); err != nil {
+		t.Fatalf("LoadFromFS: %v", err)
+	}
+
+	if data, err := fs.ReadAll(ctx, "fixtures/users.json"); err != nil || string(data) != "[]" {
+		t.Errorf("ReadAll(users.json) = %q, %v; want %q", data, err, "[]")
+	}
+	if info, err := fs.Stat(ctx, "fixtures/users.json"); err != nil || info.Metadata["owner"] != "qa" {
+		t.Errorf("Stat(users.json) = %v, %v; want metadata owner=qa", info, err)
+	}
+	if info, err := fs.Stat(ctx, "fixtures/img/logo.bin"); err != nil || info.ContentType != "image/png" {
+		t.Errorf("Stat(logo.bin) = %v, %v; want image/png", info, err)
+	}
+	if exists, _ := fs.FileExists(ctx, "other.txt"); exists {
+		t.Error("LoadFromFS copied a file outside the prefix")
+	}
+	if err := fs.LoadFromFS(ctx, src, "missing"); !errors.Is(err, filekit.ErrNotExist) {
+		t.Errorf("LoadFromFS(missing) = %v, want ErrNotExist", err)
+	}
+}
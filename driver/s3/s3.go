@@ -7,15 +7,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/gobeaver/filekit"
 )
 
@@ -24,8 +29,33 @@ type Adapter struct {
 	client *s3.Client
 	bucket string
 	prefix string
+
+	// clientOptions are applied to a copy of the client's options in New
+	clientOptions []func(*s3.Options)
+
+	// uploads maps multipart upload IDs to their target paths
+	uploads filekit.UploadStore
+
+	// streamingThreshold switches Write to a streaming multipart upload for
+	// unknown-length readers larger than this many bytes (0 = always buffer)
+	streamingThreshold int64
+
+	// sse and sseKMSKeyID request server-side encryption for new objects
+	sse         types.ServerSideEncryption
+	sseKMSKeyID string
+
+	// deleteWait is how long Delete polls for the key to disappear (0 = no wait)
+	deleteWait time.Duration
 }
 
+const (
+	// minPartSize is the smallest part S3 accepts for all but the last part
+	minPartSize = 5 * 1024 * 1024
+
+	// maxParts is the maximum number of parts in a multipart upload
+	maxParts = 10000
+)
+
 // AdapterOption is a function that configures S3Adapter
 type AdapterOption func(*Adapter)
 
@@ -40,11 +70,98 @@ func WithPrefix(prefix string) AdapterOption {
 	}
 }
 
-// New creates a new S3 filesystem adapter
+// WithUploadStore sets the store used to persist chunked upload state.
+// With a persistent store, an upload initiated before a restart can still be
+// completed by a new adapter. Default: a process-wide in-memory store.
+func WithUploadStore(store filekit.UploadStore) AdapterOption {
+	return func(a *Adapter) {
+		a.uploads = store
+	}
+}
+
+// WithStreamingThreshold makes Write stream readers of unknown length with a
+// multipart upload once they exceed threshold bytes, instead of buffering the
+// whole body in memory. Parts of threshold bytes are uploaded as the buffer
+// fills, so memory use is bounded by one part; thresholds below 5 MiB (the S3
+// minimum part size) are raised to 5 MiB. A failed stream aborts the upload.
+// Default: 0 (unknown-length readers are buffered and sent with PutObject).
+func WithStreamingThreshold(threshold int64) AdapterOption {
+	return func(a *Adapter) {
+		a.streamingThreshold = threshold
+	}
+}
+
+// WithServerSideEncryption makes S3 encrypt every object this adapter
+// creates (Write, chunked uploads and Copy) at rest. algo is "AES256" for
+// SSE-S3 keys or "aws:kms" (or "aws:kms:dsse") for SSE-KMS, in which case
+// kmsKeyID selects the KMS key; leave it empty for the AWS managed key.
+//
+// This is independent of filekit.EncryptedFS, which encrypts on the client
+// before the content reaches S3. Stat reports the encryption of an object in
+// FileInfo.ServerSideEncryption and FileInfo.EncryptionKeyID.
+func WithServerSideEncryption(algo string, kmsKeyID string) AdapterOption {
+	return func(a *Adapter) {
+		a.sse = types.ServerSideEncryption(algo)
+		a.sseKMSKeyID = kmsKeyID
+	}
+}
+
+// WithDeleteConsistencyWait makes Delete poll HeadObject after deleting until
+// the key is reported missing, for up to d. S3 has been strongly consistent
+// since December 2020, so this only matters for S3-compatible services that
+// are not; each wait adds at least one HeadObject round trip and can stall a
+// Delete for the whole of d. A wait that runs out returns an error even
+// though the object was deleted. Default: 0 (Delete returns as soon as
+// DeleteObject succeeds).
+func WithDeleteConsistencyWait(d time.Duration) AdapterOption {
+	return func(a *Adapter) {
+		a.deleteWait = d
+	}
+}
+
+// WithPathStyle enables or disables path-style addressing
+// (https://endpoint/bucket/key instead of https://bucket.endpoint/key).
+// Most S3-compatible services such as MinIO require path-style addressing.
+func WithPathStyle(enabled bool) AdapterOption {
+	return func(a *Adapter) {
+		a.clientOptions = append(a.clientOptions, func(o *s3.Options) {
+			o.UsePathStyle = enabled
+		})
+	}
+}
+
+// WithEndpoint sets a custom base endpoint for S3-compatible services,
+// e.g. "https://nyc3.digitaloceanspaces.com" for DigitalOcean Spaces,
+// "https://<account>.r2.cloudflarestorage.com" for Cloudflare R2 or
+// "http://localhost:9000" for MinIO.
+func WithEndpoint(endpoint string) AdapterOption {
+	return func(a *Adapter) {
+		a.clientOptions = append(a.clientOptions, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+	}
+}
+
+// WithEndpointResolver sets a custom endpoint resolver for the S3 client.
+// Prefer WithEndpoint for a fixed endpoint; use a resolver when the endpoint
+// depends on request parameters such as the bucket or region.
+func WithEndpointResolver(resolver s3.EndpointResolverV2) AdapterOption {
+	return func(a *Adapter) {
+		a.clientOptions = append(a.clientOptions, func(o *s3.Options) {
+			o.EndpointResolverV2 = resolver
+		})
+	}
+}
+
+// New creates a new S3 filesystem adapter.
+//
+// Client-level options (WithPathStyle, WithEndpoint, WithEndpointResolver) are
+// applied to a copy of the client, so the client passed in is not modified.
 func New(client *s3.Client, bucket string, options ...AdapterOption) *Adapter {
 	adapter := &Adapter{
-		client: client,
-		bucket: bucket,
+		client:  client,
+		bucket:  bucket,
+		uploads: defaultUploadStore,
 	}
 
 	// Apply options
@@ -52,6 +169,10 @@ func New(client *s3.Client, bucket string, options ...AdapterOption) *Adapter {
 		option(adapter)
 	}
 
+	if client != nil && len(adapter.clientOptions) > 0 {
+		adapter.client = s3.New(client.Options(), adapter.clientOptions...)
+	}
+
 	return adapter
 }
 
@@ -59,9 +180,22 @@ func New(client *s3.Client, bucket string, options ...AdapterOption) *Adapter {
 func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
 	// Process options
 	opts := processOptions(options...)
+	if opts.IfNoneMatch != "" && opts.IfNoneMatch != "*" {
+		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeNotSupported, `S3 only supports WithIfNoneMatch("*")`)
+	}
+	class, err := storageClass(filePath, opts.StorageClass)
+	if err != nil {
+		return nil, err
+	}
 
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("write", filePath, err)
+	}
+
+	// Report progress as the content is read, here or by the SDK
+	content = opts.TrackProgress(content)
 
 	// Try to get content length and create a seekable body for streaming
 	var body io.Reader
@@ -98,8 +232,24 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		}
 		body = r
 	default:
+		if a.streamingThreshold > 0 {
+			// Buffer at most one part; larger bodies continue as a multipart upload
+			partSize := max(a.streamingThreshold, minPartSize)
+			var first bytes.Buffer
+			_, err := io.CopyN(&first, content, partSize)
+			if err == nil {
+				return a.writeMultipart(ctx, filePath, key, first.Bytes(), content, partSize, opts)
+			}
+			if err != io.EOF {
+				return nil, filekit.WrapPathErr("write", filePath, err)
+			}
+			contentLength = int64(first.Len())
+			body = bytes.NewReader(first.Bytes())
+			break
+		}
+
 		// Fallback: buffer for unknown readers (required for S3 PutObject)
-		// For large files, use ChunkedUploader interface instead
+		// For large files, use WithStreamingThreshold or ChunkedUploader instead
 		data, err := io.ReadAll(content)
 		if err != nil {
 			return nil, filekit.WrapPathErr("write", filePath, err)
@@ -108,6 +258,16 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		body = bytes.NewReader(data)
 	}
 
+	// Resolve the content type, sniffing the body if the extension is unknown
+	contentType := opts.ContentType
+	if !opts.ForceContentType {
+		var err error
+		contentType, body, err = filekit.PeekContentType(filePath, opts.ContentType, body)
+		if err != nil {
+			return nil, filekit.WrapPathErr("write", filePath, err)
+		}
+	}
+
 	// Prepare upload input
 	input := &s3.PutObjectInput{
 		Bucket:            aws.String(a.bucket),
@@ -121,9 +281,8 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		input.ContentLength = aws.Int64(contentLength)
 	}
 
-	// Set content type if provided
-	if opts.ContentType != "" {
-		input.ContentType = aws.String(opts.ContentType)
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
 	}
 
 	// Set cache control if provided
@@ -131,6 +290,11 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		input.CacheControl = aws.String(opts.CacheControl)
 	}
 
+	// Set content disposition if provided
+	if opts.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(opts.ContentDisposition)
+	}
+
 	// Set metadata if provided
 	if len(opts.Metadata) > 0 {
 		metadata := make(map[string]string, len(opts.Metadata))
@@ -147,6 +311,11 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		input.ACL = types.ObjectCannedACLPrivate
 	}
 
+	// Make the write conditional if requested
+	input.IfMatch, input.IfNoneMatch = conditions(opts)
+	input.ServerSideEncryption, input.SSEKMSKeyId = a.serverSideEncryption()
+	input.StorageClass = class
+
 	// Upload the object
 	result, err := a.client.PutObject(ctx, input)
 	if err != nil {
@@ -165,14 +334,127 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		Version:           aws.ToString(result.VersionId),
 		Checksum:          aws.ToString(result.ChecksumSHA256),
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
+		ContentType:       contentType,
 		ServerTimestamp:   time.Now(),
 	}, nil
 }
 
+// writeMultipart streams content to S3 with a multipart upload. first holds
+// the already buffered first part; the remaining content is read partSize
+// bytes at a time. The upload is aborted if reading or uploading fails.
+func (a *Adapter) writeMultipart(ctx context.Context, filePath, key string, first []byte, content io.Reader, partSize int64, opts *filekit.Options) (*filekit.WriteResult, error) {
+	contentType := opts.ContentType
+	if !opts.ForceContentType {
+		contentType = filekit.ResolveContentType(filePath, opts.ContentType, first)
+	}
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = a.serverSideEncryption()
+	input.StorageClass, _ = storageClass(filePath, opts.StorageClass) // validated by Write
+	if opts.CacheControl != "" {
+		input.CacheControl = aws.String(opts.CacheControl)
+	}
+	if opts.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(opts.ContentDisposition)
+	}
+	if len(opts.Metadata) > 0 {
+		metadata := make(map[string]string, len(opts.Metadata))
+		for k, v := range opts.Metadata {
+			metadata[k] = v
+		}
+		input.Metadata = metadata
+	}
+	if opts.Visibility == filekit.Public {
+		input.ACL = types.ObjectCannedACLPublicRead
+	} else if opts.Visibility == filekit.Private {
+		input.ACL = types.ObjectCannedACLPrivate
+	}
+
+	created, err := a.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return nil, mapS3Error("write", filePath, err)
+	}
+	uploadID := created.UploadId
+
+	abort := func(cause error) (*filekit.WriteResult, error) {
+		// Abort even if ctx was canceled so no orphaned parts are billed
+		_, _ = a.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(a.bucket),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+		return nil, cause
+	}
+
+	var parts []types.CompletedPart
+	var total int64
+	buf := make([]byte, partSize)
+	data := first
+	for {
+		if len(parts) == maxParts {
+			return abort(filekit.NewPathError("write", filePath, filekit.ErrCodeValidation,
+				fmt.Sprintf("content exceeds %d parts of %d bytes", maxParts, partSize)))
+		}
+
+		partNumber := int32(len(parts) + 1) //nolint:gosec // bounded by maxParts
+		resp, err := a.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(a.bucket),
+			Key:           aws.String(key),
+			UploadId:      uploadID,
+			PartNumber:    aws.Int32(partNumber),
+			Body:          bytes.NewReader(data),
+			ContentLength: aws.Int64(int64(len(data))),
+		})
+		if err != nil {
+			return abort(mapS3Error("write", filePath, err))
+		}
+		parts = append(parts, types.CompletedPart{ETag: resp.ETag, PartNumber: aws.Int32(partNumber)})
+		total += int64(len(data))
+
+		n, err := io.ReadFull(content, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return abort(filekit.WrapPathErr("write", filePath, err))
+		}
+		data = buf[:n]
+	}
+
+	ifMatch, ifNoneMatch := conditions(opts)
+	result, err := a.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(a.bucket),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		IfMatch:         ifMatch,
+		IfNoneMatch:     ifNoneMatch,
+	})
+	if err != nil {
+		return abort(mapS3Error("write", filePath, err))
+	}
+
+	return &filekit.WriteResult{
+		BytesWritten:    total,
+		ETag:            aws.ToString(result.ETag),
+		Version:         aws.ToString(result.VersionId),
+		ContentType:     contentType,
+		ServerTimestamp: time.Now(),
+	}, nil
+}
+
 // Read implements filekit.FileReader
 func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, error) {
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("read", filePath, err)
+	}
 
 	// Get object
 	resp, err := a.client.GetObject(ctx, &s3.GetObjectInput{
@@ -200,10 +482,13 @@ func (a *Adapter) ReadAll(ctx context.Context, filePath string) ([]byte, error)
 // Delete implements filekit.FileSystem
 func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return filekit.WrapPathErr("delete", filePath, err)
+	}
 
 	// Delete the object
-	_, err := a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	_, err = a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(key),
 	})
@@ -211,12 +496,16 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 		return mapS3Error("delete", filePath, err)
 	}
 
+	if a.deleteWait <= 0 {
+		return nil
+	}
+
 	// Wait for the object to be deleted
 	waiter := s3.NewObjectNotExistsWaiter(a.client)
 	err = waiter.Wait(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(key),
-	}, 30*time.Second)
+	}, a.deleteWait)
 	if err != nil {
 		return mapS3Error("delete", filePath, err)
 	}
@@ -227,10 +516,13 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 // FileExists implements filekit.FileReader
 func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error) {
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return false, filekit.WrapPathErr("fileexists", filePath, err)
+	}
 
 	// Check if the object exists
-	_, err := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+	_, err = a.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(key),
 	})
@@ -250,7 +542,10 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 // DirExists implements filekit.FileReader
 func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 	// Combine prefix and path
-	key := path.Join(a.prefix, dirPath)
+	key, err := a.key(dirPath)
+	if err != nil {
+		return false, filekit.WrapPathErr("direxists", dirPath, err)
+	}
 	if !strings.HasSuffix(key, "/") {
 		key += "/"
 	}
@@ -271,12 +566,16 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 // Stat implements filekit.FileReader
 func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo, error) {
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("stat", filePath, err)
+	}
 
-	// Get object metadata
+	// Get object metadata; S3 only returns stored checksums when asked to
 	resp, err := a.client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: aws.String(a.bucket),
-		Key:    aws.String(key),
+		Bucket:       aws.String(a.bucket),
+		Key:          aws.String(key),
+		ChecksumMode: types.ChecksumModeEnabled,
 	})
 	if err != nil {
 		return nil, mapS3Error("stat", filePath, err)
@@ -288,8 +587,8 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 		metadata[k] = v
 	}
 
-	// Determine if it's a directory
-	isDir := strings.HasSuffix(key, "/")
+	// Determine if it's a directory marker
+	isDir := filekit.IsDirMarker(key, aws.ToInt64(resp.ContentLength), aws.ToString(resp.ContentType))
 
 	// Determine checksum - prefer SHA256, fall back to others
 	var checksum string
@@ -309,27 +608,110 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	}
 
 	return &filekit.FileInfo{
-		Name:              filepath.Base(filePath),
-		Path:              filePath,
-		Size:              aws.ToInt64(resp.ContentLength),
-		ModTime:           aws.ToTime(resp.LastModified),
-		IsDir:             isDir,
-		ContentType:       aws.ToString(resp.ContentType),
-		Metadata:          metadata,
-		ETag:              aws.ToString(resp.ETag),
-		Version:           aws.ToString(resp.VersionId),
-		StorageClass:      string(resp.StorageClass),
-		Checksum:          checksum,
-		ChecksumAlgorithm: checksumAlgorithm,
+		Name:                 filepath.Base(filePath),
+		Path:                 filePath,
+		Size:                 aws.ToInt64(resp.ContentLength),
+		ModTime:              aws.ToTime(resp.LastModified),
+		IsDir:                isDir,
+		ContentType:          aws.ToString(resp.ContentType),
+		ContentDisposition:   aws.ToString(resp.ContentDisposition),
+		Metadata:             metadata,
+		ETag:                 aws.ToString(resp.ETag),
+		Version:              aws.ToString(resp.VersionId),
+		StorageClass:         string(resp.StorageClass),
+		Checksum:             checksum,
+		ChecksumAlgorithm:    checksumAlgorithm,
+		ServerSideEncryption: string(resp.ServerSideEncryption),
+		EncryptionKeyID:      aws.ToString(resp.SSEKMSKeyId),
 	}, nil
 }
 
+// StatMany implements filekit.CanStatMany by issuing HeadObject requests
+// concurrently, bounded by filekit.DefaultStatConcurrency.
+func (a *Adapter) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	return filekit.StatConcurrently(ctx, paths, filekit.DefaultStatConcurrency, a.Stat)
+}
+
+// maxDeleteObjects is the maximum number of keys per DeleteObjects request
+const maxDeleteObjects = 1000
+
+// DeleteMany implements filekit.CanDeleteMany using the native DeleteObjects
+// API, in batches of up to 1000 keys. Like Delete, missing keys are reported
+// as deleted because S3 deletes are idempotent.
+func (a *Adapter) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	errs := make(map[string]error)
+	deleted := make([]string, 0, len(paths))
+
+	// Deduplicate and map keys back to the caller's paths
+	unique := make([]string, 0, len(paths))
+	byKey := make(map[string]string, len(paths))
+	keys := make(map[string]string, len(paths))
+	for _, p := range paths {
+		key, err := a.key(p)
+		if err != nil {
+			errs[p] = filekit.WrapPathErr("delete", p, err)
+			continue
+		}
+		if _, dup := byKey[key]; dup {
+			continue
+		}
+		byKey[key] = p
+		keys[p] = key
+		unique = append(unique, p)
+	}
+
+	for start := 0; start < len(unique); start += maxDeleteObjects {
+		batch := unique[start:min(start+maxDeleteObjects, len(unique))]
+
+		if err := filekit.FromContext(ctx, "delete", batch[0]); err != nil {
+			for _, p := range batch {
+				errs[p] = err
+			}
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i, p := range batch {
+			objects[i] = types.ObjectIdentifier{Key: aws.String(keys[p])}
+		}
+
+		out, err := a.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(a.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			for _, p := range batch {
+				errs[p] = mapS3Error("delete", p, err)
+			}
+			continue
+		}
+
+		// Quiet mode only reports failures; every other key was deleted
+		for _, e := range out.Errors {
+			p, ok := byKey[aws.ToString(e.Key)]
+			if !ok {
+				continue
+			}
+			errs[p] = mapS3Error("delete", p, &smithy.GenericAPIError{
+				Code:    aws.ToString(e.Code),
+				Message: aws.ToString(e.Message),
+			})
+		}
+	}
+
+	for _, p := range unique {
+		if _, failed := errs[p]; !failed {
+			deleted = append(deleted, p)
+		}
+	}
+	return deleted, errs
+}
+
 // ListContents implements filekit.FileReader
 func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive bool) ([]filekit.FileInfo, error) {
-	// Prepare prefix for listing
-	listPrefix := path.Join(a.prefix, prefix)
-	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
-		listPrefix += "/"
+	listPrefix, err := a.listPrefix(prefix)
+	if err != nil {
+		return nil, filekit.WrapPathErr("listcontents", prefix, err)
 	}
 
 	var files []filekit.FileInfo
@@ -346,30 +728,7 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 			if err != nil {
 				return nil, mapS3Error("listcontents", prefix, err)
 			}
-
-			for _, obj := range page.Contents {
-				// Skip the directory itself
-				if aws.ToString(obj.Key) == listPrefix {
-					continue
-				}
-
-				relPath := strings.TrimPrefix(aws.ToString(obj.Key), a.prefix)
-				if strings.HasPrefix(relPath, "/") {
-					relPath = relPath[1:]
-				}
-
-				isDir := strings.HasSuffix(aws.ToString(obj.Key), "/")
-
-				files = append(files, filekit.FileInfo{
-					Name:         filepath.Base(relPath),
-					Path:         relPath,
-					Size:         aws.ToInt64(obj.Size),
-					ModTime:      aws.ToTime(obj.LastModified),
-					IsDir:        isDir,
-					ETag:         aws.ToString(obj.ETag),
-					StorageClass: string(obj.StorageClass),
-				})
-			}
+			files = append(files, a.listEntries(prefix, listPrefix, true, page)...)
 		}
 	} else {
 		// List objects with delimiter for immediate children only
@@ -381,63 +740,151 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 		if err != nil {
 			return nil, mapS3Error("listcontents", prefix, err)
 		}
+		files = a.listEntries(prefix, listPrefix, false, resp)
+	}
 
-		// Add directories (common prefixes)
-		for _, p := range resp.CommonPrefixes {
-			dirName := strings.TrimPrefix(aws.ToString(p.Prefix), listPrefix)
-			dirName = strings.TrimSuffix(dirName, "/")
-			if dirName == "" {
-				continue
-			}
+	filekit.SortFiles(files, filekit.SortByName, false)
+	return files, nil
+}
 
-			files = append(files, filekit.FileInfo{
-				Name:  dirName,
-				Path:  path.Join(prefix, dirName),
-				IsDir: true,
-			})
-		}
+// ListPage implements filekit.CanListPage using ListObjectsV2 continuation tokens.
+func (a *Adapter) ListPage(ctx context.Context, prefix string, opts filekit.ListPageOptions) (filekit.ListPage, error) {
+	listPrefix, err := a.listPrefix(prefix)
+	if err != nil {
+		return filekit.ListPage{}, filekit.WrapPathErr("listpage", prefix, err)
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(a.bucket),
+		Prefix:  aws.String(listPrefix),
+		MaxKeys: aws.Int32(int32(opts.PageSize())),
+	}
+	if !opts.Recursive {
+		input.Delimiter = aws.String("/")
+	}
+	if opts.ContinuationToken != "" {
+		input.ContinuationToken = aws.String(opts.ContinuationToken)
+	}
+
+	resp, err := a.client.ListObjectsV2(ctx, input)
+	if err != nil {
+		return filekit.ListPage{}, mapS3Error("listpage", prefix, err)
+	}
+
+	page := filekit.ListPage{Entries: a.listEntries(prefix, listPrefix, opts.Recursive, resp)}
+	if aws.ToBool(resp.IsTruncated) {
+		page.NextToken = aws.ToString(resp.NextContinuationToken)
+	}
+	return page, nil
+}
+
+// key returns the object key for p, normalized with filekit.CleanPath. Paths
+// that escape the prefix return filekit.ErrNotAllowed.
+func (a *Adapter) key(p string) (string, error) {
+	clean, err := filekit.CleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(a.prefix, clean), nil
+}
+
+// listPrefix returns the key prefix under which the entries of prefix are stored
+func (a *Adapter) listPrefix(prefix string) (string, error) {
+	listPrefix, err := a.key(prefix)
+	if err != nil {
+		return "", err
+	}
+	if listPrefix != "" && !strings.HasSuffix(listPrefix, "/") {
+		listPrefix += "/"
+	}
+	return listPrefix, nil
+}
+
+// listEntries converts one ListObjectsV2 response into FileInfo entries
+func (a *Adapter) listEntries(prefix, listPrefix string, recursive bool, resp *s3.ListObjectsV2Output) []filekit.FileInfo {
+	var files []filekit.FileInfo
 
-		// Add files
+	if recursive {
 		for _, obj := range resp.Contents {
 			// Skip the directory itself
 			if aws.ToString(obj.Key) == listPrefix {
 				continue
 			}
 
-			fileName := strings.TrimPrefix(aws.ToString(obj.Key), listPrefix)
-			if fileName == "" || strings.Contains(fileName, "/") {
-				continue
-			}
+			// Paths never end in "/"; only zero-byte markers are directories
+			relPath := strings.Trim(strings.TrimPrefix(aws.ToString(obj.Key), a.prefix), "/")
+			isDir := filekit.IsDirMarker(aws.ToString(obj.Key), aws.ToInt64(obj.Size), "")
 
 			files = append(files, filekit.FileInfo{
-				Name:         fileName,
-				Path:         path.Join(prefix, fileName),
+				Name:         filepath.Base(relPath),
+				Path:         relPath,
 				Size:         aws.ToInt64(obj.Size),
 				ModTime:      aws.ToTime(obj.LastModified),
-				IsDir:        false,
+				IsDir:        isDir,
 				ETag:         aws.ToString(obj.ETag),
 				StorageClass: string(obj.StorageClass),
 			})
 		}
+		return files
 	}
 
-	return files, nil
+	// Add directories (common prefixes)
+	for _, p := range resp.CommonPrefixes {
+		dirName := strings.TrimPrefix(aws.ToString(p.Prefix), listPrefix)
+		dirName = strings.TrimSuffix(dirName, "/")
+		if dirName == "" {
+			continue
+		}
+
+		files = append(files, filekit.FileInfo{
+			Name:  dirName,
+			Path:  path.Join(prefix, dirName),
+			IsDir: true,
+		})
+	}
+
+	// Add files
+	for _, obj := range resp.Contents {
+		// Skip the directory itself
+		if aws.ToString(obj.Key) == listPrefix {
+			continue
+		}
+
+		fileName := strings.TrimPrefix(aws.ToString(obj.Key), listPrefix)
+		if fileName == "" || strings.Contains(fileName, "/") {
+			continue
+		}
+
+		files = append(files, filekit.FileInfo{
+			Name:         fileName,
+			Path:         path.Join(prefix, fileName),
+			Size:         aws.ToInt64(obj.Size),
+			ModTime:      aws.ToTime(obj.LastModified),
+			IsDir:        false,
+			ETag:         aws.ToString(obj.ETag),
+			StorageClass: string(obj.StorageClass),
+		})
+	}
+	return files
 }
 
 // CreateDir implements filekit.FileSystem
 func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	// S3 doesn't have real directories, but we can create an empty object with a trailing slash
-	key := path.Join(a.prefix, dirPath)
+	key, err := a.key(dirPath)
+	if err != nil {
+		return filekit.WrapPathErr("createdir", dirPath, err)
+	}
 	if !strings.HasSuffix(key, "/") {
 		key += "/"
 	}
 
 	// Create an empty object with a trailing slash
-	_, err := a.client.PutObject(ctx, &s3.PutObjectInput{
+	_, err = a.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(a.bucket),
 		Key:         aws.String(key),
 		Body:        bytes.NewReader([]byte{}),
-		ContentType: aws.String("application/x-directory"),
+		ContentType: aws.String(filekit.DirContentType),
 	})
 	if err != nil {
 		return mapS3Error("createdir", dirPath, err)
@@ -446,75 +893,82 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 	return nil
 }
 
-// DeleteDir implements filekit.FileSystem
+// DeleteDir implements filekit.FileSystem. See DeleteDirCount.
 func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
+	_, err := a.DeleteDirCount(ctx, dirPath)
+	return err
+}
+
+// DeleteDirCount deletes every object under dirPath and returns how many were
+// deleted. The prefix is listed page by page and each page is removed with
+// one DeleteObjects request (at most 1000 keys), so directories of any size
+// are deleted completely. Keys S3 fails to delete are reported in the
+// returned error (a *filekit.MultiError when there are several) and the
+// remaining keys are still deleted. A prefix with no objects returns
+// filekit.ErrNotExist.
+func (a *Adapter) DeleteDirCount(ctx context.Context, dirPath string) (deleted int, err error) {
 	// Prepare directory path
-	dirKey := path.Join(a.prefix, dirPath)
+	dirKey, err := a.key(dirPath)
+	if err != nil {
+		return 0, filekit.WrapPathErr("deletedir", dirPath, err)
+	}
 	if !strings.HasSuffix(dirKey, "/") {
 		dirKey += "/"
 	}
 
-	// List all objects with the prefix
-	resp, err := a.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(a.bucket),
-		Prefix: aws.String(dirKey),
+	paginator := s3.NewListObjectsV2Paginator(a.client, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(a.bucket),
+		Prefix:  aws.String(dirKey),
+		MaxKeys: aws.Int32(maxDeleteObjects),
 	})
-	if err != nil {
-		return mapS3Error("deletedir", dirPath, err)
-	}
 
-	// If no objects found, the directory doesn't exist
-	if len(resp.Contents) == 0 {
-		return filekit.WrapPathErr("deletedir", dirPath, filekit.ErrNotExist)
-	}
+	errs := filekit.NewMultiError("deletedir")
+	listed := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return deleted, mapS3Error("deletedir", dirPath, err)
+		}
+		listed += len(page.Contents)
 
-	// Delete all objects with the prefix
-	objectsToDelete := make([]types.ObjectIdentifier, len(resp.Contents))
-	for i, obj := range resp.Contents {
-		objectsToDelete[i] = types.ObjectIdentifier{
-			Key: obj.Key,
+		for start := 0; start < len(page.Contents); start += maxDeleteObjects {
+			batch := page.Contents[start:min(start+maxDeleteObjects, len(page.Contents))]
+			objects := make([]types.ObjectIdentifier, len(batch))
+			for i, obj := range batch {
+				objects[i] = types.ObjectIdentifier{Key: obj.Key}
+			}
+
+			out, err := a.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(a.bucket),
+				Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				return deleted, mapS3Error("deletedir", dirPath, err)
+			}
+
+			// Quiet mode only reports failures; every other key was deleted
+			deleted += len(batch) - len(out.Errors)
+			for _, e := range out.Errors {
+				key := strings.TrimPrefix(strings.TrimPrefix(aws.ToString(e.Key), a.prefix), "/")
+				errs.Add(mapS3Error("deletedir", key, &smithy.GenericAPIError{
+					Code:    aws.ToString(e.Code),
+					Message: aws.ToString(e.Message),
+				}))
+			}
 		}
 	}
 
-	// Delete the objects
-	_, err = a.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
-		Bucket: aws.String(a.bucket),
-		Delete: &types.Delete{
-			Objects: objectsToDelete,
-			Quiet:   aws.Bool(true),
-		},
-	})
-	if err != nil {
-		return mapS3Error("deletedir", dirPath, err)
+	// If no objects found, the directory doesn't exist
+	if listed == 0 {
+		return 0, filekit.WrapPathErr("deletedir", dirPath, filekit.ErrNotExist)
 	}
 
-	return nil
+	errs.Total = listed
+	return deleted, errs.Err()
 }
 
 // WriteFile writes a local file to S3
 func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath string, options ...filekit.Option) (*filekit.WriteResult, error) {
-	// Determine content type from file extension
-	contentType := ""
-	ext := filepath.Ext(localPath)
-	if ext != "" {
-		contentType = http.DetectContentType([]byte(ext))
-	}
-
-	// Add content type option if it's not already specified
-	hasContentType := false
-	for _, option := range options {
-		_ = option
-		// This is a simplistic check that assumes that if there are any options,
-		// content type might be one of them. In a real implementation,
-		// you would need to check if content type is actually set.
-		hasContentType = true
-		break
-	}
-
-	if !hasContentType && contentType != "" {
-		options = append(options, filekit.WithContentType(contentType))
-	}
-
 	// Open the file
 	file, err := os.Open(localPath)
 	if err != nil {
@@ -522,42 +976,105 @@ func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath stri
 	}
 	defer file.Close()
 
+	// Determine content type from the local file if not provided
+	opts := processOptions(options...)
+	if opts.ContentType == "" && !opts.ForceContentType {
+		contentType, _, err := filekit.PeekContentType(localPath, "", file)
+		if err != nil {
+			return nil, filekit.WrapPathErr("writefile", localPath, err)
+		}
+		options = append(options, filekit.WithContentType(contentType))
+	}
+
 	// Write the file
 	return a.Write(ctx, destPath, file, options...)
 }
 
+// defaultUploadStore holds upload state for adapters without WithUploadStore.
+var defaultUploadStore filekit.UploadStore = filekit.NewMemoryUploadStore()
+
+// loadUpload returns the state of an upload and its object key, reporting a
+// missing upload as not found.
+func (a *Adapter) loadUpload(ctx context.Context, op, uploadID string) (*filekit.UploadState, string, error) {
+	info, err := a.uploads.Load(ctx, uploadID)
+	if err != nil {
+		if filekit.IsCode(err, filekit.ErrCodeNotFound) {
+			return nil, "", filekit.NewPathError(op, uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+		}
+		return nil, "", filekit.WrapPathErr(op, uploadID, err)
+	}
+	key, err := a.key(info.Path)
+	if err != nil {
+		return nil, "", filekit.WrapPathErr(op, info.Path, err)
+	}
+	return info, key, nil
+}
+
+// validatePartNumber checks partNumber is within S3's range of 1-10000.
+func validatePartNumber(op, uploadID string, partNumber int) error {
+	if partNumber < 1 || partNumber > 10000 {
+		return filekit.NewPathError(op, uploadID, filekit.ErrCodeValidation, fmt.Sprintf("part number must be between 1 and 10000, got %d", partNumber))
+	}
+	return nil
+}
+
 // InitiateUpload implements filekit.ChunkedUploader
 func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string, error) {
 	// Combine prefix and path
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
+	}
 
 	// Initiate multipart upload
-	resp, err := a.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+	input := &s3.CreateMultipartUploadInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(key),
-	})
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = a.serverSideEncryption()
+	resp, err := a.client.CreateMultipartUpload(ctx, input)
 	if err != nil {
 		return "", mapS3Error("initiate-upload", filePath, err)
 	}
+	uploadID := aws.ToString(resp.UploadId)
+
+	// Remember the target path; S3 requires the key for every later call
+	err = a.uploads.Save(ctx, &filekit.UploadState{
+		UploadID:  uploadID,
+		Path:      filePath,
+		PartsDir:  path.Join(a.bucket, key),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		_, _ = a.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(a.bucket),
+			Key:      aws.String(key),
+			UploadId: aws.String(uploadID),
+		})
+		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
+	}
+
+	return uploadID, nil
+}
 
-	return aws.ToString(resp.UploadId), nil
+// MinPartSize returns the smallest part S3 accepts for all but the last part.
+// filekit.UploadReader raises smaller part sizes to it.
+func (a *Adapter) MinPartSize() int64 {
+	return minPartSize
 }
 
 // UploadPart implements filekit.ChunkedUploader
 func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
-	// For simplicity, we store info about the upload in memory
-	// In a real implementation, you would likely use a database or cache
-
-	// TODO: Implement a proper way to store upload metadata
-	key := "demo" // This should be retrieved from uploadID
-
-	// Upload the part
+	if err := validatePartNumber("upload-part", uploadID, partNumber); err != nil {
+		return err
+	}
 
-	// Validate partNumber is within int32 range (AWS S3 supports 1-10000 parts)
-	if partNumber < 1 || partNumber > 10000 {
-		return fmt.Errorf("part number must be between 1 and 10000, got %d", partNumber)
+	info, key, err := a.loadUpload(ctx, "upload-part", uploadID)
+	if err != nil {
+		return err
 	}
-	_, err := a.client.UploadPart(ctx, &s3.UploadPartInput{
+
+	_, err = a.client.UploadPart(ctx, &s3.UploadPartInput{
 		Bucket:     aws.String(a.bucket),
 		Key:        aws.String(key),
 		UploadId:   aws.String(uploadID),
@@ -565,23 +1082,31 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 		Body:       bytes.NewReader(data),
 	})
 	if err != nil {
-		return mapS3Error("upload-part", key, err)
+		return mapS3Error("upload-part", info.Path, err)
 	}
 
 	return nil
 }
 
-// CompleteUpload implements filekit.ChunkedUploader
+// CompleteUpload implements filekit.ChunkedUploader.
+// The part list is read back from S3 with ListParts, so parts uploaded
+// directly by clients through PresignUploadPart are included.
 func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
-	// This is a simplified implementation
-	// In a real implementation, you would retrieve the part info from a database
+	info, key, err := a.loadUpload(ctx, "complete-upload", uploadID)
+	if err != nil {
+		return err
+	}
 
-	// TODO: Implement a proper way to retrieve parts
-	key := "demo"                    // This should be retrieved from uploadID
-	parts := []types.CompletedPart{} // These should be retrieved from a store
+	parts, err := a.listParts(ctx, key, uploadID)
+	if err != nil {
+		return mapS3Error("complete-upload", info.Path, err)
+	}
+	if len(parts) == 0 {
+		return filekit.NewPathError("complete-upload", info.Path, filekit.ErrCodeValidation, "no parts uploaded")
+	}
 
 	// Complete the multipart upload
-	_, err := a.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+	_, err = a.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
 		Bucket:   aws.String(a.bucket),
 		Key:      aws.String(key),
 		UploadId: aws.String(uploadID),
@@ -590,31 +1115,250 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 		},
 	})
 	if err != nil {
-		return mapS3Error("complete-upload", key, err)
+		return mapS3Error("complete-upload", info.Path, err)
 	}
 
-	return nil
+	return a.uploads.Delete(ctx, uploadID)
+}
+
+// listParts returns the uploaded parts of a multipart upload in part order.
+func (a *Adapter) listParts(ctx context.Context, key, uploadID string) ([]types.CompletedPart, error) {
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(a.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+
+	var parts []types.CompletedPart
+	for {
+		resp, err := a.client.ListParts(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range resp.Parts {
+			parts = append(parts, types.CompletedPart{
+				ETag:       part.ETag,
+				PartNumber: part.PartNumber,
+			})
+		}
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+		input.PartNumberMarker = resp.NextPartNumberMarker
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.ToInt32(parts[i].PartNumber) < aws.ToInt32(parts[j].PartNumber)
+	})
+	return parts, nil
 }
 
 // AbortUpload implements filekit.ChunkedUploader
 func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
-	// This is a simplified implementation
-	// In a real implementation, you would retrieve the key from a database
-
-	// TODO: Implement a proper way to retrieve the key
-	key := "demo" // This should be retrieved from uploadID
+	info, key, err := a.loadUpload(ctx, "abort-upload", uploadID)
+	if err != nil {
+		return err
+	}
 
 	// Abort the multipart upload
-	_, err := a.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+	_, err = a.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
 		Bucket:   aws.String(a.bucket),
 		Key:      aws.String(key),
 		UploadId: aws.String(uploadID),
 	})
 	if err != nil {
-		return mapS3Error("abort-upload", key, err)
+		return mapS3Error("abort-upload", info.Path, err)
 	}
 
-	return nil
+	return a.uploads.Delete(ctx, uploadID)
+}
+
+// PresignUploadPart returns a presigned URL for uploading one part of a
+// multipart upload started with InitiateUpload. Clients such as browsers PUT
+// the part's bytes to the URL and keep the ETag response header; the backend
+// then finishes the upload with CompleteUpload or PresignCompleteUpload.
+//
+// Example:
+//
+//	uploadID, _ := adapter.InitiateUpload(ctx, "videos/big.mp4")
+//	for part := 1; part <= parts; part++ {
+//	    url, _ := adapter.PresignUploadPart(ctx, uploadID, part, 15*time.Minute)
+//	    // hand url to the browser
+//	}
+//	err := adapter.CompleteUpload(ctx, uploadID)
+func (a *Adapter) PresignUploadPart(ctx context.Context, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+	if err := validatePartNumber("presign-upload-part", uploadID, partNumber); err != nil {
+		return "", err
+	}
+
+	info, key, err := a.loadUpload(ctx, "presign-upload-part", uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := s3.NewPresignClient(a.client).PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(a.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(int32(partNumber)), //nolint:gosec // validated above
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiry
+	})
+	if err != nil {
+		return "", mapS3Error("presign-upload-part", info.Path, err)
+	}
+
+	return request.URL, nil
+}
+
+// PresignCompleteUpload returns a presigned URL for completing a multipart
+// upload, for clients that finish the upload themselves. The client POSTs a
+// CompleteMultipartUpload XML document listing each part's number and ETag.
+//
+// Prefer CompleteUpload when the backend can make the call: it needs no
+// client-collected ETags and removes the upload from the upload store.
+// A client-completed upload stays in the store until GarbageCollectUploads
+// removes it.
+func (a *Adapter) PresignCompleteUpload(ctx context.Context, uploadID string, expiry time.Duration) (string, error) {
+	info, key, err := a.loadUpload(ctx, "presign-complete-upload", uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := a.objectURL(ctx, key)
+	if err != nil {
+		return "", mapS3Error("presign-complete-upload", info.Path, err)
+	}
+	query := url.Values{}
+	query.Set("uploadId", uploadID)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiry/time.Second), 10))
+	target.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), nil)
+	if err != nil {
+		return "", filekit.WrapPathErr("presign-complete-upload", info.Path, err)
+	}
+
+	opts := a.client.Options()
+	if opts.Credentials == nil {
+		return "", filekit.NewPathError("presign-complete-upload", info.Path, filekit.ErrCodeAuth, "client has no credentials")
+	}
+	creds, err := opts.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", filekit.WrapPathErr("presign-complete-upload", info.Path, err)
+	}
+
+	// S3 expects the escaped path to be signed as-is, without double escaping
+	signer := v4.NewSigner(func(o *v4.SignerOptions) {
+		o.DisableURIPathEscaping = true
+	})
+	signed, _, err := signer.PresignHTTP(ctx, creds, req, "UNSIGNED-PAYLOAD", "s3", opts.Region, time.Now())
+	if err != nil {
+		return "", filekit.WrapPathErr("presign-complete-upload", info.Path, err)
+	}
+
+	return signed, nil
+}
+
+// objectURL resolves the URL of an object using the client's endpoint settings.
+func (a *Adapter) objectURL(ctx context.Context, key string) (*url.URL, error) {
+	opts := a.client.Options()
+	resolver := opts.EndpointResolverV2
+	if resolver == nil {
+		resolver = s3.NewDefaultEndpointResolverV2()
+	}
+
+	endpoint, err := resolver.ResolveEndpoint(ctx, s3.EndpointParameters{
+		Bucket:         aws.String(a.bucket),
+		Region:         aws.String(opts.Region),
+		Endpoint:       opts.BaseEndpoint,
+		ForcePathStyle: aws.Bool(opts.UsePathStyle),
+		UseFIPS:        aws.Bool(opts.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled),
+		UseDualStack:   aws.Bool(opts.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled),
+	}.WithDefaults())
+	if err != nil {
+		return nil, err
+	}
+
+	u := endpoint.URI
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = escapeKeySegment(segment)
+	}
+	u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + strings.Join(segments, "/")
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	return &u, nil
+}
+
+// GarbageCollectUploads aborts incomplete multipart uploads under the adapter's
+// prefix that were initiated more than olderThan ago, releasing the storage held
+// by their parts. It returns the number of uploads aborted.
+//
+// Upload store entries older than olderThan whose upload S3 no longer reports
+// (e.g. uploads completed by a client through PresignCompleteUpload) are
+// removed as well; they are not counted. Only entries initiated under this
+// adapter's bucket and prefix are swept, so a store shared with other
+// adapters keeps their uploads.
+func (a *Adapter) GarbageCollectUploads(ctx context.Context, olderThan time.Duration) (reclaimed int, err error) {
+	cutoff := time.Now().Add(-olderThan)
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(a.bucket),
+	}
+	if a.prefix != "" {
+		input.Prefix = aws.String(a.prefix)
+	}
+
+	errs := filekit.NewMultiError("gc-uploads")
+	active := make(map[string]bool)
+	for {
+		resp, err := a.client.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return reclaimed, mapS3Error("gc-uploads", a.prefix, err)
+		}
+
+		for _, upload := range resp.Uploads {
+			active[aws.ToString(upload.UploadId)] = true
+			if upload.Initiated == nil || !upload.Initiated.Before(cutoff) {
+				continue
+			}
+			_, err := a.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(a.bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			if err != nil {
+				errs.Add(mapS3Error("gc-uploads", aws.ToString(upload.Key), err))
+				continue
+			}
+			errs.Add(a.uploads.Delete(ctx, aws.ToString(upload.UploadId)))
+			reclaimed++
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+		input.KeyMarker = resp.NextKeyMarker
+		input.UploadIdMarker = resp.NextUploadIdMarker
+	}
+
+	states, err := a.uploads.List(ctx)
+	if err != nil {
+		errs.Add(filekit.WrapPathErr("gc-uploads", a.prefix, err))
+		return reclaimed, errs.Err()
+	}
+	for _, state := range states {
+		if !active[state.UploadID] && state.CreatedAt.Before(cutoff) && a.ownsUpload(state) {
+			errs.Add(a.uploads.Delete(ctx, state.UploadID))
+		}
+	}
+
+	return reclaimed, errs.Err()
+}
+
+// ownsUpload reports whether an upload store entry was initiated under this
+// adapter's bucket and prefix.
+func (a *Adapter) ownsUpload(state *filekit.UploadState) bool {
+	return strings.HasPrefix(state.PartsDir, path.Join(a.bucket, a.prefix)+"/")
 }
 
 // processOptions processes the provided options
@@ -627,7 +1371,10 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 }
 
 func (a *Adapter) GeneratePresignedGetURL(ctx context.Context, filePath string, expiry time.Duration) (string, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("presign-get", filePath, err)
+	}
 
 	presignClient := s3.NewPresignClient(a.client)
 	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
@@ -645,7 +1392,10 @@ func (a *Adapter) GeneratePresignedGetURL(ctx context.Context, filePath string,
 }
 
 func (a *Adapter) GeneratePresignedPutURL(ctx context.Context, filePath string, expiry time.Duration, options ...filekit.Option) (string, error) {
-	key := path.Join(a.prefix, filePath)
+	key, err := a.key(filePath)
+	if err != nil {
+		return "", filekit.WrapPathErr("presign-put", filePath, err)
+	}
 	opts := processOptions(options...)
 
 	presignClient := s3.NewPresignClient(a.client)
@@ -679,29 +1429,88 @@ func mapS3Error(op, filePath string, err error) error {
 		return filekit.WrapPathErr(op, filePath, filekit.ErrNotExist)
 	}
 
+	// Some operations (e.g. GetObjectTagging) return NoSuchKey as a generic API error
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
+		return filekit.WrapPathErr(op, filePath, filekit.ErrNotExist)
+	}
+
+	// Failed conditional writes. ConditionalRequestConflict (409) is returned
+	// when a concurrent conditional write to the same key won the race.
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "PreconditionFailed" || apiErr.ErrorCode() == "ConditionalRequestConflict") {
+		return filekit.WrapPath(filekit.ErrPreconditionFailed, op, filePath, filekit.ErrCodePreconditionFailed, apiErr.ErrorMessage())
+	}
+
+	// GetObject on a GLACIER or DEEP_ARCHIVE object that has not been restored
+	var archived *types.InvalidObjectState
+	if errors.As(err, &archived) {
+		return filekit.WrapPath(filekit.ErrNotReadable, op, filePath, filekit.ErrCodeNotReadable,
+			fmt.Sprintf("object is in storage class %s and must be restored before it can be read", archived.StorageClass))
+	}
+
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "QuotaExceeded" {
+		return filekit.WrapPath(filekit.ErrQuotaExceeded, op, filePath, filekit.ErrCodeQuotaExceeded, apiErr.ErrorMessage())
+	}
+
 	// Map other specific errors here
 
 	return filekit.WrapPathErr(op, filePath, err)
 }
 
+// conditions returns the IfMatch and IfNoneMatch values for a conditional write
+func conditions(opts *filekit.Options) (ifMatch, ifNoneMatch *string) {
+	if opts.IfMatch != "" {
+		ifMatch = aws.String(opts.IfMatch)
+	}
+	if opts.IfNoneMatch != "" {
+		ifNoneMatch = aws.String(opts.IfNoneMatch)
+	}
+	return ifMatch, ifNoneMatch
+}
+
 // ============================================================================
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker by listing at most one key in the
+// bucket.
+func (a *Adapter) Ping(ctx context.Context) error {
+	_, err := a.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(a.bucket),
+		Prefix:  aws.String(a.prefix),
+		MaxKeys: aws.Int32(1),
+	})
+	if err != nil {
+		return mapS3Error("ping", "", err)
+	}
+	return nil
+}
+
 // Copy implements filekit.CanCopy using S3's native CopyObject API.
 // This is more efficient than download+upload for same-bucket copies.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
-	srcKey := path.Join(a.prefix, src)
-	dstKey := path.Join(a.prefix, dst)
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("copy", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("copy", dst, err)
+	}
 
-	// S3 CopyObject requires source in "bucket/key" format
-	copySource := fmt.Sprintf("%s/%s", a.bucket, srcKey)
+	// S3 rejects copying an object onto itself; there is nothing to do
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
 
-	_, err := a.client.CopyObject(ctx, &s3.CopyObjectInput{
+	input := &s3.CopyObjectInput{
 		Bucket:     aws.String(a.bucket),
-		CopySource: aws.String(copySource),
+		CopySource: aws.String(copySource(a.bucket, srcKey)),
 		Key:        aws.String(dstKey),
-	})
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = a.serverSideEncryption()
+	_, err = a.client.CopyObject(ctx, input)
 	if err != nil {
 		return mapS3Error("copy", src, err)
 	}
@@ -709,17 +1518,103 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	return nil
 }
 
+// serverSideEncryption returns the SSE settings for new objects; both are
+// zero values when WithServerSideEncryption is not set.
+func (a *Adapter) serverSideEncryption() (types.ServerSideEncryption, *string) {
+	if a.sseKMSKeyID == "" {
+		return a.sse, nil
+	}
+	return a.sse, aws.String(a.sseKMSKeyID)
+}
+
+// storageClass resolves a WithStorageClass value, matched case-insensitively,
+// to an S3 storage class. An empty class selects the bucket default.
+func storageClass(filePath, class string) (types.StorageClass, error) {
+	if class == "" {
+		return "", nil
+	}
+	for _, known := range types.StorageClass("").Values() {
+		if strings.EqualFold(string(known), class) {
+			return known, nil
+		}
+	}
+	return "", filekit.NewPathError("write", filePath, filekit.ErrCodeInvalidInput,
+		fmt.Sprintf("unknown S3 storage class %q", class))
+}
+
+// copySource builds the CopySource value for CopyObject in "bucket/key" format.
+// The key must be URL-encoded; each segment is escaped so slashes are preserved.
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = escapeKeySegment(segment)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
+// escapeKeySegment percent-encodes everything except RFC 3986 unreserved
+// characters. url.PathEscape is not strict enough: it leaves '+' untouched,
+// which S3 decodes as a space, and copies the wrong object.
+func escapeKeySegment(segment string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(segment))
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if isUnreserved(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0F])
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
+
 // Move implements filekit.CanMove using S3's CopyObject + DeleteObject.
-// S3 doesn't have a native move/rename, so this is copy+delete.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+// S3 doesn't have a native move/rename, so this is copy+delete. Moving an
+// object onto itself only checks that it exists. CopyObject has no
+// destination precondition, so filekit.WithMoveNoOverwrite checks for dst
+// with HeadObject before copying.
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
+	srcKey, err := a.key(src)
+	if err != nil {
+		return filekit.WrapPathErr("move", src, err)
+	}
+	dstKey, err := a.key(dst)
+	if err != nil {
+		return filekit.WrapPathErr("move", dst, err)
+	}
+
+	// Deleting the source would delete the destination
+	if srcKey == dstKey {
+		_, err := a.Stat(ctx, src)
+		return err
+	}
+
+	if filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		exists, err := a.FileExists(ctx, dst)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
+	}
+
 	// Copy the object
 	if err := a.Copy(ctx, src, dst); err != nil {
 		return err
 	}
 
 	// Delete the source
-	srcKey := path.Join(a.prefix, src)
-	_, err := a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	_, err = a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(srcKey),
 	})
@@ -730,6 +1625,68 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 	return nil
 }
 
+// SetTags implements filekit.CanTag using S3's PutObjectTagging API.
+// The object's existing tag set is replaced. S3 allows at most 10 tags per object.
+func (a *Adapter) SetTags(ctx context.Context, filePath string, tags map[string]string) error {
+	key, err := a.key(filePath)
+	if err != nil {
+		return filekit.WrapPathErr("set-tags", filePath, err)
+	}
+
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	sort.Slice(tagSet, func(i, j int) bool {
+		return aws.ToString(tagSet[i].Key) < aws.ToString(tagSet[j].Key)
+	})
+
+	if len(tagSet) == 0 {
+		_, err := a.client.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+			Bucket: aws.String(a.bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return mapS3Error("set-tags", filePath, err)
+		}
+		return nil
+	}
+
+	_, err = a.client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(a.bucket),
+		Key:     aws.String(key),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return mapS3Error("set-tags", filePath, err)
+	}
+
+	return nil
+}
+
+// GetTags implements filekit.CanTag using S3's GetObjectTagging API.
+func (a *Adapter) GetTags(ctx context.Context, filePath string) (map[string]string, error) {
+	key, err := a.key(filePath)
+	if err != nil {
+		return nil, filekit.WrapPathErr("get-tags", filePath, err)
+	}
+
+	resp, err := a.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, mapS3Error("get-tags", filePath, err)
+	}
+
+	tags := make(map[string]string, len(resp.TagSet))
+	for _, tag := range resp.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return tags, nil
+}
+
 // SignedURL implements filekit.CanSignURL.
 func (a *Adapter) SignedURL(ctx context.Context, filePath string, expires time.Duration) (string, error) {
 	return a.GeneratePresignedGetURL(ctx, filePath, expires)
@@ -791,8 +1748,14 @@ func (a *Adapter) Checksums(ctx context.Context, filePath string, algorithms []f
 // The filter pattern supports glob patterns like "**/*.json", "config/*".
 // Default polling interval is 30 seconds.
 func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken, error) {
+	return a.WatchMany(ctx, filter)
+}
+
+// WatchMany implements filekit.CanWatchMany. All filters share one poller,
+// so each poll lists the objects once however many filters there are.
+func (a *Adapter) WatchMany(ctx context.Context, filters ...string) (filekit.ChangeToken, error) {
 	// Get initial state of matching files
-	initialState, err := a.getMatchingFilesState(ctx, filter)
+	initialState, err := a.getMatchingFilesState(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -801,7 +1764,7 @@ func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken
 	token := filekit.NewPollingChangeToken(ctx, filekit.PollingConfig{
 		Interval: 30 * time.Second,
 		CheckFunc: func() bool {
-			currentState, err := a.getMatchingFilesState(ctx, filter)
+			currentState, err := a.getMatchingFilesState(ctx, filters)
 			if err != nil {
 				return false // Can't determine change, don't signal
 			}
@@ -819,8 +1782,8 @@ type fileState struct {
 	size    int64
 }
 
-// getMatchingFilesState returns the current state of files matching the filter
-func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map[string]fileState, error) {
+// getMatchingFilesState returns the current state of files matching any of the filters
+func (a *Adapter) getMatchingFilesState(ctx context.Context, filters []string) (map[string]fileState, error) {
 	state := make(map[string]fileState)
 
 	// List all objects with the prefix
@@ -832,7 +1795,7 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, filekit.WrapPathErr("watch", filter, err)
+			return nil, filekit.WrapPathErr("watch", strings.Join(filters, ","), err)
 		}
 
 		for _, obj := range page.Contents {
@@ -843,8 +1806,8 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 			// Remove prefix from key to get relative path
 			relPath := strings.TrimPrefix(*obj.Key, a.prefix)
 
-			// Check if path matches filter
-			if matchesGlobFilter(relPath, filter) {
+			// Check if path matches any filter
+			if matchesAnyGlobFilter(relPath, filters) {
 				var modTime time.Time
 				if obj.LastModified != nil {
 					modTime = *obj.LastModified
@@ -882,6 +1845,16 @@ func statesEqual(a, b map[string]fileState) bool {
 	return true
 }
 
+// matchesAnyGlobFilter checks if a path matches at least one of the glob patterns
+func matchesAnyGlobFilter(path string, filters []string) bool {
+	for _, filter := range filters {
+		if matchesGlobFilter(path, filter) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesGlobFilter checks if a path matches a glob pattern
 func matchesGlobFilter(filePath, filter string) bool {
 	// Handle ** patterns for recursive matching
@@ -918,12 +1891,19 @@ func matchesGlobFilter(filePath, filter string) bool {
 
 // Ensure Adapter implements interfaces
 var (
-	_ filekit.FileSystem  = (*Adapter)(nil)
-	_ filekit.FileReader  = (*Adapter)(nil)
-	_ filekit.FileWriter  = (*Adapter)(nil)
-	_ filekit.CanCopy     = (*Adapter)(nil)
-	_ filekit.CanMove     = (*Adapter)(nil)
-	_ filekit.CanSignURL  = (*Adapter)(nil)
-	_ filekit.CanChecksum = (*Adapter)(nil)
-	_ filekit.CanWatch    = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanSignURL        = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanWatchMany      = (*Adapter)(nil)
+	_ filekit.CanStatMany       = (*Adapter)(nil)
+	_ filekit.CanDeleteMany     = (*Adapter)(nil)
+	_ filekit.CanTag            = (*Adapter)(nil)
+	_ filekit.CanListPage       = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
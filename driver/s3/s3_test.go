@@ -0,0 +1,1691 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/fstest"
+)
+
+func TestCopySource(t *testing.T) {
+	// CopySource must be "bucket/key" with the key URL-encoded per segment,
+	// so that S3 decodes it back to exactly the original key.
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{"plain", "docs/report.pdf", "my-bucket/docs/report.pdf"},
+		{"space", "docs/annual report.pdf", "my-bucket/docs/annual%20report.pdf"},
+		{"unicode", "photos/café/naïve.jpg", "my-bucket/photos/caf%C3%A9/na%C3%AFve.jpg"},
+		{"space and unicode", "my files/日本.txt", "my-bucket/my%20files/%E6%97%A5%E6%9C%AC.txt"},
+		{"plus", "backups/a+b.tar", "my-bucket/backups/a%2Bb.tar"},
+		{"hash", "notes/#1.md", "my-bucket/notes/%231.md"},
+		{"reserved", "q/a=1&b@c:d$e,f;g.txt", "my-bucket/q/a%3D1%26b%40c%3Ad%24e%2Cf%3Bg.txt"},
+		{"space plus unicode", "x/ü +y.txt", "my-bucket/x/%C3%BC%20%2By.txt"},
+		{"unreserved", "a-b_c.d~e", "my-bucket/a-b_c.d~e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := copySource("my-bucket", tt.key)
+			if got != tt.want {
+				t.Errorf("copySource(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+			decoded, err := url.PathUnescape(got)
+			if err != nil {
+				t.Fatalf("copySource produced invalid escaping: %v", err)
+			}
+			if decoded != "my-bucket/"+tt.key {
+				t.Errorf("decoded CopySource = %q, want %q", decoded, "my-bucket/"+tt.key)
+			}
+		})
+	}
+}
+
+func TestNew_ClientOptions(t *testing.T) {
+	client := s3.New(s3.Options{Region: "us-east-1"})
+
+	adapter := New(client, "bucket",
+		WithPathStyle(true),
+		WithEndpoint("http://localhost:9000"),
+	)
+
+	opts := adapter.client.Options()
+	if !opts.UsePathStyle {
+		t.Error("expected path-style addressing to be enabled")
+	}
+	if opts.BaseEndpoint == nil || *opts.BaseEndpoint != "http://localhost:9000" {
+		t.Errorf("unexpected base endpoint: %v", opts.BaseEndpoint)
+	}
+
+	// The caller's client must not be modified
+	if client.Options().UsePathStyle {
+		t.Error("original client should not be modified")
+	}
+
+	// Without client options the client is used as-is
+	if plain := New(client, "bucket"); plain.client != client {
+		t.Error("expected client to be reused when no client options are set")
+	}
+}
+
+// newTaggingServer returns a fake S3 endpoint that stores tagging documents per key.
+func newTaggingServer(t *testing.T) *Adapter {
+	t.Helper()
+
+	var mu sync.Mutex
+	tagging := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; !ok {
+			http.Error(w, "unsupported", http.StatusNotImplemented)
+			return
+		}
+		key := r.URL.Path
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			tagging[key] = string(body)
+		case http.MethodDelete:
+			delete(tagging, key)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			if strings.HasSuffix(key, "/missing.txt") {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+				return
+			}
+			doc, ok := tagging[key]
+			if !ok {
+				doc = `<Tagging><TagSet></TagSet></Tagging>`
+			}
+			_, _ = io.WriteString(w, doc)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	return New(client, "bucket", WithPrefix("data"))
+}
+
+func TestTags_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTaggingServer(t)
+
+	tags := map[string]string{"retention": "7y", "team": "finance"}
+	if err := adapter.SetTags(ctx, "reports/q1.pdf", tags); err != nil {
+		t.Fatalf("SetTags failed: %v", err)
+	}
+
+	got, err := adapter.GetTags(ctx, "reports/q1.pdf")
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(got) != len(tags) {
+		t.Fatalf("got %d tags, want %d: %v", len(got), len(tags), got)
+	}
+	for k, v := range tags {
+		if got[k] != v {
+			t.Errorf("tag %q = %q, want %q", k, got[k], v)
+		}
+	}
+
+	// An empty map removes all tags
+	if err := adapter.SetTags(ctx, "reports/q1.pdf", nil); err != nil {
+		t.Fatalf("SetTags(nil) failed: %v", err)
+	}
+	got, err = adapter.GetTags(ctx, "reports/q1.pdf")
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no tags, got %v", got)
+	}
+}
+
+func TestGetTags_NotFound(t *testing.T) {
+	adapter := newTaggingServer(t)
+
+	_, err := adapter.GetTags(context.Background(), "missing.txt")
+	if !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+// newPresignAdapter returns an adapter with static credentials and a fresh upload store.
+func newPresignAdapter(t *testing.T, options ...AdapterOption) *Adapter {
+	t.Helper()
+	client := s3.New(s3.Options{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	options = append([]AdapterOption{WithPrefix("uploads"), WithUploadStore(filekit.NewMemoryUploadStore())}, options...)
+	adapter := New(client, "my-bucket", options...)
+
+	err := adapter.uploads.Save(context.Background(), &filekit.UploadState{
+		UploadID:  "upload-123",
+		Path:      "videos/big movie.mp4",
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("failed to save upload state: %v", err)
+	}
+	return adapter
+}
+
+func TestPresignUploadPart(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		options  []AdapterOption
+		wantHost string
+		wantPath string
+	}{
+		{
+			name:     "virtual-hosted",
+			wantHost: "my-bucket.s3.us-east-1.amazonaws.com",
+			wantPath: "/uploads/videos/big%20movie.mp4",
+		},
+		{
+			name:     "path-style",
+			options:  []AdapterOption{WithEndpoint("http://localhost:9000"), WithPathStyle(true)},
+			wantHost: "localhost:9000",
+			wantPath: "/my-bucket/uploads/videos/big%20movie.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newPresignAdapter(t, tt.options...)
+
+			raw, err := adapter.PresignUploadPart(ctx, "upload-123", 7, 15*time.Minute)
+			if err != nil {
+				t.Fatalf("PresignUploadPart failed: %v", err)
+			}
+			u, err := url.Parse(raw)
+			if err != nil {
+				t.Fatalf("invalid URL %q: %v", raw, err)
+			}
+
+			if u.Host != tt.wantHost {
+				t.Errorf("host = %q, want %q", u.Host, tt.wantHost)
+			}
+			if u.EscapedPath() != tt.wantPath {
+				t.Errorf("path = %q, want %q", u.EscapedPath(), tt.wantPath)
+			}
+			q := u.Query()
+			if q.Get("uploadId") != "upload-123" {
+				t.Errorf("uploadId = %q, want upload-123", q.Get("uploadId"))
+			}
+			if q.Get("partNumber") != "7" {
+				t.Errorf("partNumber = %q, want 7", q.Get("partNumber"))
+			}
+			if q.Get("X-Amz-Expires") != "900" {
+				t.Errorf("X-Amz-Expires = %q, want 900", q.Get("X-Amz-Expires"))
+			}
+			if q.Get("X-Amz-Signature") == "" {
+				t.Error("expected X-Amz-Signature in URL")
+			}
+		})
+	}
+}
+
+func TestPresignUploadPart_Errors(t *testing.T) {
+	ctx := context.Background()
+	adapter := newPresignAdapter(t)
+
+	if _, err := adapter.PresignUploadPart(ctx, "unknown", 1, time.Minute); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("unknown upload: expected not found error, got %v", err)
+	}
+	for _, part := range []int{0, 10001} {
+		if _, err := adapter.PresignUploadPart(ctx, "upload-123", part, time.Minute); !filekit.IsCode(err, filekit.ErrCodeValidation) {
+			t.Errorf("part %d: expected validation error, got %v", part, err)
+		}
+	}
+}
+
+func TestPresignCompleteUpload(t *testing.T) {
+	ctx := context.Background()
+	adapter := newPresignAdapter(t)
+
+	raw, err := adapter.PresignCompleteUpload(ctx, "upload-123", time.Hour)
+	if err != nil {
+		t.Fatalf("PresignCompleteUpload failed: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", raw, err)
+	}
+
+	if u.Host != "my-bucket.s3.us-east-1.amazonaws.com" {
+		t.Errorf("host = %q", u.Host)
+	}
+	if u.EscapedPath() != "/uploads/videos/big%20movie.mp4" {
+		t.Errorf("path = %q", u.EscapedPath())
+	}
+	q := u.Query()
+	if q.Get("uploadId") != "upload-123" {
+		t.Errorf("uploadId = %q, want upload-123", q.Get("uploadId"))
+	}
+	if q.Has("partNumber") {
+		t.Error("complete URL must not carry a partNumber")
+	}
+	if q.Get("X-Amz-Expires") != "3600" {
+		t.Errorf("X-Amz-Expires = %q, want 3600", q.Get("X-Amz-Expires"))
+	}
+	if !strings.HasPrefix(q.Get("X-Amz-Credential"), "AKIDEXAMPLE/") || q.Get("X-Amz-Signature") == "" {
+		t.Errorf("expected signed URL, got %q", raw)
+	}
+}
+
+// multipartServer is a fake S3 endpoint implementing the multipart upload API.
+type multipartServer struct {
+	mu      sync.Mutex
+	parts   map[string][]byte      // part number -> data
+	objects map[string]string      // path -> content
+	puts    int                    // PutObject calls
+	types   map[string]string      // path -> Content-Type of PutObject/CreateMultipartUpload
+	headers map[string]http.Header // path -> headers of the last PutObject/CreateMultipartUpload/CopyObject
+	aborted bool
+}
+
+func (m *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		m.types[r.URL.Path] = r.Header.Get("Content-Type")
+		m.headers[r.URL.Path] = r.Header.Clone()
+		_, _ = io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><UploadId>real-upload-id</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Has("partNumber"):
+		body, _ := io.ReadAll(r.Body)
+		m.parts[q.Get("partNumber")] = body
+		w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+	case r.Method == http.MethodGet && q.Has("uploads"):
+		_, _ = io.WriteString(w, `<ListMultipartUploadsResult><IsTruncated>false</IsTruncated></ListMultipartUploadsResult>`)
+	case r.Method == http.MethodGet && q.Has("uploadId"):
+		var b strings.Builder
+		b.WriteString(`<ListPartsResult><IsTruncated>false</IsTruncated>`)
+		for n := range m.parts {
+			b.WriteString(`<Part><PartNumber>` + n + `</PartNumber><ETag>"etag-` + n + `"</ETag></Part>`)
+		}
+		b.WriteString(`</ListPartsResult>`)
+		_, _ = io.WriteString(w, b.String())
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				ETag       string
+				PartNumber int
+			} `xml:"Part"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Parts must be listed in order with the ETags returned by UploadPart
+		var content strings.Builder
+		for i, part := range complete.Parts {
+			n := fmt.Sprint(i + 1)
+			if part.PartNumber != i+1 || part.ETag != `"etag-`+n+`"` {
+				http.Error(w, "unexpected parts: "+string(body), http.StatusBadRequest)
+				return
+			}
+			content.Write(m.parts[n])
+		}
+		m.objects[r.URL.Path] = content.String()
+		_, _ = io.WriteString(w, `<CompleteMultipartUploadResult><ETag>"final"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		m.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		m.headers[r.URL.Path] = r.Header.Clone()
+		_, _ = io.WriteString(w, `<CopyObjectResult><ETag>"copy"</ETag></CopyObjectResult>`)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		m.puts++
+		m.headers[r.URL.Path] = r.Header.Clone()
+		m.types[r.URL.Path] = r.Header.Get("Content-Type")
+		m.objects[r.URL.Path] = string(body)
+		w.Header().Set("ETag", `"put"`)
+	default:
+		http.Error(w, "unsupported", http.StatusNotImplemented)
+	}
+}
+
+// newMultipartAdapter returns an adapter backed by a fake multipart server.
+func newMultipartAdapter(t *testing.T, options ...AdapterOption) (*Adapter, *multipartServer) {
+	t.Helper()
+	fake := &multipartServer{parts: map[string][]byte{}, objects: map[string]string{}, types: map[string]string{}, headers: map[string]http.Header{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	return New(client, "bucket", options...), fake
+}
+
+func TestChunkedUpload_TracksUploadID(t *testing.T) {
+	ctx := context.Background()
+	store := filekit.NewMemoryUploadStore()
+	adapter, fake := newMultipartAdapter(t, WithPrefix("data"), WithUploadStore(store))
+
+	uploadID, err := adapter.InitiateUpload(ctx, "videos/big.mp4")
+	if err != nil {
+		t.Fatalf("InitiateUpload failed: %v", err)
+	}
+	if uploadID != "real-upload-id" {
+		t.Fatalf("uploadID = %q, want the ID returned by S3", uploadID)
+	}
+
+	// Parts uploaded out of order
+	if err := adapter.UploadPart(ctx, uploadID, 2, []byte("world")); err != nil {
+		t.Fatalf("UploadPart(2) failed: %v", err)
+	}
+	if err := adapter.UploadPart(ctx, uploadID, 1, []byte("hello ")); err != nil {
+		t.Fatalf("UploadPart(1) failed: %v", err)
+	}
+	if err := adapter.CompleteUpload(ctx, uploadID); err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+
+	if got := fake.objects["/bucket/data/videos/big.mp4"]; got != "hello world" {
+		t.Errorf("object content = %q, want %q (objects: %v)", got, "hello world", fake.objects)
+	}
+	if _, err := store.Load(ctx, uploadID); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected upload state to be removed after completion, got %v", err)
+	}
+}
+
+func TestGarbageCollectUploads_SharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := filekit.NewMemoryUploadStore()
+	videos, _ := newMultipartAdapter(t, WithPrefix("videos"), WithUploadStore(store))
+	images, _ := newMultipartAdapter(t, WithPrefix("images"), WithUploadStore(store))
+
+	uploadID, err := videos.InitiateUpload(ctx, "big.mp4")
+	if err != nil {
+		t.Fatalf("InitiateUpload failed: %v", err)
+	}
+
+	// GC on another prefix must not touch the videos adapter's upload
+	if _, err := images.GarbageCollectUploads(ctx, 0); err != nil {
+		t.Fatalf("GarbageCollectUploads(images) failed: %v", err)
+	}
+	if err := videos.UploadPart(ctx, uploadID, 1, []byte("data")); err != nil {
+		t.Errorf("UploadPart after another adapter's GC = %v, want the upload kept", err)
+	}
+
+	// GC on the owning prefix sweeps the entry S3 no longer reports
+	if _, err := videos.GarbageCollectUploads(ctx, 0); err != nil {
+		t.Fatalf("GarbageCollectUploads(videos) failed: %v", err)
+	}
+	if _, err := store.Load(ctx, uploadID); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("Load after the owner's GC = %v, want not found", err)
+	}
+}
+
+// failingReader returns its data and then a failure instead of io.EOF.
+type failingReader struct {
+	r io.Reader
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestWrite_StreamingMultipart(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t, WithStreamingThreshold(minPartSize))
+
+	// 12 MiB from a reader with no known length: parts of 5, 5 and 2 MiB
+	want := strings.Repeat("0123456789abcdef", 12*1024*1024/16)
+	content := io.MultiReader(strings.NewReader(want)) // hides the length
+
+	result, err := adapter.Write(context.Background(), "logs/big.log", content)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if result.BytesWritten != int64(len(want)) || result.ETag != `"final"` {
+		t.Errorf("result = %+v, want %d bytes with the multipart ETag", result, len(want))
+	}
+	if fake.puts != 0 {
+		t.Errorf("PutObject called %d times, want multipart only", fake.puts)
+	}
+	if len(fake.parts) != 3 || len(fake.parts["1"]) != minPartSize || len(fake.parts["3"]) != 2*1024*1024 {
+		t.Errorf("unexpected parts: %d uploaded", len(fake.parts))
+	}
+	if got := fake.objects["/bucket/logs/big.log"]; got != want {
+		t.Errorf("object has %d bytes, want %d identical bytes", len(got), len(want))
+	}
+}
+
+func TestWrite_StreamingBelowThreshold(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t, WithStreamingThreshold(minPartSize))
+
+	content := io.MultiReader(strings.NewReader("small body"))
+	if _, err := adapter.Write(context.Background(), "small.txt", content); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if fake.puts != 1 || len(fake.parts) != 0 {
+		t.Errorf("puts = %d, parts = %d; want a single PutObject", fake.puts, len(fake.parts))
+	}
+	if got := fake.objects["/bucket/small.txt"]; got != "small body" {
+		t.Errorf("object content = %q, want %q", got, "small body")
+	}
+}
+
+func TestWrite_ReportsContentType(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t)
+
+	result, err := adapter.Write(context.Background(), "images/logo.png", strings.NewReader("png bytes"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if result.ContentType != "image/png" {
+		t.Errorf("ContentType = %q, want image/png", result.ContentType)
+	}
+	if got := fake.types["/bucket/images/logo.png"]; got != "image/png" {
+		t.Errorf("uploaded Content-Type = %q, want image/png", got)
+	}
+}
+
+func TestWrite_StreamingAbortsOnReadError(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t, WithStreamingThreshold(minPartSize))
+
+	content := &failingReader{r: strings.NewReader(strings.Repeat("x", minPartSize+1024))}
+	if _, err := adapter.Write(context.Background(), "broken.bin", content); err == nil {
+		t.Fatal("expected Write to fail")
+	}
+	if !fake.aborted {
+		t.Error("expected the multipart upload to be aborted")
+	}
+	if _, ok := fake.objects["/bucket/broken.bin"]; ok {
+		t.Error("no object should be created after a failed stream")
+	}
+}
+
+func TestDeleteDirCount(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		deletes int
+		objects = map[string]bool{"data/other.txt": true, "data/big/locked.txt": true}
+	)
+	for i := 0; i < 2500; i++ {
+		objects[fmt.Sprintf("data/big/%04d.txt", i)] = true
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && q.Get("list-type") == "2":
+			maxKeys := 1000
+			if v := q.Get("max-keys"); v != "" {
+				_, _ = fmt.Sscan(v, &maxKeys)
+			}
+			var keys []string
+			for key := range objects {
+				if strings.HasPrefix(key, q.Get("prefix")) && key > q.Get("continuation-token") {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+			truncated := len(keys) > maxKeys
+			keys = keys[:min(len(keys), maxKeys)]
+
+			var result strings.Builder
+			result.WriteString(`<ListBucketResult>`)
+			for _, key := range keys {
+				fmt.Fprintf(&result, `<Contents><Key>%s</Key><Size>1</Size></Contents>`, key)
+			}
+			fmt.Fprintf(&result, `<IsTruncated>%t</IsTruncated>`, truncated)
+			if truncated {
+				fmt.Fprintf(&result, `<NextContinuationToken>%s</NextContinuationToken>`, keys[len(keys)-1])
+			}
+			result.WriteString(`</ListBucketResult>`)
+			_, _ = io.WriteString(w, result.String())
+		case r.Method == http.MethodPost && q.Has("delete"):
+			var req struct {
+				Objects []struct {
+					Key string `xml:"Key"`
+				} `xml:"Object"`
+			}
+			if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("invalid DeleteObjects body: %v", err)
+			}
+			deletes++
+			if len(req.Objects) > maxDeleteObjects {
+				t.Errorf("batch of %d keys exceeds %d", len(req.Objects), maxDeleteObjects)
+			}
+
+			var result strings.Builder
+			result.WriteString(`<DeleteResult>`)
+			for _, obj := range req.Objects {
+				if obj.Key == "data/big/locked.txt" {
+					result.WriteString(`<Error><Key>data/big/locked.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+					continue
+				}
+				delete(objects, obj.Key)
+			}
+			result.WriteString(`</DeleteResult>`)
+			_, _ = io.WriteString(w, result.String())
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	deleted, err := adapter.DeleteDirCount(ctx, "big")
+	if deleted != 2500 {
+		t.Errorf("deleted = %d, want 2500", deleted)
+	}
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") || !strings.Contains(err.Error(), "big/locked.txt") {
+		t.Errorf("err = %v, want AccessDenied for big/locked.txt", err)
+	}
+	if deletes != 3 {
+		t.Errorf("DeleteObjects called %d times, want 3 batches", deletes)
+	}
+	if len(objects) != 2 || !objects["data/other.txt"] || !objects["data/big/locked.txt"] {
+		t.Errorf("%d objects remain, want only data/other.txt and data/big/locked.txt", len(objects))
+	}
+
+	if err := adapter.DeleteDir(ctx, "missing"); !errors.Is(err, filekit.ErrNotExist) {
+		t.Errorf("DeleteDir(missing) = %v, want ErrNotExist", err)
+	}
+}
+
+func TestDeleteMany(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		objects  = map[string]bool{"data/a.txt": true, "data/b.txt": true, "data/locked.txt": true}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !r.URL.Query().Has("delete") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Objects []struct {
+				Key string `xml:"Key"`
+			} `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid DeleteObjects body: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if len(req.Objects) > maxDeleteObjects {
+			t.Errorf("batch of %d keys exceeds %d", len(req.Objects), maxDeleteObjects)
+		}
+
+		var result strings.Builder
+		result.WriteString(`<DeleteResult>`)
+		for _, obj := range req.Objects {
+			if obj.Key == "data/locked.txt" {
+				result.WriteString(`<Error><Key>data/locked.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+				continue
+			}
+			delete(objects, obj.Key)
+		}
+		result.WriteString(`</DeleteResult>`)
+		_, _ = io.WriteString(w, result.String())
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+
+	paths := []string{"a.txt", "missing.txt", "locked.txt", "b.txt", "a.txt"}
+	for i := 0; i < 1500; i++ {
+		paths = append(paths, fmt.Sprintf("bulk/%d.txt", i))
+	}
+
+	deleted, errs := adapter.DeleteMany(context.Background(), paths)
+
+	if requests != 2 {
+		t.Errorf("DeleteObjects called %d times, want 2 batches", requests)
+	}
+	if len(errs) != 1 || errs["locked.txt"] == nil {
+		t.Fatalf("errs = %v, want only locked.txt", errs)
+	}
+	if !strings.Contains(errs["locked.txt"].Error(), "AccessDenied") {
+		t.Errorf("locked.txt error = %v, want AccessDenied", errs["locked.txt"])
+	}
+	// Missing keys count as deleted; duplicates are reported once
+	if len(deleted) != 1503 {
+		t.Fatalf("got %d deleted paths, want 1503", len(deleted))
+	}
+	if want := []string{"a.txt", "missing.txt", "b.txt"}; !reflect.DeepEqual(deleted[:3], want) {
+		t.Errorf("deleted[:3] = %v, want %v", deleted[:3], want)
+	}
+	if len(objects) != 1 || !objects["data/locked.txt"] {
+		t.Errorf("remaining objects = %v, want only data/locked.txt", objects)
+	}
+}
+
+// newListingServer returns a fake S3 endpoint serving ListObjectsV2 over a
+// fixed, sorted set of keys. Continuation tokens are the last key or common
+// prefix returned.
+func newListingServer(t *testing.T, keys []string) *Adapter {
+	t.Helper()
+	return newSizedListingServer(t, keys, nil)
+}
+
+// newSizedListingServer is newListingServer with the size of some keys set;
+// other keys are 1 byte.
+func newSizedListingServer(t *testing.T, keys []string, sizes map[string]int) *Adapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodGet || q.Get("list-type") != "2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prefix, delimiter, token := q.Get("prefix"), q.Get("delimiter"), q.Get("continuation-token")
+		maxKeys := 1000
+		if v := q.Get("max-keys"); v != "" {
+			_, _ = fmt.Sscan(v, &maxKeys)
+		}
+
+		var result strings.Builder
+		result.WriteString(`<ListBucketResult>`)
+		count, last, seen := 0, "", map[string]bool{}
+		truncated := false
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) || key <= token || (strings.HasSuffix(token, "/") && strings.HasPrefix(key, token)) {
+				continue
+			}
+			entry, rolledUp := key, false
+			if delimiter != "" {
+				if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+					entry, rolledUp = key[:len(prefix)+i+1], true
+				}
+			}
+			if seen[entry] {
+				continue
+			}
+			if count == maxKeys {
+				truncated = true
+				break
+			}
+			seen[entry] = true
+			count++
+			last = entry
+			if rolledUp {
+				fmt.Fprintf(&result, `<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>`, entry)
+			} else {
+				size, ok := sizes[key]
+				if !ok {
+					size = 1
+				}
+				fmt.Fprintf(&result, `<Contents><Key>%s</Key><Size>%d</Size></Contents>`, key, size)
+			}
+		}
+		fmt.Fprintf(&result, `<IsTruncated>%t</IsTruncated>`, truncated)
+		if truncated {
+			fmt.Fprintf(&result, `<NextContinuationToken>%s</NextContinuationToken>`, last)
+		}
+		result.WriteString(`</ListBucketResult>`)
+		_, _ = io.WriteString(w, result.String())
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	return New(client, "bucket", WithPrefix("data"))
+}
+
+func TestListPage(t *testing.T) {
+	var keys []string
+	for i := 0; i < 7; i++ {
+		keys = append(keys, fmt.Sprintf("data/docs/%d.txt", i))
+	}
+	keys = append(keys, "data/docs/sub/a.txt", "data/docs/sub/b.txt")
+	adapter := newListingServer(t, keys)
+
+	collect := func(t *testing.T, recursive bool) ([]string, int) {
+		t.Helper()
+		var paths []string
+		pages := 0
+		opts := filekit.ListPageOptions{MaxResults: 3, Recursive: recursive}
+		for {
+			page, err := adapter.ListPage(context.Background(), "docs", opts)
+			if err != nil {
+				t.Fatalf("ListPage: %v", err)
+			}
+			if len(page.Entries) > 3 {
+				t.Fatalf("page has %d entries, want at most 3", len(page.Entries))
+			}
+			pages++
+			for _, e := range page.Entries {
+				paths = append(paths, e.Path)
+			}
+			if page.NextToken == "" {
+				return paths, pages
+			}
+			opts.ContinuationToken = page.NextToken
+		}
+	}
+
+	t.Run("non-recursive", func(t *testing.T) {
+		paths, pages := collect(t, false)
+		// Common prefixes are listed before the files of the same page
+		slices.Sort(paths)
+		want := []string{"docs/0.txt", "docs/1.txt", "docs/2.txt", "docs/3.txt", "docs/4.txt", "docs/5.txt", "docs/6.txt", "docs/sub"}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("paths = %v, want %v", paths, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		paths, pages := collect(t, true)
+		if len(paths) != len(keys) || paths[len(paths)-1] != "docs/sub/b.txt" {
+			t.Errorf("paths = %v, want all %d keys", paths, len(keys))
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	})
+}
+
+func TestListContents_SortedByName(t *testing.T) {
+	adapter := newListingServer(t, []string{"data/docs/a.txt", "data/docs/b/x.txt", "data/docs/c.txt"})
+
+	entries, err := adapter.ListContents(context.Background(), "docs", false)
+	if err != nil {
+		t.Fatalf("ListContents: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	// Common prefixes arrive separately from the files
+	if want := []string{"a.txt", "b", "c.txt"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
+
+func TestListContents_DirectoryMarkers(t *testing.T) {
+	keys := []string{
+		"data/docs/",       // marker of the listed directory
+		"data/docs/a.txt",  // empty file
+		"data/docs/empty/", // marker of an empty directory
+		"data/docs/odd/",   // a file whose key ends in "/"
+		"data/docs/sub/",   // marker of a directory with files
+		"data/docs/sub/b.txt",
+	}
+	adapter := newSizedListingServer(t, keys, map[string]int{
+		"data/docs/":       0,
+		"data/docs/a.txt":  0,
+		"data/docs/empty/": 0,
+		"data/docs/odd/":   5,
+		"data/docs/sub/":   0,
+	})
+
+	classify := func(t *testing.T, recursive bool) map[string]bool {
+		t.Helper()
+		entries, err := adapter.ListContents(context.Background(), "docs", recursive)
+		if err != nil {
+			t.Fatalf("ListContents: %v", err)
+		}
+		got := make(map[string]bool)
+		for _, e := range entries {
+			if strings.HasSuffix(e.Path, "/") {
+				t.Errorf("path %q ends in /", e.Path)
+			}
+			got[e.Path] = e.IsDir
+		}
+		return got
+	}
+
+	t.Run("recursive", func(t *testing.T) {
+		want := map[string]bool{
+			"docs/a.txt":     false,
+			"docs/empty":     true,
+			"docs/odd":       false,
+			"docs/sub":       true,
+			"docs/sub/b.txt": false,
+		}
+		if got := classify(t, true); !reflect.DeepEqual(got, want) {
+			t.Errorf("IsDir by path = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("non-recursive", func(t *testing.T) {
+		// Delimited listings roll every key ending in "/" into a common prefix
+		want := map[string]bool{
+			"docs/a.txt": false,
+			"docs/empty": true,
+			"docs/odd":   true,
+			"docs/sub":   true,
+		}
+		if got := classify(t, false); !reflect.DeepEqual(got, want) {
+			t.Errorf("IsDir by path = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("tree stats", func(t *testing.T) {
+		stats, err := filekit.TreeStats(context.Background(), adapter, "docs")
+		if err != nil {
+			t.Fatalf("TreeStats: %v", err)
+		}
+		if stats.Files != 3 {
+			t.Errorf("Files = %d, want 3 (markers are not files)", stats.Files)
+		}
+	})
+}
+
+func TestStat_ReportsETagAndChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/bucket/data/report.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if got := r.Header.Get("X-Amz-Checksum-Mode"); got != "ENABLED" {
+			t.Errorf("x-amz-checksum-mode = %q, want ENABLED", got)
+		}
+		w.Header().Set("ETag", `"9b2cf535f27731c974343645a3985328"`)
+		w.Header().Set("X-Amz-Checksum-Sha256", "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=")
+		w.Header().Set("Content-Length", "4")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+
+	info, err := adapter.Stat(context.Background(), "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ETag != `"9b2cf535f27731c974343645a3985328"` {
+		t.Errorf("ETag = %q", info.ETag)
+	}
+	if info.Checksum != "n4bQgYhMfWWaL+qgxVrQFaO/TxsrC4Is0V1sFbDwCgg=" || info.ChecksumAlgorithm != filekit.ChecksumSHA256 {
+		t.Errorf("Checksum = %q (%s), want the SHA-256 header", info.Checksum, info.ChecksumAlgorithm)
+	}
+}
+
+func TestWrite_Conditional(t *testing.T) {
+	var mu sync.Mutex
+	etag, version := `"v0"`, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/bucket/data/config.json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		ifMatch, ifNoneMatch := r.Header.Get("If-Match"), r.Header.Get("If-None-Match")
+		if (ifMatch != "" && ifMatch != etag) || ifNoneMatch == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			fmt.Fprint(w, `<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`)
+			return
+		}
+		version++
+		etag = fmt.Sprintf(`"v%d"`, version)
+		w.Header().Set("ETag", etag)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	t.Run("one of two writers with the same base ETag wins", func(t *testing.T) {
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = adapter.Write(ctx, "config.json", strings.NewReader("update"), filekit.WithIfMatch(`"v0"`))
+			}()
+		}
+		wg.Wait()
+
+		succeeded, failed := 0, 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, filekit.ErrPreconditionFailed) && filekit.IsCode(err, filekit.ErrCodePreconditionFailed):
+				failed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if succeeded != 1 || failed != 1 {
+			t.Errorf("got %d successes and %d precondition failures, want 1 and 1", succeeded, failed)
+		}
+	})
+
+	t.Run("create only", func(t *testing.T) {
+		_, err := adapter.Write(ctx, "config.json", strings.NewReader("new"), filekit.WithIfNoneMatch("*"))
+		if !errors.Is(err, filekit.ErrPreconditionFailed) {
+			t.Errorf("Write with IfNoneMatch(*) = %v, want ErrPreconditionFailed", err)
+		}
+	})
+
+	t.Run("IfNoneMatch with an ETag is not supported", func(t *testing.T) {
+		_, err := adapter.Write(ctx, "config.json", strings.NewReader("new"), filekit.WithIfNoneMatch(`"v1"`))
+		if !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+			t.Errorf("Write with IfNoneMatch(etag) = %v, want ErrCodeNotSupported", err)
+		}
+	})
+}
+
+func TestMove_DestinationParentExists(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]bool{"data/report.txt": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch {
+		case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+			source, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+			if !objects[strings.TrimPrefix(source, "bucket/")] {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+				return
+			}
+			objects[key] = true
+			fmt.Fprint(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+			prefix := r.URL.Query().Get("prefix")
+			var result strings.Builder
+			result.WriteString(`<ListBucketResult>`)
+			for k := range objects {
+				if strings.HasPrefix(k, prefix) {
+					fmt.Fprintf(&result, `<Contents><Key>%s</Key><Size>4</Size></Contents>`, k)
+					break
+				}
+			}
+			result.WriteString(`</ListBucketResult>`)
+			fmt.Fprint(w, result.String())
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "archive/2024/report.txt"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	for _, dir := range []string{"archive", "archive/2024"} {
+		if exists, err := adapter.DirExists(ctx, dir); err != nil || !exists {
+			t.Errorf("DirExists(%s) after move = %v, %v; want true", dir, exists, err)
+		}
+	}
+	if objects["data/report.txt"] || !objects["data/archive/2024/report.txt"] {
+		t.Errorf("objects after move = %v", objects)
+	}
+}
+
+func TestMove_NoOverwrite(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]bool{"data/a.txt": true, "data/b.txt": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch {
+		case r.Method == http.MethodHead && objects[key]:
+			w.Header().Set("Content-Length", "4")
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+			objects[key] = true
+			fmt.Fprint(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+		t.Fatalf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if !objects["data/a.txt"] {
+		t.Error("refused move deleted the source")
+	}
+
+	if err := adapter.Move(ctx, "a.txt", "c.txt", filekit.WithMoveNoOverwrite()); err != nil {
+		t.Fatalf("Move(no overwrite) onto new key: %v", err)
+	}
+	if err := adapter.Move(ctx, "c.txt", "b.txt"); err != nil {
+		t.Fatalf("Move onto existing: %v", err)
+	}
+	if objects["data/a.txt"] || objects["data/c.txt"] || !objects["data/b.txt"] {
+		t.Errorf("objects after moves = %v", objects)
+	}
+}
+
+func TestMove_SamePathKeepsObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/bucket/data/report.txt":
+			w.Header().Set("Content-Length", "4")
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	if err := adapter.Move(ctx, "report.txt", "./report.txt"); err != nil {
+		t.Errorf("Move onto itself: %v", err)
+	}
+	if err := adapter.Copy(ctx, "report.txt", "report.txt"); err != nil {
+		t.Errorf("Copy onto itself: %v", err)
+	}
+	if err := adapter.Move(ctx, "missing.txt", "missing.txt"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("Move(missing) onto itself = %v, want ErrCodeNotFound", err)
+	}
+}
+
+func TestWatchMany_SharesOneListing(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("list-type") != "2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lists.Add(1)
+		_, _ = io.WriteString(w, `<ListBucketResult>`+
+			`<Contents><Key>data/app.json</Key><Size>1</Size></Contents>`+
+			`<Contents><Key>data/app.yaml</Key><Size>1</Size></Contents>`+
+			`<Contents><Key>data/notes.txt</Key><Size>1</Size></Contents>`+
+			`<IsTruncated>false</IsTruncated></ListBucketResult>`)
+	}))
+	t.Cleanup(server.Close)
+	adapter := New(s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}), "bucket", WithPrefix("data"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := adapter.WatchMany(ctx, "*.json", "*.yaml", "certs/*"); err != nil {
+		t.Fatalf("WatchMany: %v", err)
+	}
+	if n := lists.Load(); n != 1 {
+		t.Errorf("WatchMany listed the bucket %d times for three filters, want 1", n)
+	}
+
+	state, err := adapter.getMatchingFilesState(ctx, []string{"*.json", "*.yaml", "certs/*"})
+	if err != nil {
+		t.Fatalf("getMatchingFilesState: %v", err)
+	}
+	if len(state) != 2 || state["app.json"].path == "" || state["app.yaml"].path == "" {
+		t.Errorf("state = %v, want app.json and app.yaml", state)
+	}
+}
+
+func TestWrite_QuotaExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<Error><Code>QuotaExceeded</Code><Message>The storage quota for this account has been exceeded</Message></Error>`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket")
+
+	_, err := adapter.Write(context.Background(), "a.txt", strings.NewReader("data"), filekit.WithOverwrite(true))
+	if !errors.Is(err, filekit.ErrQuotaExceeded) || !filekit.IsCode(err, filekit.ErrCodeQuotaExceeded) {
+		t.Errorf("Write over quota = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	var bucketExists atomic.Bool
+	bucketExists.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodGet || q.Get("list-type") != "2" || q.Get("max-keys") != "1" || q.Get("prefix") != "data/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if !bucketExists.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>`)
+			return
+		}
+		_, _ = io.WriteString(w, `<ListBucketResult><IsTruncated>false</IsTruncated></ListBucketResult>`)
+	}))
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+
+	if err := adapter.Ping(context.Background()); err != nil {
+		t.Errorf("Ping = %v, want nil", err)
+	}
+
+	bucketExists.Store(false)
+	var fkErr *filekit.FileError
+	if err := adapter.Ping(context.Background()); !errors.As(err, &fkErr) || fkErr.Op != "ping" {
+		t.Errorf("Ping on a missing bucket = %v, want a ping error", err)
+	}
+}
+
+func TestUploadReader_EnforcesMinPartSize(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t)
+
+	want := strings.Repeat("0123456789abcdef", 12*1024*1024/16)
+	err := filekit.UploadReader(context.Background(), adapter, "logs/big.log", strings.NewReader(want),
+		filekit.WithPartSize(1024*1024), filekit.WithUploadConcurrency(3))
+	if err != nil {
+		t.Fatalf("UploadReader: %v", err)
+	}
+	// 1 MiB parts are raised to 5 MiB: parts of 5, 5 and 2 MiB
+	if len(fake.parts) != 3 || len(fake.parts["1"]) != minPartSize || len(fake.parts["3"]) != 2*1024*1024 {
+		t.Errorf("unexpected parts: %d uploaded", len(fake.parts))
+	}
+	if got := fake.objects["/bucket/logs/big.log"]; got != want {
+		t.Errorf("object has %d bytes, want %d identical bytes", len(got), len(want))
+	}
+}
+
+func TestWrite_Progress(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t)
+
+	content := strings.Repeat("p", 200*1024)
+	var calls int
+	var last, total int64
+	_, err := adapter.Write(context.Background(), "p.txt", strings.NewReader(content),
+		filekit.WithWriteProgress(func(transferred, size int64) {
+			if transferred < last {
+				t.Errorf("progress went back from %d to %d", last, transferred)
+			}
+			calls++
+			last, total = transferred, size
+		}))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if last != int64(len(content)) || total != int64(len(content)) || calls > 5 {
+		t.Errorf("%d calls, final (%d, %d); want at most 5 calls ending at (%d, %d)", calls, last, total, len(content), len(content))
+	}
+	if fake.objects["/bucket/p.txt"] != content {
+		t.Error("object content mismatch")
+	}
+}
+
+func TestWithServerSideEncryption(t *testing.T) {
+	ctx := context.Background()
+	adapter, fake := newMultipartAdapter(t, WithServerSideEncryption("aws:kms", "key-123"), WithStreamingThreshold(minPartSize))
+
+	assertSSE := func(t *testing.T, objectPath string) {
+		t.Helper()
+		h, ok := fake.headers[objectPath]
+		if !ok {
+			t.Fatalf("no request recorded for %s", objectPath)
+		}
+		if got := h.Get("X-Amz-Server-Side-Encryption"); got != "aws:kms" {
+			t.Errorf("%s: x-amz-server-side-encryption = %q, want aws:kms", objectPath, got)
+		}
+		if got := h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"); got != "key-123" {
+			t.Errorf("%s: KMS key ID = %q, want key-123", objectPath, got)
+		}
+	}
+
+	if _, err := adapter.Write(ctx, "small.txt", strings.NewReader("secret")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	assertSSE(t, "/bucket/small.txt")
+
+	big := io.MultiReader(strings.NewReader(strings.Repeat("x", minPartSize+10)))
+	if _, err := adapter.Write(ctx, "big.bin", big); err != nil {
+		t.Fatalf("streaming Write: %v", err)
+	}
+	assertSSE(t, "/bucket/big.bin")
+
+	if _, err := adapter.InitiateUpload(ctx, "chunked.bin"); err != nil {
+		t.Fatalf("InitiateUpload: %v", err)
+	}
+	assertSSE(t, "/bucket/chunked.bin")
+
+	if err := adapter.Copy(ctx, "small.txt", "copy.txt"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	assertSSE(t, "/bucket/copy.txt")
+
+	// SSE-S3 sends no key ID; the default sends no SSE headers at all
+	plain, plainFake := newMultipartAdapter(t, WithServerSideEncryption("AES256", ""))
+	if _, err := plain.Write(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatal(err)
+	}
+	if h := plainFake.headers["/bucket/a.txt"]; h.Get("X-Amz-Server-Side-Encryption") != "AES256" || h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id") != "" {
+		t.Errorf("SSE-S3 headers = %v", h)
+	}
+	none, noneFake := newMultipartAdapter(t)
+	if _, err := none.Write(ctx, "b.txt", strings.NewReader("b")); err != nil {
+		t.Fatal(err)
+	}
+	if h := noneFake.headers["/bucket/b.txt"]; h.Get("X-Amz-Server-Side-Encryption") != "" {
+		t.Errorf("unexpected SSE header without WithServerSideEncryption: %q", h.Get("X-Amz-Server-Side-Encryption"))
+	}
+}
+
+func TestStat_ReportsServerSideEncryption(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "5")
+		w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+		w.Header().Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", "arn:aws:kms:us-east-1:111122223333:key/abc")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	info, err := New(client, "bucket").Stat(context.Background(), "secret.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ServerSideEncryption != "aws:kms" || info.EncryptionKeyID != "arn:aws:kms:us-east-1:111122223333:key/abc" {
+		t.Errorf("ServerSideEncryption = %q, EncryptionKeyID = %q", info.ServerSideEncryption, info.EncryptionKeyID)
+	}
+}
+
+func TestWithStorageClass(t *testing.T) {
+	ctx := context.Background()
+	adapter, fake := newMultipartAdapter(t, WithStreamingThreshold(minPartSize))
+
+	if _, err := adapter.Write(ctx, "cold.txt", strings.NewReader("cold"), filekit.WithStorageClass("standard_ia")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := fake.headers["/bucket/cold.txt"].Get("X-Amz-Storage-Class"); got != "STANDARD_IA" {
+		t.Errorf("PutObject x-amz-storage-class = %q, want STANDARD_IA", got)
+	}
+
+	big := io.MultiReader(strings.NewReader(strings.Repeat("x", minPartSize+10)))
+	if _, err := adapter.Write(ctx, "archive.bin", big, filekit.WithStorageClass("GLACIER")); err != nil {
+		t.Fatalf("streaming Write: %v", err)
+	}
+	if got := fake.headers["/bucket/archive.bin"].Get("X-Amz-Storage-Class"); got != "GLACIER" {
+		t.Errorf("CreateMultipartUpload x-amz-storage-class = %q, want GLACIER", got)
+	}
+
+	puts := fake.puts
+	_, err := adapter.Write(ctx, "bad.txt", strings.NewReader("x"), filekit.WithStorageClass("Archive"))
+	if !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("Write with unknown class = %v, want ErrCodeInvalidInput", err)
+	}
+	if fake.puts != puts {
+		t.Error("Write with unknown class reached S3")
+	}
+}
+
+func TestRead_ArchivedObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<Error><Code>InvalidObjectState</Code><Message>The operation is not valid for the object's storage class</Message><StorageClass>GLACIER</StorageClass></Error>`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+
+	_, err := New(client, "bucket").Read(context.Background(), "backups/2024.tar")
+	if !errors.Is(err, filekit.ErrNotReadable) || !filekit.IsCode(err, filekit.ErrCodeNotReadable) {
+		t.Fatalf("Read of archived object = %v, want ErrNotReadable", err)
+	}
+	if !strings.Contains(err.Error(), "GLACIER") {
+		t.Errorf("error %q does not name the storage class", err)
+	}
+}
+
+func TestWithContentDisposition(t *testing.T) {
+	ctx := context.Background()
+	adapter, fake := newMultipartAdapter(t, WithStreamingThreshold(minPartSize))
+	const disposition = `attachment; filename="report.pdf"`
+
+	if _, err := adapter.Write(ctx, "report.pdf", strings.NewReader("pdf"), filekit.WithContentDisposition(disposition)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := fake.headers["/bucket/report.pdf"].Get("Content-Disposition"); got != disposition {
+		t.Errorf("PutObject Content-Disposition = %q, want %q", got, disposition)
+	}
+
+	big := io.MultiReader(strings.NewReader(strings.Repeat("x", minPartSize+10)))
+	if _, err := adapter.Write(ctx, "big.pdf", big, filekit.WithContentDisposition(disposition)); err != nil {
+		t.Fatalf("streaming Write: %v", err)
+	}
+	if got := fake.headers["/bucket/big.pdf"].Get("Content-Disposition"); got != disposition {
+		t.Errorf("CreateMultipartUpload Content-Disposition = %q, want %q", got, disposition)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "3")
+		w.Header().Set("Content-Disposition", disposition)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	info, err := New(client, "bucket").Stat(ctx, "report.pdf")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ContentDisposition != disposition {
+		t.Errorf("Stat ContentDisposition = %q, want %q", info.ContentDisposition, disposition)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	adapter, fake := newMultipartAdapter(t)
+
+	localPath := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(localPath, []byte("png bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := adapter.WriteFile(context.Background(), "images/logo", localPath)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if result.BytesWritten != int64(len("png bytes")) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("png bytes"))
+	}
+	if got := fake.objects["/bucket/images/logo"]; got != "png bytes" {
+		t.Errorf("object content = %q, want %q", got, "png bytes")
+	}
+	if got := fake.types["/bucket/images/logo"]; got != "image/png" {
+		t.Errorf("uploaded Content-Type = %q, want image/png from the local file name", got)
+	}
+}
+
+func TestDelete_ConsistencyWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []AdapterOption
+		wantHeads bool
+	}{
+		{"default does not wait", nil, false},
+		{"opt-in polls until missing", []AdapterOption{WithDeleteConsistencyWait(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := map[string]int{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				calls[r.Method]++
+				mu.Unlock()
+				switch r.Method {
+				case http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				case http.MethodHead:
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					http.Error(w, "unexpected request", http.StatusBadRequest)
+				}
+			}))
+			t.Cleanup(server.Close)
+			client := s3.New(s3.Options{
+				Region:       "us-east-1",
+				BaseEndpoint: aws.String(server.URL),
+				UsePathStyle: true,
+				Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+			})
+			adapter := New(client, "bucket", tt.options...)
+
+			if err := adapter.Delete(context.Background(), "a.txt"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if calls[http.MethodDelete] != 1 {
+				t.Errorf("DeleteObject called %d times, want 1", calls[http.MethodDelete])
+			}
+			if got := calls[http.MethodHead] > 0; got != tt.wantHeads {
+				t.Errorf("HeadObject called %d times, want polling = %v", calls[http.MethodHead], tt.wantHeads)
+			}
+		})
+	}
+}
+
+func TestWrite_ContentTypeResolution(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	tests := []struct {
+		name    string
+		path    string
+		content io.Reader
+		options []filekit.Option
+		want    string
+	}{
+		{"extension", "data.json", strings.NewReader(`{"ok": true}`), nil, "application/json"},
+		{"sniffed seekable", "upload-a", strings.NewReader(png), nil, "image/png"},
+		{"sniffed stream", "upload-b", io.MultiReader(strings.NewReader(png)), nil, "image/png"},
+		{"forced", "forced.json", strings.NewReader("{}"), []filekit.Option{filekit.WithForceContentType("text/plain")}, "text/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, fake := newMultipartAdapter(t)
+			result, err := adapter.Write(context.Background(), tt.path, tt.content, tt.options...)
+			if err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if result.ContentType != tt.want {
+				t.Errorf("result ContentType = %q, want %q", result.ContentType, tt.want)
+			}
+			if got := fake.types["/bucket/"+tt.path]; got != tt.want {
+				t.Errorf("uploaded Content-Type = %q, want %q", got, tt.want)
+			}
+			if strings.HasPrefix(tt.path, "upload") && fake.objects["/bucket/"+tt.path] != png {
+				t.Errorf("object content = %q, want the full content after sniffing", fake.objects["/bucket/"+tt.path])
+			}
+		})
+	}
+}
+
+func TestPathEscapes(t *testing.T) {
+	var requests []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	adapter := New(client, "bucket", WithPrefix("data"))
+	ctx := context.Background()
+
+	t.Run("rejected before any request", func(t *testing.T) {
+		fstest.TestPathEscapes(t, adapter)
+
+		p := "../other/x"
+		checks := map[string]error{}
+		_, checks["InitiateUpload"] = adapter.InitiateUpload(ctx, p)
+		_, checks["GeneratePresignedGetURL"] = adapter.GeneratePresignedGetURL(ctx, p, time.Minute)
+		_, checks["GeneratePresignedPutURL"] = adapter.GeneratePresignedPutURL(ctx, p, time.Minute)
+		checks["SetTags"] = adapter.SetTags(ctx, p, map[string]string{"k": "v"})
+		_, checks["GetTags"] = adapter.GetTags(ctx, p)
+		_, errs := adapter.DeleteMany(ctx, []string{p})
+		checks["DeleteMany"] = errs[p]
+		for op, err := range checks {
+			if !filekit.IsCode(err, filekit.ErrCodePermission) {
+				t.Errorf("%s(%q) = %v, want ErrCodePermission", op, p, err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(requests) > 0 {
+			t.Errorf("escaping paths reached the server: %v", requests)
+		}
+	})
+
+	t.Run("keys are cleaned", func(t *testing.T) {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		if _, err := adapter.FileExists(ctx, "/a//b/../c.txt"); err != nil {
+			t.Fatalf("FileExists failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if want := []string{"HEAD /bucket/data/a/c.txt"}; !slices.Equal(requests, want) {
+			t.Errorf("requests = %v, want %v", requests, want)
+		}
+	})
+}
@@ -16,6 +16,8 @@ require (
 	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
 	github.com/kr/fs v0.1.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/gobeaver/filekit => ../..
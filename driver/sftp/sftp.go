@@ -8,8 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"mime"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -17,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gobeaver/filekit"
@@ -27,12 +26,23 @@ import (
 // Adapter provides an SFTP implementation of filekit.FileSystem
 type Adapter struct {
 	mu       sync.Mutex
-	client   *sftp.Client
-	sshConn  *ssh.Client
+	conns    []*connection
+	next     atomic.Uint64
+	dial     func() (*ssh.Client, *sftp.Client, error)
+	uploads  filekit.UploadStore
 	basePath string
 	config   Config
 }
 
+// connection is a single SSH+SFTP session in the adapter's pool.
+// The SFTP client is safe for concurrent use; mu only guards health checks
+// and reconnection of this session.
+type connection struct {
+	mu      sync.Mutex
+	sshConn *ssh.Client
+	client  *sftp.Client
+}
+
 // Config holds SFTP connection configuration
 type Config struct {
 	Host       string
@@ -41,31 +51,55 @@ type Config struct {
 	Password   string
 	PrivateKey []byte // PEM encoded private key
 	BasePath   string
+
+	// OperationTimeout applies a deadline to every operation, including the
+	// data transfer of Write, Copy and streams returned by Read.
+	// Zero means no timeout beyond the caller's context.
+	OperationTimeout time.Duration
+
+	// MaxConnections is the number of independent SSH+SFTP sessions to keep
+	// open. Operations are spread across sessions, so concurrent transfers
+	// are not limited to a single connection's throughput.
+	// Default: 1
+	MaxConnections int
 }
 
 // AdapterOption is a function that configures SFTP Adapter
 type AdapterOption func(*Adapter)
 
-// WithBasePath sets the base path for SFTP operations
+// WithBasePath sets the base path for SFTP operations. Every path is resolved
+// relative to it (or to the login directory if it is empty), a leading slash
+// included; paths that escape it are rejected with ErrCodePermission.
 func WithBasePath(basePath string) AdapterOption {
 	return func(a *Adapter) {
 		a.basePath = basePath
 	}
 }
 
+// WithUploadStore sets the store used to persist chunked upload state.
+// With a persistent store, an upload initiated before a restart can still be
+// completed by a new adapter. Default: a process-wide in-memory store.
+func WithUploadStore(store filekit.UploadStore) AdapterOption {
+	return func(a *Adapter) {
+		a.uploads = store
+	}
+}
+
 // New creates a new SFTP filesystem adapter
 func New(cfg Config, options ...AdapterOption) (*Adapter, error) {
 	adapter := &Adapter{
 		config:   cfg,
 		basePath: cfg.BasePath,
+		uploads:  defaultUploadStore,
 	}
+	adapter.dial = adapter.dialSSH
 
 	// Apply options
 	for _, option := range options {
 		option(adapter)
 	}
 
-	// Establish connection
+	// Establish connections
 	if err := adapter.connect(); err != nil {
 		return nil, err
 	}
@@ -73,11 +107,34 @@ func New(cfg Config, options ...AdapterOption) (*Adapter, error) {
 	return adapter, nil
 }
 
-// connect establishes SSH and SFTP connections
+// connect establishes all pooled SSH and SFTP connections
 func (a *Adapter) connect() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	size := a.config.MaxConnections
+	if size < 1 {
+		size = 1
+	}
+
+	conns := make([]*connection, 0, size)
+	for i := 0; i < size; i++ {
+		sshConn, client, err := a.dial()
+		if err != nil {
+			for _, c := range conns {
+				_ = c.close()
+			}
+			return err
+		}
+		conns = append(conns, &connection{sshConn: sshConn, client: client})
+	}
+
+	a.conns = conns
+	return nil
+}
+
+// dialSSH opens a new SSH connection and SFTP session
+func (a *Adapter) dialSSH() (*ssh.Client, *sftp.Client, error) {
 	// Build SSH config
 	sshConfig := &ssh.ClientConfig{
 		User:            a.config.Username,
@@ -88,7 +145,7 @@ func (a *Adapter) connect() error {
 	if len(a.config.PrivateKey) > 0 {
 		signer, err := ssh.ParsePrivateKey(a.config.PrivateKey)
 		if err != nil {
-			return fmt.Errorf("failed to parse private key: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse private key: %w", err)
 		}
 		sshConfig.Auth = append(sshConfig.Auth, ssh.PublicKeys(signer))
 	}
@@ -98,7 +155,7 @@ func (a *Adapter) connect() error {
 	}
 
 	if len(sshConfig.Auth) == 0 {
-		return fmt.Errorf("no authentication method provided")
+		return nil, nil, fmt.Errorf("no authentication method provided")
 	}
 
 	// Connect to SSH
@@ -110,93 +167,143 @@ func (a *Adapter) connect() error {
 	addr := fmt.Sprintf("%s:%d", a.config.Host, port)
 	sshConn, err := ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
-		return fmt.Errorf("failed to connect to SSH: %w", err)
+		return nil, nil, fmt.Errorf("failed to connect to SSH: %w", err)
 	}
 
 	// Create SFTP client
 	sftpClient, err := sftp.NewClient(sshConn)
 	if err != nil {
 		sshConn.Close()
-		return fmt.Errorf("failed to create SFTP client: %w", err)
+		return nil, nil, fmt.Errorf("failed to create SFTP client: %w", err)
 	}
 
-	a.sshConn = sshConn
-	a.client = sftpClient
-
-	return nil
+	return sshConn, sftpClient, nil
 }
 
-// Close closes the SFTP and SSH connections
+// Close closes all SFTP and SSH connections
 func (a *Adapter) Close() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	var errs []error
-
-	if a.client != nil {
-		if err := a.client.Close(); err != nil {
+	for _, c := range a.conns {
+		if err := c.close(); err != nil {
 			errs = append(errs, err)
 		}
-		a.client = nil
+	}
+	a.conns = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("errors closing connections: %v", errs)
 	}
 
-	if a.sshConn != nil {
-		if err := a.sshConn.Close(); err != nil {
+	return nil
+}
+
+// close closes the session's SFTP client and SSH connection
+func (c *connection) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+
+	if c.client != nil {
+		if err := c.client.Close(); err != nil {
 			errs = append(errs, err)
 		}
-		a.sshConn = nil
+		c.client = nil
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("errors closing connections: %v", errs)
+	if c.sshConn != nil {
+		if err := c.sshConn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		c.sshConn = nil
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// ensureConnected ensures the SFTP connection is alive
-func (a *Adapter) ensureConnected() error {
+// acquire returns a healthy SFTP client from the pool.
+// Sessions are handed out round-robin; a session that fails its health check
+// is reconnected before use.
+func (a *Adapter) acquire() (*sftp.Client, error) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	if a.client == nil {
+	if len(a.conns) == 0 {
 		a.mu.Unlock()
-		err := a.connect()
+		if err := a.connect(); err != nil {
+			return nil, err
+		}
 		a.mu.Lock()
-		return err
 	}
+	c := a.conns[a.next.Add(1)%uint64(len(a.conns))]
+	a.mu.Unlock()
 
-	// Test connection with a simple operation
-	_, err := a.client.Getwd()
-	if err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		// Test connection with a simple operation
+		if _, err := c.client.Getwd(); err == nil {
+			return c.client, nil
+		}
 		// Connection lost, reconnect
-		a.client = nil
-		a.sshConn = nil
-		a.mu.Unlock()
-		err = a.connect()
-		a.mu.Lock()
-		return err
+		_ = c.client.Close()
+		if c.sshConn != nil {
+			_ = c.sshConn.Close()
+		}
+		c.client, c.sshConn = nil, nil
 	}
 
-	return nil
+	sshConn, client, err := a.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.sshConn, c.client = sshConn, client
+
+	return client, nil
 }
 
-// fullPath returns the full path combining base path and relative path
+// operationContext derives the context for a single operation, applying
+// Config.OperationTimeout when set.
+func (a *Adapter) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.config.OperationTimeout > 0 {
+		return context.WithTimeout(ctx, a.config.OperationTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// fullPath returns the full path combining base path and relative path,
+// normalized with filekit.CleanPath. Paths that escape the base path are only
+// cleaned lexically, so isPathSafe rejects them.
 func (a *Adapter) fullPath(relativePath string) string {
-	cleanPath := path.Clean(relativePath)
+	cleanPath, err := filekit.CleanPath(relativePath)
+	if err != nil {
+		cleanPath = path.Clean(strings.TrimLeft(relativePath, "/"))
+	}
+	if cleanPath == "" {
+		cleanPath = "."
+	}
 	if a.basePath == "" {
 		return cleanPath
 	}
 	return path.Join(a.basePath, cleanPath)
 }
 
-// isPathSafe checks if the path is safe (doesn't escape base path)
+// isPathSafe checks that relativePath stays inside the base path: it must not
+// climb out with "..", and once joined to the base path it must be the base
+// path itself or below it. A shared string prefix is not enough: /base-evil
+// is outside a base path of /base.
 func (a *Adapter) isPathSafe(relativePath string) bool {
-	fullPath := a.fullPath(relativePath)
-	if a.basePath == "" {
-		return !strings.HasPrefix(fullPath, "..")
+	if _, err := filekit.CleanPath(relativePath); err != nil {
+		return false
+	}
+	base := filepath.FromSlash(path.Clean(a.basePath))
+	rel, err := filepath.Rel(base, filepath.FromSlash(a.fullPath(relativePath)))
+	if err != nil {
+		return false
 	}
-	return strings.HasPrefix(fullPath, a.basePath)
+	return !filepath.IsAbs(rel) && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 // Write implements filekit.FileWriter
@@ -211,16 +318,23 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodePermission, "path not allowed")
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
 
+	ctx, cancel := a.operationContext(ctx)
+	defer cancel()
+
 	opts := processOptions(options...)
+	if opts.HasPreconditions() {
+		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeNotSupported, "conditional writes are not supported")
+	}
 	fullPath := a.fullPath(filePath)
 
 	// Check if file exists and overwrite is not allowed
 	if !opts.Overwrite {
-		_, err := a.client.Stat(fullPath)
+		_, err := client.Stat(fullPath)
 		if err == nil {
 			return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeAlreadyExists, "file already exists")
 		}
@@ -231,12 +345,12 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 
 	// Ensure parent directory exists
 	dir := path.Dir(fullPath)
-	if err := a.client.MkdirAll(dir); err != nil {
+	if err := client.MkdirAll(dir); err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
 
 	// Create file
-	file, err := a.client.Create(fullPath)
+	file, err := client.Create(fullPath)
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
@@ -244,7 +358,7 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 
 	// Copy content while calculating checksum
 	hash := sha256.New()
-	written, err := io.Copy(io.MultiWriter(file, hash), content)
+	written, err := io.Copy(io.MultiWriter(file, hash), &contextReader{ctx: ctx, r: opts.TrackProgress(content)})
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
@@ -254,13 +368,13 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 	if opts.Visibility == filekit.Public {
 		perm = 0644
 	}
-	if err := a.client.Chmod(fullPath, perm); err != nil {
+	if err := client.Chmod(fullPath, perm); err != nil {
 		// Non-fatal error, log and continue
 		_ = err
 	}
 
 	// Get file info for timestamp
-	stat, err := a.client.Stat(fullPath)
+	stat, err := client.Stat(fullPath)
 	var modTime time.Time
 	if err == nil {
 		modTime = stat.ModTime()
@@ -272,6 +386,7 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		BytesWritten:      written,
 		Checksum:          hex.EncodeToString(hash.Sum(nil)),
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
+		ContentType:       filekit.ResolveContentType(filePath, "", nil),
 		ServerTimestamp:   modTime,
 	}, nil
 }
@@ -288,18 +403,25 @@ func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, err
 		return nil, filekit.WrapPathErr("read", filePath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return nil, filekit.WrapPathErr("read", filePath, err)
 	}
 
 	fullPath := a.fullPath(filePath)
 
-	file, err := a.client.Open(fullPath)
+	file, err := client.Open(fullPath)
 	if err != nil {
 		return nil, mapSFTPError("read", filePath, err)
 	}
 
-	return file, nil
+	// The stream stays bound to ctx (and OperationTimeout) until it is closed
+	ctx, cancel := a.operationContext(ctx)
+	return &contextReadCloser{
+		contextReader: contextReader{ctx: ctx, r: file},
+		closer:        file,
+		cancel:        cancel,
+	}, nil
 }
 
 // ReadAll implements filekit.FileReader
@@ -324,13 +446,14 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 		return filekit.WrapPathErr("delete", filePath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("delete", filePath, err)
 	}
 
 	fullPath := a.fullPath(filePath)
 
-	if err := a.client.Remove(fullPath); err != nil {
+	if err := client.Remove(fullPath); err != nil {
 		return mapSFTPError("delete", filePath, err)
 	}
 
@@ -349,13 +472,14 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 		return false, filekit.WrapPathErr("fileexists", filePath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return false, filekit.WrapPathErr("fileexists", filePath, err)
 	}
 
 	fullPath := a.fullPath(filePath)
 
-	info, err := a.client.Stat(fullPath)
+	info, err := client.Stat(fullPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -383,13 +507,14 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 		return false, filekit.WrapPathErr("direxists", dirPath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return false, filekit.WrapPathErr("direxists", dirPath, err)
 	}
 
 	fullPath := a.fullPath(dirPath)
 
-	info, err := a.client.Stat(fullPath)
+	info, err := client.Stat(fullPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -413,13 +538,14 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 		return nil, filekit.WrapPathErr("stat", filePath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return nil, filekit.WrapPathErr("stat", filePath, err)
 	}
 
 	fullPath := a.fullPath(filePath)
 
-	info, err := a.client.Stat(fullPath)
+	info, err := client.Stat(fullPath)
 	if err != nil {
 		return nil, mapSFTPError("stat", filePath, err)
 	}
@@ -427,7 +553,7 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	// Get content type from extension
 	contentType := ""
 	if !info.IsDir() {
-		contentType = detectContentType(filePath)
+		contentType = filekit.ResolveContentType(filePath, "", nil)
 	}
 
 	// Extract owner information from SFTP FileStat
@@ -463,14 +589,15 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		return nil, filekit.WrapPathErr("listcontents", path, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return nil, filekit.WrapPathErr("listcontents", path, err)
 	}
 
 	fullPath := a.fullPath(path)
 
 	// Check if path exists and is a directory
-	info, err := a.client.Stat(fullPath)
+	info, err := client.Stat(fullPath)
 	if err != nil {
 		return nil, mapSFTPError("listcontents", path, err)
 	}
@@ -482,13 +609,13 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 
 	if recursive {
 		// Recursive listing
-		err = a.listRecursive(fullPath, path, &files)
+		err = a.listRecursive(client, fullPath, path, &files)
 		if err != nil {
 			return nil, mapSFTPError("listcontents", path, err)
 		}
 	} else {
 		// Non-recursive listing
-		entries, err := a.client.ReadDir(fullPath)
+		entries, err := client.ReadDir(fullPath)
 		if err != nil {
 			return nil, mapSFTPError("listcontents", path, err)
 		}
@@ -497,7 +624,7 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		for _, entry := range entries {
 			contentType := ""
 			if !entry.IsDir() {
-				contentType = detectContentType(entry.Name())
+				contentType = filekit.ResolveContentType(entry.Name(), "", nil)
 			}
 
 			// Extract owner information from SFTP FileStat
@@ -522,12 +649,13 @@ func (a *Adapter) ListContents(ctx context.Context, path string, recursive bool)
 		}
 	}
 
+	filekit.SortFiles(files, filekit.SortByName, false)
 	return files, nil
 }
 
 // listRecursive recursively lists all files in a directory
-func (a *Adapter) listRecursive(fullPath, relPath string, results *[]filekit.FileInfo) error {
-	entries, err := a.client.ReadDir(fullPath)
+func (a *Adapter) listRecursive(client *sftp.Client, fullPath, relPath string, results *[]filekit.FileInfo) error {
+	entries, err := client.ReadDir(fullPath)
 	if err != nil {
 		return err
 	}
@@ -538,7 +666,7 @@ func (a *Adapter) listRecursive(fullPath, relPath string, results *[]filekit.Fil
 
 		contentType := ""
 		if !entry.IsDir() {
-			contentType = detectContentType(entry.Name())
+			contentType = filekit.ResolveContentType(entry.Name(), "", nil)
 		}
 
 		// Extract owner information from SFTP FileStat
@@ -562,7 +690,7 @@ func (a *Adapter) listRecursive(fullPath, relPath string, results *[]filekit.Fil
 		})
 
 		if entry.IsDir() {
-			if err := a.listRecursive(entryFullPath, entryRelPath, results); err != nil {
+			if err := a.listRecursive(client, entryFullPath, entryRelPath, results); err != nil {
 				return err
 			}
 		}
@@ -583,13 +711,14 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 		return filekit.WrapPathErr("createdir", dirPath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("createdir", dirPath, err)
 	}
 
 	fullPath := a.fullPath(dirPath)
 
-	if err := a.client.MkdirAll(fullPath); err != nil {
+	if err := client.MkdirAll(fullPath); err != nil {
 		return mapSFTPError("createdir", dirPath, err)
 	}
 
@@ -608,14 +737,15 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 		return filekit.WrapPathErr("deletedir", dirPath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("deletedir", dirPath, err)
 	}
 
 	fullPath := a.fullPath(dirPath)
 
 	// Check if directory exists
-	info, err := a.client.Stat(fullPath)
+	info, err := client.Stat(fullPath)
 	if err != nil {
 		return mapSFTPError("deletedir", dirPath, err)
 	}
@@ -624,7 +754,7 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 	}
 
 	// Recursively delete directory contents
-	if err := a.removeAll(fullPath); err != nil {
+	if err := a.removeAll(client, fullPath); err != nil {
 		return mapSFTPError("deletedir", dirPath, err)
 	}
 
@@ -632,8 +762,8 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 }
 
 // removeAll recursively removes a directory and its contents
-func (a *Adapter) removeAll(dirPath string) error {
-	entries, err := a.client.ReadDir(dirPath)
+func (a *Adapter) removeAll(client *sftp.Client, dirPath string) error {
+	entries, err := client.ReadDir(dirPath)
 	if err != nil {
 		return err
 	}
@@ -641,17 +771,17 @@ func (a *Adapter) removeAll(dirPath string) error {
 	for _, entry := range entries {
 		entryPath := path.Join(dirPath, entry.Name())
 		if entry.IsDir() {
-			if err := a.removeAll(entryPath); err != nil {
+			if err := a.removeAll(client, entryPath); err != nil {
 				return err
 			}
 		} else {
-			if err := a.client.Remove(entryPath); err != nil {
+			if err := client.Remove(entryPath); err != nil {
 				return err
 			}
 		}
 	}
 
-	return a.client.RemoveDirectory(dirPath)
+	return client.RemoveDirectory(dirPath)
 }
 
 // WriteFile implements filekit.FileWriter
@@ -662,10 +792,13 @@ func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath stri
 	}
 	defer file.Close()
 
-	// Detect content type if not provided
+	// Detect content type from the local file if not provided
 	opts := processOptions(options...)
-	if opts.ContentType == "" {
-		contentType := detectContentType(localPath)
+	if opts.ContentType == "" && !opts.ForceContentType {
+		contentType, _, err := filekit.PeekContentType(localPath, "", file)
+		if err != nil {
+			return nil, filekit.WrapPathErr("writefile", localPath, err)
+		}
 		options = append(options, filekit.WithContentType(contentType))
 	}
 
@@ -681,19 +814,6 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 	return opts
 }
 
-// detectContentType determines the content type from file extension
-func detectContentType(filePath string) string {
-	ext := filepath.Ext(filePath)
-	if ext != "" {
-		if contentType := mime.TypeByExtension(ext); contentType != "" {
-			return contentType
-		}
-	}
-
-	// Fallback to http detection
-	return http.DetectContentType(nil)
-}
-
 // mapSFTPError maps SFTP errors to filekit errors
 func mapSFTPError(op, path string, err error) error {
 	if os.IsNotExist(err) {
@@ -714,10 +834,56 @@ func mapSFTPError(op, path string, err error) error {
 	return filekit.WrapPathErr(op, path, err)
 }
 
+// ============================================================================
+// Context-aware I/O
+// ============================================================================
+
+// contextReader aborts reads once its context is done, so long transfers stop
+// promptly on cancellation instead of running to completion.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// contextReadCloser is a contextReader that releases its context on Close.
+type contextReadCloser struct {
+	contextReader
+	closer io.Closer
+	cancel context.CancelFunc
+}
+
+func (c *contextReadCloser) Close() error {
+	c.cancel()
+	return c.closer.Close()
+}
+
 // ============================================================================
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker by asking the server for the
+// session's working directory.
+func (a *Adapter) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return filekit.FromContext(ctx, "ping", "")
+	}
+	client, err := a.acquire()
+	if err != nil {
+		return filekit.WrapPathErr("ping", "", err)
+	}
+	if _, err := client.Getwd(); err != nil {
+		return mapSFTPError("ping", "", err)
+	}
+	return nil
+}
+
 // Copy implements filekit.CanCopy by reading and writing via SFTP.
 // Note: SFTP doesn't have a native copy command, so this downloads and uploads.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
@@ -731,43 +897,55 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("copy", src, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("copy", src, err)
 	}
 
+	ctx, cancel := a.operationContext(ctx)
+	defer cancel()
+
 	srcPath := a.fullPath(src)
 	dstPath := a.fullPath(dst)
 
 	// Open source file
-	srcFile, err := a.client.Open(srcPath)
+	srcFile, err := client.Open(srcPath)
 	if err != nil {
 		return mapSFTPError("copy", src, err)
 	}
 	defer srcFile.Close()
 
+	// Creating the destination would truncate the source
+	if srcPath == dstPath {
+		return nil
+	}
+
 	// Create destination directory if needed
 	dstDir := path.Dir(dstPath)
-	if err := a.client.MkdirAll(dstDir); err != nil {
+	if err := client.MkdirAll(dstDir); err != nil {
 		return mapSFTPError("copy", dst, err)
 	}
 
 	// Create destination file
-	dstFile, err := a.client.Create(dstPath)
+	dstFile, err := client.Create(dstPath)
 	if err != nil {
 		return mapSFTPError("copy", dst, err)
 	}
 	defer dstFile.Close()
 
 	// Copy content
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
+	if _, err := io.Copy(dstFile, &contextReader{ctx: ctx, r: srcFile}); err != nil {
 		return mapSFTPError("copy", dst, err)
 	}
 
 	return nil
 }
 
-// Move implements filekit.CanMove using SFTP's native Rename.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+// Move implements filekit.CanMove using SFTP's native rename. An existing dst
+// is replaced with the posix-rename@openssh.com extension when the server
+// offers it. With filekit.WithMoveNoOverwrite, dst is checked with Lstat and
+// the plain SFTP rename is used, which fails rather than replace a file.
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -778,21 +956,41 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("move", src, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("move", src, err)
 	}
 
 	srcPath := a.fullPath(src)
 	dstPath := a.fullPath(dst)
 
+	// Moving a file onto itself only checks that it exists
+	if srcPath == dstPath {
+		if _, err := client.Stat(srcPath); err != nil {
+			return mapSFTPError("move", src, err)
+		}
+		return nil
+	}
+
+	noOverwrite := filekit.ApplyMoveOptions(opts...).NoOverwrite
+	if noOverwrite {
+		if _, err := client.Lstat(dstPath); err == nil {
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
+	}
+
 	// Create destination directory if needed
 	dstDir := path.Dir(dstPath)
-	if err := a.client.MkdirAll(dstDir); err != nil {
+	if err := client.MkdirAll(dstDir); err != nil {
 		return mapSFTPError("move", dst, err)
 	}
 
 	// Use native rename
-	if err := a.client.Rename(srcPath, dstPath); err != nil {
+	rename := client.Rename
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok && !noOverwrite {
+		rename = client.PosixRename
+	}
+	if err := rename(srcPath, dstPath); err != nil {
 		return mapSFTPError("move", src, err)
 	}
 
@@ -840,8 +1038,14 @@ func (a *Adapter) Checksums(ctx context.Context, filePath string, algorithms []f
 // The filter pattern supports glob patterns like "**/*.json", "config/*".
 // Default polling interval is 30 seconds.
 func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken, error) {
+	return a.WatchMany(ctx, filter)
+}
+
+// WatchMany implements filekit.CanWatchMany. All filters share one poller,
+// so each poll walks the remote tree once however many filters there are.
+func (a *Adapter) WatchMany(ctx context.Context, filters ...string) (filekit.ChangeToken, error) {
 	// Get initial state of matching files
-	initialState, err := a.getMatchingFilesState(ctx, filter)
+	initialState, err := a.getMatchingFilesState(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -850,7 +1054,7 @@ func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken
 	token := filekit.NewPollingChangeToken(ctx, filekit.PollingConfig{
 		Interval: 30 * time.Second,
 		CheckFunc: func() bool {
-			currentState, err := a.getMatchingFilesState(ctx, filter)
+			currentState, err := a.getMatchingFilesState(ctx, filters)
 			if err != nil {
 				return false // Can't determine change, don't signal
 			}
@@ -868,22 +1072,23 @@ type sftpFileState struct {
 	size    int64
 }
 
-// getMatchingFilesState returns the current state of files matching the filter
-func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map[string]sftpFileState, error) {
-	if err := a.ensureConnected(); err != nil {
-		return nil, filekit.WrapPathErr("watch", filter, err)
+// getMatchingFilesState returns the current state of files matching any of the filters
+func (a *Adapter) getMatchingFilesState(ctx context.Context, filters []string) (map[string]sftpFileState, error) {
+	client, err := a.acquire()
+	if err != nil {
+		return nil, filekit.WrapPathErr("watch", strings.Join(filters, ","), err)
 	}
 
 	state := make(map[string]sftpFileState)
 
 	// Walk the base path recursively
-	err := a.walkDir(a.basePath, "", func(relPath string, info os.FileInfo) {
+	err = a.walkDir(client, a.basePath, "", func(relPath string, info os.FileInfo) {
 		if info.IsDir() {
 			return
 		}
 
-		// Check if path matches filter
-		if sftpMatchesGlobFilter(relPath, filter) {
+		// Check if path matches any filter
+		if sftpMatchesAnyGlobFilter(relPath, filters) {
 			state[relPath] = sftpFileState{
 				path:    relPath,
 				modTime: info.ModTime(),
@@ -893,15 +1098,15 @@ func (a *Adapter) getMatchingFilesState(ctx context.Context, filter string) (map
 	})
 
 	if err != nil {
-		return nil, filekit.WrapPathErr("watch", filter, err)
+		return nil, filekit.WrapPathErr("watch", strings.Join(filters, ","), err)
 	}
 
 	return state, nil
 }
 
 // walkDir recursively walks a directory
-func (a *Adapter) walkDir(fullPath, relPath string, fn func(string, os.FileInfo)) error {
-	entries, err := a.client.ReadDir(fullPath)
+func (a *Adapter) walkDir(client *sftp.Client, fullPath, relPath string, fn func(string, os.FileInfo)) error {
+	entries, err := client.ReadDir(fullPath)
 	if err != nil {
 		return err
 	}
@@ -911,7 +1116,7 @@ func (a *Adapter) walkDir(fullPath, relPath string, fn func(string, os.FileInfo)
 		entryFullPath := path.Join(fullPath, entry.Name())
 
 		if entry.IsDir() {
-			if err := a.walkDir(entryFullPath, entryRelPath, fn); err != nil {
+			if err := a.walkDir(client, entryFullPath, entryRelPath, fn); err != nil {
 				return err
 			}
 		} else {
@@ -939,6 +1144,16 @@ func sftpStatesEqual(a, b map[string]sftpFileState) bool {
 	return true
 }
 
+// sftpMatchesAnyGlobFilter checks if a path matches at least one of the glob patterns
+func sftpMatchesAnyGlobFilter(path string, filters []string) bool {
+	for _, filter := range filters {
+		if sftpMatchesGlobFilter(path, filter) {
+			return true
+		}
+	}
+	return false
+}
+
 // sftpMatchesGlobFilter checks if a path matches a glob pattern
 func sftpMatchesGlobFilter(filePath, filter string) bool {
 	// Handle ** patterns for recursive matching
@@ -964,19 +1179,35 @@ func sftpMatchesGlobFilter(filePath, filter string) bool {
 // Chunked Upload Implementation
 // ============================================================================
 
-// sftpUploadInfo stores metadata for an in-progress chunked upload.
-type sftpUploadInfo struct {
-	path     string // Target path for the final file
-	partsDir string // Directory on SFTP server storing uploaded parts
-	adapter  *Adapter
+// defaultUploadStore holds upload state for adapters without WithUploadStore.
+var defaultUploadStore filekit.UploadStore = filekit.NewMemoryUploadStore()
+
+// loadUpload returns the state of an upload, reporting a missing upload as not found.
+func (a *Adapter) loadUpload(ctx context.Context, op, uploadID string) (*filekit.UploadState, error) {
+	info, err := a.uploads.Load(ctx, uploadID)
+	if err != nil {
+		return nil, uploadStoreError(op, uploadID, err)
+	}
+	return info, nil
 }
 
-// sftpUploadRegistry is a thread-safe registry for in-progress uploads.
-var sftpUploadRegistry = struct {
-	sync.RWMutex
-	uploads map[string]*sftpUploadInfo
-}{
-	uploads: make(map[string]*sftpUploadInfo),
+// takeUpload removes the state of an upload and returns it, so that only one
+// of several concurrent CompleteUpload and AbortUpload calls proceeds; the
+// others see the upload as not found.
+func (a *Adapter) takeUpload(ctx context.Context, op, uploadID string) (*filekit.UploadState, error) {
+	info, err := a.uploads.Take(ctx, uploadID)
+	if err != nil {
+		return nil, uploadStoreError(op, uploadID, err)
+	}
+	return info, nil
+}
+
+// uploadStoreError wraps an upload store error for op.
+func uploadStoreError(op, uploadID string, err error) error {
+	if filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		return filekit.NewPathError(op, uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	}
+	return filekit.WrapPathErr(op, uploadID, err)
 }
 
 // generateSFTPUploadID creates a unique upload identifier.
@@ -1001,7 +1232,8 @@ func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string,
 		return "", filekit.WrapPathErr("initiate-upload", filePath, filekit.ErrNotAllowed)
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
 	}
 
@@ -1014,24 +1246,28 @@ func (a *Adapter) InitiateUpload(ctx context.Context, filePath string) (string,
 	// Create a temporary directory on the SFTP server for storing parts
 	// Use the base path + .filekit-uploads/ + uploadID/
 	partsDir := path.Join(a.basePath, ".filekit-uploads", uploadID)
-	if err := a.client.MkdirAll(partsDir); err != nil {
+	if err := client.MkdirAll(partsDir); err != nil {
 		return "", filekit.WrapPathErr("initiate-upload", filePath, fmt.Errorf("failed to create temp directory: %w", err))
 	}
 
 	// Store upload info
-	sftpUploadRegistry.Lock()
-	sftpUploadRegistry.uploads[uploadID] = &sftpUploadInfo{
-		path:     filePath,
-		partsDir: partsDir,
-		adapter:  a,
+	err = a.uploads.Save(ctx, &filekit.UploadState{
+		UploadID:  uploadID,
+		Path:      filePath,
+		PartsDir:  partsDir,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		_ = a.removeAllSFTP(client, partsDir)
+		return "", filekit.WrapPathErr("initiate-upload", filePath, err)
 	}
-	sftpUploadRegistry.Unlock()
 
 	return uploadID, nil
 }
 
 // UploadPart uploads a part of a file in a chunked upload process.
-// Parts are stored as numbered files (1, 2, 3, ...) in the temporary directory on the SFTP server.
+// Parts are stored as numbered files (1, 2, 3, ...) in the temporary directory on the SFTP server,
+// each next to a .sha256 file holding its checksum.
 func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
 	select {
 	case <-ctx.Done():
@@ -1045,21 +1281,19 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 	}
 
 	// Get upload info
-	sftpUploadRegistry.RLock()
-	info, ok := sftpUploadRegistry.uploads[uploadID]
-	sftpUploadRegistry.RUnlock()
-
-	if !ok {
-		return filekit.NewPathError("upload-part", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	info, err := a.loadUpload(ctx, "upload-part", uploadID)
+	if err != nil {
+		return err
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("upload-part", uploadID, err)
 	}
 
 	// Write part to file on SFTP server
-	partPath := path.Join(info.partsDir, fmt.Sprintf("%d", partNumber))
-	partFile, err := a.client.Create(partPath)
+	partPath := path.Join(info.PartsDir, fmt.Sprintf("%d", partNumber))
+	partFile, err := client.Create(partPath)
 	if err != nil {
 		return filekit.WrapPathErr("upload-part", uploadID, fmt.Errorf("failed to create part file: %w", err))
 	}
@@ -1069,39 +1303,62 @@ func (a *Adapter) UploadPart(ctx context.Context, uploadID string, partNumber in
 		return filekit.WrapPathErr("upload-part", uploadID, fmt.Errorf("failed to write part data: %w", err))
 	}
 
+	sumFile, err := client.Create(partPath + ".sha256")
+	if err != nil {
+		return filekit.WrapPathErr("upload-part", uploadID, fmt.Errorf("failed to create part checksum file: %w", err))
+	}
+	defer sumFile.Close()
+
+	if _, err := sumFile.Write([]byte(filekit.PartChecksum(data))); err != nil {
+		return filekit.WrapPathErr("upload-part", uploadID, fmt.Errorf("failed to write part checksum: %w", err))
+	}
+
 	return nil
 }
 
 // CompleteUpload finalizes a chunked upload by concatenating all parts.
 // Parts are read in numerical order and written to the target file on the SFTP server.
+// A part that no longer matches the checksum recorded by UploadPart fails the upload.
 func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
+	return a.completeUpload(ctx, uploadID, "")
+}
+
+// CompleteUploadWithChecksum implements filekit.ChecksumUploader. It is
+// CompleteUpload with the concatenated parts also checked against expected,
+// a hex-encoded SHA-256.
+func (a *Adapter) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	return a.completeUpload(ctx, uploadID, expected)
+}
+
+// completeUpload concatenates the parts of an upload into its target file,
+// verifying each part and, unless expected is empty, the whole. On failure
+// the partially written file is removed.
+func (a *Adapter) completeUpload(ctx context.Context, uploadID, expected string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	// Get and remove upload info
-	sftpUploadRegistry.Lock()
-	info, ok := sftpUploadRegistry.uploads[uploadID]
-	if ok {
-		delete(sftpUploadRegistry.uploads, uploadID)
+	// Claim the upload; a concurrent call for the same ID gets not found
+	info, err := a.takeUpload(ctx, "complete-upload", uploadID)
+	if err != nil {
+		return err
 	}
-	sftpUploadRegistry.Unlock()
 
-	if !ok {
-		return filekit.NewPathError("complete-upload", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	client, err := a.acquire()
+	if err != nil {
+		return filekit.WrapPathErr("complete-upload", uploadID, err)
 	}
 
 	// Ensure cleanup of parts directory
-	defer a.removeAllSFTP(info.partsDir)
+	defer a.removeAllSFTP(client, info.PartsDir)
 
-	if err := a.ensureConnected(); err != nil {
-		return filekit.WrapPathErr("complete-upload", uploadID, err)
-	}
+	ctx, cancel := a.operationContext(ctx)
+	defer cancel()
 
 	// Read all part files
-	entries, err := a.client.ReadDir(info.partsDir)
+	entries, err := client.ReadDir(info.PartsDir)
 	if err != nil {
 		return filekit.WrapPathErr("complete-upload", uploadID, err)
 	}
@@ -1125,39 +1382,96 @@ func (a *Adapter) CompleteUpload(ctx context.Context, uploadID string) error {
 	sort.Ints(partNumbers)
 
 	// Prepare target path
-	fullPath := a.fullPath(info.path)
+	fullPath := a.fullPath(info.Path)
 
 	// Ensure the directory exists
 	dir := path.Dir(fullPath)
-	if err := a.client.MkdirAll(dir); err != nil {
-		return filekit.WrapPathErr("complete-upload", info.path, err)
+	if err := client.MkdirAll(dir); err != nil {
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
 	}
 
-	// Create the target file
-	targetFile, err := a.client.Create(fullPath)
+	// Assemble into a temporary file so a failed verification leaves any
+	// existing file untouched
+	suffix, err := generateSFTPUploadID()
 	if err != nil {
-		return filekit.WrapPathErr("complete-upload", info.path, err)
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
 	}
-	defer targetFile.Close()
+	tmpPath := path.Join(dir, "."+path.Base(fullPath)+"."+suffix+".tmp")
+	targetFile, err := client.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if err != nil {
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
+	}
+	completed := false
+	defer func() {
+		targetFile.Close()
+		if !completed {
+			client.Remove(tmpPath)
+		}
+	}()
 
-	// Concatenate all parts in order
+	// Concatenate all parts in order, hashing each part and the whole
+	whole := sha256.New()
 	for _, partNum := range partNumbers {
-		partPath := path.Join(info.partsDir, fmt.Sprintf("%d", partNum))
-		partFile, err := a.client.Open(partPath)
+		partPath := path.Join(info.PartsDir, fmt.Sprintf("%d", partNum))
+		partFile, err := client.Open(partPath)
 		if err != nil {
-			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to open part %d: %w", partNum, err))
+			return filekit.WrapPathErr("complete-upload", info.Path, fmt.Errorf("failed to open part %d: %w", partNum, err))
 		}
 
-		_, err = io.Copy(targetFile, partFile)
+		part := sha256.New()
+		_, err = io.Copy(io.MultiWriter(targetFile, whole, part), &contextReader{ctx: ctx, r: partFile})
 		partFile.Close()
 		if err != nil {
-			return filekit.WrapPathErr("complete-upload", info.path, fmt.Errorf("failed to write part %d: %w", partNum, err))
+			return filekit.WrapPathErr("complete-upload", info.Path, fmt.Errorf("failed to write part %d: %w", partNum, err))
 		}
+
+		// Parts staged without a checksum file are not verified
+		if err := filekit.VerifyUploadChecksum(info.Path, partNum, readPartChecksum(client, partPath), part.Sum(nil)); err != nil {
+			return err
+		}
+	}
+	if err := filekit.VerifyUploadChecksum(info.Path, 0, expected, whole.Sum(nil)); err != nil {
+		return err
 	}
 
+	if err := targetFile.Close(); err != nil {
+		return filekit.WrapPathErr("complete-upload", info.Path, err)
+	}
+	if err := replaceFile(client, tmpPath, fullPath); err != nil {
+		return mapSFTPError("complete-upload", info.Path, err)
+	}
+	completed = true
 	return nil
 }
 
+// replaceFile renames src over dst. Without the posix-rename extension, SFTP
+// rename fails if dst exists, so dst is removed first.
+func replaceFile(client *sftp.Client, src, dst string) error {
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		return client.PosixRename(src, dst)
+	}
+	if err := client.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return client.Rename(src, dst)
+}
+
+// readPartChecksum returns the checksum UploadPart recorded for a part, or
+// "" if there is none.
+func readPartChecksum(client *sftp.Client, partPath string) string {
+	f, err := client.Open(partPath + ".sha256")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	sum, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	return string(sum)
+}
+
 // AbortUpload cancels a chunked upload and cleans up temporary files on the SFTP server.
 func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
 	select {
@@ -1166,33 +1480,69 @@ func (a *Adapter) AbortUpload(ctx context.Context, uploadID string) error {
 	default:
 	}
 
-	// Get and remove upload info
-	sftpUploadRegistry.Lock()
-	info, ok := sftpUploadRegistry.uploads[uploadID]
-	if ok {
-		delete(sftpUploadRegistry.uploads, uploadID)
-	}
-	sftpUploadRegistry.Unlock()
-
-	if !ok {
-		return filekit.NewPathError("abort-upload", uploadID, filekit.ErrCodeNotFound, fmt.Sprintf("upload not found: %s", uploadID))
+	// Claim the upload; a concurrent call for the same ID gets not found
+	info, err := a.takeUpload(ctx, "abort-upload", uploadID)
+	if err != nil {
+		return err
 	}
 
-	if err := a.ensureConnected(); err != nil {
+	client, err := a.acquire()
+	if err != nil {
 		return filekit.WrapPathErr("abort-upload", uploadID, err)
 	}
 
 	// Clean up parts directory
-	if err := a.removeAllSFTP(info.partsDir); err != nil {
+	if err := a.removeAllSFTP(client, info.PartsDir); err != nil {
 		return filekit.WrapPathErr("abort-upload", uploadID, err)
 	}
 
 	return nil
 }
 
+// GarbageCollectUploads removes .filekit-uploads/<id> part directories of
+// chunked uploads that were never completed or aborted and have not been
+// written to for longer than olderThan, along with their upload state.
+// It returns the number of part directories removed.
+func (a *Adapter) GarbageCollectUploads(ctx context.Context, olderThan time.Duration) (reclaimed int, err error) {
+	client, err := a.acquire()
+	if err != nil {
+		return 0, filekit.WrapPathErr("gc-uploads", "", err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	uploadsDir := path.Join(a.basePath, ".filekit-uploads")
+
+	entries, err := client.ReadDir(uploadsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, mapSFTPError("gc-uploads", uploadsDir, err)
+	}
+
+	errs := filekit.NewMultiError("gc-uploads")
+	for _, entry := range entries {
+		if !entry.IsDir() || !entry.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := filekit.FromContext(ctx, "gc-uploads", entry.Name()); err != nil {
+			return reclaimed, err
+		}
+
+		if err := a.removeAllSFTP(client, path.Join(uploadsDir, entry.Name())); err != nil {
+			errs.Add(filekit.WrapPathErr("gc-uploads", entry.Name(), err))
+			continue
+		}
+		reclaimed++
+		errs.Add(a.uploads.Delete(ctx, entry.Name()))
+	}
+
+	return reclaimed, errs.Err()
+}
+
 // removeAllSFTP recursively removes a directory and its contents on the SFTP server.
-func (a *Adapter) removeAllSFTP(dirPath string) error {
-	entries, err := a.client.ReadDir(dirPath)
+func (a *Adapter) removeAllSFTP(client *sftp.Client, dirPath string) error {
+	entries, err := client.ReadDir(dirPath)
 	if err != nil {
 		// Directory may not exist, which is fine
 		if os.IsNotExist(err) {
@@ -1204,27 +1554,31 @@ func (a *Adapter) removeAllSFTP(dirPath string) error {
 	for _, entry := range entries {
 		entryPath := path.Join(dirPath, entry.Name())
 		if entry.IsDir() {
-			if err := a.removeAllSFTP(entryPath); err != nil {
+			if err := a.removeAllSFTP(client, entryPath); err != nil {
 				return err
 			}
 		} else {
-			if err := a.client.Remove(entryPath); err != nil {
+			if err := client.Remove(entryPath); err != nil {
 				return err
 			}
 		}
 	}
 
-	return a.client.RemoveDirectory(dirPath)
+	return client.RemoveDirectory(dirPath)
 }
 
 // Ensure Adapter implements required and optional interfaces
 var (
-	_ filekit.FileSystem      = (*Adapter)(nil)
-	_ filekit.FileReader      = (*Adapter)(nil)
-	_ filekit.FileWriter      = (*Adapter)(nil)
-	_ filekit.CanCopy         = (*Adapter)(nil)
-	_ filekit.CanMove         = (*Adapter)(nil)
-	_ filekit.CanChecksum     = (*Adapter)(nil)
-	_ filekit.CanWatch        = (*Adapter)(nil)
-	_ filekit.ChunkedUploader = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.CanWatchMany      = (*Adapter)(nil)
+	_ filekit.ChunkedUploader   = (*Adapter)(nil)
+	_ filekit.ChecksumUploader  = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
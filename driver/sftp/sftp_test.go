@@ -0,0 +1,610 @@
+package sftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// newTestAdapter returns an Adapter whose sessions all talk to one shared
+// in-memory SFTP server.
+func newTestAdapter(t *testing.T, cfg Config) *Adapter {
+	t.Helper()
+	adapter, _ := newTestAdapterWithServer(t, cfg, 0)
+	return adapter
+}
+
+// testServer tracks the in-memory server side of every session dialed by a
+// test adapter.
+type testServer struct {
+	mu      sync.Mutex
+	closers []func()
+}
+
+// dropSessions closes the server side of all open sessions, as if the
+// network connections were lost.
+func (s *testServer) dropSessions() {
+	s.mu.Lock()
+	closers := s.closers
+	s.closers = nil
+	s.mu.Unlock()
+
+	for _, closeServer := range closers {
+		closeServer()
+	}
+}
+
+// newTestAdapterWithServer is like newTestAdapter but delays every packet
+// sent by the client, simulating network round trips, and exposes the server.
+func newTestAdapterWithServer(t *testing.T, cfg Config, latency time.Duration) (*Adapter, *testServer) {
+	t.Helper()
+
+	handlers := sftp.InMemHandler()
+	srv := &testServer{}
+
+	adapter := &Adapter{config: cfg, basePath: cfg.BasePath, uploads: defaultUploadStore}
+	adapter.dial = func() (*ssh.Client, *sftp.Client, error) {
+		serverReader, clientWriter := io.Pipe()
+		clientReader, serverWriter := io.Pipe()
+
+		server := sftp.NewRequestServer(struct {
+			io.Reader
+			io.WriteCloser
+		}{serverReader, serverWriter}, handlers)
+		go func() { _ = server.Serve() }()
+
+		client, err := sftp.NewClientPipe(clientReader, &delayedWriter{w: clientWriter, delay: latency})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Closing the server side first lets the client's receive loop see EOF
+		srv.mu.Lock()
+		srv.closers = append(srv.closers, func() {
+			server.Close()
+			serverWriter.Close()
+		})
+		srv.mu.Unlock()
+		return nil, client, nil
+	}
+
+	if err := adapter.connect(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	t.Cleanup(func() {
+		srv.dropSessions()
+		_ = adapter.Close()
+	})
+
+	return adapter, srv
+}
+
+// delayedWriter sleeps before every write.
+type delayedWriter struct {
+	w     io.WriteCloser
+	delay time.Duration
+}
+
+func (d *delayedWriter) Write(p []byte) (int, error) {
+	if d.delay > 0 {
+		time.Sleep(d.delay)
+	}
+	return d.w.Write(p)
+}
+
+func (d *delayedWriter) Close() error {
+	return d.w.Close()
+}
+
+// cancelingReader produces data indefinitely and cancels after limit bytes.
+type cancelingReader struct {
+	read   int
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	if r.read >= r.limit {
+		r.cancel()
+	}
+	r.read += len(p)
+	return len(p), nil
+}
+
+// slowReader produces data indefinitely, pausing between reads.
+type slowReader struct{}
+
+func (slowReader) Read(p []byte) (int, error) {
+	time.Sleep(5 * time.Millisecond)
+	return len(p), nil
+}
+
+func TestWrite_CancelMidTransfer(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	_, err := adapter.Write(ctx, "big.bin", &cancelingReader{limit: 1 << 20, cancel: cancel})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("write did not stop promptly: %v", elapsed)
+	}
+}
+
+func TestRead_CancelMidTransfer(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "file.bin", io.LimitReader(slowReader{}, 256*1024)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	readCtx, cancel := context.WithCancel(ctx)
+	rc, err := adapter.Read(readCtx, "file.bin")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	defer rc.Close()
+
+	if _, err := io.ReadFull(rc, make([]byte, 1024)); err != nil {
+		t.Fatalf("initial read failed: %v", err)
+	}
+	cancel()
+
+	if _, err := io.ReadAll(rc); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCopy_Cancelled(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "src.bin", io.LimitReader(slowReader{}, 64*1024)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := adapter.Copy(cancelled, "src.bin", "dst.bin"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMove_NoOverwrite(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+	ctx := context.Background()
+
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if _, err := adapter.Write(ctx, name, strings.NewReader(name)); err != nil {
+			t.Fatalf("write %s failed: %v", name, err)
+		}
+	}
+
+	if err := adapter.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+		t.Fatalf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if data, err := adapter.ReadAll(ctx, "b.txt"); err != nil || string(data) != "b.txt" {
+		t.Errorf("ReadAll(b.txt) after refused move = %q, %v", data, err)
+	}
+
+	if err := adapter.Move(ctx, "c.txt", "b.txt"); err != nil {
+		t.Fatalf("Move onto existing: %v", err)
+	}
+	if data, err := adapter.ReadAll(ctx, "b.txt"); err != nil || string(data) != "c.txt" {
+		t.Errorf("ReadAll(b.txt) after move = %q, %v; want %q", data, err, "c.txt")
+	}
+}
+
+func TestWrite_OperationTimeout(t *testing.T) {
+	adapter := newTestAdapter(t, Config{OperationTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	_, err := adapter.Write(context.Background(), "slow.bin", slowReader{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("write did not honor timeout: %v", elapsed)
+	}
+}
+
+func TestConnectionPool_ParallelReads(t *testing.T) {
+	const files = 32
+	latency := 2 * time.Millisecond
+
+	readAll := func(t *testing.T, adapter *Adapter) time.Duration {
+		t.Helper()
+		ctx := context.Background()
+
+		for i := 0; i < files; i++ {
+			name := fmt.Sprintf("file-%d.txt", i)
+			if _, err := adapter.Write(ctx, name, strings.NewReader(name)); err != nil {
+				t.Fatalf("write %s failed: %v", name, err)
+			}
+		}
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		errs := make(chan error, files)
+		for i := 0; i < files; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				name := fmt.Sprintf("file-%d.txt", i)
+				data, err := adapter.ReadAll(ctx, name)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if string(data) != name {
+					errs <- fmt.Errorf("%s: unexpected content %q", name, data)
+				}
+			}(i)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(30 * time.Second):
+			t.Fatal("parallel reads deadlocked")
+		}
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+		return time.Since(start)
+	}
+
+	single, _ := newTestAdapterWithServer(t, Config{}, latency)
+	pool, _ := newTestAdapterWithServer(t, Config{MaxConnections: 8}, latency)
+
+	serial := readAll(t, single)
+	pooled := readAll(t, pool)
+
+	t.Logf("single connection: %v, pooled: %v", serial, pooled)
+	if pooled >= serial {
+		t.Errorf("pooled reads (%v) should be faster than a single connection (%v)", pooled, serial)
+	}
+}
+
+func TestConnectionPool_Reconnect(t *testing.T) {
+	adapter, srv := newTestAdapterWithServer(t, Config{MaxConnections: 2}, 0)
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "file.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// Drop every session; the next operations must reconnect transparently
+	srv.dropSessions()
+
+	for i := 0; i < 4; i++ {
+		data, err := adapter.ReadAll(ctx, "file.txt")
+		if err != nil {
+			t.Fatalf("read after reconnect failed: %v", err)
+		}
+		if string(data) != "hello" {
+			t.Fatalf("unexpected content: %q", data)
+		}
+	}
+}
+
+func TestChunkedUpload_ResumeAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	storeDir := t.TempDir()
+
+	store, err := filekit.NewFileUploadStore(storeDir)
+	if err != nil {
+		t.Fatalf("failed to create upload store: %v", err)
+	}
+	before := newTestAdapter(t, Config{BasePath: "/data"})
+	before.uploads = store
+
+	uploadID, err := before.InitiateUpload(ctx, "resumed.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+	if err := before.UploadPart(ctx, uploadID, 1, []byte("hello ")); err != nil {
+		t.Fatalf("failed to upload part: %v", err)
+	}
+
+	// Simulate a restart: fresh store and adapter talking to the same server
+	reopened, err := filekit.NewFileUploadStore(storeDir)
+	if err != nil {
+		t.Fatalf("failed to reopen upload store: %v", err)
+	}
+	after := &Adapter{config: before.config, basePath: before.basePath, dial: before.dial, uploads: reopened}
+	// Sessions are torn down with the shared test server
+	if err := after.connect(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := after.UploadPart(ctx, uploadID, 2, []byte("world")); err != nil {
+		t.Fatalf("failed to upload part after restart: %v", err)
+	}
+	if err := after.CompleteUpload(ctx, uploadID); err != nil {
+		t.Fatalf("failed to complete upload after restart: %v", err)
+	}
+
+	data, err := after.ReadAll(ctx, "resumed.txt")
+	if err != nil {
+		t.Fatalf("failed to read completed file: %v", err)
+	}
+	if string(data) != "hello world" {
+		t.Errorf("expected %q, got %q", "hello world", data)
+	}
+
+	if _, err := reopened.Load(ctx, uploadID); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected upload state to be removed, got %v", err)
+	}
+}
+
+func TestCompleteUploadWithChecksum(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestAdapter(t, Config{BasePath: "/data"})
+	whole := filekit.PartChecksum([]byte("hello world"))
+
+	stage := func(t *testing.T, name string) (string, string) {
+		t.Helper()
+		uploadID, err := adapter.InitiateUpload(ctx, name)
+		if err != nil {
+			t.Fatalf("failed to initiate upload: %v", err)
+		}
+		for i, part := range []string{"hello ", "world"} {
+			if err := adapter.UploadPart(ctx, uploadID, i+1, []byte(part)); err != nil {
+				t.Fatalf("failed to upload part %d: %v", i+1, err)
+			}
+		}
+		state, err := adapter.uploads.Load(ctx, uploadID)
+		if err != nil {
+			t.Fatalf("failed to load upload state: %v", err)
+		}
+		return uploadID, state.PartsDir
+	}
+
+	t.Run("matching checksum", func(t *testing.T) {
+		uploadID, _ := stage(t, "ok.txt")
+		if err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole); err != nil {
+			t.Fatalf("failed to complete upload: %v", err)
+		}
+		data, err := adapter.ReadAll(ctx, "ok.txt")
+		if err != nil || string(data) != "hello world" {
+			t.Errorf("expected %q, got %q (err %v)", "hello world", data, err)
+		}
+	})
+
+	t.Run("corrupted part", func(t *testing.T) {
+		uploadID, partsDir := stage(t, "corrupt.txt")
+
+		client, err := adapter.acquire()
+		if err != nil {
+			t.Fatalf("failed to acquire client: %v", err)
+		}
+		f, err := client.Create(partsDir + "/2")
+		if err != nil {
+			t.Fatalf("failed to open part: %v", err)
+		}
+		_, err = f.Write([]byte("w0rld"))
+		f.Close()
+		if err != nil {
+			t.Fatalf("failed to corrupt part: %v", err)
+		}
+
+		err = adapter.CompleteUploadWithChecksum(ctx, uploadID, whole)
+		var mismatch *filekit.ChecksumMismatchError
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) || !errors.As(err, &mismatch) || mismatch.Part != 2 {
+			t.Fatalf("expected integrity error for part 2, got %v", err)
+		}
+		if exists, _ := adapter.FileExists(ctx, "corrupt.txt"); exists {
+			t.Error("expected no file after a corrupted part")
+		}
+	})
+
+	t.Run("wrong expected checksum", func(t *testing.T) {
+		uploadID, _ := stage(t, "wrong.txt")
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, filekit.PartChecksum([]byte("other")))
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) {
+			t.Fatalf("expected integrity error, got %v", err)
+		}
+		if exists, _ := adapter.FileExists(ctx, "wrong.txt"); exists {
+			t.Error("expected no file after a checksum mismatch")
+		}
+	})
+
+	t.Run("keeps existing file on mismatch", func(t *testing.T) {
+		if _, err := adapter.Write(ctx, "existing.txt", strings.NewReader("original")); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		uploadID, _ := stage(t, "existing.txt")
+		err := adapter.CompleteUploadWithChecksum(ctx, uploadID, strings.Repeat("0", 64))
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) {
+			t.Fatalf("expected integrity error, got %v", err)
+		}
+		data, err := adapter.ReadAll(ctx, "existing.txt")
+		if err != nil || string(data) != "original" {
+			t.Errorf("expected original file to be kept, got %q (err %v)", data, err)
+		}
+		entries, err := adapter.ListContents(ctx, "", false)
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		for _, e := range entries {
+			if strings.HasSuffix(e.Name, ".tmp") {
+				t.Errorf("temporary file %s left behind", e.Name)
+			}
+		}
+
+		// A matching upload replaces it
+		uploadID, _ = stage(t, "existing.txt")
+		if err := adapter.CompleteUploadWithChecksum(ctx, uploadID, whole); err != nil {
+			t.Fatalf("failed to complete upload: %v", err)
+		}
+		if data, _ := adapter.ReadAll(ctx, "existing.txt"); string(data) != "hello world" {
+			t.Errorf("expected %q, got %q", "hello world", data)
+		}
+	})
+}
+
+func TestGarbageCollectUploads(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestAdapter(t, Config{BasePath: "/data"})
+	adapter.uploads = filekit.NewMemoryUploadStore()
+
+	staleID, err := adapter.InitiateUpload(ctx, "stale.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+
+	// The in-memory server does not support changing directory times, so age
+	// the stale upload by letting time pass (SFTP mtimes have 1s resolution)
+	time.Sleep(2100 * time.Millisecond)
+
+	freshID, err := adapter.InitiateUpload(ctx, "fresh.txt")
+	if err != nil {
+		t.Fatalf("failed to initiate upload: %v", err)
+	}
+
+	client, err := adapter.acquire()
+	if err != nil {
+		t.Fatalf("failed to acquire client: %v", err)
+	}
+
+	reclaimed, err := adapter.GarbageCollectUploads(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("garbage collection failed: %v", err)
+	}
+	if reclaimed != 1 {
+		t.Errorf("expected 1 reclaimed upload, got %d", reclaimed)
+	}
+
+	if _, err := client.Stat("/data/.filekit-uploads/" + staleID); err == nil {
+		t.Error("expected stale upload directory to be removed")
+	}
+	if _, err := client.Stat("/data/.filekit-uploads/" + freshID); err != nil {
+		t.Errorf("fresh upload directory should be left alone: %v", err)
+	}
+
+	if err := adapter.UploadPart(ctx, staleID, 1, []byte("x")); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected stale upload to be forgotten, got %v", err)
+	}
+	if err := adapter.UploadPart(ctx, freshID, 1, []byte("x")); err != nil {
+		t.Errorf("fresh upload should survive garbage collection: %v", err)
+	}
+}
+
+func TestIsPathSafe(t *testing.T) {
+	tests := []struct {
+		basePath string
+		path     string
+		want     bool
+	}{
+		{"/base", "file.txt", true},
+		{"/base", "a/b/c.txt", true},
+		{"/base", "", true},
+		{"/base", ".", true},
+		{"/base", "a/../b.txt", true},
+		{"/base", "/etc/passwd", true}, // relative to the base path: /base/etc/passwd
+		{"/base", "..", false},
+		{"/base", "../other", false},
+		{"/base", "../base-evil/x", false},
+		{"/base", "a/../../base-evil", false},
+		{"/base", "/../etc/passwd", false},
+		{"/base", `..\other`, false},
+		{"/base/", "../base2", false},
+		{"data", "x.txt", true},
+		{"data", "../data2/x.txt", false},
+		{"", "x.txt", true},
+		{"", "../x.txt", false},
+	}
+	for _, tt := range tests {
+		a := &Adapter{basePath: tt.basePath}
+		if got := a.isPathSafe(tt.path); got != tt.want {
+			t.Errorf("isPathSafe(%q) with base path %q = %v, want %v", tt.path, tt.basePath, got, tt.want)
+		}
+	}
+}
+
+func TestPathEscapesAreDenied(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestAdapter(t, Config{BasePath: "/base"})
+
+	if _, err := adapter.Write(ctx, "nested/ok.txt", strings.NewReader("ok")); err != nil {
+		t.Fatalf("Write(nested/ok.txt): %v", err)
+	}
+	if data, err := adapter.ReadAll(ctx, "nested/../nested/ok.txt"); err != nil || string(data) != "ok" {
+		t.Errorf("ReadAll(nested/../nested/ok.txt) = %q, %v; want ok", data, err)
+	}
+
+	for _, p := range []string{"../base-evil/x.txt", "../other.txt", "nested/../../x.txt", "/../x.txt"} {
+		if _, err := adapter.Write(ctx, p, strings.NewReader("x")); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Write(%q) = %v, want ErrCodePermission", p, err)
+		}
+		if _, err := adapter.Read(ctx, p); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Read(%q) = %v, want ErrCodePermission", p, err)
+		}
+		if err := adapter.Delete(ctx, p); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Delete(%q) = %v, want ErrCodePermission", p, err)
+		}
+		if err := adapter.Copy(ctx, "nested/ok.txt", p); !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("Copy(nested/ok.txt, %q) = %v, want ErrCodePermission", p, err)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+	ctx := context.Background()
+
+	localPath := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(localPath, []byte("quarterly figures"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := adapter.WriteFile(ctx, "reports/q1.txt", localPath)
+	if err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if result.BytesWritten != int64(len("quarterly figures")) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("quarterly figures"))
+	}
+	if data, err := adapter.ReadAll(ctx, "reports/q1.txt"); err != nil || string(data) != "quarterly figures" {
+		t.Errorf("ReadAll = %q, %v; want %q", data, err, "quarterly figures")
+	}
+}
+
+func TestStat_ContentType(t *testing.T) {
+	adapter := newTestAdapter(t, Config{})
+	ctx := context.Background()
+
+	if _, err := adapter.Write(ctx, "data.json", strings.NewReader(`{"ok": true}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	info, err := adapter.Stat(ctx, "data.json")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.ContentType != "application/json" {
+		t.Errorf("ContentType = %q, want application/json", info.ContentType)
+	}
+}
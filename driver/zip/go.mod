@@ -4,14 +4,22 @@ go 1.24.0
 
 toolchain go1.24.2
 
-require github.com/gobeaver/filekit v0.0.4
+require (
+	github.com/gobeaver/filekit v0.0.4
+	github.com/gobeaver/filekit/driver/memory v0.0.4
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/gobeaver/beaver-kit/config v0.1.0 // indirect
 	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
+	github.com/gobwas/glob v0.2.3 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/gobeaver/filekit => ../..
 
+replace github.com/gobeaver/filekit/driver/memory => ../memory
+
 replace github.com/gobeaver/filekit/filevalidator => ../../filevalidator
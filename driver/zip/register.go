@@ -8,11 +8,19 @@ import (
 
 func init() {
 	filekit.RegisterDriver("zip", func(cfg *filekit.Config) (filekit.FileSystem, error) {
-		// ZIP driver requires a path - use LocalBasePath as the ZIP file path
-		if cfg.LocalBasePath == "" {
-			return nil, fmt.Errorf("zip driver requires LocalBasePath to be set to the ZIP file path")
+		if cfg.ZipPath == "" {
+			return nil, fmt.Errorf("zip driver requires ZipPath to be set to the ZIP file path")
 		}
 
-		return OpenOrCreate(cfg.LocalBasePath)
+		switch cfg.ZipMode {
+		case "open":
+			return Open(cfg.ZipPath)
+		case "create":
+			return Create(cfg.ZipPath)
+		case "", "open-or-create":
+			return OpenOrCreate(cfg.ZipPath)
+		default:
+			return nil, fmt.Errorf("invalid zip mode %q", cfg.ZipMode)
+		}
 	})
 }
@@ -8,8 +8,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"mime"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -46,13 +44,32 @@ type Adapter struct {
 	modified bool
 }
 
-// zipEntry represents a file or directory in the ZIP
+// zipEntry represents a file or directory in the ZIP. An entry backed by
+// the opened archive has file set and no content; its data is read on
+// demand and copied through unchanged when the archive is rewritten.
 type zipEntry struct {
 	header  *zip.FileHeader
+	file    *zip.File
 	content []byte
 	isDir   bool
 }
 
+// size returns the uncompressed size of the entry
+func (e *zipEntry) size() int64 {
+	if e.content != nil || e.header == nil {
+		return int64(len(e.content))
+	}
+	return int64(e.header.UncompressedSize64)
+}
+
+// open returns a reader for the entry's content
+func (e *zipEntry) open() (io.ReadCloser, error) {
+	if e.content == nil && e.file != nil {
+		return e.file.Open()
+	}
+	return io.NopCloser(bytes.NewReader(e.content)), nil
+}
+
 // Open opens an existing ZIP file for reading
 func Open(zipPath string) (*Adapter, error) {
 	reader, err := zip.OpenReader(zipPath)
@@ -67,19 +84,23 @@ func Open(zipPath string) (*Adapter, error) {
 		files:  make(map[string]*zipEntry),
 	}
 
-	// Build file index
-	for _, f := range reader.File {
-		name := normalizePath(f.Name)
+	a.indexReader()
+	return a, nil
+}
+
+// indexReader adds every entry of a.reader to a.files without reading content
+func (a *Adapter) indexReader() {
+	for _, f := range a.reader.File {
+		name := entryName(f.Name)
 		a.files[name] = &zipEntry{
 			header: &f.FileHeader,
+			file:   f,
 			isDir:  f.FileInfo().IsDir(),
 		}
 
 		// Also add parent directories
 		a.ensureParentDirs(name)
 	}
-
-	return a, nil
 }
 
 // Create creates a new ZIP file for writing
@@ -99,14 +120,18 @@ func Create(zipPath string) (*Adapter, error) {
 	}, nil
 }
 
-// OpenOrCreate opens an existing ZIP or creates a new one
+// OpenOrCreate opens an existing ZIP or creates a new one.
+//
+// An existing archive is kept open and entries are read on demand; only
+// written, copied or moved entries are held in memory. Close rewrites the
+// archive if anything changed, copying untouched entries through without
+// recompressing them.
 func OpenOrCreate(zipPath string) (*Adapter, error) {
 	// Check if file exists
 	if _, err := os.Stat(zipPath); os.IsNotExist(err) {
 		return Create(zipPath)
 	}
 
-	// Open existing file and load into memory for read-write
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open zip: %w", err)
@@ -120,36 +145,7 @@ func OpenOrCreate(zipPath string) (*Adapter, error) {
 		pending: make(map[string]*zipEntry),
 	}
 
-	// Load existing files into memory
-	for _, f := range reader.File {
-		name := normalizePath(f.Name)
-
-		// Read file content
-		var content []byte
-		if !f.FileInfo().IsDir() {
-			rc, err := f.Open()
-			if err != nil {
-				reader.Close()
-				return nil, fmt.Errorf("failed to read zip entry: %w", err)
-			}
-			content, err = io.ReadAll(rc)
-			rc.Close()
-			if err != nil {
-				reader.Close()
-				return nil, fmt.Errorf("failed to read zip entry content: %w", err)
-			}
-		}
-
-		a.files[name] = &zipEntry{
-			header:  &f.FileHeader,
-			content: content,
-			isDir:   f.FileInfo().IsDir(),
-		}
-
-		// Also add parent directories
-		a.ensureParentDirs(name)
-	}
-
+	a.indexReader()
 	return a, nil
 }
 
@@ -193,14 +189,10 @@ func (a *Adapter) Close() error {
 	return nil
 }
 
-// rewriteZip rewrites the ZIP file with all changes
+// rewriteZip rewrites the ZIP file with all changes. Entries still backed by
+// the original archive are copied through compressed, so a.reader stays open
+// until the new file is complete.
 func (a *Adapter) rewriteZip() error {
-	// Close the reader first
-	if a.reader != nil {
-		a.reader.Close()
-		a.reader = nil
-	}
-
 	// Create a temporary file
 	tmpPath := a.path + ".tmp"
 	tmpFile, err := os.Create(tmpPath)
@@ -239,6 +231,16 @@ func (a *Adapter) rewriteZip() error {
 			Modified: time.Now(),
 		}
 
+		if !entry.isDir && entry.content == nil && entry.file != nil {
+			if err := copyRaw(writer, entry.file, name); err != nil {
+				writer.Close()
+				tmpFile.Close()
+				os.Remove(tmpPath)
+				return err
+			}
+			continue
+		}
+
 		if entry.isDir {
 			header.Name = name + "/"
 			header.SetMode(os.ModeDir | 0755)
@@ -278,6 +280,12 @@ func (a *Adapter) rewriteZip() error {
 		return err
 	}
 
+	// Close the reader before replacing the file it reads from
+	if a.reader != nil {
+		a.reader.Close()
+		a.reader = nil
+	}
+
 	// Replace original with temp
 	if err := os.Rename(tmpPath, a.path); err != nil {
 		os.Remove(tmpPath)
@@ -287,6 +295,22 @@ func (a *Adapter) rewriteZip() error {
 	return nil
 }
 
+// copyRaw copies the compressed data of f into w under name
+func copyRaw(w *zip.Writer, f *zip.File, name string) error {
+	header := f.FileHeader
+	header.Name = name
+	dst, err := w.CreateRaw(&header)
+	if err != nil {
+		return err
+	}
+	src, err := f.OpenRaw()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
 // Write implements filekit.FileWriter
 func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
 	select {
@@ -302,13 +326,15 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodePermission, "operation not allowed")
 	}
 
-	filePath = normalizePath(filePath)
-
-	if !isValidPath(filePath) {
-		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodePermission, "operation not allowed")
+	filePath, err := cleanPath("write", filePath)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := processOptions(options...)
+	if opts.HasPreconditions() {
+		return nil, filekit.NewPathError("write", filePath, filekit.ErrCodeNotSupported, "conditional writes are not supported")
+	}
 
 	// Check if file exists
 	if !opts.Overwrite {
@@ -321,7 +347,7 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 	}
 
 	// Read content
-	data, err := io.ReadAll(content)
+	data, err := io.ReadAll(opts.TrackProgress(content))
 	if err != nil {
 		return nil, filekit.WrapPathErr("write", filePath, err)
 	}
@@ -370,6 +396,7 @@ func (a *Adapter) Write(ctx context.Context, filePath string, content io.Reader,
 		BytesWritten:      int64(len(data)),
 		Checksum:          checksum,
 		ChecksumAlgorithm: filekit.ChecksumSHA256,
+		ContentType:       filekit.ResolveContentType(filePath, "", data),
 		ServerTimestamp:   now,
 	}, nil
 }
@@ -385,14 +412,17 @@ func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, err
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	filePath = normalizePath(filePath)
+	filePath, err := cleanPath("read", filePath)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check pending first (for read-write mode)
 	if entry, exists := a.pending[filePath]; exists {
 		if entry == nil || entry.isDir {
 			return nil, filekit.WrapPathErr("read", filePath, filekit.ErrNotExist)
 		}
-		return io.NopCloser(bytes.NewReader(entry.content)), nil
+		return entry.open()
 	}
 
 	// Check files index
@@ -405,21 +435,7 @@ func (a *Adapter) Read(ctx context.Context, filePath string) (io.ReadCloser, err
 		return nil, filekit.WrapPathErr("read", filePath, filekit.ErrIsDir)
 	}
 
-	// If we have content in memory (read-write mode)
-	if entry.content != nil {
-		return io.NopCloser(bytes.NewReader(entry.content)), nil
-	}
-
-	// Read from ZIP file (read mode)
-	if a.reader != nil {
-		for _, f := range a.reader.File {
-			if normalizePath(f.Name) == filePath {
-				return f.Open()
-			}
-		}
-	}
-
-	return nil, filekit.WrapPathErr("read", filePath, filekit.ErrNotExist)
+	return entry.open()
 }
 
 // ReadAll reads the entire file contents
@@ -447,7 +463,10 @@ func (a *Adapter) Delete(ctx context.Context, filePath string) error {
 		return filekit.WrapPathErr("delete", filePath, filekit.ErrNotAllowed)
 	}
 
-	filePath = normalizePath(filePath)
+	filePath, err := cleanPath("delete", filePath)
+	if err != nil {
+		return err
+	}
 
 	// Check if exists
 	_, inFiles := a.files[filePath]
@@ -476,7 +495,10 @@ func (a *Adapter) FileExists(ctx context.Context, filePath string) (bool, error)
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	filePath = normalizePath(filePath)
+	filePath, err := cleanPath("fileexists", filePath)
+	if err != nil {
+		return false, err
+	}
 
 	if entry, exists := a.pending[filePath]; exists {
 		return entry != nil && !entry.isDir, nil
@@ -500,7 +522,10 @@ func (a *Adapter) DirExists(ctx context.Context, dirPath string) (bool, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	dirPath = normalizePath(dirPath)
+	dirPath, err := cleanPath("direxists", dirPath)
+	if err != nil {
+		return false, err
+	}
 
 	if entry, exists := a.pending[dirPath]; exists {
 		return entry != nil && entry.isDir, nil
@@ -524,17 +549,20 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	filePath = normalizePath(filePath)
+	filePath, err := cleanPath("stat", filePath)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check pending first
 	if entry, exists := a.pending[filePath]; exists && entry != nil {
 		return &filekit.FileInfo{
 			Name:        filepath.Base(filePath),
 			Path:        filePath,
-			Size:        int64(len(entry.content)),
+			Size:        entry.size(),
 			ModTime:     time.Now(),
 			IsDir:       entry.isDir,
-			ContentType: detectContentType(filePath, entry.content),
+			ContentType: filekit.ResolveContentType(filePath, "", entry.content),
 		}, nil
 	}
 
@@ -544,15 +572,11 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 		return nil, filekit.WrapPathErr("stat", filePath, filekit.ErrNotExist)
 	}
 
-	var size int64
+	size := entry.size()
 	var modTime time.Time
 	if entry.header != nil {
-		size = int64(entry.header.UncompressedSize64)
 		modTime = entry.header.Modified
 	}
-	if entry.content != nil {
-		size = int64(len(entry.content))
-	}
 
 	return &filekit.FileInfo{
 		Name:        filepath.Base(filePath),
@@ -560,7 +584,7 @@ func (a *Adapter) Stat(ctx context.Context, filePath string) (*filekit.FileInfo,
 		Size:        size,
 		ModTime:     modTime,
 		IsDir:       entry.isDir,
-		ContentType: detectContentType(filePath, entry.content),
+		ContentType: filekit.ResolveContentType(filePath, "", entry.content),
 	}, nil
 }
 
@@ -575,7 +599,10 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	prefix = normalizePath(prefix)
+	prefix, err := cleanPath("listcontents", prefix)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if prefix is a directory
 	if prefix != "" {
@@ -623,15 +650,11 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 			}
 			seen[entryPath] = true
 
-			var size int64
+			size := entry.size()
 			var modTime time.Time
 			if entry.header != nil {
-				size = int64(entry.header.UncompressedSize64)
 				modTime = entry.header.Modified
 			}
-			if entry.content != nil {
-				size = int64(len(entry.content))
-			}
 
 			files = append(files, filekit.FileInfo{
 				Name:        filepath.Base(entryPath),
@@ -639,7 +662,7 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 				Size:        size,
 				ModTime:     modTime,
 				IsDir:       entry.isDir,
-				ContentType: detectContentType(entryPath, entry.content),
+				ContentType: filekit.ResolveContentType(entryPath, "", entry.content),
 			})
 		} else {
 			// Non-recursive: only immediate children
@@ -662,15 +685,11 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 				return
 			}
 
-			var size int64
+			size := entry.size()
 			var modTime time.Time
 			if entry.header != nil {
-				size = int64(entry.header.UncompressedSize64)
 				modTime = entry.header.Modified
 			}
-			if entry.content != nil {
-				size = int64(len(entry.content))
-			}
 
 			files = append(files, filekit.FileInfo{
 				Name:        childName,
@@ -678,7 +697,7 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 				Size:        size,
 				ModTime:     modTime,
 				IsDir:       entry.isDir,
-				ContentType: detectContentType(childName, entry.content),
+				ContentType: filekit.ResolveContentType(childName, "", entry.content),
 			})
 		}
 	}
@@ -693,10 +712,7 @@ func (a *Adapter) ListContents(ctx context.Context, prefix string, recursive boo
 		processEntry(entryPath, entry)
 	}
 
-	// Sort by path
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Path < files[j].Path
-	})
+	filekit.SortFiles(files, filekit.SortByName, false)
 
 	return files, nil
 }
@@ -716,10 +732,9 @@ func (a *Adapter) CreateDir(ctx context.Context, dirPath string) error {
 		return filekit.WrapPathErr("createdir", dirPath, filekit.ErrNotAllowed)
 	}
 
-	dirPath = normalizePath(dirPath)
-
-	if !isValidPath(dirPath) {
-		return filekit.WrapPathErr("createdir", dirPath, filekit.ErrNotAllowed)
+	dirPath, err := cleanPath("createdir", dirPath)
+	if err != nil {
+		return err
 	}
 
 	// Check if file exists at path
@@ -772,7 +787,10 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 		return filekit.WrapPathErr("deletedir", dirPath, filekit.ErrNotAllowed)
 	}
 
-	dirPath = normalizePath(dirPath)
+	dirPath, err := cleanPath("deletedir", dirPath)
+	if err != nil {
+		return err
+	}
 
 	// Check if directory exists
 	entry, inFiles := a.files[dirPath]
@@ -806,17 +824,24 @@ func (a *Adapter) DeleteDir(ctx context.Context, dirPath string) error {
 	return nil
 }
 
-// UploadFile implements filekit.Uploader
-func (a *Adapter) UploadFile(ctx context.Context, destPath string, localPath string, options ...filekit.Option) (*filekit.WriteResult, error) {
+// WriteFile writes a local file to the archive
+func (a *Adapter) WriteFile(ctx context.Context, destPath string, localPath string, options ...filekit.Option) (*filekit.WriteResult, error) {
 	file, err := os.Open(localPath)
 	if err != nil {
-		return nil, filekit.WrapPathErr("uploadfile", localPath, err)
+		return nil, filekit.WrapPathErr("writefile", localPath, err)
 	}
 	defer file.Close()
 
 	return a.Write(ctx, destPath, file, options...)
 }
 
+// UploadFile writes a local file to the archive.
+//
+// Deprecated: Use WriteFile, which every driver implements.
+func (a *Adapter) UploadFile(ctx context.Context, destPath string, localPath string, options ...filekit.Option) (*filekit.WriteResult, error) {
+	return a.WriteFile(ctx, destPath, localPath, options...)
+}
+
 // ensureParentDirs creates parent directory entries
 func (a *Adapter) ensureParentDirs(filePath string) {
 	dir := path.Dir(filePath)
@@ -841,35 +866,23 @@ func (a *Adapter) ensureParentDirsPending(filePath string) {
 	}
 }
 
-// normalizePath normalizes a file path
-func normalizePath(p string) string {
-	p = strings.TrimPrefix(p, "/")
-	p = strings.TrimSuffix(p, "/")
-	if p == "" || p == "." {
-		return ""
+// cleanPath normalizes p with filekit.CleanPath, rejecting paths that escape
+// the archive root
+func cleanPath(op, p string) (string, error) {
+	clean, err := filekit.CleanPath(p)
+	if err != nil {
+		return "", filekit.WrapPathErr(op, p, err)
 	}
-	return path.Clean(p)
-}
-
-// isValidPath checks if path is valid (no traversal)
-func isValidPath(p string) bool {
-	return !strings.Contains(p, "..")
+	return clean, nil
 }
 
-// detectContentType determines content type from path and content
-func detectContentType(filePath string, content []byte) string {
-	ext := filepath.Ext(filePath)
-	if ext != "" {
-		if contentType := mime.TypeByExtension(ext); contentType != "" {
-			return contentType
-		}
-	}
-
-	if len(content) > 0 {
-		return http.DetectContentType(content)
+// entryName normalizes the name of an entry read from the archive. Names that
+// escape the archive root are kept, cleaned, so ExtractTo can reject them.
+func entryName(name string) string {
+	if clean, err := filekit.CleanPath(name); err == nil {
+		return clean
 	}
-
-	return "application/octet-stream"
+	return path.Clean(name)
 }
 
 // processOptions processes the provided options
@@ -885,6 +898,12 @@ func processOptions(options ...filekit.Option) *filekit.Options {
 // Optional Capability Interfaces
 // ============================================================================
 
+// Ping implements filekit.HealthChecker. The archive is held in memory, so it is always
+// reachable.
+func (a *Adapter) Ping(ctx context.Context) error {
+	return nil
+}
+
 // Copy implements filekit.CanCopy for in-memory ZIP file copying.
 func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 	select {
@@ -900,41 +919,32 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("copy", src, filekit.ErrNotAllowed)
 	}
 
-	src = normalizePath(src)
-	dst = normalizePath(dst)
-
-	if !isValidPath(src) || !isValidPath(dst) {
-		return filekit.WrapPathErr("copy", src, filekit.ErrNotAllowed)
+	src, err := cleanPath("copy", src)
+	if err != nil {
+		return err
+	}
+	dst, err = cleanPath("copy", dst)
+	if err != nil {
+		return err
 	}
 
-	// Get source content
-	var content []byte
-	if entry, exists := a.pending[src]; exists {
-		content = make([]byte, len(entry.content))
-		copy(content, entry.content)
-	} else if entry, exists := a.files[src]; exists {
-		if entry.content != nil {
-			content = make([]byte, len(entry.content))
-			copy(content, entry.content)
-		} else if entry.header != nil {
-			// Read from ZIP
-			rc, err := a.reader.Open(entry.header.Name)
-			if err != nil {
-				return filekit.WrapPathErr("copy", src, err)
-			}
-			content, err = io.ReadAll(rc)
-			rc.Close()
-			if err != nil {
-				return filekit.WrapPathErr("copy", src, err)
-			}
-		}
-	} else {
+	// Get source entry; one still in the archive is shared, not read
+	entry, exists := a.pending[src]
+	if !exists {
+		entry, exists = a.files[src]
+	}
+	if !exists || entry == nil {
 		return filekit.WrapPathErr("copy", src, filekit.ErrNotExist)
 	}
+	if src == dst {
+		return nil
+	}
 
 	// Create destination
 	a.pending[dst] = &zipEntry{
-		content: content,
+		header:  entry.header,
+		file:    entry.file,
+		content: bytes.Clone(entry.content),
 		isDir:   false,
 	}
 	a.ensureParentDirsPending(dst)
@@ -944,7 +954,7 @@ func (a *Adapter) Copy(ctx context.Context, src, dst string) error {
 }
 
 // Move implements filekit.CanMove for in-memory ZIP file moving.
-func (a *Adapter) Move(ctx context.Context, src, dst string) error {
+func (a *Adapter) Move(ctx context.Context, src, dst string, opts ...filekit.MoveOption) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -958,11 +968,13 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 		return filekit.WrapPathErr("move", src, filekit.ErrNotAllowed)
 	}
 
-	src = normalizePath(src)
-	dst = normalizePath(dst)
-
-	if !isValidPath(src) || !isValidPath(dst) {
-		return filekit.WrapPathErr("move", src, filekit.ErrNotAllowed)
+	src, err := cleanPath("move", src)
+	if err != nil {
+		return err
+	}
+	dst, err = cleanPath("move", dst)
+	if err != nil {
+		return err
 	}
 
 	// Get source entry
@@ -972,25 +984,22 @@ func (a *Adapter) Move(ctx context.Context, src, dst string) error {
 		entry = e
 		fromPending = true
 	} else if e, exists := a.files[src]; exists {
-		// Need to load content first
-		var content []byte
-		if e.content != nil {
-			content = e.content
-		} else if e.header != nil {
-			rc, err := a.reader.Open(e.header.Name)
-			if err != nil {
-				return filekit.WrapPathErr("move", src, err)
-			}
-			content, err = io.ReadAll(rc)
-			rc.Close()
-			if err != nil {
-				return filekit.WrapPathErr("move", src, err)
-			}
-		}
-		entry = &zipEntry{content: content, isDir: e.isDir}
+		entry = e
 	} else {
 		return filekit.WrapPathErr("move", src, filekit.ErrNotExist)
 	}
+	if src == dst {
+		return nil // Deleting the source would delete the file
+	}
+	if filekit.ApplyMoveOptions(opts...).NoOverwrite {
+		existing, exists := a.pending[dst]
+		if !exists {
+			existing, exists = a.files[dst]
+		}
+		if exists && existing != nil {
+			return filekit.WrapPathErr("move", dst, filekit.ErrExist)
+		}
+	}
 
 	// Add to destination
 	a.pending[dst] = entry
@@ -1040,6 +1049,101 @@ func (a *Adapter) Checksums(ctx context.Context, filePath string, algorithms []f
 	return checksums, nil
 }
 
+// ============================================================================
+// Extraction
+// ============================================================================
+
+// ExtractTo copies the files under srcPrefix into dst, streaming each through
+// dst.Write. Paths relative to srcPrefix are kept under dstPrefix; if
+// srcPrefix names a single file it is written to dstPrefix/<base name>. An
+// empty srcPrefix extracts the whole archive.
+//
+// Entries whose names would escape the archive (e.g. "../x") are rejected
+// before anything is written. Existing files in dst are not overwritten, and
+// directories are implied by the files written rather than created.
+//
+// Example:
+//
+//	archive, _ := zip.Open("site.zip")
+//	defer archive.Close()
+//	err := archive.ExtractTo(ctx, memory.New(), "public", "www")
+func (a *Adapter) ExtractTo(ctx context.Context, dst filekit.FileSystem, srcPrefix, dstPrefix string) error {
+	srcPrefix, err := cleanPath("extract", srcPrefix)
+	if err != nil {
+		return err
+	}
+	dstPrefix, err = cleanPath("extract", dstPrefix)
+	if err != nil {
+		return err
+	}
+
+	type extractEntry struct {
+		name  string
+		entry *zipEntry
+	}
+
+	a.mu.RLock()
+	var entries []extractEntry
+	var srcIsDir bool
+	collect := func(name string, entry *zipEntry) {
+		if entry == nil {
+			return
+		}
+		if name == srcPrefix {
+			srcIsDir = entry.isDir
+		}
+		if entry.isDir {
+			return
+		}
+		if srcPrefix == "" || name == srcPrefix || strings.HasPrefix(name, srcPrefix+"/") {
+			entries = append(entries, extractEntry{name, entry})
+		}
+	}
+	for name, entry := range a.files {
+		if _, shadowed := a.pending[name]; !shadowed {
+			collect(name, entry)
+		}
+	}
+	for name, entry := range a.pending {
+		collect(name, entry)
+	}
+	a.mu.RUnlock()
+
+	if len(entries) == 0 && srcPrefix != "" && !srcIsDir {
+		return filekit.WrapPathErr("extract", srcPrefix, filekit.ErrNotExist)
+	}
+	for _, e := range entries {
+		if _, err := filekit.CleanPath(e.name); err != nil {
+			return filekit.NewPathError("extract", e.name, filekit.ErrCodePermission, "entry path escapes the archive")
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(e.name, srcPrefix+"/")
+		if srcPrefix == "" {
+			rel = e.name
+		} else if e.name == srcPrefix {
+			rel = path.Base(e.name)
+		}
+
+		rc, err := e.entry.open()
+		if err != nil {
+			return filekit.WrapPathErr("extract", e.name, err)
+		}
+		_, err = dst.Write(ctx, path.Join(dstPrefix, rel), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ============================================================================
 // Watcher Implementation
 // ============================================================================
@@ -1057,11 +1161,13 @@ func (a *Adapter) Watch(ctx context.Context, filter string) (filekit.ChangeToken
 
 // Ensure Adapter implements interfaces
 var (
-	_ filekit.FileSystem  = (*Adapter)(nil)
-	_ filekit.FileReader  = (*Adapter)(nil)
-	_ filekit.FileWriter  = (*Adapter)(nil)
-	_ filekit.CanCopy     = (*Adapter)(nil)
-	_ filekit.CanMove     = (*Adapter)(nil)
-	_ filekit.CanChecksum = (*Adapter)(nil)
-	_ filekit.CanWatch    = (*Adapter)(nil)
+	_ filekit.FileSystem        = (*Adapter)(nil)
+	_ filekit.FileReader        = (*Adapter)(nil)
+	_ filekit.FileWriter        = (*Adapter)(nil)
+	_ filekit.CanCopy           = (*Adapter)(nil)
+	_ filekit.CanMove           = (*Adapter)(nil)
+	_ filekit.CanWriteLocalFile = (*Adapter)(nil)
+	_ filekit.CanChecksum       = (*Adapter)(nil)
+	_ filekit.CanWatch          = (*Adapter)(nil)
+	_ filekit.HealthChecker     = (*Adapter)(nil)
 )
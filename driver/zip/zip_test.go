@@ -4,13 +4,17 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
 )
 
 func TestCreate(t *testing.T) {
@@ -85,6 +89,20 @@ func TestWrite(t *testing.T) {
 		}
 	})
 
+	t.Run("reports content type", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		fs, _ := Create(filepath.Join(tmpDir, "test.zip"))
+		defer fs.Close()
+
+		result, err := fs.Write(ctx, "logo.png", strings.NewReader("png bytes"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.ContentType != "image/png" {
+			t.Errorf("expected ContentType=image/png, got %q", result.ContentType)
+		}
+	})
+
 	t.Run("fails on read-only zip", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		zipPath := filepath.Join(tmpDir, "test.zip")
@@ -158,6 +176,29 @@ func TestWrite(t *testing.T) {
 			t.Error("expected error for path traversal")
 		}
 	})
+
+	t.Run("normalizes paths like the other drivers", func(t *testing.T) {
+		fs, _ := Create(filepath.Join(t.TempDir(), "test.zip"))
+		defer fs.Close()
+
+		if _, err := fs.Write(ctx, "./a//b.txt", strings.NewReader("data")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		for _, p := range []string{"a/b.txt", "/a/b.txt", "x/../a/b.txt"} {
+			if exists, err := fs.FileExists(ctx, p); err != nil || !exists {
+				t.Errorf("FileExists(%q) = %v, %v; want true", p, exists, err)
+			}
+		}
+		for _, p := range []string{"a/../../etc", "/../etc/passwd"} {
+			if _, err := fs.FileExists(ctx, p); !errors.Is(err, filekit.ErrNotAllowed) {
+				t.Errorf("FileExists(%q) = %v, want ErrNotAllowed", p, err)
+			}
+		}
+		// ".." inside a name is not traversal
+		if _, err := fs.Write(ctx, "v1..2.txt", strings.NewReader("data")); err != nil {
+			t.Errorf("Write(v1..2.txt): %v", err)
+		}
+	})
 }
 
 func TestRead(t *testing.T) {
@@ -565,6 +606,79 @@ func TestZipPersistence(t *testing.T) {
 	})
 }
 
+func TestOpenOrCreate_LazyEntries(t *testing.T) {
+	ctx := context.Background()
+	zipPath := filepath.Join(t.TempDir(), "large.zip")
+
+	// 16 entries of 4 MiB each: 64 MiB uncompressed, a few KiB on disk
+	const entrySize = 4 << 20
+	names := make([]string, 16)
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	chunk := bytes.Repeat([]byte("x"), 64<<10)
+	for i := range names {
+		names[i] = fmt.Sprintf("data/part-%02d.bin", i)
+		ew, err := w.Create(names[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < entrySize; n += len(chunk) {
+			if _, err := ew.Write(chunk); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	fs, err := OpenOrCreate(zipPath)
+	if err != nil {
+		t.Fatalf("OpenOrCreate: %v", err)
+	}
+	if info, err := fs.Stat(ctx, names[3]); err != nil || info.Size != entrySize {
+		t.Fatalf("Stat = %+v, %v; want size %d", info, err, entrySize)
+	}
+	if _, err := fs.Write(ctx, names[0], strings.NewReader("changed"), filekit.WithOverwrite(true)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fs.Copy(ctx, names[1], "copy.bin"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	runtime.ReadMemStats(&after)
+	// Loading every entry would allocate well over 64 MiB
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+		t.Errorf("read-write session allocated %d bytes, want far less than the %d bytes of content", allocated, len(names)*entrySize)
+	}
+
+	reopened, err := Open(zipPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer reopened.Close()
+	if got, err := reopened.ReadAll(ctx, names[0]); err != nil || string(got) != "changed" {
+		t.Errorf("modified entry = %q, %v; want %q", got, err, "changed")
+	}
+	for _, name := range append(names[1:], "copy.bin") {
+		got, err := reopened.ReadAll(ctx, name)
+		if err != nil || len(got) != entrySize || bytes.Count(got, []byte("x")) != entrySize {
+			t.Errorf("%s: %d bytes, %v; want %d unchanged bytes", name, len(got), err, entrySize)
+		}
+	}
+}
+
 func TestImplementsInterface(t *testing.T) {
 	var _ filekit.FileSystem = (*Adapter)(nil)
 }
@@ -619,3 +733,213 @@ func createTestZip(t *testing.T, zipPath string, files map[string]string) {
 		t.Fatalf("failed to close test zip: %v", err)
 	}
 }
+
+func TestExtractTo(t *testing.T) {
+	ctx := context.Background()
+	zipPath := filepath.Join(t.TempDir(), "site.zip")
+	createTestZip(t, zipPath, map[string]string{
+		"public/index.html":     "<h1>home</h1>",
+		"public/css/site.css":   "body{}",
+		"public/img/a/logo.svg": "<svg/>",
+		"private/key.pem":       "secret",
+	})
+
+	archive, err := Open(zipPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer archive.Close()
+
+	dst := memory.New()
+	if err := archive.ExtractTo(ctx, dst, "public", "www"); err != nil {
+		t.Fatalf("ExtractTo: %v", err)
+	}
+	want := map[string]string{
+		"www/index.html":     "<h1>home</h1>",
+		"www/css/site.css":   "body{}",
+		"www/img/a/logo.svg": "<svg/>",
+	}
+	for p, content := range want {
+		got, err := dst.ReadAll(ctx, p)
+		if err != nil || string(got) != content {
+			t.Errorf("%s = %q, %v; want %q", p, got, err, content)
+		}
+	}
+	if exists, _ := dst.FileExists(ctx, "www/private/key.pem"); exists {
+		t.Error("entry outside srcPrefix was extracted")
+	}
+	files, _ := dst.ListContents(ctx, "", true)
+	if n := countFiles(files); n != len(want) {
+		t.Errorf("extracted %d files, want %d", n, len(want))
+	}
+
+	t.Run("single file", func(t *testing.T) {
+		dst := memory.New()
+		if err := archive.ExtractTo(ctx, dst, "private/key.pem", "keys"); err != nil {
+			t.Fatalf("ExtractTo: %v", err)
+		}
+		if got, err := dst.ReadAll(ctx, "keys/key.pem"); err != nil || string(got) != "secret" {
+			t.Errorf("keys/key.pem = %q, %v; want %q", got, err, "secret")
+		}
+	})
+
+	t.Run("missing prefix", func(t *testing.T) {
+		err := archive.ExtractTo(ctx, memory.New(), "nope", "")
+		if !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+			t.Errorf("ExtractTo(missing) = %v, want ErrCodeNotFound", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		dst := memory.New()
+		if err := archive.ExtractTo(ctx, dst, "", ""); !errors.Is(err, context.Canceled) {
+			t.Errorf("ExtractTo(canceled) = %v, want context.Canceled", err)
+		}
+		if files, _ := dst.ListContents(context.Background(), "", true); len(files) != 0 {
+			t.Errorf("canceled extract wrote %d entries", len(files))
+		}
+	})
+}
+
+func TestExtractTo_RejectsTraversal(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "evil.zip")
+	createTestZip(t, zipPath, map[string]string{
+		"ok.txt":          "fine",
+		"../../etc/evil":  "pwned",
+		"docs/../../evil": "pwned",
+	})
+
+	archive, err := Open(zipPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer archive.Close()
+
+	dst := memory.New()
+	err = archive.ExtractTo(context.Background(), dst, "", "out")
+	if !filekit.IsCode(err, filekit.ErrCodePermission) {
+		t.Errorf("ExtractTo = %v, want ErrCodePermission", err)
+	}
+	if files, _ := dst.ListContents(context.Background(), "", true); len(files) != 0 {
+		t.Errorf("ExtractTo wrote %d entries before rejecting the archive", len(files))
+	}
+}
+
+func countFiles(files []filekit.FileInfo) int {
+	n := 0
+	for _, f := range files {
+		if !f.IsDir {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMove_NoOverwrite(t *testing.T) {
+	ctx := context.Background()
+	zipPath := filepath.Join(t.TempDir(), "move.zip")
+	createTestZip(t, zipPath, map[string]string{"a.txt": "a", "b.txt": "b"})
+
+	fs, err := OpenOrCreate(zipPath)
+	if err != nil {
+		t.Fatalf("OpenOrCreate: %v", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+		t.Fatalf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if got, err := fs.ReadAll(ctx, "b.txt"); err != nil || string(got) != "b" {
+		t.Errorf("ReadAll(b.txt) after refused move = %q, %v; want %q", got, err, "b")
+	}
+
+	if err := fs.Move(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Move onto existing: %v", err)
+	}
+	if got, err := fs.ReadAll(ctx, "b.txt"); err != nil || string(got) != "a" {
+		t.Errorf("ReadAll(b.txt) after move = %q, %v; want %q", got, err, "a")
+	}
+}
+
+func TestMove_SamePath(t *testing.T) {
+	ctx := context.Background()
+	zipPath := filepath.Join(t.TempDir(), "same.zip")
+	createTestZip(t, zipPath, map[string]string{"docs/a.txt": "keep me"})
+
+	fs, err := OpenOrCreate(zipPath)
+	if err != nil {
+		t.Fatalf("OpenOrCreate: %v", err)
+	}
+	if err := fs.Move(ctx, "docs/a.txt", "/docs/a.txt"); err != nil {
+		t.Errorf("Move onto itself: %v", err)
+	}
+	if err := fs.Copy(ctx, "docs/a.txt", "docs/a.txt"); err != nil {
+		t.Errorf("Copy onto itself: %v", err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := Open(zipPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer reopened.Close()
+	if got, err := reopened.ReadAll(ctx, "docs/a.txt"); err != nil || string(got) != "keep me" {
+		t.Errorf("ReadAll after same-path move = %q, %v; want %q", got, err, "keep me")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(localPath, []byte("archived notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := Create(filepath.Join(dir, "out.zip"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer fs.Close()
+
+	result, err := fs.WriteFile(ctx, "docs/notes.txt", localPath)
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if result.BytesWritten != int64(len("archived notes")) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("archived notes"))
+	}
+	if _, err := fs.WriteFile(ctx, "missing.txt", filepath.Join(dir, "missing.txt")); !errors.Is(err, filekit.ErrNotExist) {
+		t.Errorf("WriteFile(missing local file) = %v, want ErrNotExist", err)
+	}
+}
+
+func TestStat_ContentType(t *testing.T) {
+	ctx := context.Background()
+	fs, err := Create(filepath.Join(t.TempDir(), "types.zip"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer fs.Close()
+
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	for _, tt := range []struct{ path, content, want string }{
+		{"data.json", `{"ok": true}`, "application/json"},
+		{"upload", png, "image/png"},
+	} {
+		if _, err := fs.Write(ctx, tt.path, strings.NewReader(tt.content)); err != nil {
+			t.Fatalf("Write(%s): %v", tt.path, err)
+		}
+		info, err := fs.Stat(ctx, tt.path)
+		if err != nil {
+			t.Fatalf("Stat(%s): %v", tt.path, err)
+		}
+		if info.ContentType != tt.want {
+			t.Errorf("Stat(%s).ContentType = %q, want %q", tt.path, info.ContentType, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package filekit
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/aes"
@@ -11,11 +12,27 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"sync"
 )
 
-// Encryption format version for forward compatibility.
-const encryptionFormatVersion byte = 1
+// Encryption format versions for forward compatibility.
+// Version 1 files have no magic prefix and no key version, are decrypted
+// with key version 1 and without AAD, and do not mark the final chunk, so
+// truncation at a chunk boundary goes undetected for them.
+const (
+	legacyFormatVersion     byte = 1
+	encryptionFormatVersion byte = 2
+)
+
+// encryptionMagic starts every file written in the current format.
+var encryptionMagic = []byte("FKENC1")
+
+// Header sizes of the encryption formats.
+const (
+	legacyHeaderSize = 17
+	headerSize       = 27
+)
 
 // Default chunk size for encryption (64KB plaintext per chunk).
 // Each chunk will be slightly larger after encryption due to GCM overhead.
@@ -35,6 +52,9 @@ var (
 	ErrChunkSizeTooSmall    = errors.New("chunk size must be at least 1024 bytes")
 	ErrChunkSizeTooLarge    = errors.New("chunk size must be at most 16MB")
 	ErrInvalidChunkSequence = errors.New("chunk sequence number mismatch")
+	ErrUnknownKeyVersion    = errors.New("no key registered for key version")
+	ErrNotEncrypted         = errors.New("file is not encrypted by EncryptedFS")
+	ErrAlreadyEncrypted     = errors.New("content is already encrypted by EncryptedFS")
 )
 
 // Buffer pools for reducing allocations.
@@ -50,27 +70,51 @@ var (
 
 // EncryptedFS wraps a FileSystem to provide transparent AES-256-GCM encryption.
 //
-// File format (version 1):
+// File format (version 2):
 //
-//	Header (17 bytes):
-//	  - Version (1 byte): Format version (currently 1)
+//	Header (27 bytes):
+//	  - Magic (6 bytes): "FKENC1"
+//	  - Version (1 byte): Format version (currently 2)
+//	  - Key version (4 bytes): Big-endian uint32, version of the key that encrypted the file
 //	  - Chunk size (4 bytes): Big-endian uint32, plaintext chunk size
 //	  - Base nonce (12 bytes): Random nonce used to derive per-chunk nonces
 //
-//	Chunks (repeated until EOF):
+//	Chunks (repeated until EOF, at least one):
 //	  - Chunk length (4 bytes): Big-endian uint32, length of encrypted chunk
 //	  - Chunk sequence (4 bytes): Big-endian uint32, chunk number (for nonce derivation)
 //	  - Encrypted data (variable): GCM-encrypted chunk with 16-byte auth tag
 //
+// Content is encrypted and decrypted chunk by chunk, so Read and Write stream
+// and memory use is bounded by the chunk size regardless of file size.
+//
 // Security properties:
 //   - Each chunk uses a unique nonce derived from: base_nonce XOR chunk_sequence
 //   - GCM provides authenticated encryption (confidentiality + integrity)
 //   - Chunk sequence prevents chunk reordering attacks
+//   - The file path is bound as GCM additional authenticated data, so
+//     ciphertext copied to another path fails to decrypt
+//   - The final chunk is authenticated as final (a flag byte appended to the
+//     AAD), so dropping trailing chunks is detected as truncation
 //   - Format version allows future algorithm upgrades
+//   - The magic prefix makes misuse loud: reading a plaintext file fails with
+//     ErrNotEncrypted instead of returning garbage, and writing content that
+//     already carries the prefix fails with ErrAlreadyEncrypted, since it is
+//     most likely ciphertext copied from the underlying filesystem. Stacked
+//     EncryptedFS layers still work: content written by one layer is accepted
+//     by the next.
+//   - Key version allows files encrypted with different keys to coexist,
+//     so reads keep working while keys are rotated (see Rotate)
+//
+// Version 1 files (17-byte header without magic or key version) are still
+// readable: they are decrypted with key version 1, without AAD and without a
+// final-chunk flag. Rotate upgrades them to the current format.
 type EncryptedFS struct {
 	fs        FileSystem
-	key       []byte
 	chunkSize int
+
+	mu     sync.RWMutex
+	keys   map[uint32][]byte
+	active uint32
 }
 
 // EncryptedFSOption configures an EncryptedFS.
@@ -84,6 +128,22 @@ func WithChunkSize(size int) EncryptedFSOption {
 	}
 }
 
+// WithKeyVersion sets the version of the key passed to NewEncryptedFS.
+// New files record it in their header. Default is 1.
+func WithKeyVersion(version uint32) EncryptedFSOption {
+	return func(e *EncryptedFS) {
+		e.active = version
+	}
+}
+
+// WithPreviousKey registers an older key used only to decrypt files that
+// were encrypted with it, e.g. files not yet re-encrypted after a rotation.
+func WithPreviousKey(version uint32, key []byte) EncryptedFSOption {
+	return func(e *EncryptedFS) {
+		e.keys[version] = append([]byte(nil), key...)
+	}
+}
+
 // NewEncryptedFS creates a new encrypted filesystem wrapper.
 //
 // The key must be exactly 32 bytes for AES-256.
@@ -106,18 +166,24 @@ func NewEncryptedFS(fs FileSystem, key []byte, opts ...EncryptedFSOption) (*Encr
 
 	e := &EncryptedFS{
 		fs:        fs,
-		key:       make([]byte, 32),
 		chunkSize: defaultChunkSize,
+		keys:      make(map[uint32][]byte),
+		active:    1,
 	}
 
-	// Copy key to prevent external modification.
-	copy(e.key, key)
-
 	// Apply options.
 	for _, opt := range opts {
 		opt(e)
 	}
 
+	// Copy key to prevent external modification.
+	e.keys[e.active] = append([]byte(nil), key...)
+	for _, k := range e.keys {
+		if len(k) != 32 {
+			return nil, ErrInvalidKey
+		}
+	}
+
 	// Validate chunk size.
 	if e.chunkSize < 1024 {
 		return nil, ErrChunkSizeTooSmall
@@ -129,6 +195,28 @@ func NewEncryptedFS(fs FileSystem, key []byte, opts ...EncryptedFSOption) (*Encr
 	return e, nil
 }
 
+// KeyVersion returns the version of the key used to encrypt new files.
+func (e *EncryptedFS) KeyVersion() uint32 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.active
+}
+
+// activeKey returns the version and key used to encrypt new files.
+func (e *EncryptedFS) activeKey() (uint32, []byte) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.active, e.keys[e.active]
+}
+
+// keyFor returns the key registered for version.
+func (e *EncryptedFS) keyFor(version uint32) ([]byte, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	key, ok := e.keys[version]
+	return key, ok
+}
+
 // Write encrypts content and writes it to the underlying filesystem.
 func (e *EncryptedFS) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
 	// Check context before starting.
@@ -136,139 +224,192 @@ func (e *EncryptedFS) Write(ctx context.Context, path string, content io.Reader,
 		return nil, WrapPath(err, "encrypt", path, ErrCodeAborted, "context canceled")
 	}
 
-	// Create cipher.
-	block, err := aes.NewCipher(e.key)
+	// Refuse ciphertext unless it comes from a stacked EncryptedFS.
+	opts := Options{}
+	for _, option := range options {
+		option(&opts)
+	}
+	source := bufio.NewReader(content)
+	if !opts.encryptedContent {
+		if prefix, _ := source.Peek(len(encryptionMagic)); bytes.Equal(prefix, encryptionMagic) {
+			return nil, WrapPath(ErrAlreadyEncrypted, "encrypt", path, ErrCodeInvalidInput, "content is already encrypted")
+		}
+	}
+
+	version, key := e.activeKey()
+	gcm, baseNonce, err := newEncryptionCipher(key, path)
 	if err != nil {
-		return nil, WrapPath(fmt.Errorf("%w: cipher creation failed: %w", ErrEncryptionFailed, err),
+		return nil, err
+	}
+
+	// Create pipe for streaming encrypted data.
+	pr, pw := io.Pipe()
+
+	// Channel to communicate errors from goroutine.
+	errChan := make(chan error, 1)
+
+	go func() {
+		encryptErr := e.encryptStream(ctx, pw, source, gcm, baseNonce, version, pathAAD(path))
+		if encryptErr != nil {
+			pw.CloseWithError(encryptErr)
+		} else {
+			pw.Close()
+		}
+		errChan <- encryptErr
+	}()
+
+	// Write to underlying filesystem.
+	result, writeErr := e.fs.Write(ctx, path, pr, append(options, withEncryptedContent())...)
+
+	// Unblock the encryption goroutine if the write returned without
+	// draining the pipe, as a failed precondition does.
+	pr.CloseWithError(writeErr)
+
+	// Wait for encryption goroutine to finish and check for errors.
+	encryptErr := <-errChan
+
+	if writeErr != nil {
+		return nil, writeErr
+	}
+	if encryptErr != nil {
+		return nil, WrapPath(encryptErr, "encrypt", path, ErrCodeInternal, "encryption failed")
+	}
+
+	return result, nil
+}
+
+// newEncryptionCipher creates the AEAD and a random base nonce for encrypting one file.
+func newEncryptionCipher(key []byte, path string) (cipher.AEAD, []byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, WrapPath(fmt.Errorf("%w: cipher creation failed: %w", ErrEncryptionFailed, err),
 			"encrypt", path, ErrCodeInternal, "cipher creation failed")
 	}
 
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return nil, WrapPath(fmt.Errorf("%w: GCM creation failed: %w", ErrEncryptionFailed, err),
+		return nil, nil, WrapPath(fmt.Errorf("%w: GCM creation failed: %w", ErrEncryptionFailed, err),
 			"encrypt", path, ErrCodeInternal, "GCM creation failed")
 	}
 
 	// Generate base nonce.
 	baseNonce := make([]byte, gcm.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, baseNonce); err != nil {
-		return nil, WrapPath(fmt.Errorf("%w: failed to generate nonce: %w", ErrEncryptionFailed, err),
+		return nil, nil, WrapPath(fmt.Errorf("%w: failed to generate nonce: %w", ErrEncryptionFailed, err),
 			"encrypt", path, ErrCodeInternal, "nonce generation failed")
 	}
 
-	// Create pipe for streaming encrypted data.
-	pr, pw := io.Pipe()
-
-	// Channel to communicate errors from goroutine.
-	errChan := make(chan error, 1)
+	return gcm, baseNonce, nil
+}
 
-	go func() {
-		var encryptErr error
-		defer func() {
-			if encryptErr != nil {
-				pw.CloseWithError(encryptErr)
-			} else {
-				pw.Close()
-			}
-			errChan <- encryptErr
-		}()
-
-		// Write header.
-		header := make([]byte, 17)
-		header[0] = encryptionFormatVersion
-		binary.BigEndian.PutUint32(header[1:5], uint32(e.chunkSize)) //nolint:gosec // chunkSize is validated to be <= 16MB in WithChunkSize
-		copy(header[5:17], baseNonce)
-
-		if _, err := pw.Write(header); err != nil {
-			encryptErr = err
-			return
-		}
+// pathAAD returns the additional authenticated data binding ciphertext to a path.
+// The path is normalized so that equivalent spellings ("a.txt", "/a.txt") match.
+func pathAAD(p string) []byte {
+	return []byte(path.Clean("/" + p))
+}
 
-		// Get buffer from pool.
-		plaintextBufPtr := plaintextPool.Get().(*[]byte)
-		plaintextBuf := *plaintextBufPtr
-		// Ensure buffer is large enough for our chunk size.
-		if len(plaintextBuf) < e.chunkSize {
-			plaintextBuf = make([]byte, e.chunkSize)
-			*plaintextBufPtr = plaintextBuf
-		}
-		defer plaintextPool.Put(plaintextBufPtr)
+// encryptStream writes the header and encrypted chunks of content to w.
+// Every chunk is sealed with aad followed by a final-chunk flag byte as
+// additional authenticated data. Empty content produces one empty final chunk.
+func (e *EncryptedFS) encryptStream(ctx context.Context, w io.Writer, source io.Reader, gcm cipher.AEAD, baseNonce []byte, keyVersion uint32, aad []byte) error {
+	// Write header.
+	header := make([]byte, headerSize)
+	n := copy(header, encryptionMagic)
+	header[n] = encryptionFormatVersion
+	binary.BigEndian.PutUint32(header[n+1:n+5], keyVersion)
+	binary.BigEndian.PutUint32(header[n+5:n+9], uint32(e.chunkSize)) //nolint:gosec // chunkSize is validated to be <= 16MB in WithChunkSize
+	copy(header[n+9:], baseNonce)
 
-		// Pre-allocate ciphertext buffer (plaintext + GCM overhead).
-		ciphertextBuf := make([]byte, 0, e.chunkSize+gcm.Overhead())
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
 
-		// Chunk header buffer (4 bytes length + 4 bytes sequence).
-		chunkHeader := make([]byte, 8)
+	// Get buffer from pool.
+	plaintextBufPtr := plaintextPool.Get().(*[]byte)
+	plaintextBuf := *plaintextBufPtr
+	// Ensure buffer is large enough for our chunk size.
+	if len(plaintextBuf) < e.chunkSize {
+		plaintextBuf = make([]byte, e.chunkSize)
+		*plaintextBufPtr = plaintextBuf
+	}
+	defer plaintextPool.Put(plaintextBufPtr)
 
-		// Nonce buffer for per-chunk nonce derivation.
-		chunkNonce := make([]byte, gcm.NonceSize())
+	// Pre-allocate ciphertext buffer (plaintext + GCM overhead).
+	ciphertextBuf := make([]byte, 0, e.chunkSize+gcm.Overhead())
 
-		var chunkSeq uint32 = 0
+	// Chunk header buffer (4 bytes length + 4 bytes sequence).
+	chunkHeader := make([]byte, 8)
 
-		for {
-			// Check context.
-			select {
-			case <-ctx.Done():
-				encryptErr = ctx.Err()
-				return
-			default:
-			}
+	// Nonce buffer for per-chunk nonce derivation.
+	chunkNonce := make([]byte, gcm.NonceSize())
 
-			// Read plaintext chunk.
-			n, readErr := io.ReadFull(content, plaintextBuf[:e.chunkSize])
-			if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
-				encryptErr = readErr
-				return
-			}
+	// AAD buffer: path followed by the final-chunk flag.
+	chunkAAD := append(append(make([]byte, 0, len(aad)+1), aad...), 0)
 
-			if n == 0 {
-				break
-			}
+	// Buffered so we can peek whether a full chunk is the last one.
+	content := bufio.NewReader(source)
 
-			// Derive per-chunk nonce: baseNonce XOR chunkSeq.
-			copy(chunkNonce, baseNonce)
-			binary.BigEndian.PutUint32(chunkNonce[len(chunkNonce)-4:], chunkSeq)
+	var chunkSeq uint32 = 0
 
-			// Encrypt chunk (reuse ciphertext buffer).
-			ciphertextBuf = gcm.Seal(ciphertextBuf[:0], chunkNonce, plaintextBuf[:n], nil)
+	for {
+		// Check context.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
 
-			// Write chunk header.
-			binary.BigEndian.PutUint32(chunkHeader[0:4], uint32(len(ciphertextBuf))) //nolint:gosec // ciphertextBuf is bounded by chunkSize + GCM overhead
-			binary.BigEndian.PutUint32(chunkHeader[4:8], chunkSeq)
+		// Read plaintext chunk.
+		n, readErr := io.ReadFull(content, plaintextBuf[:e.chunkSize])
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
 
-			if _, err := pw.Write(chunkHeader); err != nil {
-				encryptErr = err
-				return
+		final := readErr != nil
+		if !final {
+			if _, err := content.Peek(1); err != nil {
+				if err != io.EOF {
+					return err
+				}
+				final = true
 			}
+		}
+		chunkAAD[len(chunkAAD)-1] = finalFlag(final)
 
-			// Write encrypted chunk.
-			if _, err := pw.Write(ciphertextBuf); err != nil {
-				encryptErr = err
-				return
-			}
+		// Derive per-chunk nonce: baseNonce XOR chunkSeq.
+		copy(chunkNonce, baseNonce)
+		binary.BigEndian.PutUint32(chunkNonce[len(chunkNonce)-4:], chunkSeq)
 
-			chunkSeq++
+		// Encrypt chunk (reuse ciphertext buffer).
+		ciphertextBuf = gcm.Seal(ciphertextBuf[:0], chunkNonce, plaintextBuf[:n], chunkAAD)
 
-			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
-				break
-			}
+		// Write chunk header.
+		binary.BigEndian.PutUint32(chunkHeader[0:4], uint32(len(ciphertextBuf))) //nolint:gosec // ciphertextBuf is bounded by chunkSize + GCM overhead
+		binary.BigEndian.PutUint32(chunkHeader[4:8], chunkSeq)
+
+		if _, err := w.Write(chunkHeader); err != nil {
+			return err
 		}
-	}()
 
-	// Write to underlying filesystem.
-	result, writeErr := e.fs.Write(ctx, path, pr, options...)
+		// Write encrypted chunk.
+		if _, err := w.Write(ciphertextBuf); err != nil {
+			return err
+		}
 
-	// Wait for encryption goroutine to finish and check for errors.
-	encryptErr := <-errChan
+		chunkSeq++
 
-	if writeErr != nil {
-		return nil, writeErr
-	}
-	if encryptErr != nil {
-		return nil, WrapPath(encryptErr, "encrypt", path, ErrCodeInternal, "encryption failed")
+		if final {
+			return nil
+		}
 	}
+}
 
-	return result, nil
+// finalFlag returns the AAD flag byte marking the final chunk.
+func finalFlag(final bool) byte {
+	if final {
+		return 1
+	}
+	return 0
 }
 
 // Read decrypts and returns file content from the underlying filesystem.
@@ -285,7 +426,7 @@ func (e *EncryptedFS) Read(ctx context.Context, path string) (io.ReadCloser, err
 	}
 
 	// Create decrypting reader.
-	decReader, err := newDecryptingReader(ctx, encryptedReader, e.key, path)
+	decReader, err := newDecryptingReader(ctx, encryptedReader, e.keyFor, path)
 	if err != nil {
 		encryptedReader.Close()
 		return nil, err
@@ -298,7 +439,12 @@ func (e *EncryptedFS) Read(ctx context.Context, path string) (io.ReadCloser, err
 type decryptingReader struct {
 	ctx        context.Context
 	source     io.ReadCloser
+	reader     *bufio.Reader // Buffered source, to detect the final chunk.
 	gcm        cipher.AEAD
+	format     byte
+	keyVersion uint32
+	aad        []byte // Path and final-chunk flag bound as additional authenticated data (format 2).
+	done       bool   // Final chunk has been decrypted (format 2).
 	baseNonce  []byte
 	chunkSize  int
 	chunkSeq   uint32
@@ -310,7 +456,53 @@ type decryptingReader struct {
 }
 
 // newDecryptingReader creates a new decrypting reader.
-func newDecryptingReader(ctx context.Context, source io.ReadCloser, key []byte, path string) (*decryptingReader, error) {
+// The key is looked up by the key version recorded in the file header.
+func newDecryptingReader(ctx context.Context, source io.ReadCloser, keyFor func(uint32) ([]byte, bool), path string) (*decryptingReader, error) {
+	reader := bufio.NewReader(source)
+
+	// Current files start with the magic prefix; version 1 files start
+	// directly with their version byte.
+	prefix, err := reader.Peek(len(encryptionMagic))
+	magic := bytes.Equal(prefix, encryptionMagic)
+	if !magic && len(prefix) == 0 {
+		return nil, headerReadError(err, path)
+	}
+	if magic {
+		_, _ = reader.Discard(len(encryptionMagic))
+	} else if prefix[0] != legacyFormatVersion {
+		return nil, WrapPath(ErrNotEncrypted, "decrypt", path, ErrCodeIntegrity, "file is not encrypted")
+	}
+
+	// Read format version.
+	header := make([]byte, headerSize-len(encryptionMagic))
+	if _, err := io.ReadFull(reader, header[:1]); err != nil {
+		return nil, headerReadError(err, path)
+	}
+
+	// Version 1 headers have no key version field; normalize them to the current layout.
+	version := header[0]
+	switch {
+	case magic && version == encryptionFormatVersion:
+		if _, err := io.ReadFull(reader, header[1:]); err != nil {
+			return nil, headerReadError(err, path)
+		}
+	case !magic && version == legacyFormatVersion:
+		binary.BigEndian.PutUint32(header[1:5], 1)
+		if _, err := io.ReadFull(reader, header[5:5+legacyHeaderSize-1]); err != nil {
+			return nil, headerReadError(err, path)
+		}
+	default:
+		return nil, WrapPath(fmt.Errorf("%w: got version %d, expected %d", ErrUnsupportedVersion, version, encryptionFormatVersion),
+			"decrypt", path, ErrCodeIntegrity, "unsupported encryption version")
+	}
+
+	keyVersion := binary.BigEndian.Uint32(header[1:5])
+	key, ok := keyFor(keyVersion)
+	if !ok {
+		return nil, WrapPath(fmt.Errorf("%w %d", ErrUnknownKeyVersion, keyVersion),
+			"decrypt", path, ErrCodeIntegrity, "unknown key version")
+	}
+
 	// Create cipher.
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -324,34 +516,27 @@ func newDecryptingReader(ctx context.Context, source io.ReadCloser, key []byte,
 			"decrypt", path, ErrCodeInternal, "GCM creation failed")
 	}
 
-	// Read header (17 bytes).
-	header := make([]byte, 17)
-	if _, err := io.ReadFull(source, header); err != nil {
-		if err == io.EOF || err == io.ErrUnexpectedEOF {
-			return nil, WrapPath(ErrTruncatedFile, "decrypt", path, ErrCodeIntegrity, "encrypted file is truncated")
-		}
-		return nil, WrapPath(err, "decrypt", path, ErrCodeInternal, "failed to read header")
-	}
-
-	// Parse header.
-	version := header[0]
-	if version != encryptionFormatVersion {
-		return nil, WrapPath(fmt.Errorf("%w: got version %d, expected %d", ErrUnsupportedVersion, version, encryptionFormatVersion),
-			"decrypt", path, ErrCodeIntegrity, "unsupported encryption version")
-	}
-
-	chunkSize := int(binary.BigEndian.Uint32(header[1:5]))
+	chunkSize := int(binary.BigEndian.Uint32(header[5:9]))
 	if chunkSize < 1024 || chunkSize > 16*1024*1024 {
 		return nil, WrapPath(ErrInvalidChunkSize, "decrypt", path, ErrCodeIntegrity, "invalid chunk size")
 	}
 
 	baseNonce := make([]byte, gcm.NonceSize())
-	copy(baseNonce, header[5:17])
+	copy(baseNonce, header[9:21])
+
+	var aad []byte
+	if version == encryptionFormatVersion {
+		aad = append(pathAAD(path), 0) // Final-chunk flag, set per chunk.
+	}
 
 	return &decryptingReader{
 		ctx:        ctx,
 		source:     source,
+		reader:     reader,
 		gcm:        gcm,
+		format:     version,
+		keyVersion: keyVersion,
+		aad:        aad,
 		baseNonce:  baseNonce,
 		chunkSize:  chunkSize,
 		chunkSeq:   0,
@@ -362,6 +547,14 @@ func newDecryptingReader(ctx context.Context, source io.ReadCloser, key []byte,
 	}, nil
 }
 
+// headerReadError maps a failure to read the file header.
+func headerReadError(err error, path string) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return WrapPath(ErrTruncatedFile, "decrypt", path, ErrCodeIntegrity, "encrypted file is truncated")
+	}
+	return WrapPath(err, "decrypt", path, ErrCodeInternal, "failed to read header")
+}
+
 // Read implements io.Reader.
 func (d *decryptingReader) Read(p []byte) (int, error) {
 	if d.closed {
@@ -375,17 +568,17 @@ func (d *decryptingReader) Read(p []byte) (int, error) {
 	default:
 	}
 
-	// Return buffered data first.
-	if d.decrypted.Len() > 0 {
-		return d.decrypted.Read(p)
-	}
-
-	// Read next chunk.
-	if err := d.decryptNextChunk(); err != nil {
-		if errors.Is(err, io.EOF) {
+	// Return buffered data first; an empty final chunk yields no data.
+	for d.decrypted.Len() == 0 {
+		if d.done {
 			return 0, io.EOF
 		}
-		return 0, err
+		if err := d.decryptNextChunk(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
 	}
 
 	return d.decrypted.Read(p)
@@ -395,9 +588,13 @@ func (d *decryptingReader) Read(p []byte) (int, error) {
 func (d *decryptingReader) decryptNextChunk() error {
 	// Read chunk header (8 bytes: 4 length + 4 sequence).
 	chunkHeader := make([]byte, 8)
-	_, err := io.ReadFull(d.source, chunkHeader)
+	_, err := io.ReadFull(d.reader, chunkHeader)
 	if err != nil {
 		if errors.Is(err, io.EOF) {
+			if d.format == encryptionFormatVersion {
+				// The stream ended before a chunk marked final.
+				return WrapPath(ErrTruncatedFile, "decrypt", d.path, ErrCodeIntegrity, "final chunk missing")
+			}
 			return io.EOF
 		}
 		if errors.Is(err, io.ErrUnexpectedEOF) {
@@ -424,19 +621,33 @@ func (d *decryptingReader) decryptNextChunk() error {
 
 	// Read encrypted chunk.
 	ciphertext := d.cipherBuf[:chunkLen]
-	if _, err := io.ReadFull(d.source, ciphertext); err != nil {
+	if _, err := io.ReadFull(d.reader, ciphertext); err != nil {
 		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			return WrapPath(ErrTruncatedFile, "decrypt", d.path, ErrCodeIntegrity, "chunk data truncated")
 		}
 		return WrapPath(err, "decrypt", d.path, ErrCodeInternal, "failed to read chunk data")
 	}
 
+	// The chunk is final if nothing follows it; authentication fails if
+	// trailing chunks were dropped, since the remaining last chunk was
+	// sealed as non-final.
+	final := false
+	if d.format == encryptionFormatVersion {
+		if _, err := d.reader.Peek(1); err != nil {
+			if err != io.EOF {
+				return WrapPath(err, "decrypt", d.path, ErrCodeInternal, "failed to read chunk data")
+			}
+			final = true
+		}
+		d.aad[len(d.aad)-1] = finalFlag(final)
+	}
+
 	// Derive per-chunk nonce.
 	copy(d.chunkNonce, d.baseNonce)
 	binary.BigEndian.PutUint32(d.chunkNonce[len(d.chunkNonce)-4:], chunkSeq)
 
 	// Decrypt chunk.
-	plaintext, err := d.gcm.Open(nil, d.chunkNonce, ciphertext, nil)
+	plaintext, err := d.gcm.Open(nil, d.chunkNonce, ciphertext, d.aad)
 	if err != nil {
 		return WrapPath(ErrDecryptionFailed, "decrypt", d.path, ErrCodeIntegrity, "decryption failed")
 	}
@@ -446,6 +657,7 @@ func (d *decryptingReader) decryptNextChunk() error {
 	d.decrypted.Write(plaintext)
 
 	d.chunkSeq++
+	d.done = final
 
 	return nil
 }
@@ -506,15 +718,137 @@ func (e *EncryptedFS) DeleteDir(ctx context.Context, path string) error {
 	return e.fs.DeleteDir(ctx, path)
 }
 
-// UploadFile encrypts and uploads a local file.
-func (e *EncryptedFS) UploadFile(ctx context.Context, path, localPath string, options ...Option) error {
+// WriteFile encrypts and uploads a local file.
+func (e *EncryptedFS) WriteFile(ctx context.Context, path, localPath string, options ...Option) (*WriteResult, error) {
 	file, err := os.Open(localPath)
 	if err != nil {
-		return WrapPathErr("uploadfile", localPath, err)
+		return nil, WrapPathErr("writefile", localPath, err)
 	}
 	defer file.Close()
 
-	_, err = e.Write(ctx, path, file, options...)
+	return e.Write(ctx, path, file, options...)
+}
+
+// UploadFile encrypts and uploads a local file.
+//
+// Deprecated: Use WriteFile, which also returns the WriteResult.
+func (e *EncryptedFS) UploadFile(ctx context.Context, path, localPath string, options ...Option) error {
+	_, err := e.WriteFile(ctx, path, localPath, options...)
+	return err
+}
+
+// Rotate re-encrypts the files matching paths with newKey, then makes newKey
+// the active key for new writes.
+//
+// newKey is registered under the next key version before any file is
+// touched, so reads keep working while the rotation is in progress: every
+// file header records the key version that encrypted it. Files that are
+// already encrypted with newKey in the current format are skipped, so a
+// failed rotation can simply be retried. If any file fails, the active key is left unchanged and the
+// errors are returned. A nil selector matches all files.
+//
+// Keys of files not matched by paths remain registered. When creating an
+// EncryptedFS in a later process, pass them with WithPreviousKey.
+//
+// Example:
+//
+//	err := encFS.Rotate(ctx, newKey, filekit.Glob("**/*.pdf"))
+//	version := encFS.KeyVersion() // persist alongside newKey
+func (e *EncryptedFS) Rotate(ctx context.Context, newKey []byte, paths FileSelector) error {
+	if len(newKey) != 32 {
+		return ErrInvalidKey
+	}
+
+	version := e.registerKey(newKey)
+
+	files, err := ListWithSelector(ctx, e.fs, "", paths, true)
+	if err != nil {
+		return WrapPathErr("rotate", "", err)
+	}
+
+	errs := NewMultiError("rotate")
+	for i := range files {
+		if err := FromContext(ctx, "rotate", files[i].Path); err != nil {
+			return err
+		}
+		errs.Add(e.reencrypt(ctx, &files[i], version, newKey))
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.active = version
+	e.mu.Unlock()
+	return nil
+}
+
+// registerKey adds key to the key ring and returns its version.
+// A key that is already registered keeps its version.
+func (e *EncryptedFS) registerKey(key []byte) uint32 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var latest uint32
+	for version, k := range e.keys {
+		if bytes.Equal(k, key) {
+			return version
+		}
+		latest = max(latest, version)
+	}
+	e.keys[latest+1] = append([]byte(nil), key...)
+	return latest + 1
+}
+
+// reencrypt decrypts a file and writes it back encrypted with key.
+// The new ciphertext is spooled to a temporary file first, because the
+// underlying filesystem may not support overwriting a file while it is read.
+func (e *EncryptedFS) reencrypt(ctx context.Context, file *FileInfo, version uint32, key []byte) error {
+	source, err := e.fs.Read(ctx, file.Path)
+	if err != nil {
+		return err
+	}
+	plaintext, err := newDecryptingReader(ctx, source, e.keyFor, file.Path)
+	if err != nil {
+		source.Close()
+		return err
+	}
+	defer plaintext.Close()
+
+	if plaintext.keyVersion == version && plaintext.format == encryptionFormatVersion {
+		return nil
+	}
+
+	gcm, baseNonce, err := newEncryptionCipher(key, file.Path)
+	if err != nil {
+		return err
+	}
+
+	spool, err := os.CreateTemp("", "filekit-rotate-*")
+	if err != nil {
+		return WrapPathErr("rotate", file.Path, err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	if err := e.encryptStream(ctx, spool, plaintext, gcm, baseNonce, version, pathAAD(file.Path)); err != nil {
+		return WrapPathErr("rotate", file.Path, err)
+	}
+	plaintext.Close()
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return WrapPathErr("rotate", file.Path, err)
+	}
+
+	opts := []Option{WithOverwrite(true), withEncryptedContent()}
+	if file.ContentType != "" {
+		opts = append(opts, WithContentType(file.ContentType))
+	}
+	if len(file.Metadata) > 0 {
+		opts = append(opts, WithMetadata(file.Metadata))
+	}
+
+	_, err = e.fs.Write(ctx, file.Path, spool, opts...)
 	return err
 }
 
@@ -525,7 +859,8 @@ func (e *EncryptedFS) Underlying() FileSystem {
 
 // Verify interface compliance at compile time.
 var (
-	_ FileSystem = (*EncryptedFS)(nil)
-	_ FileReader = (*EncryptedFS)(nil)
-	_ FileWriter = (*EncryptedFS)(nil)
+	_ FileSystem        = (*EncryptedFS)(nil)
+	_ FileReader        = (*EncryptedFS)(nil)
+	_ FileWriter        = (*EncryptedFS)(nil)
+	_ CanWriteLocalFile = (*EncryptedFS)(nil)
 )
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"io"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -67,7 +69,12 @@ func (m *encryptionTestFS) Stat(ctx context.Context, path string) (*FileInfo, er
 }
 
 func (m *encryptionTestFS) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
-	return nil, nil
+	var files []FileInfo
+	for p, data := range m.files {
+		files = append(files, FileInfo{Name: p, Path: p, Size: int64(len(data))})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
 }
 
 func (m *encryptionTestFS) CreateDir(ctx context.Context, path string) error {
@@ -340,7 +347,7 @@ func TestEncryptionCorruptedData(t *testing.T) {
 		// Corrupt the version byte
 		data := make([]byte, len(fs.files["test.dat"]))
 		copy(data, fs.files["test.dat"])
-		data[0] = 99 // Invalid version
+		data[len(encryptionMagic)] = 99 // Invalid version
 		fs.files["corrupted.dat"] = data
 
 		_, err := encFS.ReadAll(ctx, "corrupted.dat")
@@ -602,3 +609,379 @@ func BenchmarkEncryptionRoundtrip(b *testing.B) {
 		_, _ = encFS.ReadAll(ctx, "bench.dat")
 	}
 }
+
+// rotationHookFS calls onOverwrite before each overwriting write.
+type rotationHookFS struct {
+	*encryptionTestFS
+	onOverwrite func(path string)
+}
+
+func (h *rotationHookFS) Write(ctx context.Context, path string, r io.Reader, opts ...Option) (*WriteResult, error) {
+	if _, exists := h.files[path]; exists && h.onOverwrite != nil {
+		h.onOverwrite(path)
+	}
+	return h.encryptionTestFS.Write(ctx, path, r, opts...)
+}
+
+func TestEncryptionRotate(t *testing.T) {
+	ctx := context.Background()
+	base := newEncryptionTestFS()
+	fs := &rotationHookFS{encryptionTestFS: base}
+	oldKey := generateKey(t)
+	newKey := generateKey(t)
+
+	encFS, err := NewEncryptedFS(fs, oldKey)
+	if err != nil {
+		t.Fatalf("NewEncryptedFS failed: %v", err)
+	}
+
+	contents := map[string]string{
+		"a.txt": "alpha",
+		"b.txt": strings.Repeat("bravo", 1000),
+		"c.txt": "charlie",
+		"d.txt": "",
+	}
+	for p, c := range contents {
+		if _, err := encFS.Write(ctx, p, strings.NewReader(c)); err != nil {
+			t.Fatalf("failed to write %s: %v", p, err)
+		}
+	}
+
+	// Mid-rotation, files encrypted with either key must read back correctly.
+	midRotationChecks := 0
+	fs.onOverwrite = func(string) {
+		midRotationChecks++
+		for p, want := range contents {
+			got, err := encFS.ReadAll(ctx, p)
+			if err != nil {
+				t.Errorf("mid-rotation read of %s failed: %v", p, err)
+				continue
+			}
+			if string(got) != want {
+				t.Errorf("mid-rotation read of %s returned wrong content", p)
+			}
+		}
+	}
+
+	if err := encFS.Rotate(ctx, newKey, nil); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if midRotationChecks != len(contents) {
+		t.Errorf("expected %d re-encrypted files, got %d", len(contents), midRotationChecks)
+	}
+	if encFS.KeyVersion() != 2 {
+		t.Errorf("KeyVersion = %d, want 2", encFS.KeyVersion())
+	}
+
+	// All files are now readable with the new key alone.
+	newOnly, err := NewEncryptedFS(base, newKey, WithKeyVersion(2))
+	if err != nil {
+		t.Fatalf("NewEncryptedFS failed: %v", err)
+	}
+	for p, want := range contents {
+		got, err := newOnly.ReadAll(ctx, p)
+		if err != nil {
+			t.Fatalf("read of %s with new key failed: %v", p, err)
+		}
+		if string(got) != want {
+			t.Errorf("read of %s with new key returned wrong content", p)
+		}
+	}
+
+	// New writes use the new key.
+	fs.onOverwrite = nil
+	if _, err := encFS.Write(ctx, "e.txt", strings.NewReader("echo")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if got, err := newOnly.ReadAll(ctx, "e.txt"); err != nil || string(got) != "echo" {
+		t.Errorf("read of new file with new key = %q, %v", got, err)
+	}
+
+	// Rotating to the same key again is a no-op.
+	before := string(base.files["a.txt"])
+	if err := encFS.Rotate(ctx, newKey, nil); err != nil {
+		t.Fatalf("repeated Rotate failed: %v", err)
+	}
+	if string(base.files["a.txt"]) != before || encFS.KeyVersion() != 2 {
+		t.Error("repeated rotation should not re-encrypt files")
+	}
+}
+
+func TestEncryptionRotate_Selector(t *testing.T) {
+	ctx := context.Background()
+	fs := newEncryptionTestFS()
+	oldKey := generateKey(t)
+	newKey := generateKey(t)
+
+	encFS, _ := NewEncryptedFS(fs, oldKey)
+	for _, p := range []string{"keep.log", "rotate.txt"} {
+		if _, err := encFS.Write(ctx, p, strings.NewReader(p)); err != nil {
+			t.Fatalf("failed to write %s: %v", p, err)
+		}
+	}
+
+	if err := encFS.Rotate(ctx, newKey, Glob("*.txt")); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	// Both versions remain readable through the rotated filesystem.
+	for _, p := range []string{"keep.log", "rotate.txt"} {
+		got, err := encFS.ReadAll(ctx, p)
+		if err != nil || string(got) != p {
+			t.Errorf("ReadAll(%s) = %q, %v", p, got, err)
+		}
+	}
+
+	// A new process needs the old key to read the unrotated file.
+	newOnly, _ := NewEncryptedFS(fs, newKey, WithKeyVersion(2))
+	if _, err := newOnly.ReadAll(ctx, "keep.log"); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Errorf("expected ErrUnknownKeyVersion, got %v", err)
+	}
+	withPrevious, err := NewEncryptedFS(fs, newKey, WithKeyVersion(2), WithPreviousKey(1, oldKey))
+	if err != nil {
+		t.Fatalf("NewEncryptedFS failed: %v", err)
+	}
+	if got, err := withPrevious.ReadAll(ctx, "keep.log"); err != nil || string(got) != "keep.log" {
+		t.Errorf("ReadAll with previous key = %q, %v", got, err)
+	}
+
+	if _, err := NewEncryptedFS(fs, newKey, WithPreviousKey(7, []byte("short"))); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey for short previous key, got %v", err)
+	}
+}
+
+// encryptLegacy encrypts content in the version 1 format for compatibility
+// tests. All content goes into a single chunk, sealed without AAD.
+func encryptLegacy(t *testing.T, key []byte, path, content string) []byte {
+	t.Helper()
+	gcm, baseNonce, err := newEncryptionCipher(key, path)
+	if err != nil {
+		t.Fatalf("cipher creation failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(legacyFormatVersion)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(defaultChunkSize))
+	buf.Write(baseNonce)
+
+	nonce := append([]byte(nil), baseNonce...)
+	binary.BigEndian.PutUint32(nonce[len(nonce)-4:], 0)
+	sealed := gcm.Seal(nil, nonce, []byte(content), nil)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(sealed))) //nolint:gosec // test data is small
+	_ = binary.Write(&buf, binary.BigEndian, uint32(0))
+	buf.Write(sealed)
+	return buf.Bytes()
+}
+
+func TestEncryptionReadsLegacyFormats(t *testing.T) {
+	ctx := context.Background()
+	fs := newEncryptionTestFS()
+	key := generateKey(t)
+	encFS, _ := NewEncryptedFS(fs, key)
+
+	want := map[string]string{
+		"v1.dat":    "version one",
+		"other.dat": "another version one file",
+	}
+	for p, content := range want {
+		fs.files[p] = encryptLegacy(t, key, p, content)
+	}
+
+	for p := range want {
+		got, err := encFS.ReadAll(ctx, p)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", p, err)
+		}
+		if string(got) != want[p] {
+			t.Errorf("%s: got %q, want %q", p, got, want[p])
+		}
+	}
+
+	// Rotation upgrades them to the current format.
+	if err := encFS.Rotate(ctx, generateKey(t), nil); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	for p := range want {
+		if !bytes.HasPrefix(fs.files[p], encryptionMagic) || fs.files[p][len(encryptionMagic)] != encryptionFormatVersion {
+			t.Errorf("%s: header = %q, want magic and format %d", p, fs.files[p][:len(encryptionMagic)+1], encryptionFormatVersion)
+		}
+	}
+}
+
+func TestEncryptionBindsPath(t *testing.T) {
+	ctx := context.Background()
+	fs := newEncryptionTestFS()
+	key := generateKey(t)
+	encFS, _ := NewEncryptedFS(fs, key)
+
+	if _, err := encFS.Write(ctx, "users/alice/secret.txt", strings.NewReader("alice's data")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	// Copy the raw ciphertext to another path on the underlying filesystem.
+	fs.files["users/bob/secret.txt"] = append([]byte(nil), fs.files["users/alice/secret.txt"]...)
+
+	_, err := encFS.ReadAll(ctx, "users/bob/secret.txt")
+	if !errors.Is(err, ErrDecryptionFailed) {
+		t.Fatalf("expected ErrDecryptionFailed for moved ciphertext, got %v", err)
+	}
+	if !IsCode(err, ErrCodeIntegrity) {
+		t.Errorf("expected ErrCodeIntegrity, got %v", err)
+	}
+
+	// Equivalent spellings of the original path still decrypt.
+	fs.files["/users/alice/secret.txt"] = fs.files["users/alice/secret.txt"]
+	got, err := encFS.ReadAll(ctx, "/users/alice/secret.txt")
+	if err != nil || string(got) != "alice's data" {
+		t.Errorf("ReadAll(/users/alice/secret.txt) = %q, %v", got, err)
+	}
+}
+
+func TestEncryptionStreamsChunks(t *testing.T) {
+	ctx := context.Background()
+	fs := newEncryptionTestFS()
+	key := generateKey(t)
+	encFS, _ := NewEncryptedFS(fs, key, WithChunkSize(1024))
+
+	// 4.5 chunks of content
+	content := bytes.Repeat([]byte("0123456789abcdef"), 288)
+	if _, err := encFS.Write(ctx, "large.bin", bytes.NewReader(content)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	const chunkLen = 8 + 1024 + 16 // chunk header + plaintext + GCM tag
+	raw := fs.files["large.bin"]
+	if want := headerSize + 4*chunkLen + 8 + 512 + 16; len(raw) != want {
+		t.Fatalf("encrypted size = %d, want %d (5 chunks)", len(raw), want)
+	}
+
+	got, err := encFS.ReadAll(ctx, "large.bin")
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatal("round-tripped content mismatch")
+	}
+
+	// Tampered copies are stored at the original path, since ciphertext is bound to it.
+	t.Run("flipped byte fails affected chunk", func(t *testing.T) {
+		tampered := append([]byte(nil), raw...)
+		tampered[headerSize+2*chunkLen+8+100] ^= 0x01 // inside the third chunk
+		fs.files["large.bin"] = tampered
+
+		reader, err := encFS.Read(ctx, "large.bin")
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+		defer reader.Close()
+
+		got, err := io.ReadAll(reader)
+		if !errors.Is(err, ErrDecryptionFailed) {
+			t.Fatalf("expected ErrDecryptionFailed, got %v", err)
+		}
+		if !bytes.Equal(got, content[:2*1024]) {
+			t.Errorf("expected the two intact chunks before the error, got %d bytes", len(got))
+		}
+	})
+
+	t.Run("dropped final chunk is detected", func(t *testing.T) {
+		fs.files["large.bin"] = raw[:headerSize+4*chunkLen]
+
+		_, err := encFS.ReadAll(ctx, "large.bin")
+		if !IsCode(err, ErrCodeIntegrity) {
+			t.Fatalf("expected integrity error, got %v", err)
+		}
+	})
+
+	t.Run("all chunks dropped is detected", func(t *testing.T) {
+		fs.files["large.bin"] = raw[:headerSize]
+
+		_, err := encFS.ReadAll(ctx, "large.bin")
+		if !errors.Is(err, ErrTruncatedFile) {
+			t.Fatalf("expected ErrTruncatedFile, got %v", err)
+		}
+	})
+
+	t.Run("empty file round-trips", func(t *testing.T) {
+		if _, err := encFS.Write(ctx, "empty.bin", bytes.NewReader(nil)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+		got, err := encFS.ReadAll(ctx, "empty.bin")
+		if err != nil || len(got) != 0 {
+			t.Errorf("ReadAll(empty.bin) = %q, %v", got, err)
+		}
+	})
+}
+
+func TestEncryptionMagicHeader(t *testing.T) {
+	ctx := context.Background()
+	fs := newEncryptionTestFS()
+	encFS, _ := NewEncryptedFS(fs, generateKey(t))
+
+	if _, err := encFS.Write(ctx, "doc.txt", strings.NewReader("secret")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	raw := fs.files["doc.txt"]
+	if !bytes.HasPrefix(raw, encryptionMagic) {
+		t.Fatalf("encrypted file starts with %q, want %q", raw[:len(encryptionMagic)], encryptionMagic)
+	}
+	if got, err := encFS.ReadAll(ctx, "doc.txt"); err != nil || string(got) != "secret" {
+		t.Errorf("ReadAll = %q, %v; want %q", got, err, "secret")
+	}
+
+	t.Run("plaintext read fails clearly", func(t *testing.T) {
+		fs.files["plain.txt"] = []byte("just some text")
+		_, err := encFS.ReadAll(ctx, "plain.txt")
+		if !errors.Is(err, ErrNotEncrypted) || !IsCode(err, ErrCodeIntegrity) {
+			t.Errorf("ReadAll(plaintext) = %v, want ErrNotEncrypted", err)
+		}
+	})
+
+	t.Run("only version 1 reads without magic", func(t *testing.T) {
+		stripped := append([]byte(nil), raw[len(encryptionMagic):]...)
+		fs.files["doc.txt"] = stripped
+		defer func() { fs.files["doc.txt"] = raw }()
+
+		if _, err := encFS.ReadAll(ctx, "doc.txt"); !errors.Is(err, ErrNotEncrypted) {
+			t.Errorf("ReadAll(current format without magic) = %v, want ErrNotEncrypted", err)
+		}
+	})
+
+	t.Run("unknown version after magic is unsupported", func(t *testing.T) {
+		future := append([]byte(nil), raw...)
+		future[len(encryptionMagic)] = encryptionFormatVersion + 1
+		fs.files["doc.txt"] = future
+		defer func() { fs.files["doc.txt"] = raw }()
+
+		if _, err := encFS.ReadAll(ctx, "doc.txt"); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("ReadAll(version %d) = %v, want ErrUnsupportedVersion", encryptionFormatVersion+1, err)
+		}
+	})
+
+	t.Run("writing ciphertext is refused", func(t *testing.T) {
+		_, err := encFS.Write(ctx, "copy.txt", bytes.NewReader(raw))
+		if !errors.Is(err, ErrAlreadyEncrypted) || !IsCode(err, ErrCodeInvalidInput) {
+			t.Errorf("Write(ciphertext) = %v, want ErrAlreadyEncrypted", err)
+		}
+		if _, exists := fs.files["copy.txt"]; exists {
+			t.Error("refused write reached the underlying filesystem")
+		}
+	})
+
+	t.Run("stacked decorators round-trip", func(t *testing.T) {
+		outer, err := NewEncryptedFS(encFS, generateKey(t))
+		if err != nil {
+			t.Fatalf("NewEncryptedFS failed: %v", err)
+		}
+		if _, err := outer.Write(ctx, "twice.txt", strings.NewReader("double")); err != nil {
+			t.Fatalf("stacked write failed: %v", err)
+		}
+		if got, err := outer.ReadAll(ctx, "twice.txt"); err != nil || string(got) != "double" {
+			t.Errorf("stacked ReadAll = %q, %v; want %q", got, err, "double")
+		}
+		// The inner layer decrypts to the outer layer's ciphertext
+		if inner, err := encFS.ReadAll(ctx, "twice.txt"); err != nil || !bytes.HasPrefix(inner, encryptionMagic) {
+			t.Errorf("inner ReadAll = %q, %v; want outer ciphertext", inner, err)
+		}
+	})
+}
@@ -12,7 +12,7 @@ import (
 )
 
 // ============================================================================
-// ERROR CODES (19 total - Stable API, NEVER change values, only add)
+// ERROR CODES (21 total - Stable API, NEVER change values, only add)
 // ============================================================================
 
 // ErrorCode is a stable identifier for error types.
@@ -25,11 +25,22 @@ const (
 	ErrCodeAlreadyExists ErrorCode = "FILEKIT_ALREADY_EXISTS"
 	ErrCodeTypeMismatch  ErrorCode = "FILEKIT_TYPE_MISMATCH"
 
+	// Conditional writes (WithIfMatch / WithIfNoneMatch)
+	ErrCodePreconditionFailed ErrorCode = "FILEKIT_PRECONDITION_FAILED"
+
 	// Access
 	ErrCodePermission ErrorCode = "FILEKIT_PERMISSION"
 	ErrCodeAuth       ErrorCode = "FILEKIT_AUTH"
 	ErrCodeQuota      ErrorCode = "FILEKIT_QUOTA"
 
+	// ErrCodeQuotaExceeded is ErrCodeQuota: a storage limit, cloud quota or
+	// disabled account refused the operation.
+	ErrCodeQuotaExceeded = ErrCodeQuota
+
+	// ErrCodeNotReadable: the file is in an archive storage class and must be
+	// restored before it can be read.
+	ErrCodeNotReadable ErrorCode = "FILEKIT_NOT_READABLE"
+
 	// Validation (use Details map for specifics)
 	ErrCodeInvalidInput ErrorCode = "FILEKIT_INVALID_INPUT"
 	ErrCodeValidation   ErrorCode = "FILEKIT_VALIDATION"
@@ -58,6 +69,19 @@ const (
 
 func (c ErrorCode) String() string { return string(c) }
 
+// ErrPreconditionFailed is returned (wrapped, match with errors.Is) when the
+// WithIfMatch or WithIfNoneMatch condition of a write does not hold.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrQuotaExceeded matches (with errors.Is) any error with ErrCodeQuotaExceeded:
+// the memory driver's MaxSize limit and the quota errors of the cloud drivers.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ErrNotReadable matches (with errors.Is) any error with ErrCodeNotReadable:
+// reads of S3 GLACIER/DEEP_ARCHIVE objects and Azure Archive-tier blobs that
+// have not been restored.
+var ErrNotReadable = errors.New("file is archived and not readable")
+
 // ============================================================================
 // ERROR CATEGORIES
 // ============================================================================
@@ -89,7 +113,7 @@ func codeToCategory(code ErrorCode) ErrorCategory {
 	switch code {
 	case ErrCodeNotFound:
 		return CategoryNotFound
-	case ErrCodeAlreadyExists:
+	case ErrCodeAlreadyExists, ErrCodePreconditionFailed:
 		return CategoryConflict
 	case ErrCodeTypeMismatch, ErrCodeInvalidInput, ErrCodeValidation:
 		return CategoryValidation
@@ -193,6 +217,12 @@ func (e *FileError) Is(target error) bool {
 		return target == fs.ErrClosed || target == os.ErrClosed
 	case ErrCodeInvalidInput:
 		return target == fs.ErrInvalid || target == os.ErrInvalid
+	case ErrCodePreconditionFailed:
+		return target == ErrPreconditionFailed
+	case ErrCodeQuota:
+		return target == ErrQuotaExceeded
+	case ErrCodeNotReadable:
+		return target == ErrNotReadable
 	}
 	return false
 }
@@ -210,12 +240,16 @@ func (e *FileError) HTTPStatus() int {
 		return http.StatusNotFound
 	case ErrCodeAlreadyExists:
 		return http.StatusConflict
+	case ErrCodePreconditionFailed:
+		return http.StatusPreconditionFailed
 	case ErrCodePermission:
 		return http.StatusForbidden
 	case ErrCodeAuth:
 		return http.StatusUnauthorized
 	case ErrCodeQuota:
 		return http.StatusInsufficientStorage
+	case ErrCodeNotReadable:
+		return http.StatusConflict
 	case ErrCodeInvalidInput, ErrCodeValidation, ErrCodeTypeMismatch:
 		return http.StatusBadRequest
 	case ErrCodeIntegrity:
@@ -320,8 +354,12 @@ func inferErrorCode(err error) ErrorCode {
 		return ErrCodePermission
 	case errors.Is(err, ErrInvalidOffset), errors.Is(err, ErrInvalidWhence), errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidSize):
 		return ErrCodeInvalidInput
-	case errors.Is(err, ErrNoSpace):
+	case errors.Is(err, ErrNotDir), errors.Is(err, ErrIsDir):
+		return ErrCodeTypeMismatch
+	case errors.Is(err, ErrNoSpace), errors.Is(err, ErrQuotaExceeded):
 		return ErrCodeQuota
+	case errors.Is(err, ErrPreconditionFailed):
+		return ErrCodePreconditionFailed
 	default:
 		return ErrCodeInternal
 	}
@@ -418,6 +456,10 @@ func ToFileError(err error) *FileError {
 		return Wrap(err, ErrCodeAborted, "canceled")
 	case errors.Is(err, context.DeadlineExceeded):
 		return Wrap(err, ErrCodeTimeout, "deadline exceeded")
+	case errors.Is(err, ErrPreconditionFailed):
+		return Wrap(err, ErrCodePreconditionFailed, "precondition failed")
+	case errors.Is(err, ErrNoSpace), errors.Is(err, ErrQuotaExceeded):
+		return Wrap(err, ErrCodeQuota, "quota exceeded")
 	}
 	return Wrap(err, ErrCodeInternal, err.Error())
 }
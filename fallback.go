@@ -0,0 +1,284 @@
+package filekit
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// ============================================================================
+// FallbackFileSystem Decorator
+// ============================================================================
+
+// FallbackFileSystem reads from a primary FileSystem and falls back to a
+// secondary one when the primary does not have the file, e.g. a fast local
+// cache in front of a slow origin.
+//
+// Read, ReadAll, Stat, FileExists and DirExists try the primary first and
+// consult the secondary only when the file is not found. With
+// WithPopulateOnMiss, files read from the secondary are copied into the
+// primary so later reads are served by it. ListContents merges both listings.
+//
+// Modifications go to the primary only, unless WithWriteToSecondary is set.
+// Note that deleting a file from the primary alone makes the secondary's copy
+// visible again.
+//
+// Example:
+//
+//	fs := filekit.NewFallbackFileSystem(localCache, s3Origin, filekit.WithPopulateOnMiss())
+//	data, _ := fs.ReadAll(ctx, "assets/logo.png") // fetched from S3 once, then from disk
+type FallbackFileSystem struct {
+	primary   FileSystem
+	secondary FileSystem
+	opts      FallbackOptions
+
+	// writer receives modifications: the primary, or a strict TeeFileSystem
+	// over both backends
+	writer FileSystem
+}
+
+// FallbackOptions configures the FallbackFileSystem behavior.
+type FallbackOptions struct {
+	// PopulateOnMiss copies files read from the secondary into the primary.
+	// Default: false
+	PopulateOnMiss bool
+
+	// WriteToSecondary applies modifications to the secondary as well, after
+	// the primary. A secondary failure fails the operation.
+	// Default: false
+	WriteToSecondary bool
+}
+
+// FallbackOption is a functional option for configuring FallbackFileSystem.
+type FallbackOption func(*FallbackOptions)
+
+// WithPopulateOnMiss copies files read from the secondary into the primary,
+// so later reads are served by the primary.
+func WithPopulateOnMiss() FallbackOption {
+	return func(o *FallbackOptions) {
+		o.PopulateOnMiss = true
+	}
+}
+
+// WithWriteToSecondary applies Write, Delete, CreateDir, DeleteDir, Copy and
+// Move to the secondary as well as the primary.
+func WithWriteToSecondary() FallbackOption {
+	return func(o *FallbackOptions) {
+		o.WriteToSecondary = true
+	}
+}
+
+// NewFallbackFileSystem creates a wrapper that reads from primary and falls
+// back to secondary for files the primary does not have.
+func NewFallbackFileSystem(primary, secondary FileSystem, opts ...FallbackOption) *FallbackFileSystem {
+	options := FallbackOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	f := &FallbackFileSystem{
+		primary:   primary,
+		secondary: secondary,
+		opts:      options,
+		writer:    primary,
+	}
+	if options.WriteToSecondary {
+		f.writer = NewTeeFileSystem(primary, []FileSystem{secondary}, WithStrictTee())
+	}
+	return f
+}
+
+// Unwrap returns the primary FileSystem.
+func (f *FallbackFileSystem) Unwrap() FileSystem {
+	return f.primary
+}
+
+// Secondary returns the secondary FileSystem.
+func (f *FallbackFileSystem) Secondary() FileSystem {
+	return f.secondary
+}
+
+// populate copies content read from the secondary into the primary, keeping
+// the secondary's content type and metadata. A failed copy is deleted from the
+// primary so a partial file is never served as a hit; the file is still served
+// from the secondary.
+func (f *FallbackFileSystem) populate(ctx context.Context, path string, content io.Reader) bool {
+	opts := []Option{WithOverwrite(true)}
+	if info, err := f.secondary.Stat(ctx, path); err == nil {
+		if info.ContentType != "" {
+			opts = append(opts, WithContentType(info.ContentType))
+		}
+		if len(info.Metadata) > 0 {
+			opts = append(opts, WithMetadata(info.Metadata))
+		}
+	}
+	if _, err := f.primary.Write(ctx, path, content, opts...); err != nil {
+		_ = f.primary.Delete(ctx, path)
+		return false
+	}
+	return true
+}
+
+// ============================================================================
+// FileSystem Interface - Read Operations (With Fallback)
+// ============================================================================
+
+// Read reads from the primary, or from the secondary if the primary does not
+// have the file. With PopulateOnMiss the file is copied into the primary
+// first and then read from it.
+func (f *FallbackFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	rc, err := f.primary.Read(ctx, path)
+	if !IsNotFound(err) {
+		return rc, err
+	}
+
+	rc, err = f.secondary.Read(ctx, path)
+	if err != nil || !f.opts.PopulateOnMiss {
+		return rc, err
+	}
+	populated := f.populate(ctx, path, rc)
+	rc.Close()
+	if populated {
+		if rc, err := f.primary.Read(ctx, path); err == nil {
+			return rc, nil
+		}
+	}
+	return f.secondary.Read(ctx, path)
+}
+
+// ReadAll reads from the primary, or from the secondary if the primary does
+// not have the file. With PopulateOnMiss the content is also written to the
+// primary.
+func (f *FallbackFileSystem) ReadAll(ctx context.Context, path string) ([]byte, error) {
+	data, err := f.primary.ReadAll(ctx, path)
+	if !IsNotFound(err) {
+		return data, err
+	}
+
+	data, err = f.secondary.ReadAll(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if f.opts.PopulateOnMiss {
+		f.populate(ctx, path, bytes.NewReader(data))
+	}
+	return data, nil
+}
+
+// FileExists reports whether the file exists on the primary or the secondary.
+func (f *FallbackFileSystem) FileExists(ctx context.Context, path string) (bool, error) {
+	exists, err := f.primary.FileExists(ctx, path)
+	if err != nil || exists {
+		return exists, err
+	}
+	return f.secondary.FileExists(ctx, path)
+}
+
+// DirExists reports whether the directory exists on the primary or the
+// secondary.
+func (f *FallbackFileSystem) DirExists(ctx context.Context, path string) (bool, error) {
+	exists, err := f.primary.DirExists(ctx, path)
+	if err != nil || exists {
+		return exists, err
+	}
+	return f.secondary.DirExists(ctx, path)
+}
+
+// Stat returns file information from the primary, or from the secondary if
+// the primary does not have the file.
+func (f *FallbackFileSystem) Stat(ctx context.Context, path string) (*FileInfo, error) {
+	info, err := f.primary.Stat(ctx, path)
+	if !IsNotFound(err) {
+		return info, err
+	}
+	return f.secondary.Stat(ctx, path)
+}
+
+// ListContents merges the listings of both filesystems, sorted by name.
+// Entries present on both are reported once, from the primary. The directory
+// only has to exist on one of them.
+func (f *FallbackFileSystem) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
+	primary, err := f.primary.ListContents(ctx, path, recursive)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	primaryMissing := err != nil
+
+	secondary, err := f.secondary.ListContents(ctx, path, recursive)
+	if err != nil {
+		if IsNotFound(err) && !primaryMissing {
+			return primary, nil
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(primary))
+	for _, entry := range primary {
+		seen[entry.Path] = true
+	}
+	for _, entry := range secondary {
+		if !seen[entry.Path] {
+			primary = append(primary, entry)
+		}
+	}
+	SortFiles(primary, SortByName, false)
+	return primary, nil
+}
+
+// ============================================================================
+// FileSystem Interface - Write Operations
+// ============================================================================
+
+// Write writes to the primary (and the secondary with WriteToSecondary).
+func (f *FallbackFileSystem) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
+	return f.writer.Write(ctx, path, content, options...)
+}
+
+// Delete deletes from the primary (and the secondary with WriteToSecondary).
+func (f *FallbackFileSystem) Delete(ctx context.Context, path string) error {
+	return f.writer.Delete(ctx, path)
+}
+
+// CreateDir creates the directory on the primary (and the secondary with
+// WriteToSecondary).
+func (f *FallbackFileSystem) CreateDir(ctx context.Context, path string) error {
+	return f.writer.CreateDir(ctx, path)
+}
+
+// DeleteDir deletes the directory from the primary (and the secondary with
+// WriteToSecondary).
+func (f *FallbackFileSystem) DeleteDir(ctx context.Context, path string) error {
+	return f.writer.DeleteDir(ctx, path)
+}
+
+// ============================================================================
+// Optional Interface Delegation
+// ============================================================================
+
+// Copy copies on the primary (and the secondary with WriteToSecondary) if
+// the primary supports CanCopy.
+func (f *FallbackFileSystem) Copy(ctx context.Context, src, dst string) error {
+	if _, ok := f.primary.(CanCopy); !ok {
+		return NewPathError("copy", src, ErrCodeNotSupported, "underlying filesystem does not support copy")
+	}
+	return f.writer.(CanCopy).Copy(ctx, src, dst)
+}
+
+// Move moves on the primary (and the secondary with WriteToSecondary) if the
+// primary supports CanMove.
+func (f *FallbackFileSystem) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
+	if _, ok := f.primary.(CanMove); !ok {
+		return NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
+	}
+	return f.writer.(CanMove).Move(ctx, src, dst, opts...)
+}
+
+// ============================================================================
+// Interface Assertions
+// ============================================================================
+
+var (
+	_ FileSystem = (*FallbackFileSystem)(nil)
+	_ CanCopy    = (*FallbackFileSystem)(nil)
+	_ CanMove    = (*FallbackFileSystem)(nil)
+)
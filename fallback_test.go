@@ -0,0 +1,214 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestFallbackFileSystem_ReadsFromSecondary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	seedTree(t, secondary, map[string]string{"origin.txt": "from origin", "docs/b.txt": "b"})
+	seedTree(t, primary, map[string]string{"cached.txt": "from cache", "docs/a.txt": "a"})
+	fs := filekit.NewFallbackFileSystem(primary, secondary)
+
+	rc, err := fs.Read(ctx, "origin.txt")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "from origin" {
+		t.Errorf("Read = %q, want the secondary's content", data)
+	}
+	if data, err := fs.ReadAll(ctx, "cached.txt"); err != nil || string(data) != "from cache" {
+		t.Errorf("ReadAll(cached.txt) = %q, %v", data, err)
+	}
+	if info, err := fs.Stat(ctx, "origin.txt"); err != nil || info.Size != int64(len("from origin")) {
+		t.Errorf("Stat(origin.txt) = %+v, %v", info, err)
+	}
+	if exists, err := fs.FileExists(ctx, "origin.txt"); err != nil || !exists {
+		t.Errorf("FileExists(origin.txt) = %v, %v; want true", exists, err)
+	}
+	if _, err := fs.ReadAll(ctx, "missing.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("ReadAll(missing.txt) = %v, want not found", err)
+	}
+
+	// Without populate-on-miss the primary is left alone
+	if exists, _ := primary.FileExists(ctx, "origin.txt"); exists {
+		t.Error("primary populated without WithPopulateOnMiss")
+	}
+
+	entries, err := fs.ListContents(ctx, "docs", false)
+	if err != nil {
+		t.Fatalf("ListContents: %v", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	slices.Sort(paths)
+	if !slices.Equal(paths, []string{"docs/a.txt", "docs/b.txt"}) {
+		t.Errorf("ListContents = %v, want both backends merged", paths)
+	}
+
+	// Writes go to the primary only
+	if _, err := fs.Write(ctx, "new.txt", strings.NewReader("new")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if exists, _ := secondary.FileExists(ctx, "new.txt"); exists {
+		t.Error("write reached the secondary without WithWriteToSecondary")
+	}
+}
+
+func TestFallbackFileSystem_ListContentsSorted(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	seedTree(t, primary, map[string]string{"b.txt": "", "d.txt": ""})
+	seedTree(t, secondary, map[string]string{"a.txt": "", "c.txt": "", "d.txt": ""})
+	fs := filekit.NewFallbackFileSystem(primary, secondary)
+
+	entries, err := fs.ListContents(ctx, "", false)
+	if err != nil {
+		t.Fatalf("ListContents: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if want := []string{"a.txt", "b.txt", "c.txt", "d.txt"}; !slices.Equal(names, want) {
+		t.Errorf("ListContents = %v, want %v", names, want)
+	}
+}
+
+func TestFallbackFileSystem_PopulateOnMiss(t *testing.T) {
+	ctx := context.Background()
+
+	for _, read := range []struct {
+		name string
+		fn   func(fs filekit.FileSystem) (string, error)
+	}{
+		{"Read", func(fs filekit.FileSystem) (string, error) {
+			rc, err := fs.Read(ctx, "origin.txt")
+			if err != nil {
+				return "", err
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			return string(data), err
+		}},
+		{"ReadAll", func(fs filekit.FileSystem) (string, error) {
+			data, err := fs.ReadAll(ctx, "origin.txt")
+			return string(data), err
+		}},
+	} {
+		t.Run(read.name, func(t *testing.T) {
+			primary, secondary := memory.New(), memory.New()
+			seedTree(t, secondary, map[string]string{"origin.txt": "from origin"})
+			fs := filekit.NewFallbackFileSystem(primary, secondary, filekit.WithPopulateOnMiss())
+
+			if data, err := read.fn(fs); err != nil || data != "from origin" {
+				t.Fatalf("first read = %q, %v", data, err)
+			}
+			if data, err := primary.ReadAll(ctx, "origin.txt"); err != nil || string(data) != "from origin" {
+				t.Fatalf("primary after miss = %q, %v; want populated", data, err)
+			}
+
+			// The second read is served from the primary
+			if err := secondary.Delete(ctx, "origin.txt"); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := read.fn(fs); err != nil || data != "from origin" {
+				t.Errorf("second read = %q, %v; want it served from the primary", data, err)
+			}
+		})
+	}
+}
+
+func TestFallbackFileSystem_PopulateKeepsAttributes(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	_, err := secondary.Write(ctx, "logo.png", strings.NewReader("png"),
+		filekit.WithContentType("image/png"), filekit.WithMetadata(map[string]string{"owner": "web"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := filekit.NewFallbackFileSystem(primary, secondary, filekit.WithPopulateOnMiss())
+
+	if _, err := fs.ReadAll(ctx, "logo.png"); err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	info, err := primary.Stat(ctx, "logo.png")
+	if err != nil {
+		t.Fatalf("primary Stat: %v", err)
+	}
+	if info.ContentType != "image/png" || info.Metadata["owner"] != "web" {
+		t.Errorf("populated copy has ContentType %q, Metadata %v; want the secondary's", info.ContentType, info.Metadata)
+	}
+}
+
+// truncatingFS serves reads that fail after the first few bytes.
+type truncatingFS struct {
+	filekit.FileSystem
+}
+
+func (f truncatingFS) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	rc, err := f.FileSystem.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(io.MultiReader(io.LimitReader(rc, 4), iotest.ErrReader(errors.New("connection reset")))), nil
+}
+
+func TestFallbackFileSystem_FailedPopulateLeavesNoPartialFile(t *testing.T) {
+	ctx := context.Background()
+	primary, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New: %v", err)
+	}
+	secondary := memory.New()
+	seedTree(t, secondary, map[string]string{"origin.txt": "from origin"})
+	fs := filekit.NewFallbackFileSystem(primary, truncatingFS{secondary}, filekit.WithPopulateOnMiss())
+
+	if rc, err := fs.Read(ctx, "origin.txt"); err == nil {
+		rc.Close()
+	}
+	if exists, _ := primary.FileExists(ctx, "origin.txt"); exists {
+		data, _ := primary.ReadAll(ctx, "origin.txt")
+		t.Errorf("primary kept %q after a failed populate, want no file", data)
+	}
+}
+
+func TestFallbackFileSystem_WriteToSecondary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	fs := filekit.NewFallbackFileSystem(primary, secondary, filekit.WithWriteToSecondary())
+
+	if _, err := fs.Write(ctx, "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fs.Move(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	for _, backend := range []filekit.FileSystem{primary, secondary} {
+		if data, err := backend.ReadAll(ctx, "b.txt"); err != nil || string(data) != "a" {
+			t.Errorf("b.txt = %q, %v; want it on both backends", data, err)
+		}
+	}
+
+	if err := fs.Delete(ctx, "b.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, _ := fs.FileExists(ctx, "b.txt"); exists {
+		t.Error("deleted file still visible through the fallback")
+	}
+}
@@ -0,0 +1,139 @@
+package filekit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ============================================================================
+// FileInfo JSON - Stable wire format
+// ============================================================================
+
+// fileInfoJSON is the wire format of FileInfo. Field names are snake_case
+// and stable; is_dir is always present, other optional fields are omitted
+// when empty and times are RFC 3339 with nanoseconds.
+type fileInfoJSON struct {
+	Name                 string            `json:"name"`
+	Path                 string            `json:"path"`
+	Size                 int64             `json:"size"`
+	ModTime              string            `json:"mod_time,omitempty"`
+	IsDir                bool              `json:"is_dir"`
+	IsSymlink            bool              `json:"is_symlink,omitempty"`
+	ContentType          string            `json:"content_type,omitempty"`
+	ContentDisposition   string            `json:"content_disposition,omitempty"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	ETag                 string            `json:"etag,omitempty"`
+	Version              string            `json:"version,omitempty"`
+	StorageClass         string            `json:"storage_class,omitempty"`
+	ServerSideEncryption string            `json:"server_side_encryption,omitempty"`
+	EncryptionKeyID      string            `json:"encryption_key_id,omitempty"`
+	Checksum             string            `json:"checksum,omitempty"`
+	ChecksumAlgorithm    ChecksumAlgorithm `json:"checksum_algorithm,omitempty"`
+	CreatedAt            string            `json:"created_at,omitempty"`
+	AccessedAt           string            `json:"accessed_at,omitempty"`
+	Owner                *FileOwner        `json:"owner,omitempty"`
+	Permissions          *FilePermissions  `json:"permissions,omitempty"`
+}
+
+// MarshalJSON encodes the file info with stable snake_case field names.
+// ModTime, CreatedAt and AccessedAt are RFC 3339 timestamps, and empty
+// optional fields, including an empty Metadata map and a zero ModTime, are
+// omitted. is_dir is always present.
+//
+// Example output:
+//
+//	{"name":"a.txt","path":"docs/a.txt","size":5,"mod_time":"2024-06-01T12:00:00Z","is_dir":false,"content_type":"text/plain"}
+func (f FileInfo) MarshalJSON() ([]byte, error) {
+	w := fileInfoJSON{
+		Name:                 f.Name,
+		Path:                 f.Path,
+		Size:                 f.Size,
+		ModTime:              formatJSONTime(f.ModTime),
+		IsDir:                f.IsDir,
+		IsSymlink:            f.IsSymlink,
+		ContentType:          f.ContentType,
+		ContentDisposition:   f.ContentDisposition,
+		Metadata:             f.Metadata,
+		ETag:                 f.ETag,
+		Version:              f.Version,
+		StorageClass:         f.StorageClass,
+		ServerSideEncryption: f.ServerSideEncryption,
+		EncryptionKeyID:      f.EncryptionKeyID,
+		Checksum:             f.Checksum,
+		ChecksumAlgorithm:    f.ChecksumAlgorithm,
+		Owner:                f.Owner,
+		Permissions:          f.Permissions,
+	}
+	if f.CreatedAt != nil {
+		w.CreatedAt = formatJSONTime(*f.CreatedAt)
+	}
+	if f.AccessedAt != nil {
+		w.AccessedAt = formatJSONTime(*f.AccessedAt)
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON. Missing fields
+// are left zero, so an omitted metadata object decodes to a nil map.
+func (f *FileInfo) UnmarshalJSON(data []byte) error {
+	var w fileInfoJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+
+	modTime, err := parseJSONTime(w.ModTime)
+	if err != nil {
+		return err
+	}
+	*f = FileInfo{
+		Name:                 w.Name,
+		Path:                 w.Path,
+		Size:                 w.Size,
+		ModTime:              modTime,
+		IsDir:                w.IsDir,
+		IsSymlink:            w.IsSymlink,
+		ContentType:          w.ContentType,
+		ContentDisposition:   w.ContentDisposition,
+		Metadata:             w.Metadata,
+		ETag:                 w.ETag,
+		Version:              w.Version,
+		StorageClass:         w.StorageClass,
+		ServerSideEncryption: w.ServerSideEncryption,
+		EncryptionKeyID:      w.EncryptionKeyID,
+		Checksum:             w.Checksum,
+		ChecksumAlgorithm:    w.ChecksumAlgorithm,
+		Owner:                w.Owner,
+		Permissions:          w.Permissions,
+	}
+	if w.CreatedAt != "" {
+		t, err := parseJSONTime(w.CreatedAt)
+		if err != nil {
+			return err
+		}
+		f.CreatedAt = &t
+	}
+	if w.AccessedAt != "" {
+		t, err := parseJSONTime(w.AccessedAt)
+		if err != nil {
+			return err
+		}
+		f.AccessedAt = &t
+	}
+	return nil
+}
+
+// formatJSONTime formats t as RFC 3339 with nanoseconds, or "" if t is zero
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseJSONTime parses an RFC 3339 timestamp; "" is the zero time
+func parseJSONTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
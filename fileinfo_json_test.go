@@ -0,0 +1,105 @@
+package filekit_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+)
+
+func TestFileInfoJSON_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	accessed := time.Date(2024, 6, 1, 8, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name string
+		info filekit.FileInfo
+	}{
+		{"minimal", filekit.FileInfo{Name: "a.txt", Path: "a.txt", Size: 5}},
+		{"directory", filekit.FileInfo{Name: "docs", Path: "docs", IsDir: true, ModTime: created}},
+		{"full", filekit.FileInfo{
+			Name:                 "report.pdf",
+			Path:                 "reports/report.pdf",
+			Size:                 1024,
+			ModTime:              time.Date(2024, 6, 1, 12, 30, 0, 123456789, time.UTC),
+			IsSymlink:            true,
+			ContentType:          "application/pdf",
+			ContentDisposition:   `attachment; filename="report.pdf"`,
+			Metadata:             map[string]string{"author": "qa"},
+			ETag:                 `"abc123"`,
+			Version:              "v2",
+			StorageClass:         "STANDARD_IA",
+			ServerSideEncryption: "aws:kms",
+			EncryptionKeyID:      "key-1",
+			Checksum:             "deadbeef",
+			ChecksumAlgorithm:    filekit.ChecksumSHA256,
+			CreatedAt:            &created,
+			AccessedAt:           &accessed,
+			Owner:                &filekit.FileOwner{ID: "1000", DisplayName: "QA", Email: "qa@example.com"},
+			Permissions: &filekit.FilePermissions{
+				Mode:     "0644",
+				ACL:      []filekit.ACLEntry{{Grantee: "AllUsers", Permission: "READ", GranteeType: "GROUP"}},
+				IsPublic: true,
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.info)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got filekit.FileInfo
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			// Parsed times carry a different *time.Location; compare instants
+			if !got.ModTime.Equal(tt.info.ModTime) || !equalTimes(got.CreatedAt, tt.info.CreatedAt) || !equalTimes(got.AccessedAt, tt.info.AccessedAt) {
+				t.Errorf("times = %v, %v, %v; want %v, %v, %v", got.ModTime, got.CreatedAt, got.AccessedAt, tt.info.ModTime, tt.info.CreatedAt, tt.info.AccessedAt)
+			}
+			got.ModTime, got.CreatedAt, got.AccessedAt = tt.info.ModTime, tt.info.CreatedAt, tt.info.AccessedAt
+			if !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("round trip = %+v\nwant %+v", got, tt.info)
+			}
+		})
+	}
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestFileInfoJSON_Format(t *testing.T) {
+	info := filekit.FileInfo{
+		Name:     "a.txt",
+		Path:     "docs/a.txt",
+		Size:     5,
+		ModTime:  time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Metadata: map[string]string{},
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"name":"a.txt","path":"docs/a.txt","size":5,"mod_time":"2024-06-01T12:00:00Z","is_dir":false}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s\nwant %s", data, want)
+	}
+
+	// Pointers and slices of FileInfo use the same encoding
+	data, _ = json.Marshal([]*filekit.FileInfo{{Name: "d", Path: "d", IsDir: true}})
+	if want := `[{"name":"d","path":"d","size":0,"is_dir":true}]`; string(data) != want {
+		t.Errorf("Marshal(slice) = %s, want %s", data, want)
+	}
+
+	var bad filekit.FileInfo
+	if err := json.Unmarshal([]byte(`{"name":"a","mod_time":"yesterday"}`), &bad); err == nil || !strings.Contains(err.Error(), "yesterday") {
+		t.Errorf("Unmarshal(invalid mod_time) = %v, want a parse error", err)
+	}
+}
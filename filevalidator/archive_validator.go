@@ -25,9 +25,15 @@ type ArchiveValidator struct {
 	// Prevents decompression bombs that expand to terabytes.
 	MaxUncompressedSize int64
 
-	// MaxNestedArchives is the maximum depth of nested archives (zip within zip).
-	// Prevents recursive archive attacks.
+	// MaxNestedArchives is the maximum number of nested archives (zip within zip)
+	// contained in a single archive. Prevents recursive archive attacks.
 	MaxNestedArchives int
+
+	// MaxNestedDepth is the maximum nesting depth of archives (zip within zip within zip).
+	// Stored (uncompressed) nested ZIPs are descended into via their central directory;
+	// compressed nested archives count as a single level since inspecting them would
+	// require inflating the entry. 0 disables the depth check.
+	MaxNestedDepth int
 }
 
 // DefaultArchiveValidator creates an archive validator with sensible defaults
@@ -37,6 +43,7 @@ func DefaultArchiveValidator() *ArchiveValidator {
 		MaxFiles:            1000,
 		MaxUncompressedSize: 1 * GB,
 		MaxNestedArchives:   3,
+		MaxNestedDepth:      3,
 	}
 }
 
@@ -73,7 +80,13 @@ func (v *ArchiveValidator) validateWithReaderAt(reader io.ReaderAt, size int64)
 		return NewValidationError(ErrorTypeContent, fmt.Sprintf("cannot open archive: %v", err))
 	}
 
-	var totalUncompressedSize uint64
+	return v.validateZip(reader, zipReader, size, 0)
+}
+
+// validateZip checks the central directory of an opened ZIP archive.
+// depth is the nesting level of this archive (0 for the top-level archive).
+func (v *ArchiveValidator) validateZip(reader io.ReaderAt, zipReader *zip.Reader, size int64, depth int) error {
+	var totalUncompressedSize, totalCompressedSize uint64
 	fileCount := 0
 	nestedArchives := 0
 
@@ -94,6 +107,9 @@ func (v *ArchiveValidator) validateWithReaderAt(reader io.ReaderAt, size int64)
 				return NewValidationError(ErrorTypeContent,
 					fmt.Sprintf("too many nested archives: %d (max: %d)", nestedArchives, v.MaxNestedArchives))
 			}
+			if err := v.validateNested(reader, file, depth+1); err != nil {
+				return err
+			}
 		}
 
 		// Calculate compression ratio and total size
@@ -107,6 +123,7 @@ func (v *ArchiveValidator) validateWithReaderAt(reader io.ReaderAt, size int64)
 		}
 
 		totalUncompressedSize += file.UncompressedSize64
+		totalCompressedSize += file.CompressedSize64
 
 		// Check if we've exceeded the total uncompressed size limit
 		if v.MaxUncompressedSize > 0 && totalUncompressedSize > uint64(v.MaxUncompressedSize) { //nolint:gosec // MaxUncompressedSize is validated to be positive
@@ -122,9 +139,14 @@ func (v *ArchiveValidator) validateWithReaderAt(reader io.ReaderAt, size int64)
 		}
 	}
 
-	// Additional check: total compression ratio
-	if totalUncompressedSize > 0 && size > 0 {
-		totalRatio := float64(totalUncompressedSize) / float64(size)
+	// Additional check: total compression ratio as declared by the central directory.
+	// Falls back to the archive size when no entry declares a compressed size.
+	compressed := float64(totalCompressedSize)
+	if compressed == 0 {
+		compressed = float64(size)
+	}
+	if totalUncompressedSize > 0 && compressed > 0 {
+		totalRatio := float64(totalUncompressedSize) / compressed
 		if totalRatio > v.MaxCompressionRatio {
 			return NewValidationError(ErrorTypeContent,
 				fmt.Sprintf("archive has suspicious total compression ratio: %.2f:1", totalRatio))
@@ -134,6 +156,34 @@ func (v *ArchiveValidator) validateWithReaderAt(reader io.ReaderAt, size int64)
 	return nil
 }
 
+// validateNested enforces MaxNestedDepth for an archive entry found at the given depth.
+// Only stored (uncompressed) entries are descended into, since their bytes can be
+// read in place without inflating anything.
+func (v *ArchiveValidator) validateNested(reader io.ReaderAt, file *zip.File, depth int) error {
+	if v.MaxNestedDepth > 0 && depth > v.MaxNestedDepth {
+		return NewValidationError(ErrorTypeContent,
+			fmt.Sprintf("archive nesting too deep at %s: depth %d (max: %d)", file.Name, depth, v.MaxNestedDepth))
+	}
+
+	if file.Method != zip.Store || file.CompressedSize64 == 0 {
+		return nil
+	}
+
+	offset, err := file.DataOffset()
+	if err != nil {
+		return NewValidationError(ErrorTypeContent, fmt.Sprintf("cannot locate nested archive %s: %v", file.Name, err))
+	}
+
+	nestedSize := int64(file.CompressedSize64) //nolint:gosec // bounded by the outer archive size
+	section := io.NewSectionReader(reader, offset, nestedSize)
+	if nestedReader, err := zip.NewReader(section, nestedSize); err == nil {
+		return v.validateZip(section, nestedReader, nestedSize, depth)
+	}
+
+	// Not actually a ZIP despite the name; nothing further to inspect
+	return nil
+}
+
 // SupportedMIMETypes returns the MIME types this validator can handle.
 // Only ZIP-based formats are actually validated.
 func (v *ArchiveValidator) SupportedMIMETypes() []string {
@@ -185,3 +185,103 @@ func TestArchiveValidator_SupportedMIMETypes(t *testing.T) {
 func containsString(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
 }
+
+// buildStoredZip creates a ZIP whose single entry is stored (uncompressed), so the
+// validator can descend into it without inflating.
+func buildStoredZip(name string, content []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func TestArchiveValidator_DeclaredRatioInCentralDirectory(t *testing.T) {
+	// Craft a central directory that declares a huge uncompressed size for a tiny
+	// compressed entry. Nothing is inflated; only the headers are inspected.
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	raw := []byte{0x03, 0x00} // empty deflate stream
+	f, err := w.CreateRaw(&zip.FileHeader{
+		Name:               "bomb.txt",
+		Method:             zip.Deflate,
+		CompressedSize64:   uint64(len(raw)),
+		UncompressedSize64: 10 * 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	validator := DefaultArchiveValidator()
+	err = validator.ValidateContent(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Fatal("Expected error for high declared compression ratio, got nil")
+	}
+	if !containsString(err.Error(), "compression ratio") {
+		t.Errorf("Expected compression ratio error, got: %v", err)
+	}
+
+	// A generous limit lets the same archive through
+	validator.MaxCompressionRatio = 1e9
+	if err := validator.ValidateContent(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Errorf("Expected no error with raised ratio limit, got: %v", err)
+	}
+}
+
+func TestArchiveValidator_MaxNestedDepth(t *testing.T) {
+	// level3.zip inside level2.zip inside level1.zip inside the outer archive
+	inner, err := buildStoredZip("data.txt", []byte("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"level3.zip", "level2.zip", "level1.zip"} {
+		inner, err = buildStoredZip(name, inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		maxDepth  int
+		wantError bool
+	}{
+		{name: "depth within limit", maxDepth: 3, wantError: false},
+		{name: "depth exceeds limit", maxDepth: 2, wantError: true},
+		{name: "depth check disabled", maxDepth: 0, wantError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := DefaultArchiveValidator()
+			validator.MaxCompressionRatio = 1e9
+			validator.MaxNestedDepth = tt.maxDepth
+
+			err := validator.ValidateContent(bytes.NewReader(inner), int64(len(inner)))
+			if tt.wantError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				} else if !containsString(err.Error(), "nesting too deep") {
+					t.Errorf("Expected nesting error, got: %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
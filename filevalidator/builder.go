@@ -1,6 +1,9 @@
 package filevalidator
 
-import "regexp"
+import (
+	"regexp"
+	"time"
+)
 
 // Builder provides a fluent API for constructing validators
 type Builder struct {
@@ -77,6 +80,11 @@ func (b *Builder) AcceptMedia() *Builder {
 	return b.AcceptAudio().AcceptVideo()
 }
 
+// AcceptFonts allows all font types (WOFF, WOFF2, TTF, OTF)
+func (b *Builder) AcceptFonts() *Builder {
+	return b.Accept(string(AllowAllFonts))
+}
+
 // AcceptAll allows all file types
 func (b *Builder) AcceptAll() *Builder {
 	return b.Accept(string(AllowAll))
@@ -88,6 +96,18 @@ func (b *Builder) StrictMIME() *Builder {
 	return b
 }
 
+// AllowMIMEMismatch lets files whose extension implies the claimed MIME type pass
+// strict MIME validation when their content is detected as one of the given types
+func (b *Builder) AllowMIMEMismatch(claimed string, detected ...string) *Builder {
+	benign := make(map[string][]string, len(b.constraints.BenignMIMEMismatches)+1)
+	for k, v := range b.constraints.BenignMIMEMismatches {
+		benign[k] = v
+	}
+	benign[claimed] = append(append([]string(nil), benign[claimed]...), detected...)
+	b.constraints.BenignMIMEMismatches = benign
+	return b
+}
+
 // --- Extension constraints ---
 
 // Extensions sets the allowed file extensions (e.g., ".jpg", ".png")
@@ -116,12 +136,27 @@ func (b *Builder) AllowNoExtension() *Builder {
 
 // --- Filename constraints ---
 
-// MaxNameLength sets the maximum filename length
+// MaxNameLength sets the maximum filename length in bytes
 func (b *Builder) MaxNameLength(length int) *Builder {
 	b.constraints.MaxNameLength = length
 	return b
 }
 
+// MaxFilenameLength sets the maximum filename length in bytes.
+// Most filesystems limit a name to 255 bytes, which is fewer than 255
+// characters for non-ASCII names.
+func (b *Builder) MaxFilenameLength(n int) *Builder {
+	return b.MaxNameLength(n)
+}
+
+// RequireNFC rejects filenames that are not in Unicode Normalization Form C.
+// macOS and some clients send decomposed (NFD) names; mixing forms yields
+// distinct files whose names look identical.
+func (b *Builder) RequireNFC() *Builder {
+	b.constraints.RequireNFC = true
+	return b
+}
+
 // FileNamePattern sets a regex pattern for valid filenames
 func (b *Builder) FileNamePattern(pattern *regexp.Regexp) *Builder {
 	b.constraints.FileNameRegex = pattern
@@ -161,6 +196,14 @@ func (b *Builder) RequireContentValidation() *Builder {
 	return b
 }
 
+// RequireContentValidationFor makes content validation mandatory for the given
+// MIME types only; content failures for other types stay warnings.
+func (b *Builder) RequireContentValidationFor(mimeTypes ...string) *Builder {
+	b.constraints.ContentValidationEnabled = true
+	b.constraints.RequireContentValidationFor = append(b.constraints.RequireContentValidationFor, mimeTypes...)
+	return b
+}
+
 // WithRegistry sets a custom content validator registry
 func (b *Builder) WithRegistry(registry *ContentValidatorRegistry) *Builder {
 	b.constraints.ContentValidatorRegistry = registry
@@ -179,6 +222,92 @@ func (b *Builder) WithMinimalRegistry() *Builder {
 	return b
 }
 
+// WithZipBombLimits configures the archive validator's zip-bomb limits and makes
+// content validation mandatory for archive types, since content failures are
+// otherwise only warnings; other types keep their setting. Limits are checked
+// against the central directory only:
+//   - maxRatio: maximum declared uncompressed-to-compressed ratio (per entry and total)
+//   - maxEntries: maximum number of entries in the archive
+//   - maxNestedDepth: maximum depth of archives nested within the archive
+//
+// A value <= 0 keeps the corresponding default from DefaultArchiveValidator.
+func (b *Builder) WithZipBombLimits(maxRatio float64, maxEntries int, maxNestedDepth int) *Builder {
+	archiveValidator := DefaultArchiveValidator()
+	if maxRatio > 0 {
+		archiveValidator.MaxCompressionRatio = maxRatio
+	}
+	if maxEntries > 0 {
+		archiveValidator.MaxFiles = maxEntries
+	}
+	if maxNestedDepth > 0 {
+		archiveValidator.MaxNestedDepth = maxNestedDepth
+	}
+
+	// Clone so a shared registry (e.g. GetDefaultRegistry) is never mutated
+	if b.constraints.ContentValidatorRegistry == nil {
+		b.constraints.ContentValidatorRegistry = NewContentValidatorRegistry()
+	} else {
+		b.constraints.ContentValidatorRegistry = b.constraints.ContentValidatorRegistry.Clone()
+	}
+	for _, mime := range archiveValidator.SupportedMIMETypes() {
+		b.constraints.ContentValidatorRegistry.Register(mime, archiveValidator)
+	}
+
+	return b.RequireContentValidationFor(archiveValidator.SupportedMIMETypes()...)
+}
+
+// RejectMacros routes Office documents (DOCX, XLSX, PPTX and legacy DOC, XLS,
+// PPT) through an OfficeValidator that rejects VBA macros. Detection reads only
+// the ZIP central directory or the OLE directory. Content validation is made
+// mandatory for these Office types, since content failures are otherwise only
+// warnings; other types keep their setting.
+func (b *Builder) RejectMacros() *Builder {
+	officeValidator := DefaultOfficeValidator()
+
+	// Clone so a shared registry (e.g. GetDefaultRegistry) is never mutated
+	if b.constraints.ContentValidatorRegistry == nil {
+		b.constraints.ContentValidatorRegistry = NewContentValidatorRegistry()
+	} else {
+		b.constraints.ContentValidatorRegistry = b.constraints.ContentValidatorRegistry.Clone()
+	}
+	mimeTypes := append(officeValidator.SupportedMIMETypes(),
+		"application/vnd.ms-word.document.macroEnabled.12",
+		"application/vnd.ms-excel.sheet.macroEnabled.12",
+		"application/vnd.ms-powerpoint.presentation.macroEnabled.12",
+	)
+	for _, mime := range mimeTypes {
+		b.constraints.ContentValidatorRegistry.Register(mime, officeValidator)
+	}
+
+	return b.RequireContentValidationFor(mimeTypes...)
+}
+
+// MaxMediaDuration rejects MP4/MOV and WebM/Matroska files whose declared
+// duration exceeds maxDuration. The duration is read from the first
+// DefaultMediaHeaderBytes of the file; the format validator already registered
+// for each media type still runs. Content validation is made mandatory for
+// these media types, since content failures are otherwise only warnings; other
+// types keep their setting.
+func (b *Builder) MaxMediaDuration(maxDuration time.Duration) *Builder {
+	// Clone so a shared registry (e.g. GetDefaultRegistry) is never mutated
+	if b.constraints.ContentValidatorRegistry == nil {
+		b.constraints.ContentValidatorRegistry = NewContentValidatorRegistry()
+	} else {
+		b.constraints.ContentValidatorRegistry = b.constraints.ContentValidatorRegistry.Clone()
+	}
+	registry := b.constraints.ContentValidatorRegistry
+	mimeTypes := (&MediaDurationValidator{}).SupportedMIMETypes()
+	for _, mime := range mimeTypes {
+		format := registry.GetValidator(mime)
+		if existing, ok := format.(*MediaDurationValidator); ok {
+			format = existing.Format // replace an earlier limit rather than stacking
+		}
+		registry.Register(mime, NewMediaDurationValidator(maxDuration, format))
+	}
+
+	return b.RequireContentValidationFor(mimeTypes...)
+}
+
 // --- Build ---
 
 // Build creates the validator with the configured constraints
@@ -200,7 +329,8 @@ func ForImages() *Builder {
 		Extensions(".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg", ".bmp", ".tiff", ".tif").
 		MaxSize(10 * MB).
 		WithRegistry(ImageOnlyRegistry()).
-		WithContentValidation()
+		WithContentValidation().
+		RequireContentValidationFor("image/svg+xml") // scripts and external references are blocked
 }
 
 // ForDocuments creates a builder pre-configured for document uploads
@@ -213,11 +343,39 @@ func ForDocuments() *Builder {
 		WithContentValidation()
 }
 
-// ForMedia creates a builder pre-configured for audio/video uploads
+// Extensions accepted by the ForAudio and ForVideo presets; ForMedia accepts both
+var (
+	audioPresetExtensions = []string{".mp3", ".aac", ".flac", ".ogg", ".wav", ".m4a"}
+	videoPresetExtensions = []string{".mp4", ".webm", ".mov", ".mkv", ".avi"}
+)
+
+// ForAudio creates a builder pre-configured for audio uploads (podcasts, music)
+func ForAudio() *Builder {
+	return NewBuilder().
+		AcceptAudio().
+		Extensions(audioPresetExtensions...).
+		MaxSize(50 * MB).
+		WithRegistry(AudioOnlyRegistry()).
+		WithContentValidation()
+}
+
+// ForVideo creates a builder pre-configured for video uploads
+func ForVideo() *Builder {
+	return NewBuilder().
+		AcceptVideo().
+		Extensions(videoPresetExtensions...).
+		MaxSize(2 * GB).
+		WithRegistry(VideoOnlyRegistry()).
+		WithContentValidation()
+}
+
+// ForMedia creates a builder pre-configured for audio/video uploads,
+// accepting everything ForAudio and ForVideo accept
 func ForMedia() *Builder {
 	return NewBuilder().
 		AcceptMedia().
-		Extensions(".mp3", ".wav", ".ogg", ".flac", ".aac", ".mp4", ".webm", ".avi", ".mov", ".mkv").
+		Extensions(audioPresetExtensions...).
+		Extensions(videoPresetExtensions...).
 		MaxSize(500 * MB).
 		WithRegistry(MediaOnlyRegistry()).
 		WithContentValidation()
@@ -247,6 +405,14 @@ func ForArchives() *Builder {
 		WithContentValidation()
 }
 
+// ForFonts creates a builder pre-configured for web font uploads
+func ForFonts() *Builder {
+	return NewBuilder().
+		AcceptFonts().
+		Extensions(".woff", ".woff2", ".ttf", ".otf").
+		MaxSize(10 * MB)
+}
+
 // ForWeb creates a builder for typical web uploads (images + documents)
 func ForWeb() *Builder {
 	return NewBuilder().
@@ -260,7 +426,8 @@ func ForWeb() *Builder {
 		).
 		MaxSize(25 * MB).
 		WithDefaultRegistry().
-		WithContentValidation()
+		WithContentValidation().
+		RequireContentValidationFor("image/svg+xml") // scripts and external references are blocked
 }
 
 // Strict creates a builder with strict validation settings
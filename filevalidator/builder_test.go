@@ -1,7 +1,11 @@
 package filevalidator
 
 import (
+	"archive/zip"
+	"bytes"
+	"fmt"
 	"regexp"
+	"slices"
 	"testing"
 )
 
@@ -239,6 +243,56 @@ func TestPreset_ForMedia(t *testing.T) {
 	}
 }
 
+func TestPreset_ForAudioForVideo(t *testing.T) {
+	mp4 := []byte{0x00, 0x00, 0x00, 0x18, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm', 0x00, 0x00, 0x02, 0x00, 'i', 's', 'o', 'm', 'm', 'p', '4', '1'}
+	mp3 := []byte{'I', 'D', '3', 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	audio := ForAudio().Build()
+	video := ForVideo().Build()
+	media := ForMedia().Build()
+
+	if c := audio.GetConstraints(); c.MaxFileSize != 50*MB {
+		t.Errorf("ForAudio MaxFileSize = %d, want %d", c.MaxFileSize, 50*MB)
+	}
+	if c := video.GetConstraints(); c.MaxFileSize != 2*GB {
+		t.Errorf("ForVideo MaxFileSize = %d, want %d", c.MaxFileSize, 2*GB)
+	}
+
+	// An mp4 is video: renaming it to an audio extension does not get it past ForAudio
+	if err := audio.ValidateBytes(mp4, "clip.mp4"); !IsErrorOfType(err, ErrorTypeExtension) {
+		t.Errorf("ForAudio(clip.mp4) = %v, want extension error", err)
+	}
+	if err := audio.ValidateBytes(mp4, "clip.m4a"); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("ForAudio(mp4 as clip.m4a) = %v, want MIME error", err)
+	}
+	if err := video.ValidateBytes(mp4, "clip.mp4"); err != nil {
+		t.Errorf("ForVideo(clip.mp4) = %v, want accepted", err)
+	}
+
+	if err := audio.ValidateBytes(mp3, "episode.mp3"); err != nil {
+		t.Errorf("ForAudio(episode.mp3) = %v, want accepted", err)
+	}
+	if err := video.ValidateBytes(mp3, "episode.mp4"); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("ForVideo(mp3 as episode.mp4) = %v, want MIME error", err)
+	}
+
+	// ForMedia is the union
+	for _, tt := range []struct {
+		data []byte
+		name string
+	}{{mp4, "clip.mp4"}, {mp3, "episode.mp3"}} {
+		if err := media.ValidateBytes(tt.data, tt.name); err != nil {
+			t.Errorf("ForMedia(%s) = %v, want accepted", tt.name, err)
+		}
+	}
+	exts := media.GetConstraints().AllowedExts
+	for _, ext := range append(audio.GetConstraints().AllowedExts, video.GetConstraints().AllowedExts...) {
+		if !slices.Contains(exts, ext) {
+			t.Errorf("ForMedia does not allow %s", ext)
+		}
+	}
+}
+
 func TestPreset_ForArchives(t *testing.T) {
 	v := ForArchives().Build()
 	c := v.GetConstraints()
@@ -248,6 +302,27 @@ func TestPreset_ForArchives(t *testing.T) {
 	}
 }
 
+func TestPreset_ForFonts(t *testing.T) {
+	v := ForFonts().Build()
+	if c := v.GetConstraints(); c.MaxFileSize != 10*MB {
+		t.Errorf("MaxFileSize = %d, want %d", c.MaxFileSize, 10*MB)
+	}
+
+	for ext, header := range fontHeaders {
+		if err := v.ValidateBytes(header, "font"+ext); err != nil {
+			t.Errorf("ValidateBytes(font%s) = %v, want accepted", ext, err)
+		}
+	}
+
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	if err := v.ValidateBytes(png, "font.ttf"); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("ValidateBytes(PNG as font.ttf) = %v, want MIME error", err)
+	}
+	if err := v.ValidateBytes(fontHeaders[".ttf"], "font.png"); !IsErrorOfType(err, ErrorTypeExtension) {
+		t.Errorf("ValidateBytes(font.png) = %v, want extension error", err)
+	}
+}
+
 func TestPreset_ForWeb(t *testing.T) {
 	v := ForWeb().Build()
 	c := v.GetConstraints()
@@ -295,3 +370,56 @@ func TestBuilder_Constraints(t *testing.T) {
 		t.Error("Constraints() should return current state")
 	}
 }
+
+func TestBuilder_WithZipBombLimits(t *testing.T) {
+	b := Empty().WithZipBombLimits(50, 10, 2)
+	c := b.Constraints()
+
+	if !c.ContentValidationEnabled || !slices.Contains(c.RequireContentValidationFor, "application/zip") {
+		t.Error("content validation should be enabled and required for archives")
+	}
+	if c.RequireContentValidation {
+		t.Error("content validation should stay optional for other types")
+	}
+
+	v, ok := c.ContentValidatorRegistry.GetValidator("application/zip").(*ArchiveValidator)
+	if !ok {
+		t.Fatal("archive validator should be registered for application/zip")
+	}
+	if v.MaxCompressionRatio != 50 || v.MaxFiles != 10 || v.MaxNestedDepth != 2 {
+		t.Errorf("limits not applied: ratio=%v files=%d depth=%d", v.MaxCompressionRatio, v.MaxFiles, v.MaxNestedDepth)
+	}
+
+	// Non-positive values keep defaults
+	v = NewBuilder().WithZipBombLimits(0, 0, 0).Constraints().ContentValidatorRegistry.GetValidator("application/zip").(*ArchiveValidator)
+	def := DefaultArchiveValidator()
+	if v.MaxCompressionRatio != def.MaxCompressionRatio || v.MaxFiles != def.MaxFiles || v.MaxNestedDepth != def.MaxNestedDepth {
+		t.Error("non-positive limits should keep defaults")
+	}
+
+	// Shared registries are not mutated
+	shared := GetDefaultRegistry()
+	before := shared.GetValidator("application/zip")
+	NewBuilder().WithRegistry(shared).WithZipBombLimits(5, 5, 1)
+	if shared.GetValidator("application/zip") != before {
+		t.Error("WithZipBombLimits should not mutate a shared registry")
+	}
+
+	// An archive over the limits is rejected, not just warned about
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < 20; i++ {
+		w, err := zw.Create(fmt.Sprintf("file%d.txt", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("content"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	validator := NewBuilder().Accept("application/zip").Extensions(".zip").WithZipBombLimits(0, 5, 0).Build()
+	if err := validator.ValidateBytes(buf.Bytes(), "a.zip"); !IsErrorOfType(err, ErrorTypeContent) {
+		t.Errorf("ValidateBytes() on a 20-entry zip with a 5-entry limit = %v, want ErrorTypeContent", err)
+	}
+}
@@ -33,10 +33,13 @@ type Constraints struct {
 	// These extensions will be blocked regardless of AllowedExts configuration
 	BlockedExts []string
 
-	// MaxNameLength is the maximum allowed length for filenames (including extension)
+	// MaxNameLength is the maximum allowed length for filenames in bytes (including extension)
 	// If set to 0, no length limit will be enforced
 	MaxNameLength int
 
+	// RequireNFC rejects filenames that are not valid UTF-8 in Unicode Normalization Form C
+	RequireNFC bool
+
 	// FileNameRegex is an optional regular expression pattern for validating filenames
 	// If nil, no pattern matching will be performed
 	FileNameRegex *regexp.Regexp
@@ -50,12 +53,20 @@ type Constraints struct {
 	// StrictMIMETypeValidation requires that both the MIME type and extension match
 	StrictMIMETypeValidation bool
 
+	// BenignMIMEMismatches maps a MIME type implied by an extension to detected
+	// MIME types that are not treated as a mismatch (e.g., JSON content in a .txt file)
+	BenignMIMEMismatches map[string][]string
+
 	// ContentValidationEnabled enables deep content validation
 	ContentValidationEnabled bool
 
 	// RequireContentValidation makes content validation mandatory
 	RequireContentValidation bool
 
+	// RequireContentValidationFor lists MIME types whose content validation
+	// failures are returned even when RequireContentValidation is false
+	RequireContentValidationFor []string
+
 	// ContentValidatorRegistry holds content validators for different file types
 	ContentValidatorRegistry *ContentValidatorRegistry
 }
@@ -76,6 +87,7 @@ func DefaultConstraints() Constraints {
 		DangerousChars:           []string{"../", "\\", ";", "&", "|", ">", "<", "$", "`", "!", "*"},
 		BlockedExts:              []string{".exe", ".bat", ".cmd", ".sh", ".php", ".phtml", ".pl", ".cgi", ".386", ".dll", ".com", ".torrent", ".app", ".jar", ".pif", ".vb", ".vbs", ".vbe", ".js", ".jse", ".msc", ".ws", ".wsf", ".wsc", ".wsh", ".ps1", ".ps1xml", ".ps2", ".ps2xml", ".psc1", ".psc2", ".msh", ".msh1", ".msh2", ".mshxml", ".msh1xml", ".msh2xml", ".scf", ".lnk", ".inf", ".reg", ".docm", ".dotm", ".xlsm", ".xltm", ".xlam", ".pptm", ".potm", ".ppam", ".ppsm", ".sldm"},
 		RequireExtension:         true,
+		BenignMIMEMismatches:     DefaultBenignMIMEMismatches(),
 		ContentValidationEnabled: true,
 		RequireContentValidation: false,
 		ContentValidatorRegistry: registry,
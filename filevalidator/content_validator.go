@@ -1,7 +1,9 @@
 package filevalidator
 
 import (
+	"bytes"
 	"io"
+	"slices"
 )
 
 // ContentValidator is an interface for validating file contents beyond MIME type
@@ -12,34 +14,126 @@ type ContentValidator interface {
 	SupportedMIMETypes() []string
 }
 
-// ContentValidatorRegistry manages content validators for different file types
+// ContentValidatorRegistry manages content validators for different file types.
+// Each MIME type has a chain of validators that run in order.
 type ContentValidatorRegistry struct {
-	validators map[string]ContentValidator
+	validators map[string][]ContentValidator
 }
 
 // NewContentValidatorRegistry creates a new content validator registry
 func NewContentValidatorRegistry() *ContentValidatorRegistry {
 	return &ContentValidatorRegistry{
-		validators: make(map[string]ContentValidator),
+		validators: make(map[string][]ContentValidator),
 	}
 }
 
-// Register registers a content validator for specific MIME types
+// Register registers a content validator for specific MIME types, replacing
+// any validators already registered for it
 func (r *ContentValidatorRegistry) Register(mimeType string, validator ContentValidator) {
-	r.validators[mimeType] = validator
+	r.RegisterChain(mimeType, validator)
 }
 
-// GetValidator returns the validator for a given MIME type
+// RegisterChain registers validators that run in order for a MIME type,
+// replacing any validators already registered for it. The first failure stops
+// the chain, so cheap structural checks should come before expensive ones.
+// Registering no validators removes the MIME type.
+func (r *ContentValidatorRegistry) RegisterChain(mimeType string, validators ...ContentValidator) {
+	chain := make([]ContentValidator, 0, len(validators))
+	for _, validator := range validators {
+		if validator != nil {
+			chain = append(chain, validator)
+		}
+	}
+	if len(chain) == 0 {
+		delete(r.validators, mimeType)
+		return
+	}
+	r.validators[mimeType] = chain
+}
+
+// Validators returns the chain of validators for a MIME type, in the order
+// they run
+func (r *ContentValidatorRegistry) Validators(mimeType string) []ContentValidator {
+	return slices.Clone(r.validators[mimeType])
+}
+
+// GetValidator returns the validator for a given MIME type. A chain of several
+// validators is returned as one ContentValidator that runs the whole chain.
 func (r *ContentValidatorRegistry) GetValidator(mimeType string) ContentValidator {
-	return r.validators[mimeType]
+	switch chain := r.validators[mimeType]; len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	default:
+		return validatorChain(slices.Clone(chain))
+	}
 }
 
-// ValidateContent validates content using the appropriate validator
+// ValidateContent validates content using the appropriate validators
 func (r *ContentValidatorRegistry) ValidateContent(mimeType string, reader io.Reader, size int64) error {
-	validator := r.GetValidator(mimeType)
-	if validator == nil {
+	chain := r.validators[mimeType]
+	if len(chain) == 0 {
 		// No validator for this MIME type, which is okay
 		return nil
 	}
-	return validator.ValidateContent(reader, size)
+	return validatorChain(chain).ValidateContent(reader, size)
+}
+
+// validatorChain runs validators in order, stopping at the first failure
+type validatorChain []ContentValidator
+
+// ValidateContent gives every validator the content from the reader's current
+// position. Seekable readers are rewound between validators; other readers are
+// buffered in memory when the chain has more than one validator.
+func (c validatorChain) ValidateContent(reader io.Reader, size int64) error {
+	if len(c) == 1 {
+		return c[0].ValidateContent(reader, size)
+	}
+
+	rewind := func() error { return nil }
+	if seeker, ok := reader.(io.Seeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return NewValidationError(ErrorTypeContent, "failed to get current position in reader")
+		}
+		rewind = func() error {
+			_, err := seeker.Seek(start, io.SeekStart)
+			return err
+		}
+	} else {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return NewValidationError(ErrorTypeContent, "failed to read content for validation")
+		}
+		buffered := bytes.NewReader(data)
+		reader = buffered
+		rewind = func() error {
+			_, err := buffered.Seek(0, io.SeekStart)
+			return err
+		}
+	}
+
+	for _, validator := range c {
+		if err := rewind(); err != nil {
+			return NewValidationError(ErrorTypeContent, "failed to reset reader position for content validation")
+		}
+		if err := validator.ValidateContent(reader, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SupportedMIMETypes returns the MIME types of every validator in the chain
+func (c validatorChain) SupportedMIMETypes() []string {
+	var types []string
+	for _, validator := range c {
+		for _, mime := range validator.SupportedMIMETypes() {
+			if !slices.Contains(types, mime) {
+				types = append(types, mime)
+			}
+		}
+	}
+	return types
 }
@@ -3,6 +3,7 @@ package filevalidator
 import (
 	"bytes"
 	"io"
+	"slices"
 	"testing"
 )
 
@@ -72,3 +73,71 @@ func (m *mockContentValidator) ValidateContent(reader io.Reader, size int64) err
 func (m *mockContentValidator) SupportedMIMETypes() []string {
 	return m.mimeTypes
 }
+
+func TestContentValidatorRegistry_RegisterChain(t *testing.T) {
+	var order []string
+	step := func(name string, err error) *mockContentValidator {
+		return &mockContentValidator{
+			mimeTypes: []string{"application/test"},
+			validateFunc: func(reader io.Reader, size int64) error {
+				// Every validator sees the content from the start
+				data, _ := io.ReadAll(reader)
+				order = append(order, name+":"+string(data))
+				return err
+			},
+		}
+	}
+	structural := NewValidationError(ErrorTypeContent, "bad structure")
+	expensive := NewValidationError(ErrorTypeContent, "bad payload")
+
+	registry := NewContentValidatorRegistry()
+	registry.RegisterChain("application/test", step("cheap", nil), step("structure", nil), step("deep", nil))
+	if got := len(registry.Validators("application/test")); got != 3 {
+		t.Fatalf("Validators() returned %d validators, want 3", got)
+	}
+
+	for name, reader := range map[string]io.Reader{
+		"seekable":     bytes.NewReader([]byte("data")),
+		"non-seekable": io.MultiReader(bytes.NewReader([]byte("data"))),
+	} {
+		order = nil
+		if err := registry.ValidateContent("application/test", reader, 4); err != nil {
+			t.Errorf("%s: ValidateContent() = %v", name, err)
+		}
+		if want := []string{"cheap:data", "structure:data", "deep:data"}; !slices.Equal(order, want) {
+			t.Errorf("%s: ran %v, want %v", name, order, want)
+		}
+	}
+
+	// The first failure wins and stops the chain
+	order = nil
+	registry.RegisterChain("application/test", step("cheap", nil), step("structure", structural), step("deep", expensive))
+	if err := registry.ValidateContent("application/test", bytes.NewReader([]byte("data")), 4); err != structural {
+		t.Errorf("ValidateContent() = %v, want the structural failure", err)
+	}
+	if want := []string{"cheap:data", "structure:data"}; !slices.Equal(order, want) {
+		t.Errorf("ran %v, want %v", order, want)
+	}
+
+	// GetValidator runs the whole chain; Register replaces it
+	order = nil
+	if err := registry.GetValidator("application/test").ValidateContent(bytes.NewReader([]byte("x")), 1); err != structural {
+		t.Errorf("GetValidator().ValidateContent() = %v, want the structural failure", err)
+	}
+	registry.Register("application/test", step("only", nil))
+	if got := registry.Validators("application/test"); len(got) != 1 {
+		t.Errorf("Validators() after Register = %d validators, want 1", len(got))
+	}
+
+	// Clones do not share chains
+	clone := registry.Clone()
+	clone.RegisterChain("application/test", step("a", nil), step("b", nil))
+	if len(registry.Validators("application/test")) != 1 {
+		t.Error("RegisterChain on a clone changed the original registry")
+	}
+
+	registry.RegisterChain("application/test")
+	if registry.HasValidator("application/test") {
+		t.Error("RegisterChain with no validators did not remove the MIME type")
+	}
+}
@@ -60,8 +60,11 @@
 //
 //	filevalidator.ForImages()     // JPEG, PNG, GIF, WebP, SVG, BMP, TIFF - 10MB max
 //	filevalidator.ForDocuments()  // PDF, Word, Excel, PowerPoint, TXT, CSV - 50MB max
+//	filevalidator.ForAudio()      // MP3, AAC, FLAC, Ogg, WAV, M4A - 50MB max
+//	filevalidator.ForVideo()      // MP4, WebM, MOV, MKV, AVI - 2GB max
 //	filevalidator.ForMedia()      // Audio + Video - 500MB max
 //	filevalidator.ForArchives()   // ZIP, TAR, GZIP - 1GB max
+//	filevalidator.ForFonts()      // WOFF, WOFF2, TTF, OTF - 10MB max
 //	filevalidator.ForWeb()        // Images + Documents - 25MB max
 //	filevalidator.Strict()        // Strict MIME validation, required extension
 //
@@ -81,6 +84,7 @@
 //   - Office: ZIP structure validation, macro detection
 //   - XML: XXE protection, depth limits
 //   - PDF: Header/trailer structure validation
+//   - Media: MP4/WebM declared duration limits (MaxMediaDuration)
 //
 // All validators read only file headers, never loading full content into memory.
 //
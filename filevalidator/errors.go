@@ -71,3 +71,25 @@ func GetErrorMessage(err error) string {
 	}
 	return ""
 }
+
+// AsValidationErrors returns every ValidationError in err, following both
+// Unwrap() error and Unwrap() []error, so it takes apart the result of
+// errors.Join(v.ValidateAll(file)...). ok is false when err holds none.
+func AsValidationErrors(err error) (errs []*ValidationError, ok bool) {
+	var walk func(error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case *ValidationError:
+			errs = append(errs, e)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return errs, len(errs) > 0
+}
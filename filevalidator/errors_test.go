@@ -2,6 +2,7 @@ package filevalidator
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -169,3 +170,24 @@ func TestNewValidationError(t *testing.T) {
 		t.Errorf("Message = %v, want %v", err.Message, "invalid content")
 	}
 }
+
+func TestAsValidationErrors(t *testing.T) {
+	sizeErr := NewValidationError(ErrorTypeSize, "too big")
+	mimeErr := NewValidationError(ErrorTypeMIME, "wrong type")
+
+	joined := fmt.Errorf("upload rejected: %w", errors.Join(sizeErr, errors.New("io failure"), mimeErr))
+	got, ok := AsValidationErrors(joined)
+	if !ok || len(got) != 2 || got[0] != sizeErr || got[1] != mimeErr {
+		t.Errorf("AsValidationErrors(joined) = %v, %v; want both validation errors", got, ok)
+	}
+
+	if got, ok := AsValidationErrors(sizeErr); !ok || len(got) != 1 {
+		t.Errorf("AsValidationErrors(single) = %v, %v", got, ok)
+	}
+	if got, ok := AsValidationErrors(errors.New("other")); ok || got != nil {
+		t.Errorf("AsValidationErrors(non-validation) = %v, %v; want nil, false", got, ok)
+	}
+	if _, ok := AsValidationErrors(nil); ok {
+		t.Error("AsValidationErrors(nil) reported ok")
+	}
+}
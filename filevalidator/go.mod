@@ -3,3 +3,5 @@ module github.com/gobeaver/filekit/filevalidator
 go 1.24.0
 
 toolchain go1.24.2
+
+require golang.org/x/text v0.30.0
@@ -65,14 +65,15 @@ func (v *ImageValidator) ValidateContent(reader io.Reader, size int64) error {
 	}
 	header = header[:n]
 
+	// Reconstruct reader with the bytes we already read
+	combinedReader := io.MultiReader(bytes.NewReader(header), reader)
+
 	// Check if it's an SVG (text-based, needs different handling)
 	if v.isSVG(header) {
-		return v.validateSVG(size)
+		return v.validateSVG(combinedReader, size)
 	}
 
 	// For binary images, use DecodeConfig which only reads the header
-	// Reconstruct reader with the bytes we already read
-	combinedReader := io.MultiReader(bytes.NewReader(header), reader)
 
 	img, _, err := image.DecodeConfig(combinedReader)
 	if err != nil {
@@ -137,9 +138,9 @@ func (v *ImageValidator) isSVG(data []byte) bool {
 }
 
 // validateSVG validates SVG files.
-// SVG is XML-based, so we just check size limits.
-// For XSS protection, sanitize SVGs at render time, not upload time.
-func (v *ImageValidator) validateSVG(size int64) error {
+// SVG is XML-based and can carry scripts, so it is checked by an SVGValidator
+// rather than decoded as a raster image.
+func (v *ImageValidator) validateSVG(reader io.Reader, size int64) error {
 	if !v.AllowSVG {
 		return NewValidationError(ErrorTypeContent, "SVG files are not allowed")
 	}
@@ -149,7 +150,7 @@ func (v *ImageValidator) validateSVG(size int64) error {
 			fmt.Sprintf("SVG file size %d exceeds maximum %d", size, v.MaxSVGSize))
 	}
 
-	// SVG validated - it's text/XML, dimensions aren't in a standard header
-	// For proper SVG dimension checking, you'd need to parse the XML
-	return nil
+	svgValidator := DefaultSVGValidator()
+	svgValidator.MaxSize = 0 // checked above
+	return svgValidator.ValidateContent(reader, size)
 }
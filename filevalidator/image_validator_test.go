@@ -85,10 +85,8 @@ func TestImageValidator_ValidateContent(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name: "SVG with script (type validation only, not security)",
+			name: "SVG with script",
 			createImg: func() ([]byte, error) {
-				// SVG with script is valid for TYPE validation
-				// Security scanning should be done separately (e.g., ClamAV)
 				svg := `<?xml version="1.0" encoding="UTF-8"?>
 <svg xmlns="http://www.w3.org/2000/svg" width="100" height="100">
   <script>alert('XSS')</script>
@@ -96,7 +94,8 @@ func TestImageValidator_ValidateContent(t *testing.T) {
 </svg>`
 				return []byte(svg), nil
 			},
-			wantError: false, // We only validate type, not security
+			wantError: true,
+			errorMsg:  "<script>",
 		},
 		{
 			name: "invalid image data",
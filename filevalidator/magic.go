@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 )
 
 // MagicSignature defines a file type signature
@@ -34,6 +35,9 @@ var magicSignatures = []MagicSignature{
 	// Documents
 	{MIME: "application/pdf", Offset: 0, Magic: []byte("%PDF-")},
 
+	// OLE compound files: legacy Office (DOC, XLS, PPT), MSI, MSG
+	{MIME: "application/x-ole-storage", Offset: 0, Magic: []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}},
+
 	// Archives - ZIP-based
 	// Note: Office docs (DOCX, XLSX, PPTX) and JAR also use ZIP format
 	// We detect as generic ZIP first, then refine based on content in refineDetection()
@@ -96,24 +100,139 @@ var magicSignatures = []MagicSignature{
 	{MIME: "font/ttf", Offset: 0, Magic: []byte{0x00, 0x01, 0x00, 0x00}},
 }
 
+// MIMESource describes how a MIME type was determined
+type MIMESource string
+
+const (
+	// MIMESourceMagicBytes means the type was identified from a file signature or structure
+	MIMESourceMagicBytes MIMESource = "magic"
+	// MIMESourceExtension means the type was inferred from a file extension, not content
+	MIMESourceExtension MIMESource = "extension"
+	// MIMESourceFallback means no signature matched and the type comes from generic
+	// content sniffing (e.g. text/plain or application/octet-stream)
+	MIMESourceFallback MIMESource = "fallback"
+)
+
+// MIMEResult is the detailed outcome of MIME detection
+type MIMEResult struct {
+	// MIME is the detected MIME type
+	MIME string
+
+	// Category is the GetMIMECategory value for MIME
+	Category string
+
+	// Source tells how MIME was determined; MagicBytes is the most trustworthy
+	Source MIMESource
+
+	// Extensions lists typical extensions for MIME (sorted, may be empty)
+	Extensions []string
+
+	// Charset is the text encoding indicated by a byte order mark ("utf-8",
+	// "utf-16le", "utf-16be", "utf-32le" or "utf-32be"), or empty
+	Charset string
+}
+
+// ContentType returns MIME with a charset parameter when Charset is set,
+// e.g. "text/plain; charset=utf-16le"
+func (r MIMEResult) ContentType() string {
+	if r.Charset == "" {
+		return r.MIME
+	}
+	return r.MIME + "; charset=" + r.Charset
+}
+
 // DetectMIME detects the MIME type from file content using magic bytes
 // Falls back to http.DetectContentType if no magic match found
 func DetectMIME(reader io.Reader) (string, error) {
-	// Read enough bytes for detection (512 bytes covers most signatures)
-	buf := make([]byte, 512)
+	result, err := DetectMIMEDetailed(reader)
+	if err != nil {
+		return "", err
+	}
+	return result.MIME, nil
+}
+
+// DetectMIMEDetailed detects the MIME type from file content and reports its
+// category, whether it came from a signature match or a generic fallback, and
+// typical extensions. Only content is inspected, so Source is never
+// MIMESourceExtension.
+func DetectMIMEDetailed(reader io.Reader) (MIMEResult, error) {
+	// Read enough bytes for detection
+	buf := make([]byte, MIMEHeaderSize)
 	n, err := io.ReadFull(reader, buf)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-		return "", NewValidationError(ErrorTypeMIME, "failed to read file for MIME detection")
+		return MIMEResult{}, NewValidationError(ErrorTypeMIME, "failed to read file for MIME detection")
 	}
-	buf = buf[:n]
 
-	return DetectMIMEFromBytes(buf), nil
+	mime, source, charset := detectMIME(buf[:n])
+	return MIMEResult{
+		MIME:       mime,
+		Category:   GetMIMECategory(mime),
+		Source:     source,
+		Extensions: ExtensionsForMIMEType(mime),
+		Charset:    charset,
+	}, nil
 }
 
+// MIMEHeaderSize is how many bytes of a file MIME detection reads; 512 bytes
+// covers most signatures.
+const MIMEHeaderSize = 512
+
 // DetectMIMEFromBytes detects MIME type from a byte slice
 func DetectMIMEFromBytes(data []byte) string {
+	mime, _, _ := detectMIME(data)
+	return mime
+}
+
+// byteOrderMarks are checked in order; UTF-32LE must come before UTF-16LE,
+// whose mark is its prefix
+var byteOrderMarks = []struct {
+	mark    []byte
+	charset string
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, "utf-8"},
+	{[]byte{0xFF, 0xFE, 0x00, 0x00}, "utf-32le"},
+	{[]byte{0x00, 0x00, 0xFE, 0xFF}, "utf-32be"},
+	{[]byte{0xFF, 0xFE}, "utf-16le"},
+	{[]byte{0xFE, 0xFF}, "utf-16be"},
+}
+
+// detectBOM returns the charset named by a leading byte order mark and the
+// mark's length, or "" and 0
+func detectBOM(data []byte) (string, int) {
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(data, bom.mark) {
+			return bom.charset, len(bom.mark)
+		}
+	}
+	return "", 0
+}
+
+// detectMIME detects the MIME type of data, how it was determined, and the
+// charset named by a byte order mark
+func detectMIME(data []byte) (string, MIMESource, string) {
+	if len(data) == 0 {
+		return "application/octet-stream", MIMESourceFallback, ""
+	}
+
+	// A byte order mark means text. UTF-8 content after the mark is still
+	// sniffed so XML, SVG and the like keep their type.
+	if charset, n := detectBOM(data); charset != "" {
+		if charset == "utf-8" {
+			if mime, source, _ := detectMIME(data[n:]); !IsBinaryMIME(mime) {
+				return mime, source, charset
+			}
+		}
+		return "text/plain", MIMESourceFallback, charset
+	}
+
+	mime, source := sniffMIME(data)
+	return mime, source, ""
+}
+
+// sniffMIME detects the MIME type of data without a byte order mark
+func sniffMIME(data []byte) (string, MIMESource) {
 	if len(data) == 0 {
-		return "application/octet-stream"
+		return "application/octet-stream", MIMESourceFallback
 	}
 
 	// Try magic signatures first
@@ -121,7 +240,7 @@ func DetectMIMEFromBytes(data []byte) string {
 	if mime != "" {
 		// Special case: distinguish similar formats
 		mime = refineDetection(data, mime)
-		return mime
+		return mime, MIMESourceMagicBytes
 	}
 
 	// Fall back to http.DetectContentType
@@ -131,7 +250,41 @@ func DetectMIMEFromBytes(data []byte) string {
 		contentType = contentType[:idx]
 	}
 
-	return contentType
+	// SVG without an XML declaration sniffs as text, or as HTML after a comment
+	switch contentType {
+	case "text/plain", "text/xml", "text/html":
+		if looksLikeSVG(data) {
+			return "image/svg+xml", MIMESourceMagicBytes
+		}
+	case "application/octet-stream":
+		if looksLikeText(data) {
+			return "text/plain", MIMESourceFallback
+		}
+	}
+
+	return contentType, MIMESourceFallback
+}
+
+// looksLikeText reports whether data is UTF-8 with no control characters
+// other than the ones common in text files: tab, LF, VT, FF, CR and ESC.
+// http.DetectContentType rejects VT; a multi-byte sequence cut off at the
+// end of data is allowed.
+func looksLikeText(data []byte) bool {
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			return len(data) < utf8.UTFMax && !utf8.FullRune(data)
+		}
+		if r < 0x20 || r == 0x7F {
+			switch r {
+			case '\t', '\n', '\v', '\f', '\r', 0x1B:
+			default:
+				return false
+			}
+		}
+		data = data[size:]
+	}
+	return true
 }
 
 // detectByMagic checks data against known magic signatures
@@ -185,6 +338,13 @@ func refineDetection(data []byte, initialMIME string) string {
 		}
 		return initialMIME
 
+	case "application/xml":
+		// SVG is XML with an <svg> root element
+		if looksLikeSVG(data) {
+			return "image/svg+xml"
+		}
+		return initialMIME
+
 	case "video/webm", "video/x-matroska":
 		// Both use EBML header - would need to parse EBML to distinguish
 		// Default to WebM as it's more common on web
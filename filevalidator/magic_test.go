@@ -2,6 +2,7 @@ package filevalidator
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 )
 
@@ -166,6 +167,26 @@ func TestDetectMIMEFromBytes(t *testing.T) {
 			data:     []byte("wOF2"),
 			expected: "font/woff2",
 		},
+		{
+			name:     "WOFF header",
+			data:     fontHeaders[".woff"],
+			expected: "font/woff",
+		},
+		{
+			name:     "WOFF2 header",
+			data:     fontHeaders[".woff2"],
+			expected: "font/woff2",
+		},
+		{
+			name:     "TTF header",
+			data:     fontHeaders[".ttf"],
+			expected: "font/ttf",
+		},
+		{
+			name:     "OTF header",
+			data:     fontHeaders[".otf"],
+			expected: "font/otf",
+		},
 	}
 
 	for _, tt := range tests {
@@ -251,6 +272,15 @@ func TestRefineDetection_MP4Brands(t *testing.T) {
 	}
 }
 
+// fontHeaders holds the first bytes of a minimal font of each format: the
+// signature followed by the flavor or table count fields.
+var fontHeaders = map[string][]byte{
+	".woff":  append([]byte("wOFF"), 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x0A, 0x00, 0x00),
+	".woff2": append([]byte("wOF2"), 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x0A, 0x00, 0x00),
+	".ttf":   {0x00, 0x01, 0x00, 0x00, 0x00, 0x0A, 0x00, 0x80, 0x00, 0x03, 0x00, 0x20},
+	".otf":   append([]byte("OTTO"), 0x00, 0x0A, 0x00, 0x80, 0x00, 0x03, 0x00, 0x20),
+}
+
 func TestIsBinaryMIME(t *testing.T) {
 	tests := []struct {
 		mime     string
@@ -263,6 +293,10 @@ func TestIsBinaryMIME(t *testing.T) {
 		{"text/html", false},
 		{"application/json", false},
 		{"application/xml", false},
+		{"font/woff", true},
+		{"font/woff2", true},
+		{"font/ttf", true},
+		{"font/otf", true},
 	}
 
 	for _, tt := range tests {
@@ -308,6 +342,9 @@ func TestGetMIMECategory(t *testing.T) {
 		{"audio/mpeg", "audio"},
 		{"text/plain", "text"},
 		{"font/woff", "font"},
+		{"font/woff2", "font"},
+		{"font/ttf", "font"},
+		{"font/otf", "font"},
 		{"application/zip", "archive"},
 		{"application/gzip", "archive"},
 		{"application/pdf", "document"},
@@ -332,3 +369,133 @@ func TestDetectMIMEFromBytes_Empty(t *testing.T) {
 		t.Errorf("DetectMIMEFromBytes(empty) = %q, want %q", result, "application/octet-stream")
 	}
 }
+
+func TestDetectMIMEDetailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		wantMIME   string
+		wantSource MIMESource
+		wantExts   []string
+	}{
+		{
+			name:       "PNG",
+			data:       []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0, 0, 0, 0x0D, 'I', 'H', 'D', 'R'},
+			wantMIME:   "image/png",
+			wantSource: MIMESourceMagicBytes,
+			wantExts:   []string{".png"},
+		},
+		{
+			name:       "JPEG",
+			data:       []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F'},
+			wantMIME:   "image/jpeg",
+			wantSource: MIMESourceMagicBytes,
+			wantExts:   []string{".jpeg", ".jpg"},
+		},
+		{
+			name:       "unknown binary",
+			data:       []byte{0x13, 0x37, 0x00, 0xC0, 0xFF, 0xEE, 0x00, 0x01, 0x02},
+			wantMIME:   "application/octet-stream",
+			wantSource: MIMESourceFallback,
+		},
+		{
+			name:       "plain text",
+			data:       []byte("just some words"),
+			wantMIME:   "text/plain",
+			wantSource: MIMESourceFallback,
+			wantExts:   []string{".txt"},
+		},
+		{
+			name:       "empty",
+			data:       nil,
+			wantMIME:   "application/octet-stream",
+			wantSource: MIMESourceFallback,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DetectMIMEDetailed(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("DetectMIMEDetailed() error = %v", err)
+			}
+			if result.MIME != tt.wantMIME {
+				t.Errorf("MIME = %q, want %q", result.MIME, tt.wantMIME)
+			}
+			if result.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", result.Source, tt.wantSource)
+			}
+			if result.Category != GetMIMECategory(result.MIME) {
+				t.Errorf("Category = %q, want GetMIMECategory() = %q", result.Category, GetMIMECategory(result.MIME))
+			}
+			if !reflect.DeepEqual(result.Extensions, tt.wantExts) {
+				t.Errorf("Extensions = %v, want %v", result.Extensions, tt.wantExts)
+			}
+
+			// DetectMIME is a thin wrapper
+			mime, err := DetectMIME(bytes.NewReader(tt.data))
+			if err != nil || mime != result.MIME {
+				t.Errorf("DetectMIME() = %q, %v; want %q", mime, err, result.MIME)
+			}
+		})
+	}
+}
+
+func TestDetectMIMEDetailed_ByteOrderMark(t *testing.T) {
+	// "hi\n" in each encoding
+	tests := []struct {
+		name        string
+		data        []byte
+		wantMIME    string
+		wantCharset string
+	}{
+		{"UTF-8 BOM", []byte("\xEF\xBB\xBFhi\n"), "text/plain", "utf-8"},
+		{"UTF-8 BOM XML", []byte("\xEF\xBB\xBF<?xml version=\"1.0\"?><root/>"), "application/xml", "utf-8"},
+		{"UTF-16LE", []byte{0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0}, "text/plain", "utf-16le"},
+		{"UTF-16BE", []byte{0xFE, 0xFF, 0, 'h', 0, 'i', 0, '\n'}, "text/plain", "utf-16be"},
+		{"UTF-32LE", []byte{0xFF, 0xFE, 0, 0, 'h', 0, 0, 0, 'i', 0, 0, 0}, "text/plain", "utf-32le"},
+		{"UTF-32BE", []byte{0, 0, 0xFE, 0xFF, 0, 0, 0, 'h', 0, 0, 0, 'i'}, "text/plain", "utf-32be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DetectMIMEDetailed(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("DetectMIMEDetailed() error = %v", err)
+			}
+			if result.MIME != tt.wantMIME || result.Charset != tt.wantCharset {
+				t.Errorf("MIME, Charset = %q, %q; want %q, %q", result.MIME, result.Charset, tt.wantMIME, tt.wantCharset)
+			}
+			if want := tt.wantMIME + "; charset=" + tt.wantCharset; result.ContentType() != want {
+				t.Errorf("ContentType() = %q, want %q", result.ContentType(), want)
+			}
+			if IsBinaryMIME(result.ContentType()) || result.Category != "text" && tt.wantMIME == "text/plain" {
+				t.Errorf("%s classified as binary (category %q)", tt.name, result.Category)
+			}
+		})
+	}
+
+	if ct := (MIMEResult{MIME: "image/png"}).ContentType(); ct != "image/png" {
+		t.Errorf("ContentType() without charset = %q, want image/png", ct)
+	}
+}
+
+func TestDetectMIMEFromBytes_ControlCharacters(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"tab CR LF", []byte("a\tb\r\nc\n"), "text/plain"},
+		{"vertical tab and form feed", []byte("page one\v\fpage two\n"), "text/plain"},
+		{"truncated multi-byte rune", []byte("caf\xC3"), "text/plain"},
+		{"NUL", []byte("a\x00b"), "application/octet-stream"},
+		{"other control", []byte("a\x01b\x02"), "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMIMEFromBytes(tt.data); got != tt.want {
+				t.Errorf("DetectMIMEFromBytes(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package filevalidator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// DefaultMediaHeaderBytes is how much of a file MediaDurationValidator reads
+// looking for the declared duration.
+const DefaultMediaHeaderBytes = 1 * MB
+
+// MediaDurationValidator rejects MP4/MOV and WebM/Matroska files whose
+// declared duration exceeds MaxDuration. The duration comes from the MP4 mvhd
+// box or the Matroska Info Duration element; nothing is decoded and at most
+// MaxHeaderBytes are read. Files that do not declare a duration within the
+// header (e.g. MP4 with the moov box at the end) pass this check.
+//
+// Format, when set, runs after the duration check on the same content, so the
+// validator can replace the format validator in a registry.
+type MediaDurationValidator struct {
+	MaxDuration    time.Duration
+	MaxHeaderBytes int64
+	Format         ContentValidator
+}
+
+// NewMediaDurationValidator creates a duration validator that runs format
+// (which may be nil) after the duration check
+func NewMediaDurationValidator(maxDuration time.Duration, format ContentValidator) *MediaDurationValidator {
+	return &MediaDurationValidator{
+		MaxDuration:    maxDuration,
+		MaxHeaderBytes: DefaultMediaHeaderBytes,
+		Format:         format,
+	}
+}
+
+// ValidateContent reads the header and checks the declared duration
+func (v *MediaDurationValidator) ValidateContent(reader io.Reader, size int64) error {
+	limit := v.MaxHeaderBytes
+	if limit <= 0 {
+		limit = DefaultMediaHeaderBytes
+	}
+	header, err := io.ReadAll(io.LimitReader(reader, limit))
+	if err != nil {
+		return NewValidationError(ErrorTypeContent, "failed to read media header")
+	}
+
+	if duration, ok := DetectMediaDuration(header); ok && v.MaxDuration > 0 && duration > v.MaxDuration {
+		return NewValidationError(ErrorTypeContent,
+			fmt.Sprintf("media duration %s exceeds maximum %s", duration, v.MaxDuration))
+	}
+
+	if v.Format == nil {
+		return nil
+	}
+	return v.Format.ValidateContent(io.MultiReader(bytes.NewReader(header), reader), size)
+}
+
+// SupportedMIMETypes returns MIME types this validator handles
+func (v *MediaDurationValidator) SupportedMIMETypes() []string {
+	return []string{
+		"video/mp4",
+		"video/x-m4v",
+		"audio/mp4",
+		"audio/x-m4a",
+		"video/quicktime",
+		"video/webm",
+		"audio/webm",
+		"video/x-matroska",
+		"audio/x-matroska",
+	}
+}
+
+// DetectMediaDuration returns the duration declared in an MP4/MOV mvhd box or
+// a WebM/Matroska Info element found in header. ok is false when header holds
+// neither.
+func DetectMediaDuration(header []byte) (duration time.Duration, ok bool) {
+	if len(header) >= 4 && binary.BigEndian.Uint32(header[:4]) == ebmlIDHeader {
+		return matroskaDuration(header)
+	}
+	return mp4Duration(header)
+}
+
+// --- MP4 ---
+
+// mp4Duration walks the top-level boxes to moov and reads its mvhd box
+func mp4Duration(data []byte) (time.Duration, bool) {
+	moov, ok := findMP4Box(data, "moov")
+	if !ok {
+		return 0, false
+	}
+	mvhd, ok := findMP4Box(moov, "mvhd")
+	if !ok || len(mvhd) < 4 {
+		return 0, false
+	}
+
+	var timescale uint32
+	var units uint64
+	switch mvhd[0] {
+	case 0:
+		// version(1) flags(3) creation(4) modification(4) timescale(4) duration(4)
+		if len(mvhd) < 20 {
+			return 0, false
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[12:16])
+		units = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		if units == math.MaxUint32 {
+			return 0, false // unknown
+		}
+	case 1:
+		// version(1) flags(3) creation(8) modification(8) timescale(4) duration(8)
+		if len(mvhd) < 32 {
+			return 0, false
+		}
+		timescale = binary.BigEndian.Uint32(mvhd[20:24])
+		units = binary.BigEndian.Uint64(mvhd[24:32])
+		if units == math.MaxUint64 {
+			return 0, false // unknown
+		}
+	default:
+		return 0, false
+	}
+	if timescale == 0 {
+		return 0, false
+	}
+	return scaledDuration(float64(units) / float64(timescale) * float64(time.Second)), true
+}
+
+// findMP4Box returns the payload of the first box of the given type in data.
+// A box that runs past the end of data is truncated to what is available.
+func findMP4Box(data []byte, boxType string) ([]byte, bool) {
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[:4]))
+		typ := string(data[4:8])
+		headerLen := uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data)) // box extends to the end of the file
+		case 1:
+			if len(data) < 16 {
+				return nil, false
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			headerLen = 16
+		}
+		if size < headerLen {
+			return nil, false
+		}
+
+		end := min(size, uint64(len(data)))
+		if typ == boxType {
+			return data[headerLen:end], true
+		}
+		if size > uint64(len(data)) {
+			return nil, false // the next box starts beyond the header
+		}
+		data = data[size:]
+	}
+	return nil, false
+}
+
+// --- Matroska / WebM ---
+
+const (
+	ebmlIDHeader        = 0x1A45DFA3
+	ebmlIDSegment       = 0x18538067
+	ebmlIDInfo          = 0x1549A966
+	ebmlIDTimecodeScale = 0x2AD7B1
+	ebmlIDDuration      = 0x4489
+
+	// defaultTimecodeScale is the Matroska default: one tick is 1ms
+	defaultTimecodeScale = 1000000
+)
+
+// matroskaDuration finds Segment > Info and reads Duration and TimecodeScale
+func matroskaDuration(data []byte) (time.Duration, bool) {
+	for len(data) > 0 {
+		id, size, n, ok := readEBMLElement(data)
+		if !ok {
+			return 0, false
+		}
+		data = data[n:]
+		switch id {
+		case ebmlIDSegment:
+			// Descend; an unknown-size Segment runs to the end
+			return matroskaSegmentDuration(data[:min(size, uint64(len(data)))])
+		default:
+			if size > uint64(len(data)) {
+				return 0, false
+			}
+			data = data[size:]
+		}
+	}
+	return 0, false
+}
+
+func matroskaSegmentDuration(data []byte) (time.Duration, bool) {
+	for len(data) > 0 {
+		id, size, n, ok := readEBMLElement(data)
+		if !ok || size > uint64(len(data)-n) {
+			return 0, false
+		}
+		body := data[n : n+int(size)]
+		if id == ebmlIDInfo {
+			return matroskaInfoDuration(body)
+		}
+		data = data[n+int(size):]
+	}
+	return 0, false
+}
+
+func matroskaInfoDuration(info []byte) (time.Duration, bool) {
+	scale := uint64(defaultTimecodeScale)
+	var ticks float64
+	found := false
+	for len(info) > 0 {
+		id, size, n, ok := readEBMLElement(info)
+		if !ok || size > uint64(len(info)-n) {
+			return 0, false
+		}
+		body := info[n : n+int(size)]
+		switch id {
+		case ebmlIDTimecodeScale:
+			if len(body) == 0 || len(body) > 8 {
+				return 0, false
+			}
+			scale = 0
+			for _, b := range body {
+				scale = scale<<8 | uint64(b)
+			}
+		case ebmlIDDuration:
+			switch len(body) {
+			case 4:
+				ticks = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+			case 8:
+				ticks = math.Float64frombits(binary.BigEndian.Uint64(body))
+			default:
+				return 0, false
+			}
+			found = true
+		}
+		info = info[n+int(size):]
+	}
+	if !found || ticks < 0 || math.IsNaN(ticks) {
+		return 0, false
+	}
+	return scaledDuration(ticks * float64(scale)), true
+}
+
+// readEBMLElement parses an element ID and data size. n is the length of both;
+// an unknown size (all ones) is returned as math.MaxUint64.
+func readEBMLElement(data []byte) (id uint64, size uint64, n int, ok bool) {
+	id, idLen, ok := readEBMLVint(data, true)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	size, sizeLen, ok := readEBMLVint(data[idLen:], false)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return id, size, idLen + sizeLen, true
+}
+
+// readEBMLVint reads a variable-length integer. IDs keep their length marker
+// bit; sizes drop it.
+func readEBMLVint(data []byte, keepMarker bool) (uint64, int, bool) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0, false
+	}
+	length := 1
+	for mask := byte(0x80); data[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if length > 8 || len(data) < length {
+		return 0, 0, false
+	}
+
+	value := uint64(data[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+	allOnes := value == uint64(0xFF>>length)
+	for _, b := range data[1:length] {
+		value = value<<8 | uint64(b)
+		allOnes = allOnes && b == 0xFF
+	}
+	if !keepMarker && allOnes {
+		return math.MaxUint64, length, true
+	}
+	return value, length, true
+}
+
+// scaledDuration converts nanoseconds to a Duration, saturating on overflow
+func scaledDuration(ns float64) time.Duration {
+	if ns >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(ns)
+}
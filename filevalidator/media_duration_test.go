@@ -0,0 +1,152 @@
+package filevalidator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mp4Box builds an MP4 box with a 32-bit size.
+func mp4Box(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box, uint32(8+len(body)))
+	copy(box[4:], typ)
+	return append(box, body...)
+}
+
+// mvhdV0 builds a version 0 mvhd payload.
+func mvhdV0(timescale, duration uint32) []byte {
+	p := make([]byte, 100)
+	binary.BigEndian.PutUint32(p[12:], timescale)
+	binary.BigEndian.PutUint32(p[16:], duration)
+	return p
+}
+
+// mvhdV1 builds a version 1 mvhd payload with 64-bit times and duration.
+func mvhdV1(timescale uint32, duration uint64) []byte {
+	p := make([]byte, 112)
+	p[0] = 1
+	binary.BigEndian.PutUint32(p[20:], timescale)
+	binary.BigEndian.PutUint64(p[24:], duration)
+	return p
+}
+
+// createMP4 builds ftyp + moov(mvhd) with the given mvhd payload.
+func createMP4(mvhd []byte) []byte {
+	ftyp := mp4Box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41"))
+	return append(ftyp, mp4Box("moov", mp4Box("mvhd", mvhd))...)
+}
+
+// ebmlElement builds an EBML element with a one-byte size (payload < 127 bytes).
+func ebmlElement(id []byte, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	el := append(append([]byte(nil), id...), 0x80|byte(len(body)))
+	return append(el, body...)
+}
+
+// createWebM builds an EBML header and a Segment > Info with the given
+// duration in milliseconds (the default TimecodeScale).
+func createWebM(durationMs float64) []byte {
+	header := ebmlElement([]byte{0x1A, 0x45, 0xDF, 0xA3},
+		ebmlElement([]byte{0x42, 0x82}, []byte("webm")))
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(durationMs))
+	info := ebmlElement([]byte{0x15, 0x49, 0xA9, 0x66},
+		ebmlElement([]byte{0x2A, 0xD7, 0xB1}, []byte{0x0F, 0x42, 0x40}), // 1,000,000 ns
+		ebmlElement([]byte{0x44, 0x89}, duration),
+	)
+	// Unknown-size Segment, as live-streamed WebM writes it
+	segment := append([]byte{0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, info...)
+	return append(header, segment...)
+}
+
+func TestDetectMediaDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   time.Duration
+		wantOK bool
+	}{
+		{"mp4 v0", createMP4(mvhdV0(1000, 90_500)), 90500 * time.Millisecond, true},
+		{"mp4 v1", createMP4(mvhdV1(600, 600*3*3600)), 3 * time.Hour, true},
+		{"mp4 unknown duration", createMP4(mvhdV0(1000, math.MaxUint32)), 0, false},
+		{"mp4 zero timescale", createMP4(mvhdV0(0, 1000)), 0, false},
+		{"mp4 moov after mdat", append(mp4Box("ftyp", []byte("isom")), 0, 0, 0x10, 0, 'm', 'd', 'a', 't'), 0, false},
+		{"webm", createWebM(12_345), 12345 * time.Millisecond, true},
+		{"not media", []byte("plain text"), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectMediaDuration(tt.header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("DetectMediaDuration() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMediaDurationValidator_ValidateContent(t *testing.T) {
+	v := NewMediaDurationValidator(time.Hour, DefaultMP4Validator())
+
+	short := createMP4(mvhdV0(1000, 60_000))
+	if err := v.ValidateContent(bytes.NewReader(short), int64(len(short))); err != nil {
+		t.Errorf("1m MP4 rejected: %v", err)
+	}
+
+	long := createMP4(mvhdV1(90000, 90000*2*3600))
+	err := v.ValidateContent(bytes.NewReader(long), int64(len(long)))
+	if !IsErrorOfType(err, ErrorTypeContent) || !strings.Contains(err.Error(), "2h0m0s") {
+		t.Errorf("2h MP4 = %v, want content error naming 2h0m0s", err)
+	}
+
+	webm := createWebM(float64(3 * time.Hour / time.Millisecond))
+	if err := v.ValidateContent(bytes.NewReader(webm), int64(len(webm))); !IsErrorOfType(err, ErrorTypeContent) {
+		t.Errorf("3h WebM = %v, want content error", err)
+	}
+
+	// Only the header is read
+	r := bytes.NewReader(append(short, make([]byte, 1024)...))
+	if err := (&MediaDurationValidator{MaxDuration: time.Hour, MaxHeaderBytes: 64}).ValidateContent(r, r.Size()); err != nil {
+		t.Errorf("ValidateContent: %v", err)
+	}
+	if read := r.Size() - int64(r.Len()); read != 64 {
+		t.Errorf("read %d bytes, want 64", read)
+	}
+}
+
+func TestBuilder_MaxMediaDuration(t *testing.T) {
+	validator := ForVideo().MaxMediaDuration(time.Hour).MaxMediaDuration(10 * time.Minute).Build()
+
+	if err := validator.ValidateBytes(createMP4(mvhdV0(1000, 5*60_000)), "clip.mp4"); err != nil {
+		t.Errorf("5m MP4 rejected: %v", err)
+	}
+	err := validator.ValidateBytes(createMP4(mvhdV0(1000, 30*60_000)), "talk.mp4")
+	if !IsErrorOfType(err, ErrorTypeContent) || !strings.Contains(err.Error(), "30m0s") {
+		t.Errorf("30m MP4 = %v, want content error naming 30m0s", err)
+	}
+
+	// The format validator still runs
+	reg := validator.constraints.ContentValidatorRegistry
+	if dv, ok := reg.GetValidator("video/mp4").(*MediaDurationValidator); !ok || dv.MaxDuration != 10*time.Minute {
+		t.Errorf("video/mp4 validator = %T, want a single MediaDurationValidator with the last limit", reg.GetValidator("video/mp4"))
+	} else if _, ok := dv.Format.(*MP4Validator); !ok {
+		t.Errorf("wrapped format validator = %T, want *MP4Validator", dv.Format)
+	}
+
+	// Only the media types become mandatory
+	c := validator.GetConstraints()
+	if c.RequireContentValidation || !slices.Contains(c.RequireContentValidationFor, "video/mp4") {
+		t.Errorf("RequireContentValidation = %v, For = %v; want only the media types required", c.RequireContentValidation, c.RequireContentValidationFor)
+	}
+
+	// A shared registry is cloned, not mutated
+	NewBuilder().WithRegistry(GetDefaultRegistry()).MaxMediaDuration(time.Minute)
+	if _, ok := GetDefaultRegistry().GetValidator("video/mp4").(*MediaDurationValidator); ok {
+		t.Error("MaxMediaDuration mutated the shared registry")
+	}
+}
@@ -1,5 +1,7 @@
 package filevalidator
 
+import "sort"
+
 // MediaTypeGroup defines a categorization of MIME types
 type MediaTypeGroup string
 
@@ -9,6 +11,7 @@ const (
 	AllowAllAudio     MediaTypeGroup = "audio/*"
 	AllowAllVideo     MediaTypeGroup = "video/*"
 	AllowAllText      MediaTypeGroup = "text/*"
+	AllowAllFonts     MediaTypeGroup = "font/*"
 	AllowAll          MediaTypeGroup = "*/*"
 )
 
@@ -69,6 +72,12 @@ var mediaTypeGroups = map[MediaTypeGroup][]string{
 		"text/xml",
 		"text/markdown",
 	},
+	AllowAllFonts: {
+		"font/woff",
+		"font/woff2",
+		"font/ttf",
+		"font/otf",
+	},
 }
 
 // Common extension to MIME type mapping
@@ -123,6 +132,32 @@ var extensionToMimeType = map[string]string{
 	".xml":      "text/xml",
 	".md":       "text/markdown",
 	".markdown": "text/markdown",
+
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+}
+
+// DefaultBenignMIMEMismatches returns the claimed-to-detected MIME pairs that are
+// expected for legitimate files. Text formats without magic bytes are detected as
+// plain text, and some text files begin with bytes that look like JSON or XML.
+func DefaultBenignMIMEMismatches() map[string][]string {
+	return map[string][]string{
+		"text/plain":    {"application/json", "application/xml", "text/xml"},
+		"text/csv":      {"text/plain"},
+		"text/markdown": {"text/plain"},
+		"text/css":      {"text/plain"},
+		"text/xml":      {"application/xml"},
+		"text/rtf":      {"application/json", "text/plain"},
+		"image/svg+xml": {"application/xml", "text/xml", "text/plain"},
+		"image/heif":    {"image/heic"},
+		"audio/x-midi":  {"audio/midi"},
+
+		"application/msword":            {"application/x-ole-storage"},
+		"application/vnd.ms-excel":      {"application/x-ole-storage"},
+		"application/vnd.ms-powerpoint": {"application/x-ole-storage"},
+	}
 }
 
 // MIMETypeForExtension returns the MIME type for a given file extension
@@ -131,6 +166,19 @@ func MIMETypeForExtension(ext string) string {
 	return extensionToMimeType[ext]
 }
 
+// ExtensionsForMIMEType returns the known extensions for a MIME type, sorted.
+// Returns nil if no extension maps to the type.
+func ExtensionsForMIMEType(mimeType string) []string {
+	var exts []string
+	for ext, mime := range extensionToMimeType {
+		if mime == mimeType {
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
 // ExpandAcceptedTypes takes a slice of accepted types (which can include MediaTypeGroups)
 // and returns a slice with all specific MIME types
 func ExpandAcceptedTypes(acceptedTypes []string) []string {
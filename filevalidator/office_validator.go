@@ -3,12 +3,19 @@ package filevalidator
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"path"
+	"strings"
+	"unicode/utf16"
 )
 
-// OfficeValidator validates Microsoft Office Open XML formats (DOCX, XLSX, PPTX).
-// These are ZIP-based formats with specific required files inside.
+// OfficeValidator validates Microsoft Office Open XML formats (DOCX, XLSX, PPTX)
+// and legacy binary Office formats (DOC, XLS, PPT).
+// Open XML formats are ZIP-based with specific required files inside; legacy
+// formats are OLE compound files. Macro detection reads only the ZIP central
+// directory or the OLE directory, never the document content.
 type OfficeValidator struct {
 	// Shared settings (inherits zip bomb protection)
 	MaxSize             int64
@@ -40,6 +47,9 @@ func (v *OfficeValidator) ValidateContent(reader io.Reader, size int64) error {
 
 	// Try to use ReaderAt for memory-efficient validation
 	if readerAt, ok := reader.(io.ReaderAt); ok {
+		if isOLEFile(readerAt) {
+			return v.validateOLE(readerAt, size)
+		}
 		return v.validateWithReaderAt(readerAt, size)
 	}
 
@@ -54,6 +64,9 @@ func (v *OfficeValidator) ValidateContent(reader io.Reader, size int64) error {
 		return NewValidationError(ErrorTypeContent, "failed to read file content")
 	}
 
+	if isOLEFile(bytes.NewReader(data)) {
+		return v.validateOLE(bytes.NewReader(data), int64(len(data)))
+	}
 	return v.validateWithReaderAt(bytes.NewReader(data), int64(len(data)))
 }
 
@@ -68,7 +81,7 @@ func (v *OfficeValidator) validateWithReaderAt(reader io.ReaderAt, size int64) e
 		fileCount         int
 		hasContentTypes   bool
 		hasRels           bool
-		hasMacros         bool
+		macroFile         string
 		docType           string
 	)
 
@@ -109,8 +122,8 @@ func (v *OfficeValidator) validateWithReaderAt(reader io.ReaderAt, size int64) e
 		}
 
 		// Check for macros (VBA)
-		if v.isMacroFile(file.Name) {
-			hasMacros = true
+		if macroFile == "" && v.isMacroFile(file.Name) {
+			macroFile = file.Name
 		}
 	}
 
@@ -123,8 +136,9 @@ func (v *OfficeValidator) validateWithReaderAt(reader io.ReaderAt, size int64) e
 	}
 
 	// Check macros policy
-	if hasMacros && !v.AllowMacros {
-		return NewValidationError(ErrorTypeContent, "macro-enabled documents are not allowed")
+	if macroFile != "" && !v.AllowMacros {
+		return NewValidationError(ErrorTypeContent,
+			fmt.Sprintf("macro-enabled documents are not allowed: contains %s", macroFile))
 	}
 
 	return nil
@@ -143,23 +157,161 @@ func (v *OfficeValidator) detectDocType(path string) string {
 	return ""
 }
 
-// isMacroFile checks if a file path indicates VBA macros
-func (v *OfficeValidator) isMacroFile(path string) bool {
-	macroIndicators := []string{
-		"vbaProject.bin",
-		"vbaData.xml",
-		"word/vbaProject.bin",
-		"xl/vbaProject.bin",
-		"ppt/vbaProject.bin",
-	}
-	for _, indicator := range macroIndicators {
-		if path == indicator {
+// isMacroFile checks if a file path indicates VBA macros.
+// Besides the VBA project itself, embedded macro-enabled or legacy binary
+// documents are flagged: their macros cannot be seen without extracting them.
+func (v *OfficeValidator) isMacroFile(name string) bool {
+	base := strings.ToLower(path.Base(name))
+	switch base {
+	case "vbaproject.bin", "vbadata.xml", "vbaprojectsignature.bin":
+		return true
+	}
+
+	if strings.Contains(strings.ToLower(name), "/embeddings/") {
+		switch path.Ext(base) {
+		case ".docm", ".dotm", ".xlsm", ".xltm", ".xlam", ".pptm", ".potm", ".ppsm",
+			".doc", ".dot", ".xls", ".xlt", ".ppt", ".pot", ".pps":
 			return true
 		}
 	}
 	return false
 }
 
+// oleSignature is the magic number of OLE compound files (DOC, XLS, PPT)
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// oleMacroStorages are OLE directory entry names that hold VBA projects:
+// "Macros" in Word, "_VBA_PROJECT_CUR" in Excel, and "VBA"/"_VBA_PROJECT" within them.
+var oleMacroStorages = map[string]bool{
+	"Macros":           true,
+	"_VBA_PROJECT_CUR": true,
+	"VBA":              true,
+	"_VBA_PROJECT":     true,
+}
+
+// OLE sector IDs with special meaning
+const (
+	oleMaxRegularSector = 0xFFFFFFFA
+	oleEndOfChain       = 0xFFFFFFFE
+)
+
+// isOLEFile checks for the OLE compound file signature
+func isOLEFile(reader io.ReaderAt) bool {
+	header := make([]byte, len(oleSignature))
+	if _, err := reader.ReadAt(header, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(header, oleSignature)
+}
+
+// validateOLE checks a legacy binary Office document for macro storages
+func (v *OfficeValidator) validateOLE(reader io.ReaderAt, size int64) error {
+	storage, err := findOLEMacroStorage(reader, size)
+	if err != nil {
+		return NewValidationError(ErrorTypeContent, fmt.Sprintf("invalid OLE structure: %v", err))
+	}
+	if storage != "" && !v.AllowMacros {
+		return NewValidationError(ErrorTypeContent,
+			fmt.Sprintf("macro-enabled documents are not allowed: contains OLE storage %s", storage))
+	}
+	return nil
+}
+
+// findOLEMacroStorage walks the directory sector chain of an OLE compound file
+// and returns the name of the first entry that holds a VBA project.
+// Only the header, the FAT sectors on the chain, and the directory are read.
+func findOLEMacroStorage(reader io.ReaderAt, size int64) (string, error) {
+	header := make([]byte, 512)
+	if _, err := reader.ReadAt(header, 0); err != nil {
+		return "", fmt.Errorf("short header")
+	}
+
+	sectorShift := binary.LittleEndian.Uint16(header[0x1E:])
+	if sectorShift != 9 && sectorShift != 12 {
+		return "", fmt.Errorf("unsupported sector size 2^%d", sectorShift)
+	}
+	sectorSize := int64(1) << sectorShift
+	entriesPerFATSector := uint32(sectorSize / 4)
+
+	// The header holds the first 109 FAT sector locations (DIFAT); larger
+	// files continue the DIFAT in a sector chain
+	numFATSectors := binary.LittleEndian.Uint32(header[0x2C:])
+	fatSectors := make([]uint32, 0, min(numFATSectors, 109))
+	for i := uint32(0); i < 109 && i < numFATSectors; i++ {
+		fatSectors = append(fatSectors, binary.LittleEndian.Uint32(header[0x4C+4*i:]))
+	}
+	difatSector := binary.LittleEndian.Uint32(header[0x44:])
+	maxSectors := size / sectorSize
+	buf := make([]byte, sectorSize)
+	for hops := int64(0); uint32(len(fatSectors)) < numFATSectors && difatSector <= oleMaxRegularSector; hops++ {
+		if hops > maxSectors {
+			return "", fmt.Errorf("DIFAT chain loop")
+		}
+		if _, err := reader.ReadAt(buf, (int64(difatSector)+1)*sectorSize); err != nil {
+			return "", fmt.Errorf("truncated DIFAT sector")
+		}
+		for i := uint32(0); i < entriesPerFATSector-1 && uint32(len(fatSectors)) < numFATSectors; i++ {
+			fatSectors = append(fatSectors, binary.LittleEndian.Uint32(buf[4*i:]))
+		}
+		difatSector = binary.LittleEndian.Uint32(buf[sectorSize-4:])
+	}
+
+	nextSector := func(sector uint32) (uint32, error) {
+		idx := sector / entriesPerFATSector
+		if idx >= uint32(len(fatSectors)) {
+			return 0, fmt.Errorf("sector %d outside FAT", sector)
+		}
+		entry := make([]byte, 4)
+		offset := (int64(fatSectors[idx])+1)*sectorSize + int64(sector%entriesPerFATSector)*4
+		if _, err := reader.ReadAt(entry, offset); err != nil {
+			return 0, fmt.Errorf("truncated FAT sector")
+		}
+		return binary.LittleEndian.Uint32(entry), nil
+	}
+
+	sector := binary.LittleEndian.Uint32(header[0x30:])
+	for hops := int64(0); sector != oleEndOfChain; hops++ {
+		if sector > oleMaxRegularSector || hops > maxSectors {
+			return "", fmt.Errorf("invalid directory chain")
+		}
+		if _, err := reader.ReadAt(buf, (int64(sector)+1)*sectorSize); err != nil {
+			return "", fmt.Errorf("truncated directory sector")
+		}
+
+		// Each directory entry is 128 bytes: UTF-16LE name, name length, object type
+		for off := int64(0); off+128 <= sectorSize; off += 128 {
+			entry := buf[off : off+128]
+			if entry[66] == 0 { // unused entry
+				continue
+			}
+			if name := oleEntryName(entry); oleMacroStorages[name] {
+				return name, nil
+			}
+		}
+
+		next, err := nextSector(sector)
+		if err != nil {
+			return "", err
+		}
+		sector = next
+	}
+
+	return "", nil
+}
+
+// oleEntryName decodes the UTF-16LE name of an OLE directory entry
+func oleEntryName(entry []byte) string {
+	nameLen := int(binary.LittleEndian.Uint16(entry[64:]))
+	if nameLen < 2 || nameLen > 64 {
+		return ""
+	}
+	units := make([]uint16, 0, nameLen/2-1)
+	for i := 0; i+1 < nameLen-2; i += 2 {
+		units = append(units, binary.LittleEndian.Uint16(entry[i:]))
+	}
+	return string(utf16.Decode(units))
+}
+
 // SupportedMIMETypes returns MIME types this validator handles
 func (v *OfficeValidator) SupportedMIMETypes() []string {
 	types := []string{
@@ -171,6 +323,14 @@ func (v *OfficeValidator) SupportedMIMETypes() []string {
 		"application/vnd.openxmlformats-officedocument.presentationml.presentation", // .pptx
 	}
 
+	// Legacy binary formats are detected as generic OLE compound files
+	types = append(types,
+		"application/x-ole-storage",
+		"application/msword",            // .doc
+		"application/vnd.ms-excel",      // .xls
+		"application/vnd.ms-powerpoint", // .ppt
+	)
+
 	if v.AllowMacros {
 		types = append(types,
 			"application/vnd.ms-word.document.macroEnabled.12",           // .docm
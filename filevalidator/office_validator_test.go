@@ -3,7 +3,10 @@ package filevalidator
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/binary"
+	"slices"
 	"testing"
+	"unicode/utf16"
 )
 
 func TestOfficeValidator_ValidateContent(t *testing.T) {
@@ -186,3 +189,184 @@ func createOfficeZip(files map[string]string) []byte {
 	w.Close()
 	return buf.Bytes()
 }
+
+func TestOfficeValidator_MacroArtifacts(t *testing.T) {
+	base := map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0"?>`,
+		"_rels/.rels":         `<?xml version="1.0"?>`,
+		"xl/workbook.xml":     `<workbook/>`,
+	}
+
+	tests := []struct {
+		name     string
+		artifact string
+	}{
+		{"VBA project", "xl/vbaProject.bin"},
+		{"VBA project with unusual case", "xl/VBAProject.BIN"},
+		{"embedded macro-enabled workbook", "xl/embeddings/Microsoft_Excel_Macro-Enabled_Worksheet.xlsm"},
+		{"embedded legacy document", "xl/embeddings/Microsoft_Word_97_-_2003_Document.doc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{tt.artifact: "payload"}
+			for name, content := range base {
+				files[name] = content
+			}
+			data := createOfficeZip(files)
+
+			err := DefaultOfficeValidator().ValidateContent(bytes.NewReader(data), int64(len(data)))
+			if !IsErrorOfType(err, ErrorTypeContent) {
+				t.Fatalf("expected ErrorTypeContent, got %v", err)
+			}
+			if !bytes.Contains([]byte(err.Error()), []byte(tt.artifact)) {
+				t.Errorf("expected error naming %s, got %q", tt.artifact, err.Error())
+			}
+		})
+	}
+
+	// Embedded modern documents and images are not macro artifacts
+	files := map[string]string{
+		"xl/embeddings/Microsoft_Word_Document.docx": "payload",
+		"xl/media/image1.png":                        "payload",
+	}
+	for name, content := range base {
+		files[name] = content
+	}
+	data := createOfficeZip(files)
+	if err := DefaultOfficeValidator().ValidateContent(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Errorf("unexpected error for clean embeddings: %v", err)
+	}
+}
+
+func TestOfficeValidator_LegacyOLE(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		wantErr string
+	}{
+		{"clean doc", []string{"WordDocument", "1Table", "\x05SummaryInformation"}, ""},
+		{"doc with macros", []string{"WordDocument", "1Table", "Macros"}, "contains OLE storage Macros"},
+		{"xls with macros", []string{"Workbook", "_VBA_PROJECT_CUR"}, "contains OLE storage _VBA_PROJECT_CUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := createOLEFile(tt.entries...)
+			err := DefaultOfficeValidator().ValidateContent(bytes.NewReader(data), int64(len(data)))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !IsErrorOfType(err, ErrorTypeContent) || !bytes.Contains([]byte(err.Error()), []byte(tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if got := DetectMIMEFromBytes(createOLEFile("WordDocument")); got != "application/x-ole-storage" {
+		t.Errorf("DetectMIMEFromBytes() = %q, want application/x-ole-storage", got)
+	}
+
+	truncated := createOLEFile("WordDocument")[:600]
+	if err := DefaultOfficeValidator().ValidateContent(bytes.NewReader(truncated), int64(len(truncated))); err == nil {
+		t.Error("expected error for truncated OLE file")
+	}
+}
+
+func TestBuilder_RejectMacros(t *testing.T) {
+	docx := func(extra map[string]string) []byte {
+		files := map[string]string{
+			"[Content_Types].xml": `<?xml version="1.0"?>`,
+			"_rels/.rels":         `<?xml version="1.0"?>`,
+			"word/document.xml":   `<document/>`,
+		}
+		for name, content := range extra {
+			files[name] = content
+		}
+		return createOfficeZip(files)
+	}
+
+	validator := Empty().AcceptDocuments().Accept("application/x-ole-storage").RejectMacros().Build()
+
+	if err := validator.ValidateBytes(docx(nil), "report.docx"); err != nil {
+		t.Errorf("clean docx rejected: %v", err)
+	}
+
+	err := validator.ValidateBytes(docx(map[string]string{"word/vbaProject.bin": "VBA"}), "report.docx")
+	if !IsErrorOfType(err, ErrorTypeContent) || !bytes.Contains([]byte(err.Error()), []byte("word/vbaProject.bin")) {
+		t.Errorf("expected macro-enabled docx to be rejected naming vbaProject.bin, got %v", err)
+	}
+
+	err = validator.ValidateBytes(createOLEFile("WordDocument", "Macros"), "report.doc")
+	if !IsErrorOfType(err, ErrorTypeContent) {
+		t.Errorf("expected macro-enabled doc to be rejected, got %v", err)
+	}
+
+	// Only the Office types become mandatory
+	c := validator.GetConstraints()
+	if c.RequireContentValidation || !slices.Contains(c.RequireContentValidationFor, "application/vnd.openxmlformats-officedocument.wordprocessingml.document") {
+		t.Errorf("RequireContentValidation = %v, For = %v; want only the Office types required", c.RequireContentValidation, c.RequireContentValidationFor)
+	}
+
+	// A shared registry is cloned, not mutated
+	NewBuilder().WithRegistry(GetDefaultRegistry()).RejectMacros()
+	if GetDefaultRegistry().HasValidator("application/vnd.ms-word.document.macroEnabled.12") {
+		t.Error("RejectMacros mutated the shared registry")
+	}
+}
+
+// createOLEFile builds a minimal OLE compound file (512-byte sectors) with a root
+// entry followed by the given directory entries: sector 0 holds the FAT and
+// sector 1 the directory.
+func createOLEFile(entries ...string) []byte {
+	const sectorSize = 512
+	data := make([]byte, 3*sectorSize)
+	le := binary.LittleEndian
+
+	header := data[:sectorSize]
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	le.PutUint16(header[0x18:], 0x3E)
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)          // one FAT sector
+	le.PutUint32(header[0x30:], 1)          // directory starts at sector 1
+	le.PutUint32(header[0x38:], 4096)       // mini stream cutoff
+	le.PutUint32(header[0x3C:], 0xFFFFFFFE) // no mini FAT
+	le.PutUint32(header[0x44:], 0xFFFFFFFE) // no DIFAT sectors
+	for i := 0; i < 109; i++ {
+		le.PutUint32(header[0x4C+4*i:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[0x4C:], 0) // FAT is sector 0
+
+	fat := data[sectorSize : 2*sectorSize]
+	for i := 0; i < sectorSize/4; i++ {
+		le.PutUint32(fat[4*i:], 0xFFFFFFFF)
+	}
+	le.PutUint32(fat[0:], 0xFFFFFFFD) // FAT sector
+	le.PutUint32(fat[4:], 0xFFFFFFFE) // directory: end of chain
+
+	dir := data[2*sectorSize:]
+	names := append([]string{"Root Entry"}, entries...)
+	for i, name := range names {
+		entry := dir[i*128 : (i+1)*128]
+		units := utf16.Encode([]rune(name))
+		for j, u := range units {
+			le.PutUint16(entry[2*j:], u)
+		}
+		le.PutUint16(entry[64:], uint16(2*(len(units)+1)))
+		switch {
+		case i == 0:
+			entry[66] = 5 // root storage
+		case name == "Macros" || name == "_VBA_PROJECT_CUR":
+			entry[66] = 1 // storage
+		default:
+			entry[66] = 2 // stream
+		}
+	}
+	return data
+}
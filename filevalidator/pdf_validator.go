@@ -12,6 +12,11 @@ import (
 // For malware detection, integrate with ClamAV or similar.
 type PDFValidator struct {
 	MaxSize int64
+
+	// RejectEncryptedPDF rejects PDFs whose trailer references an /Encrypt dictionary.
+	// Encrypted PDFs often can't be processed downstream (indexing, previews).
+	// Detection is header/trailer-only; no decryption is attempted.
+	RejectEncryptedPDF bool
 }
 
 // DefaultPDFValidator creates a PDF validator with sensible defaults
@@ -77,6 +82,10 @@ func (v *PDFValidator) validateWithSeeker(reader io.ReadSeeker, size int64) erro
 		return NewValidationError(ErrorTypeContent, "invalid PDF trailer")
 	}
 
+	if v.RejectEncryptedPDF && (v.hasEncryptDictionary(header) || v.hasEncryptDictionary(trailer)) {
+		return NewValidationError(ErrorTypeContent, "encrypted PDF is not allowed")
+	}
+
 	return nil
 }
 
@@ -97,6 +106,10 @@ func (v *PDFValidator) validateSmallFile(reader io.Reader, size int64) error {
 		return NewValidationError(ErrorTypeContent, "invalid PDF trailer")
 	}
 
+	if v.RejectEncryptedPDF && v.hasEncryptDictionary(buf.Bytes()) {
+		return NewValidationError(ErrorTypeContent, "encrypted PDF is not allowed")
+	}
+
 	return nil
 }
 
@@ -129,3 +142,31 @@ func (v *PDFValidator) hasValidPDFTrailer(data []byte) bool {
 
 	return bytes.Contains(data, []byte("%%EOF"))
 }
+
+// hasEncryptDictionary checks if the data references an /Encrypt dictionary.
+// The trailer of an encrypted PDF contains "/Encrypt <obj> <gen> R" or an inline dictionary.
+func (v *PDFValidator) hasEncryptDictionary(data []byte) bool {
+	idx := bytes.Index(data, []byte("/Encrypt"))
+	for idx >= 0 {
+		// Skip keys that merely start with "Encrypt" (e.g. /EncryptMetadata inside the dictionary)
+		end := idx + len("/Encrypt")
+		if end >= len(data) || !isPDFNameChar(data[end]) {
+			return true
+		}
+		next := bytes.Index(data[end:], []byte("/Encrypt"))
+		if next < 0 {
+			return false
+		}
+		idx = end + next
+	}
+	return false
+}
+
+// isPDFNameChar reports whether c can continue a PDF name token.
+func isPDFNameChar(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '/', '<', '>', '[', ']', '(', ')', '{', '}', '%':
+		return false
+	}
+	return true
+}
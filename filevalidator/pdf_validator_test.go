@@ -118,3 +118,45 @@ func TestPDFValidator_SeekableReader(t *testing.T) {
 		t.Errorf("Expected no error for valid PDF, got: %v", err)
 	}
 }
+
+func TestPDFValidator_RejectEncryptedPDF(t *testing.T) {
+	encrypted := []byte("%PDF-1.6\n1 0 obj\n<< /Type /Catalog >>\nendobj\n" +
+		"trailer\n<< /Size 5 /Root 1 0 R /Encrypt 4 0 R /ID [<ab><cd>] >>\nstartxref\n123\n%%EOF")
+	normal := []byte("%PDF-1.6\n1 0 obj\n<< /Type /Catalog >>\nendobj\n" +
+		"trailer\n<< /Size 5 /Root 1 0 R >>\nstartxref\n123\n%%EOF")
+	metadataOnly := []byte("%PDF-1.6\n<< /EncryptMetadata false >>\ntrailer\n<< /Root 1 0 R >>\n%%EOF")
+
+	tests := []struct {
+		name      string
+		reject    bool
+		data      []byte
+		wantError bool
+	}{
+		{name: "encrypted rejected", reject: true, data: encrypted, wantError: true},
+		{name: "normal accepted", reject: true, data: normal, wantError: false},
+		{name: "EncryptMetadata key is not an Encrypt reference", reject: true, data: metadataOnly, wantError: false},
+		{name: "encrypted allowed when option disabled", reject: false, data: encrypted, wantError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := DefaultPDFValidator()
+			validator.RejectEncryptedPDF = tt.reject
+
+			// Seekable path
+			err := validator.ValidateContent(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if (err != nil) != tt.wantError {
+				t.Errorf("seekable: wantError=%v, got: %v", tt.wantError, err)
+			}
+			if err != nil && !IsErrorOfType(err, ErrorTypeContent) {
+				t.Errorf("Expected content error, got: %v", err)
+			}
+
+			// Non-seekable path
+			err = validator.ValidateContent(bytes.NewBufferString(string(tt.data)), int64(len(tt.data)))
+			if (err != nil) != tt.wantError {
+				t.Errorf("non-seekable: wantError=%v, got: %v", tt.wantError, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package filevalidator
 
-import "sync"
+import (
+	"slices"
+	"sync"
+)
 
 // DefaultRegistry returns a registry with all built-in validators registered
 // This is efficient - validators are just struct pointers in a map
@@ -154,10 +157,10 @@ func (r *ContentValidatorRegistry) RegisteredMIMETypes() []string {
 
 // HasValidator returns true if a validator is registered for the given MIME type
 func (r *ContentValidatorRegistry) HasValidator(mimeType string) bool {
-	return r.validators[mimeType] != nil
+	return len(r.validators[mimeType]) > 0
 }
 
-// Count returns the number of registered validators
+// Count returns the number of MIME types with registered validators
 func (r *ContentValidatorRegistry) Count() int {
 	return len(r.validators)
 }
@@ -169,14 +172,14 @@ func (r *ContentValidatorRegistry) Unregister(mimeType string) {
 
 // Clear removes all registered validators
 func (r *ContentValidatorRegistry) Clear() {
-	r.validators = make(map[string]ContentValidator)
+	r.validators = make(map[string][]ContentValidator)
 }
 
 // Clone creates a copy of the registry
 func (r *ContentValidatorRegistry) Clone() *ContentValidatorRegistry {
 	clone := NewContentValidatorRegistry()
-	for mime, validator := range r.validators {
-		clone.validators[mime] = validator
+	for mime, chain := range r.validators {
+		clone.validators[mime] = slices.Clone(chain)
 	}
 	return clone
 }
@@ -234,9 +237,7 @@ func DocumentOnlyRegistry() *ContentValidatorRegistry {
 
 // MediaOnlyRegistry returns a registry with only media validators
 func MediaOnlyRegistry() *ContentValidatorRegistry {
-	registry := NewContentValidatorRegistry()
-
-	validators := []ContentValidator{
+	return registryOf(
 		DefaultMP4Validator(),
 		DefaultMP3Validator(),
 		DefaultWebMValidator(),
@@ -247,13 +248,41 @@ func MediaOnlyRegistry() *ContentValidatorRegistry {
 		DefaultMKVValidator(),
 		DefaultMOVValidator(),
 		DefaultAACValidator(),
-	}
+	)
+}
+
+// AudioOnlyRegistry returns a registry with only audio validators
+// (MP3, AAC, FLAC, Ogg, WAV, and MP4 for M4A)
+func AudioOnlyRegistry() *ContentValidatorRegistry {
+	return registryOf(
+		DefaultMP3Validator(),
+		DefaultAACValidator(),
+		DefaultFLACValidator(),
+		DefaultOggValidator(),
+		DefaultWAVValidator(),
+		DefaultMP4Validator(),
+	)
+}
 
+// VideoOnlyRegistry returns a registry with only video validators
+// (MP4, WebM, MOV, MKV, AVI)
+func VideoOnlyRegistry() *ContentValidatorRegistry {
+	return registryOf(
+		DefaultMP4Validator(),
+		DefaultWebMValidator(),
+		DefaultMOVValidator(),
+		DefaultMKVValidator(),
+		DefaultAVIValidator(),
+	)
+}
+
+// registryOf returns a registry with each validator registered for its MIME types
+func registryOf(validators ...ContentValidator) *ContentValidatorRegistry {
+	registry := NewContentValidatorRegistry()
 	for _, v := range validators {
 		for _, mime := range v.SupportedMIMETypes() {
 			registry.Register(mime, v)
 		}
 	}
-
 	return registry
 }
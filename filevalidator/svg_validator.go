@@ -0,0 +1,230 @@
+package filevalidator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// SVGValidator validates SVG images and rejects active content.
+// SVGs are rendered by browsers with full scripting support, so unlike raster
+// images a valid SVG can still carry XSS or XXE payloads.
+//
+// The document is checked with a streaming XML tokenizer; no DOM is built.
+// Rejected constructs:
+//   - <script> elements (in any namespace)
+//   - event handler attributes (onload, onclick, ...), including ones set through <set>/<animate>
+//   - DTD entity declarations and external DOCTYPEs other than the standard W3C SVG DTDs
+//   - href/xlink:href values that are neither fragments, data: URIs nor relative URLs
+type SVGValidator struct {
+	// MaxSize is the maximum allowed SVG file size in bytes.
+	MaxSize int64
+
+	// MaxDepth is the maximum element nesting depth (0 = unlimited).
+	MaxDepth int
+}
+
+// DefaultSVGValidator creates an SVG validator with secure defaults
+func DefaultSVGValidator() *SVGValidator {
+	return &SVGValidator{
+		MaxSize:  5 * MB,
+		MaxDepth: 100,
+	}
+}
+
+// standardSVGPublicIDs are the public identifiers of the W3C SVG DTDs.
+// Editors such as Illustrator emit these DOCTYPEs; they declare no entities.
+var standardSVGPublicIDs = []string{
+	"-//W3C//DTD SVG 1.0//EN",
+	"-//W3C//DTD SVG 1.1//EN",
+	"-//W3C//DTD SVG 1.1 Basic//EN",
+	"-//W3C//DTD SVG 1.1 Tiny//EN",
+}
+
+// ValidateContent validates SVG structure and rejects scripts and external references
+func (v *SVGValidator) ValidateContent(reader io.Reader, size int64) error {
+	if v.MaxSize > 0 && size > v.MaxSize {
+		return NewValidationError(ErrorTypeContent,
+			fmt.Sprintf("SVG file size %d exceeds maximum %d", size, v.MaxSize))
+	}
+
+	decoder := xml.NewDecoder(reader)
+	depth := 0
+	sawRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return NewValidationError(ErrorTypeContent, fmt.Sprintf("invalid SVG: %v", err))
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			if err := v.checkDirective(t); err != nil {
+				return err
+			}
+		case xml.StartElement:
+			depth++
+			if v.MaxDepth > 0 && depth > v.MaxDepth {
+				return NewValidationError(ErrorTypeContent,
+					fmt.Sprintf("SVG nesting depth exceeds maximum %d", v.MaxDepth))
+			}
+			if !sawRoot {
+				if !strings.EqualFold(t.Name.Local, "svg") {
+					return NewValidationError(ErrorTypeContent,
+						fmt.Sprintf("SVG root element is <%s>, expected <svg>", t.Name.Local))
+				}
+				sawRoot = true
+			}
+			if err := v.checkElement(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if !sawRoot {
+		return NewValidationError(ErrorTypeContent, "SVG has no <svg> root element")
+	}
+	return nil
+}
+
+// checkDirective rejects entity declarations and external DOCTYPEs
+func (v *SVGValidator) checkDirective(d xml.Directive) error {
+	directive := string(d)
+	if !strings.HasPrefix(directive, "DOCTYPE") {
+		return NewValidationError(ErrorTypeContent,
+			"SVG contains <!ENTITY> or other DTD declarations (XXE protection)")
+	}
+	if strings.Contains(directive, "[") || strings.Contains(directive, "<!ENTITY") {
+		return NewValidationError(ErrorTypeContent,
+			"SVG DOCTYPE contains entity declarations (XXE protection)")
+	}
+	if !strings.Contains(directive, "SYSTEM") && !strings.Contains(directive, "PUBLIC") {
+		return nil
+	}
+	if strings.Contains(directive, "PUBLIC") {
+		for _, id := range standardSVGPublicIDs {
+			if strings.Contains(directive, `"`+id+`"`) {
+				return nil
+			}
+		}
+	}
+	return NewValidationError(ErrorTypeContent,
+		"SVG DOCTYPE references an external DTD (XXE protection)")
+}
+
+// checkElement rejects script elements, event handlers and external links
+func (v *SVGValidator) checkElement(el xml.StartElement) error {
+	name := strings.ToLower(el.Name.Local)
+	if name == "script" {
+		return NewValidationError(ErrorTypeContent, "SVG contains <script> element")
+	}
+
+	// <set>/<animate> can assign attributes at render time
+	var animatedAttr string
+	if name == "set" || strings.HasPrefix(name, "animate") {
+		for _, attr := range el.Attr {
+			if strings.EqualFold(attr.Name.Local, "attributeName") {
+				animatedAttr = strings.ToLower(strings.TrimSpace(attr.Value))
+				if idx := strings.IndexByte(animatedAttr, ':'); idx >= 0 {
+					animatedAttr = animatedAttr[idx+1:]
+				}
+			}
+		}
+		if strings.HasPrefix(animatedAttr, "on") {
+			return NewValidationError(ErrorTypeContent,
+				fmt.Sprintf("SVG <%s> sets event handler attribute %s", el.Name.Local, animatedAttr))
+		}
+	}
+
+	for _, attr := range el.Attr {
+		local := strings.ToLower(attr.Name.Local)
+		switch {
+		case strings.HasPrefix(local, "on"):
+			return NewValidationError(ErrorTypeContent,
+				fmt.Sprintf("SVG <%s> has event handler attribute %s", el.Name.Local, attr.Name.Local))
+		case local == "href":
+			if !isSafeSVGReference(attr.Value) {
+				return NewValidationError(ErrorTypeContent,
+					fmt.Sprintf("SVG <%s> links to external or scripted URL %q", el.Name.Local, attr.Value))
+			}
+		case animatedAttr == "href" && (local == "to" || local == "from" || local == "values" || local == "by"):
+			for _, value := range strings.Split(attr.Value, ";") {
+				if !isSafeSVGReference(value) {
+					return NewValidationError(ErrorTypeContent,
+						fmt.Sprintf("SVG <%s> animates href to external or scripted URL %q", el.Name.Local, value))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isSafeSVGReference reports whether an href is a fragment, a data: URI or a relative URL
+func isSafeSVGReference(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return true
+	}
+	if len(ref) >= 5 && strings.EqualFold(ref[:5], "data:") {
+		return true
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" && u.Host == "" && !strings.HasPrefix(ref, "//")
+}
+
+// SupportedMIMETypes returns MIME types this validator handles
+func (v *SVGValidator) SupportedMIMETypes() []string {
+	return []string{
+		"image/svg+xml",
+	}
+}
+
+// looksLikeSVG reports whether data is an XML document whose root element is <svg>.
+// The XML declaration, comments, processing instructions and a DOCTYPE may precede it.
+func looksLikeSVG(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		var end []byte
+		switch {
+		case bytes.HasPrefix(data, []byte("<?")):
+			end = []byte("?>")
+		case bytes.HasPrefix(data, []byte("<!--")):
+			end = []byte("-->")
+		case bytes.HasPrefix(data, []byte("<!DOCTYPE")):
+			end = []byte(">")
+			if bracket := bytes.IndexByte(data, '['); bracket >= 0 && bracket < bytes.IndexByte(data, '>') {
+				end = []byte("]>")
+			}
+		default:
+			if !bytes.HasPrefix(data, []byte("<svg")) || len(data) < 5 {
+				return false
+			}
+			switch data[4] {
+			case ' ', '\t', '\r', '\n', '>', '/':
+				return true
+			}
+			return false
+		}
+
+		idx := bytes.Index(data, end)
+		if idx < 0 {
+			return false
+		}
+		data = data[idx+len(end):]
+	}
+}
@@ -0,0 +1,210 @@
+package filevalidator
+
+import (
+	"strings"
+	"testing"
+)
+
+const cleanSVG = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="100" height="100">
+  <defs><linearGradient id="g"><stop offset="0" stop-color="red"/></linearGradient></defs>
+  <rect width="100" height="100" fill="url(#g)"/>
+  <use xlink:href="#g"/>
+  <image href="data:image/png;base64,iVBORw0KGgo=" width="10" height="10"/>
+  <image href="icons/logo.png" width="10" height="10"/>
+</svg>`
+
+func TestSVGValidator_ValidateContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		svg     string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "clean SVG",
+			svg:  cleanSVG,
+		},
+		{
+			name: "standard W3C DOCTYPE",
+			svg: `<?xml version="1.0"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg"><rect width="1" height="1"/></svg>`,
+		},
+		{
+			name: "script element",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+  <script type="text/javascript">alert(document.cookie)</script>
+</svg>`,
+			wantErr: true,
+			errMsg:  "<script>",
+		},
+		{
+			name:    "onload attribute",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"></svg>`,
+			wantErr: true,
+			errMsg:  "onload",
+		},
+		{
+			name:    "event attribute on child",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><rect ONCLICK="alert(1)"/></svg>`,
+			wantErr: true,
+			errMsg:  "event handler",
+		},
+		{
+			name:    "event handler set by animation",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><set attributeName="onmouseover" to="alert(1)"/></svg>`,
+			wantErr: true,
+			errMsg:  "event handler",
+		},
+		{
+			name: "XXE entity",
+			svg: `<?xml version="1.0"?>
+<!DOCTYPE svg [ <!ENTITY xxe SYSTEM "file:///etc/passwd"> ]>
+<svg xmlns="http://www.w3.org/2000/svg"><text>&xxe;</text></svg>`,
+			wantErr: true,
+			errMsg:  "XXE",
+		},
+		{
+			name: "external DTD",
+			svg: `<?xml version="1.0"?>
+<!DOCTYPE svg SYSTEM "http://attacker.example/evil.dtd">
+<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+			wantErr: true,
+			errMsg:  "external DTD",
+		},
+		{
+			name:    "javascript href",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="javascript:alert(1)"><text>x</text></a></svg>`,
+			wantErr: true,
+			errMsg:  "javascript:alert(1)",
+		},
+		{
+			name:    "entity-encoded javascript href",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><a href="&#106;avascript:alert(1)"><text>x</text></a></svg>`,
+			wantErr: true,
+			errMsg:  "links to external",
+		},
+		{
+			name:    "external image",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><image href="https://tracker.example/pixel.png"/></svg>`,
+			wantErr: true,
+			errMsg:  "links to external",
+		},
+		{
+			name:    "protocol-relative href",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><use href="//evil.example/sprite.svg#a"/></svg>`,
+			wantErr: true,
+			errMsg:  "links to external",
+		},
+		{
+			name:    "href animated to javascript",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><a><animate attributeName="href" values="#a;javascript:alert(1)"/></a></svg>`,
+			wantErr: true,
+			errMsg:  "animates href",
+		},
+		{
+			name:    "non-SVG root",
+			svg:     `<?xml version="1.0"?><html><body/></html>`,
+			wantErr: true,
+			errMsg:  "root element",
+		},
+		{
+			name:    "too deep",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg">` + strings.Repeat("<g>", 150) + strings.Repeat("</g>", 150) + `</svg>`,
+			wantErr: true,
+			errMsg:  "nesting depth",
+		},
+		{
+			name:    "malformed",
+			svg:     `<svg xmlns="http://www.w3.org/2000/svg"><rect></svg>`,
+			wantErr: true,
+			errMsg:  "invalid SVG",
+		},
+	}
+
+	validator := DefaultSVGValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateContent(strings.NewReader(tt.svg), int64(len(tt.svg)))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				if !IsErrorOfType(err, ErrorTypeContent) {
+					t.Errorf("Expected ErrorTypeContent, got %v", GetErrorType(err))
+				}
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSVGValidator_MaxSize(t *testing.T) {
+	validator := &SVGValidator{MaxSize: 10}
+	err := validator.ValidateContent(strings.NewReader(cleanSVG), int64(len(cleanSVG)))
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum") {
+		t.Errorf("Expected size error, got %v", err)
+	}
+}
+
+func TestDetectMIME_SVG(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"with XML declaration", cleanSVG, "image/svg+xml"},
+		{"without XML declaration", `<svg xmlns="http://www.w3.org/2000/svg"></svg>`, "image/svg+xml"},
+		{"after comment and DOCTYPE", `<!-- logo --><!DOCTYPE svg [ <!ENTITY a "b"> ]><svg></svg>`, "image/svg+xml"},
+		{"plain XML", `<?xml version="1.0"?><root/>`, "application/xml"},
+		{"text mentioning svg", `use <svg> tags for icons`, "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMIMEFromBytes([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectMIMEFromBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForImages_RejectsScriptedSVG(t *testing.T) {
+	presets := map[string]*FileValidator{
+		"ForImages": ForImages().Build(),
+		"ForWeb":    ForWeb().Build(),
+		"Strict":    ForImages().RequireContentValidation().Build(),
+	}
+	unsafe := map[string]string{
+		"script": `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`,
+		"onload": `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><rect width="1" height="1"/></svg>`,
+		"XXE":    `<?xml version="1.0"?><!DOCTYPE svg [<!ENTITY x SYSTEM "file:///etc/passwd">]><svg xmlns="http://www.w3.org/2000/svg">&x;</svg>`,
+	}
+
+	for name, validator := range presets {
+		t.Run(name, func(t *testing.T) {
+			if err := validator.ValidateBytes([]byte(cleanSVG), "logo.svg"); err != nil {
+				t.Errorf("clean SVG rejected: %v", err)
+			}
+			for kind, svg := range unsafe {
+				if err := validator.ValidateBytes([]byte(svg), "logo.svg"); !IsErrorOfType(err, ErrorTypeContent) {
+					t.Errorf("Expected ErrorTypeContent for %s SVG, got %v", kind, err)
+				}
+				if errs := validator.ValidateAllBytes([]byte(svg), "logo.svg"); len(errs) == 0 {
+					t.Errorf("ValidateAllBytes() accepted %s SVG", kind)
+				}
+			}
+		})
+	}
+
+	// Content failures for other image types stay warnings in ForImages
+	corruptPNG := append([]byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, "not a png"...)
+	if err := ForImages().Build().ValidateBytes(corruptPNG, "logo.png"); err != nil {
+		t.Errorf("ForImages() rejected a corrupt PNG: %v, want only SVG content failures to block", err)
+	}
+}
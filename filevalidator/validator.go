@@ -8,7 +8,12 @@ import (
 	"io"
 	"mime/multipart"
 	"path/filepath"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Validator provides the main interface for validating files
@@ -72,12 +77,8 @@ func (v *FileValidator) ValidateWithContext(ctx context.Context, file *multipart
 	fileSize := file.Size
 
 	// Check if file size is within the allowed range
-	if v.constraints.MaxFileSize > 0 && fileSize > v.constraints.MaxFileSize {
-		return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too big: %d bytes (max: %d bytes)", fileSize, v.constraints.MaxFileSize))
-	}
-
-	if v.constraints.MinFileSize > 0 && fileSize < v.constraints.MinFileSize {
-		return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too small: %d bytes (min: %d bytes)", fileSize, v.constraints.MinFileSize))
+	if err := v.validateSize(fileSize); err != nil {
+		return err
 	}
 
 	// Skip MIME validation if no accepted types are specified
@@ -101,10 +102,11 @@ func (v *FileValidator) ValidateWithContext(ctx context.Context, file *multipart
 	}
 
 	// Detect MIME type using enhanced magic bytes detection
-	mimeType, err := DetectMIME(f)
+	header, err := readHeader(ctx, f)
 	if err != nil {
 		return err
 	}
+	mimeType := DetectMIMEFromBytes(header)
 
 	// Validate MIME type against accepted types
 	if !v.isAcceptedMIMEType(mimeType) {
@@ -116,15 +118,8 @@ func (v *FileValidator) ValidateWithContext(ctx context.Context, file *multipart
 
 	// Strict MIME type validation: ensure extension matches detected MIME type
 	if v.constraints.StrictMIMETypeValidation {
-		ext := strings.ToLower(filepath.Ext(file.Filename))
-		if ext != "" {
-			expectedMIME := MIMETypeForExtension(ext)
-			if expectedMIME != "" && expectedMIME != mimeType {
-				return NewValidationError(
-					ErrorTypeMIME,
-					fmt.Sprintf("MIME type mismatch: extension %s suggests %s but detected %s", ext, expectedMIME, mimeType),
-				)
-			}
+		if err := v.checkMIMEMismatch(file.Filename, mimeType); err != nil {
+			return err
 		}
 	}
 
@@ -137,7 +132,7 @@ func (v *FileValidator) ValidateWithContext(ctx context.Context, file *multipart
 
 		if err := v.constraints.ContentValidatorRegistry.ValidateContent(mimeType, f, fileSize); err != nil {
 			// If content validation is required, return the error
-			if v.constraints.RequireContentValidation {
+			if v.contentRequired(mimeType) {
 				return err
 			}
 			// Otherwise, content validation failures are warnings (logged but not blocking)
@@ -147,21 +142,62 @@ func (v *FileValidator) ValidateWithContext(ctx context.Context, file *multipart
 	return nil
 }
 
-// ValidateReader validates a file from an io.Reader with a filename
+// ValidateReader validates a file from an io.Reader with a filename. A size
+// of zero or less means the size is unknown: it is measured if reader is an
+// io.Seeker, so an empty file still fails MinFileSize, and not checked
+// otherwise.
 func (v *FileValidator) ValidateReader(reader io.Reader, filename string, size int64) error {
+	return v.ValidateReaderWithContext(context.Background(), reader, filename, size)
+}
+
+// ValidateReaderWithContext is ValidateReader with a context bounding the read
+// of the file header used for MIME detection. A reader that stalls past the
+// context's deadline fails promptly with an error wrapping ctx.Err() (e.g.
+// context.DeadlineExceeded).
+func (v *FileValidator) ValidateReaderWithContext(ctx context.Context, reader io.Reader, filename string, size int64) error {
+	size, sizeKnown := readerSize(reader, size)
+	return v.validateReader(ctx, reader, filename, size, sizeKnown)
+}
+
+// readerSize returns size if it is positive, and otherwise the number of bytes
+// left in reader if it is an io.Seeker. sizeKnown is false if neither is
+// available.
+func readerSize(reader io.Reader, size int64) (n int64, sizeKnown bool) {
+	if size > 0 {
+		return size, true
+	}
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return size, false
+	}
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return size, false
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if _, serr := seeker.Seek(cur, io.SeekStart); err != nil || serr != nil {
+		return size, false
+	}
+	return end - cur, true
+}
+
+// validateReader implements ValidateReaderWithContext. The size is checked
+// only when sizeKnown, so callers that know the exact length can have an empty
+// file checked against MinFileSize.
+func (v *FileValidator) validateReader(ctx context.Context, reader io.Reader, filename string, size int64, sizeKnown bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Validate filename first
 	if err := v.validateFileName(filename); err != nil {
 		return err
 	}
 
 	// Check file size if provided
-	if size > 0 {
-		if v.constraints.MaxFileSize > 0 && size > v.constraints.MaxFileSize {
-			return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too big: %d bytes (max: %d bytes)", size, v.constraints.MaxFileSize))
-		}
-
-		if v.constraints.MinFileSize > 0 && size < v.constraints.MinFileSize {
-			return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too small: %d bytes (min: %d bytes)", size, v.constraints.MinFileSize))
+	if sizeKnown {
+		if err := v.validateSize(size); err != nil {
+			return err
 		}
 	}
 
@@ -179,10 +215,11 @@ func (v *FileValidator) ValidateReader(reader io.Reader, filename string, size i
 			return NewValidationError(ErrorTypeMIME, "failed to get current position in reader")
 		}
 
-		mimeType, err := DetectMIME(reader)
+		header, err := readHeader(ctx, reader)
 		if err != nil {
 			return err
 		}
+		mimeType := DetectMIMEFromBytes(header)
 
 		// Reset the reader position
 		_, err = seekable.Seek(oldPos, io.SeekStart)
@@ -200,20 +237,17 @@ func (v *FileValidator) ValidateReader(reader io.Reader, filename string, size i
 
 		// Strict MIME type validation: ensure extension matches detected MIME type
 		if v.constraints.StrictMIMETypeValidation {
-			ext := strings.ToLower(filepath.Ext(filename))
-			if ext != "" {
-				expectedMIME := MIMETypeForExtension(ext)
-				if expectedMIME != "" && expectedMIME != mimeType {
-					return NewValidationError(
-						ErrorTypeMIME,
-						fmt.Sprintf("MIME type mismatch: extension %s suggests %s but detected %s", ext, expectedMIME, mimeType),
-					)
-				}
+			if err := v.checkMIMEMismatch(filename, mimeType); err != nil {
+				return err
 			}
 		}
 
 		// Perform content validation if enabled
 		if v.constraints.ContentValidationEnabled && v.constraints.ContentValidatorRegistry != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			// Reset reader position for content validation
 			_, err = seekable.Seek(0, io.SeekStart)
 			if err != nil {
@@ -222,7 +256,7 @@ func (v *FileValidator) ValidateReader(reader io.Reader, filename string, size i
 
 			if err := v.constraints.ContentValidatorRegistry.ValidateContent(mimeType, reader, size); err != nil {
 				// If content validation is required, return the error
-				if v.constraints.RequireContentValidation {
+				if v.contentRequired(mimeType) {
 					return err
 				}
 				// Otherwise, content validation failures are warnings (logged but not blocking)
@@ -242,13 +276,152 @@ func (v *FileValidator) ValidateReader(reader io.Reader, filename string, size i
 	return nil
 }
 
-// ValidateBytes validates a file from a byte slice with a filename
+// ValidateBytes validates a file from a byte slice with a filename. Unlike
+// ValidateReader, an empty slice is checked against MinFileSize.
 func (v *FileValidator) ValidateBytes(content []byte, filename string) error {
 	// Create a reader from the byte slice
 	reader := bytes.NewReader(content)
 
-	// Validate using the reader
-	return v.ValidateReader(reader, filename, int64(len(content)))
+	// Validate using the reader; the length is exact, so always check it
+	return v.validateReader(context.Background(), reader, filename, int64(len(content)), true)
+}
+
+// ValidateAll runs every enabled check on file and returns all failures, each a
+// *ValidationError carrying its ErrorType, or nil if the file is valid. Unlike
+// Validate it does not stop at the first failure, so a wrong type, an oversized
+// file and a blocked extension are reported together. Content validation
+// failures are included only when RequireContentValidation is set or
+// RequireContentValidationFor lists the type, as in Validate.
+func (v *FileValidator) ValidateAll(file *multipart.FileHeader) []error {
+	errs := v.nameAndSizeErrors(file.Filename, file.Size, true)
+	if len(v.constraints.AcceptedTypes) == 0 {
+		return errs
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return append(errs, NewValidationError(ErrorTypeMIME, "failed to open file for MIME type detection"))
+	}
+	defer f.Close()
+
+	return append(errs, v.contentErrors(f, file.Filename, file.Size)...)
+}
+
+// ValidateAllReader is ValidateAll for a reader. As with ValidateReader, the
+// MIME type and content are checked only when reader is an io.Seeker, and the
+// size only when it is positive or can be measured by seeking.
+func (v *FileValidator) ValidateAllReader(reader io.Reader, filename string, size int64) []error {
+	size, sizeKnown := readerSize(reader, size)
+	return v.validateAllReader(reader, filename, size, sizeKnown)
+}
+
+// validateAllReader implements ValidateAllReader, checking the size only when
+// sizeKnown
+func (v *FileValidator) validateAllReader(reader io.Reader, filename string, size int64, sizeKnown bool) []error {
+	errs := v.nameAndSizeErrors(filename, size, sizeKnown)
+	if len(v.constraints.AcceptedTypes) == 0 {
+		return errs
+	}
+
+	// Without seeking, the extension checks above are all that can be done
+	if seekable, ok := reader.(io.ReadSeeker); ok {
+		errs = append(errs, v.contentErrors(seekable, filename, size)...)
+	}
+	return errs
+}
+
+// ValidateAllBytes is ValidateAll for a byte slice. As in ValidateBytes, an
+// empty slice is checked against MinFileSize.
+func (v *FileValidator) ValidateAllBytes(content []byte, filename string) []error {
+	return v.validateAllReader(bytes.NewReader(content), filename, int64(len(content)), true)
+}
+
+// nameAndSizeErrors returns the filename, extension and size failures. The
+// size is checked only when sizeKnown.
+func (v *FileValidator) nameAndSizeErrors(filename string, size int64, sizeKnown bool) []error {
+	var errs []error
+	if err := v.validateName(filename); err != nil {
+		errs = append(errs, err)
+	}
+	if len(filename) > 0 {
+		if err := v.validateExtension(filename); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if sizeKnown {
+		if err := v.validateSize(size); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// contentErrors detects the MIME type of r and returns the MIME, strict MIME
+// and content validation failures. r is restored to its original position.
+func (v *FileValidator) contentErrors(r io.ReadSeeker, filename string, size int64) []error {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return []error{NewValidationError(ErrorTypeMIME, "failed to get current position in reader")}
+	}
+	defer r.Seek(start, io.SeekStart)
+
+	mimeType, err := DetectMIME(r)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	if !v.isAcceptedMIMEType(mimeType) {
+		errs = append(errs, NewValidationError(
+			ErrorTypeMIME,
+			fmt.Sprintf("file type %s is not accepted; allowed types: %v", mimeType, v.expandedAcceptedTypes()),
+		))
+	}
+	if v.constraints.StrictMIMETypeValidation {
+		if err := v.checkMIMEMismatch(filename, mimeType); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if v.constraints.ContentValidationEnabled && v.contentRequired(mimeType) && v.constraints.ContentValidatorRegistry != nil {
+		if _, err := r.Seek(start, io.SeekStart); err != nil {
+			return append(errs, NewValidationError(ErrorTypeContent, "failed to reset reader position for content validation"))
+		}
+		if err := v.constraints.ContentValidatorRegistry.ValidateContent(mimeType, r, size); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ValidateHeader validates a file from its first bytes, for streams that cannot be
+// read twice. header must be a prefix of the content and size the total size in
+// bytes (-1 if unknown). Filename, size and MIME checks behave as in ValidateReader.
+// Content validators need the whole file, so content validation runs only when
+// header holds the complete content.
+func (v *FileValidator) ValidateHeader(header []byte, filename string, size int64) error {
+	if size >= 0 && int64(len(header)) >= size {
+		return v.ValidateBytes(header[:size], filename)
+	}
+
+	partial := &FileValidator{constraints: v.constraints}
+	partial.constraints.ContentValidationEnabled = false
+	// The header is not the whole file, so its length is not the file size
+	return partial.validateReader(context.Background(), bytes.NewReader(header), filename, size, size > 0)
+}
+
+// DetectMismatch compares the MIME type implied by the filename's extension with the
+// type detected from the reader's content. mismatch is true when both are known,
+// differ, and the pair is not listed in BenignMIMEMismatches. The reader is consumed
+// up to the bytes needed for detection.
+func (v *FileValidator) DetectMismatch(reader io.Reader, filename string) (claimed, detected string, mismatch bool, err error) {
+	detected, err = DetectMIME(reader)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	claimed = MIMETypeForExtension(strings.ToLower(filepath.Ext(filename)))
+	return claimed, detected, v.isMIMEMismatch(claimed, detected), nil
 }
 
 // GetConstraints returns the current validation constraints
@@ -256,8 +429,69 @@ func (v *FileValidator) GetConstraints() Constraints {
 	return v.constraints
 }
 
+// readHeader reads the first MIMEHeaderSize bytes of reader for MIME
+// detection. When ctx can end, the read runs in a goroutine so that a reader
+// that never returns cannot outlive ctx; the abandoned read keeps the reader
+// until it returns, so the caller must not use the reader after an error.
+func readHeader(ctx context.Context, reader io.Reader) ([]byte, error) {
+	read := func() ([]byte, error) {
+		buf := make([]byte, MIMEHeaderSize)
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, NewValidationError(ErrorTypeMIME, "failed to read file for MIME detection")
+		}
+		return buf[:n], nil
+	}
+	if ctx.Done() == nil {
+		return read()
+	}
+
+	type result struct {
+		header []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		header, err := read()
+		done <- result{header, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.header, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("reading file header for MIME detection: %w", ctx.Err())
+	}
+}
+
+// contentRequired reports whether content validation failures for mimeType
+// fail validation rather than being treated as warnings
+func (v *FileValidator) contentRequired(mimeType string) bool {
+	return v.constraints.RequireContentValidation || slices.Contains(v.constraints.RequireContentValidationFor, mimeType)
+}
+
+// validateSize checks size against MinFileSize and MaxFileSize
+func (v *FileValidator) validateSize(size int64) error {
+	if v.constraints.MaxFileSize > 0 && size > v.constraints.MaxFileSize {
+		return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too big: %d bytes (max: %d bytes)", size, v.constraints.MaxFileSize))
+	}
+
+	if v.constraints.MinFileSize > 0 && size < v.constraints.MinFileSize {
+		return NewValidationError(ErrorTypeSize, fmt.Sprintf("file size too small: %d bytes (min: %d bytes)", size, v.constraints.MinFileSize))
+	}
+	return nil
+}
+
 // validateFileName validates a filename against the validator's constraints
 func (v *FileValidator) validateFileName(filename string) error {
+	if err := v.validateName(filename); err != nil {
+		return err
+	}
+	return v.validateExtension(filename)
+}
+
+// validateName checks the filename's length, characters and pattern
+func (v *FileValidator) validateName(filename string) error {
 	if len(filename) == 0 {
 		return NewValidationError(ErrorTypeFileName, "empty filename")
 	}
@@ -266,7 +500,7 @@ func (v *FileValidator) validateFileName(filename string) error {
 	if v.constraints.MaxNameLength > 0 && len(filename) > v.constraints.MaxNameLength {
 		return NewValidationError(
 			ErrorTypeFileName,
-			fmt.Sprintf("filename exceeds maximum length of %d characters", v.constraints.MaxNameLength),
+			fmt.Sprintf("filename exceeds maximum length of %d bytes", v.constraints.MaxNameLength),
 		)
 	}
 
@@ -280,6 +514,29 @@ func (v *FileValidator) validateFileName(filename string) error {
 		}
 	}
 
+	// Control characters and path separators are never valid in a filename
+	for _, r := range filename {
+		if r == '/' || r == '\\' {
+			return NewValidationError(ErrorTypeFileName, "filename contains a path separator")
+		}
+		if unicode.IsControl(r) {
+			return NewValidationError(
+				ErrorTypeFileName,
+				fmt.Sprintf("filename contains control character %U", r),
+			)
+		}
+	}
+
+	// Mixed normalization forms make visually identical names compare unequal
+	if v.constraints.RequireNFC {
+		if !utf8.ValidString(filename) {
+			return NewValidationError(ErrorTypeFileName, "filename is not valid UTF-8")
+		}
+		if !norm.NFC.IsNormalString(filename) {
+			return NewValidationError(ErrorTypeFileName, "filename is not in Unicode NFC form")
+		}
+	}
+
 	// Optional regex validation
 	if v.constraints.FileNameRegex != nil {
 		if !v.constraints.FileNameRegex.MatchString(filename) {
@@ -290,7 +547,12 @@ func (v *FileValidator) validateFileName(filename string) error {
 		}
 	}
 
-	// Validate file extension
+	return nil
+}
+
+// validateExtension checks the filename's extension against the required,
+// blocked and allowed extensions
+func (v *FileValidator) validateExtension(filename string) error {
 	ext := strings.ToLower(filepath.Ext(filename))
 	if len(ext) == 0 && v.constraints.RequireExtension {
 		return NewValidationError(ErrorTypeExtension, "file must have an extension")
@@ -319,6 +581,34 @@ func (v *FileValidator) validateFileName(filename string) error {
 	return nil
 }
 
+// checkMIMEMismatch returns an error if the filename's extension implies a MIME type
+// that conflicts with the detected one
+func (v *FileValidator) checkMIMEMismatch(filename, detected string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	claimed := MIMETypeForExtension(ext)
+	if !v.isMIMEMismatch(claimed, detected) {
+		return nil
+	}
+	return NewValidationError(
+		ErrorTypeMIME,
+		fmt.Sprintf("MIME type mismatch: extension %s suggests %s but detected %s", ext, claimed, detected),
+	)
+}
+
+// isMIMEMismatch reports whether a claimed and detected MIME type conflict.
+// Unknown extensions never conflict.
+func (v *FileValidator) isMIMEMismatch(claimed, detected string) bool {
+	if claimed == "" || claimed == detected {
+		return false
+	}
+	for _, benign := range v.constraints.BenignMIMEMismatches[claimed] {
+		if benign == detected {
+			return false
+		}
+	}
+	return true
+}
+
 // isAcceptedMIMEType checks if a MIME type is accepted by the validator
 func (v *FileValidator) isAcceptedMIMEType(mimeType string) bool {
 	expandedTypes := v.expandedAcceptedTypes()
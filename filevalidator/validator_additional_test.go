@@ -387,3 +387,78 @@ type nonSeekableReader struct {
 func (r *nonSeekableReader) Read(p []byte) (n int, err error) {
 	return r.Reader.Read(p)
 }
+
+func TestDetectMismatch(t *testing.T) {
+	validator := NewDefault()
+
+	tests := []struct {
+		name         string
+		filename     string
+		content      []byte
+		wantClaimed  string
+		wantDetected string
+		wantMismatch bool
+	}{
+		{"renamed PDF", "photo.png", []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"), "image/png", "application/pdf", true},
+		{"matching PNG", "photo.png", []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0, 0, 0, 0}, "image/png", "image/png", false},
+		{"JSON in text file", "notes.txt", []byte(`{"note": "plain text that happens to be JSON"}`), "text/plain", "application/json", false},
+		{"CSV detected as text", "data.csv", []byte("a,b,c\n1,2,3\n"), "text/csv", "text/plain", false},
+		{"unknown extension", "data.bin", []byte("%PDF-1.4\n"), "", "application/pdf", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claimed, detected, mismatch, err := validator.DetectMismatch(bytes.NewReader(tt.content), tt.filename)
+			if err != nil {
+				t.Fatalf("DetectMismatch() error = %v", err)
+			}
+			if claimed != tt.wantClaimed || detected != tt.wantDetected || mismatch != tt.wantMismatch {
+				t.Errorf("DetectMismatch() = (%q, %q, %v), want (%q, %q, %v)",
+					claimed, detected, mismatch, tt.wantClaimed, tt.wantDetected, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestStrict_RejectsRenamedPDF(t *testing.T) {
+	validator := Strict().Accept("image/png", "application/pdf").Build()
+
+	err := validator.ValidateBytes([]byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"), "photo.png")
+	if !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("Expected ErrorTypeMIME for renamed PDF, got %v", err)
+	}
+}
+
+func TestStrict_AllowsAmbiguousText(t *testing.T) {
+	content := []byte(`{"note": "plain text that happens to be JSON"}`)
+
+	validator := Strict().Accept("text/plain", "application/json").Build()
+	if err := validator.ValidateBytes(content, "notes.txt"); err != nil {
+		t.Errorf("ValidateBytes() error = %v, want JSON in .txt accepted as benign", err)
+	}
+
+	// Without the allowlist the same file is a mismatch
+	exact := Empty().Accept("text/plain", "application/json").StrictMIME().Build()
+	if err := exact.ValidateBytes(content, "notes.txt"); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("Expected ErrorTypeMIME without benign mismatches, got %v", err)
+	}
+}
+
+func TestBuilder_AllowMIMEMismatch(t *testing.T) {
+	// Markdown that starts with a link is detected as JSON
+	content := []byte("[Release notes](https://example.com/notes)\n\n- fixed a bug\n")
+	detected := DetectMIMEFromBytes(content)
+
+	base := NewBuilder().Accept("text/markdown", detected).StrictMIME()
+	if err := base.Build().ValidateBytes(content, "CHANGES.md"); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Fatalf("Expected ErrorTypeMIME before allowing the mismatch, got %v", err)
+	}
+
+	validator := base.AllowMIMEMismatch("text/markdown", detected).Build()
+	if err := validator.ValidateBytes(content, "CHANGES.md"); err != nil {
+		t.Errorf("ValidateBytes() error = %v after AllowMIMEMismatch", err)
+	}
+	if got := DefaultBenignMIMEMismatches()["text/markdown"]; len(got) != 1 {
+		t.Errorf("AllowMIMEMismatch modified the defaults: %v", got)
+	}
+}
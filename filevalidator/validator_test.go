@@ -1,12 +1,15 @@
 package filevalidator
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"mime/multipart"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -298,6 +301,51 @@ func TestValidateFileSize(t *testing.T) {
 	}
 }
 
+func TestValidateMinFileSize(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+	t.Run("empty file rejected", func(t *testing.T) {
+		validator := NewBuilder().AcceptImages().MinSize(1).Build()
+
+		// The size check runs before MIME and content checks
+		if err := validator.ValidateBytes(nil, "logo.png"); !IsErrorOfType(err, ErrorTypeSize) {
+			t.Errorf("ValidateBytes() on an empty file = %v, want ErrorTypeSize", err)
+		}
+		errs := validator.ValidateAllBytes(nil, "logo.png")
+		if len(errs) == 0 || !IsErrorOfType(errs[0], ErrorTypeSize) {
+			t.Errorf("ValidateAllBytes() on an empty file = %v, want ErrorTypeSize first", errs)
+		}
+		if err := validator.Validate(&multipart.FileHeader{Filename: "logo.png"}); !IsErrorOfType(err, ErrorTypeSize) {
+			t.Errorf("Validate() on an empty file = %v, want ErrorTypeSize", err)
+		}
+
+		// A size of 0 is measured when the reader can seek
+		if err := validator.ValidateReader(strings.NewReader(""), "logo.png", 0); !IsErrorOfType(err, ErrorTypeSize) {
+			t.Errorf("ValidateReader() on an empty seekable reader = %v, want ErrorTypeSize", err)
+		}
+		if errs := validator.ValidateAllReader(strings.NewReader(""), "logo.png", -1); len(errs) == 0 || !IsErrorOfType(errs[0], ErrorTypeSize) {
+			t.Errorf("ValidateAllReader() on an empty seekable reader = %v, want ErrorTypeSize first", errs)
+		}
+	})
+
+	t.Run("partial header is not the file size", func(t *testing.T) {
+		validator := NewBuilder().AcceptImages().MinSize(1 * KB).Build()
+		if err := validator.ValidateHeader(png, "logo.png", -1); err != nil {
+			t.Errorf("ValidateHeader() with an unknown size = %v, want nil", err)
+		}
+	})
+
+	t.Run("exact minimum accepted", func(t *testing.T) {
+		validator := NewBuilder().AcceptImages().MinSize(int64(len(png))).Build()
+		if err := validator.ValidateBytes(png, "logo.png"); err != nil {
+			t.Errorf("ValidateBytes() at the minimum size = %v, want nil", err)
+		}
+		if err := validator.ValidateBytes(png[:len(png)-1], "logo.png"); !IsErrorOfType(err, ErrorTypeSize) {
+			t.Errorf("ValidateBytes() one byte under the minimum = %v, want ErrorTypeSize", err)
+		}
+	})
+}
+
 func TestContextCancellation(t *testing.T) {
 	validator := NewDefault()
 
@@ -543,3 +591,186 @@ func createMockFile(filename string, contentType string, size int64) *multipart.
 		Size:     size,
 	}
 }
+
+func TestValidateFileName_LengthNormalizationAndControlChars(t *testing.T) {
+	validator := Empty().
+		MaxFilenameLength(255).
+		RequireNFC().
+		Build()
+
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{"ascii name", "report.pdf", ""},
+		{"NFC name", "caf\u00e9.txt", ""},
+		{"overlong name", strings.Repeat("a", 252) + ".txt", "exceeds maximum length of 255 bytes"},
+		{"multibyte name over byte limit", strings.Repeat("\u00e9", 126) + ".txt", "exceeds maximum length"},
+		{"NFD name", "cafe\u0301.txt", "not in Unicode NFC form"},
+		{"invalid UTF-8", "caf\xe9.txt", "not valid UTF-8"},
+		{"null byte", "photo.png\x00.exe", "control character U+0000"},
+		{"newline", "photo\n.png", "control character U+000A"},
+		{"path separator", "uploads/photo.png", "path separator"},
+		{"backslash separator", "uploads\\photo.png", "path separator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateBytes([]byte("data"), tt.filename)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateBytes(%q) error = %v", tt.filename, err)
+				}
+				return
+			}
+			if !IsErrorOfType(err, ErrorTypeFileName) {
+				t.Fatalf("ValidateBytes(%q) expected ErrorTypeFileName, got %v", tt.filename, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateBytes(%q) error = %v, want %q", tt.filename, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFileName_NFDAllowedByDefault(t *testing.T) {
+	if err := Empty().Build().ValidateBytes([]byte("data"), "cafe\u0301.txt"); err != nil {
+		t.Errorf("NFD filename rejected without RequireNFC: %v", err)
+	}
+}
+
+func TestValidateHeader(t *testing.T) {
+	validator := NewBuilder().
+		Accept("application/zip").
+		Extensions(".zip").
+		MaxSize(1 * MB).
+		RequireContentValidation().
+		WithMinimalRegistry().
+		Build()
+
+	// The first bytes of a large ZIP are not a valid archive on their own
+	header := []byte{0x50, 0x4B, 0x03, 0x04, 0x14, 0x00, 0x00, 0x00}
+
+	if err := validator.ValidateHeader(header, "archive.zip", 512*KB); err != nil {
+		t.Errorf("ValidateHeader() with partial content error = %v", err)
+	}
+	if err := validator.ValidateHeader(header, "archive.zip", 2*MB); !IsErrorOfType(err, ErrorTypeSize) {
+		t.Errorf("Expected ErrorTypeSize for declared size over limit, got %v", err)
+	}
+	if err := validator.ValidateHeader(header, "archive.exe", -1); !IsErrorOfType(err, ErrorTypeExtension) {
+		t.Errorf("Expected ErrorTypeExtension, got %v", err)
+	}
+	if err := validator.ValidateHeader([]byte("plain text"), "archive.zip", -1); !IsErrorOfType(err, ErrorTypeMIME) {
+		t.Errorf("Expected ErrorTypeMIME, got %v", err)
+	}
+
+	// A header holding the complete file gets content validation
+	if err := validator.ValidateHeader(header, "archive.zip", int64(len(header))); !IsErrorOfType(err, ErrorTypeContent) {
+		t.Errorf("Expected ErrorTypeContent for complete but corrupt ZIP, got %v", err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	validator := NewBuilder().
+		AcceptImages().
+		MaxSize(16).
+		BlockExtensions(".exe").
+		Build()
+	content := []byte("this is plain text, not an image")
+
+	wantTypes := []ValidationErrorType{ErrorTypeExtension, ErrorTypeSize, ErrorTypeMIME}
+	check := func(t *testing.T, errs []error) {
+		t.Helper()
+		if len(errs) != len(wantTypes) {
+			t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(wantTypes))
+		}
+		for i, want := range wantTypes {
+			if !IsErrorOfType(errs[i], want) {
+				t.Errorf("errs[%d] = %v, want %s error", i, errs[i], want)
+			}
+		}
+
+		// The fast path still reports only the first failure
+		if err := validator.ValidateBytes(content, "payload.exe"); !IsErrorOfType(err, ErrorTypeExtension) {
+			t.Errorf("ValidateBytes() = %v, want the extension error", err)
+		}
+	}
+
+	t.Run("bytes", func(t *testing.T) {
+		check(t, validator.ValidateAllBytes(content, "payload.exe"))
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "payload.exe")
+		part.Write(content)
+		mw.Close()
+		form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(1 << 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer form.RemoveAll()
+		check(t, validator.ValidateAll(form.File["file"][0]))
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+		if errs := validator.ValidateAllBytes(png, "logo.png"); errs != nil {
+			t.Errorf("ValidateAllBytes() on a valid file = %v, want nil", errs)
+		}
+	})
+}
+
+// stallingReadSeeker blocks in Read until release is closed, like a
+// slow-loris client that never sends the file header.
+type stallingReadSeeker struct {
+	release chan struct{}
+}
+
+func (s *stallingReadSeeker) Read(p []byte) (int, error) {
+	<-s.release
+	return 0, io.EOF
+}
+
+func (s *stallingReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return 0, nil
+}
+
+func TestValidateReaderWithContext_Deadline(t *testing.T) {
+	validator := ForImages().Build()
+	reader := &stallingReadSeeker{release: make(chan struct{})}
+	defer close(reader.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := validator.ValidateReaderWithContext(ctx, reader, "photo.jpg", 1024)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ValidateReaderWithContext returned after %v, want about 20ms", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ValidateReaderWithContext() = %v, want context.DeadlineExceeded", err)
+	}
+
+	// An already-ended context fails before reading
+	if err := validator.ValidateReaderWithContext(ctx, bytes.NewReader(nil), "photo.jpg", 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ValidateReaderWithContext() with an expired context = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReadHeader_CapsBytesRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := bytes.NewReader(make([]byte, 10*MIMEHeaderSize))
+	header, err := readHeader(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(header) != MIMEHeaderSize || r.Len() != 9*MIMEHeaderSize {
+		t.Errorf("read %d header bytes, %d left; want %d read", len(header), r.Len(), MIMEHeaderSize)
+	}
+}
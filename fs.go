@@ -7,7 +7,8 @@ import (
 )
 
 // FileInfo represents file or directory metadata returned by [FileReader.Stat]
-// and [FileReader.ListContents].
+// and [FileReader.ListContents]. It encodes to JSON with stable snake_case
+// field names; see [FileInfo.MarshalJSON].
 type FileInfo struct {
 	// Name is the base name of the file or directory (e.g., "photo.jpg").
 	Name string
@@ -24,16 +25,26 @@ type FileInfo struct {
 	// IsDir is true if this entry represents a directory.
 	IsDir bool
 
+	// IsSymlink is true if this entry is a symbolic link.
+	// Only reported by the local driver with local.SymlinkReport.
+	IsSymlink bool
+
 	// ContentType is the MIME type of the file (e.g., "image/jpeg").
 	// May be empty if not detected or not applicable (directories).
 	ContentType string
 
+	// ContentDisposition is the Content-Disposition set with
+	// WithContentDisposition (e.g., `attachment; filename="report.pdf"`).
+	// Reported by the S3, GCS, Azure and memory drivers; empty elsewhere.
+	ContentDisposition string
+
 	// Metadata contains custom key-value metadata associated with the file.
 	// Cloud storage backends support arbitrary metadata; local filesystem may not.
 	Metadata map[string]string
 
-	// ETag is the entity tag for caching and conditional requests.
-	// Used by cloud storage backends (S3, GCS, Azure).
+	// ETag is the entity tag for caching and conditional requests, as returned
+	// by the backend (S3 and Azure ETags include the surrounding quotes).
+	// Populated by Stat and ListContents on S3, GCS and Azure; empty elsewhere.
 	ETag string
 
 	// Version is the version ID for versioned storage backends.
@@ -44,7 +55,17 @@ type FileInfo struct {
 	// Backend-specific; may be empty.
 	StorageClass string
 
+	// ServerSideEncryption is the at-rest encryption applied by the backend
+	// (e.g., "AES256", "aws:kms"). Reported by S3 Stat; empty elsewhere.
+	ServerSideEncryption string
+
+	// EncryptionKeyID is the KMS key that encrypts the object when
+	// ServerSideEncryption uses KMS. Reported by S3 Stat; empty elsewhere.
+	EncryptionKeyID string
+
 	// Checksum is the pre-computed checksum if available from the backend.
+	// Cloud Stat calls report the stored checksum without reading the content:
+	// S3 additional checksums, GCS CRC32C (or MD5), Azure Content-MD5.
 	Checksum string
 
 	// ChecksumAlgorithm indicates which algorithm was used for Checksum.
@@ -66,37 +87,37 @@ type FileInfo struct {
 // FileOwner represents file ownership information.
 type FileOwner struct {
 	// ID is the user/account ID.
-	ID string
+	ID string `json:"id,omitempty"`
 
 	// DisplayName is a human-readable name.
-	DisplayName string
+	DisplayName string `json:"display_name,omitempty"`
 
 	// Email is the email address (if available).
-	Email string
+	Email string `json:"email,omitempty"`
 }
 
 // FilePermissions represents file permissions/ACL.
 type FilePermissions struct {
 	// Mode is Unix-style permissions (e.g., "0644").
-	Mode string
+	Mode string `json:"mode,omitempty"`
 
 	// ACL is the access control list.
-	ACL []ACLEntry
+	ACL []ACLEntry `json:"acl,omitempty"`
 
 	// IsPublic is a quick check for public access.
-	IsPublic bool
+	IsPublic bool `json:"is_public"`
 }
 
 // ACLEntry represents a single ACL entry.
 type ACLEntry struct {
 	// Grantee is the user/group ID.
-	Grantee string
+	Grantee string `json:"grantee"`
 
 	// Permission is the permission type (READ, WRITE, FULL_CONTROL, etc.).
-	Permission string
+	Permission string `json:"permission"`
 
 	// GranteeType is USER, GROUP, ALL_USERS, etc.
-	GranteeType string
+	GranteeType string `json:"grantee_type,omitempty"`
 }
 
 // WriteResult contains metadata about a completed write operation.
@@ -119,6 +140,12 @@ type WriteResult struct {
 	// Can be used for conditional requests and caching.
 	ETag string
 
+	// ContentType is the MIME type the backend reports for the file, as a
+	// later Stat would return it: the WithContentType option if given,
+	// otherwise the type detected by the driver. Backends that don't store a
+	// content type (local, SFTP, ZIP) report the detected type.
+	ContentType string
+
 	// ServerTimestamp is when the server completed the write.
 	ServerTimestamp time.Time
 
@@ -148,7 +175,8 @@ type FileReader interface {
 	// Stat returns file/directory metadata.
 	Stat(ctx context.Context, path string) (*FileInfo, error)
 
-	// ListContents lists directory contents.
+	// ListContents lists directory contents, sorted by name as
+	// SortFiles(files, SortByName, false) orders them.
 	// If recursive is true, includes all descendants.
 	ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error)
 }
@@ -212,8 +240,69 @@ type CanCopy interface {
 
 // CanMove indicates the filesystem supports native move/rename operations.
 // Native move is more efficient than copy+delete for same-backend operations.
+// Missing parent directories of dst are created, so after a successful Move
+// DirExists reports them on every backend (object stores treat any key under
+// a prefix as a directory). Moving a file onto itself leaves it unchanged
+// and only fails if it does not exist.
+//
+// By default an existing dst is replaced. With WithMoveNoOverwrite, Move
+// returns ErrExist (ErrCodeAlreadyExists) instead and leaves src and dst
+// untouched.
 type CanMove interface {
-	Move(ctx context.Context, src, dst string) error
+	Move(ctx context.Context, src, dst string, opts ...MoveOption) error
+}
+
+// MoveOptions configures a Move call.
+type MoveOptions struct {
+	// NoOverwrite fails the move with ErrExist when dst already exists.
+	NoOverwrite bool
+}
+
+// MoveOption is a functional option for configuring Move.
+type MoveOption func(*MoveOptions)
+
+// WithMoveNoOverwrite makes Move fail with ErrExist instead of replacing an
+// existing destination, like a rename that refuses to clobber. The check is
+// atomic on local (hard link), memory, zip, GCS (DoesNotExist precondition),
+// Azure (If-None-Match: *) and SFTP (plain SFTP rename). S3 has no
+// destination precondition for copies and checks with HeadObject first, so a
+// concurrent writer can still be overwritten.
+//
+// Example:
+//
+//	err := mover.Move(ctx, "uploads/tmp-123", "photos/cat.jpg", filekit.WithMoveNoOverwrite())
+//	if errors.Is(err, filekit.ErrExist) {
+//	    // pick another name
+//	}
+func WithMoveNoOverwrite() MoveOption {
+	return func(o *MoveOptions) {
+		o.NoOverwrite = true
+	}
+}
+
+// ApplyMoveOptions returns the MoveOptions set by opts. Drivers call it at the
+// start of Move.
+func ApplyMoveOptions(opts ...MoveOption) MoveOptions {
+	var o MoveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// CanWriteLocalFile indicates the filesystem can upload a file from the local
+// disk directly. Drivers detect the content type from the local file name
+// when WithContentType is not given. Use the WriteFile helper to upload to
+// any FileWriter, with or without this interface.
+//
+// Example:
+//
+//	if w, ok := fs.(CanWriteLocalFile); ok {
+//	    result, err := w.WriteFile(ctx, "reports/q1.pdf", "/tmp/q1.pdf")
+//	}
+type CanWriteLocalFile interface {
+	// WriteFile writes the contents of the local file at localPath to path.
+	WriteFile(ctx context.Context, path string, localPath string, opts ...Option) (*WriteResult, error)
 }
 
 // ============================================================================
@@ -345,6 +434,18 @@ type CanWatch interface {
 	Watch(ctx context.Context, pattern string) (ChangeToken, error)
 }
 
+// CanWatchMany indicates the filesystem can watch several filter patterns
+// with a single change token. Polling backends implement it to check all
+// patterns in one scan instead of running a poller per pattern.
+//
+// Prefer the WatchMany helper, which falls back to combining Watch tokens
+// for backends without this interface.
+type CanWatchMany interface {
+	// WatchMany creates a change token that signals when a file matching
+	// any of the filters is created, modified, or deleted.
+	WatchMany(ctx context.Context, filters ...string) (ChangeToken, error)
+}
+
 // ============================================================================
 // Range Read Interface
 // ============================================================================
@@ -378,3 +479,140 @@ type CanReadRange interface {
 	// Caller must close the reader.
 	ReadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error)
 }
+
+// ============================================================================
+// Tagging Interface
+// ============================================================================
+
+// CanTag indicates the filesystem supports object tags.
+// Unlike metadata set at write time (WithMetadata), tags can be changed
+// after creation without rewriting the object. Useful for lifecycle rules,
+// classification and cost allocation.
+//
+// Example:
+//
+//	if tagger, ok := fs.(CanTag); ok {
+//	    err := tagger.SetTags(ctx, "reports/q1.pdf", map[string]string{
+//	        "retention": "7y",
+//	    })
+//	}
+type CanTag interface {
+	// SetTags replaces all tags of a file. An empty map removes all tags.
+	SetTags(ctx context.Context, path string, tags map[string]string) error
+
+	// GetTags returns the tags of a file. Returns an empty map if the file has no tags.
+	GetTags(ctx context.Context, path string) (map[string]string, error)
+}
+
+// ============================================================================
+// Batch Stat Interface
+// ============================================================================
+
+// CanStatMany indicates the filesystem can fetch metadata for many paths in one call.
+// Cloud drivers issue the underlying requests concurrently, which is much faster
+// than calling Stat in a loop.
+//
+// Example:
+//
+//	if batcher, ok := fs.(CanStatMany); ok {
+//	    infos, errs := batcher.StatMany(ctx, []string{"a.txt", "b.txt"})
+//	}
+type CanStatMany interface {
+	// StatMany returns metadata for each path. Every path appears in exactly one
+	// of the returned maps: infos on success, errs on failure (e.g. not found).
+	// A failure for one path does not fail the whole batch.
+	StatMany(ctx context.Context, paths []string) (infos map[string]*FileInfo, errs map[string]error)
+}
+
+// ============================================================================
+// Batch Delete Interface
+// ============================================================================
+
+// CanDeleteMany indicates the filesystem can delete many files in one call.
+// S3 and Azure use their native batch delete APIs; other drivers issue
+// individual deletes concurrently.
+//
+// Example:
+//
+//	if batcher, ok := fs.(CanDeleteMany); ok {
+//	    deleted, errs := batcher.DeleteMany(ctx, []string{"a.txt", "b.txt"})
+//	}
+type CanDeleteMany interface {
+	// DeleteMany deletes each path. Every path appears in exactly one of the
+	// results: deleted on success, errs on failure (e.g. not found).
+	// A failure for one path does not abort the batch.
+	//
+	// Exception: S3's DeleteObjects succeeds for keys that do not exist and
+	// does not say which ones were missing, so the S3 driver reports missing
+	// paths in deleted rather than in errs.
+	DeleteMany(ctx context.Context, paths []string) (deleted []string, errs map[string]error)
+}
+
+// ============================================================================
+// Paginated Listing Interface
+// ============================================================================
+
+// ListPageOptions configures a single ListPage call.
+type ListPageOptions struct {
+	// MaxResults is the maximum number of entries to return.
+	// Zero or negative uses DefaultListPageSize.
+	MaxResults int
+
+	// ContinuationToken is the NextToken of the previous page, or empty for the first page.
+	// Tokens are opaque and only valid for the same filesystem, path and Recursive setting.
+	ContinuationToken string
+
+	// Recursive includes entries in subdirectories, as with ListContents.
+	Recursive bool
+}
+
+// ListPage is one page of directory entries.
+type ListPage struct {
+	// Entries holds at most MaxResults entries.
+	Entries []FileInfo
+
+	// NextToken continues the listing; empty when this is the last page.
+	NextToken string
+}
+
+// CanListPage indicates the filesystem can list a directory one page at a time.
+// S3, GCS and Azure map tokens onto their native continuation mechanism; local
+// and memory sort entries by path and use offset tokens.
+//
+// Example:
+//
+//	opts := filekit.ListPageOptions{MaxResults: 100}
+//	for {
+//	    page, err := lister.ListPage(ctx, "photos", opts)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    render(page.Entries)
+//	    if page.NextToken == "" {
+//	        break
+//	    }
+//	    opts.ContinuationToken = page.NextToken
+//	}
+type CanListPage interface {
+	ListPage(ctx context.Context, path string, opts ListPageOptions) (ListPage, error)
+}
+
+// ============================================================================
+// Health Check Interface
+// ============================================================================
+
+// HealthChecker indicates the filesystem can confirm its backend is
+// reachable, e.g. for liveness and readiness probes. Ping is cheap: cloud
+// drivers list at most one object, local stats its root, SFTP asks the server
+// for the working directory, and memory and ZIP always succeed.
+//
+// Example:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//	    if err := filekit.Ping(r.Context(), fs); err != nil {
+//	        http.Error(w, err.Error(), http.StatusServiceUnavailable)
+//	    }
+//	})
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
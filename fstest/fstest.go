@@ -0,0 +1,670 @@
+// Package fstest implements a conformance suite for filekit.FileSystem
+// implementations.
+//
+// Drivers run it from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		fstest.RunConformanceTests(t, func() filekit.FileSystem {
+//			return memory.New()
+//		})
+//	}
+//
+// The suite checks the core contract every backend must honour:
+//
+//   - Write/Read round-trips content and creates missing parent directories
+//   - Write refuses to replace an existing file with filekit.ErrExist unless
+//     WithOverwrite(true), or always replaces it for drivers run with
+//     WithOverwriteByDefault
+//   - Stat reports name, size and IsDir for files and directories, and a
+//     content type resolved as filekit.ResolveContentType does when it
+//     reports one at all
+//   - ListContents returns direct children, or the whole subtree when
+//     recursive, with paths relative to the filesystem root, sorted by name
+//     as filekit.SortFiles(files, filekit.SortByName, false) orders them
+//   - Read, ReadAll, Stat, Delete, ListContents and DeleteDir on a missing
+//     path return an error matching filekit.ErrNotExist
+//   - ListContents and DeleteDir on a file return filekit.ErrNotDir; Read and
+//     Delete on a directory return filekit.ErrIsDir
+//   - Paths that escape the root with ".." are rejected with
+//     filekit.ErrCodePermission by every method (see TestPathEscapes)
+//
+// Optional capabilities are only checked when the filesystem implements them:
+//
+//   - filekit.CanCopy: dst gets the content of src and src is left in
+//     place; a missing src returns filekit.ErrNotExist
+//   - filekit.CanMove: dst gets the content of src, src is removed and the
+//     parent directories of dst are reported by DirExists; an existing dst is
+//     replaced, or left alone with filekit.ErrExist under
+//     filekit.WithMoveNoOverwrite
+//   - filekit.CanWriteLocalFile: WriteFile uploads a local file and reports
+//     its size in WriteResult.BytesWritten
+//   - filekit.CanChecksum: ChecksumSHA256 returns the hex SHA-256 of the
+//     content
+//   - filekit.CanReadRange: ReadRange returns exactly the requested bytes,
+//     and a length of 0 reads to the end
+//
+// A capability that is implemented but returns filekit.ErrCodeNotSupported
+// is skipped rather than failed. Capabilities that need external services
+// (signed URLs, watching, chunked uploads) are not covered.
+package fstest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+)
+
+// Option configures RunConformanceTests for behaviour that legitimately
+// differs between drivers.
+type Option func(*config)
+
+type config struct {
+	overwriteByDefault bool
+}
+
+// WithOverwriteByDefault declares that Write replaces an existing file even
+// without filekit.WithOverwrite(true), as the local driver and the cloud
+// object stores do.
+func WithOverwriteByDefault() Option {
+	return func(c *config) {
+		c.overwriteByDefault = true
+	}
+}
+
+// RunConformanceTests runs the conformance suite against the filesystems
+// returned by newFS. Each subtest calls newFS once and expects an empty
+// filesystem, so newFS must not share state between calls.
+func RunConformanceTests(t *testing.T, newFS func() filekit.FileSystem, opts ...Option) {
+	t.Helper()
+
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, fs filekit.FileSystem)
+	}{
+		{"WriteRead", testWriteRead},
+		{"Overwrite", func(t *testing.T, fs filekit.FileSystem) { testOverwrite(t, fs, cfg.overwriteByDefault) }},
+		{"Stat", testStat},
+		{"ContentType", testContentType},
+		{"ListContents", testListContents},
+		{"ListOrder", testListOrder},
+		{"Delete", testDelete},
+		{"Dirs", testDirs},
+		{"NotExist", testNotExist},
+		{"TypeMismatch", testTypeMismatch},
+		{"PathTraversal", testPathTraversal},
+		{"Copy", testCopy},
+		{"Move", testMove},
+		{"MoveOverwrite", testMoveOverwrite},
+		{"WriteFile", testWriteFile},
+		{"Checksum", testChecksum},
+		{"ReadRange", testReadRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newFS())
+		})
+	}
+}
+
+// ============================================================================
+// Core contract
+// ============================================================================
+
+func testWriteRead(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+
+	result, err := fs.Write(ctx, "docs/2024/report.txt", strings.NewReader("quarterly"))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if result != nil && result.BytesWritten != int64(len("quarterly")) {
+		t.Errorf("WriteResult.BytesWritten = %d, want %d", result.BytesWritten, len("quarterly"))
+	}
+
+	data, err := fs.ReadAll(ctx, "docs/2024/report.txt")
+	if err != nil || string(data) != "quarterly" {
+		t.Errorf("ReadAll = %q, %v; want %q", data, err, "quarterly")
+	}
+
+	rc, err := fs.Read(ctx, "docs/2024/report.txt")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	data, err = io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "quarterly" {
+		t.Errorf("Read = %q, %v; want %q", data, err, "quarterly")
+	}
+
+	if exists, err := fs.FileExists(ctx, "docs/2024/report.txt"); err != nil || !exists {
+		t.Errorf("FileExists = %v, %v; want true", exists, err)
+	}
+	for _, dir := range []string{"docs", "docs/2024"} {
+		if exists, err := fs.DirExists(ctx, dir); err != nil || !exists {
+			t.Errorf("DirExists(%s) after Write = %v, %v; want true", dir, exists, err)
+		}
+	}
+
+	// Empty files are files too
+	if _, err := fs.Write(ctx, "empty.txt", strings.NewReader("")); err != nil {
+		t.Fatalf("Write(empty.txt): %v", err)
+	}
+	if data, err := fs.ReadAll(ctx, "empty.txt"); err != nil || len(data) != 0 {
+		t.Errorf("ReadAll(empty.txt) = %q, %v; want empty", data, err)
+	}
+}
+
+func testOverwrite(t *testing.T, fs filekit.FileSystem, byDefault bool) {
+	ctx := context.Background()
+	write(t, fs, "a.txt", "first")
+
+	_, err := fs.Write(ctx, "a.txt", strings.NewReader("second"))
+	if byDefault {
+		if err != nil {
+			t.Errorf("Write over an existing file = %v, want it replaced", err)
+		}
+		if data, _ := fs.ReadAll(ctx, "a.txt"); string(data) != "second" {
+			t.Errorf("content after overwrite = %q, want %q", data, "second")
+		}
+	} else {
+		if !errors.Is(err, filekit.ErrExist) || !filekit.IsCode(err, filekit.ErrCodeAlreadyExists) {
+			t.Errorf("Write over an existing file = %v, want ErrExist", err)
+		}
+		if data, _ := fs.ReadAll(ctx, "a.txt"); string(data) != "first" {
+			t.Errorf("content after a refused overwrite = %q, want %q", data, "first")
+		}
+	}
+
+	if _, err := fs.Write(ctx, "a.txt", strings.NewReader("third"), filekit.WithOverwrite(true)); err != nil {
+		t.Fatalf("Write with overwrite: %v", err)
+	}
+	if data, _ := fs.ReadAll(ctx, "a.txt"); string(data) != "third" {
+		t.Errorf("content after overwrite = %q, want %q", data, "third")
+	}
+}
+
+func testStat(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	write(t, fs, "dir/file.txt", "12345")
+
+	info, err := fs.Stat(ctx, "dir/file.txt")
+	if err != nil {
+		t.Fatalf("Stat(file): %v", err)
+	}
+	if info.Name != "file.txt" || info.Size != 5 || info.IsDir {
+		t.Errorf("Stat(file) = {Name: %q, Size: %d, IsDir: %v}, want {file.txt 5 false}", info.Name, info.Size, info.IsDir)
+	}
+
+	info, err = fs.Stat(ctx, "dir")
+	if err != nil {
+		t.Fatalf("Stat(dir): %v", err)
+	}
+	if info.Name != "dir" || !info.IsDir {
+		t.Errorf("Stat(dir) = {Name: %q, IsDir: %v}, want {dir true}", info.Name, info.IsDir)
+	}
+}
+
+func testContentType(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	write(t, fs, "data.json", `{"ok": true}`)
+
+	info, err := fs.Stat(ctx, "data.json")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ContentType == "" {
+		t.Skip("filesystem does not report content types")
+	}
+	if contentType, _, _ := strings.Cut(info.ContentType, ";"); contentType != "application/json" {
+		t.Errorf("Stat(data.json).ContentType = %q, want application/json", info.ContentType)
+	}
+}
+
+func testListContents(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	for _, p := range []string{"top.txt", "dir/a.txt", "dir/b.txt", "dir/sub/c.txt"} {
+		write(t, fs, p, p)
+	}
+
+	entries, err := fs.ListContents(ctx, "dir", false)
+	if err != nil {
+		t.Fatalf("ListContents(dir): %v", err)
+	}
+	files, dirs := split(entries)
+	if !slices.Equal(files, []string{"dir/a.txt", "dir/b.txt"}) || !slices.Equal(dirs, []string{"dir/sub"}) {
+		t.Errorf("ListContents(dir) = files %v, dirs %v; want [dir/a.txt dir/b.txt], [dir/sub]", files, dirs)
+	}
+
+	entries, err = fs.ListContents(ctx, "dir", true)
+	if err != nil {
+		t.Fatalf("ListContents(dir, recursive): %v", err)
+	}
+	files, _ = split(entries)
+	if !slices.Equal(files, []string{"dir/a.txt", "dir/b.txt", "dir/sub/c.txt"}) {
+		t.Errorf("ListContents(dir, recursive) files = %v, want [dir/a.txt dir/b.txt dir/sub/c.txt]", files)
+	}
+
+	entries, err = fs.ListContents(ctx, "", false)
+	if err != nil {
+		t.Fatalf("ListContents(root): %v", err)
+	}
+	files, dirs = split(entries)
+	if !slices.Equal(files, []string{"top.txt"}) || !slices.Equal(dirs, []string{"dir"}) {
+		t.Errorf("ListContents(root) = files %v, dirs %v; want [top.txt], [dir]", files, dirs)
+	}
+}
+
+func testListOrder(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	for _, p := range []string{"dir/c.txt", "dir/a/z.txt", "dir/b.txt", "dir/b/a.txt"} {
+		write(t, fs, p, p)
+	}
+
+	for _, recursive := range []bool{false, true} {
+		entries, err := fs.ListContents(ctx, "dir", recursive)
+		if err != nil {
+			t.Fatalf("ListContents(dir, %t): %v", recursive, err)
+		}
+		got := paths(entries)
+		filekit.SortFiles(entries, filekit.SortByName, false)
+		if want := paths(entries); !slices.Equal(got, want) {
+			t.Errorf("ListContents(dir, %t) = %v, want sorted by name %v", recursive, got, want)
+		}
+	}
+}
+
+func testDelete(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	write(t, fs, "dir/a.txt", "a")
+	write(t, fs, "dir/b.txt", "b")
+
+	if err := fs.Delete(ctx, "dir/a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if exists, _ := fs.FileExists(ctx, "dir/a.txt"); exists {
+		t.Error("FileExists after Delete = true")
+	}
+	if _, err := fs.ReadAll(ctx, "dir/a.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("ReadAll after Delete = %v, want not found", err)
+	}
+	if data, _ := fs.ReadAll(ctx, "dir/b.txt"); string(data) != "b" {
+		t.Errorf("sibling after Delete = %q, want it untouched", data)
+	}
+}
+
+func testDirs(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+
+	if err := fs.CreateDir(ctx, "made/nested"); err != nil {
+		t.Fatalf("CreateDir: %v", err)
+	}
+	if exists, err := fs.DirExists(ctx, "made/nested"); err != nil || !exists {
+		t.Errorf("DirExists after CreateDir = %v, %v; want true", exists, err)
+	}
+	if entries, err := fs.ListContents(ctx, "made/nested", false); err != nil || len(entries) != 0 {
+		t.Errorf("ListContents(empty dir) = %v, %v; want no entries", entries, err)
+	}
+
+	write(t, fs, "tree/a.txt", "a")
+	write(t, fs, "tree/sub/b.txt", "b")
+	write(t, fs, "keep.txt", "keep")
+	if exists, _ := fs.FileExists(ctx, "tree"); exists {
+		t.Error("FileExists(directory) = true, want false")
+	}
+	if exists, _ := fs.DirExists(ctx, "keep.txt"); exists {
+		t.Error("DirExists(file) = true, want false")
+	}
+
+	if err := fs.DeleteDir(ctx, "tree"); err != nil {
+		t.Fatalf("DeleteDir: %v", err)
+	}
+	for _, p := range []string{"tree/a.txt", "tree/sub/b.txt"} {
+		if exists, _ := fs.FileExists(ctx, p); exists {
+			t.Errorf("FileExists(%s) after DeleteDir = true", p)
+		}
+	}
+	if exists, _ := fs.DirExists(ctx, "tree"); exists {
+		t.Error("DirExists after DeleteDir = true")
+	}
+	if exists, _ := fs.FileExists(ctx, "keep.txt"); !exists {
+		t.Error("DeleteDir removed a file outside the directory")
+	}
+}
+
+func testNotExist(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+
+	check := func(op string, err error) {
+		t.Helper()
+		if !errors.Is(err, filekit.ErrNotExist) || !filekit.IsNotFound(err) {
+			t.Errorf("%s(missing) = %v, want ErrNotExist", op, err)
+		}
+	}
+
+	_, err := fs.Read(ctx, "missing.txt")
+	check("Read", err)
+	_, err = fs.ReadAll(ctx, "missing.txt")
+	check("ReadAll", err)
+	_, err = fs.Stat(ctx, "missing.txt")
+	check("Stat", err)
+	check("Delete", fs.Delete(ctx, "missing.txt"))
+	_, err = fs.ListContents(ctx, "missing", false)
+	check("ListContents", err)
+	check("DeleteDir", fs.DeleteDir(ctx, "missing"))
+
+	if exists, err := fs.FileExists(ctx, "missing.txt"); err != nil || exists {
+		t.Errorf("FileExists(missing) = %v, %v; want false, nil", exists, err)
+	}
+	if exists, err := fs.DirExists(ctx, "missing"); err != nil || exists {
+		t.Errorf("DirExists(missing) = %v, %v; want false, nil", exists, err)
+	}
+}
+
+func testTypeMismatch(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	write(t, fs, "dir/file.txt", "data")
+
+	_, err := fs.ListContents(ctx, "dir/file.txt", false)
+	if !errors.Is(err, filekit.ErrNotDir) {
+		t.Errorf("ListContents(file) = %v, want ErrNotDir", err)
+	}
+	if err := fs.DeleteDir(ctx, "dir/file.txt"); !errors.Is(err, filekit.ErrNotDir) {
+		t.Errorf("DeleteDir(file) = %v, want ErrNotDir", err)
+	}
+	if exists, _ := fs.FileExists(ctx, "dir/file.txt"); !exists {
+		t.Error("DeleteDir(file) removed the file")
+	}
+
+	if _, err := fs.ReadAll(ctx, "dir"); !errors.Is(err, filekit.ErrIsDir) {
+		t.Errorf("ReadAll(dir) = %v, want ErrIsDir", err)
+	}
+	if err := fs.Delete(ctx, "dir"); !errors.Is(err, filekit.ErrIsDir) {
+		t.Errorf("Delete(dir) = %v, want ErrIsDir", err)
+	}
+	if exists, _ := fs.FileExists(ctx, "dir/file.txt"); !exists {
+		t.Error("Delete(dir) removed the directory's contents")
+	}
+}
+
+func testPathTraversal(t *testing.T, fs filekit.FileSystem) {
+	ctx := context.Background()
+	write(t, fs, "inside/a.txt", "a")
+
+	TestPathEscapes(t, fs)
+
+	// Paths are normalized as by filekit.CleanPath; ".." that stays inside the
+	// root is an ordinary path
+	for _, p := range []string{"inside/../inside/a.txt", "./inside/a.txt", "inside//a.txt", "/inside/a.txt"} {
+		if data, err := fs.ReadAll(ctx, p); err != nil || string(data) != "a" {
+			t.Errorf("ReadAll(%q) = %q, %v; want %q", p, data, err, "a")
+		}
+	}
+}
+
+// escapingPaths are paths that leave the filesystem root once cleaned.
+var escapingPaths = []string{"../escape.txt", "inside/../../escape.txt", "/../escape.txt"}
+
+// TestPathEscapes checks that every FileSystem method, and Copy and Move when
+// fs implements them, rejects paths that escape the root with ".." with
+// filekit.ErrCodePermission. The paths are rejected before the backend is
+// reached, so fs may be backed by a server that fails on any request.
+func TestPathEscapes(t *testing.T, fs filekit.FileSystem) {
+	t.Helper()
+	ctx := context.Background()
+
+	check := func(op, p string, err error) {
+		t.Helper()
+		if !filekit.IsCode(err, filekit.ErrCodePermission) {
+			t.Errorf("%s(%q) = %v, want ErrCodePermission", op, p, err)
+		}
+	}
+	for _, p := range escapingPaths {
+		_, err := fs.Write(ctx, p, strings.NewReader("x"))
+		check("Write", p, err)
+		_, err = fs.Read(ctx, p)
+		check("Read", p, err)
+		_, err = fs.ReadAll(ctx, p)
+		check("ReadAll", p, err)
+		_, err = fs.Stat(ctx, p)
+		check("Stat", p, err)
+		_, err = fs.FileExists(ctx, p)
+		check("FileExists", p, err)
+		_, err = fs.DirExists(ctx, p)
+		check("DirExists", p, err)
+		_, err = fs.ListContents(ctx, p, false)
+		check("ListContents", p, err)
+		check("Delete", p, fs.Delete(ctx, p))
+		check("CreateDir", p, fs.CreateDir(ctx, p))
+		check("DeleteDir", p, fs.DeleteDir(ctx, p))
+		if copier, ok := fs.(filekit.CanCopy); ok {
+			check("Copy", p, copier.Copy(ctx, p, "inside/b.txt"))
+			check("Copy", p, copier.Copy(ctx, "inside/a.txt", p))
+		}
+		if mover, ok := fs.(filekit.CanMove); ok {
+			check("Move", p, mover.Move(ctx, p, "inside/b.txt"))
+			check("Move", p, mover.Move(ctx, "inside/a.txt", p))
+		}
+	}
+}
+
+// ============================================================================
+// Optional capabilities
+// ============================================================================
+
+func testCopy(t *testing.T, fs filekit.FileSystem) {
+	copier, ok := fs.(filekit.CanCopy)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanCopy")
+	}
+	ctx := context.Background()
+	write(t, fs, "src.txt", "payload")
+
+	err := copier.Copy(ctx, "src.txt", "copies/dst.txt")
+	skipIfNotSupported(t, err)
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if data, err := fs.ReadAll(ctx, "copies/dst.txt"); err != nil || string(data) != "payload" {
+		t.Errorf("ReadAll(dst) = %q, %v; want %q", data, err, "payload")
+	}
+	if exists, _ := fs.FileExists(ctx, "src.txt"); !exists {
+		t.Error("Copy removed the source")
+	}
+	if err := copier.Copy(ctx, "missing.txt", "other.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("Copy(missing) = %v, want not found", err)
+	}
+}
+
+func testMove(t *testing.T, fs filekit.FileSystem) {
+	mover, ok := fs.(filekit.CanMove)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanMove")
+	}
+	ctx := context.Background()
+	write(t, fs, "src.txt", "payload")
+
+	err := mover.Move(ctx, "src.txt", "archive/2024/dst.txt")
+	skipIfNotSupported(t, err)
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if data, err := fs.ReadAll(ctx, "archive/2024/dst.txt"); err != nil || string(data) != "payload" {
+		t.Errorf("ReadAll(dst) = %q, %v; want %q", data, err, "payload")
+	}
+	if exists, _ := fs.FileExists(ctx, "src.txt"); exists {
+		t.Error("Move left the source in place")
+	}
+	for _, dir := range []string{"archive", "archive/2024"} {
+		if exists, err := fs.DirExists(ctx, dir); err != nil || !exists {
+			t.Errorf("DirExists(%s) after Move = %v, %v; want true", dir, exists, err)
+		}
+	}
+	if err := mover.Move(ctx, "missing.txt", "other.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("Move(missing) = %v, want not found", err)
+	}
+}
+
+func testMoveOverwrite(t *testing.T, fs filekit.FileSystem) {
+	mover, ok := fs.(filekit.CanMove)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanMove")
+	}
+	ctx := context.Background()
+	write(t, fs, "a.txt", "a")
+	write(t, fs, "b.txt", "b")
+	write(t, fs, "c.txt", "c")
+
+	err := mover.Move(ctx, "a.txt", "b.txt", filekit.WithMoveNoOverwrite())
+	skipIfNotSupported(t, err)
+	if !errors.Is(err, filekit.ErrExist) {
+		t.Errorf("Move(no overwrite) onto existing = %v, want ErrExist", err)
+	}
+	if data, err := fs.ReadAll(ctx, "a.txt"); err != nil || string(data) != "a" {
+		t.Errorf("ReadAll(src) after refused Move = %q, %v; want %q", data, err, "a")
+	}
+	if data, err := fs.ReadAll(ctx, "b.txt"); err != nil || string(data) != "b" {
+		t.Errorf("ReadAll(dst) after refused Move = %q, %v; want %q", data, err, "b")
+	}
+
+	if err := mover.Move(ctx, "a.txt", "new.txt", filekit.WithMoveNoOverwrite()); err != nil {
+		t.Errorf("Move(no overwrite) onto new path: %v", err)
+	}
+
+	if err := mover.Move(ctx, "c.txt", "b.txt"); err != nil {
+		t.Fatalf("Move onto existing: %v", err)
+	}
+	if data, err := fs.ReadAll(ctx, "b.txt"); err != nil || string(data) != "c" {
+		t.Errorf("ReadAll(dst) after Move = %q, %v; want %q", data, err, "c")
+	}
+}
+
+func testWriteFile(t *testing.T, fs filekit.FileSystem) {
+	w, ok := fs.(filekit.CanWriteLocalFile)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanWriteLocalFile")
+	}
+	ctx := context.Background()
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(localPath, []byte("from local disk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := w.WriteFile(ctx, "uploads/upload.txt", localPath)
+	skipIfNotSupported(t, err)
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if result == nil || result.BytesWritten != int64(len("from local disk")) {
+		t.Errorf("WriteFile result = %+v, want BytesWritten %d", result, len("from local disk"))
+	}
+	if data, err := fs.ReadAll(ctx, "uploads/upload.txt"); err != nil || string(data) != "from local disk" {
+		t.Errorf("ReadAll = %q, %v; want %q", data, err, "from local disk")
+	}
+	if _, err := w.WriteFile(ctx, "missing.txt", filepath.Join(t.TempDir(), "missing.txt")); !filekit.IsNotFound(err) {
+		t.Errorf("WriteFile(missing local file) = %v, want not found", err)
+	}
+}
+
+func testChecksum(t *testing.T, fs filekit.FileSystem) {
+	summer, ok := fs.(filekit.CanChecksum)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanChecksum")
+	}
+	ctx := context.Background()
+	write(t, fs, "sum.txt", "checksum me")
+
+	got, err := summer.Checksum(ctx, "sum.txt", filekit.ChecksumSHA256)
+	skipIfNotSupported(t, err)
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	sum := sha256.Sum256([]byte("checksum me"))
+	if want := hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("Checksum = %s, want %s", got, want)
+	}
+}
+
+func testReadRange(t *testing.T, fs filekit.FileSystem) {
+	ranger, ok := fs.(filekit.CanReadRange)
+	if !ok {
+		t.Skip("filesystem does not implement filekit.CanReadRange")
+	}
+	ctx := context.Background()
+	write(t, fs, "range.txt", "0123456789")
+
+	for _, tt := range []struct {
+		offset, length int64
+		want           string
+	}{
+		{2, 3, "234"},
+		{7, 0, "789"},
+	} {
+		rc, err := ranger.ReadRange(ctx, "range.txt", tt.offset, tt.length)
+		skipIfNotSupported(t, err)
+		if err != nil {
+			t.Fatalf("ReadRange(%d, %d): %v", tt.offset, tt.length, err)
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rc)
+		rc.Close()
+		if err != nil || buf.String() != tt.want {
+			t.Errorf("ReadRange(%d, %d) = %q, %v; want %q", tt.offset, tt.length, buf.String(), err, tt.want)
+		}
+	}
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func write(t *testing.T, fs filekit.FileSystem, path, content string) {
+	t.Helper()
+	if _, err := fs.Write(context.Background(), path, strings.NewReader(content)); err != nil {
+		t.Fatalf("Write(%s): %v", path, err)
+	}
+}
+
+// paths returns the paths of entries in order.
+func paths(entries []filekit.FileInfo) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Path)
+	}
+	return out
+}
+
+// split returns the sorted file and directory paths of entries.
+func split(entries []filekit.FileInfo) (files, dirs []string) {
+	for _, e := range entries {
+		if e.IsDir {
+			dirs = append(dirs, e.Path)
+		} else {
+			files = append(files, e.Path)
+		}
+	}
+	slices.Sort(files)
+	slices.Sort(dirs)
+	return files, dirs
+}
+
+func skipIfNotSupported(t *testing.T, err error) {
+	t.Helper()
+	if filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+		t.Skipf("capability reports not supported: %v", err)
+	}
+}
@@ -10,12 +10,14 @@ require (
 	github.com/gobeaver/filekit/driver/local v0.0.4
 	github.com/gobeaver/filekit/driver/memory v0.0.4
 	github.com/gobeaver/filekit/filevalidator v0.0.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/gobwas/glob v0.2.3 // indirect
 	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
 )
 
 // Local submodules
@@ -4,7 +4,10 @@ import (
 	"mime"
 	"net/http"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/gobeaver/filekit/filevalidator"
 )
 
 // Common MIME types
@@ -182,3 +185,62 @@ func GetFileExtensionForMIME(contentType string) string {
 	// Fall back to .bin for binary data
 	return ".bin"
 }
+
+// MIMEForExtension returns the MIME type for a file extension such as ".heic"
+// or "HEIC", or "" if it is unknown. It consults the same tables as
+// GuessContentType and filevalidator (which cover types like HEIC that the
+// standard library does not know), then the mime package.
+func MIMEForExtension(ext string) string {
+	ext = normalizeExtension(ext)
+	if ext == "" {
+		return ""
+	}
+	if contentType, ok := extensionToMIME[ext]; ok {
+		return contentType
+	}
+	if contentType := filevalidator.MIMETypeForExtension(ext); contentType != "" {
+		return contentType
+	}
+	contentType, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+	return strings.TrimSpace(contentType)
+}
+
+// ExtensionsForMIME returns the known extensions for a MIME type, with the
+// one GetFileExtensionForMIME picks first and the rest sorted, e.g.
+// [".jpg" ".jpeg"] for "image/jpeg". Parameters such as "; charset=utf-8" are
+// ignored. It returns nil for unknown types.
+func ExtensionsForMIME(contentType string) []string {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+
+	exts := filevalidator.ExtensionsForMIMEType(contentType)
+	for ext, mimeType := range extensionToMIME {
+		if mimeType == contentType && !slices.Contains(exts, ext) {
+			exts = append(exts, ext)
+		}
+	}
+	if len(exts) == 0 {
+		return nil
+	}
+
+	preferred := GetFileExtensionForMIME(contentType)
+	slices.SortFunc(exts, func(a, b string) int {
+		switch {
+		case a == preferred:
+			return -1
+		case b == preferred:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return exts
+}
+
+// normalizeExtension lowercases ext and adds the leading dot if it is missing
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,47 @@
+package filekit_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+)
+
+func TestMIMEForExtension(t *testing.T) {
+	tests := map[string]string{
+		".heic":   "image/heic",
+		"HEIC":    "image/heic",
+		".webp":   "image/webp",
+		".JPG":    "image/jpeg",
+		".woff2":  "font/woff2",
+		".m4a":    "audio/mp4",
+		".nope42": "",
+		"":        "",
+	}
+	for ext, want := range tests {
+		if got := filekit.MIMEForExtension(ext); got != want {
+			t.Errorf("MIMEForExtension(%q) = %q, want %q", ext, got, want)
+		}
+	}
+}
+
+func TestExtensionsForMIME(t *testing.T) {
+	tests := map[string][]string{
+		"image/jpeg":               {".jpg", ".jpeg"},
+		"image/heic":               {".heic"},
+		"text/html; charset=utf-8": {".html", ".htm"},
+		"application/x-unknown":    nil,
+	}
+	for contentType, want := range tests {
+		if got := filekit.ExtensionsForMIME(contentType); !slices.Equal(got, want) {
+			t.Errorf("ExtensionsForMIME(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+
+	// Every extension maps back to the type
+	for _, ext := range filekit.ExtensionsForMIME("image/tiff") {
+		if got := filekit.MIMEForExtension(ext); got != "image/tiff" {
+			t.Errorf("MIMEForExtension(%q) = %q, want image/tiff", ext, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package filekit
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Ping checks that the backend behind fs is reachable. Decorators that
+// expose Unwrap or Underlying are looked through until a HealthChecker is
+// found; a filesystem with no HealthChecker in its chain is assumed healthy.
+func Ping(ctx context.Context, fs FileSystem) error {
+	for fs != nil {
+		if err := ctx.Err(); err != nil {
+			return FromContext(ctx, "ping", "")
+		}
+		switch f := fs.(type) {
+		case HealthChecker:
+			return f.Ping(ctx)
+		case interface{ Unwrap() FileSystem }:
+			fs = f.Unwrap()
+		case interface{ Underlying() FileSystem }:
+			fs = f.Underlying()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// Ping checks every mounted filesystem concurrently (see the package-level
+// Ping). Failures are wrapped with the mount path, so the error names the
+// mounts that failed; with more than one failure it is a *MultiError.
+func (m *MountManager) Ping(ctx context.Context) error {
+	mounts := m.Mounts()
+	paths := make([]string, 0, len(mounts))
+	for p := range mounts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	results := make([]error, len(paths))
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Ping(ctx, mounts[p]); err != nil {
+				results[i] = WrapPathErr("ping", p, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	errs := NewMultiError("ping")
+	for _, err := range results {
+		errs.Add(err)
+	}
+	return errs.Err()
+}
+
+var _ HealthChecker = (*MountManager)(nil)
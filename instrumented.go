@@ -0,0 +1,335 @@
+package filekit
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// InstrumentedFileSystem Decorator
+// ============================================================================
+
+// Observer receives metrics from an InstrumentedFileSystem. Implementations
+// must be safe for concurrent use.
+//
+// Operation names match the Op of the errors drivers return: "read",
+// "readall", "write", "delete", "fileexists", "direxists", "stat",
+// "listcontents", "createdir", "deletedir", and for optional capabilities
+// "copy", "move", "checksum", "checksums", "read-range", "statmany",
+// "deletemany", "signed-url" and "signed-upload-url".
+type Observer interface {
+	// ObserveOp is called once per operation with its latency and result.
+	// For Read and ReadRange, dur covers opening the stream.
+	ObserveOp(op string, dur time.Duration, err error)
+
+	// ObserveBytes reports bytes transferred by "read", "readall", "write"
+	// and "read-range". Streams are reported when closed (reads) or when
+	// the write returns, including partial transfers of failed operations.
+	ObserveBytes(op string, n int64)
+}
+
+// InstrumentedFileSystem wraps a FileSystem and reports the latency, errors
+// and transferred bytes of every operation to an Observer.
+//
+// Example:
+//
+//	observer := prometheus.NewObserver(prometheus.DefaultRegisterer) // github.com/gobeaver/filekit/observer/prometheus
+//	fs = filekit.NewInstrumentedFileSystem(fs, observer)
+type InstrumentedFileSystem struct {
+	fs       FileSystem
+	observer Observer
+}
+
+// NewInstrumentedFileSystem creates an instrumenting wrapper around a FileSystem.
+func NewInstrumentedFileSystem(fs FileSystem, observer Observer) *InstrumentedFileSystem {
+	return &InstrumentedFileSystem{
+		fs:       fs,
+		observer: observer,
+	}
+}
+
+// Unwrap returns the underlying FileSystem.
+func (i *InstrumentedFileSystem) Unwrap() FileSystem {
+	return i.fs
+}
+
+// observe reports an operation that started at start
+func (i *InstrumentedFileSystem) observe(op string, start time.Time, err error) {
+	i.observer.ObserveOp(op, time.Since(start), err)
+}
+
+// ============================================================================
+// FileSystem Interface
+// ============================================================================
+
+// Read delegates to the underlying filesystem. Bytes read are reported when
+// the returned reader is closed.
+func (i *InstrumentedFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	start := time.Now()
+	rc, err := i.fs.Read(ctx, path)
+	i.observe("read", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &observedReader{rc: rc, op: "read", observer: i.observer}, nil
+}
+
+// ReadAll delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) ReadAll(ctx context.Context, path string) ([]byte, error) {
+	start := time.Now()
+	data, err := i.fs.ReadAll(ctx, path)
+	i.observe("readall", start, err)
+	i.observer.ObserveBytes("readall", int64(len(data)))
+	return data, err
+}
+
+// Write delegates to the underlying filesystem, counting the bytes it consumes.
+func (i *InstrumentedFileSystem) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
+	counter := &countingReader{r: content}
+	start := time.Now()
+	result, err := i.fs.Write(ctx, path, counter, options...)
+	i.observe("write", start, err)
+	i.observer.ObserveBytes("write", counter.n)
+	return result, err
+}
+
+// Delete delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) Delete(ctx context.Context, path string) error {
+	start := time.Now()
+	err := i.fs.Delete(ctx, path)
+	i.observe("delete", start, err)
+	return err
+}
+
+// FileExists delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) FileExists(ctx context.Context, path string) (bool, error) {
+	start := time.Now()
+	exists, err := i.fs.FileExists(ctx, path)
+	i.observe("fileexists", start, err)
+	return exists, err
+}
+
+// DirExists delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) DirExists(ctx context.Context, path string) (bool, error) {
+	start := time.Now()
+	exists, err := i.fs.DirExists(ctx, path)
+	i.observe("direxists", start, err)
+	return exists, err
+}
+
+// Stat delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) Stat(ctx context.Context, path string) (*FileInfo, error) {
+	start := time.Now()
+	info, err := i.fs.Stat(ctx, path)
+	i.observe("stat", start, err)
+	return info, err
+}
+
+// ListContents delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
+	start := time.Now()
+	files, err := i.fs.ListContents(ctx, path, recursive)
+	i.observe("listcontents", start, err)
+	return files, err
+}
+
+// CreateDir delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) CreateDir(ctx context.Context, path string) error {
+	start := time.Now()
+	err := i.fs.CreateDir(ctx, path)
+	i.observe("createdir", start, err)
+	return err
+}
+
+// DeleteDir delegates to the underlying filesystem.
+func (i *InstrumentedFileSystem) DeleteDir(ctx context.Context, path string) error {
+	start := time.Now()
+	err := i.fs.DeleteDir(ctx, path)
+	i.observe("deletedir", start, err)
+	return err
+}
+
+// ============================================================================
+// Optional Interface Delegation
+// ============================================================================
+
+// Copy delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) Copy(ctx context.Context, src, dst string) error {
+	start := time.Now()
+	var err error
+	if copier, ok := i.fs.(CanCopy); ok {
+		err = copier.Copy(ctx, src, dst)
+	} else {
+		err = NewPathError("copy", src, ErrCodeNotSupported, "underlying filesystem does not support copy")
+	}
+	i.observe("copy", start, err)
+	return err
+}
+
+// Move delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
+	start := time.Now()
+	var err error
+	if mover, ok := i.fs.(CanMove); ok {
+		err = mover.Move(ctx, src, dst, opts...)
+	} else {
+		err = NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
+	}
+	i.observe("move", start, err)
+	return err
+}
+
+// Checksum delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) Checksum(ctx context.Context, path string, algorithm ChecksumAlgorithm) (string, error) {
+	start := time.Now()
+	var sum string
+	var err error
+	if checksummer, ok := i.fs.(CanChecksum); ok {
+		sum, err = checksummer.Checksum(ctx, path, algorithm)
+	} else {
+		err = NewPathError("checksum", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
+	}
+	i.observe("checksum", start, err)
+	return sum, err
+}
+
+// Checksums delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) Checksums(ctx context.Context, path string, algorithms []ChecksumAlgorithm) (map[ChecksumAlgorithm]string, error) {
+	start := time.Now()
+	var sums map[ChecksumAlgorithm]string
+	var err error
+	if checksummer, ok := i.fs.(CanChecksum); ok {
+		sums, err = checksummer.Checksums(ctx, path, algorithms)
+	} else {
+		err = NewPathError("checksums", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
+	}
+	i.observe("checksums", start, err)
+	return sums, err
+}
+
+// ReadRange delegates to the underlying filesystem if supported. Bytes read
+// are reported when the returned reader is closed.
+func (i *InstrumentedFileSystem) ReadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	start := time.Now()
+	var rc io.ReadCloser
+	var err error
+	if ranger, ok := i.fs.(CanReadRange); ok {
+		rc, err = ranger.ReadRange(ctx, path, offset, length)
+	} else {
+		err = NewPathError("read-range", path, ErrCodeNotSupported, "underlying filesystem does not support range reads")
+	}
+	i.observe("read-range", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &observedReader{rc: rc, op: "read-range", observer: i.observer}, nil
+}
+
+// StatMany delegates to the underlying filesystem, falling back to Stat per
+// path. The operation is reported once, with the first error if any.
+func (i *InstrumentedFileSystem) StatMany(ctx context.Context, paths []string) (map[string]*FileInfo, map[string]error) {
+	start := time.Now()
+	infos, errs := StatMany(ctx, i.fs, paths)
+	i.observe("statmany", start, firstError(paths, errs))
+	return infos, errs
+}
+
+// DeleteMany delegates to the underlying filesystem, falling back to Delete
+// per path. The operation is reported once, with the first error if any.
+func (i *InstrumentedFileSystem) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	start := time.Now()
+	deleted, errs := DeleteMany(ctx, i.fs, paths)
+	i.observe("deletemany", start, firstError(paths, errs))
+	return deleted, errs
+}
+
+// SignedURL delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) SignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	start := time.Now()
+	var url string
+	var err error
+	if signer, ok := i.fs.(CanSignURL); ok {
+		url, err = signer.SignedURL(ctx, path, expires)
+	} else {
+		err = NewPathError("signed-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
+	}
+	i.observe("signed-url", start, err)
+	return url, err
+}
+
+// SignedUploadURL delegates to the underlying filesystem if supported.
+func (i *InstrumentedFileSystem) SignedUploadURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	start := time.Now()
+	var url string
+	var err error
+	if signer, ok := i.fs.(CanSignURL); ok {
+		url, err = signer.SignedUploadURL(ctx, path, expires)
+	} else {
+		err = NewPathError("signed-upload-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
+	}
+	i.observe("signed-upload-url", start, err)
+	return url, err
+}
+
+// firstError returns the error of the first failed path in input order
+func firstError(paths []string, errs map[string]error) error {
+	for _, p := range paths {
+		if err, ok := errs[p]; ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Byte Counting
+// ============================================================================
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// observedReader reports the bytes read through it once, on Close
+type observedReader struct {
+	rc       io.ReadCloser
+	op       string
+	observer Observer
+	n        int64
+	once     sync.Once
+}
+
+func (o *observedReader) Read(p []byte) (int, error) {
+	n, err := o.rc.Read(p)
+	o.n += int64(n)
+	return n, err
+}
+
+func (o *observedReader) Close() error {
+	o.once.Do(func() { o.observer.ObserveBytes(o.op, o.n) })
+	return o.rc.Close()
+}
+
+// ============================================================================
+// Interface Assertions
+// ============================================================================
+
+var (
+	_ FileSystem    = (*InstrumentedFileSystem)(nil)
+	_ CanCopy       = (*InstrumentedFileSystem)(nil)
+	_ CanMove       = (*InstrumentedFileSystem)(nil)
+	_ CanChecksum   = (*InstrumentedFileSystem)(nil)
+	_ CanReadRange  = (*InstrumentedFileSystem)(nil)
+	_ CanStatMany   = (*InstrumentedFileSystem)(nil)
+	_ CanDeleteMany = (*InstrumentedFileSystem)(nil)
+	_ CanSignURL    = (*InstrumentedFileSystem)(nil)
+)
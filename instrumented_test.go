@@ -0,0 +1,130 @@
+package filekit_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// recordingObserver records every reported operation and byte count.
+type recordingObserver struct {
+	mu    sync.Mutex
+	ops   []observedOp
+	bytes map[string]int64
+}
+
+type observedOp struct {
+	op  string
+	dur time.Duration
+	err error
+}
+
+func (r *recordingObserver) ObserveOp(op string, dur time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, observedOp{op: op, dur: dur, err: err})
+}
+
+func (r *recordingObserver) ObserveBytes(op string, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.bytes == nil {
+		r.bytes = make(map[string]int64)
+	}
+	r.bytes[op] += n
+}
+
+// slowFS delays Stat so durations are measurable.
+type slowFS struct {
+	filekit.FileSystem
+}
+
+func (s slowFS) Stat(ctx context.Context, path string) (*filekit.FileInfo, error) {
+	time.Sleep(20 * time.Millisecond)
+	return s.FileSystem.Stat(ctx, path)
+}
+
+func TestInstrumentedFileSystem_ReportsOps(t *testing.T) {
+	ctx := context.Background()
+	obs := &recordingObserver{}
+	fs := filekit.NewInstrumentedFileSystem(slowFS{memory.New()}, obs)
+
+	if _, err := fs.Write(ctx, "docs/a.txt", strings.NewReader("hello world")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	rc, err := fs.Read(ctx, "docs/a.txt")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if _, err := io.ReadAll(rc); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	rc.Close()
+	if _, err := fs.ReadAll(ctx, "docs/a.txt"); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if _, err := fs.Stat(ctx, "docs/a.txt"); err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if _, err := fs.ListContents(ctx, "docs", false); err != nil {
+		t.Fatalf("ListContents failed: %v", err)
+	}
+	_, missingErr := fs.Stat(ctx, "missing.txt")
+	if err := fs.Delete(ctx, "docs/a.txt"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	wantOps := []string{"write", "read", "readall", "stat", "listcontents", "stat", "delete"}
+	if len(obs.ops) != len(wantOps) {
+		t.Fatalf("got %d ops %v, want %v", len(obs.ops), obs.ops, wantOps)
+	}
+	for i, want := range wantOps {
+		if obs.ops[i].op != want {
+			t.Errorf("op[%d] = %q, want %q", i, obs.ops[i].op, want)
+		}
+	}
+
+	if stat := obs.ops[3]; stat.dur < 20*time.Millisecond || stat.err != nil {
+		t.Errorf("stat reported dur=%v err=%v, want >= 20ms and no error", stat.dur, stat.err)
+	}
+	if failed := obs.ops[5]; failed.err == nil || failed.err != missingErr {
+		t.Errorf("missing stat reported err=%v, want %v", failed.err, missingErr)
+	}
+
+	for op, want := range map[string]int64{"write": 11, "read": 11, "readall": 11} {
+		if got := obs.bytes[op]; got != want {
+			t.Errorf("bytes[%s] = %d, want %d", op, got, want)
+		}
+	}
+}
+
+func TestInstrumentedFileSystem_OptionalCapabilities(t *testing.T) {
+	ctx := context.Background()
+	obs := &recordingObserver{}
+	mem := memory.New()
+	seedTree(t, mem, map[string]string{"a.txt": "alpha"})
+	fs := filekit.NewInstrumentedFileSystem(mem, obs)
+
+	if err := fs.Copy(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if _, err := fs.SignedURL(ctx, "a.txt", time.Minute); !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+		t.Errorf("SignedURL error = %v, want ErrCodeNotSupported", err)
+	}
+	if _, errs := fs.DeleteMany(ctx, []string{"a.txt", "b.txt"}); len(errs) != 0 {
+		t.Fatalf("DeleteMany failed: %v", errs)
+	}
+
+	if len(obs.ops) != 3 || obs.ops[0].op != "copy" || obs.ops[1].op != "signed-url" || obs.ops[2].op != "deletemany" {
+		t.Fatalf("unexpected ops: %v", obs.ops)
+	}
+	if obs.ops[1].err == nil {
+		t.Error("unsupported SignedURL must be reported as an error")
+	}
+}
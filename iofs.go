@@ -0,0 +1,257 @@
+package filekit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AsFS returns a read-only io/fs view of fsys for code that accepts fs.FS,
+// such as html/template.ParseFS, http.FS or fs.WalkDir.
+//
+// The returned value also implements fs.StatFS and fs.ReadDirFS. Files are
+// streamed with Read and support Seek (using ReadRange when available), so
+// http.FileServer can serve range requests. Directories are listed with
+// ListContents. Not-found errors match fs.ErrNotExist.
+//
+// Example:
+//
+//	tmpl, err := template.ParseFS(filekit.AsFS(fs), "templates/*.html")
+//	http.Handle("/static/", http.FileServer(http.FS(filekit.AsFS(fs))))
+func AsFS(fsys FileSystem) fs.FS {
+	return &ioFS{fs: fsys}
+}
+
+// ioFS adapts a FileSystem to fs.FS
+type ioFS struct {
+	fs FileSystem
+}
+
+var (
+	_ fs.FS        = (*ioFS)(nil)
+	_ fs.StatFS    = (*ioFS)(nil)
+	_ fs.ReadDirFS = (*ioFS)(nil)
+)
+
+// Open implements fs.FS
+func (f *ioFS) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &ioDir{fsys: f, name: name, info: info}, nil
+	}
+	return &ioFile{
+		fileReadSeeker: fileReadSeeker{
+			ctx:  context.Background(),
+			fs:   f.fs,
+			path: name,
+			size: info.Size(),
+		},
+		info: info,
+	}, nil
+}
+
+// Stat implements fs.StatFS
+func (f *ioFS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+// ReadDir implements fs.ReadDirFS. Entries are sorted by name.
+func (f *ioFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	info, err := f.stat("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return f.readDir(name)
+}
+
+func (f *ioFS) stat(op, name string) (fs.FileInfo, error) {
+	// FileSystem paths treat a backslash as a separator (see CleanPath), so
+	// io/fs names containing one would alias a different file
+	if !fs.ValidPath(name) || strings.Contains(name, `\`) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		// Not every driver can Stat its root
+		return ioFileInfo{name: ".", isDir: true}, nil
+	}
+
+	info, err := f.fs.Stat(context.Background(), name)
+	if err != nil {
+		return nil, toFSError(op, name, err)
+	}
+	return newIOFileInfo(info), nil
+}
+
+func (f *ioFS) readDir(name string) ([]fs.DirEntry, error) {
+	dir := name
+	if dir == "." {
+		dir = ""
+	}
+
+	infos, err := f.fs.ListContents(context.Background(), dir, false)
+	if err != nil {
+		return nil, toFSError("readdir", name, err)
+	}
+
+	entries := make([]fs.DirEntry, 0, len(infos))
+	for i := range infos {
+		info := newIOFileInfo(&infos[i])
+		if info.Name() == "" || info.Name() == "." {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// toFSError converts filekit errors to the io/fs sentinel errors
+func toFSError(op, name string, err error) error {
+	switch {
+	case IsCode(err, ErrCodeNotFound):
+		err = fs.ErrNotExist
+	case IsCode(err, ErrCodePermission):
+		err = fs.ErrPermission
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// ioFileInfo implements fs.FileInfo on top of a FileInfo
+type ioFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	sys     *FileInfo
+}
+
+func newIOFileInfo(info *FileInfo) ioFileInfo {
+	name := info.Name
+	if name == "" {
+		name = path.Base(strings.TrimSuffix(info.Path, "/"))
+	}
+	return ioFileInfo{
+		name:    name,
+		size:    info.Size,
+		modTime: info.ModTime,
+		isDir:   info.IsDir,
+		sys:     info,
+	}
+}
+
+func (i ioFileInfo) Name() string       { return i.name }
+func (i ioFileInfo) Size() int64        { return i.size }
+func (i ioFileInfo) ModTime() time.Time { return i.modTime }
+func (i ioFileInfo) IsDir() bool        { return i.isDir }
+
+// Sys returns the underlying *FileInfo (nil for the root directory)
+func (i ioFileInfo) Sys() any { return i.sys }
+
+// Mode reports read-only permissions; FileSystem does not expose Unix modes
+func (i ioFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// ioFile is an fs.File for a regular file. It reads lazily and supports Seek.
+type ioFile struct {
+	fileReadSeeker
+	info   fs.FileInfo
+	closed bool
+}
+
+func (f *ioFile) Stat() (fs.FileInfo, error) {
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.path, Err: fs.ErrClosed}
+	}
+	return f.info, nil
+}
+
+func (f *ioFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrClosed}
+	}
+	n, err := f.fileReadSeeker.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = toFSError("read", f.path, err)
+	}
+	return n, err
+}
+
+func (f *ioFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrClosed}
+	}
+	if whence != io.SeekStart && whence != io.SeekCurrent && whence != io.SeekEnd {
+		return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrInvalid}
+	}
+	return f.fileReadSeeker.Seek(offset, whence)
+}
+
+func (f *ioFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.path, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return f.fileReadSeeker.Close()
+}
+
+// ioDir is an fs.ReadDirFile for a directory. Entries are listed on the first
+// ReadDir call.
+type ioDir struct {
+	fsys    *ioFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	listed  bool
+	offset  int
+}
+
+func (d *ioDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *ioDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *ioDir) Close() error { return nil }
+
+// ReadDir implements fs.ReadDirFile
+func (d *ioDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.fsys.readDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.listed = true
+	}
+
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
@@ -0,0 +1,68 @@
+package filekit_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func newIOFSTree(t *testing.T) fs.FS {
+	t.Helper()
+	mem := memory.New()
+	seedTree(t, mem, map[string]string{
+		"index.html":           "<html>home</html>",
+		"css/site.css":         "body{}",
+		"js/app.js":            "console.log(1)",
+		"docs/guide/intro.txt": "intro",
+		"docs/readme.md":       "# readme",
+	})
+	return filekit.AsFS(mem)
+}
+
+func TestAsFS_TestFS(t *testing.T) {
+	fsys := newIOFSTree(t)
+	if err := fstest.TestFS(fsys,
+		"index.html", "css/site.css", "js/app.js", "docs/guide/intro.txt", "docs/readme.md",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsFS_NotExist(t *testing.T) {
+	fsys := newIOFSTree(t)
+
+	if _, err := fsys.Open("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := fs.Stat(fsys, "docs/missing.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := fsys.Open("../escape"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open invalid path error = %v, want fs.ErrInvalid", err)
+	}
+}
+
+func TestAsFS_HTTPFileServer(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.FS(newIOFSTree(t))))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/css/site.css", nil)
+	req.Header.Set("Range", "bytes=2-3")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "dy" {
+		t.Errorf("got %d %q, want 206 %q", resp.StatusCode, body, "dy")
+	}
+}
@@ -0,0 +1,102 @@
+package filekit
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultListPageSize is the page size used when ListPageOptions.MaxResults is not set.
+const DefaultListPageSize = 1000
+
+// PageSize returns MaxResults, or DefaultListPageSize if it is not positive.
+func (o ListPageOptions) PageSize() int {
+	if o.MaxResults <= 0 {
+		return DefaultListPageSize
+	}
+	return o.MaxResults
+}
+
+// ListContentsPage returns one page of the entries under path. It uses the
+// native implementation when fs implements CanListPage, and pages the result
+// of ListContents with PageEntries otherwise.
+//
+// Example:
+//
+//	page, err := filekit.ListContentsPage(ctx, fs, "photos", filekit.ListPageOptions{MaxResults: 50})
+func ListContentsPage(ctx context.Context, fs FileReader, path string, opts ListPageOptions) (ListPage, error) {
+	if lister, ok := fs.(CanListPage); ok {
+		return lister.ListPage(ctx, path, opts)
+	}
+
+	entries, err := fs.ListContents(ctx, path, opts.Recursive)
+	if err != nil {
+		return ListPage{}, err
+	}
+	return PageEntries(path, entries, opts)
+}
+
+// IsEmpty reports whether the directory at path has no entries. Only one entry
+// is listed: a single-result request on object stores, one directory read on
+// local and SFTP. A missing path returns ErrNotExist and a file returns
+// ErrNotDir (ErrCodeTypeMismatch).
+//
+// Example:
+//
+//	if empty, err := filekit.IsEmpty(ctx, fs, "uploads/tmp"); err == nil && empty {
+//	    _ = fs.Delete(ctx, "uploads/tmp")
+//	}
+func IsEmpty(ctx context.Context, fs FileReader, path string) (bool, error) {
+	isDir, err := fs.DirExists(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	if !isDir {
+		isFile, err := fs.FileExists(ctx, path)
+		if err != nil {
+			return false, err
+		}
+		if isFile {
+			return false, WrapPathErr("isempty", path, ErrNotDir)
+		}
+		return false, WrapPathErr("isempty", path, ErrNotExist)
+	}
+
+	page, err := ListContentsPage(ctx, fs, path, ListPageOptions{MaxResults: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(page.Entries) == 0, nil
+}
+
+// PageEntries returns one page of a complete listing. Entries are sorted by
+// Path so the order is deterministic, and tokens are offsets into that order.
+//
+// Drivers without native pagination use it to implement CanListPage on top of
+// ListContents.
+func PageEntries(path string, entries []FileInfo, opts ListPageOptions) (ListPage, error) {
+	offset := 0
+	if opts.ContinuationToken != "" {
+		n, err := strconv.Atoi(opts.ContinuationToken)
+		if err != nil || n < 0 {
+			return ListPage{}, NewPathError("listpage", path, ErrCodeInvalidInput, "invalid continuation token")
+		}
+		offset = n
+	}
+
+	slices.SortFunc(entries, func(a, b FileInfo) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	if offset >= len(entries) {
+		return ListPage{Entries: []FileInfo{}}, nil
+	}
+	end := min(offset+opts.PageSize(), len(entries))
+
+	page := ListPage{Entries: entries[offset:end]}
+	if end < len(entries) {
+		page.NextToken = strconv.Itoa(end)
+	}
+	return page, nil
+}
@@ -0,0 +1,143 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// plainFS hides the optional interfaces of the wrapped filesystem
+type plainFS struct {
+	filekit.FileSystem
+}
+
+// collectPages lists path in pages of size and returns the entry paths in order
+func collectPages(t *testing.T, fs filekit.FileReader, path string, size int, recursive bool) ([]string, int) {
+	t.Helper()
+	var paths []string
+	pages := 0
+	opts := filekit.ListPageOptions{MaxResults: size, Recursive: recursive}
+	for {
+		page, err := filekit.ListContentsPage(context.Background(), fs, path, opts)
+		if err != nil {
+			t.Fatalf("ListContentsPage: %v", err)
+		}
+		if len(page.Entries) > size {
+			t.Fatalf("page has %d entries, want at most %d", len(page.Entries), size)
+		}
+		pages++
+		for _, e := range page.Entries {
+			paths = append(paths, e.Path)
+		}
+		if page.NextToken == "" {
+			return paths, pages
+		}
+		opts.ContinuationToken = page.NextToken
+	}
+}
+
+func TestListPage_Reassembles(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i < 10; i++ {
+		files[fmt.Sprintf("docs/%02d.txt", i)] = "x"
+	}
+	files["docs/sub/a.txt"] = "x"
+
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, fs := range map[string]filekit.FileSystem{
+		"memory":   memory.New(),
+		"local":    localFS,
+		"fallback": plainFS{memory.New()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			seedTree(t, fs, files)
+
+			for _, recursive := range []bool{false, true} {
+				want, _ := collectPages(t, fs, "docs", 1000, recursive)
+				got, pages := collectPages(t, fs, "docs", 4, recursive)
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("recursive=%v: paged listing = %v, want %v", recursive, got, want)
+				}
+				if wantPages := (len(want) + 3) / 4; pages != wantPages {
+					t.Errorf("recursive=%v: got %d pages, want %d", recursive, pages, wantPages)
+				}
+			}
+		})
+	}
+}
+
+func TestListPage_SortedByPath(t *testing.T) {
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{"c.txt": "", "a.txt": "", "b.txt": ""})
+
+	got, _ := collectPages(t, fs, "", 2, false)
+	if want := []string{"a.txt", "b.txt", "c.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %v, want %v", got, want)
+	}
+}
+
+func TestListPage_InvalidToken(t *testing.T) {
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{"a.txt": ""})
+
+	_, err := fs.ListPage(context.Background(), "", filekit.ListPageOptions{ContinuationToken: "bogus"})
+	if !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("err = %v, want ErrCodeInvalidInput", err)
+	}
+}
+
+func TestListPage_TokenPastEnd(t *testing.T) {
+	page, err := filekit.PageEntries("", []filekit.FileInfo{{Path: "a.txt"}}, filekit.ListPageOptions{ContinuationToken: "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 0 || page.NextToken != "" {
+		t.Errorf("page = %+v, want empty last page", page)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	ctx := context.Background()
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, fs := range map[string]filekit.FileSystem{
+		"memory": memory.New(),
+		"local":  localFS,
+		"plain":  plainFS{memory.New()}, // ListContents fallback
+	} {
+		t.Run(name, func(t *testing.T) {
+			seedTree(t, fs, map[string]string{"full/a.txt": "a", "full/b.txt": "b", "file.txt": "x"})
+			if err := fs.CreateDir(ctx, "empty"); err != nil {
+				t.Fatal(err)
+			}
+
+			if empty, err := filekit.IsEmpty(ctx, fs, "empty"); err != nil || !empty {
+				t.Errorf("IsEmpty(empty) = %v, %v; want true", empty, err)
+			}
+			if empty, err := filekit.IsEmpty(ctx, fs, "full"); err != nil || empty {
+				t.Errorf("IsEmpty(full) = %v, %v; want false", empty, err)
+			}
+			if _, err := filekit.IsEmpty(ctx, fs, "missing"); !errors.Is(err, filekit.ErrNotExist) {
+				t.Errorf("IsEmpty(missing) error = %v, want ErrNotExist", err)
+			}
+			_, err := filekit.IsEmpty(ctx, fs, "file.txt")
+			if !errors.Is(err, filekit.ErrNotDir) || !filekit.IsCode(err, filekit.ErrCodeTypeMismatch) {
+				t.Errorf("IsEmpty(file.txt) error = %v, want ErrNotDir", err)
+			}
+		})
+	}
+}
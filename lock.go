@@ -0,0 +1,235 @@
+package filekit
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLockNotHeld is returned (wrapped, match with errors.Is) by
+// FileLock.Unlock and FileLock.Refresh when the lock file no longer belongs
+// to the caller, either because it was never acquired or because it expired
+// and another owner took it over.
+var ErrLockNotHeld = errors.New("lock not held")
+
+// Locker is a lock whose operations take a context.
+type Locker interface {
+	Lock(ctx context.Context) error
+	Unlock(ctx context.Context) error
+}
+
+// FileLock is an advisory lock held through a lock file. The file records an
+// owner ID and an expiry; a lock whose expiry has passed is stale and can be
+// taken over by another owner, so a holder that needs the lock for longer
+// than ttl calls Refresh.
+//
+// It works over any backend with conditional writes (WithIfNoneMatch("*") and
+// WithIfMatch): S3, GCS, Azure, local and memory. Every FileLock value is a
+// distinct owner; processes that share the lock each create their own.
+//
+// Example:
+//
+//	lock := filekit.NewFileLock(fs, "locks/reindex.lock", time.Minute)
+//	if err := lock.Lock(ctx); err != nil {
+//	    return err
+//	}
+//	defer lock.Unlock(ctx)
+type FileLock struct {
+	fs    FileSystem
+	path  string
+	ttl   time.Duration
+	owner string
+
+	mu sync.Mutex
+}
+
+// lockRecord is the content of a lock file.
+type lockRecord struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewFileLock creates a lock that is held through the file at path and
+// expires ttl after it was last acquired or refreshed.
+func NewFileLock(fs FileSystem, path string, ttl time.Duration) *FileLock {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return &FileLock{
+		fs:    fs,
+		path:  path,
+		ttl:   ttl,
+		owner: hex.EncodeToString(id),
+	}
+}
+
+// Owner returns the owner ID written to the lock file.
+func (l *FileLock) Owner() string {
+	return l.owner
+}
+
+// Lock acquires the lock, waiting while another owner holds it. It returns
+// the context error if ctx ends first.
+func (l *FileLock) Lock(ctx context.Context) error {
+	for {
+		acquired, err := l.TryLock(ctx)
+		if err != nil || acquired {
+			return err
+		}
+
+		timer := time.NewTimer(l.retryInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return FromContext(ctx, "lock", l.path)
+		case <-timer.C:
+		}
+	}
+}
+
+// TryLock acquires the lock if it is free or stale, and reports false if
+// another owner holds it.
+func (l *FileLock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := CreateExclusive(ctx, l.fs, l.path, l.record())
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, ErrExist) {
+		return false, err
+	}
+
+	current, etag, err := l.read(ctx, "lock")
+	if IsNotFound(err) {
+		// Released since the create failed; the next attempt may win
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if current.Owner != l.owner && time.Now().Before(current.Expires) {
+		return false, nil
+	}
+
+	// Ours already, or stale: rewrite it unless another owner did first
+	err = l.replace(ctx, etag)
+	if errors.Is(err, ErrPreconditionFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Refresh extends the lock by ttl from now. It fails with ErrLockNotHeld if
+// the lock file no longer belongs to this owner.
+func (l *FileLock) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	etag, err := l.verify(ctx, "refresh")
+	if err != nil {
+		return err
+	}
+	err = l.replace(ctx, etag)
+	if errors.Is(err, ErrPreconditionFailed) {
+		return WrapPath(ErrLockNotHeld, "refresh", l.path, ErrCodePreconditionFailed, "lock was taken over by another owner")
+	}
+	return err
+}
+
+// Unlock releases the lock by overwriting the lock file with an already
+// expired record, conditional on the ETag it verified, so a lock taken over
+// by another owner in the meantime is never released. The file is left in
+// place as a stale lock that the next owner takes over. It fails with
+// ErrLockNotHeld if the file belongs to another owner or has expired.
+func (l *FileLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	etag, err := l.verify(ctx, "unlock")
+	if err != nil {
+		return err
+	}
+	_, err = l.fs.Write(ctx, l.path, l.recordExpiring(time.Time{}), WithIfMatch(etag))
+	if errors.Is(err, ErrPreconditionFailed) {
+		return WrapPath(ErrLockNotHeld, "unlock", l.path, ErrCodePreconditionFailed, "lock was taken over by another owner")
+	}
+	return err
+}
+
+// verify checks that the lock file belongs to this owner and has not
+// expired, returning its ETag.
+func (l *FileLock) verify(ctx context.Context, op string) (string, error) {
+	current, etag, err := l.read(ctx, op)
+	if IsNotFound(err) {
+		return "", WrapPath(ErrLockNotHeld, op, l.path, ErrCodePreconditionFailed, "lock is not held")
+	}
+	if err != nil {
+		return "", err
+	}
+	if current.Owner != l.owner {
+		return "", WrapPath(ErrLockNotHeld, op, l.path, ErrCodePreconditionFailed, "lock is held by another owner")
+	}
+	if !time.Now().Before(current.Expires) {
+		// Another owner may take it over at any moment
+		return "", WrapPath(ErrLockNotHeld, op, l.path, ErrCodePreconditionFailed, "lock has expired")
+	}
+	return etag, nil
+}
+
+// read returns the current lock record and the ETag to make a replacement
+// conditional on. Drivers whose Stat reports no ETag use the SHA-256 of the
+// content, as local and memory do for conditional writes.
+func (l *FileLock) read(ctx context.Context, op string) (lockRecord, string, error) {
+	var rec lockRecord
+	info, err := l.fs.Stat(ctx, l.path)
+	if err != nil {
+		return rec, "", err
+	}
+	data, err := l.fs.ReadAll(ctx, l.path)
+	if err != nil {
+		return rec, "", err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, "", WrapPath(err, op, l.path, ErrCodeInvalidInput, "lock file is not a valid lock record")
+	}
+
+	etag := info.ETag
+	if etag == "" {
+		etag, err = CalculateChecksum(bytes.NewReader(data), ChecksumSHA256)
+		if err != nil {
+			return rec, "", WrapPath(err, op, l.path, ErrCodeInternal, "failed to hash lock file")
+		}
+	}
+	return rec, etag, nil
+}
+
+// replace overwrites the lock file with a fresh record for this owner if it
+// still has the given ETag.
+func (l *FileLock) replace(ctx context.Context, etag string) error {
+	_, err := l.fs.Write(ctx, l.path, l.record(), WithIfMatch(etag))
+	return err
+}
+
+// record returns the lock file content for this owner, expiring ttl from now.
+func (l *FileLock) record() *bytes.Reader {
+	return l.recordExpiring(time.Now().Add(l.ttl))
+}
+
+// recordExpiring returns the lock file content for this owner, expiring at expires.
+func (l *FileLock) recordExpiring(expires time.Time) *bytes.Reader {
+	data, _ := json.Marshal(lockRecord{Owner: l.owner, Expires: expires})
+	return bytes.NewReader(data)
+}
+
+// retryInterval is how long Lock waits between attempts.
+func (l *FileLock) retryInterval() time.Duration {
+	return min(max(l.ttl/10, 10*time.Millisecond), time.Second)
+}
+
+var _ Locker = (*FileLock)(nil)
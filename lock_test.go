@@ -0,0 +1,195 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func forEachLockBackend(t *testing.T, test func(t *testing.T, fs filekit.FileSystem)) {
+	for name, newFS := range map[string]func(t *testing.T) filekit.FileSystem{
+		"memory": func(*testing.T) filekit.FileSystem { return memory.New() },
+		"local": func(t *testing.T) filekit.FileSystem {
+			fs, err := local.New(t.TempDir())
+			if err != nil {
+				t.Fatalf("local.New: %v", err)
+			}
+			return fs
+		},
+	} {
+		t.Run(name, func(t *testing.T) { test(t, newFS(t)) })
+	}
+}
+
+func TestFileLock_RoundTrip(t *testing.T) {
+	forEachLockBackend(t, func(t *testing.T, fs filekit.FileSystem) {
+		ctx := context.Background()
+		lock := filekit.NewFileLock(fs, "locks/job.lock", time.Minute)
+
+		if err := lock.Lock(ctx); err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		var rec struct{ Owner string }
+		if err := filekit.ReadJSON(ctx, fs, "locks/job.lock", &rec); err != nil || rec.Owner != lock.Owner() {
+			t.Errorf("lock file owner = %q, %v; want %q", rec.Owner, err, lock.Owner())
+		}
+		if err := lock.Refresh(ctx); err != nil {
+			t.Errorf("Refresh: %v", err)
+		}
+		if err := lock.Unlock(ctx); err != nil {
+			t.Fatalf("Unlock: %v", err)
+		}
+		if err := lock.Unlock(ctx); !errors.Is(err, filekit.ErrLockNotHeld) {
+			t.Errorf("second Unlock = %v, want ErrLockNotHeld", err)
+		}
+
+		// The released lock is free for the next owner at once
+		next := filekit.NewFileLock(fs, "locks/job.lock", time.Minute)
+		if ok, err := next.TryLock(ctx); !ok || err != nil {
+			t.Errorf("TryLock after Unlock = %v, %v; want true, nil", ok, err)
+		}
+	})
+}
+
+// takeoverFS runs beforeWrite once, just before the next Write reaches the
+// underlying filesystem.
+type takeoverFS struct {
+	filekit.FileSystem
+	beforeWrite func()
+}
+
+func (f *takeoverFS) Write(ctx context.Context, path string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
+	if hook := f.beforeWrite; hook != nil {
+		f.beforeWrite = nil
+		hook()
+	}
+	return f.FileSystem.Write(ctx, path, content, options...)
+}
+
+func TestFileLock_UnlockKeepsNewOwner(t *testing.T) {
+	forEachLockBackend(t, func(t *testing.T, base filekit.FileSystem) {
+		ctx := context.Background()
+		fs := &takeoverFS{FileSystem: base}
+		lock := filekit.NewFileLock(fs, "job.lock", time.Minute)
+		if err := lock.Lock(ctx); err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+
+		// Another owner takes the lock over between Unlock's check and its write
+		fs.beforeWrite = func() {
+			err := filekit.WriteJSON(ctx, base, "job.lock", map[string]any{
+				"owner":   "other",
+				"expires": time.Now().Add(time.Minute),
+			}, filekit.WithOverwrite(true))
+			if err != nil {
+				t.Fatalf("takeover write: %v", err)
+			}
+		}
+		if err := lock.Unlock(ctx); !errors.Is(err, filekit.ErrLockNotHeld) {
+			t.Errorf("Unlock after a takeover = %v, want ErrLockNotHeld", err)
+		}
+
+		var rec struct{ Owner string }
+		if err := filekit.ReadJSON(ctx, base, "job.lock", &rec); err != nil || rec.Owner != "other" {
+			t.Errorf("lock file owner = %q, %v; want the new owner kept", rec.Owner, err)
+		}
+	})
+}
+
+func TestFileLock_Contention(t *testing.T) {
+	forEachLockBackend(t, func(t *testing.T, fs filekit.FileSystem) {
+		ctx := context.Background()
+		first := filekit.NewFileLock(fs, "job.lock", time.Minute)
+		// A short TTL keeps the waiter's retry interval short
+		second := filekit.NewFileLock(fs, "job.lock", 200*time.Millisecond)
+
+		if err := first.Lock(ctx); err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		if ok, err := second.TryLock(ctx); ok || err != nil {
+			t.Errorf("TryLock while held = %v, %v; want false, nil", ok, err)
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err := second.Lock(waitCtx); !filekit.IsCode(err, filekit.ErrCodeTimeout) {
+			t.Errorf("Lock while held = %v, want ErrCodeTimeout", err)
+		}
+		if err := second.Unlock(ctx); !errors.Is(err, filekit.ErrLockNotHeld) {
+			t.Errorf("Unlock by another owner = %v, want ErrLockNotHeld", err)
+		}
+
+		// Released: the waiting owner gets it
+		acquired := make(chan error, 1)
+		go func() { acquired <- second.Lock(ctx) }()
+		time.Sleep(20 * time.Millisecond)
+		if err := first.Unlock(ctx); err != nil {
+			t.Fatalf("Unlock: %v", err)
+		}
+		select {
+		case err := <-acquired:
+			if err != nil {
+				t.Fatalf("Lock after release: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Lock did not acquire the released lock")
+		}
+	})
+}
+
+func TestFileLock_StaleTakeover(t *testing.T) {
+	forEachLockBackend(t, func(t *testing.T, fs filekit.FileSystem) {
+		const ttl = 30 * time.Millisecond
+		ctx := context.Background()
+		stale := filekit.NewFileLock(fs, "job.lock", ttl)
+		fresh := filekit.NewFileLock(fs, "job.lock", time.Minute)
+
+		if err := stale.Lock(ctx); err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		if ok, _ := fresh.TryLock(ctx); ok {
+			t.Fatal("TryLock took over a lock before its TTL")
+		}
+		time.Sleep(2 * ttl)
+
+		if ok, err := fresh.TryLock(ctx); !ok || err != nil {
+			t.Fatalf("TryLock on a stale lock = %v, %v; want true, nil", ok, err)
+		}
+		if err := stale.Refresh(ctx); !errors.Is(err, filekit.ErrLockNotHeld) {
+			t.Errorf("Refresh by the previous owner = %v, want ErrLockNotHeld", err)
+		}
+		if err := stale.Unlock(ctx); !errors.Is(err, filekit.ErrLockNotHeld) {
+			t.Errorf("Unlock by the previous owner = %v, want ErrLockNotHeld", err)
+		}
+		if err := fresh.Unlock(ctx); err != nil {
+			t.Errorf("Unlock by the new owner: %v", err)
+		}
+	})
+}
+
+func TestFileLock_RefreshKeepsLock(t *testing.T) {
+	forEachLockBackend(t, func(t *testing.T, fs filekit.FileSystem) {
+		const ttl = 100 * time.Millisecond
+		ctx := context.Background()
+		holder := filekit.NewFileLock(fs, "job.lock", ttl)
+		other := filekit.NewFileLock(fs, "job.lock", ttl)
+
+		if err := holder.Lock(ctx); err != nil {
+			t.Fatalf("Lock: %v", err)
+		}
+		for range 4 {
+			time.Sleep(ttl / 4)
+			if err := holder.Refresh(ctx); err != nil {
+				t.Fatalf("Refresh: %v", err)
+			}
+		}
+		if ok, _ := other.TryLock(ctx); ok {
+			t.Error("TryLock took over a refreshed lock")
+		}
+	})
+}
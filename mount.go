@@ -2,9 +2,12 @@ package filekit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -60,19 +63,96 @@ func (m *MountManager) Mount(mountPath string, fs FileSystem) error {
 
 // Unmount removes the filesystem at the specified path.
 func (m *MountManager) Unmount(mountPath string) error {
+	_, err := m.unmount(mountPath)
+	return err
+}
+
+// UnmountAndClose removes a mount point and closes its filesystem if it
+// implements io.Closer (e.g. SFTP connections, ZIP archives). A filesystem
+// that is still mounted at another path is left open; it is closed when its
+// last mount is removed. The close error, if any, is returned.
+func (m *MountManager) UnmountAndClose(mountPath string) error {
+	fs, err := m.unmount(mountPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	for _, other := range m.mounts {
+		if sameFileSystem(fs, other) {
+			m.mu.RUnlock()
+			return nil
+		}
+	}
+	m.mu.RUnlock()
+
+	if closer, ok := fs.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return WrapPathErr("unmount", normalizeMountPath(mountPath), err)
+		}
+	}
+	return nil
+}
+
+// Close unmounts every filesystem and closes those that implement io.Closer,
+// in mount path order. A filesystem mounted at several paths is closed once.
+// Close errors are wrapped with the mount path; with more than one failure
+// the result is a *MultiError.
+func (m *MountManager) Close() error {
+	m.mu.Lock()
+	mounts := m.mounts
+	m.mounts = make(map[string]FileSystem)
+	m.updateSortedPaths()
+	m.mu.Unlock()
+
+	paths := make([]string, 0, len(mounts))
+	for p := range mounts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	errs := NewMultiError("close")
+	var closed []FileSystem
+	for _, p := range paths {
+		fs := mounts[p]
+		if slices.ContainsFunc(closed, func(c FileSystem) bool { return sameFileSystem(c, fs) }) {
+			continue
+		}
+		closed = append(closed, fs)
+		if closer, ok := fs.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs.Add(WrapPathErr("close", p, err))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// unmount removes a mount point and returns the filesystem that was mounted
+func (m *MountManager) unmount(mountPath string) (FileSystem, error) {
 	mountPath = normalizeMountPath(mountPath)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.mounts[mountPath]; !exists {
-		return fmt.Errorf("%w: %s", ErrMountNotFound, mountPath)
+	fs, exists := m.mounts[mountPath]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrMountNotFound, mountPath)
 	}
 
 	delete(m.mounts, mountPath)
 	m.updateSortedPaths()
 
-	return nil
+	return fs, nil
+}
+
+// sameFileSystem reports whether a and b are the same filesystem. Values of
+// non-comparable types cannot be compared and are never considered the same.
+func sameFileSystem(a, b FileSystem) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
 }
 
 // Mounts returns a copy of all current mount points and their filesystems.
@@ -111,6 +191,38 @@ func (m *MountManager) GetMount(mountPath string) (FileSystem, error) {
 	return fs, nil
 }
 
+// ResolveMount reports which backend a virtual path routes to.
+// It returns the longest matching mount prefix, the mounted filesystem, and the
+// path relative to that mount. ok is false if no mount matches.
+//
+// Example:
+//
+//	mounts.Mount("/cloud", s3Driver)
+//	mounts.Mount("/cloud/archive", glacierDriver)
+//
+//	mountPath, fs, rel, ok := mounts.ResolveMount("/cloud/archive/x.txt")
+//	// mountPath = "/cloud/archive", fs = glacierDriver, rel = "x.txt", ok = true
+func (m *MountManager) ResolveMount(p string) (mountPath string, fs FileSystem, relativePath string, ok bool) {
+	p = normalizeMountPath(p)
+	if p == "" {
+		return "", nil, "", false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// sortedPaths is ordered longest-first, so the first match is the longest prefix
+	for _, mp := range m.sortedPaths {
+		if p == mp || strings.HasPrefix(p, mp+"/") {
+			rel := strings.TrimPrefix(p, mp)
+			rel = strings.TrimPrefix(rel, "/")
+			return mp, m.mounts[mp], rel, true
+		}
+	}
+
+	return "", nil, "", false
+}
+
 // resolve finds the correct mount and relative path for an absolute path.
 // Uses longest-prefix matching to support nested mounts.
 func (m *MountManager) resolve(absPath string) (FileSystem, string, error) {
@@ -119,20 +231,11 @@ func (m *MountManager) resolve(absPath string) (FileSystem, string, error) {
 		return nil, "", ErrEmptyMountPath
 	}
 
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	// Find the longest matching mount path
-	for _, mountPath := range m.sortedPaths {
-		if absPath == mountPath || strings.HasPrefix(absPath, mountPath+"/") {
-			fs := m.mounts[mountPath]
-			relativePath := strings.TrimPrefix(absPath, mountPath)
-			relativePath = strings.TrimPrefix(relativePath, "/")
-			return fs, relativePath, nil
-		}
+	_, fs, relativePath, ok := m.ResolveMount(absPath)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrMountNotFound, absPath)
 	}
-
-	return nil, "", fmt.Errorf("%w: %s", ErrMountNotFound, absPath)
+	return fs, relativePath, nil
 }
 
 // updateSortedPaths updates the sorted paths slice for longest-prefix matching.
@@ -180,6 +283,16 @@ func (m *MountManager) Write(ctx context.Context, filePath string, content io.Re
 	return fs.Write(ctx, relativePath, content, options...)
 }
 
+// WriteFile writes the local file at localPath to the path, routing to the
+// appropriate mount.
+func (m *MountManager) WriteFile(ctx context.Context, filePath, localPath string, options ...Option) (*WriteResult, error) {
+	fs, relativePath, err := m.resolve(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return WriteFile(ctx, fs, relativePath, localPath, options...)
+}
+
 // Read reads content from the path, routing to the appropriate mount.
 func (m *MountManager) Read(ctx context.Context, filePath string) (io.ReadCloser, error) {
 	fs, relativePath, err := m.resolve(filePath)
@@ -309,6 +422,12 @@ func (m *MountManager) Copy(ctx context.Context, srcPath, dstPath string) error
 		return fmt.Errorf("resolve destination: %w", err)
 	}
 
+	// Copying a file onto itself would truncate or duplicate it
+	if srcFS == dstFS && srcRelative == dstRelative {
+		_, err := srcFS.Stat(ctx, srcRelative)
+		return err
+	}
+
 	// If same mount, try native copy if supported
 	if srcFS == dstFS {
 		if copier, ok := srcFS.(CanCopy); ok {
@@ -317,14 +436,20 @@ func (m *MountManager) Copy(ctx context.Context, srcPath, dstPath string) error
 	}
 
 	// Cross-mount copy: read and write
-	reader, err := srcFS.Read(ctx, srcRelative)
+	return streamCopy(ctx, srcFS, srcRelative, dstFS, dstRelative)
+}
+
+// streamCopy reads a file from srcFS and writes it to dstFS, preserving
+// content type and metadata. extra is appended to the write options.
+func streamCopy(ctx context.Context, srcFS FileSystem, srcPath string, dstFS FileSystem, dstPath string, extra ...Option) error {
+	reader, err := srcFS.Read(ctx, srcPath)
 	if err != nil {
 		return fmt.Errorf("read source: %w", err)
 	}
 	defer reader.Close()
 
 	// Get source file info for metadata
-	srcInfo, err := srcFS.Stat(ctx, srcRelative)
+	srcInfo, err := srcFS.Stat(ctx, srcPath)
 	if err != nil {
 		return fmt.Errorf("get source info: %w", err)
 	}
@@ -336,8 +461,9 @@ func (m *MountManager) Copy(ctx context.Context, srcPath, dstPath string) error
 	if len(srcInfo.Metadata) > 0 {
 		opts = append(opts, WithMetadata(srcInfo.Metadata))
 	}
+	opts = append(opts, extra...)
 
-	if _, err := dstFS.Write(ctx, dstRelative, reader, opts...); err != nil {
+	if _, err := dstFS.Write(ctx, dstPath, reader, opts...); err != nil {
 		return fmt.Errorf("write destination: %w", err)
 	}
 
@@ -345,8 +471,14 @@ func (m *MountManager) Copy(ctx context.Context, srcPath, dstPath string) error
 }
 
 // Move moves a file from source to destination.
-// Supports cross-mount moving (copy + delete).
-func (m *MountManager) Move(ctx context.Context, srcPath, dstPath string) error {
+// When both paths resolve to the same backend and it implements CanMove, the
+// native move is used. Otherwise the file is streamed from the source backend
+// to the destination backend, and the source is deleted only after the write
+// succeeds, so a failed copy always leaves the source intact. An existing
+// destination is replaced unless WithMoveNoOverwrite is given. Across mounts
+// that option needs a destination backend with conditional writes; others
+// fail with ErrCodeNotSupported.
+func (m *MountManager) Move(ctx context.Context, srcPath, dstPath string, opts ...MoveOption) error {
 	srcFS, srcRelative, err := m.resolve(srcPath)
 	if err != nil {
 		return fmt.Errorf("resolve source: %w", err)
@@ -357,15 +489,54 @@ func (m *MountManager) Move(ctx context.Context, srcPath, dstPath string) error
 		return fmt.Errorf("resolve destination: %w", err)
 	}
 
+	// Moving a file onto itself is a no-op; copy+delete would delete it
+	if srcFS == dstFS && srcRelative == dstRelative {
+		_, err := srcFS.Stat(ctx, srcRelative)
+		return err
+	}
+
 	// If same mount, try native move if supported
 	if srcFS == dstFS {
 		if mover, ok := srcFS.(CanMove); ok {
-			return mover.Move(ctx, srcRelative, dstRelative)
+			return mover.Move(ctx, srcRelative, dstRelative, opts...)
 		}
 	}
 
-	// Cross-mount move: copy then delete
-	if err := m.Copy(ctx, srcPath, dstPath); err != nil {
+	noOverwrite := ApplyMoveOptions(opts...).NoOverwrite
+
+	// Same backend without native move: native copy then delete
+	if srcFS == dstFS {
+		if copier, ok := srcFS.(CanCopy); ok {
+			if noOverwrite {
+				exists, err := srcFS.FileExists(ctx, dstRelative)
+				if err != nil {
+					return err
+				}
+				if exists {
+					return WrapPathErr("move", dstPath, ErrExist)
+				}
+			}
+			if err := copier.Copy(ctx, srcRelative, dstRelative); err != nil {
+				return err
+			}
+			if err := srcFS.Delete(ctx, srcRelative); err != nil {
+				return fmt.Errorf("delete source after move: %w", err)
+			}
+			return nil
+		}
+	}
+
+	// Cross-mount move: stream copy, then delete the source once the write
+	// succeeded. Without overwrite the copy is a conditional create, so the
+	// destination backend rejects an existing file atomically.
+	extra := []Option{WithOverwrite(!noOverwrite)}
+	if noOverwrite {
+		extra = append(extra, WithIfNoneMatch("*"))
+	}
+	if err := streamCopy(ctx, srcFS, srcRelative, dstFS, dstRelative, extra...); err != nil {
+		if errors.Is(err, ErrPreconditionFailed) {
+			return WrapPath(err, "move", dstPath, ErrCodeAlreadyExists, "destination already exists")
+		}
 		return err
 	}
 
@@ -382,17 +553,8 @@ func (m *MountManager) Move(ctx context.Context, srcPath, dstPath string) error
 
 // getMountPathForFile returns the mount path for a given file path.
 func (m *MountManager) getMountPathForFile(filePath string) string {
-	filePath = normalizeMountPath(filePath)
-
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	for _, mountPath := range m.sortedPaths {
-		if filePath == mountPath || strings.HasPrefix(filePath, mountPath+"/") {
-			return mountPath
-		}
-	}
-	return ""
+	mountPath, _, _, _ := m.ResolveMount(filePath)
+	return mountPath
 }
 
 // listMountPoints returns virtual directory entries for each root-level mount.
@@ -551,9 +713,10 @@ func (m *MountManager) watchAllMounts(ctx context.Context, filter string) (Chang
 
 // Ensure MountManager implements FileSystem and optional interfaces
 var (
-	_ FileSystem  = (*MountManager)(nil)
-	_ CanCopy     = (*MountManager)(nil)
-	_ CanMove     = (*MountManager)(nil)
-	_ CanChecksum = (*MountManager)(nil)
-	_ CanWatch    = (*MountManager)(nil)
+	_ FileSystem        = (*MountManager)(nil)
+	_ CanCopy           = (*MountManager)(nil)
+	_ CanMove           = (*MountManager)(nil)
+	_ CanWriteLocalFile = (*MountManager)(nil)
+	_ CanChecksum       = (*MountManager)(nil)
+	_ CanWatch          = (*MountManager)(nil)
 )
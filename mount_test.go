@@ -13,12 +13,13 @@ import (
 
 // mockFS is a simple mock filesystem for testing
 type mockFS struct {
-	mu      sync.RWMutex
-	name    string
-	files   map[string][]byte
-	dirs    map[string]bool
-	copyErr error
-	moveErr error
+	mu       sync.RWMutex
+	name     string
+	files    map[string][]byte
+	dirs     map[string]bool
+	copyErr  error
+	moveErr  error
+	writeErr error
 }
 
 func newMockFS(name string) *mockFS {
@@ -30,6 +31,9 @@ func newMockFS(name string) *mockFS {
 }
 
 func (m *mockFS) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
 	data, err := io.ReadAll(content)
 	if err != nil {
 		return nil, err
@@ -165,7 +169,7 @@ func newMockMoverFS(name string) *mockMoverFS {
 	return &mockMoverFS{mockFS: newMockFS(name)}
 }
 
-func (m *mockMoverFS) Move(ctx context.Context, src, dst string) error {
+func (m *mockMoverFS) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
 	m.moveCalled = true
 	if m.moveErr != nil {
 		return m.moveErr
@@ -411,6 +415,54 @@ func TestNestedMounts(t *testing.T) {
 	}
 }
 
+func TestResolveMount(t *testing.T) {
+	mm := NewMountManager()
+	cloud := newMockFS("cloud")
+	archive := newMockFS("archive")
+
+	if err := mm.Mount("/cloud", cloud); err != nil {
+		t.Fatalf("mount /cloud failed: %v", err)
+	}
+	if err := mm.Mount("/cloud/archive", archive); err != nil {
+		t.Fatalf("mount /cloud/archive failed: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		mountPath string
+		fs        FileSystem
+		relative  string
+		ok        bool
+	}{
+		{"/cloud/archive/x.txt", "/cloud/archive", archive, "x.txt", true},
+		{"/cloud/archive", "/cloud/archive", archive, "", true},
+		{"/cloud/archived/x.txt", "/cloud", cloud, "archived/x.txt", true},
+		{"/cloud/docs/a/b.txt", "/cloud", cloud, "docs/a/b.txt", true},
+		{"cloud/x.txt", "/cloud", cloud, "x.txt", true},
+		{"/cloud/archive/../x.txt", "/cloud", cloud, "x.txt", true},
+		{"/local/x.txt", "", nil, "", false},
+		{"", "", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mountPath, fs, relative, ok := mm.ResolveMount(tt.path)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if mountPath != tt.mountPath {
+				t.Errorf("mountPath = %q, want %q", mountPath, tt.mountPath)
+			}
+			if fs != tt.fs {
+				t.Errorf("fs = %v, want %v", fs, tt.fs)
+			}
+			if relative != tt.relative {
+				t.Errorf("relative = %q, want %q", relative, tt.relative)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	ctx := context.Background()
 	mm := NewMountManager()
@@ -686,6 +738,72 @@ func TestMoveCrossMount(t *testing.T) {
 	}
 }
 
+func TestMoveCrossMount_WriteFailurePreservesSource(t *testing.T) {
+	ctx := context.Background()
+	mm := NewMountManager()
+	local := newMockFS("local")
+	cloud := newMockFS("cloud")
+	cloud.writeErr = errors.New("quota exceeded")
+	if err := mm.Mount("/local", local); err != nil {
+		t.Fatalf("mount /local failed: %v", err)
+	}
+	if err := mm.Mount("/cloud", cloud); err != nil {
+		t.Fatalf("mount /cloud failed: %v", err)
+	}
+
+	local.files["file.txt"] = []byte("precious")
+
+	err := mm.Move(ctx, "/local/file.txt", "/cloud/file.txt")
+	if err == nil {
+		t.Fatal("expected move to fail when destination write fails")
+	}
+	if !errors.Is(err, cloud.writeErr) {
+		t.Errorf("expected write error to be wrapped, got %v", err)
+	}
+
+	// Source must be untouched
+	if data, ok := local.files["file.txt"]; !ok || string(data) != "precious" {
+		t.Error("source file should be intact after failed move")
+	}
+	if _, ok := cloud.files["file.txt"]; ok {
+		t.Error("destination should not exist after failed move")
+	}
+}
+
+func TestMoveSameMount_CopyFallback(t *testing.T) {
+	ctx := context.Background()
+	mm := NewMountManager()
+	local := newMockCopierFS("local")
+	if err := mm.Mount("/local", local); err != nil {
+		t.Fatalf("mount failed: %v", err)
+	}
+
+	local.files["src.txt"] = []byte("content")
+
+	if err := mm.Move(ctx, "/local/src.txt", "/local/dst.txt"); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if !local.copyCalled {
+		t.Error("expected native Copy to be used when Move is unsupported")
+	}
+	if _, ok := local.files["src.txt"]; ok {
+		t.Error("source file should not exist after move")
+	}
+	if string(local.files["dst.txt"]) != "content" {
+		t.Error("destination content mismatch")
+	}
+
+	// A failed native copy must leave the source in place
+	local.copyErr = errors.New("copy failed")
+	local.files["keep.txt"] = []byte("keep")
+	if err := mm.Move(ctx, "/local/keep.txt", "/local/other.txt"); err == nil {
+		t.Fatal("expected move to fail when copy fails")
+	}
+	if _, ok := local.files["keep.txt"]; !ok {
+		t.Error("source file should be intact after failed copy")
+	}
+}
+
 func TestResolveErrors(t *testing.T) {
 	ctx := context.Background()
 	mm := NewMountManager()
@@ -757,3 +875,169 @@ func TestConcurrency(t *testing.T) {
 		<-done
 	}
 }
+
+// pingFS is a mockFS whose backend reports err from Ping
+type pingFS struct {
+	*mockFS
+	err error
+}
+
+func (p *pingFS) Ping(ctx context.Context) error { return p.err }
+
+// closerFS is a mockFS that counts Close calls
+type closerFS struct {
+	*mockFS
+	closes int
+	err    error
+}
+
+func (c *closerFS) Close() error {
+	c.closes++
+	return c.err
+}
+
+func TestMountManager_Ping(t *testing.T) {
+	ctx := context.Background()
+	down := errors.New("connection refused")
+
+	mm := NewMountManager()
+	for path, fs := range map[string]FileSystem{
+		"/local":   newMockFS("local"), // no HealthChecker: assumed healthy
+		"/cloud":   &pingFS{mockFS: newMockFS("cloud")},
+		"/archive": &pingFS{mockFS: newMockFS("archive"), err: down},
+	} {
+		if err := mm.Mount(path, fs); err != nil {
+			t.Fatalf("mount %s: %v", path, err)
+		}
+	}
+
+	err := mm.Ping(ctx)
+	if !errors.Is(err, down) {
+		t.Fatalf("Ping = %v, want the failing backend's error", err)
+	}
+	var fkErr *FileError
+	if !errors.As(err, &fkErr) || fkErr.Path != "/archive" {
+		t.Errorf("Ping = %v, want an error naming /archive", err)
+	}
+
+	// Every failure is reported, in mount path order
+	if err := mm.Mount("/backup", &pingFS{mockFS: newMockFS("backup"), err: down}); err != nil {
+		t.Fatal(err)
+	}
+	var multi *MultiError
+	if err := mm.Ping(ctx); !errors.As(err, &multi) || len(multi.Errors) != 2 ||
+		!strings.Contains(multi.Errors[0].Error(), "/archive") || !strings.Contains(multi.Errors[1].Error(), "/backup") {
+		t.Errorf("Ping = %v, want failures for /archive and /backup", err)
+	}
+
+	if err := mm.Unmount("/archive"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mm.Unmount("/backup"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mm.Ping(ctx); err != nil {
+		t.Errorf("Ping with healthy mounts = %v, want nil", err)
+	}
+}
+
+func TestPing_Unwraps(t *testing.T) {
+	down := errors.New("connection refused")
+	fs := NewReadOnlyFileSystem(&pingFS{mockFS: newMockFS("cloud"), err: down})
+	if err := Ping(context.Background(), fs); !errors.Is(err, down) {
+		t.Errorf("Ping through a decorator = %v, want the backend's error", err)
+	}
+}
+
+func TestUnmountAndClose(t *testing.T) {
+	mm := NewMountManager()
+	sftp := &closerFS{mockFS: newMockFS("sftp")}
+	shared := &closerFS{mockFS: newMockFS("shared")}
+	for path, fs := range map[string]FileSystem{
+		"/sftp":   sftp,
+		"/a":      shared,
+		"/b":      shared,
+		"/memory": newMockFS("memory"), // not a Closer
+	} {
+		if err := mm.Mount(path, fs); err != nil {
+			t.Fatalf("mount %s: %v", path, err)
+		}
+	}
+
+	if err := mm.UnmountAndClose("/sftp"); err != nil {
+		t.Fatalf("UnmountAndClose: %v", err)
+	}
+	if sftp.closes != 1 {
+		t.Errorf("Close called %d times, want 1", sftp.closes)
+	}
+	if _, err := mm.GetMount("/sftp"); !errors.Is(err, ErrMountNotFound) {
+		t.Errorf("GetMount after unmount = %v, want ErrMountNotFound", err)
+	}
+	if err := mm.UnmountAndClose("/sftp"); !errors.Is(err, ErrMountNotFound) {
+		t.Errorf("second UnmountAndClose = %v, want ErrMountNotFound", err)
+	}
+	if sftp.closes != 1 {
+		t.Errorf("Close called %d times after a second unmount, want 1", sftp.closes)
+	}
+
+	// A backend mounted twice stays open until its last mount is removed
+	if err := mm.UnmountAndClose("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closes != 0 {
+		t.Errorf("shared backend closed while still mounted at /b")
+	}
+	if err := mm.UnmountAndClose("/b"); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closes != 1 {
+		t.Errorf("shared backend closed %d times, want 1", shared.closes)
+	}
+
+	if err := mm.UnmountAndClose("/memory"); err != nil {
+		t.Errorf("UnmountAndClose on a non-Closer = %v, want nil", err)
+	}
+
+	// Close errors are returned after the mount is removed
+	broken := &closerFS{mockFS: newMockFS("zip"), err: errors.New("flush failed")}
+	if err := mm.Mount("/zip", broken); err != nil {
+		t.Fatal(err)
+	}
+	if err := mm.UnmountAndClose("/zip"); err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("UnmountAndClose = %v, want the close error", err)
+	}
+	if len(mm.MountPaths()) != 0 {
+		t.Errorf("mounts left: %v", mm.MountPaths())
+	}
+}
+
+func TestMountManager_Close(t *testing.T) {
+	mm := NewMountManager()
+	first := &closerFS{mockFS: newMockFS("first")}
+	shared := &closerFS{mockFS: newMockFS("shared"), err: errors.New("connection reset")}
+	for path, fs := range map[string]FileSystem{
+		"/first":  first,
+		"/a":      shared,
+		"/b":      shared,
+		"/memory": newMockFS("memory"),
+	} {
+		if err := mm.Mount(path, fs); err != nil {
+			t.Fatalf("mount %s: %v", path, err)
+		}
+	}
+
+	err := mm.Close()
+	var fkErr *FileError
+	if !errors.As(err, &fkErr) || fkErr.Path != "/a" || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Close = %v, want the close error for /a", err)
+	}
+	if first.closes != 1 || shared.closes != 1 {
+		t.Errorf("Close calls = %d, %d; want each backend closed once", first.closes, shared.closes)
+	}
+	if len(mm.MountPaths()) != 0 {
+		t.Errorf("mounts left after Close: %v", mm.MountPaths())
+	}
+	if err := mm.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
@@ -0,0 +1,186 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// TestMove_DestinationParentExists checks that every backend reports the
+// destination's parent directories after a move into a new directory tree.
+func TestMove_DestinationParentExists(t *testing.T) {
+	newLocal := func(t *testing.T) filekit.FileSystem {
+		fs, err := local.New(t.TempDir())
+		if err != nil {
+			t.Fatalf("local.New: %v", err)
+		}
+		return fs
+	}
+
+	for name, newFS := range map[string]func(t *testing.T) filekit.FileSystem{
+		"memory": func(*testing.T) filekit.FileSystem { return memory.New() },
+		"local":  newLocal,
+		"mount": func(t *testing.T) filekit.FileSystem {
+			// A cross-mount move streams the file to another backend
+			mm := filekit.NewMountManager()
+			if err := mm.Mount("/src", memory.New()); err != nil {
+				t.Fatal(err)
+			}
+			if err := mm.Mount("/dst", newLocal(t)); err != nil {
+				t.Fatal(err)
+			}
+			return mm
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			fs := newFS(t)
+			srcRoot, dstRoot := "", ""
+			if _, ok := fs.(*filekit.MountManager); ok {
+				srcRoot, dstRoot = "/src/", "/dst/"
+			}
+			src, dst := srcRoot+"report.txt", dstRoot+"archive/2024/q1/report.txt"
+
+			if _, err := fs.Write(ctx, src, strings.NewReader("data")); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := fs.(filekit.CanMove).Move(ctx, src, dst); err != nil {
+				t.Fatalf("Move: %v", err)
+			}
+
+			for _, dir := range []string{"archive", "archive/2024", "archive/2024/q1"} {
+				exists, err := fs.DirExists(ctx, dstRoot+dir)
+				if err != nil || !exists {
+					t.Errorf("DirExists(%s) after move = %v, %v; want true", dstRoot+dir, exists, err)
+				}
+			}
+			if exists, _ := fs.FileExists(ctx, dst); !exists {
+				t.Errorf("FileExists(%s) after move = false", dst)
+			}
+			if exists, _ := fs.FileExists(ctx, src); exists {
+				t.Errorf("FileExists(%s) after move = true", src)
+			}
+		})
+	}
+}
+
+// TestMove_SamePath checks that moving or copying a file onto itself leaves
+// it in place on every backend, including the copy+delete fallbacks.
+func TestMove_SamePath(t *testing.T) {
+	for name, newFS := range map[string]func(t *testing.T) filekit.FileSystem{
+		"memory": func(*testing.T) filekit.FileSystem { return memory.New() },
+		"local": func(t *testing.T) filekit.FileSystem {
+			fs, err := local.New(t.TempDir())
+			if err != nil {
+				t.Fatalf("local.New: %v", err)
+			}
+			return fs
+		},
+		"mount without native move": func(t *testing.T) filekit.FileSystem {
+			// Embedding hides CanMove and CanCopy, so Move streams and deletes
+			mm := filekit.NewMountManager()
+			if err := mm.Mount("/m", struct{ filekit.FileSystem }{memory.New()}); err != nil {
+				t.Fatal(err)
+			}
+			return mm
+		},
+		"tee with a secondary without native move": func(*testing.T) filekit.FileSystem {
+			return filekit.NewTeeFileSystem(memory.New(), []filekit.FileSystem{struct{ filekit.FileSystem }{memory.New()}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			fs := newFS(t)
+			p := "docs/a.txt"
+			if _, ok := fs.(*filekit.MountManager); ok {
+				p = "/m/docs/a.txt"
+			}
+			if _, err := fs.Write(ctx, p, strings.NewReader("keep me")); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			if err := fs.(filekit.CanMove).Move(ctx, p, p); err != nil {
+				t.Errorf("Move(%s, %s): %v", p, p, err)
+			}
+			if err := fs.(filekit.CanCopy).Copy(ctx, p, p); err != nil {
+				t.Errorf("Copy(%s, %s): %v", p, p, err)
+			}
+			got, err := fs.ReadAll(ctx, p)
+			if err != nil || string(got) != "keep me" {
+				t.Errorf("ReadAll after same-path move = %q, %v; want %q", got, err, "keep me")
+			}
+
+			// A missing file is still reported
+			missing := strings.Replace(p, "a.txt", "missing.txt", 1)
+			if err := fs.(filekit.CanMove).Move(ctx, missing, missing); err == nil {
+				t.Errorf("Move(%s, %s) = nil, want an error", missing, missing)
+			}
+		})
+	}
+}
+
+// TestMove_NoOverwrite checks that WithMoveNoOverwrite refuses an existing
+// destination and that a plain Move replaces it, including cross-mount moves.
+func TestMove_NoOverwrite(t *testing.T) {
+	for name, newFS := range map[string]func(t *testing.T) filekit.FileSystem{
+		"memory": func(*testing.T) filekit.FileSystem { return memory.New() },
+		"cross-mount": func(t *testing.T) filekit.FileSystem {
+			mm := filekit.NewMountManager()
+			if err := mm.Mount("/src", memory.New()); err != nil {
+				t.Fatal(err)
+			}
+			if err := mm.Mount("/dst", memory.New()); err != nil {
+				t.Fatal(err)
+			}
+			return mm
+		},
+		"cross-mount/local": func(t *testing.T) filekit.FileSystem {
+			dst, err := local.New(t.TempDir())
+			if err != nil {
+				t.Fatalf("local.New: %v", err)
+			}
+			mm := filekit.NewMountManager()
+			if err := mm.Mount("/src", memory.New()); err != nil {
+				t.Fatal(err)
+			}
+			if err := mm.Mount("/dst", dst); err != nil {
+				t.Fatal(err)
+			}
+			return mm
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			fs := newFS(t)
+			src, dst := "a.txt", "b.txt"
+			if _, ok := fs.(*filekit.MountManager); ok {
+				src, dst = "/src/a.txt", "/dst/b.txt"
+			}
+			for p, content := range map[string]string{src: "new", dst: "old"} {
+				if _, err := fs.Write(ctx, p, strings.NewReader(content)); err != nil {
+					t.Fatalf("Write(%s): %v", p, err)
+				}
+			}
+			mover := fs.(filekit.CanMove)
+
+			if err := mover.Move(ctx, src, dst, filekit.WithMoveNoOverwrite()); !errors.Is(err, filekit.ErrExist) {
+				t.Fatalf("Move(no overwrite) = %v, want ErrExist", err)
+			}
+			if got, _ := fs.ReadAll(ctx, dst); string(got) != "old" {
+				t.Errorf("ReadAll(%s) after refused move = %q, want %q", dst, got, "old")
+			}
+
+			if err := mover.Move(ctx, src, dst); err != nil {
+				t.Fatalf("Move: %v", err)
+			}
+			if got, _ := fs.ReadAll(ctx, dst); string(got) != "new" {
+				t.Errorf("ReadAll(%s) after move = %q, want %q", dst, got, "new")
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+module github.com/gobeaver/filekit/observer/prometheus
+
+go 1.24.0
+
+toolchain go1.24.2
+
+require (
+	github.com/gobeaver/filekit v0.0.4
+	github.com/prometheus/client_golang v1.20.5
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/gobeaver/beaver-kit/config v0.1.0 // indirect
+	github.com/gobeaver/filekit/filevalidator v0.0.4 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/gobeaver/filekit => ../..
+
+replace github.com/gobeaver/filekit/filevalidator => ../../filevalidator
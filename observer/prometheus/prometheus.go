@@ -0,0 +1,126 @@
+// Package prometheus provides a filekit.Observer that exports
+// InstrumentedFileSystem metrics to Prometheus.
+//
+// It lives in its own module so that importing filekit does not pull in the
+// Prometheus client.
+//
+// Example:
+//
+//	observer, err := prometheus.NewObserver(promclient.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	fs = filekit.NewInstrumentedFileSystem(fs, observer)
+package prometheus
+
+import (
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer implements filekit.Observer with Prometheus metrics:
+//
+//   - <namespace>_operation_duration_seconds (histogram, labels: op)
+//   - <namespace>_operation_errors_total (counter, labels: op, code)
+//   - <namespace>_bytes_total (counter, labels: op)
+//
+// The code label is the filekit error code, or "unknown" for other errors.
+type Observer struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+}
+
+var _ filekit.Observer = (*Observer)(nil)
+
+// config holds the NewObserver options
+type config struct {
+	namespace   string
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+// Option configures NewObserver.
+type Option func(*config)
+
+// WithNamespace sets the metric name prefix. Default: "filekit".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithConstLabels adds fixed labels to every metric, e.g. the backend name
+// when several instrumented filesystems share a registry.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets sets the latency histogram buckets in seconds.
+// Default: prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// NewObserver creates an Observer and registers its metrics with reg.
+func NewObserver(reg prometheus.Registerer, opts ...Option) (*Observer, error) {
+	cfg := config{
+		namespace: "filekit",
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	o := &Observer{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Name:        "operation_duration_seconds",
+			Help:        "Latency of filesystem operations.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{"op"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "operation_errors_total",
+			Help:        "Failed filesystem operations by error code.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"op", "code"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "bytes_total",
+			Help:        "Bytes transferred by read and write operations.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"op"}),
+	}
+
+	for _, c := range []prometheus.Collector{o.duration, o.errors, o.bytes} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// ObserveOp implements filekit.Observer
+func (o *Observer) ObserveOp(op string, dur time.Duration, err error) {
+	o.duration.WithLabelValues(op).Observe(dur.Seconds())
+	if err != nil {
+		code := string(filekit.GetCode(err))
+		if code == "" {
+			code = "unknown"
+		}
+		o.errors.WithLabelValues(op, code).Inc()
+	}
+}
+
+// ObserveBytes implements filekit.Observer
+func (o *Observer) ObserveBytes(op string, n int64) {
+	o.bytes.WithLabelValues(op).Add(float64(n))
+}
@@ -0,0 +1,43 @@
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserver(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	o, err := NewObserver(reg, WithNamespace("test"))
+	if err != nil {
+		t.Fatalf("NewObserver failed: %v", err)
+	}
+
+	o.ObserveOp("stat", 5*time.Millisecond, nil)
+	o.ObserveOp("stat", time.Millisecond, filekit.NewPathError("stat", "a.txt", filekit.ErrCodeNotFound, "file not found"))
+	o.ObserveOp("write", time.Millisecond, errors.New("boom"))
+	o.ObserveBytes("write", 100)
+	o.ObserveBytes("write", 28)
+
+	if got := testutil.ToFloat64(o.errors.WithLabelValues("stat", string(filekit.ErrCodeNotFound))); got != 1 {
+		t.Errorf("stat not-found errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(o.errors.WithLabelValues("write", "unknown")); got != 1 {
+		t.Errorf("write unknown errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(o.bytes.WithLabelValues("write")); got != 128 {
+		t.Errorf("write bytes = %v, want 128", got)
+	}
+	if n := testutil.CollectAndCount(o.duration); n != 2 {
+		t.Errorf("duration series = %d, want 2 (stat, write)", n)
+	}
+
+	// Registering the same metrics twice fails
+	if _, err := NewObserver(reg, WithNamespace("test")); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+}
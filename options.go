@@ -14,6 +14,10 @@ type Options struct {
 	// ContentType specifies the MIME type of the file
 	ContentType string
 
+	// ForceContentType stores ContentType as is, even when empty, instead of
+	// resolving it. See WithForceContentType.
+	ForceContentType bool
+
 	// Metadata contains additional metadata for the file
 	Metadata map[string]string
 
@@ -26,6 +30,14 @@ type Options struct {
 	// Overwrite determines whether to overwrite existing files
 	Overwrite bool
 
+	// IfMatch makes the write conditional on the current ETag of the file.
+	// See WithIfMatch.
+	IfMatch string
+
+	// IfNoneMatch makes the write conditional on the file not existing ("*")
+	// or not having the given ETag. See WithIfNoneMatch.
+	IfNoneMatch string
+
 	// Encryption specifies encryption settings for the file
 	Encryption *EncryptionOptions
 
@@ -47,8 +59,19 @@ type Options struct {
 	// ACL sets specific access control list settings
 	ACL string
 
+	// StorageClass selects the storage tier of the file. See WithStorageClass.
+	StorageClass string
+
 	// Validator is an optional file validator to use before upload
 	Validator filevalidator.Validator
+
+	// Progress is called as the content of a write is consumed.
+	// See WithWriteProgress.
+	Progress ProgressFunc
+
+	// encryptedContent marks content written by an EncryptedFS, so a stacked
+	// EncryptedFS below it accepts the ciphertext.
+	encryptedContent bool
 }
 
 // Visibility represents file visibility
@@ -77,13 +100,33 @@ type EncryptionOptions struct {
 	KeyID string
 }
 
-// WithContentType sets the content type of the file
+// withEncryptedContent marks the written content as EncryptedFS ciphertext.
+func withEncryptedContent() Option {
+	return func(o *Options) {
+		o.encryptedContent = true
+	}
+}
+
+// WithContentType sets the content type of the file. Without it, drivers
+// resolve one from the extension and the content; see ResolveContentType.
 func WithContentType(contentType string) Option {
 	return func(o *Options) {
 		o.ContentType = contentType
 	}
 }
 
+// WithForceContentType stores contentType exactly as given and bypasses all
+// detection, by filekit and by the backend. Unlike WithContentType, an empty
+// contentType is kept: the file is stored without a content type (GCS would
+// otherwise sniff one, S3 and Azure would default it). Drivers that derive
+// the content type when reading (local, SFTP, ZIP) ignore it.
+func WithForceContentType(contentType string) Option {
+	return func(o *Options) {
+		o.ContentType = contentType
+		o.ForceContentType = true
+	}
+}
+
 // WithMetadata sets additional metadata for the file
 func WithMetadata(metadata map[string]string) Option {
 	return func(o *Options) {
@@ -105,6 +148,28 @@ func WithCacheControl(cacheControl string) Option {
 	}
 }
 
+// WithStorageClass sets the storage tier the file is written to, for
+// tiering cold data to cheaper storage. The class is backend-specific:
+//
+//   - S3: STANDARD, STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING, GLACIER_IR,
+//     GLACIER, DEEP_ARCHIVE, ...
+//   - GCS: STANDARD, NEARLINE, COLDLINE, ARCHIVE
+//   - Azure: Hot, Cool, Cold, Archive
+//
+// Cloud drivers reject a class their backend does not recognize with
+// ErrCodeInvalidInput; the other drivers ignore it. Files in S3 GLACIER or
+// DEEP_ARCHIVE and Azure Archive must be restored before they can be read:
+// Read fails with ErrNotReadable (ErrCodeNotReadable) until then.
+//
+// Example:
+//
+//	_, err := fs.Write(ctx, "backups/2024.tar", r, filekit.WithStorageClass("GLACIER"))
+func WithStorageClass(class string) Option {
+	return func(o *Options) {
+		o.StorageClass = class
+	}
+}
+
 // WithOverwrite enables or disables overwriting existing files
 func WithOverwrite(overwrite bool) Option {
 	return func(o *Options) {
@@ -112,6 +177,35 @@ func WithOverwrite(overwrite bool) Option {
 	}
 }
 
+// WithIfMatch only writes if the file exists and its ETag is etag, as
+// returned in FileInfo.ETag or WriteResult.ETag; WithOverwrite is ignored.
+// Otherwise the write fails with ErrPreconditionFailed (ErrCodePreconditionFailed).
+// Use it for read-modify-write cycles so that concurrent writers cannot
+// silently overwrite each other.
+//
+// Example:
+//
+//	info, _ := fs.Stat(ctx, "config.json")
+//	_, err := fs.Write(ctx, "config.json", updated, filekit.WithIfMatch(info.ETag))
+//	if errors.Is(err, filekit.ErrPreconditionFailed) {
+//	    // someone else updated the file first: re-read and retry
+//	}
+func WithIfMatch(etag string) Option {
+	return func(o *Options) {
+		o.IfMatch = etag
+	}
+}
+
+// WithIfNoneMatch only writes if the file does not have the given ETag. Pass
+// "*" to only create the file if it does not exist yet. Otherwise the write
+// fails with ErrPreconditionFailed (ErrCodePreconditionFailed).
+// S3 only supports "*".
+func WithIfNoneMatch(etag string) Option {
+	return func(o *Options) {
+		o.IfNoneMatch = etag
+	}
+}
+
 // WithEncryption enables encryption for the file
 func WithEncryption(algorithm string, key []byte) Option {
 	return func(o *Options) {
@@ -154,7 +248,10 @@ func WithHeaders(headers map[string]string) Option {
 	}
 }
 
-// WithContentDisposition sets the Content-Disposition header
+// WithContentDisposition sets the Content-Disposition header, e.g.
+// `attachment; filename="report.pdf"`. It is stored by the S3, GCS, Azure and
+// memory drivers, and by the local driver in an extended attribute on Linux
+// and macOS, reported in FileInfo.ContentDisposition and sent by ServeFile.
 func WithContentDisposition(disposition string) Option {
 	return func(o *Options) {
 		o.ContentDisposition = disposition
@@ -0,0 +1,53 @@
+package filekit
+
+import (
+	"path"
+	"strings"
+)
+
+// ============================================================================
+// Path Normalization
+// ============================================================================
+
+// CleanPath normalizes a path given to a FileSystem and rejects paths that
+// escape its root. Backslashes are treated as separators, leading slashes are
+// dropped (paths are always relative to the root) and ".", ".." and repeated
+// separators are resolved lexically, so "/a//b/./c" becomes "a/b/c". The root
+// itself is "".
+//
+// A path that climbs above the root, such as "../x" or "a/../../etc", returns
+// ErrNotAllowed. Drivers wrap it with the operation and the original path:
+//
+//	clean, err := filekit.CleanPath(p)
+//	if err != nil {
+//	    return filekit.WrapPathErr("read", p, err)
+//	}
+func CleanPath(p string) (string, error) {
+	p = path.Clean(strings.TrimLeft(strings.ReplaceAll(p, `\`, "/"), "/"))
+	switch {
+	case p == ".":
+		return "", nil
+	case p == ".." || strings.HasPrefix(p, "../"):
+		return "", ErrNotAllowed
+	}
+	return p, nil
+}
+
+// ============================================================================
+// Directory Markers
+// ============================================================================
+
+// DirContentType is the content type object-store drivers give the empty
+// marker objects CreateDir writes.
+const DirContentType = "application/x-directory"
+
+// IsDirMarker reports whether an object-store object is a directory marker
+// rather than a file: an object with the DirContentType content type, or a
+// zero-byte object whose key ends in "/". Pass an empty contentType when it is
+// unknown, as in S3 listings. A key ending in "/" with content is a file.
+func IsDirMarker(key string, size int64, contentType string) bool {
+	if contentType == DirContentType {
+		return true
+	}
+	return size == 0 && strings.HasSuffix(key, "/")
+}
@@ -0,0 +1,66 @@
+package filekit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"a/b.txt", "a/b.txt", false},
+		{"./a", "a", false},
+		{"a//b", "a/b", false},
+		{"/a/b/", "a/b", false},
+		{`a\b\c.txt`, "a/b/c.txt", false},
+		{"a/./b/../c", "a/c", false},
+		{"a..b/c..txt", "a..b/c..txt", false},
+		{"", "", false},
+		{".", "", false},
+		{"/", "", false},
+		{"a/..", "", false},
+		{"..", "", true},
+		{"../x", "", true},
+		{"a/../../etc", "", true},
+		{"/../etc/passwd", "", true},
+		{`..\windows`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := filekit.CleanPath(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, filekit.ErrNotAllowed) {
+				t.Errorf("CleanPath(%q) = %q, %v; want ErrNotAllowed", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("CleanPath(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestIsDirMarker(t *testing.T) {
+	tests := []struct {
+		key         string
+		size        int64
+		contentType string
+		want        bool
+	}{
+		{"docs/", 0, "", true},
+		{"docs/", 0, filekit.DirContentType, true},
+		{"docs", 0, filekit.DirContentType, true},
+		{"docs/", 12, "", false},
+		{"docs/a.txt", 0, "", false},
+		{"docs/a.txt", 0, "text/plain", false},
+	}
+	for _, tt := range tests {
+		if got := filekit.IsDirMarker(tt.key, tt.size, tt.contentType); got != tt.want {
+			t.Errorf("IsDirMarker(%q, %d, %q) = %v, want %v", tt.key, tt.size, tt.contentType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package filekit
+
+import (
+	"io"
+	"os"
+)
+
+// ============================================================================
+// Progress - Transfer progress callbacks for reads and writes
+// ============================================================================
+
+// ProgressInterval is how many bytes are transferred between progress
+// callbacks. The final call, at EOF or once total bytes have been read, is
+// always made.
+const ProgressInterval = 64 * 1024
+
+// WithWriteProgress reports how much of the content has been consumed by a
+// Write. total is the content length when it is known from the reader
+// (bytes.Reader, strings.Reader, bytes.Buffer, os.File or any io.Seeker), or
+// -1 otherwise. fn is called from the goroutine running Write, every
+// ProgressInterval bytes and once more when the content is exhausted.
+//
+// Example:
+//
+//	_, err := fs.Write(ctx, "videos/intro.mp4", file,
+//	    filekit.WithWriteProgress(func(transferred, total int64) {
+//	        bar.Set(transferred, total)
+//	    }),
+//	)
+func WithWriteProgress(fn ProgressFunc) Option {
+	return func(o *Options) {
+		o.Progress = fn
+	}
+}
+
+// TrackProgress wraps content so reads are reported to the WithWriteProgress
+// callback. It returns content unchanged when no callback is set. Readers that
+// implement io.Seeker stay seekable; re-reading bytes after a seek (e.g. an
+// SDK retrying a request) does not move the reported count backwards.
+//
+// Drivers call it where they start consuming the content of a Write.
+func (o *Options) TrackProgress(content io.Reader) io.Reader {
+	if o.Progress == nil || content == nil {
+		return content
+	}
+	r := &progressReader{reader: content, progress: o.Progress, size: contentLength(content)}
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			return &progressReadSeeker{progressReader: r, seeker: seeker, start: start}
+		}
+	}
+	return r
+}
+
+// ProgressReader wraps rc so that reads are reported to cb, e.g. to drive a
+// download progress bar. total is the expected size (FileInfo.Size), or -1 if
+// unknown. cb is called every ProgressInterval bytes and once more at EOF.
+//
+// Example:
+//
+//	info, _ := fs.Stat(ctx, "backup.tar")
+//	rc, err := fs.Read(ctx, "backup.tar")
+//	if err != nil {
+//	    return err
+//	}
+//	rc = filekit.ProgressReader(rc, info.Size, func(read, total int64) {
+//	    fmt.Printf("\r%d / %d bytes", read, total)
+//	})
+//	defer rc.Close()
+func ProgressReader(rc io.ReadCloser, total int64, cb func(bytesTransferred, total int64)) io.ReadCloser {
+	if cb == nil {
+		return rc
+	}
+	return &progressReadCloser{
+		progressReader: &progressReader{reader: rc, progress: cb, size: total},
+		closer:         rc,
+	}
+}
+
+// progressReader is a reader that reports progress
+type progressReader struct {
+	reader       io.Reader
+	progress     ProgressFunc
+	size         int64
+	offset       int64 // position relative to where reading started
+	bytesRead    int64 // furthest offset reached
+	lastReported int64
+	done         bool
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	if r.offset > r.bytesRead {
+		r.bytesRead = r.offset
+	}
+
+	complete := err == io.EOF || (r.size >= 0 && r.bytesRead >= r.size)
+	switch {
+	case r.done:
+	case complete:
+		r.done = true
+		r.report()
+	case r.bytesRead-r.lastReported >= ProgressInterval:
+		r.report()
+	}
+	return n, err
+}
+
+func (r *progressReader) report() {
+	r.lastReported = r.bytesRead
+	r.progress(r.bytesRead, r.size)
+}
+
+// progressReadSeeker keeps a seekable reader seekable
+type progressReadSeeker struct {
+	*progressReader
+	seeker io.Seeker
+	start  int64
+}
+
+func (r *progressReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.seeker.Seek(offset, whence)
+	if err == nil {
+		r.offset = pos - r.start
+	}
+	return pos, err
+}
+
+// progressReadCloser adds Close to a progressReader
+type progressReadCloser struct {
+	*progressReader
+	closer io.Closer
+}
+
+func (r *progressReadCloser) Close() error {
+	return r.closer.Close()
+}
+
+// contentLength returns the number of bytes left in r, or -1 if unknown.
+// Seekable readers are left at their current position.
+func contentLength(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - pos
+	case io.Seeker:
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(pos, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - pos
+	}
+	return -1
+}
@@ -0,0 +1,115 @@
+package filekit_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// progressLog records progress callbacks
+type progressLog struct {
+	calls       int
+	transferred []int64
+	total       int64
+}
+
+func (p *progressLog) record(transferred, total int64) {
+	p.calls++
+	p.transferred = append(p.transferred, transferred)
+	p.total = total
+}
+
+func (p *progressLog) last() int64 {
+	if len(p.transferred) == 0 {
+		return -1
+	}
+	return p.transferred[len(p.transferred)-1]
+}
+
+func TestProgressReader(t *testing.T) {
+	const size = 1 << 20
+	var log progressLog
+	rc := filekit.ProgressReader(io.NopCloser(bytes.NewReader(make([]byte, size))), size, log.record)
+
+	n, err := io.Copy(io.Discard, rc)
+	if err != nil || n != size {
+		t.Fatalf("Copy = %d, %v", n, err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.last() != size || log.total != size {
+		t.Errorf("final call = (%d, %d), want (%d, %d)", log.last(), log.total, size, size)
+	}
+	// Roughly one call per ProgressInterval, never one per Read
+	if maxCalls := size/filekit.ProgressInterval + 1; log.calls > maxCalls {
+		t.Errorf("callback called %d times, want at most %d", log.calls, maxCalls)
+	}
+	for i := 1; i < len(log.transferred); i++ {
+		if log.transferred[i] <= log.transferred[i-1] {
+			t.Errorf("progress went from %d to %d", log.transferred[i-1], log.transferred[i])
+		}
+	}
+}
+
+func TestWithWriteProgress(t *testing.T) {
+	ctx := context.Background()
+	content := strings.Repeat("x", 300*1024+7)
+	size := int64(len(content))
+
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	filesystems := map[string]filekit.FileSystem{"memory": memory.New(), "local": localFS}
+
+	for name, fs := range filesystems {
+		t.Run(name, func(t *testing.T) {
+			var log progressLog
+			if _, err := fs.Write(ctx, "known.txt", strings.NewReader(content), filekit.WithWriteProgress(log.record)); err != nil {
+				t.Fatal(err)
+			}
+			if log.last() != size || log.total != size {
+				t.Errorf("final call = (%d, %d), want (%d, %d)", log.last(), log.total, size, size)
+			}
+
+			// Unknown length: total is -1, the final count is still exact
+			log = progressLog{}
+			if _, err := fs.Write(ctx, "unknown.txt", io.MultiReader(strings.NewReader(content)), filekit.WithWriteProgress(log.record)); err != nil {
+				t.Fatal(err)
+			}
+			if log.last() != size || log.total != -1 {
+				t.Errorf("final call = (%d, %d), want (%d, -1)", log.last(), log.total, size)
+			}
+		})
+	}
+
+	t.Run("file", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), "upload.bin")
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		var log progressLog
+		if _, err := memory.New().Write(ctx, "upload.bin", f, filekit.WithWriteProgress(log.record)); err != nil {
+			t.Fatal(err)
+		}
+		if log.last() != size || log.total != size {
+			t.Errorf("final call = (%d, %d), want (%d, %d)", log.last(), log.total, size, size)
+		}
+	})
+}
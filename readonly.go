@@ -92,7 +92,10 @@ func WithErrorWrapper(wrapper func(op, path string, err error) error) ReadOnlyOp
 
 // NewReadOnlyFileSystem creates a read-only wrapper around a FileSystem.
 // All write operations (Write, Delete, CreateDir, DeleteDir) will fail
-// with ErrReadOnly unless configured otherwise via options.
+// with ErrReadOnly unless configured otherwise via options. Optional
+// capabilities that modify storage (Copy, Move, SignedUploadURL, SetTags,
+// DeleteMany and ChunkedUploader) are blocked the same way; read-only ones
+// (Checksum, SignedURL, ReadRange, StatMany, GetTags, Watch) are delegated.
 func NewReadOnlyFileSystem(fs FileSystem, opts ...ReadOnlyOption) *ReadOnlyFileSystem {
 	options := ReadOnlyOptions{}
 	for _, opt := range opts {
@@ -231,13 +234,13 @@ func (r *ReadOnlyFileSystem) Copy(ctx context.Context, src, dst string) error {
 }
 
 // Move returns ErrReadOnly for write operations.
-func (r *ReadOnlyFileSystem) Move(ctx context.Context, src, dst string) error {
+func (r *ReadOnlyFileSystem) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
 	if err := r.readOnlyError("move", dst); err != nil {
 		return err
 	}
 	// Handler allowed the operation
 	if mover, ok := r.fs.(CanMove); ok {
-		return mover.Move(ctx, src, dst)
+		return mover.Move(ctx, src, dst, opts...)
 	}
 	return NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
 }
@@ -281,6 +284,62 @@ func (r *ReadOnlyFileSystem) SignedUploadURL(ctx context.Context, path string, e
 	return "", NewPathError("signed-upload-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
 }
 
+// ReadRange delegates to the underlying filesystem if supported.
+func (r *ReadOnlyFileSystem) ReadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	if ranger, ok := r.fs.(CanReadRange); ok {
+		return ranger.ReadRange(ctx, path, offset, length)
+	}
+	return nil, NewPathError("read-range", path, ErrCodeNotSupported, "underlying filesystem does not support range reads")
+}
+
+// StatMany delegates to the underlying filesystem.
+func (r *ReadOnlyFileSystem) StatMany(ctx context.Context, paths []string) (map[string]*FileInfo, map[string]error) {
+	return StatMany(ctx, r.fs, paths)
+}
+
+// DeleteMany returns ErrReadOnly for every path unless AllowDelete is enabled.
+func (r *ReadOnlyFileSystem) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	if !r.opts.AllowDelete {
+		errs := make(map[string]error)
+		allowed := make([]string, 0, len(paths))
+		for _, p := range paths {
+			if err := r.readOnlyError("delete", p); err != nil {
+				errs[p] = err
+				continue
+			}
+			allowed = append(allowed, p)
+		}
+		deleted, delErrs := DeleteMany(ctx, r.fs, allowed)
+		for p, err := range delErrs {
+			errs[p] = err
+		}
+		if len(errs) == 0 {
+			errs = nil
+		}
+		return deleted, errs
+	}
+	return DeleteMany(ctx, r.fs, paths)
+}
+
+// GetTags delegates to the underlying filesystem if supported.
+func (r *ReadOnlyFileSystem) GetTags(ctx context.Context, path string) (map[string]string, error) {
+	if tagger, ok := r.fs.(CanTag); ok {
+		return tagger.GetTags(ctx, path)
+	}
+	return nil, NewPathError("get-tags", path, ErrCodeNotSupported, "underlying filesystem does not support tags")
+}
+
+// SetTags returns ErrReadOnly (tags are object metadata).
+func (r *ReadOnlyFileSystem) SetTags(ctx context.Context, path string, tags map[string]string) error {
+	if err := r.readOnlyError("set-tags", path); err != nil {
+		return err
+	}
+	if tagger, ok := r.fs.(CanTag); ok {
+		return tagger.SetTags(ctx, path, tags)
+	}
+	return NewPathError("set-tags", path, ErrCodeNotSupported, "underlying filesystem does not support tags")
+}
+
 // Watch delegates to the underlying filesystem if supported.
 func (r *ReadOnlyFileSystem) Watch(ctx context.Context, filter string) (ChangeToken, error) {
 	if watcher, ok := r.fs.(CanWatch); ok {
@@ -289,20 +348,86 @@ func (r *ReadOnlyFileSystem) Watch(ctx context.Context, filter string) (ChangeTo
 	return CancelledChangeToken{}, nil
 }
 
+// ============================================================================
+// ChunkedUploader (Blocked)
+// ============================================================================
+
+// InitiateUpload returns ErrReadOnly.
+func (r *ReadOnlyFileSystem) InitiateUpload(ctx context.Context, path string) (string, error) {
+	if err := r.readOnlyError("initiate-upload", path); err != nil {
+		return "", err
+	}
+	if uploader, ok := r.fs.(ChunkedUploader); ok {
+		return uploader.InitiateUpload(ctx, path)
+	}
+	return "", NewPathError("initiate-upload", path, ErrCodeNotSupported, "underlying filesystem does not support chunked uploads")
+}
+
+// UploadPart returns ErrReadOnly. The upload ID is passed as the path to
+// OnWriteAttempt and ErrorWrapper.
+func (r *ReadOnlyFileSystem) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
+	if err := r.readOnlyError("upload-part", uploadID); err != nil {
+		return err
+	}
+	if uploader, ok := r.fs.(ChunkedUploader); ok {
+		return uploader.UploadPart(ctx, uploadID, partNumber, data)
+	}
+	return NewPathError("upload-part", uploadID, ErrCodeNotSupported, "underlying filesystem does not support chunked uploads")
+}
+
+// CompleteUpload returns ErrReadOnly.
+func (r *ReadOnlyFileSystem) CompleteUpload(ctx context.Context, uploadID string) error {
+	if err := r.readOnlyError("complete-upload", uploadID); err != nil {
+		return err
+	}
+	if uploader, ok := r.fs.(ChunkedUploader); ok {
+		return uploader.CompleteUpload(ctx, uploadID)
+	}
+	return NewPathError("complete-upload", uploadID, ErrCodeNotSupported, "underlying filesystem does not support chunked uploads")
+}
+
+// CompleteUploadWithChecksum returns ErrReadOnly.
+func (r *ReadOnlyFileSystem) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	if err := r.readOnlyError("complete-upload", uploadID); err != nil {
+		return err
+	}
+	if uploader, ok := r.fs.(ChecksumUploader); ok {
+		return uploader.CompleteUploadWithChecksum(ctx, uploadID, expected)
+	}
+	return NewPathError("complete-upload", uploadID, ErrCodeNotSupported, "underlying filesystem does not support checksum-verified uploads")
+}
+
+// AbortUpload returns ErrReadOnly.
+func (r *ReadOnlyFileSystem) AbortUpload(ctx context.Context, uploadID string) error {
+	if err := r.readOnlyError("abort-upload", uploadID); err != nil {
+		return err
+	}
+	if uploader, ok := r.fs.(ChunkedUploader); ok {
+		return uploader.AbortUpload(ctx, uploadID)
+	}
+	return NewPathError("abort-upload", uploadID, ErrCodeNotSupported, "underlying filesystem does not support chunked uploads")
+}
+
 // ============================================================================
 // Interface Assertions
 // ============================================================================
 
 // Ensure ReadOnlyFileSystem implements FileSystem and optional interfaces
 var (
-	_ FileSystem  = (*ReadOnlyFileSystem)(nil)
-	_ FileReader  = (*ReadOnlyFileSystem)(nil)
-	_ FileWriter  = (*ReadOnlyFileSystem)(nil)
-	_ CanCopy     = (*ReadOnlyFileSystem)(nil)
-	_ CanMove     = (*ReadOnlyFileSystem)(nil)
-	_ CanChecksum = (*ReadOnlyFileSystem)(nil)
-	_ CanSignURL  = (*ReadOnlyFileSystem)(nil)
-	_ CanWatch    = (*ReadOnlyFileSystem)(nil)
+	_ FileSystem       = (*ReadOnlyFileSystem)(nil)
+	_ FileReader       = (*ReadOnlyFileSystem)(nil)
+	_ FileWriter       = (*ReadOnlyFileSystem)(nil)
+	_ CanCopy          = (*ReadOnlyFileSystem)(nil)
+	_ CanMove          = (*ReadOnlyFileSystem)(nil)
+	_ CanChecksum      = (*ReadOnlyFileSystem)(nil)
+	_ CanSignURL       = (*ReadOnlyFileSystem)(nil)
+	_ CanWatch         = (*ReadOnlyFileSystem)(nil)
+	_ CanReadRange     = (*ReadOnlyFileSystem)(nil)
+	_ CanStatMany      = (*ReadOnlyFileSystem)(nil)
+	_ CanDeleteMany    = (*ReadOnlyFileSystem)(nil)
+	_ CanTag           = (*ReadOnlyFileSystem)(nil)
+	_ ChunkedUploader  = (*ReadOnlyFileSystem)(nil)
+	_ ChecksumUploader = (*ReadOnlyFileSystem)(nil)
 )
 
 // ============================================================================
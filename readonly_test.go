@@ -0,0 +1,98 @@
+package filekit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// signingFS adds fake signed URLs to a memory filesystem.
+type signingFS struct {
+	*memory.Adapter
+	uploadURLs int
+}
+
+func (s *signingFS) SignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	return "https://example.com/" + path + "?sig=download", nil
+}
+
+func (s *signingFS) SignedUploadURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	s.uploadURLs++
+	return "https://example.com/" + path + "?sig=upload", nil
+}
+
+func TestReadOnlyFileSystem_BlocksWriteCapabilities(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+	seedTree(t, mem, map[string]string{"docs/a.txt": "alpha"})
+	ro := filekit.NewReadOnlyFileSystem(mem)
+
+	if err := ro.Copy(ctx, "docs/a.txt", "docs/b.txt"); !filekit.IsReadOnlyError(err) {
+		t.Errorf("Copy error = %v, want ErrReadOnly", err)
+	}
+	if err := ro.Move(ctx, "docs/a.txt", "docs/c.txt"); !filekit.IsReadOnlyError(err) {
+		t.Errorf("Move error = %v, want ErrReadOnly", err)
+	}
+	if _, err := ro.InitiateUpload(ctx, "docs/big.bin"); !filekit.IsReadOnlyError(err) {
+		t.Errorf("InitiateUpload error = %v, want ErrReadOnly", err)
+	}
+	if err := ro.UploadPart(ctx, "upload-1", 1, []byte("x")); !filekit.IsReadOnlyError(err) {
+		t.Errorf("UploadPart error = %v, want ErrReadOnly", err)
+	}
+	if err := ro.CompleteUpload(ctx, "upload-1"); !filekit.IsReadOnlyError(err) {
+		t.Errorf("CompleteUpload error = %v, want ErrReadOnly", err)
+	}
+	if err := ro.CompleteUploadWithChecksum(ctx, "upload-1", ""); !filekit.IsReadOnlyError(err) {
+		t.Errorf("CompleteUploadWithChecksum error = %v, want ErrReadOnly", err)
+	}
+	if err := ro.SetTags(ctx, "docs/a.txt", map[string]string{"k": "v"}); !filekit.IsReadOnlyError(err) {
+		t.Errorf("SetTags error = %v, want ErrReadOnly", err)
+	}
+	deleted, errs := ro.DeleteMany(ctx, []string{"docs/a.txt"})
+	if len(deleted) != 0 || !filekit.IsReadOnlyError(errs["docs/a.txt"]) {
+		t.Errorf("DeleteMany = %v, %v; want ErrReadOnly", deleted, errs)
+	}
+
+	// Nothing changed underneath
+	files, err := mem.ListContents(ctx, "docs", false)
+	if err != nil || len(files) != 1 || files[0].Name != "a.txt" {
+		t.Errorf("underlying files = %v (err %v), want only a.txt", files, err)
+	}
+}
+
+func TestReadOnlyFileSystem_AllowsReadCapabilities(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+	seedTree(t, mem, map[string]string{"docs/a.txt": "alpha"})
+	signer := &signingFS{Adapter: mem}
+	ro := filekit.NewReadOnlyFileSystem(signer)
+
+	want, err := mem.Checksum(ctx, "docs/a.txt", filekit.ChecksumSHA256)
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+	got, err := ro.Checksum(ctx, "docs/a.txt", filekit.ChecksumSHA256)
+	if err != nil || got != want {
+		t.Errorf("Checksum = %q, %v; want %q", got, err, want)
+	}
+
+	url, err := ro.SignedURL(ctx, "docs/a.txt", time.Hour)
+	if err != nil || !strings.Contains(url, "sig=download") {
+		t.Errorf("SignedURL = %q, %v; want a download URL", url, err)
+	}
+	if _, err := ro.SignedUploadURL(ctx, "docs/a.txt", time.Hour); !filekit.IsReadOnlyError(err) {
+		t.Errorf("SignedUploadURL error = %v, want ErrReadOnly", err)
+	}
+	if signer.uploadURLs != 0 {
+		t.Error("upload URL must not be generated")
+	}
+
+	infos, errs := ro.StatMany(ctx, []string{"docs/a.txt"})
+	if len(errs) != 0 || infos["docs/a.txt"] == nil {
+		t.Errorf("StatMany = %v, %v", infos, errs)
+	}
+}
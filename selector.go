@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // ============================================================================
@@ -61,9 +62,23 @@ func ListWithSelector(ctx context.Context, fs FileSystem, path string, selector
 		selector = All()
 	}
 
+	start := path
+	if g, ok := selector.(*globSelector); ok && recursive {
+		prefix, ok := g.listPrefix(path)
+		if !ok {
+			// No path under the listing directory can match
+			return nil, nil
+		}
+		start = prefix
+	}
+
 	var results []FileInfo
-	err := listRecursive(ctx, fs, path, selector, recursive, &results)
+	err := listRecursive(ctx, fs, start, selector, recursive, &results)
 	if err != nil {
+		if start != path && IsNotFound(err) {
+			// The glob's literal prefix does not exist, so nothing matches
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -124,21 +139,39 @@ func All() FileSelector {
 
 type globSelector struct {
 	pattern string
+	// segments is the slash-separated pattern, nil for name-only patterns
+	segments []string
 }
 
 // Glob creates a selector using glob patterns (like VFS WildcardFileSelector).
 // Supports: *, ?, [abc], [a-z]
 //
+// A pattern without a slash matches the file name at any depth. A pattern
+// with a slash matches the whole path from the filesystem root (a leading
+// slash is ignored), and "**" matches any number of directories.
+// ListWithSelector lists only the directory named by the literal segments
+// before the first wildcard, so Glob("images/2024/*.jpg") never lists
+// outside images/2024 on cloud backends.
+//
 // Examples:
 //
 //	Glob("*.txt")           // All .txt files
 //	Glob("image_????.jpg")  // image_0001.jpg, etc.
 //	Glob("[a-z]*.go")       // Go files starting with lowercase
+//	Glob("images/*.jpg")    // JPEGs directly in images/
+//	Glob("docs/**/*.md")    // Markdown files anywhere under docs/
 func Glob(pattern string) FileSelector {
-	return &globSelector{pattern: pattern}
+	s := &globSelector{pattern: pattern}
+	if trimmed := strings.Trim(pattern, "/"); strings.Contains(trimmed, "/") {
+		s.segments = strings.Split(trimmed, "/")
+	}
+	return s
 }
 
 func (s *globSelector) Match(file *FileInfo) bool {
+	if s.segments != nil {
+		return matchSegments(s.segments, splitPath(file.Path))
+	}
 	matched, err := filepath.Match(s.pattern, file.Name)
 	if err != nil {
 		return false
@@ -147,9 +180,86 @@ func (s *globSelector) Match(file *FileInfo) bool {
 }
 
 func (s *globSelector) TraverseDescendants(file *FileInfo) bool {
+	if s.segments == nil {
+		return true
+	}
+	// Only enter directories that could still contain a match
+	dir := splitPath(file.Path)
+	for i, seg := range dir {
+		if i < len(s.segments) && s.segments[i] == "**" {
+			return true
+		}
+		if i >= len(s.segments)-1 {
+			return false
+		}
+		if ok, _ := filepath.Match(s.segments[i], seg); !ok {
+			return false
+		}
+	}
 	return true
 }
 
+// listPrefix returns the directory ListWithSelector should list for a
+// listing of dir: the pattern's literal prefix if it lies below dir, or dir
+// itself. It returns false if the pattern cannot match anything under dir.
+func (s *globSelector) listPrefix(dir string) (string, bool) {
+	if s.segments == nil {
+		return dir, true
+	}
+
+	var literal []string
+	for _, seg := range s.segments[:len(s.segments)-1] {
+		if seg == "**" || strings.ContainsAny(seg, `*?[\`) {
+			break
+		}
+		literal = append(literal, seg)
+	}
+
+	base := splitPath(dir)
+	n := min(len(literal), len(base))
+	for i := 0; i < n; i++ {
+		if literal[i] != base[i] {
+			return "", false
+		}
+	}
+	if len(literal) <= len(base) {
+		return dir, true
+	}
+	return strings.Join(literal, "/"), true
+}
+
+// matchSegments reports whether the path segments match the pattern
+// segments, where "**" matches zero or more segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(segments); i >= 0; i-- {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// splitPath splits a slash-separated path into its non-empty segments
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
 // ============================================================================
 // Depth - Depth limiting (like VFS FileDepthSelector)
 // ============================================================================
@@ -189,6 +299,107 @@ func (s *depthSelector) TraverseDescendants(file *FileInfo) bool {
 	return s.getDepth(file.Path) < s.maxDepth
 }
 
+// ============================================================================
+// Attribute Selectors (size, modification time, content type)
+// ============================================================================
+
+type sizeSelector struct {
+	minSize int64
+	maxSize int64
+}
+
+// SizeBetween matches files whose size is within [minSize, maxSize] bytes (inclusive).
+// A maxSize of 0 or less means no upper bound.
+//
+// Example:
+//
+//	SizeBetween(1024, 10*1024*1024)  // Between 1KB and 10MB
+//	SizeBetween(1024, 0)             // At least 1KB
+func SizeBetween(minSize, maxSize int64) FileSelector {
+	return &sizeSelector{minSize: minSize, maxSize: maxSize}
+}
+
+func (s *sizeSelector) Match(file *FileInfo) bool {
+	if file.Size < s.minSize {
+		return false
+	}
+	return s.maxSize <= 0 || file.Size <= s.maxSize
+}
+
+func (s *sizeSelector) TraverseDescendants(file *FileInfo) bool {
+	return true
+}
+
+type modTimeSelector struct {
+	after  time.Time
+	before time.Time
+}
+
+// ModifiedAfter matches files modified strictly after t.
+func ModifiedAfter(t time.Time) FileSelector {
+	return &modTimeSelector{after: t}
+}
+
+// ModifiedBefore matches files modified strictly before t.
+func ModifiedBefore(t time.Time) FileSelector {
+	return &modTimeSelector{before: t}
+}
+
+func (s *modTimeSelector) Match(file *FileInfo) bool {
+	if !s.after.IsZero() && !file.ModTime.After(s.after) {
+		return false
+	}
+	if !s.before.IsZero() && !file.ModTime.Before(s.before) {
+		return false
+	}
+	return true
+}
+
+func (s *modTimeSelector) TraverseDescendants(file *FileInfo) bool {
+	return true
+}
+
+type contentTypeSelector struct {
+	pattern string
+}
+
+// ContentTypeMatches matches files by MIME type. Matching is case-insensitive
+// and ignores parameters such as "; charset=utf-8".
+// Supports exact types ("image/png"), subtype wildcards ("image/*") and "*/*".
+//
+// Example:
+//
+//	ContentTypeMatches("image/*")  // All images
+func ContentTypeMatches(pattern string) FileSelector {
+	return &contentTypeSelector{pattern: normalizeContentType(pattern)}
+}
+
+func (s *contentTypeSelector) Match(file *FileInfo) bool {
+	contentType := normalizeContentType(file.ContentType)
+	if contentType == "" {
+		return false
+	}
+	if s.pattern == "*/*" || s.pattern == contentType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(s.pattern, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+	return false
+}
+
+func (s *contentTypeSelector) TraverseDescendants(file *FileInfo) bool {
+	return true
+}
+
+// normalizeContentType lowercases a MIME type and strips any parameters.
+func normalizeContentType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
 // ============================================================================
 // Composable Selectors (And, Or, Not)
 // ============================================================================
@@ -197,7 +408,8 @@ type andSelector struct {
 	selectors []FileSelector
 }
 
-// And matches only if ALL selectors match.
+// And matches only if ALL selectors match, stopping at the first mismatch.
+// An empty And matches every file.
 func And(selectors ...FileSelector) FileSelector {
 	return &andSelector{selectors: selectors}
 }
@@ -224,7 +436,8 @@ type orSelector struct {
 	selectors []FileSelector
 }
 
-// Or matches if ANY selector matches.
+// Or matches if ANY selector matches, stopping at the first match.
+// An empty Or matches no files.
 func Or(selectors ...FileSelector) FileSelector {
 	return &orSelector{selectors: selectors}
 }
@@ -252,6 +465,8 @@ type notSelector struct {
 }
 
 // Not inverts a selector's match result.
+// Traversal is never negated: descendants are always visited so that
+// files not matched by the inner selector can still be found.
 func Not(selector FileSelector) FileSelector {
 	return &notSelector{selector: selector}
 }
@@ -0,0 +1,309 @@
+package filekit_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestAttributeSelectors(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		selector filekit.FileSelector
+		file     filekit.FileInfo
+		want     bool
+	}{
+		{"size within range", filekit.SizeBetween(10, 100), filekit.FileInfo{Size: 50}, true},
+		{"size at lower bound", filekit.SizeBetween(10, 100), filekit.FileInfo{Size: 10}, true},
+		{"size at upper bound", filekit.SizeBetween(10, 100), filekit.FileInfo{Size: 100}, true},
+		{"size below range", filekit.SizeBetween(10, 100), filekit.FileInfo{Size: 9}, false},
+		{"size above range", filekit.SizeBetween(10, 100), filekit.FileInfo{Size: 101}, false},
+		{"size no upper bound", filekit.SizeBetween(10, 0), filekit.FileInfo{Size: 1 << 40}, true},
+		{"modified after", filekit.ModifiedAfter(now.Add(-time.Hour)), filekit.FileInfo{ModTime: now}, true},
+		{"not modified after", filekit.ModifiedAfter(now), filekit.FileInfo{ModTime: now.Add(-time.Hour)}, false},
+		{"modified before", filekit.ModifiedBefore(now), filekit.FileInfo{ModTime: now.Add(-time.Hour)}, true},
+		{"not modified before", filekit.ModifiedBefore(now.Add(-time.Hour)), filekit.FileInfo{ModTime: now}, false},
+		{"content type exact", filekit.ContentTypeMatches("image/png"), filekit.FileInfo{ContentType: "image/png"}, true},
+		{"content type wildcard", filekit.ContentTypeMatches("image/*"), filekit.FileInfo{ContentType: "image/jpeg"}, true},
+		{"content type wildcard mismatch", filekit.ContentTypeMatches("image/*"), filekit.FileInfo{ContentType: "video/mp4"}, false},
+		{"content type any", filekit.ContentTypeMatches("*/*"), filekit.FileInfo{ContentType: "application/pdf"}, true},
+		{"content type with params", filekit.ContentTypeMatches("text/plain"), filekit.FileInfo{ContentType: "Text/Plain; charset=utf-8"}, true},
+		{"content type empty", filekit.ContentTypeMatches("*/*"), filekit.FileInfo{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Match(&tt.file); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListWithSelector_AttributeSelectors(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+
+	start := time.Now().Add(-time.Second)
+	files := map[string]int{
+		"photos/small.jpg":        10,
+		"photos/large.jpg":        5000,
+		"photos/medium.jpg":       500,
+		"photos/medium.png":       500,
+		"photos/nested/deep.jpg":  600,
+		"photos/nested/notes.txt": 600,
+	}
+	for p, size := range files {
+		if _, err := fs.Write(ctx, p, bytes.NewReader(make([]byte, size))); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		selector filekit.FileSelector
+		want     []string
+	}{
+		{
+			name:     "glob and size",
+			selector: filekit.And(filekit.Glob("*.jpg"), filekit.SizeBetween(100, 1000)),
+			want:     []string{"photos/medium.jpg", "photos/nested/deep.jpg"},
+		},
+		{
+			name: "glob, size and modified after",
+			selector: filekit.And(
+				filekit.Glob("*.jpg"),
+				filekit.SizeBetween(100, 1000),
+				filekit.ModifiedAfter(start),
+			),
+			want: []string{"photos/medium.jpg", "photos/nested/deep.jpg"},
+		},
+		{
+			name: "modified after the future matches nothing",
+			selector: filekit.And(
+				filekit.Glob("*.jpg"),
+				filekit.SizeBetween(100, 1000),
+				filekit.ModifiedAfter(time.Now().Add(time.Hour)),
+			),
+			want: nil,
+		},
+		{
+			name:     "content type or size",
+			selector: filekit.Or(filekit.ContentTypeMatches("image/png"), filekit.SizeBetween(5000, 0)),
+			want:     []string{"photos/large.jpg", "photos/medium.png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := filekit.ListWithSelector(ctx, fs, "photos", tt.selector, true)
+			if err != nil {
+				t.Fatalf("ListWithSelector: %v", err)
+			}
+
+			var got []string
+			for _, f := range result {
+				got = append(got, f.Path)
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestComposableSelectors(t *testing.T) {
+	jpg := &filekit.FileInfo{Name: "photo.jpg", Size: 1024}
+	png := &filekit.FileInfo{Name: "icon.png", Size: 10 * 1024 * 1024}
+	txt := &filekit.FileInfo{Name: "notes.txt", Size: 10}
+
+	small := filekit.FuncSelector(func(f *filekit.FileInfo) bool { return f.Size < 5*1024*1024 })
+
+	tests := []struct {
+		name     string
+		selector filekit.FileSelector
+		file     *filekit.FileInfo
+		want     bool
+	}{
+		{"empty Or matches nothing", filekit.Or(), jpg, false},
+		{"empty And matches everything", filekit.And(), jpg, true},
+		{"single Or match", filekit.Or(filekit.Glob("*.jpg")), jpg, true},
+		{"single Or mismatch", filekit.Or(filekit.Glob("*.jpg")), png, false},
+		{"single And match", filekit.And(filekit.Glob("*.jpg")), jpg, true},
+		{"Not glob", filekit.Not(filekit.Glob("*.jpg")), txt, true},
+		{"Not glob mismatch", filekit.Not(filekit.Glob("*.jpg")), jpg, false},
+		{"Not func", filekit.Not(small), png, true},
+		{"double Not", filekit.Not(filekit.Not(filekit.Glob("*.jpg"))), jpg, true},
+		{"jpg or png under 5MB: jpg", filekit.And(filekit.Or(filekit.Glob("*.jpg"), filekit.Glob("*.png")), small), jpg, true},
+		{"jpg or png under 5MB: large png", filekit.And(filekit.Or(filekit.Glob("*.jpg"), filekit.Glob("*.png")), small), png, false},
+		{"jpg or png under 5MB: txt", filekit.And(filekit.Or(filekit.Glob("*.jpg"), filekit.Glob("*.png")), small), txt, false},
+		{"And(Or, Not): match", filekit.And(filekit.Or(filekit.Glob("*.jpg"), filekit.Glob("*.txt")), filekit.Not(filekit.Glob("notes.*"))), jpg, true},
+		{"And(Or, Not): excluded", filekit.And(filekit.Or(filekit.Glob("*.jpg"), filekit.Glob("*.txt")), filekit.Not(filekit.Glob("notes.*"))), txt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Match(tt.file); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposableSelectors_ShortCircuit(t *testing.T) {
+	calls := 0
+	counting := filekit.FuncSelector(func(*filekit.FileInfo) bool {
+		calls++
+		return true
+	})
+	file := &filekit.FileInfo{Name: "a.jpg"}
+
+	filekit.Or(filekit.Glob("*.jpg"), counting).Match(file)
+	if calls != 0 {
+		t.Errorf("Or should stop at first match, got %d extra calls", calls)
+	}
+
+	filekit.And(filekit.Glob("*.png"), counting).Match(file)
+	if calls != 0 {
+		t.Errorf("And should stop at first mismatch, got %d extra calls", calls)
+	}
+}
+
+func TestNotSelector_TraversesDescendants(t *testing.T) {
+	dir := &filekit.FileInfo{Name: "tmp", IsDir: true}
+	if !filekit.Not(filekit.FuncSelectorFull(
+		func(*filekit.FileInfo) bool { return true },
+		func(*filekit.FileInfo) bool { return false },
+	)).TraverseDescendants(dir) {
+		t.Error("Not should always traverse descendants")
+	}
+}
+
+// listCountingFS records the directories listed through it
+type listCountingFS struct {
+	filekit.FileSystem
+	listed []string
+}
+
+func (c *listCountingFS) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
+	c.listed = append(c.listed, path)
+	return c.FileSystem.ListContents(ctx, path, recursive)
+}
+
+func TestListWithSelector_GlobPrefixPushdown(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+
+	files := map[string]string{
+		"a/b/one.txt":       "",
+		"a/b/two.txt":       "",
+		"a/b/skip.jpg":      "",
+		"a/b/c/deep.txt":    "",
+		"a/other/three.txt": "",
+		"z/four.txt":        "",
+	}
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("bulk/%d/file.txt", i)] = ""
+	}
+	seedTree(t, mem, files)
+
+	list := func(t *testing.T, dir, pattern string) ([]string, []string) {
+		t.Helper()
+		fs := &listCountingFS{FileSystem: mem}
+		result, err := filekit.ListWithSelector(ctx, fs, dir, filekit.Glob(pattern), true)
+		if err != nil {
+			t.Fatalf("ListWithSelector: %v", err)
+		}
+		var got []string
+		for _, f := range result {
+			got = append(got, f.Path)
+		}
+		sort.Strings(got)
+		return got, fs.listed
+	}
+
+	t.Run("literal prefix", func(t *testing.T) {
+		got, listed := list(t, "", "a/b/*.txt")
+		if want := []string{"a/b/one.txt", "a/b/two.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if want := []string{"a/b"}; !reflect.DeepEqual(listed, want) {
+			t.Errorf("listed %v, want only %v", listed, want)
+		}
+	})
+
+	t.Run("double star", func(t *testing.T) {
+		got, listed := list(t, "/", "a/**/*.txt")
+		if want := []string{"a/b/c/deep.txt", "a/b/one.txt", "a/b/two.txt", "a/other/three.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		for _, dir := range listed {
+			if dir != "a" && !strings.HasPrefix(dir, "a/") {
+				t.Errorf("listed %q outside the a/ prefix", dir)
+			}
+		}
+	})
+
+	t.Run("trailing double star", func(t *testing.T) {
+		got, _ := list(t, "", "a/**")
+		if want := []string{"a/b/c/deep.txt", "a/b/one.txt", "a/b/skip.jpg", "a/b/two.txt", "a/other/three.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("leading double star", func(t *testing.T) {
+		got, _ := list(t, "", "**/b/*.txt")
+		if want := []string{"a/b/one.txt", "a/b/two.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("listing inside the prefix", func(t *testing.T) {
+		got, listed := list(t, "a/b", "a/*/*.txt")
+		if want := []string{"a/b/one.txt", "a/b/two.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if want := []string{"a/b"}; !reflect.DeepEqual(listed, want) {
+			t.Errorf("listed %v, want only %v", listed, want)
+		}
+	})
+
+	t.Run("prefix outside the listing", func(t *testing.T) {
+		got, listed := list(t, "z", "a/b/*.txt")
+		if len(got) != 0 || len(listed) != 0 {
+			t.Errorf("got %v after listing %v, want nothing", got, listed)
+		}
+	})
+
+	t.Run("missing prefix", func(t *testing.T) {
+		got, _ := list(t, "", "missing/dir/*.txt")
+		if len(got) != 0 {
+			t.Errorf("got %v, want nothing", got)
+		}
+	})
+
+	t.Run("name pattern still matches at any depth", func(t *testing.T) {
+		got, _ := list(t, "a", "*.txt")
+		if want := []string{"a/b/c/deep.txt", "a/b/one.txt", "a/b/two.txt", "a/other/three.txt"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package filekit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ============================================================================
+// HTTP Serving
+// ============================================================================
+
+// ServeFile writes the content of a file to an HTTP response.
+//
+// It stats the file and delegates to http.ServeContent, which sets
+// Content-Type and Content-Length, answers conditional requests
+// (If-Modified-Since, If-None-Match, If-Range) using the file's ModTime and
+// ETag, and satisfies Range requests. A stored Content-Disposition
+// (FileInfo.ContentDisposition) is sent as is. Content is streamed: ranges are read
+// with CanReadRange when the filesystem supports it; otherwise the file is
+// read from the start and skipped up to the requested offset.
+//
+// Missing files and directories get a 404 response; other Stat failures are
+// answered with the status of the error (see FileError.HTTPStatus) or 500.
+// The response is always written; the returned error is for logging only.
+//
+// Example:
+//
+//	http.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+//	    name := strings.TrimPrefix(r.URL.Path, "/files/")
+//	    if err := filekit.ServeFile(w, r, fs, name); err != nil {
+//	        log.Printf("serve %s: %v", name, err)
+//	    }
+//	})
+func ServeFile(w http.ResponseWriter, r *http.Request, fs FileSystem, p string) error {
+	ctx := r.Context()
+
+	info, err := fs.Stat(ctx, p)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var httpErr HTTPError
+		if IsNotExist(err) {
+			status = http.StatusNotFound
+		} else if errors.As(err, &httpErr) {
+			status = httpErr.HTTPStatus()
+		}
+		http.Error(w, http.StatusText(status), status)
+		return err
+	}
+	if info.IsDir {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return NewPathError("serve", p, ErrCodeNotFound, "path is a directory")
+	}
+
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	if info.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", info.ContentDisposition)
+	}
+	if info.ETag != "" {
+		w.Header().Set("ETag", quoteETag(info.ETag))
+	}
+
+	content := &fileReadSeeker{ctx: ctx, fs: fs, path: p, size: info.Size}
+	defer content.Close()
+
+	http.ServeContent(w, r, path.Base(p), info.ModTime, content)
+	return content.err
+}
+
+// quoteETag returns etag as a quoted entity tag, as required by HTTP.
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// fileReadSeeker is an io.ReadSeeker over a file that opens the underlying
+// stream lazily at the current offset, so seeking does not read data.
+type fileReadSeeker struct {
+	ctx    context.Context
+	fs     FileSystem
+	path   string
+	size   int64
+	offset int64
+	reader io.ReadCloser
+	err    error // First read error, reported by ServeFile.
+}
+
+// Read implements io.Reader.
+func (f *fileReadSeeker) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if f.reader == nil {
+		reader, err := f.open()
+		if err != nil {
+			f.err = err
+			return 0, err
+		}
+		f.reader = reader
+	}
+
+	n, err := f.reader.Read(p)
+	f.offset += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && f.err == nil {
+		f.err = err
+	}
+	return n, err
+}
+
+// open returns a stream positioned at the current offset.
+func (f *fileReadSeeker) open() (io.ReadCloser, error) {
+	if f.offset > 0 {
+		if ranger, ok := f.fs.(CanReadRange); ok {
+			return ranger.ReadRange(f.ctx, f.path, f.offset, 0)
+		}
+	}
+
+	reader, err := f.fs.Read(f.ctx, f.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, reader, f.offset); err != nil {
+		reader.Close()
+		return nil, WrapPathErr("serve", f.path, err)
+	}
+	return reader, nil
+}
+
+// Seek implements io.Seeker.
+func (f *fileReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	}
+	if offset < 0 {
+		return 0, NewPathError("seek", f.path, ErrCodeInvalidInput, "negative position")
+	}
+
+	if offset != f.offset {
+		f.Close()
+		f.offset = offset
+	}
+	return offset, nil
+}
+
+// Close releases the underlying stream, if open.
+func (f *fileReadSeeker) Close() error {
+	if f.reader == nil {
+		return nil
+	}
+	err := f.reader.Close()
+	f.reader = nil
+	return err
+}
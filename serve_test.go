@@ -0,0 +1,176 @@
+package filekit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+const serveContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// etagFS adds a fixed ETag to Stat results.
+type etagFS struct {
+	filekit.FileSystem
+}
+
+func (e etagFS) Stat(ctx context.Context, path string) (*filekit.FileInfo, error) {
+	info, err := e.FileSystem.Stat(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info.ETag = "v1"
+	return info, nil
+}
+
+func serveTestFileSystems(t *testing.T) map[string]filekit.FileSystem {
+	t.Helper()
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New failed: %v", err)
+	}
+	systems := map[string]filekit.FileSystem{
+		"range reader": localFS,              // implements CanReadRange
+		"fallback":     etagFS{memory.New()}, // full read and skip
+	}
+	for _, fs := range systems {
+		_, err := fs.Write(context.Background(), "docs/file.txt", strings.NewReader(serveContent),
+			filekit.WithContentType("text/plain"))
+		if err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	return systems
+}
+
+func serve(t *testing.T, fs filekit.FileSystem, path string, header http.Header) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/"+path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	if err := filekit.ServeFile(rec, req, fs, path); err != nil && rec.Code < 400 {
+		t.Fatalf("ServeFile failed: %v", err)
+	}
+	return rec.Result()
+}
+
+func TestServeFile_Full(t *testing.T) {
+	for name, fs := range serveTestFileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			resp := serve(t, fs, "docs/file.txt", nil)
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if string(body) != serveContent {
+				t.Errorf("body = %q, want %q", body, serveContent)
+			}
+			if got := resp.Header.Get("Content-Length"); got != "36" {
+				t.Errorf("Content-Length = %q, want 36", got)
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+				t.Errorf("Content-Type = %q, want text/plain", got)
+			}
+		})
+	}
+}
+
+func TestServeFile_ContentDisposition(t *testing.T) {
+	fs := memory.New()
+	const disposition = `attachment; filename="report.pdf"`
+	_, err := fs.Write(context.Background(), "report.pdf", strings.NewReader("pdf"),
+		filekit.WithContentDisposition(disposition))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	resp := serve(t, fs, "report.pdf", nil)
+	if got := resp.Header.Get("Content-Disposition"); got != disposition {
+		t.Errorf("Content-Disposition = %q, want %q", got, disposition)
+	}
+}
+
+func TestServeFile_Range(t *testing.T) {
+	for name, fs := range serveTestFileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			resp := serve(t, fs, "docs/file.txt", http.Header{"Range": {"bytes=10-15"}})
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != http.StatusPartialContent {
+				t.Fatalf("status = %d, want 206", resp.StatusCode)
+			}
+			if string(body) != "abcdef" {
+				t.Errorf("body = %q, want %q", body, "abcdef")
+			}
+			if got := resp.Header.Get("Content-Range"); got != "bytes 10-15/36" {
+				t.Errorf("Content-Range = %q, want bytes 10-15/36", got)
+			}
+
+			// Suffix range
+			resp = serve(t, fs, "docs/file.txt", http.Header{"Range": {"bytes=-4"}})
+			body, _ = io.ReadAll(resp.Body)
+			if string(body) != "wxyz" {
+				t.Errorf("suffix range body = %q, want %q", body, "wxyz")
+			}
+		})
+	}
+}
+
+func TestServeFile_NotModified(t *testing.T) {
+	for name, fs := range serveTestFileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+			resp := serve(t, fs, "docs/file.txt", http.Header{"If-Modified-Since": {future}})
+			if resp.StatusCode != http.StatusNotModified {
+				t.Errorf("If-Modified-Since: status = %d, want 304", resp.StatusCode)
+			}
+		})
+	}
+
+	// ETag-based revalidation
+	fs := etagFS{memory.New()}
+	if _, err := fs.Write(context.Background(), "a.txt", strings.NewReader("a")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	resp := serve(t, fs, "a.txt", nil)
+	if got := resp.Header.Get("ETag"); got != `"v1"` {
+		t.Fatalf("ETag = %q, want %q", got, `"v1"`)
+	}
+	resp = serve(t, fs, "a.txt", http.Header{"If-None-Match": {`"v1"`}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("If-None-Match: status = %d, want 304", resp.StatusCode)
+	}
+	resp = serve(t, fs, "a.txt", http.Header{"If-None-Match": {`"v0"`}})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("stale If-None-Match: status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServeFile_NotFound(t *testing.T) {
+	fs := memory.New()
+	if err := fs.CreateDir(context.Background(), "dir"); err != nil {
+		t.Fatalf("CreateDir failed: %v", err)
+	}
+
+	for _, path := range []string{"missing.txt", "dir"} {
+		req := httptest.NewRequest(http.MethodGet, "/"+path, nil)
+		rec := httptest.NewRecorder()
+		err := filekit.ServeFile(rec, req, fs, path)
+		if !filekit.IsNotExist(err) {
+			t.Errorf("%s: expected not found error, got %v", path, err)
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package filekit
 import (
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -67,18 +66,32 @@ func Init(configs ...*Config) error {
 	return defaultErr
 }
 
-// New creates a new file system instance with given config
-func New(cfg *Config) (FileSystem, error) {
-	// Validation
-	if err := validateConfig(cfg); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
+// NewFromConfig validates cfg and creates the driver it selects, without the
+// encryption, validation and default-option layers added by New.
+// The driver package must be imported so that it registers itself:
+//
+//	import _ "github.com/gobeaver/filekit/driver/gcs"
+//
+//	fs, err := filekit.NewFromConfig(&filekit.Config{Driver: "gcs", GCSBucket: "my-bucket"})
+func NewFromConfig(cfg *Config) (FileSystem, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Create base filesystem using factory
 	fs, err := CreateDriver(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create driver: %w", err)
 	}
+	return fs, nil
+}
+
+// New creates a new file system instance with given config
+func New(cfg *Config) (FileSystem, error) {
+	// Create base filesystem using factory
+	fs, err := NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Wrap with encryption if enabled
 	if cfg.EncryptionEnabled && cfg.EncryptionKey != "" {
@@ -113,29 +126,6 @@ func New(cfg *Config) (FileSystem, error) {
 	return fs, nil
 }
 
-// validateConfig checks configuration validity
-func validateConfig(cfg *Config) error {
-	if cfg.Driver == "" {
-		return errors.New("driver is required")
-	}
-
-	switch cfg.Driver {
-	case "local":
-		if cfg.LocalBasePath == "" {
-			return errors.New("local base path is required for local driver")
-		}
-	case "s3":
-		if cfg.S3Bucket == "" {
-			return errors.New("S3 bucket is required for S3 driver")
-		}
-		// Access keys can be provided via IAM roles, so not always required
-	default:
-		return fmt.Errorf("unknown driver: %s", cfg.Driver)
-	}
-
-	return nil
-}
-
 // createValidator creates a file validator from config
 func createValidator(cfg *Config) filevalidator.Validator {
 	// Start with default constraints
@@ -48,20 +48,104 @@ func TestValidateConfig(t *testing.T) {
 		},
 		{
 			name:    "s3 driver with bucket",
-			config:  Config{Driver: "s3", S3Bucket: "test-bucket"},
+			config:  Config{Driver: "s3", S3Bucket: "test-bucket", S3Region: "us-east-1"},
+			wantErr: false,
+		},
+		{
+			name:    "gcs driver without bucket",
+			config:  Config{Driver: "gcs"},
+			wantErr: true,
+			errMsg:  "GCS bucket is required for GCS driver",
+		},
+		{
+			name:    "gcs driver with bucket",
+			config:  Config{Driver: "gcs", GCSBucket: "test-bucket"},
+			wantErr: false,
+		},
+		{
+			name:    "azure driver without container",
+			config:  Config{Driver: "azure", AzureAccountName: "acct", AzureAccountKey: "key"},
+			wantErr: true,
+			errMsg:  "azure container name is required for azure driver",
+		},
+		{
+			name:    "azure driver without credentials",
+			config:  Config{Driver: "azure", AzureContainerName: "files", AzureAccountName: "acct"},
+			wantErr: true,
+			errMsg:  "azure account name and key, a connection string, or managed identity is required",
+		},
+		{
+			name:    "azure managed identity without account",
+			config:  Config{Driver: "azure", AzureContainerName: "files", AzureUseManagedIdentity: true},
+			wantErr: true,
+			errMsg:  "azure account name or endpoint is required for managed identity",
+		},
+		{
+			name:    "azure driver with account key",
+			config:  Config{Driver: "azure", AzureContainerName: "files", AzureAccountName: "acct", AzureAccountKey: "key"},
+			wantErr: false,
+		},
+		{
+			name:    "azure driver with connection string",
+			config:  Config{Driver: "azure", AzureContainerName: "files", AzureConnectionString: "UseDevelopmentStorage=true"},
+			wantErr: false,
+		},
+		{
+			name:    "sftp driver without host",
+			config:  Config{Driver: "sftp", SFTPUsername: "user", SFTPPassword: "secret"},
+			wantErr: true,
+			errMsg:  "SFTP host is required for SFTP driver",
+		},
+		{
+			name:    "sftp driver without username",
+			config:  Config{Driver: "sftp", SFTPHost: "sftp.example.com", SFTPPassword: "secret"},
+			wantErr: true,
+			errMsg:  "SFTP username is required for SFTP driver",
+		},
+		{
+			name:    "sftp driver without password or key",
+			config:  Config{Driver: "sftp", SFTPHost: "sftp.example.com", SFTPUsername: "user"},
+			wantErr: true,
+			errMsg:  "SFTP password or private key is required for SFTP driver",
+		},
+		{
+			name:    "sftp driver with private key",
+			config:  Config{Driver: "sftp", SFTPHost: "sftp.example.com", SFTPUsername: "user", SFTPPrivateKey: "/keys/id_ed25519", SFTPPort: 22},
+			wantErr: false,
+		},
+		{
+			name:    "memory driver",
+			config:  Config{Driver: "memory"},
+			wantErr: false,
+		},
+		{
+			name:    "zip driver without path",
+			config:  Config{Driver: "zip"},
+			wantErr: true,
+			errMsg:  "ZIP path is required for zip driver",
+		},
+		{
+			name:    "zip driver with invalid mode",
+			config:  Config{Driver: "zip", ZipPath: "/tmp/a.zip", ZipMode: "append"},
+			wantErr: true,
+			errMsg:  "invalid ZIP mode",
+		},
+		{
+			name:    "zip driver with path",
+			config:  Config{Driver: "zip", ZipPath: "/tmp/a.zip", ZipMode: "open"},
 			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateConfig(&tt.config)
+			err := tt.config.Validate()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if err != nil && tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
-				t.Errorf("validateConfig() error = %v, want error containing %v", err, tt.errMsg)
+				t.Errorf("Validate() error = %v, want error containing %v", err, tt.errMsg)
 			}
 		})
 	}
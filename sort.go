@@ -0,0 +1,59 @@
+package filekit
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Sorting - Deterministic ordering of listings
+// ============================================================================
+
+// SortKey selects the FileInfo field SortFiles orders by.
+type SortKey int
+
+const (
+	// SortByName orders by base name. This is the order ListContents returns.
+	SortByName SortKey = iota
+	// SortBySize orders by size in bytes.
+	SortBySize
+	// SortByModTime orders by modification time.
+	SortByModTime
+)
+
+// SortFiles sorts files in place by key, in descending order if desc is true.
+// Entries with equal keys are ordered by Path, so the result is the same on
+// every driver for the same tree, even for recursive listings where names
+// repeat across directories.
+//
+// Every driver returns ListContents sorted by SortByName; use SortFiles to
+// order a listing differently.
+//
+// Example:
+//
+//	files, err := fs.ListContents(ctx, "logs", false)
+//	if err != nil {
+//	    return err
+//	}
+//	filekit.SortFiles(files, filekit.SortByModTime, true) // newest first
+func SortFiles(files []FileInfo, by SortKey, desc bool) {
+	slices.SortFunc(files, func(a, b FileInfo) int {
+		var c int
+		switch by {
+		case SortBySize:
+			c = cmp.Compare(a.Size, b.Size)
+		case SortByModTime:
+			c = a.ModTime.Compare(b.ModTime)
+		default:
+			c = strings.Compare(a.Name, b.Name)
+		}
+		if c == 0 {
+			c = strings.Compare(a.Path, b.Path)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
@@ -0,0 +1,49 @@
+package filekit_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+)
+
+func TestSortFiles(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := func() []filekit.FileInfo {
+		return []filekit.FileInfo{
+			{Name: "b.txt", Path: "x/b.txt", Size: 30, ModTime: base.Add(time.Hour)},
+			{Name: "a.txt", Path: "y/a.txt", Size: 10, ModTime: base.Add(2 * time.Hour)},
+			{Name: "c.txt", Path: "c.txt", Size: 20, ModTime: base},
+			{Name: "a.txt", Path: "x/a.txt", Size: 10, ModTime: base.Add(3 * time.Hour)},
+		}
+	}
+	paths := func(files []filekit.FileInfo) []string {
+		var out []string
+		for _, f := range files {
+			out = append(out, f.Path)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		by   filekit.SortKey
+		desc bool
+		want []string
+	}{
+		{"name", filekit.SortByName, false, []string{"x/a.txt", "y/a.txt", "x/b.txt", "c.txt"}},
+		{"name desc", filekit.SortByName, true, []string{"c.txt", "x/b.txt", "y/a.txt", "x/a.txt"}},
+		{"size", filekit.SortBySize, false, []string{"x/a.txt", "y/a.txt", "c.txt", "x/b.txt"}},
+		{"modtime desc", filekit.SortByModTime, true, []string{"x/a.txt", "y/a.txt", "x/b.txt", "c.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := files()
+			filekit.SortFiles(got, tt.by, tt.desc)
+			if !reflect.DeepEqual(paths(got), tt.want) {
+				t.Errorf("SortFiles = %v, want %v", paths(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package filekit
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultStatConcurrency is the number of parallel Stat calls drivers use for StatMany.
+const DefaultStatConcurrency = 16
+
+// StatMany returns metadata for many paths. It uses the native batch
+// implementation when fs implements CanStatMany, and calls Stat for each
+// path otherwise. Failures are reported per path in errs.
+//
+// Example:
+//
+//	infos, errs := filekit.StatMany(ctx, fs, []string{"a.txt", "b.txt", "missing.txt"})
+//	if err, ok := errs["missing.txt"]; ok && filekit.IsCode(err, filekit.ErrCodeNotFound) {
+//	    // handle missing file
+//	}
+func StatMany(ctx context.Context, fs FileReader, paths []string) (infos map[string]*FileInfo, errs map[string]error) {
+	if batcher, ok := fs.(CanStatMany); ok {
+		return batcher.StatMany(ctx, paths)
+	}
+	return StatConcurrently(ctx, paths, 1, fs.Stat)
+}
+
+// StatConcurrently calls stat for each path using at most concurrency calls in
+// flight, and collects the results per path. Duplicate paths are stat'ed once.
+// Paths not yet started when ctx is cancelled report the context error.
+//
+// Drivers use it to implement CanStatMany on top of a single-object metadata call.
+func StatConcurrently(
+	ctx context.Context,
+	paths []string,
+	concurrency int,
+	stat func(ctx context.Context, path string) (*FileInfo, error),
+) (infos map[string]*FileInfo, errs map[string]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	infos = make(map[string]*FileInfo, len(paths))
+	errs = make(map[string]error)
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	record := func(p string, info *FileInfo, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[p] = err
+			return
+		}
+		infos[p] = info
+	}
+
+	seen := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		if _, dup := seen[p]; dup {
+			continue
+		}
+		seen[p] = struct{}{}
+
+		if err := FromContext(ctx, "stat", p); err != nil {
+			record(p, nil, err)
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := stat(ctx, p)
+			record(p, info, err)
+		}(p)
+	}
+	wg.Wait()
+
+	return infos, errs
+}
@@ -0,0 +1,145 @@
+package filekit_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestStatConcurrently_RespectsBound(t *testing.T) {
+	const limit = 3
+
+	var inFlight, peak atomic.Int32
+	stat := func(ctx context.Context, p string) (*filekit.FileInfo, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return &filekit.FileInfo{Path: p}, nil
+	}
+
+	paths := make([]string, 20)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("file-%d.txt", i)
+	}
+
+	infos, errs := filekit.StatConcurrently(context.Background(), paths, limit, stat)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(infos) != len(paths) {
+		t.Fatalf("got %d infos, want %d", len(infos), len(paths))
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak concurrency = %d, want <= %d", got, limit)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Errorf("peak concurrency = %d, expected calls to run in parallel", got)
+	}
+}
+
+func TestStatConcurrently_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stat := func(ctx context.Context, p string) (*filekit.FileInfo, error) {
+		t.Errorf("stat called for %s after cancellation", p)
+		return nil, nil
+	}
+	infos, errs := filekit.StatConcurrently(ctx, []string{"a", "b"}, 2, stat)
+	if len(infos) != 0 || len(errs) != 2 {
+		t.Fatalf("got %d infos, %d errors; want 0, 2", len(infos), len(errs))
+	}
+	if !filekit.IsCode(errs["a"], filekit.ErrCodeAborted) {
+		t.Errorf("expected aborted error, got %v", errs["a"])
+	}
+}
+
+func TestStatMany_MissingFiles(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{
+		"a.txt":     "a",
+		"dir/b.txt": "bb",
+	})
+
+	infos, errs := filekit.StatMany(ctx, fs, []string{"a.txt", "dir/b.txt", "missing.txt", "a.txt"})
+
+	if len(infos) != 2 {
+		t.Fatalf("got %d infos, want 2: %v", len(infos), infos)
+	}
+	if infos["dir/b.txt"].Size != 2 {
+		t.Errorf("dir/b.txt size = %d, want 2", infos["dir/b.txt"].Size)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+	if !filekit.IsCode(errs["missing.txt"], filekit.ErrCodeNotFound) {
+		t.Errorf("expected not found error, got %v", errs["missing.txt"])
+	}
+}
+
+// statRecorder records the paths requested through StatMany.
+type statRecorder struct {
+	filekit.FileSystem
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (r *statRecorder) StatMany(ctx context.Context, paths []string) (map[string]*filekit.FileInfo, map[string]error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, append([]string(nil), paths...))
+	r.mu.Unlock()
+	return filekit.StatConcurrently(ctx, paths, 4, r.Stat)
+}
+
+func TestCachingFileSystem_StatMany(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	seedTree(t, base, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+
+	recorder := &statRecorder{FileSystem: base}
+	cached := filekit.NewCachingFileSystem(recorder, filekit.NewMemoryCache())
+
+	// Warm the cache for a.txt
+	if _, err := cached.Stat(ctx, "a.txt"); err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+
+	infos, errs := cached.StatMany(ctx, []string{"a.txt", "b.txt", "c.txt", "missing.txt"})
+	if len(infos) != 3 || len(errs) != 1 {
+		t.Fatalf("got %d infos, %d errors; want 3, 1", len(infos), len(errs))
+	}
+
+	if len(recorder.calls) != 1 {
+		t.Fatalf("expected 1 batch call, got %d", len(recorder.calls))
+	}
+	fetched := recorder.calls[0]
+	sort.Strings(fetched)
+	if got := strings.Join(fetched, ","); got != "b.txt,c.txt,missing.txt" {
+		t.Errorf("fetched %s, want only cache misses", got)
+	}
+
+	// Everything that exists is now cached
+	infos, errs = cached.StatMany(ctx, []string{"a.txt", "b.txt", "c.txt"})
+	if len(infos) != 3 || len(errs) != 0 {
+		t.Fatalf("got %d infos, %d errors; want 3, 0", len(infos), len(errs))
+	}
+	if len(recorder.calls) != 1 {
+		t.Errorf("expected no further batch calls, got %d", len(recorder.calls))
+	}
+}
@@ -0,0 +1,440 @@
+package filekit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Sub FileSystem (Prefix Confinement)
+// ============================================================================
+
+// Sub returns a FileSystem confined to prefix within fs, e.g. to give each
+// tenant of a multi-tenant application its own view of a shared bucket.
+//
+// Every path is resolved relative to prefix, and paths in returned FileInfo
+// values, listings and errors are reported relative to it again. Paths that
+// would escape prefix through ".." are rejected with ErrCodePermission
+// (wrapping ErrNotAllowed) without touching fs. Optional capabilities (Copy,
+// Move, Checksum, SignedURL, ReadRange, tags, StatMany, DeleteMany and
+// ListPage) are rewritten the same way and return ErrCodeNotSupported when
+// fs does not implement them.
+//
+// Example:
+//
+//	tenantFS := filekit.Sub(fs, "tenants/"+tenantID)
+//	tenantFS.Write(ctx, "x.txt", content) // stored at tenants/<id>/x.txt
+//	tenantFS.Read(ctx, "../other/x.txt")  // ErrCodePermission
+func Sub(fs FileSystem, prefix string) FileSystem {
+	prefix = strings.Trim(path.Clean("/"+prefix), "/")
+	return &subFS{fs: fs, prefix: prefix}
+}
+
+// subFS prepends a prefix to every path of the wrapped FileSystem
+type subFS struct {
+	fs     FileSystem
+	prefix string
+}
+
+// Unwrap returns the underlying FileSystem.
+func (s *subFS) Unwrap() FileSystem {
+	return s.fs
+}
+
+// full returns the path of name within the underlying filesystem, rejecting
+// names that escape the prefix
+func (s *subFS) full(op, name string) (string, error) {
+	rel, err := CleanPath(name)
+	if err != nil {
+		return "", WrapPath(ErrNotAllowed, op, name, ErrCodePermission, "path escapes the sub filesystem")
+	}
+	if s.prefix == "" {
+		return rel, nil
+	}
+	if rel == "" {
+		return s.prefix, nil
+	}
+	return s.prefix + "/" + rel, nil
+}
+
+// rel strips the prefix from a path returned by the underlying filesystem
+func (s *subFS) rel(p string) string {
+	if s.prefix == "" {
+		return p
+	}
+	trimmed := strings.TrimPrefix(p, "/")
+	if trimmed == s.prefix {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(trimmed, s.prefix+"/"); ok {
+		return rest
+	}
+	return p
+}
+
+// relInfo rewrites the path of a FileInfo returned by the underlying filesystem
+func (s *subFS) relInfo(info *FileInfo) *FileInfo {
+	if info != nil {
+		info.Path = s.rel(info.Path)
+	}
+	return info
+}
+
+// relInfos rewrites the paths of a listing in place
+func (s *subFS) relInfos(infos []FileInfo) []FileInfo {
+	for i := range infos {
+		infos[i].Path = s.rel(infos[i].Path)
+	}
+	return infos
+}
+
+// relErr rewrites the path of a FileError so the prefix does not leak
+func (s *subFS) relErr(err error) error {
+	var fe *FileError
+	if errors.As(err, &fe) {
+		fe.Path = s.rel(fe.Path)
+	}
+	return err
+}
+
+// ============================================================================
+// FileSystem Interface
+// ============================================================================
+
+// Read reads the file at path within the prefix.
+func (s *subFS) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	full, err := s.full("read", path)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := s.fs.Read(ctx, full)
+	return rc, s.relErr(err)
+}
+
+// ReadAll reads the file at path within the prefix.
+func (s *subFS) ReadAll(ctx context.Context, path string) ([]byte, error) {
+	full, err := s.full("readall", path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.fs.ReadAll(ctx, full)
+	return data, s.relErr(err)
+}
+
+// Write writes the file at path within the prefix.
+func (s *subFS) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
+	full, err := s.full("write", path)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.fs.Write(ctx, full, content, options...)
+	return result, s.relErr(err)
+}
+
+// Delete deletes the file at path within the prefix.
+func (s *subFS) Delete(ctx context.Context, path string) error {
+	full, err := s.full("delete", path)
+	if err != nil {
+		return err
+	}
+	return s.relErr(s.fs.Delete(ctx, full))
+}
+
+// FileExists checks for the file at path within the prefix.
+func (s *subFS) FileExists(ctx context.Context, path string) (bool, error) {
+	full, err := s.full("fileexists", path)
+	if err != nil {
+		return false, err
+	}
+	exists, err := s.fs.FileExists(ctx, full)
+	return exists, s.relErr(err)
+}
+
+// DirExists checks for the directory at path within the prefix.
+func (s *subFS) DirExists(ctx context.Context, path string) (bool, error) {
+	full, err := s.full("direxists", path)
+	if err != nil {
+		return false, err
+	}
+	exists, err := s.fs.DirExists(ctx, full)
+	return exists, s.relErr(err)
+}
+
+// Stat returns information about the file at path within the prefix.
+func (s *subFS) Stat(ctx context.Context, path string) (*FileInfo, error) {
+	full, err := s.full("stat", path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.fs.Stat(ctx, full)
+	if err != nil {
+		return nil, s.relErr(err)
+	}
+	return s.relInfo(info), nil
+}
+
+// ListContents lists path within the prefix. Entry paths are relative to the
+// prefix.
+func (s *subFS) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
+	full, err := s.full("listcontents", path)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.fs.ListContents(ctx, full, recursive)
+	if err != nil {
+		return nil, s.relErr(err)
+	}
+	return s.relInfos(files), nil
+}
+
+// CreateDir creates the directory at path within the prefix.
+func (s *subFS) CreateDir(ctx context.Context, path string) error {
+	full, err := s.full("createdir", path)
+	if err != nil {
+		return err
+	}
+	return s.relErr(s.fs.CreateDir(ctx, full))
+}
+
+// DeleteDir deletes the directory at path within the prefix.
+func (s *subFS) DeleteDir(ctx context.Context, path string) error {
+	full, err := s.full("deletedir", path)
+	if err != nil {
+		return err
+	}
+	return s.relErr(s.fs.DeleteDir(ctx, full))
+}
+
+// ============================================================================
+// Optional Interface Delegation
+// ============================================================================
+
+// fullPair resolves the source and destination of a copy or move
+func (s *subFS) fullPair(op, src, dst string) (string, string, error) {
+	fullSrc, err := s.full(op, src)
+	if err != nil {
+		return "", "", err
+	}
+	fullDst, err := s.full(op, dst)
+	if err != nil {
+		return "", "", err
+	}
+	return fullSrc, fullDst, nil
+}
+
+// Copy copies src to dst within the prefix if the underlying filesystem
+// supports CanCopy.
+func (s *subFS) Copy(ctx context.Context, src, dst string) error {
+	copier, ok := s.fs.(CanCopy)
+	if !ok {
+		return NewPathError("copy", src, ErrCodeNotSupported, "underlying filesystem does not support copy")
+	}
+	fullSrc, fullDst, err := s.fullPair("copy", src, dst)
+	if err != nil {
+		return err
+	}
+	return s.relErr(copier.Copy(ctx, fullSrc, fullDst))
+}
+
+// Move moves src to dst within the prefix if the underlying filesystem
+// supports CanMove.
+func (s *subFS) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
+	mover, ok := s.fs.(CanMove)
+	if !ok {
+		return NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
+	}
+	fullSrc, fullDst, err := s.fullPair("move", src, dst)
+	if err != nil {
+		return err
+	}
+	return s.relErr(mover.Move(ctx, fullSrc, fullDst, opts...))
+}
+
+// Checksum delegates to the underlying filesystem if supported.
+func (s *subFS) Checksum(ctx context.Context, path string, algorithm ChecksumAlgorithm) (string, error) {
+	checksummer, ok := s.fs.(CanChecksum)
+	if !ok {
+		return "", NewPathError("checksum", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
+	}
+	full, err := s.full("checksum", path)
+	if err != nil {
+		return "", err
+	}
+	sum, err := checksummer.Checksum(ctx, full, algorithm)
+	return sum, s.relErr(err)
+}
+
+// Checksums delegates to the underlying filesystem if supported.
+func (s *subFS) Checksums(ctx context.Context, path string, algorithms []ChecksumAlgorithm) (map[ChecksumAlgorithm]string, error) {
+	checksummer, ok := s.fs.(CanChecksum)
+	if !ok {
+		return nil, NewPathError("checksums", path, ErrCodeNotSupported, "underlying filesystem does not support checksums")
+	}
+	full, err := s.full("checksums", path)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := checksummer.Checksums(ctx, full, algorithms)
+	return sums, s.relErr(err)
+}
+
+// SignedURL delegates to the underlying filesystem if supported.
+func (s *subFS) SignedURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	signer, ok := s.fs.(CanSignURL)
+	if !ok {
+		return "", NewPathError("signed-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
+	}
+	full, err := s.full("signed-url", path)
+	if err != nil {
+		return "", err
+	}
+	url, err := signer.SignedURL(ctx, full, expires)
+	return url, s.relErr(err)
+}
+
+// SignedUploadURL delegates to the underlying filesystem if supported.
+func (s *subFS) SignedUploadURL(ctx context.Context, path string, expires time.Duration) (string, error) {
+	signer, ok := s.fs.(CanSignURL)
+	if !ok {
+		return "", NewPathError("signed-upload-url", path, ErrCodeNotSupported, "underlying filesystem does not support signed URLs")
+	}
+	full, err := s.full("signed-upload-url", path)
+	if err != nil {
+		return "", err
+	}
+	url, err := signer.SignedUploadURL(ctx, full, expires)
+	return url, s.relErr(err)
+}
+
+// ReadRange delegates to the underlying filesystem if supported.
+func (s *subFS) ReadRange(ctx context.Context, path string, offset, length int64) (io.ReadCloser, error) {
+	ranger, ok := s.fs.(CanReadRange)
+	if !ok {
+		return nil, NewPathError("read-range", path, ErrCodeNotSupported, "underlying filesystem does not support range reads")
+	}
+	full, err := s.full("read-range", path)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := ranger.ReadRange(ctx, full, offset, length)
+	return rc, s.relErr(err)
+}
+
+// SetTags delegates to the underlying filesystem if supported.
+func (s *subFS) SetTags(ctx context.Context, path string, tags map[string]string) error {
+	tagger, ok := s.fs.(CanTag)
+	if !ok {
+		return NewPathError("set-tags", path, ErrCodeNotSupported, "underlying filesystem does not support tags")
+	}
+	full, err := s.full("set-tags", path)
+	if err != nil {
+		return err
+	}
+	return s.relErr(tagger.SetTags(ctx, full, tags))
+}
+
+// GetTags delegates to the underlying filesystem if supported.
+func (s *subFS) GetTags(ctx context.Context, path string) (map[string]string, error) {
+	tagger, ok := s.fs.(CanTag)
+	if !ok {
+		return nil, NewPathError("get-tags", path, ErrCodeNotSupported, "underlying filesystem does not support tags")
+	}
+	full, err := s.full("get-tags", path)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := tagger.GetTags(ctx, full)
+	return tags, s.relErr(err)
+}
+
+// fullPaths resolves a batch of paths, returning the resolved paths, a map
+// back to the caller's paths, and errors for paths that escape the prefix
+func (s *subFS) fullPaths(op string, paths []string) ([]string, map[string]string, map[string]error) {
+	fulls := make([]string, 0, len(paths))
+	orig := make(map[string]string, len(paths))
+	errs := make(map[string]error)
+	for _, p := range paths {
+		full, err := s.full(op, p)
+		if err != nil {
+			errs[p] = err
+			continue
+		}
+		fulls = append(fulls, full)
+		orig[full] = p
+	}
+	return fulls, orig, errs
+}
+
+// StatMany delegates to the underlying filesystem, falling back to Stat per
+// path.
+func (s *subFS) StatMany(ctx context.Context, paths []string) (map[string]*FileInfo, map[string]error) {
+	fulls, orig, errs := s.fullPaths("stat", paths)
+	fullInfos, fullErrs := StatMany(ctx, s.fs, fulls)
+
+	infos := make(map[string]*FileInfo, len(fullInfos))
+	for full, info := range fullInfos {
+		infos[orig[full]] = s.relInfo(info)
+	}
+	for full, err := range fullErrs {
+		errs[orig[full]] = s.relErr(err)
+	}
+	if len(errs) == 0 {
+		errs = nil
+	}
+	return infos, errs
+}
+
+// DeleteMany delegates to the underlying filesystem, falling back to Delete
+// per path.
+func (s *subFS) DeleteMany(ctx context.Context, paths []string) ([]string, map[string]error) {
+	fulls, orig, errs := s.fullPaths("delete", paths)
+	fullDeleted, fullErrs := DeleteMany(ctx, s.fs, fulls)
+
+	deleted := make([]string, 0, len(fullDeleted))
+	for _, full := range fullDeleted {
+		deleted = append(deleted, orig[full])
+	}
+	for full, err := range fullErrs {
+		errs[orig[full]] = s.relErr(err)
+	}
+	if len(errs) == 0 {
+		errs = nil
+	}
+	return deleted, errs
+}
+
+// ListPage delegates to the underlying filesystem, falling back to paging
+// ListContents. Entry paths are relative to the prefix.
+func (s *subFS) ListPage(ctx context.Context, path string, opts ListPageOptions) (ListPage, error) {
+	full, err := s.full("listpage", path)
+	if err != nil {
+		return ListPage{}, err
+	}
+	page, err := ListContentsPage(ctx, s.fs, full, opts)
+	if err != nil {
+		return ListPage{}, s.relErr(err)
+	}
+	page.Entries = s.relInfos(page.Entries)
+	return page, nil
+}
+
+// ============================================================================
+// Interface Assertions
+// ============================================================================
+
+var (
+	_ FileSystem    = (*subFS)(nil)
+	_ CanCopy       = (*subFS)(nil)
+	_ CanMove       = (*subFS)(nil)
+	_ CanChecksum   = (*subFS)(nil)
+	_ CanSignURL    = (*subFS)(nil)
+	_ CanReadRange  = (*subFS)(nil)
+	_ CanTag        = (*subFS)(nil)
+	_ CanStatMany   = (*subFS)(nil)
+	_ CanDeleteMany = (*subFS)(nil)
+	_ CanListPage   = (*subFS)(nil)
+)
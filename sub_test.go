@@ -0,0 +1,112 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestSub(t *testing.T) {
+	localFS, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("local.New: %v", err)
+	}
+
+	for name, base := range map[string]filekit.FileSystem{
+		"memory": memory.New(),
+		"local":  localFS,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			seedTree(t, base, map[string]string{"tenants/b/secret.txt": "b's data"})
+			tenant := filekit.Sub(base, "tenants/a")
+
+			if _, err := tenant.Write(ctx, "x.txt", strings.NewReader("x")); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if _, err := tenant.Write(ctx, "docs/y.txt", strings.NewReader("y")); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if data, err := base.ReadAll(ctx, "tenants/a/x.txt"); err != nil || string(data) != "x" {
+				t.Errorf("underlying tenants/a/x.txt = %q, %v; want the tenant's write", data, err)
+			}
+
+			info, err := tenant.Stat(ctx, "docs/y.txt")
+			if err != nil || info.Path != "docs/y.txt" {
+				t.Errorf("Stat = %+v, %v; want tenant-relative path docs/y.txt", info, err)
+			}
+
+			entries, err := tenant.ListContents(ctx, "", true)
+			if err != nil {
+				t.Fatalf("ListContents: %v", err)
+			}
+			var paths []string
+			for _, e := range entries {
+				if !e.IsDir {
+					paths = append(paths, e.Path)
+				}
+			}
+			slices.Sort(paths)
+			if !slices.Equal(paths, []string{"docs/y.txt", "x.txt"}) {
+				t.Errorf("ListContents = %v, want tenant-relative paths", paths)
+			}
+
+			// Optional interfaces rewrite both arguments
+			if err := tenant.(filekit.CanCopy).Copy(ctx, "x.txt", "copy.txt"); err != nil {
+				t.Fatalf("Copy: %v", err)
+			}
+			if err := tenant.(filekit.CanMove).Move(ctx, "copy.txt", "docs/moved.txt"); err != nil {
+				t.Fatalf("Move: %v", err)
+			}
+			if exists, _ := base.FileExists(ctx, "tenants/a/docs/moved.txt"); !exists {
+				t.Error("Move did not land under the prefix")
+			}
+			if _, err := tenant.(filekit.CanChecksum).Checksum(ctx, "docs/moved.txt", filekit.ChecksumSHA256); err != nil {
+				t.Errorf("Checksum: %v", err)
+			}
+
+			// Errors report tenant-relative paths
+			_, err = tenant.Stat(ctx, "missing.txt")
+			var fe *filekit.FileError
+			if !errors.As(err, &fe) || !filekit.IsNotFound(err) || strings.Contains(fe.Path, "tenants") {
+				t.Errorf("Stat(missing.txt) = %v, want not found without the prefix", err)
+			}
+		})
+	}
+}
+
+func TestSub_RejectsEscapes(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	seedTree(t, base, map[string]string{"tenants/b/secret.txt": "b's data", "root.txt": "root"})
+	tenant := filekit.Sub(base, "tenants/a")
+
+	for _, p := range []string{"../b/secret.txt", "../../root.txt", "docs/../../b/secret.txt", "/../b/secret.txt"} {
+		if _, err := tenant.ReadAll(ctx, p); !filekit.IsCode(err, filekit.ErrCodePermission) || !errors.Is(err, filekit.ErrNotAllowed) {
+			t.Errorf("ReadAll(%q) = %v, want ErrCodePermission", p, err)
+		}
+	}
+	if err := tenant.(filekit.CanCopy).Copy(ctx, "x.txt", "../b/x.txt"); !filekit.IsCode(err, filekit.ErrCodePermission) {
+		t.Errorf("Copy to an escaping destination = %v, want ErrCodePermission", err)
+	}
+	if _, err := tenant.Write(ctx, "../b/secret.txt", strings.NewReader("overwritten"), filekit.WithOverwrite(true)); err == nil {
+		t.Error("Write escaped the prefix")
+	}
+	if data, _ := base.ReadAll(ctx, "tenants/b/secret.txt"); string(data) != "b's data" {
+		t.Errorf("other tenant's file = %q, want it untouched", data)
+	}
+
+	// ".." that stays inside the prefix is fine
+	if _, err := tenant.Write(ctx, "docs/../inside.txt", strings.NewReader("ok")); err != nil {
+		t.Errorf("Write(docs/../inside.txt) = %v", err)
+	}
+	if exists, _ := base.FileExists(ctx, "tenants/a/inside.txt"); !exists {
+		t.Error("docs/../inside.txt did not resolve to tenants/a/inside.txt")
+	}
+}
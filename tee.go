@@ -0,0 +1,310 @@
+package filekit
+
+import (
+	"context"
+	"io"
+	"path"
+	"slices"
+)
+
+// ============================================================================
+// TeeFileSystem Decorator
+// ============================================================================
+
+// TeeFileSystem wraps a primary FileSystem and mirrors every modification to
+// one or more secondary filesystems, e.g. for live migration or hot backup.
+//
+// Write, Delete, CreateDir, DeleteDir, Copy and Move are applied to the
+// primary first; only when that succeeds are they applied to each secondary
+// in order. Reads always come from the primary.
+//
+// By default secondaries are best-effort: their failures are reported to the
+// OnSecondaryError callback and the primary result is returned. With
+// WithStrictTee, secondary failures are returned as the operation's error
+// (the primary has already been modified at that point).
+//
+// Example:
+//
+//	tee := filekit.NewTeeFileSystem(newFS, []filekit.FileSystem{oldFS},
+//	    filekit.WithTeeErrorHandler(func(op, path string, index int, err error) {
+//	        log.Printf("mirror %d: %s %s: %v", index, op, path, err)
+//	    }),
+//	)
+type TeeFileSystem struct {
+	primary     FileSystem
+	secondaries []FileSystem
+	opts        TeeOptions
+}
+
+// TeeOptions configures the TeeFileSystem behavior.
+type TeeOptions struct {
+	// Strict makes a failure on any secondary fail the operation.
+	// Default: false (best-effort)
+	Strict bool
+
+	// OnSecondaryError is called for every failed secondary operation, in
+	// both best-effort and strict mode. index is the position of the
+	// secondary as passed to NewTeeFileSystem.
+	OnSecondaryError func(op, path string, index int, err error)
+}
+
+// TeeOption is a functional option for configuring TeeFileSystem.
+type TeeOption func(*TeeOptions)
+
+// WithStrictTee makes the TeeFileSystem return an error when any secondary
+// fails, instead of only reporting it to OnSecondaryError.
+func WithStrictTee() TeeOption {
+	return func(o *TeeOptions) {
+		o.Strict = true
+	}
+}
+
+// WithTeeErrorHandler sets the callback for failed secondary operations.
+func WithTeeErrorHandler(handler func(op, path string, index int, err error)) TeeOption {
+	return func(o *TeeOptions) {
+		o.OnSecondaryError = handler
+	}
+}
+
+// NewTeeFileSystem creates a wrapper that mirrors modifications of primary to
+// secondaries.
+func NewTeeFileSystem(primary FileSystem, secondaries []FileSystem, opts ...TeeOption) *TeeFileSystem {
+	options := TeeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &TeeFileSystem{
+		primary:     primary,
+		secondaries: slices.Clone(secondaries),
+		opts:        options,
+	}
+}
+
+// Unwrap returns the primary FileSystem.
+func (t *TeeFileSystem) Unwrap() FileSystem {
+	return t.primary
+}
+
+// Secondaries returns the secondary filesystems.
+func (t *TeeFileSystem) Secondaries() []FileSystem {
+	return slices.Clone(t.secondaries)
+}
+
+// mirror applies fn to every secondary, reporting failures. It returns the
+// failures only in strict mode.
+func (t *TeeFileSystem) mirror(op, path string, fn func(fs FileSystem) error) error {
+	errs := NewMultiError(op)
+	errs.Path = path
+	for i, fs := range t.secondaries {
+		err := fn(fs)
+		errs.Add(err)
+		if err != nil && t.opts.OnSecondaryError != nil {
+			t.opts.OnSecondaryError(op, path, i, err)
+		}
+	}
+	if !t.opts.Strict {
+		return nil
+	}
+	return errs.Err()
+}
+
+// copyFromPrimary writes the primary's file at path to fs
+func (t *TeeFileSystem) copyFromPrimary(ctx context.Context, fs FileSystem, path string, options ...Option) error {
+	rc, err := t.primary.Read(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	_, err = fs.Write(ctx, path, rc, mirrorOptions(options)...)
+	return err
+}
+
+// mirrorOptions returns the write options for a secondary. Secondaries follow
+// the primary, so conditions already checked against the primary are dropped
+// and existing files are overwritten. A progress callback covers the primary
+// write only.
+func mirrorOptions(options []Option) []Option {
+	return append(slices.Clone(options), WithOverwrite(true), WithIfMatch(""), WithIfNoneMatch(""), WithWriteProgress(nil))
+}
+
+// ============================================================================
+// FileSystem Interface - Read Operations (Primary)
+// ============================================================================
+
+// Read reads from the primary filesystem.
+func (t *TeeFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	return t.primary.Read(ctx, path)
+}
+
+// ReadAll reads from the primary filesystem.
+func (t *TeeFileSystem) ReadAll(ctx context.Context, path string) ([]byte, error) {
+	return t.primary.ReadAll(ctx, path)
+}
+
+// FileExists checks the primary filesystem.
+func (t *TeeFileSystem) FileExists(ctx context.Context, path string) (bool, error) {
+	return t.primary.FileExists(ctx, path)
+}
+
+// DirExists checks the primary filesystem.
+func (t *TeeFileSystem) DirExists(ctx context.Context, path string) (bool, error) {
+	return t.primary.DirExists(ctx, path)
+}
+
+// Stat returns file information from the primary filesystem.
+func (t *TeeFileSystem) Stat(ctx context.Context, path string) (*FileInfo, error) {
+	return t.primary.Stat(ctx, path)
+}
+
+// ListContents lists the primary filesystem.
+func (t *TeeFileSystem) ListContents(ctx context.Context, path string, recursive bool) ([]FileInfo, error) {
+	return t.primary.ListContents(ctx, path, recursive)
+}
+
+// ============================================================================
+// FileSystem Interface - Write Operations (Mirrored)
+// ============================================================================
+
+// Write writes to the primary, then to each secondary. Seekable content is
+// rewound for each secondary; other content is read back from the primary.
+// The returned WriteResult is the primary's.
+func (t *TeeFileSystem) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
+	seeker, _ := content.(io.ReadSeeker)
+	start := int64(-1)
+	if seeker != nil {
+		if pos, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			start = pos
+		}
+	}
+
+	result, err := t.primary.Write(ctx, path, content, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.mirror("write", path, func(fs FileSystem) error {
+		if start >= 0 {
+			if _, err := seeker.Seek(start, io.SeekStart); err == nil {
+				_, err := fs.Write(ctx, path, seeker, mirrorOptions(options)...)
+				return err
+			}
+		}
+		return t.copyFromPrimary(ctx, fs, path, options...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete deletes from the primary, then from each secondary. Files already
+// missing from a secondary are not an error.
+func (t *TeeFileSystem) Delete(ctx context.Context, path string) error {
+	if err := t.primary.Delete(ctx, path); err != nil {
+		return err
+	}
+	return t.mirror("delete", path, func(fs FileSystem) error {
+		if err := fs.Delete(ctx, path); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// CreateDir creates the directory on the primary, then on each secondary.
+// Directories that already exist on a secondary are not an error.
+func (t *TeeFileSystem) CreateDir(ctx context.Context, path string) error {
+	if err := t.primary.CreateDir(ctx, path); err != nil {
+		return err
+	}
+	return t.mirror("createdir", path, func(fs FileSystem) error {
+		if err := fs.CreateDir(ctx, path); err != nil && !IsCode(err, ErrCodeAlreadyExists) {
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteDir deletes the directory from the primary, then from each secondary.
+// Directories already missing from a secondary are not an error.
+func (t *TeeFileSystem) DeleteDir(ctx context.Context, path string) error {
+	if err := t.primary.DeleteDir(ctx, path); err != nil {
+		return err
+	}
+	return t.mirror("deletedir", path, func(fs FileSystem) error {
+		if err := fs.DeleteDir(ctx, path); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// ============================================================================
+// Optional Interface Delegation
+// ============================================================================
+
+// Copy copies on the primary if it supports CanCopy, then on each secondary.
+// A secondary copies natively when it implements CanCopy and has the source;
+// otherwise the copied file is transferred from the primary.
+func (t *TeeFileSystem) Copy(ctx context.Context, src, dst string) error {
+	copier, ok := t.primary.(CanCopy)
+	if !ok {
+		return NewPathError("copy", src, ErrCodeNotSupported, "underlying filesystem does not support copy")
+	}
+	if err := copier.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	return t.mirror("copy", dst, func(fs FileSystem) error {
+		if copier, ok := fs.(CanCopy); ok {
+			if err := copier.Copy(ctx, src, dst); !IsNotFound(err) {
+				return err
+			}
+		}
+		return t.copyFromPrimary(ctx, fs, dst)
+	})
+}
+
+// Move moves on the primary if it supports CanMove, then on each secondary.
+// A secondary moves natively when it implements CanMove and has the source;
+// otherwise the moved file is transferred from the primary and the source is
+// deleted from the secondary. opts apply to the primary; secondaries follow
+// whatever the primary did.
+func (t *TeeFileSystem) Move(ctx context.Context, src, dst string, opts ...MoveOption) error {
+	mover, ok := t.primary.(CanMove)
+	if !ok {
+		return NewPathError("move", src, ErrCodeNotSupported, "underlying filesystem does not support move")
+	}
+	if err := mover.Move(ctx, src, dst, opts...); err != nil {
+		return err
+	}
+	// Nothing moved, and the copy+delete fallback would delete the file
+	if path.Clean("/"+src) == path.Clean("/"+dst) {
+		return nil
+	}
+	return t.mirror("move", dst, func(fs FileSystem) error {
+		if mover, ok := fs.(CanMove); ok {
+			if err := mover.Move(ctx, src, dst); !IsNotFound(err) {
+				return err
+			}
+		}
+		if err := t.copyFromPrimary(ctx, fs, dst); err != nil {
+			return err
+		}
+		if err := fs.Delete(ctx, src); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// ============================================================================
+// Interface Assertions
+// ============================================================================
+
+var (
+	_ FileSystem = (*TeeFileSystem)(nil)
+	_ CanCopy    = (*TeeFileSystem)(nil)
+	_ CanMove    = (*TeeFileSystem)(nil)
+)
@@ -0,0 +1,165 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestTeeFileSystem_MirrorsWrites(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+	tee := filekit.NewTeeFileSystem(primary, []filekit.FileSystem{secondary})
+
+	// Seekable content is rewound, other readers are read back from the primary
+	contents := map[string]io.Reader{
+		"docs/seekable.txt": strings.NewReader("seekable"),
+		"docs/streamed.txt": io.MultiReader(strings.NewReader("stream"), strings.NewReader("ed")),
+	}
+	for path, content := range contents {
+		if _, err := tee.Write(ctx, path, content); err != nil {
+			t.Fatalf("Write(%s): %v", path, err)
+		}
+	}
+	for path, want := range map[string]string{"docs/seekable.txt": "seekable", "docs/streamed.txt": "streamed"} {
+		for name, fs := range map[string]filekit.FileSystem{"primary": primary, "secondary": secondary} {
+			data, err := fs.ReadAll(ctx, path)
+			if err != nil || string(data) != want {
+				t.Errorf("%s %s = %q, %v; want %q", name, path, data, err, want)
+			}
+		}
+	}
+
+	if err := tee.Copy(ctx, "docs/seekable.txt", "docs/copy.txt"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if err := tee.Move(ctx, "docs/streamed.txt", "archive/streamed.txt"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if err := tee.Delete(ctx, "docs/seekable.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, fs := range []filekit.FileSystem{primary, secondary} {
+		for path, want := range map[string]bool{
+			"docs/seekable.txt":    false,
+			"docs/streamed.txt":    false,
+			"docs/copy.txt":        true,
+			"archive/streamed.txt": true,
+		} {
+			if exists, _ := fs.FileExists(ctx, path); exists != want {
+				t.Errorf("FileExists(%s) = %v, want %v", path, exists, want)
+			}
+		}
+	}
+}
+
+func TestTeeFileSystem_ProgressCoversPrimaryOnly(t *testing.T) {
+	ctx := context.Background()
+	tee := filekit.NewTeeFileSystem(memory.New(), []filekit.FileSystem{memory.New(), memory.New()})
+
+	content := strings.Repeat("x", 3*filekit.ProgressInterval)
+	var reported []int64
+	_, err := tee.Write(ctx, "big.bin", strings.NewReader(content), filekit.WithWriteProgress(func(transferred, total int64) {
+		reported = append(reported, transferred)
+	}))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(reported) == 0 || reported[len(reported)-1] != int64(len(content)) {
+		t.Fatalf("progress = %v, want it to end at %d", reported, len(content))
+	}
+	for i := 1; i < len(reported); i++ {
+		if reported[i] < reported[i-1] {
+			t.Fatalf("progress restarted for a secondary: %v", reported)
+		}
+	}
+}
+
+func TestTeeFileSystem_CopyFallsBackToPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := memory.New(), memory.New()
+
+	// The file predates mirroring, so the secondary cannot copy it natively
+	if _, err := primary.Write(ctx, "old.txt", strings.NewReader("old")); err != nil {
+		t.Fatal(err)
+	}
+	tee := filekit.NewTeeFileSystem(primary, []filekit.FileSystem{secondary}, filekit.WithStrictTee())
+
+	if err := tee.Copy(ctx, "old.txt", "new.txt"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if data, err := secondary.ReadAll(ctx, "new.txt"); err != nil || string(data) != "old" {
+		t.Errorf("secondary new.txt = %q, %v; want the primary's content", data, err)
+	}
+	if err := tee.Delete(ctx, "old.txt"); err != nil {
+		t.Errorf("Delete of a file missing from the secondary = %v, want nil", err)
+	}
+}
+
+func TestTeeFileSystem_SecondaryFailure(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("best effort", func(t *testing.T) {
+		primary := memory.New()
+		failing := filekit.NewReadOnlyFileSystem(memory.New())
+
+		var reported []string
+		tee := filekit.NewTeeFileSystem(primary, []filekit.FileSystem{failing},
+			filekit.WithTeeErrorHandler(func(op, path string, index int, err error) {
+				if index != 0 || !errors.Is(err, filekit.ErrReadOnly) {
+					t.Errorf("handler got index %d, err %v", index, err)
+				}
+				reported = append(reported, op+" "+path)
+			}),
+		)
+
+		result, err := tee.Write(ctx, "a.txt", strings.NewReader("a"))
+		if err != nil || result == nil {
+			t.Fatalf("Write = %v, %v; want the primary result", result, err)
+		}
+		if exists, _ := primary.FileExists(ctx, "a.txt"); !exists {
+			t.Error("primary write missing")
+		}
+		if len(reported) != 1 || reported[0] != "write a.txt" {
+			t.Errorf("reported = %v, want [write a.txt]", reported)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		primary, healthy := memory.New(), memory.New()
+		failing := filekit.NewReadOnlyFileSystem(memory.New())
+		tee := filekit.NewTeeFileSystem(primary, []filekit.FileSystem{healthy, failing}, filekit.WithStrictTee())
+
+		_, err := tee.Write(ctx, "a.txt", strings.NewReader("a"))
+		if !errors.Is(err, filekit.ErrReadOnly) {
+			t.Fatalf("Write = %v, want the secondary's ErrReadOnly", err)
+		}
+		// The primary and healthy secondaries are still written
+		for _, fs := range []filekit.FileSystem{primary, healthy} {
+			if exists, _ := fs.FileExists(ctx, "a.txt"); !exists {
+				t.Error("write missing from a healthy backend")
+			}
+		}
+
+		if err := tee.CreateDir(ctx, "dir"); !errors.Is(err, filekit.ErrReadOnly) {
+			t.Errorf("CreateDir = %v, want ErrReadOnly", err)
+		}
+	})
+
+	t.Run("primary failure skips secondaries", func(t *testing.T) {
+		secondary := memory.New()
+		tee := filekit.NewTeeFileSystem(filekit.NewReadOnlyFileSystem(memory.New()), []filekit.FileSystem{secondary})
+
+		if _, err := tee.Write(ctx, "a.txt", strings.NewReader("a")); !errors.Is(err, filekit.ErrReadOnly) {
+			t.Fatalf("Write = %v, want the primary's ErrReadOnly", err)
+		}
+		if exists, _ := secondary.FileExists(ctx, "a.txt"); exists {
+			t.Error("secondary written although the primary failed")
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package filekit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// WriteString writes content to path. It goes through fs.Write, so it works
+// with any backend and decorator.
+//
+// Example:
+//
+//	err := filekit.WriteString(ctx, fs, "notes/todo.txt", "buy milk", filekit.WithOverwrite(true))
+func WriteString(ctx context.Context, fs FileWriter, path, content string, opts ...Option) error {
+	_, err := fs.Write(ctx, path, strings.NewReader(content), opts...)
+	return err
+}
+
+// WriteBytes writes data to path and returns a WriteResult that is the same
+// shape on every backend: BytesWritten is len(data) and Checksum is the hex
+// SHA-256 of data, replacing whatever the backend reported (some report
+// nothing, others MD5 or CRC32C). ETag, Version, ContentType and the other
+// fields are passed through from fs.Write.
+//
+// Example:
+//
+//	res, err := filekit.WriteBytes(ctx, fs, "thumbs/a.png", png, filekit.WithContentType("image/png"))
+func WriteBytes(ctx context.Context, fs FileWriter, path string, data []byte, opts ...Option) (*WriteResult, error) {
+	result, err := fs.Write(ctx, path, bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &WriteResult{}
+	}
+	sum := sha256.Sum256(data)
+	result.BytesWritten = int64(len(data))
+	result.Checksum = hex.EncodeToString(sum[:])
+	result.ChecksumAlgorithm = ChecksumSHA256
+	return result, nil
+}
+
+// ReadString reads the whole file at path as a string.
+func ReadString(ctx context.Context, fs FileReader, path string) (string, error) {
+	data, err := fs.ReadAll(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteJSON marshals v with encoding/json and writes it to path. The content
+// type defaults to application/json; opts can override it.
+//
+// Example:
+//
+//	err := filekit.WriteJSON(ctx, fs, "config.json", cfg, filekit.WithOverwrite(true))
+func WriteJSON(ctx context.Context, fs FileWriter, path string, v any, opts ...Option) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return WrapPath(err, "write", path, ErrCodeInvalidInput, "failed to marshal JSON")
+	}
+	opts = append([]Option{WithContentType("application/json")}, opts...)
+	_, err = fs.Write(ctx, path, bytes.NewReader(data), opts...)
+	return err
+}
+
+// ReadJSON reads the file at path and unmarshals it into v with
+// encoding/json.
+//
+// Example:
+//
+//	var cfg Config
+//	err := filekit.ReadJSON(ctx, fs, "config.json", &cfg)
+func ReadJSON(ctx context.Context, fs FileReader, path string, v any) error {
+	data, err := fs.ReadAll(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return WrapPath(err, "read", path, ErrCodeInvalidInput, "failed to unmarshal JSON")
+	}
+	return nil
+}
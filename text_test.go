@@ -0,0 +1,131 @@
+package filekit_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestWriteReadString(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+
+	if err := filekit.WriteString(ctx, fs, "notes/todo.txt", "buy milk"); err != nil {
+		t.Fatalf("WriteString: %v", err)
+	}
+	got, err := filekit.ReadString(ctx, fs, "notes/todo.txt")
+	if err != nil || got != "buy milk" {
+		t.Errorf("ReadString = %q, %v; want %q", got, err, "buy milk")
+	}
+
+	// Options are passed through to Write
+	if err := filekit.WriteString(ctx, fs, "notes/todo.txt", "again"); !filekit.IsCode(err, filekit.ErrCodeAlreadyExists) {
+		t.Errorf("WriteString without overwrite = %v, want ErrCodeAlreadyExists", err)
+	}
+	if err := filekit.WriteString(ctx, fs, "notes/todo.txt", "again", filekit.WithOverwrite(true)); err != nil {
+		t.Errorf("WriteString with overwrite: %v", err)
+	}
+
+	if _, err := filekit.ReadString(ctx, fs, "missing.txt"); !filekit.IsNotFound(err) {
+		t.Errorf("ReadString(missing.txt) = %v, want not found", err)
+	}
+}
+
+func TestWriteReadJSON(t *testing.T) {
+	type config struct {
+		Name    string            `json:"name"`
+		Retries int               `json:"retries"`
+		Labels  map[string]string `json:"labels"`
+	}
+
+	ctx := context.Background()
+	fs := memory.New()
+	want := config{Name: "uploads", Retries: 3, Labels: map[string]string{"env": "prod"}}
+
+	if err := filekit.WriteJSON(ctx, fs, "config.json", want); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	info, err := fs.Stat(ctx, "config.json")
+	if err != nil || info.ContentType != "application/json" {
+		t.Errorf("Stat = %+v, %v; want content type application/json", info, err)
+	}
+
+	var got config
+	if err := filekit.ReadJSON(ctx, fs, "config.json", &got); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadJSON = %+v, want %+v", got, want)
+	}
+
+	if err := filekit.WriteString(ctx, fs, "broken.json", "{not json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := filekit.ReadJSON(ctx, fs, "broken.json", &got); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("ReadJSON(broken.json) = %v, want ErrCodeInvalidInput", err)
+	}
+	if err := filekit.WriteJSON(ctx, fs, "func.json", func() {}); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("WriteJSON(func) = %v, want ErrCodeInvalidInput", err)
+	}
+}
+
+// resultFS replaces the WriteResult of the wrapped filesystem, the way a
+// backend that reports less (or a different checksum) would.
+type resultFS struct {
+	filekit.FileSystem
+	result *filekit.WriteResult
+}
+
+func (fs resultFS) Write(ctx context.Context, path string, r io.Reader, opts ...filekit.Option) (*filekit.WriteResult, error) {
+	if _, err := fs.FileSystem.Write(ctx, path, r, opts...); err != nil {
+		return nil, err
+	}
+	return fs.result, nil
+}
+
+func TestWriteBytes(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("thumbnail bytes")
+	sum := sha256.Sum256(data)
+	wantChecksum := hex.EncodeToString(sum[:])
+
+	backends := map[string]filekit.FileSystem{
+		"memory":    memory.New(),
+		"nil":       resultFS{FileSystem: memory.New()},
+		"partial":   resultFS{FileSystem: memory.New(), result: &filekit.WriteResult{BytesWritten: 3, ETag: "etag-1"}},
+		"other sum": resultFS{FileSystem: memory.New(), result: &filekit.WriteResult{Checksum: "abc", ChecksumAlgorithm: filekit.ChecksumMD5}},
+	}
+	for name, fs := range backends {
+		t.Run(name, func(t *testing.T) {
+			res, err := filekit.WriteBytes(ctx, fs, "a/thumb.png", data)
+			if err != nil {
+				t.Fatalf("WriteBytes: %v", err)
+			}
+			if res.BytesWritten != int64(len(data)) {
+				t.Errorf("BytesWritten = %d, want %d", res.BytesWritten, len(data))
+			}
+			if res.Checksum != wantChecksum || res.ChecksumAlgorithm != filekit.ChecksumSHA256 {
+				t.Errorf("Checksum = %s %q, want sha256 %q", res.ChecksumAlgorithm, res.Checksum, wantChecksum)
+			}
+			if name == "partial" && res.ETag != "etag-1" {
+				t.Errorf("ETag = %q, want the backend's etag-1", res.ETag)
+			}
+			got, err := fs.ReadAll(ctx, "a/thumb.png")
+			if err != nil || string(got) != string(data) {
+				t.Errorf("ReadAll = %q, %v; want %q", got, err, data)
+			}
+
+			// Errors are returned as is, with no result
+			res, err = filekit.WriteBytes(ctx, fs, "a/thumb.png", data)
+			if res != nil || !filekit.IsCode(err, filekit.ErrCodeAlreadyExists) {
+				t.Errorf("WriteBytes without overwrite = %v, %v; want nil, ErrCodeAlreadyExists", res, err)
+			}
+		})
+	}
+}
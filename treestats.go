@@ -0,0 +1,106 @@
+package filekit
+
+import (
+	"context"
+
+	"github.com/gobeaver/filekit/filevalidator"
+)
+
+// ============================================================================
+// TreeStats - Aggregate size and type breakdown for a tree
+// ============================================================================
+
+// ByTypeStat is the file count and size for one content-type category.
+type ByTypeStat struct {
+	// Files is the number of files in the category.
+	Files int64
+
+	// Bytes is the total size of the files in the category.
+	Bytes int64
+}
+
+// TreeStatsResult summarizes the files under a path.
+type TreeStatsResult struct {
+	// Files is the number of files; directories are not counted.
+	Files int64
+
+	// Bytes is the total size of all files.
+	Bytes int64
+
+	// Largest is the largest file, or nil if the tree has no files.
+	Largest *FileInfo
+
+	// ByType breaks Files and Bytes down by content-type category as returned
+	// by filevalidator.GetMIMECategory ("image", "video", "document", ...).
+	ByType map[string]ByTypeStat
+}
+
+// TreeStats walks everything under path and reports file counts and sizes,
+// in total and per content-type category. Entries without a content type are
+// categorized by extension.
+//
+// When fs implements [CanListPage] the tree is listed a page at a time, so
+// memory use does not grow with the size of the tree; otherwise a single
+// recursive ListContents is used.
+//
+// Example:
+//
+//	stats, err := filekit.TreeStats(ctx, fs, "tenants/acme")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("%d files, %d bytes, %d bytes of video\n",
+//	    stats.Files, stats.Bytes, stats.ByType["video"].Bytes)
+func TreeStats(ctx context.Context, fs FileSystem, path string) (TreeStatsResult, error) {
+	result := TreeStatsResult{ByType: make(map[string]ByTypeStat)}
+	add := func(entries []FileInfo) {
+		for i := range entries {
+			entry := &entries[i]
+			if entry.IsDir {
+				continue
+			}
+			result.Files++
+			result.Bytes += entry.Size
+			if result.Largest == nil || entry.Size > result.Largest.Size {
+				largest := *entry
+				result.Largest = &largest
+			}
+
+			contentType := entry.ContentType
+			if contentType == "" {
+				contentType = GuessContentType(entry.Path, nil)
+			}
+			category := filevalidator.GetMIMECategory(contentType)
+			stat := result.ByType[category]
+			stat.Files++
+			stat.Bytes += entry.Size
+			result.ByType[category] = stat
+		}
+	}
+
+	lister, ok := fs.(CanListPage)
+	if !ok {
+		entries, err := fs.ListContents(ctx, path, true)
+		if err != nil {
+			return TreeStatsResult{}, err
+		}
+		add(entries)
+		return result, nil
+	}
+
+	opts := ListPageOptions{Recursive: true}
+	for {
+		if err := ctx.Err(); err != nil {
+			return TreeStatsResult{}, FromContext(ctx, "treestats", path)
+		}
+		page, err := lister.ListPage(ctx, path, opts)
+		if err != nil {
+			return TreeStatsResult{}, err
+		}
+		add(page.Entries)
+		if page.NextToken == "" {
+			return result, nil
+		}
+		opts.ContinuationToken = page.NextToken
+	}
+}
@@ -0,0 +1,76 @@
+package filekit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestTreeStats(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{
+		"tenant/photos/a.jpg":     strings.Repeat("j", 300),
+		"tenant/photos/b.png":     strings.Repeat("p", 200),
+		"tenant/videos/intro.mp4": strings.Repeat("v", 1000),
+		"tenant/docs/report.pdf":  strings.Repeat("d", 50),
+		"tenant/docs/notes.txt":   "hello",
+		"tenant/empty/.keep":      "",
+		"other/ignored.jpg":       strings.Repeat("x", 5000),
+	})
+	if _, err := fs.Write(ctx, "tenant/blob", strings.NewReader("raw"), filekit.WithContentType("application/octet-stream")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(t *testing.T, fs filekit.FileSystem) {
+		t.Helper()
+		stats, err := filekit.TreeStats(ctx, fs, "tenant")
+		if err != nil {
+			t.Fatalf("TreeStats: %v", err)
+		}
+		if stats.Files != 7 || stats.Bytes != 1558 {
+			t.Errorf("totals = %d files, %d bytes; want 7 files, 1558 bytes", stats.Files, stats.Bytes)
+		}
+		if stats.Largest == nil || stats.Largest.Path != "tenant/videos/intro.mp4" {
+			t.Errorf("Largest = %+v, want tenant/videos/intro.mp4", stats.Largest)
+		}
+		want := map[string]filekit.ByTypeStat{
+			"image":    {Files: 2, Bytes: 500},
+			"video":    {Files: 1, Bytes: 1000},
+			"document": {Files: 1, Bytes: 50},
+			"text":     {Files: 1, Bytes: 5},
+			"other":    {Files: 2, Bytes: 3},
+		}
+		if len(stats.ByType) != len(want) {
+			t.Errorf("ByType = %v, want %v", stats.ByType, want)
+		}
+		for category, w := range want {
+			if got := stats.ByType[category]; got != w {
+				t.Errorf("ByType[%q] = %+v, want %+v", category, got, w)
+			}
+		}
+	}
+
+	t.Run("paged", func(t *testing.T) { check(t, fs) })
+	// Embedding hides CanListPage, so TreeStats falls back to ListContents
+	t.Run("list contents", func(t *testing.T) { check(t, struct{ filekit.FileSystem }{fs}) })
+}
+
+func TestTreeStats_Empty(t *testing.T) {
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{"a/b.txt": "x"})
+	if err := fs.CreateDir(context.Background(), "empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := filekit.TreeStats(context.Background(), fs, "empty")
+	if err != nil {
+		t.Fatalf("TreeStats: %v", err)
+	}
+	if stats.Files != 0 || stats.Bytes != 0 || stats.Largest != nil || len(stats.ByType) != 0 {
+		t.Errorf("TreeStats on an empty directory = %+v, want zero values", stats)
+	}
+}
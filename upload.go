@@ -2,7 +2,21 @@ package filekit
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
 	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultPartSize is the part size used by chunked uploads when none is set
+	DefaultPartSize = 5 * 1024 * 1024
+
+	// DefaultUploadConcurrency is the number of parts UploadReader uploads in
+	// parallel when none is set
+	DefaultUploadConcurrency = 4
 )
 
 // ProgressFunc is a callback function for upload progress
@@ -14,9 +28,13 @@ type UploadOptions struct {
 	ContentType string
 
 	// ChunkSize defines the size of chunks for chunked upload
-	// If zero, a default chunk size will be used
+	// If zero, DefaultPartSize is used
 	ChunkSize int64
 
+	// Concurrency is the number of parts uploaded in parallel
+	// If zero, DefaultUploadConcurrency is used
+	Concurrency int
+
 	// Progress is a callback function for upload progress
 	Progress ProgressFunc
 
@@ -25,6 +43,43 @@ type UploadOptions struct {
 
 	// Visibility defines the file visibility (public or private)
 	Visibility Visibility
+
+	// VerifyChecksum hashes the data as it is read and completes the upload
+	// with CompleteUploadWithChecksum, so the assembled file is verified
+	// against what was read. The uploader must implement ChecksumUploader.
+	VerifyChecksum bool
+}
+
+// UploadOption is a functional option for configuring UploadReader
+type UploadOption func(*UploadOptions)
+
+// WithPartSize sets the size of each uploaded part. Backends with a minimum
+// part size (S3 requires 5MB for all but the last part) raise smaller values
+// to their minimum.
+func WithPartSize(size int64) UploadOption {
+	return func(o *UploadOptions) {
+		o.ChunkSize = size
+	}
+}
+
+// WithUploadConcurrency sets the number of parts uploaded in parallel. Each
+// in-flight part holds one part-sized buffer.
+func WithUploadConcurrency(n int) UploadOption {
+	return func(o *UploadOptions) {
+		o.Concurrency = n
+	}
+}
+
+// WithUploadPartChecksum verifies a chunked upload end to end. UploadReader
+// computes the SHA-256 of the data as it reads it and finishes with
+// CompleteUploadWithChecksum, which checks every part against the checksum
+// recorded when it was uploaded and the assembled file against the whole.
+// A corrupted part fails the upload instead of producing a corrupt file.
+// The uploader must implement [ChecksumUploader].
+func WithUploadPartChecksum() UploadOption {
+	return func(o *UploadOptions) {
+		o.VerifyChecksum = true
+	}
 }
 
 // ChunkedUploader is the interface for filesystems that support chunked uploads
@@ -42,6 +97,46 @@ type ChunkedUploader interface {
 	AbortUpload(ctx context.Context, uploadID string) error
 }
 
+// ChecksumUploader is implemented by chunked uploaders that record the
+// SHA-256 of each part in UploadPart and can verify an upload when it is
+// completed.
+type ChecksumUploader interface {
+	// CompleteUploadWithChecksum finalizes a chunked upload like
+	// CompleteUpload, checking each part against the SHA-256 recorded when it
+	// was uploaded and the assembled content against expected, a hex-encoded
+	// SHA-256 (an empty expected checks the parts only). A mismatch fails the
+	// upload with an ErrCodeIntegrity error wrapping a *ChecksumMismatchError
+	// and leaves no file at the target path.
+	CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error
+}
+
+// PartChecksum returns the hex-encoded SHA-256 of a chunked upload part.
+// Drivers record it in UploadPart and check it with VerifyUploadChecksum.
+func PartChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyUploadChecksum compares sum, a SHA-256 digest, with expected, a
+// hex-encoded SHA-256, for the given part of the upload to path, or for the
+// assembled file if part is 0. An empty expected always matches. On a
+// mismatch it returns an ErrCodeIntegrity error wrapping a
+// *ChecksumMismatchError.
+func VerifyUploadChecksum(path string, part int, expected string, sum []byte) error {
+	actual := hex.EncodeToString(sum)
+	if expected == "" || strings.EqualFold(actual, expected) {
+		return nil
+	}
+	mismatch := &ChecksumMismatchError{
+		Path:      path,
+		Part:      part,
+		Algorithm: ChecksumSHA256,
+		Expected:  expected,
+		Actual:    actual,
+	}
+	return WrapPath(mismatch, "complete-upload", path, ErrCodeIntegrity, mismatch.Error())
+}
+
 // Upload uploads a file to the filesystem with the given options
 func Upload(ctx context.Context, fs FileSystem, path string, r io.Reader, size int64, opts *UploadOptions) error {
 	if opts == nil {
@@ -88,76 +183,136 @@ func uploadChunked(ctx context.Context, fs ChunkedUploader, path string, r io.Re
 	if size <= 0 {
 		return ErrInvalidSize
 	}
+	return uploadParts(ctx, fs, path, r, opts)
+}
+
+// UploadReader uploads r to path as a chunked upload. The reader is split into
+// parts of the configured size (WithPartSize, default DefaultPartSize) and up
+// to WithUploadConcurrency parts are uploaded at once. The upload is aborted if
+// reading or any part fails; the first error is returned.
+//
+// Example:
+//
+//	err := filekit.UploadReader(ctx, s3FS, "backups/db.tar", r,
+//	    filekit.WithPartSize(16*1024*1024),
+//	    filekit.WithUploadConcurrency(8),
+//	)
+func UploadReader(ctx context.Context, u ChunkedUploader, path string, r io.Reader, opts ...UploadOption) error {
+	var options UploadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return uploadParts(ctx, u, path, r, &options)
+}
 
-	// Initiate upload
-	uploadID, err := fs.InitiateUpload(ctx, path)
+// uploadParts reads r one part at a time and uploads the parts concurrently
+func uploadParts(ctx context.Context, u ChunkedUploader, path string, r io.Reader, opts *UploadOptions) error {
+	partSize := opts.ChunkSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	if limited, ok := u.(interface{ MinPartSize() int64 }); ok {
+		partSize = max(partSize, limited.MinPartSize())
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultUploadConcurrency
+	}
+
+	var (
+		verifier ChecksumUploader
+		hasher   hash.Hash
+	)
+	if opts.VerifyChecksum {
+		var ok bool
+		if verifier, ok = u.(ChecksumUploader); !ok {
+			return NewPathError("upload", path, ErrCodeNotSupported, "uploader does not support checksum verification")
+		}
+		hasher = sha256.New()
+	}
+
+	uploadID, err := u.InitiateUpload(ctx, path)
 	if err != nil {
 		return err
 	}
 
-	// Make sure to abort upload on error
-	defer func() {
-		if err != nil {
-			_ = fs.AbortUpload(ctx, uploadID)
-		}
-	}()
-
-	// Determine chunk size
-	chunkSize := opts.ChunkSize
-	if chunkSize <= 0 {
-		chunkSize = 5 * 1024 * 1024 // Default to 5MB
-	}
-
-	// Upload parts
-	partNumber := 1
-	buffer := make([]byte, chunkSize)
-	for {
-		// Read chunk
-		n, err := io.ReadFull(r, buffer)
-		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-			return err
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
 		}
+	}
 
-		// If we read something, upload it
-		if n > 0 {
-			if err := fs.UploadPart(ctx, uploadID, partNumber, buffer[:n]); err != nil {
-				return err
-			}
-			partNumber++
+	// Buffers are handed back after each part, so at most concurrency parts
+	// are held in memory
+	buffers := make(chan []byte, concurrency)
+	for range concurrency {
+		buffers <- nil
+	}
+
+read:
+	for partNumber := 1; ; partNumber++ {
+		var buf []byte
+		select {
+		case buf = <-buffers:
+		case <-partCtx.Done():
+			break read
+		}
+		if buf == nil {
+			buf = make([]byte, partSize)
 		}
 
-		// If we reached EOF, break
-		if err == io.EOF || err == io.ErrUnexpectedEOF {
+		n, readErr := io.ReadFull(r, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			fail(WrapPathErr("upload", path, readErr))
 			break
 		}
-	}
+		// An empty reader still uploads one (empty) part
+		if n == 0 && partNumber > 1 {
+			break
+		}
+		if hasher != nil {
+			hasher.Write(buf[:n])
+		}
 
-	// Complete upload
-	return fs.CompleteUpload(ctx, uploadID)
-}
+		wg.Add(1)
+		go func(partNumber int, data []byte) {
+			defer wg.Done()
+			if err := u.UploadPart(partCtx, uploadID, partNumber, data); err != nil {
+				fail(err)
+			}
+			buffers <- buf
+		}(partNumber, buf[:n])
 
-// progressReader is a reader that reports progress
-type progressReader struct {
-	reader        io.Reader
-	progress      ProgressFunc
-	size          int64
-	bytesRead     int64
-	lastReported  int64
-	reportingStep int64
-}
+		if readErr != nil {
+			break
+		}
+	}
+	wg.Wait()
 
-func (r *progressReader) Read(p []byte) (int, error) {
-	n, err := r.reader.Read(p)
-	if n > 0 {
-		r.bytesRead += int64(n)
-
-		// Report progress if we've read enough since the last report
-		// or if we're at the end
-		if r.progress != nil &&
-			(r.bytesRead-r.lastReported >= r.reportingStep || err == io.EOF) {
-			r.progress(r.bytesRead, r.size)
-			r.lastReported = r.bytesRead
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = FromContext(ctx, "upload", path)
+	}
+	if firstErr == nil {
+		if verifier != nil {
+			firstErr = verifier.CompleteUploadWithChecksum(ctx, uploadID, hex.EncodeToString(hasher.Sum(nil)))
+		} else {
+			firstErr = u.CompleteUpload(ctx, uploadID)
 		}
 	}
-	return n, err
+	if firstErr != nil {
+		_ = u.AbortUpload(context.WithoutCancel(ctx), uploadID)
+		return firstErr
+	}
+	return nil
 }
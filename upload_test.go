@@ -0,0 +1,213 @@
+package filekit_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/local"
+)
+
+func TestUploadReader(t *testing.T) {
+	ctx := context.Background()
+	fs, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]byte, 10*1024*1024+123)
+	rand.New(rand.NewSource(1)).Read(want)
+	err = filekit.UploadReader(ctx, fs, "big.bin", io.MultiReader(bytes.NewReader(want)),
+		filekit.WithPartSize(512*1024), filekit.WithUploadConcurrency(4))
+	if err != nil {
+		t.Fatalf("UploadReader: %v", err)
+	}
+
+	rc, err := fs.Read(ctx, "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("reassembled file has %d bytes, want %d identical bytes", len(got), len(want))
+	}
+}
+
+// recordingUploader is a ChunkedUploader that keeps parts in memory and
+// tracks how many UploadPart calls run at once.
+type recordingUploader struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	failPart  int
+	inFlight  atomic.Int32
+	maxFlight atomic.Int32
+	completed bool
+	aborted   bool
+}
+
+func (u *recordingUploader) InitiateUpload(ctx context.Context, path string) (string, error) {
+	u.parts = map[int][]byte{}
+	return "upload-1", nil
+}
+
+func (u *recordingUploader) UploadPart(ctx context.Context, uploadID string, partNumber int, data []byte) error {
+	n := u.inFlight.Add(1)
+	defer u.inFlight.Add(-1)
+	for {
+		old := u.maxFlight.Load()
+		if n <= old || u.maxFlight.CompareAndSwap(old, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if partNumber == u.failPart {
+		return fmt.Errorf("part %d rejected", partNumber)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.parts[partNumber] = bytes.Clone(data)
+	return nil
+}
+
+func (u *recordingUploader) CompleteUpload(ctx context.Context, uploadID string) error {
+	u.completed = true
+	return nil
+}
+
+func (u *recordingUploader) AbortUpload(ctx context.Context, uploadID string) error {
+	u.aborted = true
+	return nil
+}
+
+func TestUploadReader_Concurrency(t *testing.T) {
+	u := &recordingUploader{}
+	data := bytes.Repeat([]byte("abcdefgh"), 1024) // 8 KiB
+	err := filekit.UploadReader(context.Background(), u, "f.bin", bytes.NewReader(data),
+		filekit.WithPartSize(1000), filekit.WithUploadConcurrency(3))
+	if err != nil {
+		t.Fatalf("UploadReader: %v", err)
+	}
+
+	if len(u.parts) != 9 || len(u.parts[9]) != 192 {
+		t.Errorf("uploaded %d parts (last %d bytes), want 9 parts with a 192-byte last part", len(u.parts), len(u.parts[9]))
+	}
+	var got []byte
+	for i := 1; i <= len(u.parts); i++ {
+		got = append(got, u.parts[i]...)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("parts do not reassemble to the input")
+	}
+	if m := u.maxFlight.Load(); m < 2 || m > 3 {
+		t.Errorf("max concurrent parts = %d, want 2..3", m)
+	}
+	if !u.completed || u.aborted {
+		t.Errorf("completed = %v, aborted = %v; want completed only", u.completed, u.aborted)
+	}
+}
+
+func TestUploadReader_AbortsOnError(t *testing.T) {
+	t.Run("part", func(t *testing.T) {
+		u := &recordingUploader{failPart: 3}
+		err := filekit.UploadReader(context.Background(), u, "f.bin", bytes.NewReader(make([]byte, 10_000)),
+			filekit.WithPartSize(1000))
+		if err == nil || err.Error() != "part 3 rejected" {
+			t.Errorf("UploadReader = %v, want the part error", err)
+		}
+		if u.completed || !u.aborted {
+			t.Errorf("completed = %v, aborted = %v; want aborted only", u.completed, u.aborted)
+		}
+	})
+
+	t.Run("read", func(t *testing.T) {
+		u := &recordingUploader{}
+		readErr := errors.New("disk on fire")
+		r := io.MultiReader(bytes.NewReader(make([]byte, 2500)), iotest.ErrReader(readErr))
+		err := filekit.UploadReader(context.Background(), u, "f.bin", r, filekit.WithPartSize(1000))
+		if !errors.Is(err, readErr) {
+			t.Errorf("UploadReader = %v, want the read error", err)
+		}
+		if u.completed || !u.aborted {
+			t.Errorf("completed = %v, aborted = %v; want aborted only", u.completed, u.aborted)
+		}
+	})
+}
+
+// verifyingUploader is a recordingUploader that implements ChecksumUploader
+type verifyingUploader struct {
+	recordingUploader
+	expected string
+}
+
+func (u *verifyingUploader) CompleteUploadWithChecksum(ctx context.Context, uploadID, expected string) error {
+	u.expected = expected
+	return u.CompleteUpload(ctx, uploadID)
+}
+
+func TestUploadReader_PartChecksum(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("abcdefgh"), 1024)
+
+	t.Run("completes with the stream checksum", func(t *testing.T) {
+		u := &verifyingUploader{}
+		err := filekit.UploadReader(ctx, u, "f.bin", bytes.NewReader(data),
+			filekit.WithPartSize(1000), filekit.WithUploadPartChecksum())
+		if err != nil {
+			t.Fatalf("UploadReader: %v", err)
+		}
+		if want := filekit.PartChecksum(data); u.expected != want {
+			t.Errorf("expected checksum = %q, want %q", u.expected, want)
+		}
+	})
+
+	t.Run("requires ChecksumUploader", func(t *testing.T) {
+		u := &recordingUploader{}
+		err := filekit.UploadReader(ctx, u, "f.bin", bytes.NewReader(data), filekit.WithUploadPartChecksum())
+		if !filekit.IsCode(err, filekit.ErrCodeNotSupported) {
+			t.Errorf("UploadReader = %v, want ErrCodeNotSupported", err)
+		}
+		if u.parts != nil {
+			t.Error("upload was initiated without checksum support")
+		}
+	})
+
+	t.Run("mismatch aborts", func(t *testing.T) {
+		fs, err := local.New(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadID, err := fs.InitiateUpload(ctx, "f.bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.UploadPart(ctx, uploadID, 1, data); err != nil {
+			t.Fatal(err)
+		}
+
+		err = fs.CompleteUploadWithChecksum(ctx, uploadID, filekit.PartChecksum([]byte("other")))
+		var mismatch *filekit.ChecksumMismatchError
+		if !filekit.IsCode(err, filekit.ErrCodeIntegrity) || !errors.As(err, &mismatch) {
+			t.Fatalf("CompleteUploadWithChecksum = %v, want an integrity error", err)
+		}
+		if mismatch.Algorithm != filekit.ChecksumSHA256 || mismatch.Part != 0 || mismatch.Actual != filekit.PartChecksum(data) {
+			t.Errorf("mismatch = %+v", mismatch)
+		}
+		if exists, _ := fs.FileExists(ctx, "f.bin"); exists {
+			t.Error("file exists after a checksum mismatch")
+		}
+	})
+}
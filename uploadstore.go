@@ -0,0 +1,337 @@
+package filekit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Upload Store - Persistence for chunked upload state
+// ============================================================================
+
+// UploadState is the metadata of an in-progress chunked upload.
+// Drivers save it on InitiateUpload and remove it on CompleteUpload or AbortUpload.
+type UploadState struct {
+	// UploadID is the identifier returned by InitiateUpload.
+	UploadID string `json:"upload_id"`
+
+	// Path is the target path of the final file.
+	Path string `json:"path"`
+
+	// PartsDir is the driver-specific location where parts are staged. For
+	// S3 it is the bucket and object key of the multipart upload.
+	PartsDir string `json:"parts_dir,omitempty"`
+
+	// CreatedAt is when the upload was initiated.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UploadStore persists chunked upload state.
+//
+// Drivers default to an in-memory store, so in-progress uploads are lost when
+// the process exits. Use a persistent store such as [FileUploadStore] to let
+// CompleteUpload resume an upload started by a previous process.
+//
+// Implementations must be safe for concurrent use.
+type UploadStore interface {
+	// Save creates or replaces the state of an upload.
+	Save(ctx context.Context, state *UploadState) error
+
+	// Load returns the state of an upload.
+	// Returns an error with ErrCodeNotFound if the upload does not exist.
+	Load(ctx context.Context, uploadID string) (*UploadState, error)
+
+	// Delete removes the state of an upload. Deleting a missing upload is not an error.
+	Delete(ctx context.Context, uploadID string) error
+
+	// Take removes the state of an upload and returns it. When several
+	// callers take the same upload concurrently, exactly one gets the state;
+	// the others get an error with ErrCodeNotFound, as for a missing upload.
+	// Drivers use it to claim an upload in CompleteUpload and AbortUpload.
+	Take(ctx context.Context, uploadID string) (*UploadState, error)
+
+	// List returns the state of all known uploads, ordered by creation time.
+	List(ctx context.Context) ([]*UploadState, error)
+}
+
+// ============================================================================
+// MemoryUploadStore
+// ============================================================================
+
+// MemoryUploadStore is an in-memory UploadStore.
+type MemoryUploadStore struct {
+	mu      sync.RWMutex
+	uploads map[string]UploadState
+}
+
+// NewMemoryUploadStore creates an empty in-memory upload store.
+func NewMemoryUploadStore() *MemoryUploadStore {
+	return &MemoryUploadStore{uploads: make(map[string]UploadState)}
+}
+
+// Save implements UploadStore.
+func (s *MemoryUploadStore) Save(ctx context.Context, state *UploadState) error {
+	if err := FromContext(ctx, "save-upload", state.UploadID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.uploads[state.UploadID] = *state
+	s.mu.Unlock()
+	return nil
+}
+
+// Load implements UploadStore.
+func (s *MemoryUploadStore) Load(ctx context.Context, uploadID string) (*UploadState, error) {
+	if err := FromContext(ctx, "load-upload", uploadID); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	state, ok := s.uploads[uploadID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, NewPathError("load-upload", uploadID, ErrCodeNotFound, "upload not found")
+	}
+	return &state, nil
+}
+
+// Delete implements UploadStore.
+func (s *MemoryUploadStore) Delete(ctx context.Context, uploadID string) error {
+	if err := FromContext(ctx, "delete-upload", uploadID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+	return nil
+}
+
+// Take implements UploadStore.
+func (s *MemoryUploadStore) Take(ctx context.Context, uploadID string) (*UploadState, error) {
+	if err := FromContext(ctx, "take-upload", uploadID); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	state, ok := s.uploads[uploadID]
+	delete(s.uploads, uploadID)
+	s.mu.Unlock()
+	if !ok {
+		return nil, NewPathError("take-upload", uploadID, ErrCodeNotFound, "upload not found")
+	}
+	return &state, nil
+}
+
+// List implements UploadStore.
+func (s *MemoryUploadStore) List(ctx context.Context) ([]*UploadState, error) {
+	if err := FromContext(ctx, "list-uploads", ""); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	states := make([]*UploadState, 0, len(s.uploads))
+	for _, state := range s.uploads {
+		state := state
+		states = append(states, &state)
+	}
+	s.mu.RUnlock()
+	sortUploadStates(states)
+	return states, nil
+}
+
+// ============================================================================
+// FileUploadStore
+// ============================================================================
+
+// FileUploadStore is an UploadStore that keeps one JSON file per upload in a
+// directory, so upload state survives process restarts. Files are named after
+// the hex-encoded upload ID, so any ID a backend returns can be stored.
+type FileUploadStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileUploadStore creates a file-backed upload store in dir, creating the
+// directory if needed.
+//
+// Example:
+//
+//	store, err := filekit.NewFileUploadStore("/var/lib/myapp/uploads")
+//	fs, err := local.New("/data", local.WithUploadStore(store))
+func NewFileUploadStore(dir string) (*FileUploadStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, WrapPathErr("upload-store", dir, err)
+	}
+	return &FileUploadStore{dir: dir}, nil
+}
+
+// statePath returns the file holding the state of uploadID.
+func (s *FileUploadStore) statePath(op, uploadID string) (string, error) {
+	if uploadID == "" {
+		return "", NewPathError(op, uploadID, ErrCodeInvalidInput, "invalid upload ID")
+	}
+	return filepath.Join(s.dir, hex.EncodeToString([]byte(uploadID))+".json"), nil
+}
+
+// Save implements UploadStore. The state file is written atomically.
+func (s *FileUploadStore) Save(ctx context.Context, state *UploadState) error {
+	if err := FromContext(ctx, "save-upload", state.UploadID); err != nil {
+		return err
+	}
+	target, err := s.statePath("save-upload", state.UploadID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return WrapPathErr("save-upload", state.UploadID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return WrapPathErr("save-upload", state.UploadID, err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return WrapPathErr("save-upload", state.UploadID, err)
+	}
+	return nil
+}
+
+// Load implements UploadStore.
+func (s *FileUploadStore) Load(ctx context.Context, uploadID string) (*UploadState, error) {
+	if err := FromContext(ctx, "load-upload", uploadID); err != nil {
+		return nil, err
+	}
+	target, err := s.statePath("load-upload", uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readUploadState("load-upload", uploadID, target)
+}
+
+// Delete implements UploadStore.
+func (s *FileUploadStore) Delete(ctx context.Context, uploadID string) error {
+	if err := FromContext(ctx, "delete-upload", uploadID); err != nil {
+		return err
+	}
+	target, err := s.statePath("delete-upload", uploadID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return WrapPathErr("delete-upload", uploadID, err)
+	}
+	return nil
+}
+
+// Take implements UploadStore. The state file is first renamed to a name
+// unique to this call, so only one of several processes sharing the
+// directory can claim it.
+func (s *FileUploadStore) Take(ctx context.Context, uploadID string) (*UploadState, error) {
+	if err := FromContext(ctx, "take-upload", uploadID); err != nil {
+		return nil, err
+	}
+	target, err := s.statePath("take-upload", uploadID)
+	if err != nil {
+		return nil, err
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, WrapPathErr("take-upload", uploadID, err)
+	}
+	claimed := target + ".taken-" + hex.EncodeToString(suffix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(target, claimed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, NewPathError("take-upload", uploadID, ErrCodeNotFound, "upload not found")
+		}
+		return nil, WrapPathErr("take-upload", uploadID, err)
+	}
+	defer os.Remove(claimed)
+	return readUploadState("take-upload", uploadID, claimed)
+}
+
+// readUploadState reads the state file at name.
+func readUploadState(op, uploadID, name string) (*UploadState, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, NewPathError(op, uploadID, ErrCodeNotFound, "upload not found")
+		}
+		return nil, WrapPathErr(op, uploadID, err)
+	}
+
+	var state UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, WrapPath(err, op, uploadID, ErrCodeIntegrity, "invalid upload state")
+	}
+	return &state, nil
+}
+
+// List implements UploadStore. Unreadable state files are skipped.
+func (s *FileUploadStore) List(ctx context.Context) ([]*UploadState, error) {
+	if err := FromContext(ctx, "list-uploads", s.dir); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, WrapPathErr("list-uploads", s.dir, err)
+	}
+
+	states := make([]*UploadState, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		uploadID, err := hex.DecodeString(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		state, err := s.Load(ctx, string(uploadID))
+		if err != nil {
+			continue
+		}
+		states = append(states, state)
+	}
+	sortUploadStates(states)
+	return states, nil
+}
+
+// sortUploadStates orders states by creation time, then upload ID.
+func sortUploadStates(states []*UploadState) {
+	sort.Slice(states, func(i, j int) bool {
+		if !states[i].CreatedAt.Equal(states[j].CreatedAt) {
+			return states[i].CreatedAt.Before(states[j].CreatedAt)
+		}
+		return states[i].UploadID < states[j].UploadID
+	})
+}
+
+// Ensure implementations satisfy UploadStore
+var (
+	_ UploadStore = (*MemoryUploadStore)(nil)
+	_ UploadStore = (*FileUploadStore)(nil)
+)
@@ -0,0 +1,167 @@
+package filekit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobeaver/filekit"
+)
+
+func TestUploadStores(t *testing.T) {
+	fileStore, err := filekit.NewFileUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file store: %v", err)
+	}
+
+	stores := map[string]filekit.UploadStore{
+		"memory": filekit.NewMemoryUploadStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Second)
+
+			older := &filekit.UploadState{UploadID: "b", Path: "old.bin", PartsDir: "/tmp/b", CreatedAt: now.Add(-time.Hour)}
+			newer := &filekit.UploadState{UploadID: "a", Path: "new.bin", PartsDir: "/tmp/a", CreatedAt: now}
+			for _, state := range []*filekit.UploadState{newer, older} {
+				if err := store.Save(ctx, state); err != nil {
+					t.Fatalf("save %s failed: %v", state.UploadID, err)
+				}
+			}
+
+			loaded, err := store.Load(ctx, "b")
+			if err != nil {
+				t.Fatalf("load failed: %v", err)
+			}
+			if loaded.Path != "old.bin" || loaded.PartsDir != "/tmp/b" || !loaded.CreatedAt.Equal(older.CreatedAt) {
+				t.Errorf("unexpected state: %+v", loaded)
+			}
+
+			states, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			if len(states) != 2 || states[0].UploadID != "b" || states[1].UploadID != "a" {
+				t.Errorf("expected uploads ordered by creation time, got %+v", states)
+			}
+
+			if err := store.Delete(ctx, "b"); err != nil {
+				t.Fatalf("delete failed: %v", err)
+			}
+			if _, err := store.Load(ctx, "b"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+				t.Errorf("expected not found after delete, got %v", err)
+			}
+
+			// Deleting a missing upload is not an error
+			if err := store.Delete(ctx, "missing"); err != nil {
+				t.Errorf("delete of missing upload failed: %v", err)
+			}
+
+			taken, err := store.Take(ctx, "a")
+			if err != nil || taken.Path != "new.bin" {
+				t.Fatalf("Take = %+v, %v; want the state of a", taken, err)
+			}
+			if _, err := store.Take(ctx, "a"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+				t.Errorf("second Take = %v, want not found", err)
+			}
+			if states, _ := store.List(ctx); len(states) != 0 {
+				t.Errorf("List after Take = %+v, want empty", states)
+			}
+		})
+	}
+}
+
+func TestUploadStores_TakeOnce(t *testing.T) {
+	fileStore, err := filekit.NewFileUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create file store: %v", err)
+	}
+	stores := map[string]filekit.UploadStore{
+		"memory": filekit.NewMemoryUploadStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := store.Save(ctx, &filekit.UploadState{UploadID: "a", Path: "a.bin"}); err != nil {
+				t.Fatalf("save failed: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			var won atomic.Int32
+			for range 16 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					state, err := store.Take(ctx, "a")
+					switch {
+					case err == nil && state.Path == "a.bin":
+						won.Add(1)
+					case !filekit.IsCode(err, filekit.ErrCodeNotFound):
+						t.Errorf("Take = %+v, %v; want the state or not found", state, err)
+					}
+				}()
+			}
+			wg.Wait()
+			if n := won.Load(); n != 1 {
+				t.Errorf("%d concurrent Takes got the state, want 1", n)
+			}
+		})
+	}
+}
+
+func TestFileUploadStore_EncodesIDs(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "store")
+	store, err := filekit.NewFileUploadStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create file store: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.Save(ctx, &filekit.UploadState{UploadID: ""}); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("Save(\"\"): expected invalid input error, got %v", err)
+	}
+
+	// IDs are backend data, such as S3 upload IDs with '.', '~' or '=', and
+	// must not be read as paths
+	ids := []string{"../escape", "a/b", "a.json", "VXBsb2FkIElE.ZX~Jv=", `a\b`}
+	for _, id := range ids {
+		if err := store.Save(ctx, &filekit.UploadState{UploadID: id, Path: id + ".bin"}); err != nil {
+			t.Fatalf("Save(%q) failed: %v", id, err)
+		}
+		state, err := store.Load(ctx, id)
+		if err != nil || state.Path != id+".bin" {
+			t.Errorf("Load(%q) = %+v, %v", id, state, err)
+		}
+	}
+
+	states, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	listed := make([]string, 0, len(states))
+	for _, state := range states {
+		listed = append(listed, state.UploadID)
+	}
+	slices.Sort(listed)
+	want := slices.Sorted(slices.Values(ids))
+	if !slices.Equal(listed, want) {
+		t.Errorf("List = %v, want %v", listed, want)
+	}
+
+	// Every state file stays inside the store directory
+	entries, _ := os.ReadDir(parent)
+	if len(entries) != 1 || entries[0].Name() != "store" {
+		t.Errorf("files written outside the store directory: %v", entries)
+	}
+}
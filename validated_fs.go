@@ -14,100 +14,270 @@ import (
 type ValidatedFileSystem struct {
 	fs        FileSystem
 	validator filevalidator.Validator
+	opts      ValidatedOptions
+}
+
+// ValidationMode controls what ValidatedFileSystem does with invalid content.
+type ValidationMode int
+
+const (
+	// ModeEnforce rejects invalid writes with the validation error (default).
+	ModeEnforce ValidationMode = iota
+
+	// ModeWarn reports invalid writes to OnViolation but stores them anyway.
+	ModeWarn
+
+	// ModeReportOnly writes without validating. Content is validated only by
+	// explicit CheckWrite calls, which report violations to OnViolation.
+	ModeReportOnly
+)
+
+// ValidatedOptions configures the ValidatedFileSystem behavior.
+type ValidatedOptions struct {
+	// Mode selects enforcing, warning or report-only validation.
+	// Default: ModeEnforce
+	Mode ValidationMode
+
+	// OnViolation is called with the path and validation error whenever
+	// content fails validation in ModeWarn, and from CheckWrite in any mode.
+	OnViolation func(path string, err error)
+}
+
+// ValidatedOption is a functional option for configuring ValidatedFileSystem.
+type ValidatedOption func(*ValidatedOptions)
+
+// WithValidationMode sets how invalid content is handled. Use ModeWarn or
+// ModeReportOnly to audit what would be rejected before enforcing.
+func WithValidationMode(mode ValidationMode) ValidatedOption {
+	return func(o *ValidatedOptions) {
+		o.Mode = mode
+	}
+}
+
+// WithOnViolation sets the callback invoked for content that fails validation.
+func WithOnViolation(fn func(path string, err error)) ValidatedOption {
+	return func(o *ValidatedOptions) {
+		o.OnViolation = fn
+	}
 }
 
 // NewValidatedFileSystem creates a new FileSystem with validation
-func NewValidatedFileSystem(fs FileSystem, validator filevalidator.Validator) *ValidatedFileSystem {
+func NewValidatedFileSystem(fs FileSystem, validator filevalidator.Validator, opts ...ValidatedOption) *ValidatedFileSystem {
+	options := ValidatedOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &ValidatedFileSystem{
 		fs:        fs,
 		validator: validator,
+		opts:      options,
 	}
 }
 
-// Write implements FileSystem with validation
+// Write implements FileSystem with validation.
+// In ModeWarn, violations are reported to OnViolation and the content is
+// still written unchanged; in ModeReportOnly, Write does not validate.
 func (v *ValidatedFileSystem) Write(ctx context.Context, path string, content io.Reader, options ...Option) (*WriteResult, error) {
-	// Process options
+	validator := v.validatorFor(options)
+
+	// Validate while keeping the content streamable: seekable readers are rewound,
+	// other readers have only their header buffered and replayed
+	if validator != nil && v.opts.Mode != ModeReportOnly {
+		var report func(error)
+		if v.opts.Mode == ModeWarn {
+			report = func(err error) { v.reportViolation(path, err) }
+		}
+
+		var err error
+		content, err = validateStream(validator, filepath.Base(path), content, readerLen(content), report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Pass through to the underlying filesystem
+	return v.fs.Write(ctx, path, content, options...)
+}
+
+// CheckWrite validates content as Write would, without writing anything.
+// It returns the validation error, if any, and reports it to OnViolation.
+// The content is read to the end so size limits are checked for streams.
+//
+// With ModeReportOnly this audits uploads without affecting real writes:
+//
+//	vfs := filekit.NewValidatedFileSystem(fs, validator,
+//	    filekit.WithValidationMode(filekit.ModeReportOnly),
+//	    filekit.WithOnViolation(func(path string, err error) {
+//	        log.Printf("would reject %s: %v", path, err)
+//	    }),
+//	)
+//	_ = vfs.CheckWrite(ctx, "uploads/a.exe", bytes.NewReader(data))
+func (v *ValidatedFileSystem) CheckWrite(ctx context.Context, path string, content io.Reader, options ...Option) error {
+	if err := FromContext(ctx, "check-write", path); err != nil {
+		return err
+	}
+
+	validator := v.validatorFor(options)
+	if validator == nil {
+		return nil
+	}
+
+	r, err := validateStream(validator, filepath.Base(path), content, readerLen(content), nil)
+	if err == nil {
+		_, err = io.Copy(io.Discard, r)
+	}
+	if err != nil {
+		v.reportViolation(path, err)
+	}
+	return err
+}
+
+// validatorFor returns the validator from the write options, or the default one
+func (v *ValidatedFileSystem) validatorFor(options []Option) filevalidator.Validator {
 	opts := &Options{}
 	for _, option := range options {
 		option(opts)
 	}
-
-	// If a validator is provided in options, use it; otherwise use the default validator
-	validator := v.validator
 	if opts.Validator != nil {
-		validator = opts.Validator
+		return opts.Validator
 	}
+	return v.validator
+}
 
-	// If we have a validator, perform validation
-	if validator != nil {
-		// Optimization: If the reader is an io.Seeker (like os.File), use it directly!
-		// filevalidator handles seeking automatically.
-		if seeker, ok := content.(io.ReadSeeker); ok {
-			// We need to know the size for validation
-			size, err := getStreamSize(seeker)
-			if err != nil {
-				return nil, err
-			}
+func (v *ValidatedFileSystem) reportViolation(path string, err error) {
+	if v.opts.OnViolation != nil {
+		v.opts.OnViolation(path, err)
+	}
+}
 
-			// Validate using the seeker
-			if err := validator.ValidateReader(content, filepath.Base(path), size); err != nil {
-				return nil, err
-			}
+// readerLen returns the length of readers that expose it, or -1
+func readerLen(r io.Reader) int64 {
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len())
+	}
+	return -1
+}
 
-			// Reset seeker to start for write
-			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
-				return nil, err
-			}
-		} else {
-			// For non-seekable streams (like HTTP body), we have to be careful.
-			// We can't validate the *entire* content (like Zip structure) without buffering it all.
-			// But we CAN validate MIME type and size efficiently.
-
-			// 1. Read the header for MIME detection (512 bytes)
-			header := make([]byte, 512)
-			n, err := io.ReadFull(content, header)
-			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-				return nil, err
-			}
-			header = header[:n]
+// validationHeaderSize is how much of a non-seekable stream is buffered for validation
+const validationHeaderSize = 8 * 1024
+
+// headerValidator is implemented by validators that can validate a stream from its
+// first bytes and declared size, such as *filevalidator.FileValidator
+type headerValidator interface {
+	ValidateHeader(header []byte, filename string, size int64) error
+}
+
+// ValidateAndStore validates r with v and writes it to path if valid, reading the
+// payload once. Only the first bytes are buffered for validation; the rest is
+// streamed straight to Write. size is the declared content length, or -1 if unknown.
+// On validation failure nothing is written and r is not read beyond the header.
+//
+// Content validation (e.g. ZIP structure) needs the whole file, so for streams
+// larger than the header it runs only when r is an io.ReadSeeker.
+func ValidateAndStore(ctx context.Context, fs FileSystem, v *filevalidator.FileValidator, path string, r io.Reader, size int64, opts ...Option) (*WriteResult, error) {
+	if err := FromContext(ctx, "write", path); err != nil {
+		return nil, err
+	}
+
+	content := r
+	if v != nil {
+		var err error
+		content, err = validateStream(v, filepath.Base(path), r, size, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fs.Write(ctx, path, content, opts...)
+}
 
-			// 2. Perform "Best Effort" validation using the header
-			if err := validator.ValidateBytes(header, filepath.Base(path)); err != nil {
+// validateStream validates content destined for filename and returns the reader to
+// write. Seekable readers are validated in place and rewound. Other readers have
+// only their first validationHeaderSize bytes buffered; the returned reader replays
+// them ahead of the rest of the stream and enforces MaxFileSize while streaming.
+//
+// If report is non-nil, validation failures are passed to it instead of being
+// returned, and the returned reader yields the content unchanged. MaxFileSize
+// overruns detected while streaming are reported the same way.
+func validateStream(validator filevalidator.Validator, filename string, content io.Reader, size int64, report func(error)) (io.Reader, error) {
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			if size, err = getStreamSize(seeker); err != nil {
 				return nil, err
 			}
+		}
+		verr := validator.ValidateReader(content, filename, size)
+		if verr != nil && report == nil {
+			return nil, verr
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if verr != nil {
+			report(verr)
+		}
+		return content, nil
+	}
 
-			// 3. Reconstruct the reader for Write
-			// We stitch the header back with the rest of the stream
-			content = io.MultiReader(bytes.NewReader(header), content)
-
-			// 4. Enforce MaxFileSize for streams
-			// Since we can't know the size upfront, we wrap the reader to error if it exceeds the limit.
-			constraints := validator.GetConstraints()
-			if constraints.MaxFileSize > 0 {
-				content = &SizeLimitReader{
-					R:     content,
-					Limit: constraints.MaxFileSize,
-				}
-			}
+	header := make([]byte, validationHeaderSize)
+	n, err := io.ReadFull(content, header)
+	complete := err == io.EOF || err == io.ErrUnexpectedEOF
+	if err != nil && !complete {
+		return nil, err
+	}
+	header = header[:n]
+	if complete {
+		size = int64(n)
+	}
+
+	if hv, ok := validator.(headerValidator); ok {
+		err = hv.ValidateHeader(header, filename, size)
+	} else {
+		err = validator.ValidateBytes(header, filename)
+	}
+	if err != nil {
+		if report == nil {
+			return nil, err
 		}
+		report(err)
 	}
 
-	// Pass through to the underlying filesystem
-	return v.fs.Write(ctx, path, content, options...)
+	if complete {
+		return bytes.NewReader(header), nil
+	}
+	content = io.MultiReader(bytes.NewReader(header), content)
+	if maxSize := validator.GetConstraints().MaxFileSize; maxSize > 0 {
+		content = &SizeLimitReader{R: content, Limit: maxSize, OnExceed: report}
+	}
+	return content, nil
 }
 
 // SizeLimitReader restricts the number of bytes read and returns an error if the limit is exceeded.
+// If OnExceed is set, it is called once with that error instead and reading continues.
 type SizeLimitReader struct {
-	R     io.Reader
-	Limit int64
-	N     int64
+	R        io.Reader
+	Limit    int64
+	N        int64
+	OnExceed func(error)
 }
 
 func (l *SizeLimitReader) Read(p []byte) (n int, err error) {
 	n, err = l.R.Read(p)
+	before := l.N
 	l.N += int64(n)
 	if l.N > l.Limit {
-		return n, fmt.Errorf("file size exceeds limit of %d bytes", l.Limit)
+		limitErr := fmt.Errorf("file size exceeds limit of %d bytes", l.Limit)
+		if l.OnExceed == nil {
+			return n, limitErr
+		}
+		if before <= l.Limit {
+			l.OnExceed(limitErr)
+		}
 	}
 	return n, err
 }
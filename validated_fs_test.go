@@ -0,0 +1,407 @@
+package filekit_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+	"github.com/gobeaver/filekit/filevalidator"
+)
+
+// countingReader records how many bytes have been read and hides any Seek method
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func pngContent(size int) []byte {
+	data := make([]byte, size)
+	copy(data, []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A})
+	for i := 8; i < size; i++ {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func TestValidateAndStore(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(1 * filevalidator.MB).
+		Build()
+
+	content := pngContent(256 * 1024)
+	source := &countingReader{r: bytes.NewReader(content)}
+
+	result, err := filekit.ValidateAndStore(ctx, fs, validator, "images/photo.png", source, int64(len(content)))
+	if err != nil {
+		t.Fatalf("ValidateAndStore failed: %v", err)
+	}
+	if result.BytesWritten != int64(len(content)) {
+		t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len(content))
+	}
+	if source.n != int64(len(content)) {
+		t.Errorf("source read %d bytes, want exactly %d", source.n, len(content))
+	}
+
+	stored, err := fs.ReadAll(ctx, "images/photo.png")
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(stored, content) {
+		t.Error("stored content differs from input")
+	}
+}
+
+func TestValidateAndStore_Invalid(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(1 * filevalidator.MB).
+		Build()
+
+	tests := []struct {
+		name    string
+		path    string
+		content []byte
+		size    int64
+		errType filevalidator.ValidationErrorType
+	}{
+		{"wrong type", "fake.png", append([]byte("%PDF-1.7\n"), make([]byte, 64*1024)...), -1, filevalidator.ErrorTypeMIME},
+		{"blocked extension", "photo.exe", pngContent(64 * 1024), -1, filevalidator.ErrorTypeExtension},
+		{"declared size too large", "big.png", pngContent(64 * 1024), 2 * filevalidator.MB, filevalidator.ErrorTypeSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &countingReader{r: bytes.NewReader(tt.content)}
+
+			_, err := filekit.ValidateAndStore(ctx, fs, validator, tt.path, source, tt.size)
+			if !filevalidator.IsErrorOfType(err, tt.errType) {
+				t.Fatalf("expected %s validation error, got %v", tt.errType, err)
+			}
+			if source.n >= int64(len(tt.content)) {
+				t.Errorf("source was fully read (%d bytes) despite failing validation", source.n)
+			}
+			if exists, _ := fs.FileExists(ctx, tt.path); exists {
+				t.Error("invalid file was stored")
+			}
+		})
+	}
+}
+
+func TestValidatedFileSystem_EmptyFile(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	validator := filevalidator.NewBuilder().Extensions(".txt").MinSize(1).Build()
+	vfs := filekit.NewValidatedFileSystem(fs, validator)
+
+	if _, err := vfs.Write(ctx, "a.txt", strings.NewReader("")); !filevalidator.IsErrorOfType(err, filevalidator.ErrorTypeSize) {
+		t.Errorf("Write of an empty file = %v, want ErrorTypeSize", err)
+	}
+	for _, size := range []int64{0, -1} {
+		if _, err := filekit.ValidateAndStore(ctx, fs, validator, "a.txt", strings.NewReader(""), size); !filevalidator.IsErrorOfType(err, filevalidator.ErrorTypeSize) {
+			t.Errorf("ValidateAndStore of an empty file with size %d = %v, want ErrorTypeSize", size, err)
+		}
+	}
+	if exists, _ := fs.FileExists(ctx, "a.txt"); exists {
+		t.Error("empty file was stored")
+	}
+
+	if _, err := vfs.Write(ctx, "a.txt", strings.NewReader("x")); err != nil {
+		t.Errorf("Write of a file at the minimum size = %v, want nil", err)
+	}
+}
+
+func TestValidateAndStore_StreamExceedsMaxSize(t *testing.T) {
+	ctx := context.Background()
+	fs := memory.New()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(32 * filevalidator.KB).
+		Build()
+
+	// The declared size is unknown, so the limit is enforced while streaming
+	content := pngContent(64 * 1024)
+	_, err := filekit.ValidateAndStore(ctx, fs, validator, "big.png", &countingReader{r: bytes.NewReader(content)}, -1)
+	if err == nil {
+		t.Fatal("expected error for stream exceeding MaxFileSize")
+	}
+	if exists, _ := fs.FileExists(ctx, "big.png"); exists {
+		t.Error("oversized file was stored")
+	}
+}
+
+// patternReader generates size bytes of PNG-looking content without holding it in memory
+type patternReader struct {
+	size, off int64
+}
+
+func (p *patternReader) Read(b []byte) (int, error) {
+	if p.off >= p.size {
+		return 0, io.EOF
+	}
+	n := int64(len(b))
+	if remaining := p.size - p.off; n > remaining {
+		n = remaining
+	}
+	signature := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	for i := int64(0); i < n; i++ {
+		pos := p.off + i
+		if pos < int64(len(signature)) {
+			b[i] = signature[pos]
+		} else {
+			b[i] = byte(pos * 31)
+		}
+	}
+	p.off += n
+	return int(n), nil
+}
+
+// hashingFS records a digest of written content instead of storing it
+type hashingFS struct {
+	filekit.FileSystem
+	sum []byte
+	n   int64
+}
+
+func (h *hashingFS) Write(ctx context.Context, path string, content io.Reader, options ...filekit.Option) (*filekit.WriteResult, error) {
+	hash := sha256.New()
+	n, err := io.Copy(hash, content)
+	if err != nil {
+		return nil, err
+	}
+	h.sum, h.n = hash.Sum(nil), n
+	return &filekit.WriteResult{BytesWritten: n}, nil
+}
+
+func TestValidatedFileSystem_StreamsLargeFile(t *testing.T) {
+	ctx := context.Background()
+	const size = 64 << 20
+
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(128 * filevalidator.MB).
+		Build()
+	target := &hashingFS{FileSystem: memory.New()}
+	fs := filekit.NewValidatedFileSystem(target, validator)
+
+	expected := sha256.New()
+	source := io.TeeReader(&patternReader{size: size}, expected)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	if _, err := fs.Write(ctx, "large.png", source); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 8<<20 {
+		t.Errorf("Write allocated %d bytes for a %d byte stream; content was buffered", allocated, size)
+	}
+	if target.n != size {
+		t.Errorf("underlying Write received %d bytes, want %d", target.n, size)
+	}
+	if !bytes.Equal(target.sum, expected.Sum(nil)) {
+		t.Error("stored bytes differ from input")
+	}
+}
+
+func TestValidatedFileSystem_PreservesContent(t *testing.T) {
+	ctx := context.Background()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(1 * filevalidator.MB).
+		Build()
+	target := memory.New()
+	fs := filekit.NewValidatedFileSystem(target, validator)
+
+	content := pngContent(100 * 1024)
+
+	tests := []struct {
+		name   string
+		reader io.Reader
+		want   []byte
+	}{
+		{"non-seekable", &countingReader{r: bytes.NewReader(content)}, content},
+		{"seekable", bytes.NewReader(content), content},
+		{"buffer", bytes.NewBuffer(append([]byte(nil), content...)), content},
+		{"smaller than header", &countingReader{r: bytes.NewReader(content[:1000])}, content[:1000]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fs.Write(ctx, "photo.png", tt.reader, filekit.WithOverwrite(true)); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			stored, err := target.ReadAll(ctx, "photo.png")
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if !bytes.Equal(stored, tt.want) {
+				t.Errorf("stored %d bytes, want the %d input bytes", len(stored), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestValidatedFileSystem_StreamSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(64 * filevalidator.KB).
+		Build()
+	target := memory.New()
+	fs := filekit.NewValidatedFileSystem(target, validator)
+
+	// Size is unknown for plain readers, so the limit is enforced by counting
+	if _, err := fs.Write(ctx, "big.png", &countingReader{r: bytes.NewReader(pngContent(65*1024 + 1))}); err == nil {
+		t.Error("expected error for stream exceeding MaxFileSize")
+	}
+
+	// A bytes.Buffer declares its size up front
+	_, err := fs.Write(ctx, "big.png", bytes.NewBuffer(pngContent(65*1024)))
+	if !filevalidator.IsErrorOfType(err, filevalidator.ErrorTypeSize) {
+		t.Errorf("expected size validation error from declared size, got %v", err)
+	}
+}
+
+func TestValidatedFileSystem_Modes(t *testing.T) {
+	ctx := context.Background()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(64 * filevalidator.KB).
+		Build()
+	invalid := []byte("#!/bin/sh\necho not an image\n")
+
+	tests := []struct {
+		name       string
+		mode       filekit.ValidationMode
+		reader     io.Reader
+		wantStored bool
+		wantCalls  int
+	}{
+		{"enforce blocks", filekit.ModeEnforce, bytes.NewReader(invalid), false, 0},
+		{"warn allows seekable", filekit.ModeWarn, bytes.NewReader(invalid), true, 1},
+		{"warn allows stream", filekit.ModeWarn, &countingReader{r: bytes.NewReader(invalid)}, true, 1},
+		{"report-only allows", filekit.ModeReportOnly, bytes.NewReader(invalid), true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := memory.New()
+			var violations []string
+			fs := filekit.NewValidatedFileSystem(target, validator,
+				filekit.WithValidationMode(tt.mode),
+				filekit.WithOnViolation(func(path string, err error) {
+					if err == nil {
+						t.Error("OnViolation called with nil error")
+					}
+					violations = append(violations, path)
+				}),
+			)
+
+			_, err := fs.Write(ctx, "script.png", tt.reader)
+			if tt.wantStored && err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if !tt.wantStored && err == nil {
+				t.Fatal("expected Write to be rejected")
+			}
+			if len(violations) != tt.wantCalls {
+				t.Errorf("OnViolation called %d times, want %d", len(violations), tt.wantCalls)
+			}
+
+			stored, err := target.ReadAll(ctx, "script.png")
+			if tt.wantStored && !bytes.Equal(stored, invalid) {
+				t.Errorf("stored %q (err %v), want the original content", stored, err)
+			}
+			if !tt.wantStored && err == nil {
+				t.Error("rejected content must not be stored")
+			}
+		})
+	}
+}
+
+func TestValidatedFileSystem_WarnStreamSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(64 * filevalidator.KB).
+		Build()
+	target := memory.New()
+	calls := 0
+	fs := filekit.NewValidatedFileSystem(target, validator,
+		filekit.WithValidationMode(filekit.ModeWarn),
+		filekit.WithOnViolation(func(string, error) { calls++ }),
+	)
+
+	content := pngContent(128 * 1024)
+	if _, err := fs.Write(ctx, "big.png", &countingReader{r: bytes.NewReader(content)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("OnViolation called %d times, want 1", calls)
+	}
+	if stored, _ := target.ReadAll(ctx, "big.png"); len(stored) != len(content) {
+		t.Errorf("stored %d bytes, want %d", len(stored), len(content))
+	}
+}
+
+func TestValidatedFileSystem_CheckWrite(t *testing.T) {
+	ctx := context.Background()
+	validator := filevalidator.NewBuilder().
+		Accept("image/png").
+		Extensions(".png").
+		MaxSize(64 * filevalidator.KB).
+		Build()
+	target := memory.New()
+	var violations []string
+	fs := filekit.NewValidatedFileSystem(target, validator,
+		filekit.WithValidationMode(filekit.ModeReportOnly),
+		filekit.WithOnViolation(func(path string, err error) {
+			violations = append(violations, path)
+		}),
+	)
+
+	if err := fs.CheckWrite(ctx, "ok.png", bytes.NewReader(pngContent(1024))); err != nil {
+		t.Errorf("CheckWrite(valid) = %v, want nil", err)
+	}
+	if err := fs.CheckWrite(ctx, "bad.png", bytes.NewReader([]byte("plain text"))); err == nil {
+		t.Error("CheckWrite(invalid) = nil, want error")
+	}
+	if err := fs.CheckWrite(ctx, "big.png", &countingReader{r: bytes.NewReader(pngContent(65*1024 + 1))}); err == nil {
+		t.Error("CheckWrite(oversized stream) = nil, want error")
+	}
+
+	if len(violations) != 2 || violations[0] != "bad.png" || violations[1] != "big.png" {
+		t.Errorf("violations = %v, want [bad.png big.png]", violations)
+	}
+	if files, _ := target.ListContents(ctx, "", true); len(files) != 0 {
+		t.Errorf("CheckWrite must not write, found %d files", len(files))
+	}
+}
@@ -0,0 +1,356 @@
+package filekit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// VersionedFileSystem Decorator
+// ============================================================================
+
+// VersionedFileSystem wraps a FileSystem to keep prior versions of files.
+// Before a file is overwritten, its current content is copied to a sidecar
+// key under the versions directory (".versions/<path>/<versionID>" by default).
+// At most MaxVersions versions are kept per path; older ones are pruned.
+//
+// Versioning works over any backend using Read/Write, or the backend's native
+// copy when it implements CanCopy. Deleting a file keeps its versions, so it
+// can still be restored.
+//
+// Example:
+//
+//	versioned := filekit.NewVersionedFileSystem(fs, filekit.WithMaxVersions(5))
+//
+//	versioned.Write(ctx, "config.json", v1)
+//	versioned.Write(ctx, "config.json", v2, filekit.WithOverwrite(true))
+//
+//	versions, _ := versioned.ListVersions(ctx, "config.json")
+//	err := versioned.RestoreVersion(ctx, "config.json", versions[0].ID)
+type VersionedFileSystem struct {
+	fs   FileSystem
+	opts VersionedOptions
+
+	mu  sync.Mutex
+	seq int64
+}
+
+// VersionedOptions configures the VersionedFileSystem behavior.
+type VersionedOptions struct {
+	// MaxVersions is the number of prior versions to keep per path.
+	// Default: 10
+	MaxVersions int
+
+	// VersionsDir is the directory holding version sidecars.
+	// It is hidden from ListContents.
+	// Default: ".versions"
+	VersionsDir string
+}
+
+// VersionedOption is a functional option for configuring VersionedFileSystem.
+type VersionedOption func(*VersionedOptions)
+
+// WithMaxVersions sets the number of prior versions kept per path.
+func WithMaxVersions(n int) VersionedOption {
+	return func(o *VersionedOptions) {
+		o.MaxVersions = n
+	}
+}
+
+// WithVersionsDir sets the directory holding version sidecars.
+func WithVersionsDir(dir string) VersionedOption {
+	return func(o *VersionedOptions) {
+		o.VersionsDir = dir
+	}
+}
+
+// VersionInfo describes a stored prior version of a file.
+type VersionInfo struct {
+	// ID identifies the version. IDs sort chronologically.
+	ID string
+
+	// Path is the path of the versioned file.
+	Path string
+
+	// Size is the size of the version's content in bytes.
+	Size int64
+
+	// CreatedAt is when the version was saved, i.e. when it was overwritten.
+	CreatedAt time.Time
+}
+
+// NewVersionedFileSystem creates a versioning wrapper around a FileSystem.
+func NewVersionedFileSystem(fs FileSystem, opts ...VersionedOption) *VersionedFileSystem {
+	options := VersionedOptions{
+		MaxVersions: 10,
+		VersionsDir: ".versions",
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.MaxVersions < 1 {
+		options.MaxVersions = 1
+	}
+	options.VersionsDir = strings.Trim(path.Clean("/"+options.VersionsDir), "/")
+
+	return &VersionedFileSystem{
+		fs:   fs,
+		opts: options,
+	}
+}
+
+// Unwrap returns the underlying FileSystem.
+func (v *VersionedFileSystem) Unwrap() FileSystem {
+	return v.fs
+}
+
+// ============================================================================
+// Version Management
+// ============================================================================
+
+// ListVersions returns the stored prior versions of a file, newest first.
+func (v *VersionedFileSystem) ListVersions(ctx context.Context, p string) ([]VersionInfo, error) {
+	entries, err := v.fs.ListContents(ctx, v.versionDir(p), false)
+	if err != nil {
+		if IsCode(err, ErrCodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	versions := make([]VersionInfo, 0, len(entries))
+	for i := range entries {
+		if entries[i].IsDir {
+			continue
+		}
+		id := path.Base(entries[i].Path)
+		versions = append(versions, VersionInfo{
+			ID:        id,
+			Path:      strings.TrimPrefix(cleanVersionPath(p), "/"),
+			Size:      entries[i].Size,
+			CreatedAt: versionTime(id, entries[i].ModTime),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ID > versions[j].ID
+	})
+	return versions, nil
+}
+
+// RestoreVersion replaces the current content of a file with a prior version.
+// The content being replaced is itself saved as a new version first.
+func (v *VersionedFileSystem) RestoreVersion(ctx context.Context, p, versionID string) error {
+	if versionID == "" || strings.ContainsAny(versionID, `/\`) || versionID == "." || versionID == ".." {
+		return NewPathError("restore-version", p, ErrCodeInvalidInput, "invalid version ID")
+	}
+
+	source := path.Join(v.versionDir(p), versionID)
+	info, err := v.fs.Stat(ctx, source)
+	if err != nil {
+		if IsCode(err, ErrCodeNotFound) {
+			return NewPathError("restore-version", p, ErrCodeNotFound, fmt.Sprintf("version not found: %s", versionID))
+		}
+		return err
+	}
+
+	if _, err := v.snapshot(ctx, p); err != nil {
+		return err
+	}
+	if err := copyFile(ctx, v.fs, v.fs, info, p); err != nil {
+		return WrapPathErr("restore-version", p, err)
+	}
+	return v.prune(ctx, p)
+}
+
+// versionDir returns the sidecar directory holding versions of p.
+func (v *VersionedFileSystem) versionDir(p string) string {
+	return path.Join(v.opts.VersionsDir, cleanVersionPath(p))
+}
+
+// isVersionPath reports whether p lies inside the versions directory.
+func (v *VersionedFileSystem) isVersionPath(p string) bool {
+	clean := strings.TrimPrefix(cleanVersionPath(p), "/")
+	return clean == v.opts.VersionsDir || strings.HasPrefix(clean, v.opts.VersionsDir+"/")
+}
+
+// nextVersionID returns a new, chronologically sortable version ID.
+func (v *VersionedFileSystem) nextVersionID() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ns := time.Now().UTC().UnixNano()
+	if ns <= v.seq {
+		ns = v.seq + 1
+	}
+	v.seq = ns
+	return fmt.Sprintf("%019d", ns)
+}
+
+// snapshot copies the current content of p into a new version.
+// It returns the version path, or "" if p does not exist.
+func (v *VersionedFileSystem) snapshot(ctx context.Context, p string) (string, error) {
+	exists, err := v.fs.FileExists(ctx, p)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	target := path.Join(v.versionDir(p), v.nextVersionID())
+	if err := v.fs.CreateDir(ctx, v.versionDir(p)); err != nil {
+		return "", WrapPathErr("snapshot-version", p, err)
+	}
+
+	if copier, ok := v.fs.(CanCopy); ok {
+		if err := copier.Copy(ctx, p, target); err != nil {
+			return "", WrapPathErr("snapshot-version", p, err)
+		}
+		return target, nil
+	}
+
+	info, err := v.fs.Stat(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if err := copyFile(ctx, v.fs, v.fs, info, target); err != nil {
+		return "", WrapPathErr("snapshot-version", p, err)
+	}
+	return target, nil
+}
+
+// prune deletes the oldest versions of p beyond MaxVersions.
+func (v *VersionedFileSystem) prune(ctx context.Context, p string) error {
+	versions, err := v.ListVersions(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	errs := NewMultiError("prune-versions")
+	for _, version := range versions[min(len(versions), v.opts.MaxVersions):] {
+		errs.Add(v.fs.Delete(ctx, path.Join(v.versionDir(p), version.ID)))
+	}
+	return errs.Err()
+}
+
+// cleanVersionPath normalizes a file path for use as a versions key.
+func cleanVersionPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// versionTime recovers the save time encoded in a version ID.
+func versionTime(id string, fallback time.Time) time.Time {
+	var ns int64
+	if _, err := fmt.Sscanf(id, "%d", &ns); err != nil || ns <= 0 {
+		return fallback
+	}
+	return time.Unix(0, ns).UTC()
+}
+
+// ============================================================================
+// FileSystem Interface - Read Operations (Delegated)
+// ============================================================================
+
+// Read delegates to the underlying filesystem.
+func (v *VersionedFileSystem) Read(ctx context.Context, p string) (io.ReadCloser, error) {
+	return v.fs.Read(ctx, p)
+}
+
+// ReadAll delegates to the underlying filesystem.
+func (v *VersionedFileSystem) ReadAll(ctx context.Context, p string) ([]byte, error) {
+	return v.fs.ReadAll(ctx, p)
+}
+
+// FileExists delegates to the underlying filesystem.
+func (v *VersionedFileSystem) FileExists(ctx context.Context, p string) (bool, error) {
+	return v.fs.FileExists(ctx, p)
+}
+
+// DirExists delegates to the underlying filesystem.
+func (v *VersionedFileSystem) DirExists(ctx context.Context, p string) (bool, error) {
+	return v.fs.DirExists(ctx, p)
+}
+
+// Stat delegates to the underlying filesystem.
+func (v *VersionedFileSystem) Stat(ctx context.Context, p string) (*FileInfo, error) {
+	return v.fs.Stat(ctx, p)
+}
+
+// ListContents delegates to the underlying filesystem, hiding the versions directory.
+func (v *VersionedFileSystem) ListContents(ctx context.Context, p string, recursive bool) ([]FileInfo, error) {
+	entries, err := v.fs.ListContents(ctx, p, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := entries[:0]
+	for i := range entries {
+		if !v.isVersionPath(entries[i].Path) {
+			visible = append(visible, entries[i])
+		}
+	}
+	return visible, nil
+}
+
+// ============================================================================
+// FileSystem Interface - Write Operations
+// ============================================================================
+
+// Write saves the current content of path as a version before writing.
+// If the write fails (for example because the file exists and overwriting is
+// not enabled), the version just saved is discarded.
+func (v *VersionedFileSystem) Write(ctx context.Context, p string, content io.Reader, options ...Option) (*WriteResult, error) {
+	if v.isVersionPath(p) {
+		return nil, NewPathError("write", p, ErrCodePermission, "cannot write inside the versions directory")
+	}
+
+	version, err := v.snapshot(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := v.fs.Write(ctx, p, content, options...)
+	if err != nil {
+		if version != "" {
+			_ = v.fs.Delete(ctx, version)
+		}
+		return nil, err
+	}
+
+	if version != "" {
+		if err := v.prune(ctx, p); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// Delete delegates to the underlying filesystem. Versions of the file are kept.
+func (v *VersionedFileSystem) Delete(ctx context.Context, p string) error {
+	return v.fs.Delete(ctx, p)
+}
+
+// CreateDir delegates to the underlying filesystem.
+func (v *VersionedFileSystem) CreateDir(ctx context.Context, p string) error {
+	return v.fs.CreateDir(ctx, p)
+}
+
+// DeleteDir delegates to the underlying filesystem.
+func (v *VersionedFileSystem) DeleteDir(ctx context.Context, p string) error {
+	return v.fs.DeleteDir(ctx, p)
+}
+
+// ============================================================================
+// Interface Assertions
+// ============================================================================
+
+// Ensure VersionedFileSystem implements FileSystem
+var (
+	_ FileSystem = (*VersionedFileSystem)(nil)
+	_ FileReader = (*VersionedFileSystem)(nil)
+	_ FileWriter = (*VersionedFileSystem)(nil)
+)
@@ -0,0 +1,126 @@
+package filekit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestVersionedFileSystem(t *testing.T) {
+	ctx := context.Background()
+	versioned := filekit.NewVersionedFileSystem(memory.New(), filekit.WithMaxVersions(2))
+
+	// Initial write plus three overwrites
+	for _, content := range []string{"v1", "v2", "v3", "v4"} {
+		if _, err := versioned.Write(ctx, "docs/report.txt", strings.NewReader(content), filekit.WithOverwrite(true)); err != nil {
+			t.Fatalf("write %s failed: %v", content, err)
+		}
+	}
+
+	versions, err := versioned.ListVersions(ctx, "docs/report.txt")
+	if err != nil {
+		t.Fatalf("list versions failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions))
+	}
+
+	// Newest first: v3, then v2; v1 was pruned
+	for i, want := range []string{"v3", "v2"} {
+		data, err := versioned.ReadAll(ctx, ".versions/docs/report.txt/"+versions[i].ID)
+		if err != nil {
+			t.Fatalf("read version %s failed: %v", versions[i].ID, err)
+		}
+		if string(data) != want {
+			t.Errorf("version %d: expected %q, got %q", i, want, data)
+		}
+		if versions[i].Path != "docs/report.txt" || versions[i].Size != 2 || versions[i].CreatedAt.IsZero() {
+			t.Errorf("unexpected version info: %+v", versions[i])
+		}
+	}
+
+	// Restore the oldest remaining version
+	if err := versioned.RestoreVersion(ctx, "docs/report.txt", versions[1].ID); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	data, err := versioned.ReadAll(ctx, "docs/report.txt")
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(data) != "v2" {
+		t.Errorf("expected restored content %q, got %q", "v2", data)
+	}
+
+	// The replaced content (v4) became a version, still capped at two
+	versions, err = versioned.ListVersions(ctx, "docs/report.txt")
+	if err != nil {
+		t.Fatalf("list versions failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions after restore, got %d", len(versions))
+	}
+	latest, err := versioned.ReadAll(ctx, ".versions/docs/report.txt/"+versions[0].ID)
+	if err != nil {
+		t.Fatalf("read version failed: %v", err)
+	}
+	if string(latest) != "v4" {
+		t.Errorf("expected newest version %q, got %q", "v4", latest)
+	}
+}
+
+func TestVersionedFileSystem_FailedWriteKeepsNoVersion(t *testing.T) {
+	ctx := context.Background()
+	versioned := filekit.NewVersionedFileSystem(memory.New())
+
+	if _, err := versioned.Write(ctx, "a.txt", strings.NewReader("original")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// The memory driver refuses to overwrite without WithOverwrite
+	if _, err := versioned.Write(ctx, "a.txt", strings.NewReader("new")); err == nil {
+		t.Fatal("expected write without overwrite to fail")
+	}
+
+	versions, err := versioned.ListVersions(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("list versions failed: %v", err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("expected no versions after failed write, got %d", len(versions))
+	}
+}
+
+func TestVersionedFileSystem_HidesVersionsDir(t *testing.T) {
+	ctx := context.Background()
+	versioned := filekit.NewVersionedFileSystem(memory.New())
+
+	for _, content := range []string{"one", "two"} {
+		if _, err := versioned.Write(ctx, "a.txt", strings.NewReader(content), filekit.WithOverwrite(true)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	entries, err := versioned.ListContents(ctx, "", true)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(strings.TrimPrefix(entry.Path, "/"), ".versions") {
+			t.Errorf("versions directory should be hidden, got %s", entry.Path)
+		}
+	}
+
+	if _, err := versioned.Write(ctx, ".versions/a.txt/1", strings.NewReader("x")); !filekit.IsCode(err, filekit.ErrCodePermission) {
+		t.Errorf("expected writes into versions directory to be rejected, got %v", err)
+	}
+
+	if err := versioned.RestoreVersion(ctx, "a.txt", "missing"); !filekit.IsCode(err, filekit.ErrCodeNotFound) {
+		t.Errorf("expected not found for missing version, got %v", err)
+	}
+	if err := versioned.RestoreVersion(ctx, "a.txt", "../b.txt"); !filekit.IsCode(err, filekit.ErrCodeInvalidInput) {
+		t.Errorf("expected invalid input for unsafe version ID, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package filekit
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ============================================================================
+// Walk - Context-aware tree traversal
+// ============================================================================
+
+// SkipDir can be returned from a [WalkFunc] to skip a directory. Returned for
+// a directory, Walk does not descend into it; returned for a file, Walk skips
+// the remaining entries of the file's directory. It is the same value as
+// fs.SkipDir, so io/fs walk functions can be reused as is.
+var SkipDir = fs.SkipDir
+
+// SkipAll can be returned from a [WalkFunc] to stop the walk. Walk returns
+// nil. It is the same value as fs.SkipAll.
+var SkipAll = fs.SkipAll
+
+// WalkFunc is called by Walk for each file and directory it visits.
+//
+// If err is non-nil, Walk could not stat the root (info is zero) or could not
+// list the directory at path (info describes it, and fn has already been
+// called for it once with a nil error). Returning err stops the walk;
+// returning nil or SkipDir carries on with the next entry.
+type WalkFunc func(path string, info FileInfo, err error) error
+
+// Walk walks the tree rooted at root, calling fn for root and then for each
+// entry below it, like filepath.WalkDir. Each directory is listed with a
+// non-recursive ListContents, so it works with every driver, and its entries
+// are visited in the order ListContents returns them, by name. Returning
+// [SkipDir] from fn skips a directory, returning [SkipAll] stops the walk,
+// and any other error stops the walk and is returned by Walk.
+//
+// The context is checked before each entry and each listing; when it is done
+// Walk returns an ErrCodeAborted or ErrCodeTimeout error.
+//
+// Example:
+//
+//	err := filekit.Walk(ctx, fs, "tenants/acme", func(p string, info filekit.FileInfo, err error) error {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if info.IsDir && info.Name == "tmp" {
+//	        return filekit.SkipDir
+//	    }
+//	    fmt.Println(p, info.Size)
+//	    return nil
+//	})
+func Walk(ctx context.Context, fsys FileSystem, root string, fn WalkFunc) error {
+	if err := FromContext(ctx, "walk", root); err != nil {
+		return err
+	}
+
+	info, err := walkRoot(ctx, fsys, root)
+	if err != nil {
+		err = fn(root, FileInfo{}, err)
+	} else {
+		err = walkDir(ctx, fsys, root, info, fn)
+	}
+	if errors.Is(err, SkipDir) || errors.Is(err, SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// walkRoot describes root. Object stores often have no object for a
+// directory, so a root that fails Stat but exists as a directory is reported
+// as one.
+func walkRoot(ctx context.Context, fsys FileSystem, root string) (FileInfo, error) {
+	dir := FileInfo{Name: path.Base(root), Path: root, IsDir: true}
+	if strings.Trim(root, "/") == "" {
+		dir.Name = ""
+		return dir, nil
+	}
+
+	info, err := fsys.Stat(ctx, root)
+	if err == nil {
+		return *info, nil
+	}
+	if isDir, dirErr := fsys.DirExists(ctx, root); dirErr == nil && isDir {
+		return dir, nil
+	}
+	return FileInfo{}, err
+}
+
+// walkDir calls fn for p and, if it is a directory, walks its entries
+func walkDir(ctx context.Context, fsys FileSystem, p string, info FileInfo, fn WalkFunc) error {
+	if err := fn(p, info, nil); err != nil || !info.IsDir {
+		if errors.Is(err, SkipDir) && info.IsDir {
+			err = nil
+		}
+		return err
+	}
+	if err := FromContext(ctx, "walk", p); err != nil {
+		return err
+	}
+
+	entries, err := fsys.ListContents(ctx, p, false)
+	if err != nil {
+		if err = fn(p, info, err); err != nil {
+			if errors.Is(err, SkipDir) {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for i := range entries {
+		if err := FromContext(ctx, "walk", entries[i].Path); err != nil {
+			return err
+		}
+		if err := walkDir(ctx, fsys, entries[i].Path, entries[i], fn); err != nil {
+			if errors.Is(err, SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package filekit_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+// listFailFS fails ListContents for one directory
+type listFailFS struct {
+	filekit.FileSystem
+	path string
+	err  error
+}
+
+func (f listFailFS) ListContents(ctx context.Context, path string, recursive bool) ([]filekit.FileInfo, error) {
+	if path == f.path {
+		return nil, f.err
+	}
+	return f.FileSystem.ListContents(ctx, path, recursive)
+}
+
+func newWalkTree(t *testing.T) filekit.FileSystem {
+	t.Helper()
+	fs := memory.New()
+	seedTree(t, fs, map[string]string{
+		"site/index.html":     "<html>",
+		"site/css/main.css":   "body{}",
+		"site/css/print.css":  "@media print{}",
+		"site/img/logo.png":   "png",
+		"site/tmp/cache.bin":  "cache",
+		"site/tmp/deep/x.bin": "x",
+		"other/ignored.txt":   "ignored",
+	})
+	return fs
+}
+
+// walkPaths returns the paths Walk visits, in order
+func walkPaths(t *testing.T, fs filekit.FileSystem, root string, fn filekit.WalkFunc) ([]string, error) {
+	t.Helper()
+	var visited []string
+	err := filekit.Walk(context.Background(), fs, root, func(p string, info filekit.FileInfo, err error) error {
+		if err == nil {
+			visited = append(visited, p)
+		}
+		return fn(p, info, err)
+	})
+	return visited, err
+}
+
+func TestWalk(t *testing.T) {
+	fs := newWalkTree(t)
+	visited, err := walkPaths(t, fs, "site", func(string, filekit.FileInfo, error) error { return nil })
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	want := []string{
+		"site",
+		"site/css", "site/css/main.css", "site/css/print.css",
+		"site/img", "site/img/logo.png",
+		"site/index.html",
+		"site/tmp", "site/tmp/cache.bin", "site/tmp/deep", "site/tmp/deep/x.bin",
+	}
+	if !slices.Equal(visited, want) {
+		t.Errorf("visited = %v, want %v", visited, want)
+	}
+}
+
+func TestWalk_Root(t *testing.T) {
+	fs := newWalkTree(t)
+	var rootInfo filekit.FileInfo
+	var files int
+	err := filekit.Walk(context.Background(), fs, "", func(p string, info filekit.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "" {
+			rootInfo = info
+		}
+		if !info.IsDir {
+			files++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if !rootInfo.IsDir {
+		t.Error("root was not reported as a directory")
+	}
+	if files != 7 {
+		t.Errorf("files = %d, want 7", files)
+	}
+}
+
+func TestWalk_File(t *testing.T) {
+	fs := newWalkTree(t)
+	visited, err := walkPaths(t, fs, "site/index.html", func(string, filekit.FileInfo, error) error { return nil })
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if !slices.Equal(visited, []string{"site/index.html"}) {
+		t.Errorf("visited = %v, want only the file", visited)
+	}
+}
+
+func TestWalk_SkipDir(t *testing.T) {
+	fs := newWalkTree(t)
+
+	t.Run("directory", func(t *testing.T) {
+		visited, err := walkPaths(t, fs, "site", func(p string, info filekit.FileInfo, err error) error {
+			if info.IsDir && info.Name == "tmp" {
+				return filekit.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Walk: %v", err)
+		}
+		if slices.Contains(visited, "site/tmp/cache.bin") || slices.Contains(visited, "site/tmp/deep") {
+			t.Errorf("visited = %v, want site/tmp skipped", visited)
+		}
+		if !slices.Contains(visited, "site/index.html") {
+			t.Errorf("visited = %v, want siblings of site/tmp", visited)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		// SkipDir from a file skips the rest of its directory
+		visited, err := walkPaths(t, fs, "site", func(p string, info filekit.FileInfo, err error) error {
+			if p == "site/css/main.css" {
+				return filekit.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Walk: %v", err)
+		}
+		if slices.Contains(visited, "site/css/print.css") {
+			t.Errorf("visited = %v, want rest of site/css skipped", visited)
+		}
+		if !slices.Contains(visited, "site/img/logo.png") {
+			t.Errorf("visited = %v, want walk to continue after site/css", visited)
+		}
+	})
+
+	t.Run("root", func(t *testing.T) {
+		visited, err := walkPaths(t, fs, "site", func(string, filekit.FileInfo, error) error { return filekit.SkipDir })
+		if err != nil {
+			t.Fatalf("Walk: %v", err)
+		}
+		if !slices.Equal(visited, []string{"site"}) {
+			t.Errorf("visited = %v, want only the root", visited)
+		}
+	})
+}
+
+func TestWalk_SkipAll(t *testing.T) {
+	fs := newWalkTree(t)
+	visited, err := walkPaths(t, fs, "site", func(p string, info filekit.FileInfo, err error) error {
+		if p == "site/css/main.css" {
+			return filekit.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if want := []string{"site", "site/css", "site/css/main.css"}; !slices.Equal(visited, want) {
+		t.Errorf("visited = %v, want %v", visited, want)
+	}
+}
+
+func TestWalk_Errors(t *testing.T) {
+	ctx := context.Background()
+	fs := newWalkTree(t)
+
+	t.Run("callback error stops the walk", func(t *testing.T) {
+		stop := errors.New("stop")
+		visited, err := walkPaths(t, fs, "site", func(p string, info filekit.FileInfo, err error) error {
+			if p == "site/img" {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Fatalf("err = %v, want the callback error", err)
+		}
+		if slices.Contains(visited, "site/img/logo.png") || slices.Contains(visited, "site/index.html") {
+			t.Errorf("visited = %v, want walk stopped at site/img", visited)
+		}
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		var calls int
+		err := filekit.Walk(ctx, fs, "nope", func(p string, info filekit.FileInfo, err error) error {
+			calls++
+			if p != "nope" || !filekit.IsNotFound(err) {
+				t.Errorf("fn(%q, _, %v), want the root and a not-found error", p, err)
+			}
+			return err
+		})
+		if calls != 1 || !filekit.IsNotFound(err) {
+			t.Errorf("calls = %d, err = %v; want one call and a not-found error", calls, err)
+		}
+	})
+
+	t.Run("list error is passed to fn", func(t *testing.T) {
+		listErr := errors.New("list failed")
+		failing := listFailFS{FileSystem: fs, path: "site/css", err: listErr}
+
+		var got error
+		visited, err := walkPaths(t, failing, "site", func(p string, info filekit.FileInfo, err error) error {
+			if err != nil {
+				if p != "site/css" || !info.IsDir {
+					t.Errorf("fn(%q, %+v, %v), want site/css directory info", p, info, err)
+				}
+				got = err
+				return nil
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Walk: %v", err)
+		}
+		if !errors.Is(got, listErr) {
+			t.Errorf("fn err = %v, want the list error", got)
+		}
+		if !slices.Contains(visited, "site/img/logo.png") {
+			t.Errorf("visited = %v, want walk to continue when fn ignores the error", visited)
+		}
+
+		err = filekit.Walk(ctx, failing, "site", func(p string, info filekit.FileInfo, err error) error {
+			return err
+		})
+		if !errors.Is(err, listErr) {
+			t.Errorf("Walk err = %v, want the list error when fn returns it", err)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		var visited int
+		err := filekit.Walk(ctx, fs, "site", func(p string, info filekit.FileInfo, err error) error {
+			visited++
+			if p == "site/css" {
+				cancel()
+			}
+			return nil
+		})
+		if !filekit.IsCode(err, filekit.ErrCodeAborted) {
+			t.Errorf("err = %v, want ErrCodeAborted", err)
+		}
+		if visited != 2 {
+			t.Errorf("visited %d entries, want the walk to stop after site/css", visited)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package filekit
+
+import (
+	"context"
+	"os"
+)
+
+// WriteFile writes the local file at localPath to path on fs. It uses the
+// driver's own WriteFile when fs implements CanWriteLocalFile, and opens the
+// file and calls Write otherwise, so decorators and mounts can upload local
+// files the same way as drivers.
+//
+// Example:
+//
+//	result, err := filekit.WriteFile(ctx, fs, "backups/db.sql", "/var/backups/db.sql")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("uploaded %d bytes\n", result.BytesWritten)
+func WriteFile(ctx context.Context, fs FileWriter, path, localPath string, opts ...Option) (*WriteResult, error) {
+	if w, ok := fs.(CanWriteLocalFile); ok {
+		return w.WriteFile(ctx, path, localPath, opts...)
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, WrapPathErr("writefile", localPath, err)
+	}
+	defer file.Close()
+
+	return fs.Write(ctx, path, file, opts...)
+}
@@ -0,0 +1,57 @@
+package filekit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gobeaver/filekit"
+	"github.com/gobeaver/filekit/driver/memory"
+)
+
+func TestWriteFile(t *testing.T) {
+	ctx := context.Background()
+	localPath := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(localPath, []byte("local content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newMount := func() filekit.FileSystem {
+		mm := filekit.NewMountManager()
+		if err := mm.Mount("/data", memory.New()); err != nil {
+			t.Fatal(err)
+		}
+		return mm
+	}
+
+	tests := []struct {
+		name string
+		fs   filekit.FileSystem
+		path string
+	}{
+		{"driver", memory.New(), "a/upload.txt"},
+		{"without CanWriteLocalFile", struct{ filekit.FileSystem }{memory.New()}, "a/upload.txt"},
+		{"decorator", filekit.NewVersionedFileSystem(memory.New()), "a/upload.txt"},
+		{"mount", newMount(), "/data/a/upload.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := filekit.WriteFile(ctx, tt.fs, tt.path, localPath)
+			if err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			if result.BytesWritten != int64(len("local content")) {
+				t.Errorf("BytesWritten = %d, want %d", result.BytesWritten, len("local content"))
+			}
+			if data, err := tt.fs.ReadAll(ctx, tt.path); err != nil || string(data) != "local content" {
+				t.Errorf("ReadAll = %q, %v; want %q", data, err, "local content")
+			}
+
+			_, err = filekit.WriteFile(ctx, tt.fs, tt.path, filepath.Join(t.TempDir(), "missing.txt"))
+			if !filekit.IsNotFound(err) {
+				t.Errorf("WriteFile(missing local file) = %v, want not found", err)
+			}
+		})
+	}
+}